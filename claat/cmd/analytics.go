@@ -0,0 +1,47 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// ReadAnalytics reads the analytics configuration of profile from a JSON
+// file containing an object of profile:configuration pairs.
+func ReadAnalytics(path, profile string) (*types.Analytics, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	profiles := types.AnalyticsProfiles{}
+	if err := json.Unmarshal(b, &profiles); err != nil {
+		return nil, err
+	}
+	a, ok := profiles[profile]
+	if !ok || a == nil {
+		return nil, fmt.Errorf("no analytics profile %q", profile)
+	}
+	if err := a.Validate(); err != nil {
+		return nil, fmt.Errorf("analytics profile %q: %v", profile, err)
+	}
+	if a.StepEvent == "" {
+		a.StepEvent = types.DefaultStepEvent
+	}
+	return a, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestReadAnalytics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analytics.json")
+	content := `{
+  "public": {"ga4": "G-ABC123", "scripts": ["https://example.com/a.js"]},
+  "partner": {"ga4": "G-XYZ789", "stepEvent": "step_view"},
+  "invalid": {"ga4": "UA-1-1"},
+  "insecure": {"scripts": ["http://example.com/a.js"]}
+}`
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		profile string
+		want    *types.Analytics
+		ok      bool
+	}{
+		{"public", &types.Analytics{GA4: "G-ABC123", Scripts: []string{"https://example.com/a.js"}, StepEvent: types.DefaultStepEvent}, true},
+		{"partner", &types.Analytics{GA4: "G-XYZ789", StepEvent: "step_view"}, true},
+		{"invalid", nil, false},
+		{"insecure", nil, false},
+		{"missing", nil, false},
+	}
+	for _, tc := range tests {
+		a, err := ReadAnalytics(path, tc.profile)
+		if (err == nil) != tc.ok {
+			t.Errorf("ReadAnalytics(%q) error = %v; want ok %v", tc.profile, err, tc.ok)
+			continue
+		}
+		if diff := cmp.Diff(tc.want, a); diff != "" {
+			t.Errorf("ReadAnalytics(%q) got diff (-want +got): %s", tc.profile, diff)
+		}
+	}
+}
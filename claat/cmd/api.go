@@ -0,0 +1,127 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+
+	"github.com/googlecodelabs/tools/claat/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	// maxRenderSize is the maximum size of a source posted to /render.
+	maxRenderSize = 10 << 20
+)
+
+var (
+	// apiFormats are the content types of the formats served by the API.
+	// Other formats, such as local template files, are not served.
+	apiFormats = map[string]string{
+		"html":     "text/html; charset=utf-8",
+		"md":       "text/markdown; charset=utf-8",
+		"slides":   "text/html; charset=utf-8",
+		"tutorial": "text/markdown; charset=utf-8",
+	}
+	// docIDRegexp matches a Google Doc ID. Other sources, such as
+	// local files and URLs, are not exported by the API.
+	docIDRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{25,}$`)
+)
+
+// CmdServeAPI is the "claat serve -api" subcommand.
+// addr is the hostname and port to bind the web server to,
+// and codelabs are exported with opts.
+// It serves until ctx is done, and returns a process exit code.
+func CmdServeAPI(ctx context.Context, addr string, opts CmdExportOptions) int {
+	logging.Infof("Serving the claat API on %s", addr)
+	if err := listenAndServe(ctx, addr, NewAPIHandler(opts)); err != nil {
+		logging.Fatalf("claat serve: %v", err)
+	}
+	return 0
+}
+
+// NewAPIHandler returns the handler of the HTTP API, rendering codelabs
+// with opts. Its endpoints are:
+//
+//	POST /render?fmt=md renders the Markdown codelab of the request body.
+//	GET /export?doc=<id>&fmt=md exports Google Doc <id>.
+//
+// The format is one of apiFormats, "html" by default.
+func NewAPIHandler(opts CmdExportOptions) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/render", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body := ioutil.NopCloser(http.MaxBytesReader(w, r.Body, maxRenderSize))
+		serveCodelab(w, r, opts, func(ctx context.Context, buf *bytes.Buffer, o CmdExportOptions) error {
+			_, err := ExportCodelabMemory(ctx, body, buf, o)
+			return err
+		})
+	})
+	mux.HandleFunc("/export", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		doc := r.URL.Query().Get("doc")
+		if !docIDRegexp.MatchString(doc) {
+			http.Error(w, fmt.Sprintf("invalid doc ID %q", doc), http.StatusBadRequest)
+			return
+		}
+		serveCodelab(w, r, opts, func(ctx context.Context, buf *bytes.Buffer, o CmdExportOptions) error {
+			_, err := ExportCodelabWriter(ctx, doc, buf, o)
+			return err
+		})
+	})
+	return mux
+}
+
+// serveCodelab responds to r with the codelab written by export with opts,
+// in the format of the fmt parameter of r.
+func serveCodelab(w http.ResponseWriter, r *http.Request, opts CmdExportOptions, export func(context.Context, *bytes.Buffer, CmdExportOptions) error) {
+	format := r.URL.Query().Get("fmt")
+	if format == "" {
+		format = "html"
+	}
+	typ, ok := apiFormats[format]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unsupported format %q", format), http.StatusBadRequest)
+		return
+	}
+	// continue the trace of the client, if any
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	opts.Tmplout = format
+	var buf bytes.Buffer
+	if err := export(ctx, &buf, opts); err != nil {
+		logging.With("source", r.URL.Path).Errorf("%v", err)
+		code := http.StatusUnprocessableEntity
+		if errors.Is(err, context.DeadlineExceeded) {
+			code = http.StatusServiceUnavailable
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	w.Header().Set("Content-Type", typ)
+	buf.WriteTo(w)
+}
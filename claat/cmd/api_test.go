@@ -0,0 +1,74 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIHandler(t *testing.T) {
+	ts := httptest.NewServer(NewAPIHandler(CmdExportOptions{Expenv: "web"}))
+	defer ts.Close()
+	src := "id: lab\nsummary: s\n\n---\n\n# Lab\n\n## Setup\nDuration: 2\n\nSome **text**\n"
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		typ    string
+		want   string
+	}{
+		{"render html", "POST", "/render", src, http.StatusOK, "text/html; charset=utf-8", `<google-codelab-step label="Setup"`},
+		{"render md", "POST", "/render?fmt=md", src, http.StatusOK, "text/markdown; charset=utf-8", "## Setup\nDuration: 02:00\n"},
+		{"render slides", "POST", "/render?fmt=slides", src, http.StatusOK, "text/html; charset=utf-8", "<h2>1. Setup</h2>"},
+		{"render template", "POST", "/render?fmt=/etc/passwd", src, http.StatusBadRequest, "", "unsupported format"},
+		{"render get", "GET", "/render", "", http.StatusMethodNotAllowed, "", ""},
+		{"export path", "GET", "/export?doc=../../etc/passwd", "", http.StatusBadRequest, "", "invalid doc ID"},
+		{"export url", "GET", "/export?doc=https://example.com/lab.md", "", http.StatusBadRequest, "", "invalid doc ID"},
+		{"export post", "POST", "/export", "", http.StatusMethodNotAllowed, "", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, ts.URL+tc.path, strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+			b, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tc.status {
+				t.Errorf("status = %d; want %d", res.StatusCode, tc.status)
+			}
+			if typ := res.Header.Get("Content-Type"); tc.typ != "" && typ != tc.typ {
+				t.Errorf("content type = %q; want %q", typ, tc.typ)
+			}
+			if !strings.Contains(string(b), tc.want) {
+				t.Errorf("response does not contain %q:\n%s", tc.want, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/diff"
+	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+const (
+	// commentsFilename is the report of open comments and suggestions
+	// written to the output directory of Google Docs codelabs with -comments.
+	commentsFilename = "comments.json"
+	// maxListedComments is the number of open comments listed in the error
+	// of -no_open_comments.
+	maxListedComments = 5
+)
+
+// openComments returns the open comments and suggestions of the Google Doc src,
+// fetched by f, if opts asks for them.
+// It fails if there are any and opts.NoOpenComments is set.
+func openComments(ctx context.Context, f *fetch.Fetcher, src string, opts CmdExportOptions) ([]*fetch.Comment, error) {
+	if !opts.Comments && !opts.NoOpenComments {
+		return nil, nil
+	}
+	cc, err := f.OpenComments(ctx, src)
+	if err != nil {
+		return nil, fmt.Errorf("comments: %v", err)
+	}
+	if len(cc) == 0 {
+		return []*fetch.Comment{}, nil
+	}
+	if opts.NoOpenComments {
+		var list []string
+		for i, c := range cc {
+			if i == maxListedComments {
+				list = append(list, fmt.Sprintf("and %d more", len(cc)-i))
+				break
+			}
+			list = append(list, c.String())
+		}
+		return nil, fmt.Errorf("%d open comments and suggestions: %s", len(cc), strings.Join(list, "; "))
+	}
+	logging.With("source", src).Warnf("%d open comments and suggestions", len(cc))
+	return cc, nil
+}
+
+// writeComments stores the comments report cc in dir.
+func writeComments(dir string, cc []*fetch.Comment) error {
+	b, err := json.MarshalIndent(cc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, commentsFilename), append(b, '\n'), 0644)
+}
+
+// annotateComments adds each comment of cc to clab as a warning box after
+// the first top-level block which contains its quoted text.
+// Comments without a quote found in clab are added at the start of the first step.
+func annotateComments(clab *types.Codelab, cc []*fetch.Comment) {
+	if len(clab.Steps) == 0 {
+		return
+	}
+	// annotations of each step by index of the top-level node they follow,
+	// -1 for the start of the step
+	after := make([]map[int][]nodes.Node, len(clab.Steps))
+	for i := range after {
+		after[i] = map[int][]nodes.Node{}
+	}
+	for _, c := range cc {
+		s, n := findQuote(clab, c.Quote)
+		after[s][n] = append(after[s][n], commentNode(c))
+	}
+	for i, st := range clab.Steps {
+		if len(after[i]) == 0 {
+			continue
+		}
+		content := append([]nodes.Node(nil), after[i][-1]...)
+		for j, n := range st.Content.Nodes {
+			content = append(content, n)
+			content = append(content, after[i][j]...)
+		}
+		st.Content.Nodes = content
+	}
+}
+
+// findQuote returns the indexes of the step and its top-level node
+// which contain the text quote, or 0 and -1 if none does.
+func findQuote(clab *types.Codelab, quote string) (step, node int) {
+	quote = strings.Join(strings.Fields(quote), " ")
+	if quote == "" {
+		return 0, -1
+	}
+	for i, st := range clab.Steps {
+		for j, n := range st.Content.Nodes {
+			if strings.Contains(diff.Text(n), quote) {
+				return i, j
+			}
+		}
+	}
+	return 0, -1
+}
+
+// commentNode returns the annotation of comment c.
+func commentNode(c *fetch.Comment) nodes.Node {
+	t := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Open " + c.String()})
+	return nodes.NewInfoboxNode(nodes.InfoboxNegative, nodes.NewListNode(t))
+}
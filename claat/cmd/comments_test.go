@@ -0,0 +1,107 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+type commentsTransport struct{}
+
+func (commentsTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	body := `{"body": {}}`
+	if strings.HasSuffix(r.URL.Path, "/comments") {
+		body = `{"comments": [
+			{"id": "c1", "content": "Typo?", "author": {"displayName": "Ann"}, "quotedFileContent": {"value": "teh"}},
+			{"id": "c2", "content": "Done", "resolved": true}
+		]}`
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+}
+
+func TestOpenComments(t *testing.T) {
+	f, err := fetch.NewFetcher("token", nil, commentsTransport{}, fetch.FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const src = "1AbCdEf"
+	cc, err := openComments(context.Background(), f, src, CmdExportOptions{})
+	if err != nil || cc != nil {
+		t.Errorf("openComments() without options = %v, %v; want nil, nil", cc, err)
+	}
+	cc, err = openComments(context.Background(), f, src, CmdExportOptions{Comments: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*fetch.Comment{{ID: "c1", Author: "Ann", Content: "Typo?", Quote: "teh"}}
+	if diff := cmp.Diff(want, cc); diff != "" {
+		t.Errorf("openComments() diff (-want +got):\n%s", diff)
+	}
+	_, err = openComments(context.Background(), f, src, CmdExportOptions{NoOpenComments: true})
+	if err == nil || !strings.Contains(err.Error(), `1 open comments and suggestions: comment by Ann on "teh": Typo?`) {
+		t.Errorf("openComments() with NoOpenComments = %v; want open comments error", err)
+	}
+}
+
+func TestAnnotateComments(t *testing.T) {
+	text := func(s string) nodes.Node {
+		return nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: s}))
+	}
+	clab := &types.Codelab{}
+	clab.NewStep("Setup").Content.Append(text("Open teh console."), text("Create a project."))
+	clab.NewStep("Deploy").Content.Append(text("Deploy  it\nnow."))
+	annotateComments(clab, []*fetch.Comment{
+		{Author: "Ann", Content: "Typo?", Quote: "teh"},
+		{Content: "Add a diagram"},
+		{Quote: "it now.", Suggestion: true},
+		{Author: "Bob", Content: "Where?", Quote: "not in the codelab"},
+	})
+
+	want := map[string][]string{
+		"Setup": {
+			"Open comment: Add a diagram",
+			`Open comment by Bob on "not in the codelab": Where?`,
+			"Open teh console.",
+			`Open comment by Ann on "teh": Typo?`,
+			"Create a project.",
+		},
+		"Deploy": {
+			"Deploy it now.",
+			`Open suggestion to delete "it now."`,
+		},
+	}
+	got := map[string][]string{}
+	for _, st := range clab.Steps {
+		for _, n := range st.Content.Nodes {
+			s := nodes.PlainText(n)
+			if ib, ok := n.(*nodes.InfoboxNode); !ok || ib.Kind != nodes.InfoboxNegative {
+				s = strings.Join(strings.Fields(s), " ")
+			}
+			got[st.Title] = append(got[st.Title], s)
+		}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("annotateComments() diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/render"
+	"github.com/googlecodelabs/tools/claat/types"
+	"github.com/googlecodelabs/tools/claat/util"
+)
+
+// courseFilename is the course record written next to the course page.
+const courseFilename = "course.json"
+
+// CmdCourseExport is the "claat course export ..." subcommand.
+// Each of opts.Srcs is a course file, exported with ExportCourse.
+// It returns a process exit code.
+func CmdCourseExport(ctx context.Context, opts CmdExportOptions) int {
+	if len(opts.Srcs) == 0 {
+		logging.Fatalf("Need at least one course file. Try '-h' for options.")
+	}
+	if isStdout(opts.Output) {
+		logging.Fatalf("Courses cannot be exported to stdout. Try '-h' for options.")
+	}
+	if opts.Release != "" {
+		logging.Fatalf("-release: courses cannot be exported to releases")
+	}
+	if opts.Split {
+		if err := checkSplit(opts.Tmplout, opts.Output); err != nil {
+			logging.Fatalf("%v", err)
+		}
+	}
+	var exitCode int
+	for _, src := range util.Unique(opts.Srcs) {
+		c, err := ExportCourse(ctx, src, opts)
+		if err != nil {
+			logging.With("source", src).Errorf("%v", err)
+			exitCode = 1
+			continue
+		}
+		logging.With("source", src, "id", c.ID, "labs", len(c.Labs), "duration", c.Duration).Infof("exported")
+	}
+	return exitCode
+}
+
+// ExportCourse exports the labs of course file src to opts.Output,
+// as ExportCodelab does, then writes an overview page of the course,
+// listing its labs in order along with their prerequisites and
+// the cumulative duration of the course, and the course record
+// in JSON format, to the directory of the course ID under opts.Output.
+// The overview page is in Markdown if opts.Tmplout is "md",
+// and in HTML otherwise.
+func ExportCourse(ctx context.Context, src string, opts CmdExportOptions) (*types.Course, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	c, err := types.ReadCourse(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	metas := make([]*types.Meta, len(c.Labs))
+	errs := make([]error, len(c.Labs))
+	var wg sync.WaitGroup
+	for i, lab := range c.Labs {
+		wg.Add(1)
+		go func(i int, src string) {
+			defer wg.Done()
+			metas[i], errs[i] = ExportCodelab(ctx, src, nil, opts)
+		}(i, fetch.ResolveSource(src, lab.Source))
+	}
+	wg.Wait()
+	var msgs []string
+	for i, err := range errs {
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("%s: %v", c.Labs[i].Source, err))
+		}
+	}
+	if len(msgs) > 0 {
+		return nil, errors.New(strings.Join(msgs, "\n"))
+	}
+
+	format := "html"
+	if opts.Tmplout == "md" {
+		format = "md"
+	}
+	dir := filepath.Join(opts.Output, c.ID)
+	data, err := courseContext(c, metas, opts.Output, dir, format)
+	if err != nil {
+		return nil, err
+	}
+	data.Extra = opts.ExtraVars
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, courseFilename), append(b, '\n'), 0644); err != nil {
+		return nil, err
+	}
+	w, err := os.Create(filepath.Join(dir, "index."+format))
+	if err != nil {
+		return nil, err
+	}
+	defer w.Close()
+	if err := render.ExecuteCourse(w, format, data); err != nil {
+		return nil, err
+	}
+	return c, w.Close()
+}
+
+// courseContext sets the codelab fields of the labs of c from their
+// exported metadata metas and returns the template context of the course
+// page, written in format to dir, of labs exported to output.
+// Prerequisites of a lab which are labs of the course must come before it,
+// and those listed in c must be either labs of the course or URLs.
+func courseContext(c *types.Course, metas []*types.Meta, output, dir, format string) (*render.CourseContext, error) {
+	data := &render.CourseContext{Course: c}
+	pos := make(map[string]int, len(metas))
+	for i, m := range metas {
+		if m.ID == c.ID {
+			return nil, fmt.Errorf("lab %s has the ID of the course", m.ID)
+		}
+		if _, ok := pos[m.ID]; ok {
+			return nil, fmt.Errorf("lab %s is listed more than once", m.ID)
+		}
+		pos[m.ID] = i
+	}
+	c.Duration = 0
+	for i, m := range metas {
+		lab := c.Labs[i]
+		lab.ID, lab.Title, lab.Duration = m.ID, m.Title, m.Duration
+		c.Duration += m.Duration
+		link, err := courseLink(output, dir, format, m)
+		if err != nil {
+			return nil, err
+		}
+		e := &render.CourseEntry{Meta: m, Num: i + 1, Link: link, Elapsed: c.Duration}
+		listed := make(map[string]bool, len(lab.Prerequisites))
+		for _, p := range lab.Prerequisites {
+			listed[p] = true
+		}
+		for _, p := range util.Unique(append(append([]string{}, lab.Prerequisites...), m.Prerequisites...)) {
+			j, ok := pos[p]
+			switch {
+			case ok && j >= i:
+				return nil, fmt.Errorf("lab %s requires %s, which does not come before it", m.ID, p)
+			case ok:
+				e.Requires = append(e.Requires, &render.CourseRef{Title: metas[j].Title, Link: data.Labs[j].Link})
+			case strings.Contains(p, "://"):
+				e.Requires = append(e.Requires, &render.CourseRef{Title: p, Link: p})
+			case listed[p]:
+				return nil, fmt.Errorf("lab %s requires %s, which is neither a lab of the course nor a URL", m.ID, p)
+			default:
+				// a lab exported to a sibling directory, as linked in the lab itself
+				e.Requires = append(e.Requires, &render.CourseRef{Title: p, Link: "../" + p + "/"})
+			}
+		}
+		data.Labs = append(data.Labs, e)
+	}
+	return data, nil
+}
+
+// courseLink returns the link to the page of codelab m, exported
+// to output in format, relative to the course page in dir.
+func courseLink(output, dir, format string, m *types.Meta) (string, error) {
+	rel, err := filepath.Rel(dir, codelabDir(output, m))
+	if err != nil {
+		return "", err
+	}
+	link := filepath.ToSlash(rel) + "/"
+	if format == "md" {
+		link += "index.md"
+	}
+	return link, nil
+}
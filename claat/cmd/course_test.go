@@ -0,0 +1,139 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/cmd"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// writeFiles writes files, keyed by name, to dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestExportCourse(t *testing.T) {
+	src := t.TempDir()
+	writeFiles(t, src, map[string]string{
+		"intro.md": "id: intro\nsummary: Get started.\n\n---\n\n# Intro\n\n## Setup\nDuration: 10\n\nText\n",
+		"deploy.md": "id: deploy\nsummary: Ship it.\nprerequisites: https://example.com/labs/iam\n\n---\n\n" +
+			"# Deploy\n\n## Deploy\nDuration: 20\n\nText\n",
+		"course.yaml": "id: track\ntitle: Track\nsummary: All of it.\nlabs:\n" +
+			"  - source: intro.md\n  - source: deploy.md\n    prerequisites: [intro]\n",
+	})
+	out := t.TempDir()
+	opts := cmd.CmdExportOptions{Output: out, Tmplout: "md"}
+	c, err := cmd.ExportCourse(context.Background(), filepath.Join(src, "course.yaml"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &types.Course{
+		ID:      "track",
+		Title:   "Track",
+		Summary: "All of it.",
+		Labs: []*types.CourseLab{
+			{Source: "intro.md", ID: "intro", Title: "Intro", Duration: 10},
+			{Source: "deploy.md", Prerequisites: []string{"intro"}, ID: "deploy", Title: "Deploy", Duration: 20},
+		},
+		Duration: 30,
+	}
+	if diff := cmp.Diff(want, c); diff != "" {
+		t.Errorf("ExportCourse() diff (-want +got):\n%s", diff)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(out, "track", "course.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &types.Course{}
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("course.json diff (-want +got):\n%s", diff)
+	}
+	if _, err := ioutil.ReadFile(filepath.Join(out, "deploy", "index.md")); err != nil {
+		t.Errorf("lab not exported: %v", err)
+	}
+	b, err = ioutil.ReadFile(filepath.Join(out, "track", "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"# Track\n\nAll of it.\n\n2 labs, 30 min\n",
+		"1. [Intro](../intro/index.md) (10 min, 10 min total)\n   Get started.\n",
+		"2. [Deploy](../deploy/index.md) (20 min, 30 min total)\n   Ship it.\n" +
+			"   Requires: [Intro](../intro/index.md), [https://example.com/labs/iam](https://example.com/labs/iam)\n",
+	} {
+		if !strings.Contains(string(b), s) {
+			t.Errorf("index.md does not contain %q:\n%s", s, b)
+		}
+	}
+}
+
+func TestExportCourseOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		course string
+		err    string
+	}{
+		{
+			name:   "later lab",
+			course: "id: track\nlabs:\n  - source: intro.md\n    prerequisites: [deploy]\n  - source: deploy.md\n",
+			err:    "lab intro requires deploy, which does not come before it",
+		},
+		{
+			name:   "unknown lab",
+			course: "id: track\nlabs:\n  - source: intro.md\n  - source: deploy.md\n    prerequisites: [iam]\n",
+			err:    "lab deploy requires iam, which is neither a lab of the course nor a URL",
+		},
+		{
+			name:   "duplicate lab",
+			course: "id: track\nlabs:\n  - source: intro.md\n  - source: intro.md\n",
+			err:    "lab intro is listed more than once",
+		},
+		{
+			name:   "course ID",
+			course: "id: intro\nlabs:\n  - source: intro.md\n",
+			err:    "lab intro has the ID of the course",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			src := t.TempDir()
+			writeFiles(t, src, map[string]string{
+				"intro.md":    "id: intro\nsummary: s\n\n---\n\n# Intro\n\n## Setup\n\nText\n",
+				"deploy.md":   "id: deploy\nsummary: s\n\n---\n\n# Deploy\n\n## Deploy\n\nText\n",
+				"course.yaml": tc.course,
+			})
+			_, err := cmd.ExportCourse(context.Background(), filepath.Join(src, "course.yaml"), cmd.CmdExportOptions{Output: t.TempDir(), Tmplout: "html"})
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("ExportCourse() error = %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
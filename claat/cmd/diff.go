@@ -0,0 +1,125 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/googlecodelabs/tools/claat/diff"
+	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/transform"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// Options type to make the CmdDiff signature succinct.
+type CmdDiffOptions struct {
+	// AuthToken is the token to use for the Drive API.
+	AuthToken string
+	// ADC authorizes with Application Default Credentials instead of the user flow.
+	ADC bool
+	// DocsAPI fetches Google Docs with the Docs API instead of the Drive HTML export.
+	DocsAPI bool
+	// Format is the report format, either "text" or "json".
+	Format string
+	// PassMetadata are the extra metadata fields to pass along.
+	PassMetadata map[string]bool
+	// Srcs are the old and new sources to compare.
+	Srcs []string
+}
+
+// CmdDiff is the "claat diff old new" subcommand.
+// It parses both sources and writes the changes from old to new
+// to stdout, as diff.Compare finds them.
+// It returns a process exit code.
+func CmdDiff(ctx context.Context, opts CmdDiffOptions) int {
+	if len(opts.Srcs) != 2 {
+		logging.Fatalf("Need an old and a new source. Try '-h' for options.")
+	}
+	fo := fetch.FetcherOptions{ADC: opts.ADC, DocsAPI: opts.DocsAPI}
+	var outlines [2]*diff.Outline
+	for i, src := range opts.Srcs {
+		o, err := sourceOutline(ctx, src, opts.AuthToken, opts.PassMetadata, fo, diffTransform(src))
+		if err != nil {
+			logging.With("source", src).Errorf("%v", err)
+			return 1
+		}
+		outlines[i] = o
+	}
+	if err := writeChanges(os.Stdout, diff.Compare(outlines[0], outlines[1]), opts.Format); err != nil {
+		logging.Errorf("%v", err)
+		return 1
+	}
+	return 0
+}
+
+// sourceOutline returns the outline of codelab src: the outline stored
+// in src if it is a release directory or the outline.json of a release,
+// or that of the codelab parsed from src and transformed by prep otherwise.
+func sourceOutline(ctx context.Context, src, authToken string, pm map[string]bool, fo fetch.FetcherOptions, prep func(*types.Codelab) error) (*diff.Outline, error) {
+	if fi, err := os.Stat(src); err == nil && fi.IsDir() {
+		o, err := diff.ReadOutline(filepath.Join(src, outlineFilename))
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s has no %s; export releases with -release", src, outlineFilename)
+		}
+		return o, err
+	}
+	if filepath.Base(src) == outlineFilename {
+		return diff.ReadOutline(src)
+	}
+	f, err := fetch.NewFetcher(authToken, pm, nil, fo)
+	if err != nil {
+		return nil, err
+	}
+	clab, err := f.SlurpCodelab(ctx, src, stdout)
+	if err != nil {
+		return nil, err
+	}
+	if err := prep(clab.Codelab); err != nil {
+		return nil, err
+	}
+	return diff.NewOutline(clab.Codelab), nil
+}
+
+// diffTransform returns the transformation of codelab src before it is
+// outlined: that of exports with the default options, so that sources
+// compare with the outlines of releases.
+func diffTransform(src string) func(*types.Codelab) error {
+	return func(clab *types.Codelab) error {
+		return transformCodelab(src, clab, transform.Options{}, nil)
+	}
+}
+
+// writeChanges writes changes to w in format: "json", or a Markdown list
+// as diff.Format writes it otherwise.
+func writeChanges(w io.Writer, changes []*diff.Change, format string) error {
+	if format != "json" {
+		return diff.Format(w, changes)
+	}
+	if changes == nil {
+		changes = []*diff.Change{}
+	}
+	b, err := json.MarshalIndent(changes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("diff: %v", err)
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/diff"
+	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestDiffSources(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"old.md": "id: lab\nsummary: s\n\n---\n\n# Lab\n\n## Setup\n\nOpen the console.\n\n## Deploy\n\n```\ngcloud run deploy\n```\n",
+		"new.md": "id: lab\nsummary: s\n\n---\n\n# Lab\n\n## Deploy\n\n```\ngcloud run deploy --region us-central1\n```\n\n" +
+			"## Setup\n\nOpen the Cloud console.\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old, err := sourceOutline(context.Background(), filepath.Join(dir, "old.md"), "", nil, fetch.FetcherOptions{}, diffTransform("old.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a stored outline compares the same as its source
+	stored := filepath.Join(dir, outlineFilename)
+	if err := diff.WriteOutline(stored, old); err != nil {
+		t.Fatal(err)
+	}
+	if old, err = sourceOutline(context.Background(), stored, "", nil, fetch.FetcherOptions{}, diffTransform(stored)); err != nil {
+		t.Fatal(err)
+	}
+	new, err := sourceOutline(context.Background(), filepath.Join(dir, "new.md"), "", nil, fetch.FetcherOptions{}, diffTransform("new.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*diff.Change{
+		{Kind: diff.CodeChanged, Step: "Deploy", Old: "gcloud run deploy\n", New: "gcloud run deploy --region us-central1\n"},
+		{Kind: diff.StepMoved, Step: "Setup", Old: "1", New: "2"},
+		{Kind: diff.TextChanged, Step: "Setup", Old: "Open the console.", New: "Open the Cloud console."},
+	}
+	got := diff.Compare(old, new)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Compare() diff (-want +got):\n%s", diff)
+	}
+
+	var buf bytes.Buffer
+	if err := writeChanges(&buf, got[1:2], "json"); err != nil {
+		t.Fatal(err)
+	}
+	wantJSON := "[\n  {\n    \"kind\": \"step moved\",\n    \"step\": \"Setup\",\n    \"old\": \"1\",\n    \"new\": \"2\"\n  }\n]\n"
+	if diff := cmp.Diff(wantJSON, buf.String()); diff != "" {
+		t.Errorf("writeChanges(json) diff (-want +got):\n%s", diff)
+	}
+	buf.Reset()
+	if err := writeChanges(&buf, nil, "json"); err != nil || buf.String() != "[]\n" {
+		t.Errorf("writeChanges(nil, json) = %q, %v; want %q", buf.String(), err, "[]\n")
+	}
+}
+
+func TestDiffSourcesTransformed(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "lab.md")
+	content := "id: lab\nsummary: s\n\n---\n\n# Lab\n\n## Copy\n\nPress [[Ctrl+C]].\n"
+	if err := ioutil.WriteFile(src, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := sourceOutline(context.Background(), src, "", nil, fetch.FetcherOptions{}, func(*types.Codelab) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err := sourceOutline(context.Background(), src, "", nil, fetch.FetcherOptions{}, diffTransform(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmp.Equal(raw.Steps[0].Text, o.Steps[0].Text) {
+		t.Errorf("sourceOutline(context.Background(), ) text = %q; want the keyboard shortcut transformed", o.Steps[0].Text)
+	}
+}
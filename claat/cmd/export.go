@@ -15,77 +15,325 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/googlecodelabs/tools/claat/diff"
 	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/logging"
 	"github.com/googlecodelabs/tools/claat/render"
+	"github.com/googlecodelabs/tools/claat/transform"
 	"github.com/googlecodelabs/tools/claat/types"
 	"github.com/googlecodelabs/tools/claat/util"
 )
 
+// Version is the version of claat recorded in the provenance of exports.
+var Version = "devel"
+
 // Options type to make the CmdExport signature succinct.
 type CmdExportOptions struct {
+	// Analytics are the analytics snippets to inject into HTML output,
+	// replacing GlobalGA, if not nil.
+	Analytics *types.Analytics
+	// Audience, if not empty, is the audience of the export, one of
+	// transform.Audiences: instructor guides show the instructor notes
+	// of codelabs, and student handouts strip them.
+	Audience string
 	// AuthToken is the token to use for the Drive API.
 	AuthToken string
+	// CloudShell, if not empty, is how terminal code blocks of HTML output
+	// open Cloud Shell on the repository and tutorial of the codelab:
+	// render.CloudShellLink or render.CloudShellEmbed.
+	CloudShell string
+	// ADC authorizes with Application Default Credentials instead of the user flow.
+	ADC bool
+	// Comments writes the open comments and suggestions of Google Docs
+	// codelabs to comments.json in their output directory, and adds them
+	// to the content as warnings along with Review.
+	Comments bool
+	// DocsAPI fetches Google Docs with the Docs API instead of the Drive HTML export.
+	DocsAPI bool
+	// DriveMatch is a glob pattern of doc names to export from drive:// folders.
+	DriveMatch string
+	// DurationTolerance is the maximum difference between the declared codelab
+	// duration and the sum of step durations not reported as a warning.
+	DurationTolerance time.Duration
+	// EmojiImages is the URL prefix of emoji images to replace emoji shortcodes
+	// of text with, instead of emoji, as in transform.EmojiOptions.
+	EmojiImages string
+	// EstimateDurations sets durations of steps without one to their estimate.
+	EstimateDurations bool
 	// Expenv is the codelab environment to export to.
 	Expenv string
 	// ExtraVars is extra template variables.
 	ExtraVars map[string]string
+	// FigureLabel is the label of figures numbered with NumberFigures,
+	// as in transform.CaptionOptions.
+	FigureLabel string
+	// Fixtures is the directory of recorded responses of Offline and Record.
+	Fixtures string
 	// GlobalGA is the global Google Analytics account to use.
 	GlobalGA string
+	// IframeDomains are domains allowed in iframes in addition
+	// to nodes.IframeAllowlist.
+	IframeDomains []string
+	// IconImages is the URL of the images of icons in Markdown output,
+	// as in render.Context.
+	IconImages string
+	// ImportCache is a directory to cache imported remote fragments in.
+	ImportCache string
+	// ImportCacheTTL is how long a cached import is reused.
+	ImportCacheTTL time.Duration
+	// KeepRuntimeVars leaves Qwiklabs {{{...}}} expressions unescaped in Markdown and HTML output.
+	KeepRuntimeVars bool
+	// Lightbox is the minimum width in pixels of images zooming in on click
+	// in HTML output, including images of unknown width, or 0 to disable it.
+	Lightbox int
+	// Limits, if not nil, bound the size and export time of each codelab,
+	// and fail codelabs fetching other files or URLs with imports,
+	// code includes, attachments or manifests. It hardens exports
+	// of untrusted sources.
+	Limits *Limits
+	// MaxTestedAge fails the export if the Last Tested watermark is older.
+	MaxTestedAge time.Duration
+	// NoOpenComments fails the export of Google Docs codelabs with
+	// unresolved comments or pending suggestions.
+	NoOpenComments bool
+	// NumberFigures numbers the images captioned by the captions pass
+	// as figures.
+	NumberFigures bool
+	// Offline serves remote fetches with the responses recorded in Fixtures.
+	Offline bool
+	// OnlyStatus, if not empty, is the statuses of the codelabs to export,
+	// such as published. Other codelabs, and those without a status, are skipped.
+	OnlyStatus []string
 	// Output is the output directory, or "-" for stdout.
 	Output string
+	// PWA makes HTML output a progressive web app, installable and working
+	// offline once visited, with a manifest and a service worker.
+	PWA bool
+	// Paper is the paper size of printed HTML output, one of render.PaperSizes,
+	// or empty for the default size of the printer.
+	Paper string
 	// PassMetadata are the extra metadata fields to pass along.
 	PassMetadata map[string]bool
+	// Passes are the names of transform passes to apply before rendering.
+	Passes []string
 	// Prefix is a URL prefix to prepend when using HTML format.
 	Prefix string
+	// Progress, if not nil, is called with each phase of a codelab as it is
+	// exported: the fetch.Phase* phases, phaseTransform and phaseWrite.
+	Progress func(phase string)
+	// ProgressBar draws a progress bar of the exports of CmdExport on stderr,
+	// if it is a terminal.
+	ProgressBar bool
+	// Record records the responses of remote fetches in Fixtures.
+	Record bool
+	// Redaction, if not nil, looks for sensitive content such as API keys,
+	// and fails the export or masks it, as configured.
+	Redaction *transform.Redaction
+	// Release, if not empty, is the release directory to export codelabs to,
+	// under their output directory, and to record their changes since
+	// the previous release in, "auto" for the next vN release, or "tag"
+	// for the git tag of the commit of their source.
+	Release string
+	// Review, if not empty, is a previous version of the codelabs to mark
+	// the changes of their content since, as diff.Review does: a release
+	// directory, its outline.json or a codelab source.
+	Review string
+	// Retry is the policy of retrying failed remote fetches.
+	Retry fetch.RetryPolicy
+	// SanitizePolicy is a JSON file of HTML elements, attributes and URL
+	// schemes to allow in rendered content, as read by
+	// render.ReadSanitizePolicy, if any.
+	SanitizePolicy string
+	// SiteURL is the URL Output is published at. If not empty,
+	// a sitemap and an Atom feed of the codelabs in Output are written to it.
+	SiteURL string
+	// Snippets is a directory of shared snippets referenced as {{> name}}.
+	Snippets string
+	// Split writes each step of md and html output to its own file,
+	// along with an index of the steps.
+	Split bool
 	// Srcs is the sources to export codelabs from.
 	Srcs []string
+	// StampStatus adds the status of codelabs at the start of their first step,
+	// as previews do, so that drafts are not mistaken for published codelabs.
+	StampStatus bool
+	// Strict fails the export of codelabs with nodes which the output format
+	// cannot render, rather than warning about them.
+	Strict bool
+	// StrictMeta fails the export of codelabs with invalid metadata.
+	StrictMeta bool
+	// Suggest replaces the categories and tags of codelabs which Taxonomy
+	// does not allow with their canonical values, if it can tell them.
+	Suggest bool
+	// Taxonomy fails the export of codelabs with categories or tags
+	// it does not allow, if not nil.
+	Taxonomy *types.Taxonomy
+	// TestedAt is the date to set in Last Tested watermarks, if not zero.
+	TestedAt time.Time
+	// TOC emits a linked table of contents at the top of Markdown output.
+	TOC bool
+	// Theme is the name of a bundled theme, or the directory of a theme,
+	// branding HTML output, as loaded by render.LoadTheme.
+	Theme string
 	// Tmplout is the output format.
 	Tmplout string
+	// UpdatedAt is the date to set in Last Updated watermarks.
+	// If zero, the source modification time is used.
+	UpdatedAt time.Time
+	// Vars are the values substituted for {{name}} references in codelab content.
+	Vars map[string]string
+
+	comments []*fetch.Comment // open comments of the exported codelab
+	prune    bool             // remove unused images and attachments of previous exports
 }
 
 // CmdExport is the "claat export ..." subcommand.
 // It returns a process exit code.
-func CmdExport(opts CmdExportOptions) int {
+func CmdExport(ctx context.Context, opts CmdExportOptions) int {
 	var exitCode int
 	if len(opts.Srcs) == 0 {
-		log.Fatalf("Need at least one source. Try '-h' for options.")
+		logging.Fatalf("Need at least one source. Try '-h' for options.")
+	}
+	if opts.Split {
+		if err := checkSplit(opts.Tmplout, opts.Output); err != nil {
+			logging.Fatalf("%v", err)
+		}
+	}
+	if opts.Release != "" {
+		if err := checkRelease(opts.Release, opts.Output); err != nil {
+			logging.Fatalf("%v", err)
+		}
+		if opts.Review != "" {
+			logging.Fatalf("-release and -review are mutually exclusive")
+		}
+	}
+	if opts.Audience != "" && !knownAudience(opts.Audience) {
+		logging.Fatalf("unknown audience %q, want one of %s", opts.Audience, strings.Join(transform.Audiences, ", "))
+	}
+	switch opts.CloudShell {
+	case "", render.CloudShellEmbed, render.CloudShellLink:
+	default:
+		logging.Fatalf("unknown cloudshell mode %q, want one of %s", opts.CloudShell, strings.Join(render.CloudShellModes, ", "))
+	}
+	if _, ok := render.PaperSizes[opts.Paper]; opts.Paper != "" && !ok {
+		logging.Fatalf("unknown paper %q, want one of %s", opts.Paper, strings.Join(render.Papers(), ", "))
+	}
+	if opts.SanitizePolicy != "" {
+		if _, err := render.ReadSanitizePolicy(opts.SanitizePolicy); err != nil {
+			logging.Fatalf("Error reading %s: %v", opts.SanitizePolicy, err)
+		}
+	}
+	if opts.Theme != "" {
+		t, err := render.LoadTheme(opts.Theme)
+		if err != nil {
+			logging.Fatalf("%v", err)
+		}
+		// themes of directories are found again by update from anywhere
+		opts.Theme = t.Name
 	}
 	type result struct {
 		src  string
 		meta *types.Meta
 		err  error
 	}
-	srcs := util.Unique(opts.Srcs)
+	srcs, outputs, err := expandSources(ctx, util.Unique(opts.Srcs), opts)
+	if err != nil {
+		logging.Errorf("%v", err)
+		exitCode = 1
+	}
+	pr := newProgress(os.Stderr, len(srcs), opts.ProgressBar && isTerminal(os.Stderr))
+	if pr.bar {
+		prev := logging.SetOutput(pr)
+		defer logging.SetOutput(prev)
+	}
 	ch := make(chan *result, len(srcs))
-	for _, src := range srcs {
-		go func(src string) {
-			meta, err := ExportCodelab(src, nil, opts)
+	for i, src := range srcs {
+		go func(src, output string) {
+			o := opts
+			o.Output = output
+			o.Progress = pr.tracker(src)
+			if rel, err := filepath.Rel(opts.Output, output); err == nil && rel != "." && opts.SiteURL != "" {
+				// output of a Drive subfolder
+				o.SiteURL = siteLink(opts.SiteURL, filepath.ToSlash(rel))
+			}
+			meta, err := ExportCodelab(ctx, src, nil, o)
 			ch <- &result{src, meta, err}
-		}(src)
+		}(src, outputs[i])
 	}
 	for range srcs {
 		res := <-ch
-		if res.err != nil {
+		l := logging.With("source", res.src)
+		if res.err == nil {
+			l = l.With("id", res.meta.ID)
+		}
+		l = l.With(pr.finish(res.src)...)
+		if errors.Is(res.err, errSkipped) {
+			l.Infof("%v", res.err)
+		} else if res.err != nil {
 			exitCode = 1
-			log.Printf(reportErr, res.src, res.err)
+			l.Errorf("%v", res.err)
 		} else if !isStdout(opts.Output) {
-			log.Printf(reportOk, res.meta.ID)
+			l.Infof("exported")
+		}
+	}
+	pr.close()
+	if opts.SiteURL != "" && !isStdout(opts.Output) {
+		if err := writeSite(opts.Output, opts.SiteURL); err != nil {
+			logging.Errorf("%v", err)
+			exitCode = 1
 		}
 	}
 	return exitCode
 }
 
+// expandSources replaces sources of collections, such as drive://folderID
+// sources, with the codelabs they contain, as resolved by the fetch.Source
+// of each source.
+// It returns the resulting sources along with the output directory of each,
+// which mirrors the structure of their collection under opts.Output,
+// such as the Drive folder structure.
+// Collections which cannot be listed are reported in the returned error
+// and skipped.
+func expandSources(ctx context.Context, srcs []string, opts CmdExportOptions) ([]string, []string, error) {
+	f, err := opts.newFetcher(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var res, outputs []string
+	var errs []string
+	for _, src := range srcs {
+		entries, err := f.Resolve(ctx, src, opts.DriveMatch)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", src, err))
+			continue
+		}
+		for _, e := range entries {
+			out := opts.Output
+			if !isStdout(out) {
+				out = filepath.Join(out, filepath.FromSlash(e.Dir))
+			}
+			res = append(res, e.Src)
+			outputs = append(outputs, out)
+		}
+	}
+	if len(errs) > 0 {
+		return res, outputs, errors.New(strings.Join(errs, "\n"))
+	}
+	return res, outputs, nil
+}
+
 // ExportCodelab fetches codelab src from either local disk or remote,
 // parses and stores the results on disk, in a dir ancestored by output.
 //
@@ -97,132 +345,549 @@ func CmdExport(opts CmdExportOptions) int {
 // is printed to stdout.
 //
 // An alternate http.RoundTripper may be specified if desired. Leave null for default.
-func ExportCodelab(src string, rt http.RoundTripper, opts CmdExportOptions) (*types.Meta, error) {
-	f, err := fetch.NewFetcher(opts.AuthToken, opts.PassMetadata, rt)
+func ExportCodelab(ctx context.Context, src string, rt http.RoundTripper, opts CmdExportOptions) (*types.Meta, error) {
+	ctx, cancel := opts.withTimeout(ctx)
+	defer cancel()
+	ctx, end := traceExport(ctx, src, &opts)
+	meta, err := exportCodelab(ctx, src, rt, opts)
+	end(meta, err)
+	return meta, err
+}
+
+// exportCodelab implements ExportCodelab.
+func exportCodelab(ctx context.Context, src string, rt http.RoundTripper, opts CmdExportOptions) (*types.Meta, error) {
+	f, err := opts.newFetcher(rt)
+	if err != nil {
+		return nil, err
+	}
+	clab, err := f.SlurpCodelab(ctx, src, opts.Output)
 	if err != nil {
 		return nil, err
 	}
-	clab, err := f.SlurpCodelab(src, opts.Output)
+	logging.With("source", src, "id", clab.ID).Debugf("fetched %d steps", len(clab.Steps))
+	if len(clab.Translations) == 0 || isStdout(opts.Output) {
+		return exportSlurped(ctx, f, src, clab.Codelab, clab.Mod, clab.Imgs, clab.Files, opts)
+	}
+
+	// a set of translations: resolve their sources and export each,
+	// along with the original
+	clab.Locale = clab.LocaleOrDefault()
+	set := types.Translations{clab.Locale: src}
+	for l, ref := range clab.Translations {
+		if l != clab.Locale {
+			set[l] = fetch.ResolveSource(src, ref)
+		}
+	}
+	clab.Translations = set
+	meta, err := exportSlurped(ctx, f, src, clab.Codelab, clab.Mod, clab.Imgs, clab.Files, opts)
 	if err != nil {
 		return nil, err
 	}
+	for _, l := range set.Locales() {
+		if l == meta.Locale {
+			continue
+		}
+		tclab, err := f.SlurpTranslation(ctx, set[l], opts.Output, meta, l)
+		if err == nil {
+			_, err = exportSlurped(ctx, f, set[l], tclab.Codelab, tclab.Mod, tclab.Imgs, tclab.Files, opts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s translation %s: %v", l, set[l], err)
+		}
+	}
+	return meta, nil
+}
+
+// prepareCodelab transforms codelab clab, fetched by f, which may be nil,
+// and last modified at mod, before it is rendered with opts, and sets
+// the metadata computed from its content.
+// Warnings are logged with the label of the codelab.
+func prepareCodelab(ctx context.Context, f *fetch.Fetcher, label string, clab *types.Codelab, mod time.Time, opts CmdExportOptions) error {
+	if err := checkNodes(ctx, clab, opts.Limits); err != nil {
+		return err
+	}
+	if err := checkTaxonomy(label, &clab.Meta, opts.Taxonomy, opts.Suggest); err != nil {
+		return err
+	}
+	if err := opts.transformContent(label, clab); err != nil {
+		return err
+	}
+	if err := transform.UpdateDates(clab, opts.datesOptions(mod)); err != nil {
+		return err
+	}
+	if opts.EstimateDurations {
+		if err := transform.FillDurations(clab, estimateOptions(ctx, f)); err != nil {
+			return err
+		}
+	}
+	for _, w := range transform.ComputeDuration(clab, opts.DurationTolerance) {
+		logWarning(label, w)
+	}
+	for _, w := range transform.ValidateQuizzes(clab) {
+		logWarning(label, w)
+	}
+	for _, w := range transform.ValidateButtons(clab) {
+		logWarning(label, w)
+	}
+	if opts.Review != "" {
+		// the previous version is transformed the same way,
+		// so that only changes of the source are reviewed
+		prep := func(old *types.Codelab) error {
+			return opts.transformContent(opts.Review, old)
+		}
+		old, err := sourceOutline(ctx, opts.Review, opts.AuthToken, opts.PassMetadata, opts.fetcherOptions(), prep)
+		if err != nil {
+			return fmt.Errorf("-review: %v", err)
+		}
+		diff.Review(old, clab)
+		annotateComments(clab, opts.comments)
+	}
+	if opts.StampStatus {
+		stampStatus(clab)
+	}
+	// last, so that the content added above is redacted too
+	if err := redactCodelab(label, clab, opts.comments, opts.Redaction); err != nil {
+		return err
+	}
+	if err := checkUnsupported(label, opts.Tmplout, clab.Steps, opts.Strict); err != nil {
+		return err
+	}
+	clab.Quizzes = transform.Quizzes(clab)
+	clab.StepInfo = clab.StepsInfo()
+	return nil
+}
+
+// transformContent applies the content transformations of opts
+// to codelab clab, logging warnings with its label.
+func (opts CmdExportOptions) transformContent(label string, clab *types.Codelab) error {
+	to := transform.Options{
+		Audience: opts.Audience,
+		Captions: transform.CaptionOptions{Number: opts.NumberFigures, Label: opts.FigureLabel},
+		Emoji:    transform.EmojiOptions{Images: opts.EmojiImages},
+		Snippets: opts.Snippets,
+		Vars:     opts.varsOptions(),
+	}
+	return transformCodelab(label, clab, to, opts.Passes)
+}
+
+// redactCodelab looks for sensitive content in clab and its comments cc,
+// as configured by r, if not nil. Masked content is logged with label.
+func redactCodelab(label string, clab *types.Codelab, cc []*fetch.Comment, r *transform.Redaction) error {
+	if r == nil {
+		return nil
+	}
+	found, err := transform.Redact(clab, r)
+	if err != nil {
+		return err
+	}
+	var ff []transform.RedactField
+	for _, c := range cc {
+		loc := "comment " + c.ID
+		ff = append(ff, transform.RedactField{Loc: loc, Value: &c.Content}, transform.RedactField{Loc: loc, Value: &c.Quote})
+	}
+	more, err := transform.RedactFields(ff, r)
+	if err != nil {
+		return err
+	}
+	for _, w := range append(found, more...) {
+		logWarning(label, w+" masked")
+	}
+	return nil
+}
+
+// exportSlurped transforms and stores codelab clab, fetched from src by f
+// and last modified at mod, in the output directory of opts.
+// imgs and files are its images and attachments, as fetched.
+func exportSlurped(ctx context.Context, f *fetch.Fetcher, src string, clab *types.Codelab, mod time.Time, imgs, files map[string]string, opts CmdExportOptions) (*types.Meta, error) {
+	var err error
+	if opts.comments, err = openComments(ctx, f, src, opts); err != nil {
+		return nil, err
+	}
+	opts.progress(phaseTransform)
+	if err := prepareCodelab(ctx, f, src, clab, mod, opts); err != nil {
+		return nil, err
+	}
 
 	// codelab export context
-	lastmod := types.ContextTime(clab.Mod)
 	clab.Meta.Source = src
 	meta := &clab.Meta
 
 	dir := opts.Output // output dir or stdout
+	locales := meta.LocaleDirs()
+	var release string
 	if !isStdout(dir) {
 		dir = codelabDir(dir, meta)
 	}
+	if opts.Release != "" && !isStdout(dir) {
+		if release, err = releaseName(ctx, f, src, dir, opts.Release); err != nil {
+			return nil, err
+		}
+		locales = releaseLocales(locales, release)
+	}
+	logging.With("source", src, "id", meta.ID).Debugf("writing %s format to %s", opts.Tmplout, filepath.Join(dir, release))
+	opts.progress(phaseWrite)
 	// write codelab and its metadata to disk
-	return meta, writeCodelab(dir, clab.Codelab, opts.ExtraVars, &types.Context{
-		Env:     opts.Expenv,
-		Format:  opts.Tmplout,
-		Prefix:  opts.Prefix,
-		MainGA:  opts.GlobalGA,
-		Updated: &lastmod,
-	})
+	err = writeCodelab(ctx, filepath.Join(dir, release), clab, opts.ExtraVars, opts.exportContext(meta, mod, locales))
+	if err == nil && opts.Comments && opts.comments != nil && !isStdout(dir) {
+		err = writeComments(filepath.Join(dir, release), opts.comments)
+	}
+	if err == nil && opts.prune && !isStdout(dir) {
+		err = removeUnusedAssets(filepath.Join(dir, release), imgs, files)
+	}
+	if err != nil || release == "" {
+		return meta, err
+	}
+	return meta, recordRelease(dir, release, clab, mod)
 }
 
-func ExportCodelabMemory(src io.ReadCloser, w io.Writer, opts CmdExportOptions) (*types.Meta, error) {
+func ExportCodelabMemory(ctx context.Context, src io.ReadCloser, w io.Writer, opts CmdExportOptions) (*types.Meta, error) {
+	ctx, cancel := opts.withTimeout(ctx)
+	defer cancel()
+	ctx, end := traceExport(ctx, "", &opts)
+	meta, err := exportCodelabMemory(ctx, src, w, opts)
+	end(meta, err)
+	return meta, err
+}
+
+// exportCodelabMemory implements ExportCodelabMemory.
+func exportCodelabMemory(ctx context.Context, src io.ReadCloser, w io.Writer, opts CmdExportOptions) (*types.Meta, error) {
 	m := fetch.NewMemoryFetcher(opts.PassMetadata)
-	clab, err := m.SlurpCodelab(src)
+	m.IframeDomains = opts.IframeDomains
+	if opts.Limits != nil {
+		m.MaxSize = opts.Limits.MaxSourceSize
+	}
+	m.StrictMeta = opts.StrictMeta
+	opts.progress(fetch.PhaseParse)
+	clab, err := m.SlurpCodelab(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	opts.progress(phaseTransform)
+	if err := prepareCodelab(ctx, nil, clab.ID, clab.Codelab, clab.Mod, opts); err != nil {
+		return nil, err
+	}
+	opts.progress(phaseWrite)
+	return &clab.Meta, writeCodelabWriter(ctx, w, clab.Codelab, opts.ExtraVars, opts.exportContext(&clab.Meta, clab.Mod, clab.Meta.LocaleDirs()))
+}
+
+// ExportCodelabWriter fetches codelab src, like ExportCodelab, and writes it
+// in the opts.Tmplout format to w. Its images and metadata are not stored.
+func ExportCodelabWriter(ctx context.Context, src string, w io.Writer, opts CmdExportOptions) (*types.Meta, error) {
+	ctx, cancel := opts.withTimeout(ctx)
+	defer cancel()
+	ctx, end := traceExport(ctx, src, &opts)
+	meta, err := exportCodelabWriter(ctx, src, w, opts)
+	end(meta, err)
+	return meta, err
+}
+
+// exportCodelabWriter implements ExportCodelabWriter.
+func exportCodelabWriter(ctx context.Context, src string, w io.Writer, opts CmdExportOptions) (*types.Meta, error) {
+	f, err := opts.newFetcher(nil)
 	if err != nil {
 		return nil, err
 	}
+	clab, err := f.SlurpCodelab(ctx, src, stdout)
+	if err != nil {
+		return nil, err
+	}
+	clab.Meta.Source = src
+	if opts.comments, err = openComments(ctx, f, src, opts); err != nil {
+		return nil, err
+	}
+	opts.progress(phaseTransform)
+	if err := prepareCodelab(ctx, f, src, clab.Codelab, clab.Mod, opts); err != nil {
+		return nil, err
+	}
+	opts.progress(phaseWrite)
+	return &clab.Meta, writeCodelabWriter(ctx, w, clab.Codelab, opts.ExtraVars, opts.exportContext(&clab.Meta, clab.Mod, clab.Meta.LocaleDirs()))
+}
 
-	// codelab export context
-	lastmod := types.ContextTime(clab.Mod)
-	meta := &clab.Meta
-	ctx := &types.Context{
-		Env:     opts.Expenv,
-		Format:  opts.Tmplout,
-		Prefix:  opts.Prefix,
-		MainGA:  opts.GlobalGA,
-		Updated: &lastmod,
+// exportContext returns the export context of codelab meta, last modified
+// at mod and exported with opts. locales are the directories of the codelab
+// in each locale of its translations, relative to its own.
+func (opts CmdExportOptions) exportContext(meta *types.Meta, mod time.Time, locales map[string]string) *types.Context {
+	lastmod := types.ContextTime(mod)
+	return &types.Context{
+		Env:        opts.Expenv,
+		Format:     opts.Tmplout,
+		Prefix:     opts.Prefix,
+		MainGA:     opts.mainGA(),
+		Updated:    &lastmod,
+		TOC:        opts.TOC,
+		Split:      opts.Split,
+		Lightbox:   opts.Lightbox,
+		HTMLTheme:  opts.Theme,
+		Paper:      opts.Paper,
+		PWA:        opts.PWA,
+		CloudShell: opts.CloudShell,
+		Audience:   opts.Audience,
+		Locales:    locales,
+		PageURL:    opts.pageURL(meta),
+		Analytics:  opts.Analytics,
+		Provenance: opts.provenance(meta),
+
+		KeepRuntimeVars: opts.KeepRuntimeVars,
+		IconImages:      opts.IconImages,
+		SanitizePolicy:  opts.SanitizePolicy,
 	}
+}
 
-	return meta, writeCodelabWriter(w, clab.Codelab, opts.ExtraVars, ctx)
+// provenance returns the provenance of codelab meta, exported now with opts.
+func (opts CmdExportOptions) provenance(meta *types.Meta) *types.Provenance {
+	return &types.Provenance{
+		Source:   meta.Source,
+		Revision: meta.Revision,
+		Inputs:   meta.Inputs,
+		Version:  Version,
+		Profile:  opts.profile(),
+		Exported: types.ContextTime(time.Now()),
+	}
 }
 
-func writeCodelabWriter(w io.Writer, clab *types.Codelab, extraVars map[string]string, ctx *types.Context) error {
-	// main content file(s)
-	data := &struct {
-		render.Context
-		Current *types.Step
-		StepNum int
-		Prev    bool
-		Next    bool
-	}{Context: render.Context{
-		Env:      ctx.Env,
-		Prefix:   ctx.Prefix,
-		Format:   ctx.Format,
-		GlobalGA: ctx.MainGA,
-		Updated:  time.Time(*ctx.Updated).Format(time.RFC3339),
-		Meta:     &clab.Meta,
-		Steps:    clab.Steps,
-		Extra:    extraVars,
-	}}
-
-	if ctx.Format == "offline" {
+// profile returns the render options of opts, as key=value pairs.
+func (opts CmdExportOptions) profile() string {
+	profile := []string{"format=" + opts.Tmplout, "env=" + opts.Expenv}
+	if opts.Audience != "" {
+		profile = append(profile, "audience="+opts.Audience)
+	}
+	if opts.Prefix != "" {
+		profile = append(profile, "prefix="+opts.Prefix)
+	}
+	if len(opts.Passes) > 0 {
+		profile = append(profile, "passes="+strings.Join(opts.Passes, ","))
+	}
+	return strings.Join(profile, " ")
+}
+
+// mainGA returns the global Google Analytics account of opts,
+// which is empty if replaced by an analytics profile.
+func (opts CmdExportOptions) mainGA() string {
+	if opts.Analytics != nil {
+		return ""
+	}
+	return opts.GlobalGA
+}
+
+// pageURL returns the published URL of the page of a codelab with meta
+// exported to the output directory of opts, or "" if opts.SiteURL is empty.
+func (opts CmdExportOptions) pageURL(meta *types.Meta) string {
+	if opts.SiteURL == "" || isStdout(opts.Output) {
+		return ""
+	}
+	return siteLink(opts.SiteURL, meta.Dir()+"/")
+}
+
+// fetcherOptions returns fetch options of opts.
+func (opts CmdExportOptions) fetcherOptions() fetch.FetcherOptions {
+	var accept func(*types.Meta) error
+	if len(opts.OnlyStatus) > 0 {
+		accept = func(m *types.Meta) error {
+			return checkStatus(m, opts.OnlyStatus)
+		}
+	}
+	var maxSize int64
+	var maxNodes int
+	if opts.Limits != nil {
+		maxSize = opts.Limits.MaxSourceSize
+		maxNodes = opts.Limits.MaxNodes
+	}
+	return fetch.FetcherOptions{
+		Accept:         accept,
+		ADC:            opts.ADC,
+		DocsAPI:        opts.DocsAPI,
+		Fixtures:       opts.Fixtures,
+		IframeDomains:  opts.IframeDomains,
+		ImportCache:    opts.ImportCache,
+		ImportCacheTTL: opts.ImportCacheTTL,
+		MaxNodes:       maxNodes,
+		MaxSize:        maxSize,
+		NoExternal:     opts.Limits != nil,
+		Offline:        opts.Offline,
+		Progress:       opts.Progress,
+		Record:         opts.Record,
+		Retry:          opts.Retry,
+		StrictMeta:     opts.StrictMeta,
+	}
+}
+
+// newFetcher returns a fetcher of opts with alternate http.RoundTripper rt,
+// if not nil.
+func (opts CmdExportOptions) newFetcher(rt http.RoundTripper) (*fetch.Fetcher, error) {
+	f, err := fetch.NewFetcher(opts.AuthToken, opts.PassMetadata, rt, opts.fetcherOptions())
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// progress reports phase to opts.Progress, if any.
+func (opts CmdExportOptions) progress(phase string) {
+	if opts.Progress != nil {
+		opts.Progress(phase)
+	}
+}
+
+// datesOptions returns watermark date options of opts,
+// with mod as the default Last Updated date.
+func (opts CmdExportOptions) datesOptions(mod time.Time) transform.DatesOptions {
+	updated := opts.UpdatedAt
+	if updated.IsZero() {
+		updated = mod
+	}
+	return transform.DatesOptions{
+		Updated:      updated,
+		Tested:       opts.TestedAt,
+		MaxTestedAge: opts.MaxTestedAge,
+	}
+}
+
+// varsOptions returns variable substitution options of opts.
+func (opts CmdExportOptions) varsOptions() transform.VarsOptions {
+	return transform.VarsOptions{
+		Vars: opts.Vars,
+	}
+}
+
+// templateData is the data of format templates. The offline and split
+// output templates render the Current step, numbered StepNum from 1,
+// with links to the previous and next steps if Prev and Next are set.
+type templateData struct {
+	render.Context
+	Current *types.Step
+	StepNum int
+	Prev    bool
+	Next    bool
+}
+
+func writeCodelabWriter(ctx context.Context, w io.Writer, clab *types.Codelab, extraVars map[string]string, tc *types.Context) error {
+	theme, err := loadTheme(tc)
+	if err != nil {
+		return err
+	}
+	if tc.Format == "offline" {
 		return errors.New("exporting codelab offline is not supported for In-Memory Export")
 	}
+	if tc.Split {
+		return errors.New("exporting codelab split is not supported for In-Memory Export")
+	}
+	sanitizer, err := loadSanitizePolicy(tc)
+	if err != nil {
+		return err
+	}
+	data := &templateData{Context: renderContext(clab, extraVars, tc, theme, sanitizer)}
+
+	return render.Execute(w, tc.Format, data, render.WithContext(ctx))
+}
+
+// renderContext returns the template context of codelab clab exported
+// in the context tc, branded with theme and sanitized with sanitizer
+// if not nil, with the anchors of its steps computed once for all of them.
+// extraVars is extra variables to pass into the template context.
+func renderContext(clab *types.Codelab, extraVars map[string]string, tc *types.Context, theme *render.Theme, sanitizer *render.SanitizePolicy) render.Context {
+	return render.Context{
+		Env:        tc.Env,
+		Prefix:     tc.Prefix,
+		Format:     tc.Format,
+		GlobalGA:   tc.MainGA,
+		GlobalGA4:  tc.Analytics.MeasurementID(),
+		Updated:    time.Time(*tc.Updated).Format(time.RFC3339),
+		TOC:        tc.TOC,
+		Split:      tc.Split,
+		PageURL:    tc.PageURL,
+		Lightbox:   tc.Lightbox,
+		Theme:      theme,
+		Paper:      tc.Paper,
+		PWA:        tc.PWA,
+		CloudShell: tc.CloudShell,
+		Analytics:  tc.Analytics,
+		Meta:       &clab.Meta,
+		Provenance: tc.Provenance,
+		Steps:      clab.Steps,
+		Extra:      extraVars,
 
-	return render.Execute(w, ctx.Format, data)
+		KeepRuntimeVars: tc.KeepRuntimeVars,
+		Sanitizer:       sanitizer,
+		IconImages:      tc.IconImages,
+	}.WithAnchors()
 }
 
-// writeCodelab stores codelab main content in ctx.Format and its metadata
-// in JSON format on disk.
+// writeCodelab stores codelab main content in tc.Format and its metadata
+// in JSON format on disk, along with the files of a progressive web app
+// if tc.PWA is set.
 // extraVars is extra variables to pass into the template context.
-func writeCodelab(dir string, clab *types.Codelab, extraVars map[string]string, ctx *types.Context) error {
-	// output to stdout does not include metadata
+func writeCodelab(ctx context.Context, dir string, clab *types.Codelab, extraVars map[string]string, tc *types.Context) (err error) {
+	if tc.Split {
+		if err := checkSplit(tc.Format, dir); err != nil {
+			return err
+		}
+		setStepFiles(clab, tc)
+	}
+	theme, err := loadTheme(tc)
+	if err != nil {
+		return err
+	}
+	sanitizer, err := loadSanitizePolicy(tc)
+	if err != nil {
+		return err
+	}
+	if tc.PWA && !isStdout(dir) && !isMarkdown(tc.Format) {
+		// the service worker caches all other files, once written
+		defer func() {
+			if err == nil {
+				err = writePWA(dir, &clab.Meta)
+			}
+		}()
+	}
+	// output to stdout does not include metadata nor theme files
 	if !isStdout(dir) {
 		// make sure codelab dir exists
 		if err := os.MkdirAll(dir, 0755); err != nil {
 			return err
 		}
-		// codelab metadata
-		cm := &types.ContextMeta{Context: *ctx, Meta: clab.Meta}
-		f := filepath.Join(dir, metaFilename)
-		if err := writeMeta(f, cm); err != nil {
-			return err
+		// codelab metadata, kept as is by slides written next to the codelab
+		if tc.Format != "slides" {
+			cm := &types.ContextMeta{Context: *tc, Meta: clab.Meta}
+			if err := writeMeta(filepath.Join(dir, metaFilename), cm); err != nil {
+				return err
+			}
+			// external resources the codelab depends on
+			if err := writeResources(filepath.Join(dir, resourcesFilename), transform.Resources(clab)); err != nil {
+				return err
+			}
+		}
+		if theme != nil {
+			if err := theme.Write(dir); err != nil {
+				return err
+			}
 		}
 	}
 
 	// main content file(s)
-	data := &struct {
-		render.Context
-		Current *types.Step
-		StepNum int
-		Prev    bool
-		Next    bool
-	}{Context: render.Context{
-		Env:      ctx.Env,
-		Prefix:   ctx.Prefix,
-		Format:   ctx.Format,
-		GlobalGA: ctx.MainGA,
-		Updated:  time.Time(*ctx.Updated).Format(time.RFC3339),
-		Meta:     &clab.Meta,
-		Steps:    clab.Steps,
-		Extra:    extraVars,
-	}}
-	if ctx.Format != "offline" {
+	data := &templateData{Context: renderContext(clab, extraVars, tc, theme, sanitizer)}
+	if tc.Split {
+		return writeSplit(ctx, dir, clab, data)
+	}
+	if tc.Format != "offline" {
 		w := os.Stdout
 		if !isStdout(dir) {
-			ext := ctx.Format
-			if ext != "md" {
-				ext = "html"
+			name := "index.html"
+			switch tc.Format {
+			case "md":
+				name = "index.md"
+			case "tutorial":
+				name = "index." + render.TutorialExt
+			case "slides":
+				name = render.SlidesFile
 			}
-			f, err := os.Create(filepath.Join(dir, "index."+ext))
+			f, err := os.Create(filepath.Join(dir, name))
 			if err != nil {
 				return err
 			}
 			w = f
 			defer f.Close()
 		}
-		return render.Execute(w, ctx.Format, data)
+		return render.Execute(w, tc.Format, data, render.WithContext(ctx))
 	}
 	for i, step := range clab.Steps {
 		data.Current = step
@@ -242,13 +907,37 @@ func writeCodelab(dir string, clab *types.Codelab, extraVars map[string]string,
 			w = f
 			defer f.Close()
 		}
-		if err := render.Execute(w, ctx.Format, data); err != nil {
+		if err := render.Execute(w, tc.Format, data, render.WithContext(ctx)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// loadTheme loads the theme of export context tc, if any,
+// which Markdown output goes without.
+func loadTheme(tc *types.Context) (*render.Theme, error) {
+	if tc.HTMLTheme == "" || isMarkdown(tc.Format) {
+		return nil, nil
+	}
+	return render.LoadTheme(tc.HTMLTheme)
+}
+
+// loadSanitizePolicy reads the sanitize policy of export context tc,
+// if any, or returns nil.
+func loadSanitizePolicy(tc *types.Context) (*render.SanitizePolicy, error) {
+	if tc.SanitizePolicy == "" {
+		return nil, nil
+	}
+	return render.ReadSanitizePolicy(tc.SanitizePolicy)
+}
+
+// isMarkdown reports whether format is one of the built-in Markdown
+// formats: md, or tutorial for Cloud Shell tutorials.
+func isMarkdown(format string) bool {
+	return format == "md" || format == "tutorial"
+}
+
 // writeMeta writes codelab metadata to a local disk location
 // specified by path.
 func writeMeta(path string, cm *types.ContextMeta) error {
@@ -262,3 +951,13 @@ func writeMeta(path string, cm *types.ContextMeta) error {
 	b = append(b, '\n')
 	return ioutil.WriteFile(path, b, 0644)
 }
+
+// writeResources writes the resource manifest r to path, in JSON format.
+func writeResources(path string, r *types.Resources) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	return ioutil.WriteFile(path, b, 0644)
+}
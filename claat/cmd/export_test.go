@@ -17,15 +17,20 @@ package cmd_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/googlecodelabs/tools/claat/cmd"
+	"github.com/googlecodelabs/tools/claat/types"
 )
 
 func TestExportCodelabMemory(t *testing.T) {
@@ -68,7 +73,7 @@ func TestExportCodelabMemory(t *testing.T) {
 			}
 
 			// Given the same markdown input, ExportCodelabMemory should have the same output content as ExportCodelab
-			wantMeta, err := cmd.ExportCodelab(test.filePath, nil, opts)
+			wantMeta, err := cmd.ExportCodelab(context.Background(), test.filePath, nil, opts)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -89,13 +94,15 @@ func TestExportCodelabMemory(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			gotMeta, err := cmd.ExportCodelabMemory(testContent, gotBytes, opts)
+			gotMeta, err := cmd.ExportCodelabMemory(context.Background(), testContent, gotBytes, opts)
 			if err != nil {
 				t.Errorf("ExportCodelabMemory got error %q, want nil", err)
 			}
 
 			// Because the In-Memory codelab doesn't have the source, when comparing, we remove Source
+			// and its revision
 			wantMeta.Source = ""
+			wantMeta.Revision = ""
 			if !reflect.DeepEqual(wantMeta, gotMeta) {
 				t.Errorf("ExportCodelabMemory returns metadata:\n%+v\nwant:\n%+v\n", gotMeta, wantMeta)
 			}
@@ -115,6 +122,7 @@ func filterIgnoredLinePrefix(content string) string {
 	// 2. Some expected bugs to be resolved.
 	ignoredLinePrefix := []string{
 		"<meta name=\"original_source\" content=\"",
+		"<meta name=\"claat:", // provenance of the source and export
 		"doc-id=\"",
 		"last-updated=\"", // https://github.com/googlecodelabs/tools/issues/395
 	}
@@ -138,3 +146,396 @@ func filterIgnoredLinePrefix(content string) string {
 
 	return strings.Join(processedContent, "\n")
 }
+
+func TestExportCodelabTranslations(t *testing.T) {
+	src := t.TempDir()
+	codelab := func(id, title, extra string) string {
+		return "id: " + id + "\nsummary: s\n" + extra + "\n---\n\n# " + title + "\n\n## Step\nDuration: 1\n\nText.\n"
+	}
+	files := map[string]string{
+		"intro.md":    codelab("intro", "Intro", "translations: es=intro.es.md, ja=ja/intro.md\n"),
+		"intro.es.md": codelab("intro-es", "Introducción", ""),
+		"ja/intro.md": codelab("intro-ja", "紹介", ""),
+	}
+	for name, content := range files {
+		p := filepath.Join(src, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := t.TempDir()
+	opts := cmd.CmdExportOptions{Expenv: "web", Output: out, Tmplout: "html"}
+	meta, err := cmd.ExportCodelab(context.Background(), filepath.Join(src, "intro.md"), nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Locale != types.DefaultLocale {
+		t.Errorf("meta.Locale = %q; want %q", meta.Locale, types.DefaultLocale)
+	}
+	wantLocales := map[string]string{
+		"en": "../../en/intro",
+		"es": "../../es/intro",
+		"ja": "../../ja/intro",
+	}
+	for loc, title := range map[string]string{"en": "Intro", "es": "Introducción", "ja": "紹介"} {
+		b, err := ioutil.ReadFile(filepath.Join(out, loc, "intro", "codelab.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cm types.ContextMeta
+		if err := json.Unmarshal(b, &cm); err != nil {
+			t.Fatal(err)
+		}
+		if cm.ID != "intro" || cm.Locale != loc || cm.Title != title {
+			t.Errorf("%s: got id %q, locale %q, title %q; want intro, %s, %s", loc, cm.ID, cm.Locale, cm.Title, loc, title)
+		}
+		if diff := cmp.Diff(wantLocales, cm.Locales); diff != "" {
+			t.Errorf("%s: locales got diff (-want +got):\n%s", loc, diff)
+		}
+		if len(cm.Translations) != 3 {
+			t.Errorf("%s: translations = %v; want 3", loc, cm.Translations)
+		}
+	}
+}
+
+func TestExportResources(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "lab.md")
+	content := "id: lab\nsummary: s\n\n---\n\n# Lab\n\n## Setup\nDuration: 1\n\n" +
+		"```console\ngcloud services enable run.googleapis.com\ngsutil cp gs://lab-data/input.csv .\n```\n"
+	if err := ioutil.WriteFile(src, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	opts := cmd.CmdExportOptions{Expenv: "web", Output: out, Tmplout: "html"}
+	if _, err := cmd.ExportCodelab(context.Background(), src, nil, opts); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(out, "lab", "resources.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got types.Resources
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := types.Resources{
+		ID:         "lab",
+		GCSObjects: []*types.Resource{{Name: "gs://lab-data/input.csv", Steps: []int{1}}},
+		APIs:       []*types.Resource{{Name: "run.googleapis.com", Steps: []int{1}}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("resources.json got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestExportProvenance(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "lab.md")
+	img := filepath.Join(dir, "img.png")
+	content := "id: lab\nsummary: s\n\n---\n\n# Lab\n\n## Setup\nDuration: 1\n\nText.\n\n![img](img.png)\n"
+	if err := ioutil.WriteFile(src, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(img, []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mod := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	if err := os.Chtimes(src, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+	imgMod := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(img, imgMod, imgMod); err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	opts := cmd.CmdExportOptions{Expenv: "web", Output: out, Tmplout: "html"}
+	start := time.Now().Add(-time.Second)
+	if _, err := cmd.ExportCodelab(context.Background(), src, nil, opts); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(out, "lab", "codelab.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cm types.ContextMeta
+	if err := json.Unmarshal(b, &cm); err != nil {
+		t.Fatal(err)
+	}
+	var top map[string]interface{}
+	if err := json.Unmarshal(b, &top); err != nil {
+		t.Fatal(err)
+	}
+	if rev, ok := top["revision"]; ok {
+		t.Errorf("codelab.json has a revision %v outside of its provenance", rev)
+	}
+	p := cm.Provenance
+	if p == nil {
+		t.Fatal("codelab.json has no provenance")
+	}
+	if time.Time(p.Exported).Before(start) {
+		t.Errorf("exported at %v; want after %v", time.Time(p.Exported), start)
+	}
+	p.Exported = types.ContextTime{}
+	if diff := cmp.Diff(map[string]string{img: "2026-02-01T00:00:00Z"}, p.Inputs); diff != "" {
+		t.Errorf("inputs mismatch (-want +got):\n%s", diff)
+	}
+	want := &types.Provenance{
+		Source:   src,
+		Revision: "2026-03-04T05:06:07Z",
+		Inputs:   p.Inputs,
+		Version:  cmd.Version,
+		Profile:  "format=html env=web",
+	}
+	if diff := cmp.Diff(want, p, cmp.Comparer(func(a, b types.ContextTime) bool { return a == b })); diff != "" {
+		t.Errorf("provenance mismatch (-want +got):\n%s", diff)
+	}
+
+	html, err := ioutil.ReadFile(filepath.Join(out, "lab", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range []string{
+		`<meta name="claat:revision" content="2026-03-04T05:06:07Z">`,
+		`<meta name="claat:profile" content="format=html env=web">`,
+	} {
+		if !strings.Contains(string(html), tag) {
+			t.Errorf("index.html has no %s", tag)
+		}
+	}
+}
+
+func TestExportStepInfo(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "lab.md")
+	content := "id: lab\nsummary: s\n\n---\n\n# Lab\n\n## Setup\nDuration: 2\n\nText\n\n" +
+		"## Extra credit\nDuration: 5\n\nOptional: yes\n\nProducts: Cloud Storage, BigQuery\n\nEnvironment: web\n\nMore text\n"
+	if err := ioutil.WriteFile(src, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	opts := cmd.CmdExportOptions{Expenv: "web", Output: out, Tmplout: "html"}
+	if _, err := cmd.ExportCodelab(context.Background(), src, nil, opts); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(out, "lab", "codelab.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got types.Meta
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []*types.StepInfo{
+		{Title: "Setup", Duration: 2},
+		{Title: "Extra credit", Duration: 5, Environments: []string{"web"}, Optional: true, Products: []string{"Cloud Storage", "BigQuery"}},
+	}
+	if diff := cmp.Diff(want, got.StepInfo); diff != "" {
+		t.Errorf("codelab.json steps got diff (-want +got):\n%s", diff)
+	}
+	b, err = ioutil.ReadFile(filepath.Join(out, "lab", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if step := `<google-codelab-step label="Extra credit" duration="5" optional products="Cloud Storage,BigQuery">`; !strings.Contains(string(b), step) {
+		t.Errorf("index.html does not contain %s", step)
+	}
+}
+
+func TestExportRenderOptions(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "lab.md")
+	content := "id: lab\nsummary: s\n\n---\n\n# Lab\n\n## Setup\n\nClick {icon:menu} or [call us](tel:123).\n"
+	if err := ioutil.WriteFile(src, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	policy := filepath.Join(dir, "sanitize.json")
+	if err := ioutil.WriteFile(policy, []byte(`{"schemes": ["tel"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	tests := []struct {
+		opts cmd.CmdExportOptions
+		file string
+		want string
+	}{
+		{cmd.CmdExportOptions{Tmplout: "md", IconImages: "https://example.com/{name}.svg"}, "index.md", `<img src="https://example.com/menu.svg"`},
+		{cmd.CmdExportOptions{Tmplout: "md"}, "index.md", "Click ☰ or"},
+		{cmd.CmdExportOptions{Tmplout: "html", SanitizePolicy: policy}, "index.html", `href="tel:123"`},
+	}
+	for _, tc := range tests {
+		tc.opts.Output = out
+		if _, err := cmd.ExportCodelab(context.Background(), src, nil, tc.opts); err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadFile(filepath.Join(out, "lab", tc.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), tc.want) {
+			t.Errorf("%s exported with %+v does not contain %s:\n%s", tc.file, tc.opts, tc.want, b)
+		}
+	}
+	// the default policy drops the tel: link
+	if _, err := cmd.ExportCodelab(context.Background(), src, nil, cmd.CmdExportOptions{Output: out, Tmplout: "html"}); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(out, "lab", "index.html")); err != nil || strings.Contains(string(b), "tel:123") {
+		t.Errorf("index.html without SanitizePolicy = %v; want no tel: link", err)
+	}
+}
+
+func TestExportSplit(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "lab.md")
+	content := "id: lab\nsummary: s\n\n---\n\n# Lab\n\n## Setup\nDuration: 2\n\nSee Step 3: Deploy.\n\n" +
+		"## Kiosk\nEnvironment: kiosk\n\nKiosk text\n\n## Deploy\nDuration: 5\n\nMore text\n"
+	if err := ioutil.WriteFile(src, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	dir := filepath.Join(out, "lab")
+	// step files of a previous export with more steps
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"step-3.md", "step-3.md.orig"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte("stale"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts := cmd.CmdExportOptions{Expenv: "web", Output: out, Split: true, Tmplout: "md"}
+	if _, err := cmd.ExportCodelab(context.Background(), src, nil, opts); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "codelab.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got types.ContextMeta
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Split {
+		t.Error("codelab.json split = false; want true")
+	}
+	var files []string
+	for _, si := range got.StepInfo {
+		files = append(files, si.File)
+	}
+	if diff := cmp.Diff([]string{"step-1.md", "", "step-2.md"}, files); diff != "" {
+		t.Errorf("codelab.json step files got diff (-want +got):\n%s", diff)
+	}
+	b, err = ioutil.ReadFile(filepath.Join(dir, "step-1.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"title: \"Setup\"\n", "next: \"step-2.md\"\n", "[Step 3: Deploy](step-2.md#deploy)"} {
+		if !strings.Contains(string(b), s) {
+			t.Errorf("step-1.md does not contain %q:\n%s", s, b)
+		}
+	}
+	b, err = ioutil.ReadFile(filepath.Join(dir, "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := "1. [Deploy](step-2.md) (5 min)\n"; !strings.Contains(string(b), s) {
+		t.Errorf("index.md does not contain %q:\n%s", s, b)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "step-3.md")); !os.IsNotExist(err) {
+		t.Errorf("stale step-3.md exists: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "step-3.md.orig")); err != nil {
+		t.Errorf("step-3.md.orig, which is not a step file, was removed: %v", err)
+	}
+
+	opts.Output = "-"
+	if _, err := cmd.ExportCodelab(context.Background(), src, nil, opts); err == nil {
+		t.Error("ExportCodelab(split to stdout) = nil error; want error")
+	}
+	var buf bytes.Buffer
+	if _, err := cmd.ExportCodelabWriter(context.Background(), src, &buf, opts); err == nil {
+		t.Error("ExportCodelabWriter(split) = nil error; want error")
+	}
+}
+
+func TestExportLabSequence(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "lab.md")
+	content := "id: lab\nsummary: s\nprerequisites: gcs-basics, https://example.com/labs/iam\nnext_labs: bigquery-intro\n\n---\n\n" +
+		"# Lab\n\n## Setup\n\nText\n\n## Done\n\nMore text\n"
+	if err := ioutil.WriteFile(src, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	opts := cmd.CmdExportOptions{Expenv: "web", Output: out, Tmplout: "html"}
+	if _, err := cmd.ExportCodelab(context.Background(), src, nil, opts); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(out, "lab", "codelab.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got types.Meta
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"gcs-basics", "https://example.com/labs/iam"}, got.Prerequisites); diff != "" {
+		t.Errorf("codelab.json prerequisites got diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"bigquery-intro"}, got.Next); diff != "" {
+		t.Errorf("codelab.json next got diff (-want +got):\n%s", diff)
+	}
+	b, err = ioutil.ReadFile(filepath.Join(out, "lab", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Before you begin", `href="../gcs-basics/"`, "What&#39;s next", `href="../bigquery-intro/"`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("index.html does not contain %s", want)
+		}
+	}
+}
+
+func TestExportSlides(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "lab.md")
+	content := "id: lab\nsummary: s\n\n---\n\n# Lab\n\n## Setup\nDuration: 2\n\nText\n"
+	if err := ioutil.WriteFile(src, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	opts := cmd.CmdExportOptions{Expenv: "web", Output: out, Tmplout: "html"}
+	if _, err := cmd.ExportCodelab(context.Background(), src, nil, opts); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(out, "lab")
+	meta, err := ioutil.ReadFile(filepath.Join(dir, "codelab.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Tmplout = "slides"
+	if _, err := cmd.ExportCodelab(context.Background(), src, nil, opts); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "slides.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "Reveal.initialize(") {
+		t.Errorf("slides.html is not a slide deck:\n%s", b)
+	}
+	b, err = ioutil.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "Reveal.initialize(") {
+		t.Error("index.html was overwritten by the slide deck")
+	}
+	b, err = ioutil.ReadFile(filepath.Join(dir, "codelab.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(meta), string(b)); diff != "" {
+		t.Errorf("codelab.json was overwritten by the slide deck (-want +got):\n%s", diff)
+	}
+}
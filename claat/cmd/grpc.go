@@ -0,0 +1,128 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/rpc"
+	"github.com/googlecodelabs/tools/claat/types"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	_ "google.golang.org/grpc/encoding/gzip" // accept compressed messages
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// CmdServeGRPC is the "claat serve -grpc" subcommand.
+// addr is the hostname and port to bind the gRPC server to,
+// and codelabs are exported with opts.
+// It serves until ctx is done, and returns a process exit code.
+func CmdServeGRPC(ctx context.Context, addr string, opts CmdExportOptions) int {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		logging.Fatalf("claat serve: %v", err)
+	}
+	// HTTP/2 without TLS, as gRPC clients and Cloud Run use
+	srv := NewGRPCServer(ctx, opts)
+	go func() {
+		<-ctx.Done()
+		logging.Infof("Shutting down")
+		srv.GracefulStop()
+	}()
+	logging.Infof("Serving the %s gRPC service on %s", rpc.ServiceName, addr)
+	if err := srv.Serve(lis); err != nil {
+		logging.Fatalf("claat serve: %v", err)
+	}
+	return 0
+}
+
+// NewGRPCServer returns a gRPC server of the Converter service,
+// rendering codelabs like the HTTP API does, with opts.
+// Exports are canceled once ctx is done, or at the deadline of their call,
+// which grpc-go sets from the grpc-timeout of the request.
+func NewGRPCServer(ctx context.Context, opts CmdExportOptions) *grpc.Server {
+	done := ctx.Done()
+	srv := grpc.NewServer(grpc.MaxRecvMsgSize(rpc.MaxMessageSize))
+	rpc.RegisterConverterServer(srv, rpc.NewServer(func(ctx context.Context, req *rpc.RenderRequest, w io.Writer) (*types.Meta, error) {
+		// exports are canceled on shutdown too
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			select {
+			case <-done:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		// continue the trace of the client, if any
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+		}
+		o := opts
+		o.Tmplout = req.Format
+		if o.Tmplout == "" {
+			o.Tmplout = "html"
+		}
+		if _, ok := apiFormats[o.Tmplout]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported format %q", req.Format)
+		}
+		var buf bytes.Buffer
+		var meta *types.Meta
+		var err error
+		if req.Doc != "" {
+			if !docIDRegexp.MatchString(req.Doc) {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid doc ID %q", req.Doc)
+			}
+			meta, err = ExportCodelabWriter(ctx, req.Doc, &buf, o)
+		} else {
+			meta, err = ExportCodelabMemory(ctx, ioutil.NopCloser(bytes.NewReader(req.Source)), &buf, o)
+		}
+		if err != nil {
+			return nil, err
+		}
+		_, err = buf.WriteTo(w)
+		return meta, err
+	}))
+	return srv
+}
+
+// metadataCarrier carries trace context propagation headers in gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
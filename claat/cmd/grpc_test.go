@@ -0,0 +1,58 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCServer(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewGRPCServer(context.Background(), CmdExportOptions{Expenv: "web"})
+	go srv.Serve(lis)
+	defer srv.Stop()
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := rpc.NewConverterClient(conn)
+
+	req := &rpc.RenderRequest{Source: []byte("id: lab\nsummary: s\n\n---\n\n# Lab\n\n## Setup\n\nText\n"), Format: "md"}
+	m, err := client.Render(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Id != "lab" || !strings.Contains(string(m.Content), "## Setup\n") {
+		t.Errorf("response ID %q content:\n%s\nwant lab and a Setup step", m.Id, m.Content)
+	}
+
+	_, err = client.Render(context.Background(), &rpc.RenderRequest{Format: "pdf"})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("Render(pdf) = %v; want %s", err, codes.InvalidArgument)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/i18n"
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/types"
+	"github.com/googlecodelabs/tools/claat/util"
+)
+
+// Options type to make the CmdI18nExtract signature succinct.
+type CmdI18nExtractOptions struct {
+	// AuthToken is the token to use for the Drive API.
+	AuthToken string
+	// ADC authorizes with Application Default Credentials instead of the user flow.
+	ADC bool
+	// DocsAPI fetches Google Docs with the Docs API instead of the Drive HTML export.
+	DocsAPI bool
+	// Format is the catalog format, i18n.FormatJSON or i18n.FormatXLIFF.
+	Format string
+	// Output is the output directory, or "-" for stdout.
+	Output string
+	// PassMetadata are the extra metadata fields to pass along.
+	PassMetadata map[string]bool
+	// Srcs is the sources to extract strings from.
+	Srcs []string
+}
+
+// CmdI18nExtract is the "claat i18n extract ..." subcommand.
+// It writes a catalog of the translatable strings of each source
+// to <id>.json or <id>.xlf in the output directory.
+// It returns a process exit code.
+func CmdI18nExtract(ctx context.Context, opts CmdI18nExtractOptions) int {
+	if len(opts.Srcs) == 0 {
+		logging.Fatalf("Need at least one source. Try '-h' for options.")
+	}
+	var exitCode int
+	for _, src := range util.Unique(opts.Srcs) {
+		id, err := extractCatalog(ctx, src, opts)
+		if err != nil {
+			logging.With("source", src).Errorf("%v", err)
+			exitCode = 1
+		} else if !isStdout(opts.Output) {
+			logging.With("source", src, "id", id).Infof("extracted")
+		}
+	}
+	return exitCode
+}
+
+// extractCatalog writes the catalog of codelab src and returns its ID.
+func extractCatalog(ctx context.Context, src string, opts CmdI18nExtractOptions) (string, error) {
+	fo := fetch.FetcherOptions{ADC: opts.ADC, DocsAPI: opts.DocsAPI}
+	f, err := fetch.NewFetcher(opts.AuthToken, opts.PassMetadata, nil, fo)
+	if err != nil {
+		return "", err
+	}
+	clab, err := f.SlurpCodelab(ctx, src, stdout)
+	if err != nil {
+		return "", err
+	}
+	c := i18n.Extract(clab.Codelab)
+	if isStdout(opts.Output) {
+		return c.ID, c.Write(os.Stdout, opts.Format)
+	}
+	if err := os.MkdirAll(opts.Output, 0755); err != nil {
+		return "", err
+	}
+	ext := ".json"
+	if opts.Format == i18n.FormatXLIFF {
+		ext = ".xlf"
+	}
+	w, err := os.Create(filepath.Join(opts.Output, c.ID+ext))
+	if err != nil {
+		return "", err
+	}
+	if err := c.Write(w, opts.Format); err != nil {
+		w.Close()
+		return "", err
+	}
+	return c.ID, w.Close()
+}
+
+// CmdI18nApply is the "claat i18n apply ..." subcommand.
+// The first of opts.Srcs is the codelab source, the others
+// are translated catalogs of its strings. The codelab is exported
+// with the translations of each catalog, in the directory of
+// the catalog target locale under opts.Output.
+// It returns a process exit code.
+func CmdI18nApply(ctx context.Context, opts CmdExportOptions) int {
+	if len(opts.Srcs) < 2 {
+		logging.Fatalf("Need a source and at least one catalog. Try '-h' for options.")
+	}
+	src := opts.Srcs[0]
+	var exitCode int
+	for _, file := range util.Unique(opts.Srcs[1:]) {
+		meta, err := applyCatalog(ctx, src, file, opts)
+		if err != nil {
+			logging.With("source", file).Errorf("%v", err)
+			exitCode = 1
+		} else if !isStdout(opts.Output) {
+			logging.With("source", file, "dir", meta.Dir()).Infof("translated")
+		}
+	}
+	return exitCode
+}
+
+// applyCatalog exports codelab src translated with catalog file.
+// Code blocks and protected markup of src are kept as is.
+func applyCatalog(ctx context.Context, src, file string, opts CmdExportOptions) (*types.Meta, error) {
+	c, err := readCatalog(file)
+	if err != nil {
+		return nil, err
+	}
+	if c.TargetLocale == "" {
+		return nil, fmt.Errorf("catalog has no target locale")
+	}
+	f, err := opts.newFetcher(nil)
+	if err != nil {
+		return nil, err
+	}
+	// the translated copy is part of a set of translations with src,
+	// so that it is stored under its locale next to the original
+	orig := &types.Meta{
+		ID:           c.ID,
+		URL:          c.ID,
+		Translations: types.Translations{c.SourceLocale: src, c.TargetLocale: file},
+	}
+	if isStdout(opts.Output) {
+		orig = nil
+	}
+	clab, err := f.SlurpTranslation(ctx, src, opts.Output, orig, c.TargetLocale)
+	if err != nil {
+		return nil, err
+	}
+	warns, err := c.Apply(clab.Codelab)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range warns {
+		logWarning(file, w)
+	}
+	return exportSlurped(ctx, f, src, clab.Codelab, clab.Mod, clab.Imgs, clab.Files, opts)
+}
+
+// readCatalog reads a catalog file, in XLIFF format if its extension
+// is .xlf or .xliff, and in JSON format otherwise.
+func readCatalog(file string) (*i18n.Catalog, error) {
+	r, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	format := catalogFormat(file)
+	c, err := i18n.Read(r, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s catalog: %v", format, err)
+	}
+	return c, nil
+}
+
+// catalogFormat returns the format of catalog file from its extension.
+func catalogFormat(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".xlf", ".xliff":
+		return i18n.FormatXLIFF
+	}
+	return i18n.FormatJSON
+}
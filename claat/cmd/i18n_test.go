@@ -0,0 +1,94 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/cmd"
+	"github.com/googlecodelabs/tools/claat/i18n"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestI18nExtractApply(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "intro.md")
+	content := "id: intro\nsummary: Learn\n\n---\n\n# Intro\n\n## Setup\nDuration: 1\n\nRun this command.\n\n```\nmake install\n```\n"
+	if err := ioutil.WriteFile(src, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	code := cmd.CmdI18nExtract(context.Background(), cmd.CmdI18nExtractOptions{Format: i18n.FormatJSON, Output: dir, Srcs: []string{src}})
+	if code != 0 {
+		t.Fatalf("CmdI18nExtract exit code = %d", code)
+	}
+	r, err := os.Open(filepath.Join(dir, "intro.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := i18n.Read(r, i18n.FormatJSON)
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	es := map[string]string{"Intro": "Introducción", "Setup": "Preparación", "Run this command.": "Ejecute este comando."}
+	for _, m := range c.Messages {
+		if strings.Contains(m.Source, "make") {
+			t.Errorf("catalog has code %q", m.Source)
+		}
+		m.Translation = es[m.Source]
+	}
+	c.TargetLocale = "es"
+	catalog := filepath.Join(dir, "es.json")
+	w, err := os.Create(catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Write(w, i18n.FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	out := t.TempDir()
+	opts := cmd.CmdExportOptions{Expenv: "web", Output: out, Tmplout: "md", Srcs: []string{src, catalog}}
+	if code := cmd.CmdI18nApply(context.Background(), opts); code != 0 {
+		t.Fatalf("CmdI18nApply exit code = %d", code)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(out, "es", "intro", "codelab.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cm types.ContextMeta
+	if err := json.Unmarshal(b, &cm); err != nil {
+		t.Fatal(err)
+	}
+	if cm.ID != "intro" || cm.Locale != "es" || cm.Title != "Introducción" {
+		t.Errorf("got id %q, locale %q, title %q; want intro, es, Introducción", cm.ID, cm.Locale, cm.Title)
+	}
+	b, err = ioutil.ReadFile(filepath.Join(out, "es", "intro", "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"## Preparación", "Ejecute este comando.", "make install"} {
+		if !strings.Contains(string(b), s) {
+			t.Errorf("output does not contain %q:\n%s", s, b)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/render"
+	"github.com/googlecodelabs/tools/claat/util"
+)
+
+// indexFilename is the catalog page written by the index command.
+const indexFilename = "index.html"
+
+// Options type to make the CmdIndex signature succinct.
+type CmdIndexOptions struct {
+	// ExtraVars is extra template variables.
+	ExtraVars map[string]string
+	// Output is the directory to write the catalog page to, or "-" for stdout.
+	// Links to codelabs are relative to it.
+	Output string
+	// SiteURL is the URL Output is published at. If not empty,
+	// a sitemap and an Atom feed of the codelabs are written to Output.
+	SiteURL string
+	// Srcs are the directories to scan for exported codelabs, recursively.
+	Srcs []string
+	// Tmplout is the catalog page template: "html" for the built-in page,
+	// or a path to a local template file.
+	Tmplout string
+}
+
+// CmdIndex is the "claat index ..." subcommand.
+// It returns a process exit code.
+func CmdIndex(opts CmdIndexOptions) int {
+	roots := opts.Srcs
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+	dirs, err := scanPaths(roots)
+	if err != nil {
+		logging.Fatalf("%v", err)
+	}
+	if len(dirs) == 0 {
+		logging.Fatalf("no codelabs found in %s", strings.Join(roots, ", "))
+	}
+	base := opts.Output
+	if isStdout(base) {
+		base = "."
+	}
+	data, err := indexCodelabs(dirs, base)
+	if err != nil {
+		logging.Errorf("%v", err)
+		return 1
+	}
+	data.Extra = opts.ExtraVars
+
+	w := os.Stdout
+	if !isStdout(opts.Output) {
+		if err := os.MkdirAll(opts.Output, 0755); err != nil {
+			logging.Errorf("%v", err)
+			return 1
+		}
+		f, err := os.Create(filepath.Join(opts.Output, indexFilename))
+		if err != nil {
+			logging.Errorf("%v", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := render.ExecuteIndex(w, opts.Tmplout, data); err != nil {
+		logging.Errorf("%v", err)
+		return 1
+	}
+	if opts.SiteURL != "" && !isStdout(opts.Output) {
+		if err := writeSiteFiles(opts.Output, opts.SiteURL, data); err != nil {
+			logging.Errorf("%v", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// indexCodelabs reads the metadata of the codelabs exported to dirs
+// and returns the catalog page context of those which are not hidden,
+// sorted by title, with links relative to the base directory.
+func indexCodelabs(dirs []string, base string) (*render.IndexContext, error) {
+	data := &render.IndexContext{}
+	var categories, products []string
+	for _, dir := range dirs {
+		cm, err := readMeta(filepath.Join(dir, metaFilename))
+		if err != nil {
+			return nil, err
+		}
+		if cm.Status != nil && hasStatus(*cm.Status, "hidden") || isOldRelease(dir) {
+			continue
+		}
+		rel, err := filepath.Rel(base, dir)
+		if err != nil {
+			return nil, err
+		}
+		e := &render.IndexEntry{ContextMeta: cm, Link: filepath.ToSlash(rel) + "/"}
+		switch cm.Format {
+		case "md":
+			e.Link += "index.md"
+		case "tutorial":
+			e.Link += "index." + render.TutorialExt
+		}
+		for _, st := range cm.StepInfo {
+			e.Products = append(e.Products, st.Products...)
+		}
+		e.Products = util.Unique(e.Products)
+		data.Codelabs = append(data.Codelabs, e)
+		categories = append(categories, cm.Categories...)
+		products = append(products, e.Products...)
+	}
+	sort.SliceStable(data.Codelabs, func(i, j int) bool {
+		a, b := data.Codelabs[i], data.Codelabs[j]
+		if a.Title != b.Title {
+			return a.Title < b.Title
+		}
+		return a.ID < b.ID
+	})
+	data.Categories = util.Unique(categories)
+	sort.Strings(data.Categories)
+	data.Products = util.Unique(products)
+	sort.Strings(data.Products)
+	return data, nil
+}
+
+// hasStatus reports whether status contains s, ignoring case.
+func hasStatus(status []string, s string) bool {
+	for _, v := range status {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/render"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestIndexCodelabs(t *testing.T) {
+	dir := t.TempDir()
+	for _, cm := range []*types.ContextMeta{
+		{
+			Context: types.Context{Format: "html"},
+			Meta: types.Meta{ID: "storage", Title: "Storage", Duration: 20, Categories: []string{"Cloud"},
+				StepInfo: []*types.StepInfo{{Products: []string{"Cloud Storage"}}, {Products: []string{"Cloud Storage", "BigQuery"}}}},
+		},
+		{
+			Context: types.Context{Format: "md"},
+			Meta:    types.Meta{ID: "android", Title: "Android", Duration: 45, Categories: []string{"Android"}},
+		},
+		{
+			Context: types.Context{Format: "html"},
+			Meta:    types.Meta{ID: "secret", Title: "Secret", Status: &types.LegacyStatus{"Hidden"}},
+		},
+	} {
+		d := filepath.Join(dir, "labs", cm.ID)
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(cm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(d, metaFilename), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dirs, err := scanPaths([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := indexCodelabs(dirs, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type entry struct{ ID, Link string }
+	var got []entry
+	for _, e := range data.Codelabs {
+		got = append(got, entry{e.ID, e.Link})
+	}
+	want := []entry{{"android", "labs/android/index.md"}, {"storage", "labs/storage/"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("indexCodelabs() codelabs got diff (-want +got): %s", diff)
+	}
+	if diff := cmp.Diff([]string{"Android", "Cloud"}, data.Categories); diff != "" {
+		t.Errorf("indexCodelabs() categories got diff (-want +got): %s", diff)
+	}
+	if diff := cmp.Diff([]string{"BigQuery", "Cloud Storage"}, data.Products); diff != "" {
+		t.Errorf("indexCodelabs() products got diff (-want +got): %s", diff)
+	}
+
+	var buf bytes.Buffer
+	if err := render.ExecuteIndex(&buf, "html", data); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`href="labs/storage/"`,
+		`data-categories="Cloud"`,
+		`data-duration="20"`,
+		`data-products="Cloud Storage|BigQuery"`,
+		`<option value="BigQuery">BigQuery</option>`,
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("index page does not contain %s", s)
+		}
+	}
+}
+
+func TestWriteSiteFiles(t *testing.T) {
+	t1 := types.ContextTime(time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC))
+	t2 := types.ContextTime(time.Date(2026, 5, 2, 10, 0, 0, 0, time.UTC))
+	data := &render.IndexContext{Codelabs: []*render.IndexEntry{
+		{ContextMeta: &types.ContextMeta{Context: types.Context{Updated: &t1}, Meta: types.Meta{Title: "Old", Summary: "Old lab"}}, Link: "old/"},
+		{ContextMeta: &types.ContextMeta{Context: types.Context{Updated: &t2}, Meta: types.Meta{Title: "New"}}, Link: "new/"},
+	}}
+	dir := t.TempDir()
+	if err := writeSiteFiles(dir, "https://example.com/labs/", data); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, sitemapFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := xml.Header + `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://example.com/labs/old/</loc>
+    <lastmod>2026-03-01</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/labs/new/</loc>
+    <lastmod>2026-05-02</lastmod>
+  </url>
+</urlset>
+`
+	if diff := cmp.Diff(want, string(b)); diff != "" {
+		t.Errorf("sitemap got diff (-want +got): %s", diff)
+	}
+
+	b, err = ioutil.ReadFile(filepath.Join(dir, feedFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = xml.Header + `<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Codelabs</title>
+  <id>https://example.com/labs/</id>
+  <link href="https://example.com/labs/"></link>
+  <updated>2026-05-02T10:00:00Z</updated>
+  <entry>
+    <title>New</title>
+    <id>https://example.com/labs/new/</id>
+    <link href="https://example.com/labs/new/"></link>
+    <updated>2026-05-02T10:00:00Z</updated>
+  </entry>
+  <entry>
+    <title>Old</title>
+    <id>https://example.com/labs/old/</id>
+    <link href="https://example.com/labs/old/"></link>
+    <updated>2026-03-01T10:00:00Z</updated>
+    <summary>Old lab</summary>
+  </entry>
+</feed>
+`
+	if diff := cmp.Diff(want, string(b)); diff != "" {
+		t.Errorf("feed got diff (-want +got): %s", diff)
+	}
+}
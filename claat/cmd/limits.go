@@ -0,0 +1,68 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// Limits bound the resources of each export, so that a single malicious
+// or oversized source cannot exhaust a shared exporter, such as the API
+// and gRPC services. Zero fields are not limited.
+type Limits struct {
+	// MaxNodes is the maximum number of content nodes of a codelab.
+	MaxNodes int
+	// MaxSourceSize is the maximum size of a codelab source,
+	// and of each of its images, in bytes.
+	MaxSourceSize int64
+	// Timeout is the maximum duration of an export, fetch included.
+	Timeout time.Duration
+}
+
+// DefaultLimits are the limits of -hardened exports.
+var DefaultLimits = Limits{
+	MaxNodes:      50000,
+	MaxSourceSize: 5 << 20,
+	Timeout:       time.Minute,
+}
+
+// withTimeout returns a copy of ctx canceled once the timeout of the limits
+// of opts, if any, expires, and the function releasing its timer.
+func (opts CmdExportOptions) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if opts.Limits == nil || opts.Limits.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, opts.Limits.Timeout)
+}
+
+// checkNodes returns an error if codelab clab has more content nodes
+// than allowed by limits l, which may be nil, or ctx is done.
+// The fetcher checks the nodes of the source before fetching its assets,
+// and this checks those of imports too.
+func checkNodes(ctx context.Context, clab *types.Codelab, l *Limits) error {
+	if l == nil {
+		return nil
+	}
+	var nn []nodes.Node
+	for _, s := range clab.Steps {
+		nn = append(nn, s.Content.Nodes...)
+	}
+	return fetch.CheckNodes(ctx, nn, l.MaxNodes)
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+const limitsCodelab = "id: lab\nsummary: s\n\n---\n\n# Lab\n\n## Setup\n\nSome **text** and more.\n"
+
+func TestExportCodelabMemoryLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits *Limits
+		err    string
+	}{
+		{"none", nil, ""},
+		{"default", &DefaultLimits, ""},
+		{"size", &Limits{MaxSourceSize: 20}, "larger than 20 bytes"},
+		{"nodes", &Limits{MaxNodes: 2}, "more than 2 content nodes"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := CmdExportOptions{Expenv: "web", Tmplout: "html", Limits: tc.limits}
+			var buf bytes.Buffer
+			_, err := ExportCodelabMemory(context.Background(), ioutil.NopCloser(strings.NewReader(limitsCodelab)), &buf, opts)
+			if tc.err == "" {
+				if err != nil {
+					t.Errorf("ExportCodelabMemory: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("ExportCodelabMemory err = %v; want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	ctx, cancel := CmdExportOptions{Limits: &Limits{Timeout: time.Millisecond}}.withTimeout(context.Background())
+	defer cancel()
+	<-ctx.Done()
+	if err := ctx.Err(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("context err = %v; want %v", err, context.DeadlineExceeded)
+	}
+
+	ctx, cancel = CmdExportOptions{}.withTimeout(context.Background())
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("context without limits has a deadline")
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/transform"
+	"github.com/googlecodelabs/tools/claat/types"
+	"github.com/googlecodelabs/tools/claat/util"
+)
+
+// Severities of lint findings.
+const (
+	lintError   = "error"
+	lintWarning = "warning"
+)
+
+// Options type to make the CmdLint signature succinct.
+type CmdLintOptions struct {
+	// AuthToken is the token to use for the Drive API.
+	AuthToken string
+	// ADC authorizes with Application Default Credentials instead of the user flow.
+	ADC bool
+	// DocsAPI fetches Google Docs with the Docs API instead of the Drive HTML export.
+	DocsAPI bool
+	// Format is the report format, either "text" or "json".
+	Format string
+	// PassMetadata are the extra metadata fields to pass along.
+	PassMetadata map[string]bool
+	// Srcs is the sources to lint. Local directories are scanned
+	// for Markdown sources, recursively.
+	Srcs []string
+}
+
+// LintFinding is an issue found in a codelab by a lint check.
+type LintFinding struct {
+	Source   string `json:"source"`
+	Check    string `json:"check"`
+	Severity string `json:"severity"`       // error or warning
+	Step     int    `json:"step,omitempty"` // Step number, if about a step
+	Message  string `json:"message"`
+}
+
+// lintChecks are the checks of the lint command, in report order.
+var lintChecks = []struct {
+	name     string
+	severity string
+	check    func(*types.Codelab) []string
+}{
+	{"secrets", lintError, transform.ScanSecrets},
+	{"quizzes", lintError, transform.ValidateQuizzes},
+	{"buttons", lintWarning, transform.ValidateButtons},
+	{"duration", lintWarning, func(clab *types.Codelab) []string {
+		return transform.ComputeDuration(clab, transform.DefaultDurationTolerance)
+	}},
+}
+
+// CmdLint is the "claat lint ..." subcommand.
+// It runs all lint checks on codelabs and writes a report of their findings
+// to stdout. It returns a process exit code, 1 if a codelab cannot be
+// fetched or has findings of error severity, such as credentials in code.
+func CmdLint(ctx context.Context, opts CmdLintOptions) int {
+	if len(opts.Srcs) == 0 {
+		logging.Fatalf("Need at least one source. Try '-h' for options.")
+	}
+	srcs, err := scanSources(util.Unique(opts.Srcs))
+	if err != nil {
+		logging.Fatalf("%v", err)
+	}
+	fo := fetch.FetcherOptions{ADC: opts.ADC, DocsAPI: opts.DocsAPI}
+	findings := make([][]*LintFinding, len(srcs))
+	errs := make([]error, len(srcs))
+	ch := make(chan int, len(srcs))
+	for i, src := range srcs {
+		go func(i int, src string) {
+			findings[i], errs[i] = lintSource(ctx, src, opts.AuthToken, opts.PassMetadata, fo)
+			ch <- i
+		}(i, src)
+	}
+	for range srcs {
+		<-ch
+	}
+	var exitCode int
+	var all []*LintFinding
+	for i, src := range srcs {
+		if errs[i] != nil {
+			logging.With("source", src).Errorf("%v", errs[i])
+			exitCode = 1
+		}
+		for _, f := range findings[i] {
+			if f.Severity == lintError {
+				exitCode = 1
+			}
+		}
+		all = append(all, findings[i]...)
+	}
+	if err := writeLint(os.Stdout, all, opts.Format); err != nil {
+		logging.Errorf("%v", err)
+		return 1
+	}
+	return exitCode
+}
+
+// lintSource fetches and parses codelab src and returns the findings
+// of all lint checks.
+func lintSource(ctx context.Context, src, authToken string, pm map[string]bool, fo fetch.FetcherOptions) ([]*LintFinding, error) {
+	f, err := fetch.NewFetcher(authToken, pm, nil, fo)
+	if err != nil {
+		return nil, err
+	}
+	clab, err := f.SlurpCodelab(ctx, src, stdout)
+	if err != nil {
+		return nil, err
+	}
+	return lintCodelab(src, clab.Codelab), nil
+}
+
+// lintCodelab runs all lint checks on clab, fetched from src.
+func lintCodelab(src string, clab *types.Codelab) []*LintFinding {
+	var res []*LintFinding
+	for _, c := range lintChecks {
+		for _, msg := range c.check(clab) {
+			lf := &LintFinding{Source: src, Check: c.name, Severity: c.severity, Message: msg}
+			if m := stepWarning.FindStringSubmatch(msg); m != nil {
+				lf.Step, _ = strconv.Atoi(m[1])
+			}
+			res = append(res, lf)
+		}
+	}
+	return res
+}
+
+// writeLint writes the lint report of findings to w, in format "json",
+// or else one finding per line.
+func writeLint(w io.Writer, findings []*LintFinding, format string) error {
+	if format == "json" {
+		if findings == nil {
+			findings = []*LintFinding{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(findings)
+	}
+	for _, f := range findings {
+		if _, err := fmt.Fprintf(w, "%s: %s: %s: %s\n", f.Source, f.Severity, f.Check, f.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/fetch/drive/auth"
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/util"
+)
+
+const (
+	// DefaultPreviewTTL is how long previews are kept by default.
+	DefaultPreviewTTL = 72 * time.Hour
+	// previewDir is the bucket directory previews are uploaded to.
+	previewDir = "previews"
+	// gcsUploadURL is the Cloud Storage JSON API upload endpoint.
+	gcsUploadURL = "https://storage.googleapis.com/upload/storage/v1/b/"
+	// gcsPublicURL is the public URL of Cloud Storage objects.
+	gcsPublicURL = "https://storage.googleapis.com/"
+)
+
+// Options type to make the CmdPreview signature succinct.
+type CmdPreviewOptions struct {
+	// Bucket is the Cloud Storage bucket to upload previews to.
+	Bucket string
+	// Export are the options to export the codelabs with.
+	// The output directory is ignored.
+	Export CmdExportOptions
+	// TTL is how long a preview is kept. Objects are deleted once expired
+	// by a lifecycle rule of the bucket.
+	TTL time.Duration
+}
+
+// CmdPreview is the "claat preview ..." subcommand.
+// It returns a process exit code.
+func CmdPreview(ctx context.Context, opts CmdPreviewOptions) int {
+	if len(opts.Export.Srcs) == 0 {
+		logging.Fatalf("Need at least one source. Try '-h' for options.")
+	}
+	if opts.Bucket == "" {
+		logging.Fatalf("Need -bucket. Try '-h' for options.")
+	}
+	client, err := auth.NewADCClient(nil, auth.ScopeStorageReadWrite)
+	if err != nil {
+		logging.Errorf("%v", err)
+		return 1
+	}
+	up := &gcsUploader{client: client, uploadURL: gcsUploadURL, bucket: opts.Bucket}
+	if err := preview(ctx, up, opts, os.Stdout); err != nil {
+		logging.Errorf("%v", err)
+		return 1
+	}
+	return 0
+}
+
+// preview exports the codelabs of opts to a temporary directory,
+// uploads it with up under a new random directory of previewDir
+// and writes the URL of each codelab to w.
+func preview(ctx context.Context, up *gcsUploader, opts CmdPreviewOptions, w io.Writer) error {
+	tmp, err := ioutil.TempDir("", "claat-preview")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	eo := opts.Export
+	eo.Output = tmp
+	eo.SiteURL = ""
+	eo.StampStatus = true
+	var dirs []string
+	for _, src := range util.Unique(eo.Srcs) {
+		meta, err := ExportCodelab(ctx, src, nil, eo)
+		if errors.Is(err, errSkipped) {
+			logging.With("source", src).Infof("%v", err)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", src, err)
+		}
+		dirs = append(dirs, meta.Dir())
+	}
+
+	id, err := previewID()
+	if err != nil {
+		return err
+	}
+	prefix := path.Join(previewDir, id)
+	expires := time.Now().Add(opts.TTL).UTC()
+	if err := up.uploadDir(tmp, prefix, expires); err != nil {
+		return err
+	}
+	for _, d := range dirs {
+		u := gcsPublicURL + path.Join(opts.Bucket, prefix, d, "index.html")
+		fmt.Fprintf(w, "%s\t%s\n", u, expires.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// previewID returns a random, unguessable name of a preview directory.
+func previewID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return time.Now().UTC().Format("20060102") + "-" + hex.EncodeToString(b), nil
+}
+
+// gcsUploader uploads files to a Cloud Storage bucket
+// with the JSON API.
+type gcsUploader struct {
+	client    *http.Client
+	uploadURL string // upload endpoint, with a trailing slash
+	bucket    string
+}
+
+// gcsObject is the metadata of an uploaded object.
+type gcsObject struct {
+	Name         string `json:"name"`
+	ContentType  string `json:"contentType,omitempty"`
+	CacheControl string `json:"cacheControl,omitempty"`
+	// CustomTime is the expiry of the object, for a lifecycle rule
+	// with a daysSinceCustomTime condition.
+	CustomTime string `json:"customTime,omitempty"`
+}
+
+// uploadDir uploads the files of local directory dir, recursively,
+// to objects under prefix, expiring at expires.
+func (up *gcsUploader) uploadDir(dir, prefix string, expires time.Time) error {
+	return filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		return up.upload(p, path.Join(prefix, filepath.ToSlash(rel)), expires)
+	})
+}
+
+// upload uploads local file p to object name, expiring at expires.
+// Objects with a zero expires do not expire.
+func (up *gcsUploader) upload(p, name string, expires time.Time) error {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	obj := &gcsObject{
+		Name:         name,
+		ContentType:  mime.TypeByExtension(filepath.Ext(p)),
+		CacheControl: "no-cache",
+	}
+	if !expires.IsZero() {
+		obj.CustomTime = expires.Format(time.RFC3339)
+	}
+	if obj.ContentType == "" {
+		obj.ContentType = "application/octet-stream"
+	}
+	meta, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	// multipart upload of metadata and content
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	parts := []struct {
+		typ  string
+		data []byte
+	}{
+		{"application/json; charset=UTF-8", meta},
+		{obj.ContentType, b},
+	}
+	for _, part := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {part.typ}})
+		if err != nil {
+			return err
+		}
+		if _, err := pw.Write(part.data); err != nil {
+			return err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+	u := up.uploadURL + url.PathEscape(up.bucket) + "/o?uploadType=multipart"
+	res, err := up.client.Post(u, "multipart/related; boundary="+mw.Boundary(), &body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1<<10))
+		return fmt.Errorf("upload %s: %s: %s", name, res.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
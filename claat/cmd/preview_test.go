@@ -0,0 +1,117 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPreview(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string]*gcsObject{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bucket/o" || r.URL.Query().Get("uploadType") != "multipart" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		part, err := multipart.NewReader(r.Body, params["boundary"]).NextPart()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var obj gcsObject
+		if err := json.NewDecoder(part).Decode(&obj); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		objects[obj.Name] = &obj
+		mu.Unlock()
+	}))
+	defer ts.Close()
+
+	up := &gcsUploader{client: ts.Client(), uploadURL: ts.URL + "/", bucket: "bucket"}
+	opts := CmdPreviewOptions{
+		Bucket: "bucket",
+		Export: CmdExportOptions{Srcs: []string{"testdata/simple-2-steps.md"}, Tmplout: "html"},
+		TTL:    time.Hour,
+	}
+	var out bytes.Buffer
+	if err := preview(context.Background(), up, opts, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	var prefix string
+	for name, obj := range objects {
+		i := strings.Index(name, "/example/")
+		if i < 0 || !strings.HasPrefix(name, previewDir+"/") {
+			t.Errorf("unexpected object %q", name)
+			continue
+		}
+		prefix = name[:i]
+		names = append(names, name[i+1:])
+		if obj.CustomTime == "" || obj.CacheControl != "no-cache" {
+			t.Errorf("object %q metadata = %+v; want an expiry custom time", name, obj)
+		}
+	}
+	sort.Strings(names)
+	want := []string{"example/codelab.json", "example/index.html", "example/resources.json"}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Errorf("uploaded objects got diff (-want +got): %s", diff)
+	}
+	if ct := objects[prefix+"/example/index.html"].ContentType; !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("index.html content type = %q; want text/html", ct)
+	}
+	if u := gcsPublicURL + "bucket/" + prefix + "/example/index.html\t"; !strings.HasPrefix(out.String(), u) {
+		t.Errorf("preview output = %q; want a line starting with %q", out.String(), u)
+	}
+}
+
+func TestPreviewUploadError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no access", http.StatusForbidden)
+	}))
+	defer ts.Close()
+	up := &gcsUploader{client: ts.Client(), uploadURL: ts.URL + "/", bucket: "bucket"}
+	p := filepath.Join(t.TempDir(), "index.html")
+	if err := ioutil.WriteFile(p, []byte("<p>hi</p>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := up.upload(p, "previews/x/index.html", time.Now())
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("upload() error = %v; want a 403 error", err)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+)
+
+// Profile kinds of ParseProfiles.
+const (
+	ProfileCPU = "cpu" // CPU time of the whole run
+	ProfileMem = "mem" // heap allocations, at the end of the run
+)
+
+// ParseProfiles parses a comma-delimited list of kind=path pairs,
+// such as "cpu=cpu.pprof,mem=mem.pprof", into the paths of the pprof
+// profiles to write by kind. An empty s is valid and has no profiles.
+func ParseProfiles(s string) (map[string]string, error) {
+	profiles := map[string]string{}
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		kind, path := p, ""
+		if i := strings.IndexByte(p, '='); i >= 0 {
+			kind, path = strings.TrimSpace(p[:i]), strings.TrimSpace(p[i+1:])
+		}
+		switch {
+		case kind != ProfileCPU && kind != ProfileMem:
+			return nil, fmt.Errorf("-profile: unknown profile %q, want %s or %s", kind, ProfileCPU, ProfileMem)
+		case path == "":
+			return nil, fmt.Errorf("-profile: no file for the %s profile, want %s=path", kind, kind)
+		case profiles[kind] != "":
+			return nil, fmt.Errorf("-profile: %s profile is given twice", kind)
+		}
+		profiles[kind] = path
+	}
+	return profiles, nil
+}
+
+// StartProfiles starts the profiles returned by ParseProfiles.
+// The returned stop function ends the CPU profile and writes the heap
+// profile; it must be called once the profiled work is done.
+func StartProfiles(profiles map[string]string) (stop func() error, err error) {
+	var cpu *os.File
+	if path := profiles[ProfileCPU]; path != "" {
+		if cpu, err = os.Create(path); err != nil {
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(cpu); err != nil {
+			cpu.Close()
+			return nil, err
+		}
+	}
+	stop = func() error {
+		var errs []string
+		if cpu != nil {
+			pprof.StopCPUProfile()
+			if err := cpu.Close(); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if path := profiles[ProfileMem]; path != "" {
+			if err := writeHeapProfile(path); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("writing profiles: %s", strings.Join(errs, "; "))
+		}
+		return nil
+	}
+	return stop, nil
+}
+
+// writeHeapProfile writes a heap profile to path, up to date
+// with the allocations of the last garbage collection.
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseProfiles(t *testing.T) {
+	tests := []struct {
+		in   string
+		want map[string]string
+		ok   bool
+	}{
+		{"", map[string]string{}, true},
+		{"cpu=cpu.pprof", map[string]string{"cpu": "cpu.pprof"}, true},
+		{" cpu = a.pprof , mem=b.pprof ", map[string]string{"cpu": "a.pprof", "mem": "b.pprof"}, true},
+		{"mem=out/mem=1.pprof", map[string]string{"mem": "out/mem=1.pprof"}, true},
+		{"cpu", nil, false},
+		{"cpu=", nil, false},
+		{"heap=heap.pprof", nil, false},
+		{"cpu=a.pprof,cpu=b.pprof", nil, false},
+	}
+	for _, tc := range tests {
+		got, err := ParseProfiles(tc.in)
+		if (err == nil) != tc.ok {
+			t.Errorf("ParseProfiles(%q) error = %v; want ok %v", tc.in, err, tc.ok)
+			continue
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("ParseProfiles(%q) got diff (-want +got): %s", tc.in, diff)
+		}
+	}
+}
+
+func TestStartProfiles(t *testing.T) {
+	dir := t.TempDir()
+	profiles := map[string]string{
+		ProfileCPU: filepath.Join(dir, "cpu.pprof"),
+		ProfileMem: filepath.Join(dir, "mem.pprof"),
+	}
+	stop, err := StartProfiles(profiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+	for kind, path := range profiles {
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Errorf("%s profile: %v", kind, err)
+			continue
+		}
+		if fi.Size() == 0 {
+			t.Errorf("%s profile %s is empty", kind, path)
+		}
+	}
+
+	if _, err := StartProfiles(map[string]string{ProfileCPU: filepath.Join(dir, "missing", "cpu.pprof")}); err == nil {
+		t.Error("StartProfiles() with an unwritable CPU profile returned no error")
+	}
+}
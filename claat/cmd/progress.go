@@ -0,0 +1,173 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Phases of exporting a codelab after it is fetched,
+// reported to CmdExportOptions.Progress.
+const (
+	phaseTransform = "transforming"
+	phaseWrite     = "writing"
+)
+
+// barWidth is the number of cells of a progress bar.
+const barWidth = 30
+
+// progress tracks the time each source of a batch export spends
+// in each phase and draws a progress bar of the batch, if enabled.
+// It is also an io.Writer of log entries, written above the bar.
+type progress struct {
+	mu      sync.Mutex
+	w       io.Writer
+	bar     bool                    // whether the bar is drawn
+	total   int                     // number of sources
+	done    int                     // number of finished sources
+	current string                  // last phase started, for the bar
+	timers  map[string]*sourceTimer // by source
+	drawn   bool                    // whether the bar is on screen
+	now     func() time.Time
+}
+
+// sourceTimer is the time spent by a source in each phase.
+type sourceTimer struct {
+	start  time.Time // of the first phase
+	phase  string    // current phase
+	since  time.Time // start of the current phase
+	phases []string  // in order of first start
+	spent  map[string]time.Duration
+}
+
+// stop adds the time spent in the current phase until now.
+func (t *sourceTimer) stop(now time.Time) {
+	if t.phase != "" {
+		t.spent[t.phase] += now.Sub(t.since)
+	}
+}
+
+// newProgress returns the progress of a batch of total sources,
+// drawing a progress bar on w if bar is true.
+func newProgress(w io.Writer, total int, bar bool) *progress {
+	return &progress{
+		w:      w,
+		bar:    bar,
+		total:  total,
+		timers: make(map[string]*sourceTimer),
+		now:    time.Now,
+	}
+}
+
+// tracker returns the progress func of the export options of src.
+func (p *progress) tracker(src string) func(phase string) {
+	return func(phase string) { p.phase(src, phase) }
+}
+
+// phase starts phase of src, ending its current phase.
+func (p *progress) phase(src, phase string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	t := p.timers[src]
+	if t == nil {
+		t = &sourceTimer{start: now, spent: make(map[string]time.Duration)}
+		p.timers[src] = t
+	}
+	t.stop(now)
+	if _, ok := t.spent[phase]; !ok {
+		t.spent[phase] = 0
+		t.phases = append(t.phases, phase)
+	}
+	t.phase, t.since = phase, now
+	p.current = src + ": " + phase
+	p.draw()
+}
+
+// finish ends the current phase of src. It returns log fields
+// of the batch progress, the time src spent in each phase and in total.
+func (p *progress) finish(src string) []interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	kv := []interface{}{"progress", fmt.Sprintf("%d/%d", p.done, p.total)}
+	if t := p.timers[src]; t != nil {
+		now := p.now()
+		t.stop(now)
+		for _, ph := range t.phases {
+			kv = append(kv, ph, t.spent[ph].Round(time.Millisecond).String())
+		}
+		kv = append(kv, "total", now.Sub(t.start).Round(time.Millisecond).String())
+		delete(p.timers, src)
+	}
+	p.draw()
+	return kv
+}
+
+// Write writes log entry b above the progress bar.
+func (p *progress) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	n, err := p.w.Write(b)
+	p.draw()
+	return n, err
+}
+
+// close removes the progress bar, which is no longer drawn.
+func (p *progress) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	p.bar = false
+}
+
+// draw draws the progress bar over the current line, if enabled, like:
+// [==========>                   ] 12/36 codelab.md: parsing
+func (p *progress) draw() {
+	if !p.bar {
+		return
+	}
+	n := barWidth * p.done / p.total
+	cells := strings.Repeat("=", n)
+	if n < barWidth {
+		cells += ">" + strings.Repeat(" ", barWidth-n-1)
+	}
+	cur := p.current
+	if len(cur) > 50 {
+		cur = "…" + cur[len(cur)-49:]
+	}
+	fmt.Fprintf(p.w, "\r\033[K[%s] %d/%d %s", cells, p.done, p.total, cur)
+	p.drawn = true
+}
+
+// clear erases the progress bar, if drawn.
+func (p *progress) clear() {
+	if p.drawn {
+		fmt.Fprint(p.w, "\r\033[K")
+		p.drawn = false
+	}
+}
+
+// isTerminal reports whether f is a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
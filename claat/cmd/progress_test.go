@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/fetch"
+)
+
+func TestProgress(t *testing.T) {
+	var b bytes.Buffer
+	p := newProgress(&b, 2, true)
+	now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+
+	track := p.tracker("a.md")
+	for _, step := range []struct {
+		phase string
+		d     time.Duration
+	}{
+		{fetch.PhaseFetch, 2 * time.Second},
+		{fetch.PhaseParse, 100 * time.Millisecond},
+		{fetch.PhaseAssets, time.Second},
+		{phaseTransform, 10 * time.Millisecond},
+		{phaseWrite, 20 * time.Millisecond},
+		// translations are fetched again
+		{fetch.PhaseFetch, time.Second},
+	} {
+		track(step.phase)
+		now = now.Add(step.d)
+	}
+	got := p.finish("a.md")
+	want := []interface{}{
+		"progress", "1/2",
+		"fetching", "3s",
+		"parsing", "100ms",
+		"assets", "1s",
+		"transforming", "10ms",
+		"writing", "20ms",
+		"total", "4.13s",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("finish mismatch (-want +got):\n%s", diff)
+	}
+
+	b.Reset()
+	p.Write([]byte("info\texported\n"))
+	wantOut := "\r\033[K" + "info\texported\n" +
+		"\r\033[K[===============>              ] 1/2 a.md: fetching"
+	if diff := cmp.Diff(wantOut, b.String()); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%q", diff)
+	}
+	b.Reset()
+	p.close()
+	p.Write([]byte("info\tdone\n"))
+	if got, want := b.String(), "\r\033[Kinfo\tdone\n"; got != want {
+		t.Errorf("output after close = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	textTemplate "text/template"
+	"unicode/utf8"
+
+	"github.com/googlecodelabs/tools/claat/types"
+	"golang.org/x/net/html"
+)
+
+// Files of codelabs exported as progressive web apps, installable
+// and working offline once visited.
+const (
+	pwaManifest = "manifest.webmanifest"
+	pwaWorker   = "sw.js"
+	pwaIcon     = "icon.svg"
+)
+
+// pwaThemeColor is the color of the title bar of installed codelabs.
+const pwaThemeColor = "#4285f4"
+
+// pwaIconSVG is the icon of installed codelabs.
+const pwaIconSVG = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 512 512"><rect width="512" height="512" rx="96" fill="` + pwaThemeColor + `"/><path d="M208 160l-96 96 96 96M304 160l96 96-96 96" fill="none" stroke="#fff" stroke-width="40" stroke-linecap="round" stroke-linejoin="round"/></svg>
+`
+
+// pwaSkipped are the files of an output directory not needed by its pages.
+var pwaSkipped = map[string]bool{
+	metaFilename:      true,
+	resourcesFilename: true,
+	commentsFilename:  true,
+	pwaWorker:         true,
+}
+
+// pwaWorkerTemplate is the service worker of a codelab. It caches its
+// files and the remote assets of its pages when installed, and serves
+// requests from the cache first, caching other responses as they come.
+// Codelab files are matched whatever their query, such as the ?step
+// of a page, and directories, such as the start URL, by their index.html.
+// Remote assets are matched exactly, since their query usually selects
+// their content, as in fonts.googleapis.com/css?family=Roboto.
+var pwaWorkerTemplate = textTemplate.Must(textTemplate.New(pwaWorker).Funcs(map[string]interface{}{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}).Parse(`// Service worker of codelab {{.ID}}, generated by claat.
+const PREFIX = {{json .Prefix}};
+const CACHE = {{json .Cache}};
+const FILES = {{json .Files}};
+const ASSETS = {{json .Assets}};
+
+self.addEventListener('install', (event) => {
+  event.waitUntil(caches.open(CACHE).then((cache) => Promise.all([
+    cache.addAll(FILES),
+    ...ASSETS.map((url) => fetch(url, {mode: 'no-cors'})
+      .then((res) => cache.put(url, res))
+      .catch(() => {})),
+  ])).then(() => self.skipWaiting()));
+});
+
+self.addEventListener('activate', (event) => {
+  event.waitUntil(caches.keys()
+    .then((keys) => Promise.all(keys
+      .filter((key) => key.startsWith(PREFIX) && key !== CACHE)
+      .map((key) => caches.delete(key))))
+    .then(() => self.clients.claim()));
+});
+
+self.addEventListener('fetch', (event) => {
+  if (event.request.method !== 'GET') {
+    return;
+  }
+  const url = new URL(event.request.url);
+  const local = url.origin === self.location.origin;
+  const key = local && url.pathname.endsWith('/') ? new URL('index.html', url).href : event.request;
+  event.respondWith(caches.match(key, {ignoreSearch: local}).then((cached) => {
+    if (cached) {
+      return cached;
+    }
+    return fetch(event.request).then((res) => {
+      if (res.ok || res.type === 'opaque') {
+        const copy = res.clone();
+        caches.open(CACHE).then((cache) => cache.put(event.request, copy));
+      }
+      return res;
+    });
+  }));
+});
+`))
+
+// webManifest is the web app manifest of a codelab.
+type webManifest struct {
+	Name            string            `json:"name"`
+	ShortName       string            `json:"short_name"`
+	Description     string            `json:"description,omitempty"`
+	StartURL        string            `json:"start_url"`
+	Scope           string            `json:"scope"`
+	Display         string            `json:"display"`
+	BackgroundColor string            `json:"background_color"`
+	ThemeColor      string            `json:"theme_color"`
+	Icons           []webManifestIcon `json:"icons"`
+}
+
+// webManifestIcon is an icon of a web app manifest.
+type webManifestIcon struct {
+	Src   string `json:"src"`
+	Sizes string `json:"sizes"`
+	Type  string `json:"type"`
+}
+
+// writePWA writes the manifest, icon and service worker of the codelab
+// of meta written to dir, so that it is installable and works offline.
+// It must be called once all the other files of the codelab are written,
+// which the service worker caches along with the remote assets of its pages.
+func writePWA(dir string, meta *types.Meta) error {
+	if err := ioutil.WriteFile(filepath.Join(dir, pwaIcon), []byte(pwaIconSVG), 0644); err != nil {
+		return err
+	}
+	short := meta.Title
+	if utf8.RuneCountInString(short) > 12 {
+		short = meta.ID
+	}
+	b, err := json.MarshalIndent(&webManifest{
+		Name:            meta.Title,
+		ShortName:       short,
+		Description:     meta.Summary,
+		StartURL:        "./",
+		Scope:           "./",
+		Display:         "standalone",
+		BackgroundColor: "#ffffff",
+		ThemeColor:      pwaThemeColor,
+		Icons:           []webManifestIcon{{Src: pwaIcon, Sizes: "any", Type: "image/svg+xml"}},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, pwaManifest), append(b, '\n'), 0644); err != nil {
+		return err
+	}
+
+	files, assets, version, err := pwaFiles(dir)
+	if err != nil {
+		return err
+	}
+	prefix := "claat-" + meta.ID + "-"
+	data := struct {
+		ID, Prefix, Cache string
+		Files, Assets     []string
+	}{
+		ID:     meta.ID,
+		Prefix: prefix,
+		Cache:  prefix + version,
+		Files:  files,
+		Assets: assets,
+	}
+	var buf bytes.Buffer
+	if err := pwaWorkerTemplate.Execute(&buf, data); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, pwaWorker), buf.Bytes(), 0644)
+}
+
+// pwaFiles returns the files of dir needed by its pages, such as pages,
+// images and theme files, the remote assets of its pages, such as scripts
+// and stylesheets, and a version of them all, which changes with any file.
+// Subdirectories of other codelabs, such as releases, are skipped.
+func pwaFiles(dir string) (files, assets []string, version string, err error) {
+	h := sha256.New()
+	seen := make(map[string]bool)
+	err = filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			if _, err := os.Stat(filepath.Join(p, metaFilename)); err == nil && rel != "." {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if pwaSkipped[rel] {
+			return nil
+		}
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		files = append(files, rel)
+		io.WriteString(h, rel+"\n")
+		h.Write(b)
+		if filepath.Ext(p) == ".html" {
+			for _, u := range remoteAssets(b) {
+				if !seen[u] {
+					seen[u] = true
+					assets = append(assets, u)
+				}
+			}
+		}
+		return nil
+	})
+	sort.Strings(assets)
+	for _, u := range assets {
+		io.WriteString(h, u+"\n")
+	}
+	return files, assets, hex.EncodeToString(h.Sum(nil))[:12], err
+}
+
+// remoteAssets returns the remote stylesheets, scripts and images of page b.
+func remoteAssets(b []byte) []string {
+	var res []string
+	z := html.NewTokenizer(bytes.NewReader(b))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return res
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			attrs := make(map[string]string, len(t.Attr))
+			for _, a := range t.Attr {
+				attrs[a.Key] = a.Val
+			}
+			var u string
+			switch t.Data {
+			case "link":
+				if attrs["rel"] == "stylesheet" {
+					u = attrs["href"]
+				}
+			case "script", "img":
+				u = attrs["src"]
+			}
+			if strings.HasPrefix(u, "https://") || strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "//") {
+				res = append(res, u)
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestWritePWA(t *testing.T) {
+	dir, err := ioutil.TempDir("", "claat-pwa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	page := `<html><head>
+<link rel="stylesheet" href="//fonts.googleapis.com/icon?family=Material+Icons">
+<link rel="stylesheet" href="theme/theme.css">
+<link rel="manifest" href="manifest.webmanifest">
+</head><body>
+<img src="img/a.png"><img src="https://example.com/b.png">
+<script src="https://example.com/elements.js"></script>
+</body></html>`
+	for name, s := range map[string]string{
+		"index.html":         page,
+		"img/a.png":          "a",
+		"theme/theme.css":    "body {}",
+		metaFilename:         "{}",
+		"v1/index.html":      page,
+		"v1/" + metaFilename: "{}",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(s), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	meta := &types.Meta{ID: "lab", Title: "Build a codelab", Summary: "Learn it"}
+	if err := writePWA(dir, meta); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, pwaManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m webManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != meta.Title || m.ShortName != "lab" || m.StartURL != "./" || len(m.Icons) != 1 {
+		t.Errorf("manifest = %+v", m)
+	}
+
+	b, err = ioutil.ReadFile(filepath.Join(dir, pwaWorker))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sw := string(b)
+	for _, want := range []string{
+		`const PREFIX = "claat-lab-";`,
+		`const FILES = ["icon.svg","img/a.png","index.html","manifest.webmanifest","theme/theme.css"];`,
+		`const ASSETS = ["//fonts.googleapis.com/icon?family=Material+Icons","https://example.com/b.png","https://example.com/elements.js"];`,
+		`caches.match(key, {ignoreSearch: local})`,
+	} {
+		if !strings.Contains(sw, want) {
+			t.Errorf("%s does not contain %s:\n%s", pwaWorker, want, sw)
+		}
+	}
+
+	// the cache is refreshed when a file changes
+	if err := ioutil.WriteFile(filepath.Join(dir, "img", "a.png"), []byte("a2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writePWA(dir, meta); err != nil {
+		t.Fatal(err)
+	}
+	b, err = ioutil.ReadFile(filepath.Join(dir, pwaWorker))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := func(s string) string {
+		for _, l := range strings.Split(s, "\n") {
+			if strings.HasPrefix(l, "const CACHE") {
+				return l
+			}
+		}
+		return ""
+	}
+	if cache(sw) == cache(string(b)) {
+		t.Errorf("CACHE %s did not change with the files", cache(sw))
+	}
+}
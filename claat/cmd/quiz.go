@@ -0,0 +1,84 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/transform"
+	"github.com/googlecodelabs/tools/claat/util"
+)
+
+// Options type to make the CmdQuizValidate signature succinct.
+type CmdQuizOptions struct {
+	// AuthToken is the token to use for the Drive API.
+	AuthToken string
+	// ADC authorizes with Application Default Credentials instead of the user flow.
+	ADC bool
+	// DocsAPI fetches Google Docs with the Docs API instead of the Drive HTML export.
+	DocsAPI bool
+	// PassMetadata are the extra metadata fields to pass along.
+	PassMetadata map[string]bool
+	// Srcs is the sources to validate. Local directories are scanned
+	// for Markdown sources, recursively.
+	Srcs []string
+}
+
+// CmdQuizValidate is the "claat quiz validate ..." subcommand.
+// It checks the scoring metadata of the quizzes of each source offline,
+// without exporting it, and reports every problem found.
+// It returns a process exit code.
+func CmdQuizValidate(ctx context.Context, opts CmdQuizOptions) int {
+	if len(opts.Srcs) == 0 {
+		logging.Fatalf("Need at least one source. Try '-h' for options.")
+	}
+	srcs, err := scanSources(util.Unique(opts.Srcs))
+	if err != nil {
+		logging.Fatalf("%v", err)
+	}
+	fo := fetch.FetcherOptions{ADC: opts.ADC, DocsAPI: opts.DocsAPI}
+	var exitCode int
+	for _, src := range srcs {
+		id, problems, err := validateQuizzes(ctx, src, opts.AuthToken, opts.PassMetadata, fo)
+		switch {
+		case err != nil:
+			logging.With("source", src).Errorf("%v", err)
+			exitCode = 1
+		case len(problems) > 0:
+			for _, p := range problems {
+				logging.With("source", src).Errorf("%v", p)
+			}
+			exitCode = 1
+		default:
+			logging.With("source", src, "id", id).Infof("valid")
+		}
+	}
+	return exitCode
+}
+
+// validateQuizzes fetches and parses codelab src and returns its ID
+// along with the problems of its quizzes.
+func validateQuizzes(ctx context.Context, src, authToken string, pm map[string]bool, fo fetch.FetcherOptions) (string, []string, error) {
+	f, err := fetch.NewFetcher(authToken, pm, nil, fo)
+	if err != nil {
+		return "", nil, err
+	}
+	clab, err := f.SlurpCodelab(ctx, src, stdout)
+	if err != nil {
+		return "", nil, err
+	}
+	return clab.ID, transform.ValidateQuizzes(clab.Codelab), nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/cmd"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+const quizSource = `id: %s
+summary: Learn
+
+---
+
+# Quiz
+
+## Check
+Duration: 1
+
+<form pass="2">
+<name points="2">Lists files?</name>
+<input value="ls" %s>
+<input value="cd">
+</form>
+`
+
+func writeQuiz(t *testing.T, dir, id, correct string) string {
+	t.Helper()
+	src := filepath.Join(dir, id+".md")
+	content := []byte(fmt.Sprintf(quizSource, id, correct))
+	if err := ioutil.WriteFile(src, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return src
+}
+
+func TestQuizValidate(t *testing.T) {
+	dir := t.TempDir()
+	valid := writeQuiz(t, dir, "valid", "correct")
+	invalid := writeQuiz(t, dir, "invalid", "")
+	if code := cmd.CmdQuizValidate(context.Background(), cmd.CmdQuizOptions{Srcs: []string{valid}}); code != 0 {
+		t.Errorf("CmdQuizValidate(valid) exit code = %d; want 0", code)
+	}
+	if code := cmd.CmdQuizValidate(context.Background(), cmd.CmdQuizOptions{Srcs: []string{valid, invalid}}); code != 1 {
+		t.Errorf("CmdQuizValidate(valid, invalid) exit code = %d; want 1", code)
+	}
+}
+
+func TestExportQuizzes(t *testing.T) {
+	dir := t.TempDir()
+	src := writeQuiz(t, dir, "quiz", "correct")
+	out := filepath.Join(dir, "out")
+	if code := cmd.CmdExport(context.Background(), cmd.CmdExportOptions{Output: out, Srcs: []string{src}, Tmplout: "md"}); code != 0 {
+		t.Fatalf("CmdExport exit code = %d", code)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(out, "quiz", "codelab.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var meta types.Meta
+	if err := json.Unmarshal(b, &meta); err != nil {
+		t.Fatal(err)
+	}
+	want := []*types.Quiz{{
+		ID:     "quiz-1",
+		Step:   1,
+		Pass:   2,
+		Points: 2,
+		Questions: []*types.QuizQuestion{{
+			Name:    "Lists files?",
+			Points:  2,
+			Options: []string{"ls", "cd"},
+			Correct: []string{"ls"},
+		}},
+	}}
+	if diff := cmp.Diff(want, meta.Quizzes); diff != "" {
+		t.Errorf("codelab.json quizzes got diff (-want +got):\n%s", diff)
+	}
+}
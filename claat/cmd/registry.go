@@ -0,0 +1,335 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/transform"
+	"github.com/googlecodelabs/tools/claat/types"
+	"github.com/googlecodelabs/tools/claat/util"
+)
+
+// Registry maps output directories to the sources of their codelabs,
+// and the render profiles they are exported with, for the update command.
+type Registry struct {
+	// Profiles are named sets of render options.
+	Profiles map[string]*RenderProfile `json:"profiles,omitempty"`
+	// Codelabs are the registered sources.
+	Codelabs []*RegistryEntry `json:"codelabs"`
+}
+
+// RenderProfile is a set of render options of exports.
+// Empty fields keep the options of the command line.
+type RenderProfile struct {
+	Audience string   `json:"audience,omitempty"` // Instructor or student, like -audience
+	Env      string   `json:"env,omitempty"`      // Codelab environment, like -e
+	Format   string   `json:"format,omitempty"`   // Output format, like -f
+	Passes   []string `json:"passes,omitempty"`   // Transform passes, like -passes
+	Prefix   string   `json:"prefix,omitempty"`   // URL prefix of HTML output, like -prefix
+}
+
+// builtinProfiles are the render profiles of registries which do not
+// define profiles of the same name: instructor guides and student handouts
+// of the same sources.
+var builtinProfiles = map[string]*RenderProfile{
+	transform.AudienceInstructor: {Audience: transform.AudienceInstructor},
+	transform.AudienceStudent:    {Audience: transform.AudienceStudent},
+}
+
+// knownAudience reports whether audience is one of transform.Audiences.
+func knownAudience(audience string) bool {
+	return audience == transform.AudienceInstructor || audience == transform.AudienceStudent
+}
+
+// profile returns the render profile called name, either defined
+// by reg or built in, and false if there is none.
+func (reg *Registry) profile(name string) (*RenderProfile, bool) {
+	if p, ok := reg.Profiles[name]; ok {
+		return p, true
+	}
+	p, ok := builtinProfiles[name]
+	return p, ok
+}
+
+// RegistryEntry is a source registered in a Registry. Its categories
+// and tags, if any, are used by filters until it is first exported,
+// after which those of its codelabs are used.
+type RegistryEntry struct {
+	Source     string   `json:"source"`               // Codelab source, or collection such as drive://folderID
+	Output     string   `json:"output"`               // Output directory, relative to the registry file
+	Profile    string   `json:"profile,omitempty"`    // Name of its render profile
+	Categories []string `json:"categories,omitempty"` // Categories, until first exported
+	Tags       []string `json:"tags,omitempty"`       // Tags, until first exported
+}
+
+// ReadRegistry reads a registry from JSON file path, resolving its output
+// directories relative to path.
+func ReadRegistry(path string) (*Registry, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	reg := &Registry{}
+	if err := json.Unmarshal(b, reg); err != nil {
+		return nil, err
+	}
+	for name, p := range reg.Profiles {
+		if p != nil && p.Audience != "" && !knownAudience(p.Audience) {
+			return nil, fmt.Errorf("profile %q: unknown audience %q", name, p.Audience)
+		}
+	}
+	for i, e := range reg.Codelabs {
+		if e.Source == "" || e.Output == "" {
+			return nil, fmt.Errorf("codelab %d: need a source and an output", i+1)
+		}
+		if _, ok := reg.profile(e.Profile); e.Profile != "" && !ok {
+			return nil, fmt.Errorf("%s: unknown profile %q", e.Source, e.Profile)
+		}
+		if !filepath.IsAbs(e.Output) {
+			e.Output = filepath.Join(filepath.Dir(path), e.Output)
+		}
+	}
+	return reg, nil
+}
+
+// apply returns opts with the options of profile p, which may be nil.
+func (p *RenderProfile) apply(opts CmdExportOptions) CmdExportOptions {
+	if p == nil {
+		return opts
+	}
+	if p.Audience != "" {
+		opts.Audience = p.Audience
+	}
+	if p.Env != "" {
+		opts.Expenv = p.Env
+	}
+	if p.Format != "" {
+		opts.Tmplout = p.Format
+	}
+	if p.Passes != nil {
+		opts.Passes = p.Passes
+	}
+	if p.Prefix != "" {
+		opts.Prefix = p.Prefix
+	}
+	return opts
+}
+
+// registryExport is a codelab of a registry entry to export.
+type registryExport struct {
+	src  string
+	opts CmdExportOptions
+	prev *types.ContextMeta // previous export, if any
+	dir  string             // directory of prev
+}
+
+// registryConcurrency is how many codelabs of a registry are checked
+// and exported at once.
+const registryConcurrency = 8
+
+// updateRegistry re-exports the stale codelabs of the registry of opts
+// which match its filters, and returns a process exit code.
+func updateRegistry(ctx context.Context, opts CmdUpdateOptions) int {
+	reg, err := ReadRegistry(opts.Registry)
+	if err != nil {
+		logging.Errorf("%v", err)
+		return 1
+	}
+	f, err := opts.Export.newFetcher(nil)
+	if err != nil {
+		logging.Errorf("%v", err)
+		return 1
+	}
+	var exitCode int
+	var exports []*registryExport
+	for _, e := range reg.Codelabs {
+		p, _ := reg.profile(e.Profile)
+		o := p.apply(opts.Export)
+		entries, err := f.Resolve(ctx, e.Source, o.DriveMatch)
+		if err != nil {
+			exitCode = 1
+			logging.With("source", e.Source).Errorf("%v", err)
+			continue
+		}
+		exported, err := exportedCodelabs(e.Output)
+		if err != nil {
+			exitCode = 1
+			logging.With("source", e.Source).Errorf("%v", err)
+			continue
+		}
+		for _, r := range entries {
+			x := &registryExport{src: r.Src, opts: o}
+			x.opts.prune = true
+			x.opts.Output = filepath.Join(e.Output, filepath.FromSlash(r.Dir))
+			if prev, ok := exported[r.Src]; ok {
+				x.dir, x.prev = prev.dir, prev.meta
+			}
+			categories, tags := e.Categories, e.Tags
+			if x.prev != nil {
+				categories, tags = x.prev.Categories, x.prev.Tags
+			}
+			if matchesAny(opts.Categories, categories) && matchesAny(opts.Tags, tags) {
+				exports = append(exports, x)
+			}
+		}
+	}
+
+	type result struct {
+		src    string
+		meta   *types.Meta
+		reason string
+		err    error
+	}
+	ch := make(chan *result, len(exports))
+	sem := make(chan struct{}, registryConcurrency)
+	for _, x := range exports {
+		go func(x *registryExport) {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			// random sleep up to 1 sec
+			// to reduce number of rate limit errors
+			select {
+			case <-time.After(time.Duration(rand.Intn(1000)) * time.Millisecond):
+			case <-ctx.Done():
+			}
+			reason := staleReason(x, func(src string) (string, error) {
+				return f.Revision(ctx, src)
+			})
+			if reason == "" {
+				ch <- &result{src: x.src}
+				return
+			}
+			meta, err := ExportCodelab(ctx, x.src, nil, x.opts)
+			if err == nil && x.prev != nil && x.dir != codelabDir(x.opts.Output, meta) {
+				// the ID has changed, and so has the output directory
+				err = os.RemoveAll(x.dir)
+			}
+			ch <- &result{x.src, meta, reason, err}
+		}(x)
+	}
+	for range exports {
+		res := <-ch
+		l := logging.With("source", res.src)
+		switch {
+		case errors.Is(res.err, errSkipped):
+			l.Infof("%v", res.err)
+		case res.err != nil:
+			exitCode = 1
+			l.Errorf("%v", res.err)
+		case res.reason == "":
+			l.Infof("up to date")
+		default:
+			l.With("id", res.meta.ID).Infof("updated: %s", res.reason)
+		}
+	}
+	return exitCode
+}
+
+// staleReason returns why the codelab of x needs to be exported again,
+// or an empty string if it is up to date, according to revision,
+// which returns the current revision of a source. The codelab is stale
+// if its source or any other source it was read from, such as an import
+// or an image, has a new revision.
+func staleReason(x *registryExport, revision func(string) (string, error)) string {
+	if x.prev == nil {
+		return "not exported yet"
+	}
+	p := x.prev.Provenance
+	if p == nil || p.Revision == "" {
+		return "no recorded revision"
+	}
+	if p.Profile != x.opts.profile() {
+		return fmt.Sprintf("profile changed from %q", p.Profile)
+	}
+	rev, err := revision(x.src)
+	if err != nil {
+		logging.With("source", x.src).Debugf("revision: %v", err)
+		return "unknown revision"
+	}
+	if rev != p.Revision {
+		return fmt.Sprintf("revision %s, exported %s", rev, p.Revision)
+	}
+	inputs := make([]string, 0, len(p.Inputs))
+	for src := range p.Inputs {
+		inputs = append(inputs, src)
+	}
+	sort.Strings(inputs)
+	for _, src := range inputs {
+		rev, err := revision(src)
+		if err != nil {
+			logging.With("source", x.src).Debugf("revision of %s: %v", src, err)
+			return fmt.Sprintf("unknown revision of %s", src)
+		}
+		if exported := p.Inputs[src]; rev != exported {
+			return fmt.Sprintf("%s revision %s, exported %s", src, rev, exported)
+		}
+	}
+	return ""
+}
+
+// exportedCodelab is a codelab previously exported in dir.
+type exportedCodelab struct {
+	dir  string
+	meta *types.ContextMeta
+}
+
+// exportedCodelabs returns the codelabs exported in output, recursively,
+// by source. A missing output has none.
+func exportedCodelabs(output string) (map[string]*exportedCodelab, error) {
+	res := make(map[string]*exportedCodelab)
+	dirs, err := walkPath(output)
+	if os.IsNotExist(err) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range dirs {
+		cm, err := readMeta(filepath.Join(d, metaFilename))
+		if err != nil {
+			return nil, err
+		}
+		res[cm.Source] = &exportedCodelab{d, cm}
+	}
+	return res, nil
+}
+
+// matchesAny reports whether any of values is in filter, ignoring case
+// and spaces, or filter is empty.
+func matchesAny(filter, values []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, v := range util.NormalizedSplit(strings.Join(values, ",")) {
+		for _, f := range filter {
+			if v == f {
+				return true
+			}
+		}
+	}
+	return false
+}
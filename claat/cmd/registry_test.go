@@ -0,0 +1,229 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestReadRegistry(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *Registry
+		err     string
+	}{
+		{
+			name: "valid",
+			content: `{"profiles": {"kiosk": {"format": "offline", "env": "kiosk"}},
+				"codelabs": [{"source": "a.md", "output": "site", "tags": ["web"]},
+				{"source": "b.md", "output": "/abs", "profile": "kiosk"}]}`,
+			want: &Registry{
+				Profiles: map[string]*RenderProfile{"kiosk": {Format: "offline", Env: "kiosk"}},
+				Codelabs: []*RegistryEntry{
+					{Source: "a.md", Output: "DIR/site", Tags: []string{"web"}},
+					{Source: "b.md", Output: "/abs", Profile: "kiosk"},
+				},
+			},
+		},
+		{
+			name: "builtin profiles",
+			content: `{"codelabs": [{"source": "a.md", "output": "guide", "profile": "instructor"},
+				{"source": "a.md", "output": "handout", "profile": "student"}]}`,
+			want: &Registry{
+				Codelabs: []*RegistryEntry{
+					{Source: "a.md", Output: "DIR/guide", Profile: "instructor"},
+					{Source: "a.md", Output: "DIR/handout", Profile: "student"},
+				},
+			},
+		},
+		{
+			name:    "unknown audience",
+			content: `{"profiles": {"guide": {"audience": "teacher"}}, "codelabs": []}`,
+			err:     `profile "guide": unknown audience "teacher"`,
+		},
+		{
+			name:    "no output",
+			content: `{"codelabs": [{"source": "a.md"}]}`,
+			err:     "codelab 1: need a source and an output",
+		},
+		{
+			name:    "unknown profile",
+			content: `{"codelabs": [{"source": "a.md", "output": "site", "profile": "kiosk"}]}`,
+			err:     `a.md: unknown profile "kiosk"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "registry.json")
+			if err := ioutil.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			reg, err := ReadRegistry(path)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("ReadRegistry err = %v; want %s", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range tc.want.Codelabs {
+				e.Output = strings.Replace(e.Output, "DIR", dir, 1)
+			}
+			if diff := cmp.Diff(tc.want, reg); diff != "" {
+				t.Errorf("ReadRegistry mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStaleReason(t *testing.T) {
+	opts := CmdExportOptions{Tmplout: "html", Expenv: "web"}
+	exported := func(rev, profile string, inputs ...string) *types.ContextMeta {
+		cm := &types.ContextMeta{}
+		cm.Provenance = &types.Provenance{Revision: rev, Profile: profile}
+		for i := 0; i < len(inputs); i += 2 {
+			if cm.Provenance.Inputs == nil {
+				cm.Provenance.Inputs = map[string]string{}
+			}
+			cm.Provenance.Inputs[inputs[i]] = inputs[i+1]
+		}
+		return cm
+	}
+	revision := func(src string) (string, error) {
+		if src == "gone" {
+			return "", errors.New("not found")
+		}
+		return "r2", nil
+	}
+	tests := []struct {
+		name string
+		src  string
+		prev *types.ContextMeta
+		want string
+	}{
+		{"not exported", "a", nil, "not exported yet"},
+		{"no provenance", "a", &types.ContextMeta{}, "no recorded revision"},
+		{"profile changed", "a", exported("r2", "format=md env=web"), `profile changed from "format=md env=web"`},
+		{"unknown revision", "gone", exported("r2", "format=html env=web"), "unknown revision"},
+		{"new revision", "a", exported("r1", "format=html env=web"), "revision r2, exported r1"},
+		{"up to date", "a", exported("r2", "format=html env=web"), ""},
+		{"new input revision", "a", exported("r2", "format=html env=web", "b.md", "r2", "img.png", "r1"), "img.png revision r2, exported r1"},
+		{"unknown input revision", "a", exported("r2", "format=html env=web", "gone", "r2"), "unknown revision of gone"},
+		{"inputs up to date", "a", exported("r2", "format=html env=web", "b.md", "r2", "img.png", "r2"), ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			x := &registryExport{src: tc.src, opts: opts, prev: tc.prev}
+			if got := staleReason(x, revision); got != tc.want {
+				t.Errorf("staleReason = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdateRegistry(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	web := write("web.md", "id: web\nsummary: s\ntags: web\n\n---\n\n# Web\n\n## Setup\nDuration: 1\n\nText.\n\n![img](web.png)\n")
+	img := write("web.png", "png")
+	kiosk := write("kiosk.md", "id: kiosk\nsummary: s\ntags: kiosk\n\n---\n\n# Kiosk\n\n## Setup\nDuration: 1\n\nText.\n")
+	reg := write("registry.json", `{"profiles": {"md": {"format": "md"}}, "codelabs": [
+		{"source": "`+filepath.ToSlash(web)+`", "output": "site"},
+		{"source": "`+filepath.ToSlash(kiosk)+`", "output": "kiosk", "profile": "md", "tags": ["kiosk"]}]}`)
+	webOut := filepath.Join(dir, "site", "web", "index.html")
+	kioskOut := filepath.Join(dir, "kiosk", "kiosk", "index.md")
+	exists := func(p string) bool {
+		_, err := os.Stat(p)
+		return err == nil
+	}
+
+	opts := CmdUpdateOptions{
+		Export:   CmdExportOptions{Expenv: "web", Tmplout: "html"},
+		Registry: reg,
+		Tags:     []string{"kiosk"},
+	}
+	if code := CmdUpdate(context.Background(), opts); code != 0 {
+		t.Fatalf("CmdUpdate = %d", code)
+	}
+	if exists(webOut) || !exists(kioskOut) {
+		t.Fatalf("-tag kiosk exported %s: %v, %s: %v; want only the latter", webOut, exists(webOut), kioskOut, exists(kioskOut))
+	}
+
+	// kiosk is up to date and not exported again, unlike web
+	opts.Tags = nil
+	if err := os.Remove(kioskOut); err != nil {
+		t.Fatal(err)
+	}
+	if code := CmdUpdate(context.Background(), opts); code != 0 {
+		t.Fatalf("CmdUpdate = %d", code)
+	}
+	if !exists(webOut) || exists(kioskOut) {
+		t.Fatalf("exported %s: %v, %s: %v; want only the former", webOut, exists(webOut), kioskOut, exists(kioskOut))
+	}
+
+	// a new revision of kiosk is
+	mod := time.Now().Add(time.Hour)
+	if err := os.Chtimes(kiosk, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+	if code := CmdUpdate(context.Background(), opts); code != 0 {
+		t.Fatalf("CmdUpdate = %d", code)
+	}
+	if !exists(kioskOut) {
+		t.Errorf("%s of a new revision not exported", kioskOut)
+	}
+
+	// so is web, once its image changes, whose previous version is removed
+	imgs, err := filepath.Glob(filepath.Join(dir, "site", "web", "img", "*"))
+	if err != nil || len(imgs) != 1 {
+		t.Fatalf("images = %v, %v; want one", imgs, err)
+	}
+	if err := os.Remove(webOut); err != nil {
+		t.Fatal(err)
+	}
+	write("web.png", "new png")
+	if err := os.Chtimes(img, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+	if code := CmdUpdate(context.Background(), opts); code != 0 {
+		t.Fatalf("CmdUpdate = %d", code)
+	}
+	if !exists(webOut) {
+		t.Errorf("%s of a new image not exported", webOut)
+	}
+	if exists(imgs[0]) {
+		t.Errorf("previous image %s not removed", imgs[0])
+	}
+}
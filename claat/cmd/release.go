@@ -0,0 +1,212 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/diff"
+	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+const (
+	// releasesFilename lists the releases of a codelab in its output directory.
+	releasesFilename = "releases.json"
+	// changelogFilename is the changelog of the releases of a codelab,
+	// next to releasesFilename.
+	changelogFilename = "CHANGELOG.md"
+	// outlineFilename is the outline of a codelab release, compared
+	// to that of the next release.
+	outlineFilename = "outline.json"
+	// autoRelease is the release name of the next vN release.
+	autoRelease = "auto"
+	// tagRelease is the release name of the git tag of the codelab source.
+	tagRelease = "tag"
+)
+
+// release is a codelab export to a release directory.
+type release struct {
+	Name     string         `json:"name"`
+	Updated  time.Time      `json:"updated"`
+	Previous string         `json:"previous,omitempty"` // Release compared to, if any
+	Changes  []*diff.Change `json:"changes"`            // Changes since Previous
+}
+
+// autoName matches the names of releases numbered by autoRelease.
+var autoName = regexp.MustCompile(`^v(\d+)$`)
+
+// checkRelease returns an error if codelabs cannot be exported
+// to output in release name.
+func checkRelease(name, output string) error {
+	if isStdout(output) {
+		return fmt.Errorf("-release: releases cannot be written to stdout")
+	}
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("-release: %q is not a valid directory name", name)
+	}
+	return nil
+}
+
+// readReleases returns the releases of the codelab exported to dir,
+// in order, or nil if it has none.
+func readReleases(dir string) ([]*release, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, releasesFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rr []*release
+	if err := json.Unmarshal(b, &rr); err != nil {
+		return nil, fmt.Errorf("%s: %v", releasesFilename, err)
+	}
+	return rr, nil
+}
+
+// releaseName returns the name of release name of codelab src exported
+// to dir: name itself, the next vN release if it is autoRelease, or
+// the git tag of the commit of src if it is tagRelease.
+func releaseName(ctx context.Context, f *fetch.Fetcher, src, dir, name string) (string, error) {
+	if name == tagRelease {
+		tag, err := f.GitTag(ctx, src)
+		if err != nil {
+			return "", err
+		}
+		return tag, checkRelease(tag, dir)
+	}
+	if name != autoRelease {
+		return name, nil
+	}
+	rr, err := readReleases(dir)
+	if err != nil {
+		return "", err
+	}
+	var last int
+	for _, r := range rr {
+		if m := autoName.FindStringSubmatch(r.Name); m != nil {
+			if n, _ := strconv.Atoi(m[1]); n > last {
+				last = n
+			}
+		}
+	}
+	return "v" + strconv.Itoa(last+1), nil
+}
+
+// releaseLocales returns the directories of the translations of
+// release name of a codelab, relative to it, given those of
+// the codelab output directory.
+func releaseLocales(dirs map[string]string, name string) map[string]string {
+	if len(dirs) == 0 {
+		return dirs
+	}
+	res := make(map[string]string, len(dirs))
+	for l, d := range dirs {
+		res[l] = path.Join("..", d, name)
+	}
+	return res
+}
+
+// recordRelease stores the outline of clab, exported to release name of
+// dir, and records its changes since the previous release in the releases
+// and changelog of dir. An existing release of the same name is replaced.
+func recordRelease(dir, name string, clab *types.Codelab, updated time.Time) error {
+	rr, err := readReleases(dir)
+	if err != nil {
+		return err
+	}
+	o := diff.NewOutline(clab)
+	if err := diff.WriteOutline(filepath.Join(dir, name, outlineFilename), o); err != nil {
+		return err
+	}
+	r := &release{Name: name, Updated: updated}
+	i := len(rr)
+	for k, old := range rr {
+		if old.Name == name {
+			i = k
+			break
+		}
+	}
+	if i > 0 {
+		prev := rr[i-1]
+		po, err := diff.ReadOutline(filepath.Join(dir, prev.Name, outlineFilename))
+		if err != nil {
+			return fmt.Errorf("release %s: %v", prev.Name, err)
+		}
+		r.Previous = prev.Name
+		r.Changes = diff.Compare(po, o)
+	}
+	if i == len(rr) {
+		rr = append(rr, r)
+	} else {
+		rr[i] = r
+	}
+
+	b, err := json.MarshalIndent(rr, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, releasesFilename), append(b, '\n'), 0644); err != nil {
+		return err
+	}
+	return writeChangelog(filepath.Join(dir, changelogFilename), clab.Title, rr)
+}
+
+// writeChangelog writes the changelog of releases rr of a codelab
+// titled title to file, in Markdown, latest release first.
+func writeChangelog(file, title string, rr []*release) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s changelog\n", title)
+	for i := len(rr) - 1; i >= 0; i-- {
+		r := rr[i]
+		fmt.Fprintf(&b, "\n## %s (%s)\n\n", r.Name, r.Updated.Format("2006-01-02"))
+		if r.Previous == "" {
+			b.WriteString("First release.\n")
+			continue
+		}
+		fmt.Fprintf(&b, "Changes since %s:\n\n", r.Previous)
+		if err := diff.Format(&b, r.Changes); err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(file, []byte(strings.TrimRight(b.String(), "\n")+"\n"), 0644)
+}
+
+// isOldRelease reports whether codelab dir is a release directory other
+// than the latest release of its codelab.
+func isOldRelease(dir string) bool {
+	rr, err := readReleases(filepath.Dir(dir))
+	if err != nil || len(rr) == 0 {
+		return false
+	}
+	name := filepath.Base(dir)
+	for _, r := range rr[:len(rr)-1] {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -15,27 +15,61 @@
 package cmd
 
 import (
-	"log"
+	"context"
+	"net"
 	"net/http"
 	"os/exec"
 	"runtime"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/logging"
 )
 
+// shutdownTimeout is how long servers wait for active requests
+// to complete once stopped.
+const shutdownTimeout = 10 * time.Second
+
 // CmdServe is the "claat serve ..." subcommand.
 // addr is the hostname and port to bind the web server to.
-// It returns a process exit code.
-func CmdServe(addr string) int {
-	http.Handle("/", http.FileServer(http.Dir(".")))
-	log.Printf("Serving codelabs on %s, opening browser tab now...", addr)
+// It serves until ctx is done, and returns a process exit code.
+func CmdServe(ctx context.Context, addr string) int {
+	logging.Infof("Serving codelabs on %s, opening browser tab now...", addr)
 	ch := make(chan error, 1)
 	go func() {
-		ch <- http.ListenAndServe(addr, nil)
+		ch <- listenAndServe(ctx, addr, http.FileServer(http.Dir(".")))
 	}()
 	openBrowser("http://" + addr)
-	log.Fatalf("claat serve: %v", <-ch)
+	if err := <-ch; err != nil {
+		logging.Fatalf("claat serve: %v", err)
+	}
 	return 0
 }
 
+// listenAndServe serves h on addr until ctx is done.
+// The requests get a context derived from ctx, cancelled once it is done,
+// and are given shutdownTimeout to complete.
+// It returns nil once the server is shut down.
+func listenAndServe(ctx context.Context, addr string, h http.Handler) error {
+	srv := &http.Server{
+		Addr:        addr,
+		Handler:     h,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	ch := make(chan error, 1)
+	go func() {
+		ch <- srv.ListenAndServe()
+	}()
+	select {
+	case err := <-ch:
+		return err
+	case <-ctx.Done():
+	}
+	logging.Infof("Shutting down")
+	sctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(sctx)
+}
+
 // openBrowser tries to open the URL in a browser.
 func openBrowser(url string) error {
 	var args []string
@@ -0,0 +1,43 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestListenAndServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan error, 1)
+	go func() {
+		ch <- listenAndServe(ctx, "127.0.0.1:0", http.NotFoundHandler())
+	}()
+	cancel()
+	select {
+	case err := <-ch:
+		if err != nil {
+			t.Errorf("listenAndServe() = %v; want nil once shut down", err)
+		}
+	case <-time.After(shutdownTimeout):
+		t.Fatal("listenAndServe() did not return once ctx was done")
+	}
+
+	if err := listenAndServe(context.Background(), "127.0.0.1:bad", http.NotFoundHandler()); err == nil {
+		t.Error("listenAndServe() with a bad address = nil; want an error")
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/render"
+)
+
+const (
+	// sitemapFilename is the sitemap of a directory of codelabs.
+	sitemapFilename = "sitemap.xml"
+	// feedFilename is the Atom feed of recently updated codelabs.
+	feedFilename = "feed.xml"
+	// feedSize is the maximum number of codelabs in the feed.
+	feedSize = 20
+)
+
+// sitemap is the XML document of the sitemaps.org protocol.
+type sitemap struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// atomFeed is an Atom syndication feed, as defined by RFC 4287.
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Link    atomLink    `xml:"link"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title   string   `xml:"title"`
+	ID      string   `xml:"id"`
+	Link    atomLink `xml:"link"`
+	Updated string   `xml:"updated"`
+	Summary string   `xml:"summary,omitempty"`
+}
+
+// writeSite writes the sitemap and the feed of the codelabs exported to dir,
+// recursively, to dir, published at siteURL.
+func writeSite(dir, siteURL string) error {
+	dirs, err := scanPaths([]string{dir})
+	if err != nil {
+		return err
+	}
+	data, err := indexCodelabs(dirs, dir)
+	if err != nil {
+		return err
+	}
+	return writeSiteFiles(dir, siteURL, data)
+}
+
+// writeSiteFiles writes the sitemap and the feed of the codelabs of data
+// to dir, published at siteURL.
+func writeSiteFiles(dir, siteURL string, data *render.IndexContext) error {
+	files := []struct {
+		name  string
+		write func(io.Writer, string, []*render.IndexEntry) error
+	}{
+		{sitemapFilename, writeSitemap},
+		{feedFilename, writeFeed},
+	}
+	for _, file := range files {
+		f, err := os.Create(filepath.Join(dir, file.name))
+		if err != nil {
+			return err
+		}
+		err = file.write(f, siteURL, data.Codelabs)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSitemap writes a sitemap of codelabs published at siteURL to w.
+func writeSitemap(w io.Writer, siteURL string, codelabs []*render.IndexEntry) error {
+	sm := &sitemap{}
+	for _, e := range codelabs {
+		u := sitemapURL{Loc: siteLink(siteURL, e.Link)}
+		if t := entryUpdated(e); !t.IsZero() {
+			u.LastMod = t.Format("2006-01-02")
+		}
+		sm.URLs = append(sm.URLs, u)
+	}
+	return writeXML(w, sm)
+}
+
+// writeFeed writes an Atom feed of the feedSize most recently updated
+// codelabs published at siteURL to w.
+func writeFeed(w io.Writer, siteURL string, codelabs []*render.IndexEntry) error {
+	recent := append([]*render.IndexEntry(nil), codelabs...)
+	sort.SliceStable(recent, func(i, j int) bool {
+		return entryUpdated(recent[i]).After(entryUpdated(recent[j]))
+	})
+	if len(recent) > feedSize {
+		recent = recent[:feedSize]
+	}
+	var updated time.Time
+	if len(recent) > 0 {
+		updated = entryUpdated(recent[0])
+	}
+	feed := &atomFeed{
+		Title:   "Codelabs",
+		ID:      siteLink(siteURL, ""),
+		Link:    atomLink{siteLink(siteURL, "")},
+		Updated: updated.Format(time.RFC3339),
+	}
+	for _, e := range recent {
+		link := siteLink(siteURL, e.Link)
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:   e.Title,
+			ID:      link,
+			Link:    atomLink{link},
+			Updated: entryUpdated(e).Format(time.RFC3339),
+			Summary: e.Summary,
+		})
+	}
+	return writeXML(w, feed)
+}
+
+// writeXML writes v to w as an indented XML document.
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// siteLink returns the URL of link, relative to siteURL.
+func siteLink(siteURL, link string) string {
+	return strings.TrimSuffix(siteURL, "/") + "/" + link
+}
+
+// entryUpdated returns the last update time of e, or zero if unknown.
+func entryUpdated(e *render.IndexEntry) time.Time {
+	if e.Updated == nil {
+		return time.Time{}
+	}
+	return time.Time(*e.Updated)
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/render"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// checkSplit returns an error if codelabs cannot be exported in format
+// to output with each step in its own file.
+func checkSplit(format, output string) error {
+	if _, _, ok := render.SplitTemplates(format); !ok {
+		return fmt.Errorf("-split: %s format cannot be split, want md or html", format)
+	}
+	if isStdout(output) {
+		return fmt.Errorf("-split: steps cannot be written to stdout")
+	}
+	return nil
+}
+
+// setStepFiles sets the files of the steps of clab in split output
+// of tc.Format. Steps of other environments than tc.Env have no file.
+func setStepFiles(clab *types.Codelab, tc *types.Context) {
+	if len(clab.StepInfo) != len(clab.Steps) {
+		clab.StepInfo = clab.StepsInfo()
+	}
+	var num int
+	for i, st := range clab.Steps {
+		clab.StepInfo[i].File = ""
+		if nodes.MatchEnv(st.Tags, tc.Env) {
+			num++
+			clab.StepInfo[i].File = render.StepFile(num, tc.Format)
+		}
+	}
+}
+
+// writeSplit writes each step of clab, rendered with data, to its own file
+// in dir, named as in the step info of clab, and the index of the steps.
+// Step files of a previous export with more steps are removed.
+func writeSplit(ctx context.Context, dir string, clab *types.Codelab, data *templateData) error {
+	index, step, _ := render.SplitTemplates(data.Format)
+	var last int
+	for _, si := range clab.StepInfo {
+		if si.File != "" {
+			last++
+		}
+	}
+	var num int
+	for i, st := range clab.Steps {
+		file := clab.StepInfo[i].File
+		if file == "" {
+			continue
+		}
+		num++
+		data.Current = st
+		data.StepNum = num
+		data.Prev = num > 1
+		data.Next = num < last
+		if err := writeTemplate(ctx, filepath.Join(dir, file), step, data); err != nil {
+			return err
+		}
+	}
+	data.Current, data.StepNum, data.Prev, data.Next = nil, 0, false, false
+	if err := removeStaleSteps(dir, clab, data.Format); err != nil {
+		return err
+	}
+	return writeTemplate(ctx, filepath.Join(dir, render.SplitIndexFile(data.Format)), index, data)
+}
+
+// removeStaleSteps removes the step files of split output in format
+// in dir which are not files of the steps of clab.
+func removeStaleSteps(dir string, clab *types.Codelab, format string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "step-*."+format))
+	if err != nil {
+		return err
+	}
+	current := make(map[string]bool, len(clab.StepInfo))
+	for _, si := range clab.StepInfo {
+		current[si.File] = true
+	}
+	for _, f := range files {
+		name := filepath.Base(f)
+		if current[name] || !render.IsStepFile(name, format) {
+			continue
+		}
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTemplate executes template name with data into file path.
+func writeTemplate(ctx context.Context, path, name string, data *templateData) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := render.Execute(f, name, data, render.WithContext(ctx)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,227 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/transform"
+	"github.com/googlecodelabs/tools/claat/types"
+	"github.com/googlecodelabs/tools/claat/util"
+)
+
+// Options type to make the CmdStats signature succinct.
+type CmdStatsOptions struct {
+	// AuthToken is the token to use for the Drive API.
+	AuthToken string
+	// ADC authorizes with Application Default Credentials instead of the user flow.
+	ADC bool
+	// DocsAPI fetches Google Docs with the Docs API instead of the Drive HTML export.
+	DocsAPI bool
+	// Format is the report format, either "table" or "json".
+	Format string
+	// PassMetadata are the extra metadata fields to pass along.
+	PassMetadata map[string]bool
+	// Srcs is the sources to report on. Local directories are scanned
+	// for Markdown sources, recursively.
+	Srcs []string
+}
+
+// CodelabStats are content statistics of a single codelab.
+type CodelabStats struct {
+	Source        string `json:"source"`
+	ID            string `json:"id,omitempty"`
+	Title         string `json:"title,omitempty"`
+	Steps         int    `json:"steps"`
+	Images        int    `json:"images"`
+	CodeBlocks    int    `json:"codeBlocks"`
+	ExternalLinks int    `json:"externalLinks"`
+	Infoboxes     int    `json:"infoboxes"`
+	Videos        int    `json:"videos"`
+	Duration      int    `json:"duration"` // Declared or computed duration in minutes
+	Estimate      int    `json:"estimate"` // Estimated duration in minutes
+	Error         string `json:"error,omitempty"`
+}
+
+// CmdStats is the "claat stats ..." subcommand.
+// It returns a process exit code.
+func CmdStats(ctx context.Context, opts CmdStatsOptions) int {
+	if len(opts.Srcs) == 0 {
+		logging.Fatalf("Need at least one source. Try '-h' for options.")
+	}
+	srcs, err := scanSources(util.Unique(opts.Srcs))
+	if err != nil {
+		logging.Fatalf("%v", err)
+	}
+	fo := fetch.FetcherOptions{ADC: opts.ADC, DocsAPI: opts.DocsAPI}
+	stats := make([]*CodelabStats, len(srcs))
+	ch := make(chan int, len(srcs))
+	for i, src := range srcs {
+		go func(i int, src string) {
+			stats[i] = sourceStats(ctx, src, opts.AuthToken, opts.PassMetadata, fo)
+			ch <- i
+		}(i, src)
+	}
+	var exitCode int
+	for range srcs {
+		if s := stats[<-ch]; s.Error != "" {
+			logging.With("source", s.Source).Errorf("%v", s.Error)
+			exitCode = 1
+		}
+	}
+	if err := writeStats(os.Stdout, stats, opts.Format); err != nil {
+		logging.Errorf("%v", err)
+		return 1
+	}
+	return exitCode
+}
+
+// scanSources replaces local directories of srcs with the Markdown files
+// they contain, recursively, skipping hidden directories.
+func scanSources(srcs []string) ([]string, error) {
+	var res []string
+	for _, src := range srcs {
+		fi, err := os.Stat(src)
+		if err != nil || !fi.IsDir() {
+			res = append(res, src)
+			continue
+		}
+		err = filepath.Walk(src, func(p string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if fi.IsDir() && p != src && strings.HasPrefix(fi.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if !fi.IsDir() && strings.ToLower(filepath.Ext(p)) == ".md" {
+				res = append(res, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// sourceStats fetches and parses codelab src and returns its statistics.
+// Errors are reported in the Error field of the result.
+func sourceStats(ctx context.Context, src, authToken string, pm map[string]bool, fo fetch.FetcherOptions) *CodelabStats {
+	res := &CodelabStats{Source: src}
+	f, err := fetch.NewFetcher(authToken, pm, nil, fo)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	clab, err := f.SlurpCodelab(ctx, src, stdout)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	s, err := codelabStats(clab.Codelab, estimateOptions(ctx, f))
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	s.Source = src
+	return s
+}
+
+// codelabStats counts content nodes of clab and estimates its duration.
+func codelabStats(clab *types.Codelab, eo transform.EstimateOptions) (*CodelabStats, error) {
+	s := &CodelabStats{
+		ID:       clab.ID,
+		Title:    clab.Title,
+		Steps:    len(clab.Steps),
+		Duration: clab.Duration,
+	}
+	if s.Duration == 0 {
+		s.Duration = int(transform.StepsDuration(clab).Minutes())
+	}
+	for _, st := range clab.Steps {
+		_, err := nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			if !entering {
+				return n, nil
+			}
+			switch n := n.(type) {
+			case *nodes.ImageNode:
+				s.Images++
+			case *nodes.CodeNode:
+				s.CodeBlocks++
+			case *nodes.URLNode:
+				if u, err := url.Parse(n.URL); err == nil && u.Host != "" {
+					s.ExternalLinks++
+				}
+			case *nodes.InfoboxNode:
+				s.Infoboxes++
+			case *nodes.YouTubeNode:
+				s.Videos++
+			}
+			return n, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	ee, err := transform.EstimateCodelab(clab, eo)
+	if err != nil {
+		return nil, err
+	}
+	var est time.Duration
+	for _, e := range ee {
+		est += e.Duration
+	}
+	s.Estimate = int((est + time.Minute - 1) / time.Minute)
+	return s, nil
+}
+
+// writeStats writes stats of codelabs which could be parsed to w,
+// as a table or in JSON format.
+func writeStats(w io.Writer, stats []*CodelabStats, format string) error {
+	var ok []*CodelabStats
+	for _, s := range stats {
+		if s.Error == "" {
+			ok = append(ok, s)
+		}
+	}
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(ok)
+	case "", "table":
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tSTEPS\tIMAGES\tCODE\tLINKS\tINFOBOXES\tVIDEOS\tDURATION\tESTIMATE")
+		for _, s := range ok {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n",
+				s.ID, s.Steps, s.Images, s.CodeBlocks, s.ExternalLinks, s.Infoboxes, s.Videos, s.Duration, s.Estimate)
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unknown stats format %q", format)
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/transform"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestCodelabStats(t *testing.T) {
+	clab := types.NewCodelab()
+	clab.ID = "lab"
+	st := clab.NewStep("One")
+	st.Duration = 5 * time.Minute
+	st.Content.Append(
+		nodes.NewImageNode(nodes.NewImageNodeOptions{Src: "img.png"}),
+		nodes.NewCodeNode("ls", true, ""),
+		nodes.NewURLNode("https://example.com", nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "example"})),
+		nodes.NewURLNode("#step-2"),
+		nodes.NewInfoboxNode(nodes.InfoboxPositive, nodes.NewCodeNode("pwd", true, "")),
+	)
+	clab.NewStep("Two").Content.Append(nodes.NewYouTubeNode("vid"))
+
+	s, err := codelabStats(clab, transform.EstimateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &CodelabStats{
+		ID:            "lab",
+		Steps:         2,
+		Images:        1,
+		CodeBlocks:    2,
+		ExternalLinks: 1,
+		Infoboxes:     1,
+		Videos:        1,
+		Duration:      5,
+		Estimate:      1,
+	}
+	if diff := cmp.Diff(want, s); diff != "" {
+		t.Errorf("codelabStats() got diff (-want +got): %s", diff)
+	}
+}
+
+func TestScanSources(t *testing.T) {
+	srcs, err := scanSources([]string{"testdata", "doc-id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join("testdata", "fragments", "import-test-fragment1.md"),
+		filepath.Join("testdata", "fragments", "import-test-fragment2.md"),
+		filepath.Join("testdata", "import-test.md"),
+		filepath.Join("testdata", "simple-2-steps.md"),
+		"doc-id",
+	}
+	if diff := cmp.Diff(want, srcs); diff != "" {
+		t.Errorf("scanSources() got diff (-want +got): %s", diff)
+	}
+}
+
+func TestWriteStats(t *testing.T) {
+	stats := []*CodelabStats{
+		sourceStats(context.Background(), filepath.Join("testdata", "simple-2-steps.md"), "", nil, fetch.FetcherOptions{}),
+		{Source: "broken.md", Error: "parse error"},
+	}
+	var buf bytes.Buffer
+	if err := writeStats(&buf, stats, "table"); err != nil {
+		t.Fatal(err)
+	}
+	want := "ID       STEPS  IMAGES  CODE  LINKS  INFOBOXES  VIDEOS  DURATION  ESTIMATE\n" +
+		"example  2      0       0     0      0          0       0         1\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("writeStats() got diff (-want +got): %s", diff)
+	}
+	if err := writeStats(&buf, stats, "xml"); err == nil {
+		t.Errorf("writeStats() with unknown format returned nil error")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// errSkipped is wrapped by the errors of codelabs which are deliberately
+// not exported, such as those with a status left out by OnlyStatus.
+var errSkipped = errors.New("skipped")
+
+// checkStatus returns an error wrapping errSkipped if meta has none of
+// the statuses in only. Codelabs without a status have none of them.
+// An empty only accepts all codelabs.
+func checkStatus(meta *types.Meta, only []string) error {
+	if len(only) == 0 {
+		return nil
+	}
+	for _, s := range only {
+		if meta.Status.Has(s) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: status %s is not %s", errSkipped, statusLabel(meta.Status), strings.Join(only, " or "))
+}
+
+// statusLabel returns status s for display, or "unset" if it is empty.
+func statusLabel(s *types.LegacyStatus) string {
+	if s == nil || len(*s) == 0 {
+		return "unset"
+	}
+	return strings.Join(*s, ", ")
+}
+
+// stampStatus adds the status of clab in a box at the start of its first step,
+// a warning unless clab is published.
+func stampStatus(clab *types.Codelab) {
+	if len(clab.Steps) == 0 {
+		return
+	}
+	kind := nodes.InfoboxNegative
+	if clab.Status.Has("published") {
+		kind = nodes.InfoboxPositive
+	}
+	t := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Status: " + statusLabel(clab.Status), Bold: true})
+	content := clab.Steps[0].Content
+	content.Nodes = append([]nodes.Node{nodes.NewInfoboxNode(kind, nodes.NewListNode(t))}, content.Nodes...)
+}
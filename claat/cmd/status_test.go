@@ -0,0 +1,102 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestCheckStatus(t *testing.T) {
+	tests := []struct {
+		status *types.LegacyStatus
+		only   []string
+		ok     bool
+	}{
+		{nil, nil, true},
+		{&types.LegacyStatus{"draft"}, nil, true},
+		{&types.LegacyStatus{"published"}, []string{"published"}, true},
+		{&types.LegacyStatus{"Review"}, []string{"review", "published"}, true},
+		{&types.LegacyStatus{"draft"}, []string{"published"}, false},
+		{nil, []string{"published"}, false},
+	}
+	for _, tc := range tests {
+		err := checkStatus(&types.Meta{Status: tc.status}, tc.only)
+		if tc.ok && err != nil {
+			t.Errorf("checkStatus(%v, %v) = %v; want nil", tc.status, tc.only, err)
+		}
+		if !tc.ok && !errors.Is(err, errSkipped) {
+			t.Errorf("checkStatus(%v, %v) = %v; want errSkipped", tc.status, tc.only, err)
+		}
+	}
+}
+
+func TestStampStatus(t *testing.T) {
+	tests := []struct {
+		status *types.LegacyStatus
+		kind   nodes.InfoboxKind
+		text   string
+	}{
+		{&types.LegacyStatus{"draft"}, nodes.InfoboxNegative, "Status: draft"},
+		{&types.LegacyStatus{"published"}, nodes.InfoboxPositive, "Status: published"},
+		{nil, nodes.InfoboxNegative, "Status: unset"},
+	}
+	for _, tc := range tests {
+		clab := &types.Codelab{Meta: types.Meta{Status: tc.status}}
+		clab.NewStep("Setup").Content.Append(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Open the console."}))
+		stampStatus(clab)
+		nn := clab.Steps[0].Content.Nodes
+		ib, ok := nn[0].(*nodes.InfoboxNode)
+		if len(nn) != 2 || !ok {
+			t.Fatalf("stampStatus(%v): first step nodes = %v; want an infobox and the content", tc.status, nn)
+		}
+		if ib.Kind != tc.kind || nodes.PlainText(ib) != tc.text {
+			t.Errorf("stampStatus(%v) = %s %q; want %s %q", tc.status, ib.Kind, nodes.PlainText(ib), tc.kind, tc.text)
+		}
+	}
+}
+
+func TestExportOnlyStatus(t *testing.T) {
+	dir := t.TempDir()
+	out := t.TempDir()
+	labs := map[string]string{
+		"draft.md":     "id: draft\nstatus: draft\n\n---\n\n# Draft\n\n## Step\n\nText.\n",
+		"published.md": "id: published\nstatus: published\n\n---\n\n# Published\n\n## Step\n\nText.\n",
+	}
+	opts := CmdExportOptions{Output: out, Tmplout: "md", OnlyStatus: []string{"published"}}
+	for name, content := range labs {
+		src := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(src, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		opts.Srcs = append(opts.Srcs, src)
+	}
+	if code := CmdExport(context.Background(), opts); code != 0 {
+		t.Errorf("CmdExport() = %d; want 0", code)
+	}
+	if _, err := os.Stat(filepath.Join(out, "published", "codelab.json")); err != nil {
+		t.Errorf("published codelab: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(out, "draft")); !os.IsNotExist(err) {
+		t.Errorf("draft codelab was exported: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// ReadTaxonomy reads the allowed categories and tags of codelabs,
+// and aliases of their canonical values, from a JSON file.
+func ReadTaxonomy(path string) (*types.Taxonomy, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t := &types.Taxonomy{}
+	if err := json.Unmarshal(b, t); err != nil {
+		return nil, err
+	}
+	if err := t.Normalize(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// checkTaxonomy checks the categories and tags of codelab meta m against
+// taxonomy t, if not nil, after replacing those it has a suggestion for
+// if suggest is true. Replacements are logged with the label of the codelab.
+func checkTaxonomy(label string, m *types.Meta, t *types.Taxonomy, suggest bool) error {
+	if t == nil {
+		return nil
+	}
+	if suggest {
+		for _, w := range t.Suggest(m) {
+			logWarning(label, w)
+		}
+	}
+	return t.Check(m)
+}
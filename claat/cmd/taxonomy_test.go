@@ -0,0 +1,57 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExportTaxonomy(t *testing.T) {
+	dir := t.TempDir()
+	tf := filepath.Join(dir, "taxonomy.json")
+	if err := ioutil.WriteFile(tf, []byte(`{"categories": ["Web"], "tags": ["kubernetes"], "aliases": {"k8s": "kubernetes"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tax, err := ReadTaxonomy(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(dir, "lab.md")
+	content := "id: lab\nsummary: s\ncategories: web\ntags: kuberenetes\n\n---\n\n# Lab\n\n## Setup\nDuration: 1\n\nText.\n"
+	if err := ioutil.WriteFile(src, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := CmdExportOptions{Expenv: "web", Output: t.TempDir(), Tmplout: "html", Taxonomy: tax}
+	_, err = ExportCodelab(context.Background(), src, nil, opts)
+	if err == nil || !strings.Contains(err.Error(), `did you mean "kubernetes"?`) {
+		t.Fatalf("ExportCodelab err = %v; want a suggestion of kubernetes", err)
+	}
+
+	opts.Suggest = true
+	meta, err := ExportCodelab(context.Background(), src, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"kubernetes"}, meta.Tags); diff != "" {
+		t.Errorf("tags mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/instrument"
+	"github.com/googlecodelabs/tools/claat/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Metrics of codelab exports.
+const (
+	metricExported       = "claat.codelabs.exported"
+	metricErrors         = "claat.export.errors"
+	metricRenderDuration = "claat.render.duration"
+)
+
+// exportTrace is the span of a codelab export, with a child span
+// for each of its phases.
+type exportTrace struct {
+	ctx    context.Context // of span
+	span   trace.Span
+	format attribute.KeyValue
+	phase  string     // current phase, "" before the first
+	child  trace.Span // span of phase
+	since  time.Time  // start of phase
+}
+
+// traceExport starts the span of the export of src with opts, if any, as a child
+// of the span of ctx if any, and returns the context of the span.
+// It wraps the Progress of opts to record phases. The returned func
+// ends the span with the result of the export and records its metrics.
+func traceExport(ctx context.Context, src string, opts *CmdExportOptions) (context.Context, func(*types.Meta, error)) {
+	tr := &exportTrace{format: attribute.String("claat.format", opts.Tmplout)}
+	attrs := []attribute.KeyValue{tr.format}
+	if src != "" {
+		attrs = append(attrs, attribute.String("claat.source", src))
+	}
+	tr.ctx, tr.span = otel.Tracer(instrument.ScopeName).Start(ctx, "claat.export", trace.WithAttributes(attrs...))
+	next := opts.Progress
+	opts.Progress = func(phase string) {
+		tr.startPhase(phase)
+		if next != nil {
+			next(phase)
+		}
+	}
+	return tr.ctx, tr.end
+}
+
+// startPhase ends the current phase and starts phase.
+func (tr *exportTrace) startPhase(phase string) {
+	tr.endPhase(nil)
+	tr.phase = phase
+	_, tr.child = otel.Tracer(instrument.ScopeName).Start(tr.ctx, phase)
+	tr.since = time.Now()
+}
+
+// endPhase ends the current phase, if any, failed with err if not nil.
+func (tr *exportTrace) endPhase(err error) {
+	if tr.child == nil {
+		return
+	}
+	setError(tr.child, err)
+	tr.child.End()
+	tr.child = nil
+	if tr.phase == phaseWrite {
+		h, herr := otel.Meter(instrument.ScopeName).Float64Histogram(metricRenderDuration, metric.WithUnit("s"))
+		if herr == nil {
+			h.Record(tr.ctx, time.Since(tr.since).Seconds(), metric.WithAttributes(tr.format))
+		}
+	}
+}
+
+// end ends the export, failed with err if not nil. Errors are counted
+// by type, the phase they occurred in.
+func (tr *exportTrace) end(meta *types.Meta, err error) {
+	tr.endPhase(err)
+	name, attrs := metricExported, []attribute.KeyValue{tr.format}
+	if err != nil {
+		typ := tr.phase
+		if typ == "" {
+			typ = "setup"
+		}
+		name, attrs = metricErrors, []attribute.KeyValue{attribute.String("error.type", typ), tr.format}
+	}
+	if c, cerr := otel.Meter(instrument.ScopeName).Int64Counter(name, metric.WithUnit("1")); cerr == nil {
+		c.Add(tr.ctx, 1, metric.WithAttributes(attrs...))
+	}
+	setError(tr.span, err)
+	tr.span.End()
+}
+
+// setError records err, if not nil, as the error of span.
+func setError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/instrument"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordOTel sets the global providers to record spans and metrics in
+// memory until the end of t.
+func recordOTel(t *testing.T) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter, sdkmetric.Reader) {
+	spans := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithView(instrument.DurationView))
+	otel.SetTracerProvider(tp)
+	otel.SetMeterProvider(mp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider())
+		otel.SetMeterProvider(sdkmetric.NewMeterProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+	return tp, spans, reader
+}
+
+func TestTraceExport(t *testing.T) {
+	tp, spans, reader := recordOTel(t)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	opts := CmdExportOptions{Output: t.TempDir(), Tmplout: "md"}
+	if _, err := ExportCodelab(ctx, "testdata/simple-2-steps.md", nil, opts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExportCodelab(ctx, "testdata/fragments", nil, opts); err == nil {
+		t.Fatal("ExportCodelab(testdata/fragments) = nil error; want error")
+	}
+	parent.End()
+
+	var names []string
+	for _, s := range spans.GetSpans() {
+		if s.Name == "claat.export" && s.Parent.SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s: parent = %s; want %s", s.Name, s.Parent.SpanID(), parent.SpanContext().SpanID())
+		}
+		if s.Name != "parent" {
+			names = append(names, s.Name)
+		}
+	}
+	sort.Strings(names)
+	wantSpans := []string{"assets", "claat.export", "claat.export", "fetching", "fetching", "parsing", "parsing", "transforming", "writing"}
+	if diff := cmp.Diff(wantSpans, names); diff != "" {
+		t.Errorf("spans mismatch (-want +got):\n%s", diff)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	metrics := map[string][]string{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			var sets []attribute.Set
+			switch d := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range d.DataPoints {
+					sets = append(sets, dp.Attributes)
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range d.DataPoints {
+					sets = append(sets, dp.Attributes)
+				}
+			}
+			for _, set := range sets {
+				for _, kv := range set.ToSlice() {
+					metrics[m.Name] = append(metrics[m.Name], string(kv.Key)+"="+kv.Value.Emit())
+				}
+			}
+		}
+	}
+	wantMetrics := map[string][]string{
+		metricExported:       {"claat.format=md"},
+		metricErrors:         {"claat.format=md", "error.type=parsing"},
+		metricRenderDuration: {"claat.format=md"},
+	}
+	if diff := cmp.Diff(wantMetrics, metrics); diff != "" {
+		t.Errorf("metrics mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTraceAPIParent(t *testing.T) {
+	_, spans, _ := recordOTel(t)
+	ts := httptest.NewServer(NewAPIHandler(CmdExportOptions{}))
+	defer ts.Close()
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	src := "id: lab\nsummary: s\n\n---\n\n# Lab\n\n## Setup\n\ntext\n"
+	req, err := http.NewRequest("POST", ts.URL+"/render", strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d; want %d", res.StatusCode, http.StatusOK)
+	}
+	for _, s := range spans.GetSpans() {
+		if s.Name == "claat.export" {
+			if got := s.SpanContext.TraceID().String(); got != traceID {
+				t.Errorf("trace ID = %s; want %s", got, traceID)
+			}
+			return
+		}
+	}
+	t.Error("no claat.export span")
+}
@@ -15,11 +15,11 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -27,38 +27,129 @@ import (
 	"time"
 
 	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/render"
+	"github.com/googlecodelabs/tools/claat/transform"
 	"github.com/googlecodelabs/tools/claat/types"
 	"github.com/googlecodelabs/tools/claat/util"
 )
 
 // Options type to make the CmdUpdate signature succinct.
 type CmdUpdateOptions struct {
+	// Analytics are the analytics snippets to inject into HTML output,
+	// replacing GlobalGA, if not nil.
+	Analytics *types.Analytics
 	// AuthToken is the token to use for the Drive API.
 	AuthToken string
+	// ADC authorizes with Application Default Credentials instead of the user flow.
+	ADC bool
+	// Categories only updates codelabs in one of these normalized categories,
+	// if not empty.
+	Categories []string
+	// DocsAPI fetches Google Docs with the Docs API instead of the Drive HTML export.
+	DocsAPI bool
+	// DurationTolerance is the maximum difference between the declared codelab
+	// duration and the sum of step durations not reported as a warning.
+	DurationTolerance time.Duration
+	// EmojiImages is the URL prefix of emoji images to replace emoji shortcodes
+	// of text with, instead of emoji, as in transform.EmojiOptions.
+	EmojiImages string
+	// EstimateDurations sets durations of steps without one to their estimate.
+	EstimateDurations bool
+	// Export are the options of exports of Registry codelabs,
+	// before their render profile is applied.
+	Export CmdExportOptions
 	// ExtraVars is extra template variables.
 	ExtraVars map[string]string
+	// FigureLabel is the label of figures numbered with NumberFigures,
+	// as in transform.CaptionOptions.
+	FigureLabel string
+	// Fixtures is the directory of recorded responses of Offline and Record.
+	Fixtures string
 	// GlobalGA is the global Google Analytics account to use.
 	GlobalGA string
+	// IconImages is the URL of the images of icons in Markdown output,
+	// as in render.Context.
+	IconImages string
+	// ImportCache is a directory to cache imported remote fragments in.
+	ImportCache string
+	// ImportCacheTTL is how long a cached import is reused.
+	ImportCacheTTL time.Duration
+	// KeepRuntimeVars leaves Qwiklabs {{{...}}} expressions unescaped in Markdown and HTML output.
+	KeepRuntimeVars bool
+	// MaxTestedAge fails the export if the Last Tested watermark is older.
+	MaxTestedAge time.Duration
+	// NumberFigures numbers the images captioned by the captions pass
+	// as figures.
+	NumberFigures bool
+	// Offline serves remote fetches with the responses recorded in Fixtures.
+	Offline bool
 	// PassMetadata are the extra metadata fields to pass along.
 	PassMetadata map[string]bool
+	// Passes are the names of transform passes to apply before rendering.
+	Passes []string
 	// Prefix is a URL prefix to prepend when using HTML format.
 	Prefix string
+	// Redaction, if not nil, looks for sensitive content in updated codelabs,
+	// as in CmdExportOptions.
+	Redaction *transform.Redaction
+	// Record records the responses of remote fetches in Fixtures.
+	Record bool
+	// Registry is a JSON file of the sources of codelabs to export, and their
+	// output directories, instead of the metadata of previous exports.
+	Registry string
+	// Retry is the policy of retrying failed remote fetches.
+	Retry fetch.RetryPolicy
+	// SanitizePolicy is a JSON file of HTML elements, attributes and URL
+	// schemes to allow in rendered content, as in CmdExportOptions.
+	SanitizePolicy string
+	// Snippets is a directory of shared snippets referenced as {{> name}}.
+	Snippets string
+	// Strict fails the export of codelabs with nodes which the output format
+	// cannot render, rather than warning about them.
+	Strict bool
+	// StrictMeta fails the export of codelabs with invalid metadata.
+	StrictMeta bool
+	// Suggest replaces the categories and tags of codelabs which Taxonomy
+	// does not allow with their canonical values, if it can tell them.
+	Suggest bool
+	// Tags only updates codelabs with one of these normalized tags, if not empty.
+	Tags []string
+	// Taxonomy fails the update of codelabs with categories or tags
+	// it does not allow, if not nil.
+	Taxonomy *types.Taxonomy
+	// TestedAt is the date to set in Last Tested watermarks, if not zero.
+	TestedAt time.Time
+	// UpdatedAt is the date to set in Last Updated watermarks.
+	// If zero, the source modification time is used.
+	UpdatedAt time.Time
+	// Vars are the values substituted for {{name}} references in codelab content.
+	Vars map[string]string
 }
 
 // CmdUpdate is the "claat update ..." subcommand.
 // It returns a process exit code.
-func CmdUpdate(opts CmdUpdateOptions) int {
+func CmdUpdate(ctx context.Context, opts CmdUpdateOptions) int {
+	if opts.SanitizePolicy != "" {
+		if _, err := render.ReadSanitizePolicy(opts.SanitizePolicy); err != nil {
+			logging.Fatalf("Error reading %s: %v", opts.SanitizePolicy, err)
+		}
+	}
+	if opts.Registry != "" {
+		return updateRegistry(ctx, opts)
+	}
 	roots := flag.Args()
 	if len(roots) == 0 {
 		roots = []string{"."}
 	}
 	dirs, err := scanPaths(roots)
 	if err != nil {
-		log.Fatalf("%v", err)
+		logging.Fatalf("%v", err)
 	}
 	if len(dirs) == 0 {
-		log.Fatalf("no codelabs found in %s", strings.Join(roots, ", "))
+		logging.Fatalf("no codelabs found in %s", strings.Join(roots, ", "))
 	}
+	dirs = filterDirs(dirs, opts.Categories, opts.Tags)
 
 	type result struct {
 		dir  string
@@ -70,8 +161,11 @@ func CmdUpdate(opts CmdUpdateOptions) int {
 		go func(d string) {
 			// random sleep up to 1 sec
 			// to reduce number of rate limit errors
-			time.Sleep(time.Duration(rand.Intn(1000)) * time.Millisecond)
-			meta, err := updateCodelab(d, opts)
+			select {
+			case <-time.After(time.Duration(rand.Intn(1000)) * time.Millisecond):
+			case <-ctx.Done():
+			}
+			meta, err := updateCodelab(ctx, d, opts)
 			ch <- &result{d, meta, err}
 		}(d)
 	}
@@ -81,9 +175,9 @@ func CmdUpdate(opts CmdUpdateOptions) int {
 		res := <-ch
 		if res.err != nil {
 			exitCode = 1
-			log.Printf(reportErr, res.dir, res.err)
+			logging.With("source", res.dir).Errorf("%v", res.err)
 		} else {
-			log.Printf(reportOk, res.meta.ID)
+			logging.With("source", res.dir, "id", res.meta.ID).Infof("updated")
 		}
 	}
 	return exitCode
@@ -92,61 +186,164 @@ func CmdUpdate(opts CmdUpdateOptions) int {
 // updateCodelab reads metadata from a dir/codelab.json file,
 // re-exports the codelab just like it normally would in exportCodelab,
 // and removes assets (images) which are not longer in use.
-func updateCodelab(dir string, opts CmdUpdateOptions) (*types.Meta, error) {
+func updateCodelab(ctx context.Context, dir string, opts CmdUpdateOptions) (*types.Meta, error) {
 	// get stored codelab metadata and fail early if we can't
 	meta, err := readMeta(filepath.Join(dir, metaFilename))
 	if err != nil {
 		return nil, err
 	}
-	// override allowed options from cli
-	if opts.Prefix != "" {
-		meta.Prefix = opts.Prefix
-	}
-	if opts.GlobalGA != "" {
-		meta.MainGA = opts.GlobalGA
-	}
+	eo := opts.exportOptions(&meta.Context)
 
 	// fetch and parse codelab source
-	f, err := fetch.NewFetcher(opts.AuthToken, opts.PassMetadata, nil)
+	f, err := eo.newFetcher(nil)
 	if err != nil {
 		return nil, err
 	}
 	basedir := filepath.Join(dir, "..")
-	clab, err := f.SlurpCodelab(meta.Source, basedir)
+	var orig *types.Meta
+	if len(meta.Translations) > 0 {
+		// stored under a locale dir, along with the other translations
+		basedir = filepath.Join(dir, "..", "..")
+		orig = &meta.Meta
+	}
+	clab, err := f.SlurpTranslation(ctx, meta.Source, basedir, orig, meta.LocaleOrDefault())
 	if err != nil {
 		return nil, err
 	}
-	updated := types.ContextTime(clab.Mod)
-	meta.Context.Updated = &updated
+	clab.Meta.Source = meta.Source
+	if err := prepareCodelab(ctx, f, meta.Source, clab.Codelab, clab.Mod, eo); err != nil {
+		return nil, err
+	}
 
 	newdir := codelabDir(basedir, &clab.Meta)
-	imgdir := filepath.Join(newdir, util.ImgDirname)
 
-	// write codelab and its metadata
-	if err := writeCodelab(newdir, clab.Codelab, opts.ExtraVars, &meta.Context); err != nil {
+	// write codelab and its metadata, with a new provenance
+	tc := eo.exportContext(&clab.Meta, clab.Mod, clab.Meta.LocaleDirs())
+	tc.PageURL = meta.Context.PageURL
+	if err := writeCodelab(ctx, newdir, clab.Codelab, opts.ExtraVars, tc); err != nil {
 		return nil, err
 	}
 
 	// cleanup:
 	// - remove original dir if codelab ID has changed and so has the output dir
-	// - otherwise, remove images which are not in imgs
+	// - otherwise, remove images and attachments which are not in imgs and files
 	old := codelabDir(basedir, &meta.Meta)
 	if old != newdir {
 		return &meta.Meta, os.RemoveAll(old)
 	}
+	return &meta.Meta, removeUnusedAssets(newdir, clab.Imgs, clab.Files)
+}
+
+// exportOptions returns the options to re-export a codelab previously
+// exported in context cm with: those of opts.Export, overridden by those
+// of opts and by the output options of cm, unless set in opts.
+func (opts CmdUpdateOptions) exportOptions(cm *types.Context) CmdExportOptions {
+	eo := opts.Export
+	eo.ADC = opts.ADC
+	eo.AuthToken = opts.AuthToken
+	eo.DocsAPI = opts.DocsAPI
+	eo.DurationTolerance = opts.DurationTolerance
+	eo.EmojiImages = opts.EmojiImages
+	eo.EstimateDurations = opts.EstimateDurations
+	eo.ExtraVars = opts.ExtraVars
+	eo.FigureLabel = opts.FigureLabel
+	eo.Fixtures = opts.Fixtures
+	eo.IconImages = opts.IconImages
+	eo.ImportCache = opts.ImportCache
+	eo.ImportCacheTTL = opts.ImportCacheTTL
+	eo.KeepRuntimeVars = opts.KeepRuntimeVars
+	eo.MaxTestedAge = opts.MaxTestedAge
+	eo.NumberFigures = opts.NumberFigures
+	eo.Offline = opts.Offline
+	eo.PassMetadata = opts.PassMetadata
+	eo.Passes = opts.Passes
+	eo.Record = opts.Record
+	eo.Redaction = opts.Redaction
+	eo.Retry = opts.Retry
+	eo.SanitizePolicy = opts.SanitizePolicy
+	eo.Snippets = opts.Snippets
+	eo.Strict = opts.Strict
+	eo.StrictMeta = opts.StrictMeta
+	eo.Suggest = opts.Suggest
+	eo.Taxonomy = opts.Taxonomy
+	eo.TestedAt = opts.TestedAt
+	eo.UpdatedAt = opts.UpdatedAt
+	eo.Vars = opts.Vars
+
+	// the output of the previous export
+	eo.Audience = cm.Audience
+	eo.CloudShell = cm.CloudShell
+	eo.Expenv = cm.Env
+	eo.Lightbox = cm.Lightbox
+	eo.PWA = cm.PWA
+	eo.Paper = cm.Paper
+	eo.Split = cm.Split
+	eo.TOC = cm.TOC
+	eo.Theme = cm.HTMLTheme
+	eo.Tmplout = cm.Format
+	eo.Prefix = opts.Prefix
+	if eo.Prefix == "" {
+		eo.Prefix = cm.Prefix
+	}
+	eo.Analytics, eo.GlobalGA = opts.Analytics, opts.GlobalGA
+	if eo.Analytics == nil && eo.GlobalGA == "" {
+		eo.Analytics, eo.GlobalGA = cm.Analytics, cm.MainGA
+	}
+
+	// options of exports of new codelabs
+	eo.OnlyStatus = nil
+	eo.Output = ""
+	eo.Release = ""
+	eo.Review = ""
+	eo.SiteURL = ""
+	eo.Srcs = nil
+	return eo
+}
+
+// filterDirs returns the codelab dirs with one of categories and one of tags,
+// each if not empty. Dirs with unreadable metadata are kept, to report.
+func filterDirs(dirs, categories, tags []string) []string {
+	if len(categories) == 0 && len(tags) == 0 {
+		return dirs
+	}
+	var res []string
+	for _, d := range dirs {
+		cm, err := readMeta(filepath.Join(d, metaFilename))
+		if err != nil || matchesAny(categories, cm.Categories) && matchesAny(tags, cm.Tags) {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+// removeUnusedAssets removes the images and attachments of codelab output
+// dir which are not in imgs and files, such as those of previous exports.
+func removeUnusedAssets(dir string, imgs, files map[string]string) error {
+	if err := removeUnused(filepath.Join(dir, util.ImgDirname), imgs); err != nil {
+		return err
+	}
+	return removeUnused(filepath.Join(dir, util.FilesDirname), files)
+}
+
+// removeUnused removes the files of dir which are not in keep.
+// Subdirectories are left untouched, and a missing dir is not an error.
+func removeUnused(dir string, keep map[string]string) error {
 	visit := func(p string, fi os.FileInfo, err error) error {
-		if err != nil || p == imgdir {
+		if err != nil || p == dir {
 			return err
 		}
 		if fi.IsDir() {
 			return filepath.SkipDir
 		}
-		if _, ok := clab.Imgs[filepath.Base(p)]; !ok {
+		if _, ok := keep[filepath.Base(p)]; !ok {
 			return os.Remove(p)
 		}
 		return nil
 	}
-	return &meta.Meta, filepath.Walk(imgdir, visit)
+	if err := filepath.Walk(dir, visit); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
 }
 
 // scanPaths looks for codelab metadata files in roots, recursively.
@@ -0,0 +1,66 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestUpdateCodelab(t *testing.T) {
+	out := t.TempDir()
+	if _, err := ExportCodelab(context.Background(), "testdata/simple-2-steps.md", nil, CmdExportOptions{Output: out, Tmplout: "md", Prefix: "/assets"}); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(out, "example")
+	cm, err := readMeta(filepath.Join(dir, metaFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// as if exported long ago by another version
+	cm.Provenance.Exported = types.ContextTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	cm.Provenance.Version = "old"
+	if err := writeMeta(filepath.Join(dir, metaFilename), cm); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := CmdUpdateOptions{Export: CmdExportOptions{StampStatus: true}}
+	if _, err := updateCodelab(context.Background(), dir, opts); err != nil {
+		t.Fatal(err)
+	}
+	cm, err = readMeta(filepath.Join(dir, metaFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := cm.Provenance; p.Version != Version || time.Time(p.Exported).Year() == 2020 {
+		t.Errorf("provenance = %+v; want a new one", p)
+	}
+	if cm.Format != "md" || cm.Prefix != "/assets" {
+		t.Errorf("format, prefix = %q, %q; want those of the previous export", cm.Format, cm.Prefix)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "Status: published") {
+		t.Errorf("index.md has no status stamp:\n%s", b)
+	}
+}
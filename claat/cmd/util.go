@@ -20,24 +20,33 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
 
+	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/render"
+	"github.com/googlecodelabs/tools/claat/transform"
 	"github.com/googlecodelabs/tools/claat/types"
 
 	// allow parsers to register themselves
 	_ "github.com/googlecodelabs/tools/claat/parser/gdoc"
+	_ "github.com/googlecodelabs/tools/claat/parser/html"
 	_ "github.com/googlecodelabs/tools/claat/parser/md"
+	_ "github.com/googlecodelabs/tools/claat/parser/notion"
 )
 
 const (
 	// metaFilename is codelab metadata file.
 	metaFilename = "codelab.json"
+	// resourcesFilename is the manifest of external resources of a codelab.
+	resourcesFilename = "resources.json"
 	// stdout is a special value for -o cli arg to identify stdout writer.
 	stdout = "-"
-
-	// log report formats
-	reportErr = "err\t%s %v"
-	reportOk  = "ok\t%s"
 )
 
 // isStdout reports whether filename is stdout.
@@ -48,5 +57,63 @@ func isStdout(filename string) bool {
 // codelabDir returns codelab root directory.
 // The base argument is codelab parent directory.
 func codelabDir(base string, m *types.Meta) string {
-	return filepath.Join(base, m.ID)
+	return filepath.Join(base, filepath.FromSlash(m.Dir()))
+}
+
+// transformCodelab applies content transformations to a parsed codelab
+// before it is rendered: the passes of transform.DefaultPipeline,
+// with the passes registered under names, configured by opts.
+// Warnings are logged with the label of the codelab.
+func transformCodelab(label string, clab *types.Codelab, opts transform.Options, names []string) error {
+	pl, err := transform.NewCodelabPipeline(transform.DefaultPipeline(names...)...)
+	if err != nil {
+		return err
+	}
+	opts.Warn = func(w string) { logWarning(label, w) }
+	return pl.Run(clab, &opts)
+}
+
+// estimateOptions returns step duration estimation options, with lengths
+// of videos retrieved by f with ctx if f is not nil and a YouTube API key is set.
+func estimateOptions(ctx context.Context, f *fetch.Fetcher) transform.EstimateOptions {
+	var eo transform.EstimateOptions
+	if f != nil && fetch.HasYouTubeKey() {
+		eo.VideoLength = func(id string) (time.Duration, error) {
+			return f.VideoLength(ctx, id)
+		}
+	}
+	return eo
+}
+
+// stepWarning matches the step number and source position of warnings
+// about a step, formatted as types.Step.Location: step <n> "<title>": <warning>,
+// or step <n> "<title>", line <l>: <warning> with a position.
+var stepWarning = regexp.MustCompile(`^step (\d+) "(?:[^"\\]|\\.)*"(?:, (line|paragraph) (\d+))?`)
+
+// logWarning logs warning w about codelab src, with the step
+// it is about and its source position, if any, as fields.
+func logWarning(src, w string) {
+	l := logging.With("source", src)
+	if m := stepWarning.FindStringSubmatch(w); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		l = l.With("step", n)
+		if m[2] != "" {
+			p, _ := strconv.Atoi(m[3])
+			l = l.With(m[2], p)
+		}
+	}
+	l.Warnf("%s", w)
+}
+
+// checkUnsupported logs warnings about the nodes of steps of codelab src
+// which cannot be rendered in format. If strict, it fails if there are any.
+func checkUnsupported(src, format string, steps []*types.Step, strict bool) error {
+	ww := render.Unsupported(format, steps)
+	for _, w := range ww {
+		logWarning(src, w)
+	}
+	if strict && len(ww) > 0 {
+		return fmt.Errorf("%d unsupported node(s) in %s output, see warnings", len(ww), format)
+	}
+	return nil
 }
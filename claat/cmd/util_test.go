@@ -0,0 +1,79 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestLogWarning(t *testing.T) {
+	var b bytes.Buffer
+	l, err := logging.New(&b, logging.LevelInfo, logging.FormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logging.SetDefault(l)
+	defer func() {
+		l, _ := logging.New(os.Stderr, logging.LevelInfo, logging.FormatText)
+		logging.SetDefault(l)
+	}()
+
+	logWarning("lab.md", `step 12 "Deploy" has no duration`)
+	logWarning("lab.md", `step 3 "Run \"it\"", line 42: md output cannot embed iframe https://codepen.io/foo, written as a link`)
+	logWarning("lab.gdoc", `step 2 "Setup", paragraph 7: quiz q1: question "Pick" has no correct answer, want at least 1`)
+	logWarning("lab.md", "declared duration of 20 min differs from the 30 min sum of step durations")
+	want := "warn\tstep 12 \"Deploy\" has no duration source=lab.md step=12\n" +
+		"warn\tstep 3 \"Run \\\"it\\\"\", line 42: md output cannot embed iframe https://codepen.io/foo, written as a link source=lab.md step=3 line=42\n" +
+		"warn\tstep 2 \"Setup\", paragraph 7: quiz q1: question \"Pick\" has no correct answer, want at least 1 source=lab.gdoc step=2 paragraph=7\n" +
+		"warn\tdeclared duration of 20 min differs from the 30 min sum of step durations source=lab.md\n"
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("log mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCheckUnsupported(t *testing.T) {
+	var b bytes.Buffer
+	l, err := logging.New(&b, logging.LevelInfo, logging.FormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logging.SetDefault(l)
+	defer func() {
+		l, _ := logging.New(os.Stderr, logging.LevelInfo, logging.FormatText)
+		logging.SetDefault(l)
+	}()
+
+	steps := []*types.Step{{Title: "Demo", Content: nodes.NewListNode(nodes.NewIframeNode("https://codepen.io/foo"))}}
+	if err := checkUnsupported("lab.md", "html", steps, true); err != nil {
+		t.Errorf("checkUnsupported(html) = %v; want nil", err)
+	}
+	if err := checkUnsupported("lab.md", "offline", steps, false); err != nil {
+		t.Errorf("checkUnsupported(offline) = %v; want nil", err)
+	}
+	if err := checkUnsupported("lab.md", "offline", steps, true); err == nil {
+		t.Error("checkUnsupported(offline, strict) = nil; want error")
+	}
+	want := "warn\tstep 1 \"Demo\": offline output has no renderer for iframe nodes, dropped source=lab.md step=1\n"
+	if diff := cmp.Diff(want+want, b.String()); diff != "" {
+		t.Errorf("log mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+	"github.com/googlecodelabs/tools/claat/util"
+)
+
+// snippetEnv is the environment variable holding the path of the file
+// of the code block a verifier command is run against.
+const snippetEnv = "SNIPPET"
+
+// Options type to make the CmdVerify signature succinct.
+type CmdVerifyOptions struct {
+	// AuthToken is the token to use for the Drive API.
+	AuthToken string
+	// ADC authorizes with Application Default Credentials instead of the user flow.
+	ADC bool
+	// DocsAPI fetches Google Docs with the Docs API instead of the Drive HTML export.
+	DocsAPI bool
+	// PassMetadata are the extra metadata fields to pass along.
+	PassMetadata map[string]bool
+	// Srcs is the sources to verify. Local directories are scanned
+	// for Markdown sources, recursively.
+	Srcs []string
+	// Verifiers maps code languages to the shell command verifying
+	// runnable code blocks of the language, such as "bash -n $SNIPPET".
+	Verifiers map[string]string
+}
+
+// CmdVerify is the "claat verify ..." subcommand.
+// It runs the verifier command of the language of every code block
+// tagged as runnable, and reports failures per step.
+// It returns a process exit code.
+func CmdVerify(ctx context.Context, opts CmdVerifyOptions) int {
+	if len(opts.Srcs) == 0 {
+		logging.Fatalf("Need at least one source. Try '-h' for options.")
+	}
+	if len(opts.Verifiers) == 0 {
+		logging.Fatalf("Need verifier commands. Try '-h' for options.")
+	}
+	srcs, err := scanSources(util.Unique(opts.Srcs))
+	if err != nil {
+		logging.Fatalf("%v", err)
+	}
+	fo := fetch.FetcherOptions{ADC: opts.ADC, DocsAPI: opts.DocsAPI}
+	var exitCode int
+	for _, src := range srcs {
+		f, err := fetch.NewFetcher(opts.AuthToken, opts.PassMetadata, nil, fo)
+		if err != nil {
+			logging.With("source", src).Errorf("%v", err)
+			exitCode = 1
+			continue
+		}
+		clab, err := f.SlurpCodelab(ctx, src, stdout)
+		if err != nil {
+			logging.With("source", src).Errorf("%v", err)
+			exitCode = 1
+			continue
+		}
+		failures, unverified, err := verifyCodelab(clab.Codelab, opts.Verifiers)
+		if err != nil {
+			logging.With("source", src).Errorf("%v", err)
+			exitCode = 1
+			continue
+		}
+		for _, lang := range unverified {
+			logWarning(src, fmt.Sprintf("no verifier for runnable %s code", lang))
+		}
+		for _, f := range failures {
+			logging.With("source", src).Errorf("%v", f)
+		}
+		if len(failures) > 0 {
+			exitCode = 1
+			continue
+		}
+		logging.With("source", src, "id", clab.ID).Infof("verified")
+	}
+	return exitCode
+}
+
+// ReadVerifiers reads verifier commands from a JSON file
+// containing an object of language:command pairs.
+func ReadVerifiers(path string) (map[string]string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	verifiers := map[string]string{}
+	if err := json.Unmarshal(b, &verifiers); err != nil {
+		return nil, err
+	}
+	return verifiers, nil
+}
+
+// verifyCodelab runs the verifier of every runnable code block of clab.
+// It returns the failures, and the sorted languages of runnable code
+// without a verifier. Terminal code blocks are of language "console".
+// Expected output is never run.
+func verifyCodelab(clab *types.Codelab, verifiers map[string]string) ([]string, []string, error) {
+	var failures []string
+	unverified := make(map[string]bool)
+	for i, st := range clab.Steps {
+		var num int
+		_, err := nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			cn, ok := n.(*nodes.CodeNode)
+			if !ok || !entering || !cn.Runnable || cn.Output {
+				return n, nil
+			}
+			num++
+			lang := cn.Lang
+			if cn.Term {
+				lang = "console"
+			}
+			command, ok := verifiers[lang]
+			if !ok {
+				unverified[lang] = true
+				return n, nil
+			}
+			if err := runVerifier(command, lang, cn.Value); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: runnable code block %d: %v", st.Location(i+1, cn), num, err))
+			}
+			return n, nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	var langs []string
+	for lang := range unverified {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return failures, langs, nil
+}
+
+// runVerifier runs shell command in a new temporary directory, holding
+// code in a file named after lang, with the file path in $SNIPPET.
+// The returned error includes the command output.
+func runVerifier(command, lang, code string) error {
+	dir, err := ioutil.TempDir("", "claat-verify")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	name := "snippet"
+	if lang != "" {
+		name += "." + lang
+	}
+	file := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(file, []byte(strings.TrimLeft(code, "\n")), 0644); err != nil {
+		return err
+	}
+	c := exec.Command("sh", "-c", command)
+	c.Dir = dir
+	c.Env = append(os.Environ(), snippetEnv+"="+file)
+	out, err := c.CombinedOutput()
+	if err != nil {
+		if out := strings.TrimSpace(string(out)); out != "" {
+			return fmt.Errorf("%s: %v\n%s", command, err, out)
+		}
+		return fmt.Errorf("%s: %v", command, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/cmd"
+)
+
+const verifySource = "id: %s\nsummary: Verify\n\n---\n\n# Verify\n\n## Run\nDuration: 1\n\n" +
+	"```sh runnable\n%s\n```\n\n```go runnable\nfunc main() {}\n```\n\n```sh\nif then\n```\n"
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	write := func(id, code string) string {
+		src := filepath.Join(dir, id+".md")
+		if err := ioutil.WriteFile(src, []byte(fmt.Sprintf(verifySource, id, code)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return src
+	}
+	valid := write("valid", "echo ok")
+	invalid := write("invalid", "if then")
+	verifiers := map[string]string{"sh": `sh -n "$SNIPPET"`}
+	tests := []struct {
+		srcs []string
+		code int
+	}{
+		{srcs: []string{valid}, code: 0},
+		{srcs: []string{valid, invalid}, code: 1},
+	}
+	for _, tc := range tests {
+		code := cmd.CmdVerify(context.Background(), cmd.CmdVerifyOptions{Srcs: tc.srcs, Verifiers: verifiers})
+		if code != tc.code {
+			t.Errorf("CmdVerify(%v) exit code = %d; want %d", tc.srcs, code, tc.code)
+		}
+	}
+}
+
+func TestReadVerifiers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "verifiers.json")
+	if err := ioutil.WriteFile(path, []byte(`{"bash": "bash -n $SNIPPET", "go": "gofmt -e $SNIPPET"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := cmd.ReadVerifiers(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"bash": "bash -n $SNIPPET", "go": "gofmt -e $SNIPPET"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ReadVerifiers got diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/googlecodelabs/tools/claat/logging"
+)
+
+// Options type to make the CmdWebhook signature succinct.
+type CmdWebhookOptions struct {
+	// Addr is the hostname and port to listen for webhooks on.
+	Addr string
+	// Output is the directory of the codelabs to re-export, recursively.
+	Output string
+	// Token is the shared secret authenticating webhooks.
+	Token string
+	// Update are the options to re-export codelabs with.
+	Update CmdUpdateOptions
+}
+
+// CmdWebhook is the "claat webhook ..." subcommand.
+// It serves until ctx is done, and returns a process exit code.
+func CmdWebhook(ctx context.Context, opts CmdWebhookOptions) int {
+	if opts.Token == "" {
+		logging.Fatalf("Need -webhook_token. Try '-h' for options.")
+	}
+	h := newWebhookHandler(opts.Output, opts.Token, func(dir string) error {
+		_, err := updateCodelab(ctx, dir, opts.Update)
+		return err
+	})
+	logging.Infof("Listening for webhooks on %s", opts.Addr)
+	if err := listenAndServe(ctx, opts.Addr, h); err != nil {
+		logging.Fatalf("claat webhook: %v", err)
+	}
+	// re-exports are cancelled along with ctx
+	h.wg.Wait()
+	return 0
+}
+
+// webhookHandler re-exports codelabs of a directory when notified
+// that their source has changed.
+type webhookHandler struct {
+	root   string                 // directory of exported codelabs
+	token  string                 // shared secret
+	update func(dir string) error // re-exports the codelab of dir
+	mux    *http.ServeMux
+
+	mu      sync.Mutex
+	running map[string]bool // sources being re-exported
+	pending map[string]bool // sources changed again while re-exported
+	wg      sync.WaitGroup  // running re-exports
+}
+
+func newWebhookHandler(root, token string, update func(dir string) error) *webhookHandler {
+	h := &webhookHandler{
+		root:    root,
+		token:   token,
+		update:  update,
+		mux:     http.NewServeMux(),
+		running: make(map[string]bool),
+		pending: make(map[string]bool),
+	}
+	h.mux.HandleFunc("/drive", h.drive)
+	h.mux.HandleFunc("/export", h.export)
+	return h
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// drive handles Drive push notifications of the files.watch channels
+// of codelab docs, created with the shared secret as channel token.
+// It always succeeds once authenticated, so that Drive does not retry.
+func (h *webhookHandler) drive(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r.Header.Get("X-Goog-Channel-Token")) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	switch r.Header.Get("X-Goog-Resource-State") {
+	case "update", "change":
+		if id := driveFileID(r.Header.Get("X-Goog-Resource-URI")); id != "" {
+			h.schedule(id)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// export handles generic webhooks, POST /export?src=<source> with
+// the shared secret as bearer token.
+func (h *webhookHandler) export(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	src := r.URL.Query().Get("src")
+	if src == "" {
+		http.Error(w, "missing src", http.StatusBadRequest)
+		return
+	}
+	if !h.schedule(src) {
+		http.Error(w, "no codelab exported from "+src, http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// authorized reports whether token is the shared secret.
+func (h *webhookHandler) authorized(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1
+}
+
+// schedule re-exports the codelab of src in the background, unless
+// there is none. If it is being re-exported, it is re-exported again
+// once done, so that the latest changes are always exported.
+// It reports whether there is a codelab exported from src.
+func (h *webhookHandler) schedule(src string) bool {
+	if h.find(src) == "" {
+		return false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.running[src] {
+		h.pending[src] = true
+		return true
+	}
+	h.running[src] = true
+	h.wg.Add(1)
+	go h.run(src)
+	return true
+}
+
+// run re-exports the codelab of src until it has no pending changes.
+func (h *webhookHandler) run(src string) {
+	defer h.wg.Done()
+	for {
+		// the directory changes along with the codelab ID
+		if dir := h.find(src); dir != "" {
+			if err := h.update(dir); err != nil {
+				logging.With("source", src).Errorf("%v", err)
+			} else {
+				logging.With("source", src, "dir", dir).Infof("updated")
+			}
+		}
+		h.mu.Lock()
+		if !h.pending[src] {
+			delete(h.running, src)
+			h.mu.Unlock()
+			return
+		}
+		delete(h.pending, src)
+		h.mu.Unlock()
+	}
+}
+
+// find returns the directory of the codelab exported from src,
+// or "" if there is none.
+func (h *webhookHandler) find(src string) string {
+	dirs, err := walkPath(h.root)
+	if err != nil {
+		logging.Errorf("%v", err)
+		return ""
+	}
+	for _, d := range dirs {
+		cm, err := readMeta(filepath.Join(d, metaFilename))
+		if err == nil && cm.Source == src {
+			return d
+		}
+	}
+	return ""
+}
+
+// driveFileID returns the file ID of the X-Goog-Resource-URI of a Drive
+// push notification, such as https://www.googleapis.com/drive/v3/files/ID?alt=json.
+func driveFileID(resource string) string {
+	u, err := url.Parse(resource)
+	if err != nil {
+		return ""
+	}
+	if dir, id := path.Split(u.Path); path.Base(dir) == "files" {
+		return id
+	}
+	return ""
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	dir := t.TempDir()
+	for id, src := range map[string]string{"drive-lab": "1aBcDeFgHiJkLmNoPqRsTuVwXyZ", "md-lab": "labs/md-lab.md"} {
+		d := filepath.Join(dir, id)
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(&types.ContextMeta{Meta: types.Meta{ID: id, Source: src}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(d, metaFilename), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mu sync.Mutex
+	var updated []string
+	h := newWebhookHandler(dir, "secret", func(d string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		updated = append(updated, filepath.Base(d))
+		return nil
+	})
+
+	tests := []struct {
+		name   string
+		path   string
+		header map[string]string
+		code   int
+	}{
+		{
+			name:   "drive",
+			path:   "/drive",
+			header: map[string]string{"X-Goog-Channel-Token": "secret", "X-Goog-Resource-State": "update", "X-Goog-Resource-URI": "https://www.googleapis.com/drive/v3/files/1aBcDeFgHiJkLmNoPqRsTuVwXyZ?alt=json"},
+			code:   http.StatusOK,
+		},
+		{
+			name:   "drive sync",
+			path:   "/drive",
+			header: map[string]string{"X-Goog-Channel-Token": "secret", "X-Goog-Resource-State": "sync", "X-Goog-Resource-URI": "https://www.googleapis.com/drive/v3/files/1aBcDeFgHiJkLmNoPqRsTuVwXyZ?alt=json"},
+			code:   http.StatusOK,
+		},
+		{
+			name:   "drive unknown file",
+			path:   "/drive",
+			header: map[string]string{"X-Goog-Channel-Token": "secret", "X-Goog-Resource-State": "update", "X-Goog-Resource-URI": "https://www.googleapis.com/drive/v3/files/other?alt=json"},
+			code:   http.StatusOK,
+		},
+		{
+			name:   "drive bad token",
+			path:   "/drive",
+			header: map[string]string{"X-Goog-Channel-Token": "guess", "X-Goog-Resource-State": "update"},
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "export",
+			path:   "/export?src=labs/md-lab.md",
+			header: map[string]string{"Authorization": "Bearer secret"},
+			code:   http.StatusAccepted,
+		},
+		{
+			name:   "export unknown source",
+			path:   "/export?src=labs/other.md",
+			header: map[string]string{"Authorization": "Bearer secret"},
+			code:   http.StatusNotFound,
+		},
+		{
+			name: "export no token",
+			path: "/export?src=labs/md-lab.md",
+			code: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, tc.path, nil)
+			for k, v := range tc.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tc.code {
+				t.Errorf("code = %d; want %d", w.Code, tc.code)
+			}
+		})
+	}
+	h.wg.Wait()
+
+	sort.Strings(updated)
+	if diff := cmp.Diff([]string{"drive-lab", "md-lab"}, updated); diff != "" {
+		t.Errorf("updated mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWebhookPending(t *testing.T) {
+	dir := t.TempDir()
+	d := filepath.Join(dir, "lab")
+	if err := os.MkdirAll(d, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(d, metaFilename), []byte(`{"source":"lab.md"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	n := 0
+	h := newWebhookHandler(dir, "secret", func(string) error {
+		n++
+		if n == 1 {
+			close(started)
+			<-release
+		}
+		return nil
+	})
+	h.schedule("lab.md")
+	<-started
+	// changes while being re-exported are coalesced into one more run
+	for i := 0; i < 3; i++ {
+		h.schedule("lab.md")
+	}
+	close(release)
+	h.wg.Wait()
+	if n != 2 {
+		t.Errorf("updates = %d; want 2", n)
+	}
+}
+
+func TestDriveFileID(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"https://www.googleapis.com/drive/v3/files/1aBc?alt=json", "1aBc"},
+		{"https://www.googleapis.com/drive/v2/files/1aBc", "1aBc"},
+		{"https://www.googleapis.com/drive/v3/changes?pageToken=1", ""},
+		{"", ""},
+	}
+	for _, tc := range tests {
+		if got := driveFileID(tc.in); got != tc.out {
+			t.Errorf("driveFileID(%q) = %q; want %q", tc.in, got, tc.out)
+		}
+	}
+}
@@ -0,0 +1,403 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/fetch/drive/auth"
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+const (
+	// DefaultWorkerAttempts is how many times a job is tried by default.
+	DefaultWorkerAttempts = 3
+	// workerBackoff is the delay before the second try of a job,
+	// doubled for each further try.
+	workerBackoff = 2 * time.Second
+	// workerAckDeadline is the ack deadline of the job being handled,
+	// extended every half of it until the job is done.
+	workerAckDeadline = time.Minute
+	// workerRetryDelay is the delay before a failed job is redelivered,
+	// doubled for each further delivery attempt up to maxRetryDelay.
+	workerRetryDelay = time.Minute
+	// maxRetryDelay is the maximum ack deadline of Pub/Sub.
+	maxRetryDelay = 10 * time.Minute
+	// pubsubURL is the Pub/Sub REST API endpoint.
+	pubsubURL = "https://pubsub.googleapis.com/v1/"
+)
+
+// Options type to make the CmdWorker signature succinct.
+type CmdWorkerOptions struct {
+	// Attempts is how many times a job is tried before it fails.
+	Attempts int
+	// DeadLetter is the Pub/Sub topic failed jobs are published to,
+	// as projects/<project>/topics/<topic>. If empty, failed jobs are
+	// redelivered after a delay instead, e.g. to the dead-letter topic
+	// of the subscription.
+	DeadLetter string
+	// Export are the options to export the codelabs with. Jobs override
+	// the output format and output to subdirectories of the output directory.
+	Export CmdExportOptions
+	// Subscription is the Pub/Sub subscription to pull jobs from,
+	// as projects/<project>/subscriptions/<subscription>.
+	Subscription string
+	// Topic is the Pub/Sub topic completion events are published to,
+	// as projects/<project>/topics/<topic>; none are published if empty.
+	Topic string
+}
+
+// exportJob is the JSON message of an export job.
+type exportJob struct {
+	Source string `json:"source"`
+	Format string `json:"format,omitempty"`
+	// Output is a directory relative to the output directory of the worker,
+	// or a gs://<bucket>/<prefix> Cloud Storage location.
+	Output string `json:"output,omitempty"`
+}
+
+// exportEvent is the JSON message of the completion of an export job.
+type exportEvent struct {
+	Job       *exportJob `json:"job"`
+	MessageID string     `json:"messageId"`
+	Status    string     `json:"status"`       // done or failed
+	ID        string     `json:"id,omitempty"` // exported codelab ID
+	Error     string     `json:"error,omitempty"`
+	Attempts  int        `json:"attempts"`
+}
+
+// CmdWorker is the "claat worker" subcommand.
+// It pulls jobs until ctx is done,
+// and returns a process exit code.
+func CmdWorker(ctx context.Context, opts CmdWorkerOptions) int {
+	if opts.Subscription == "" {
+		logging.Fatalf("Need -subscription. Try '-h' for options.")
+	}
+	client, err := auth.NewADCClient(nil, auth.ScopePubSub, auth.ScopeStorageReadWrite)
+	if err != nil {
+		logging.Errorf("%v", err)
+		return 1
+	}
+	wk := &worker{
+		ps:   &pubsubClient{client: client, baseURL: pubsubURL},
+		up:   gcsUploader{client: client, uploadURL: gcsUploadURL},
+		opts: opts,
+		sleep: func(d time.Duration) {
+			select {
+			case <-time.After(d):
+			case <-ctx.Done():
+			}
+		},
+	}
+	logging.Infof("Pulling export jobs from %s", opts.Subscription)
+	wk.extendEvery = workerAckDeadline / 2
+	wk.run(ctx)
+	return 0
+}
+
+// worker exports the codelabs of jobs pulled from a Pub/Sub subscription.
+type worker struct {
+	ps    *pubsubClient
+	up    gcsUploader // uploader of gs:// outputs, without bucket
+	opts  CmdWorkerOptions
+	sleep func(time.Duration)
+	// extendEvery is how often the ack deadline of the job being handled
+	// is extended to workerAckDeadline.
+	extendEvery time.Duration
+}
+
+// run handles jobs one at a time, until ctx is done.
+func (wk *worker) run(ctx context.Context) {
+	backoff := workerBackoff
+	for ctx.Err() == nil {
+		msgs, err := wk.ps.pull(ctx, wk.opts.Subscription)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			logging.Errorf("%v", err)
+			wk.sleep(backoff)
+			if backoff < time.Minute {
+				backoff *= 2
+			}
+			continue
+		}
+		backoff = workerBackoff
+		for _, m := range msgs {
+			wk.handle(ctx, m)
+		}
+	}
+}
+
+// handle tries the job of m up to opts.Attempts times, with exponential backoff,
+// extending the ack deadline of m meanwhile.
+// Failed jobs are published to the dead-letter topic, if any.
+// m is acked unless it failed without dead-letter topic, and is then
+// redelivered after retryDelay, or was interrupted by ctx being done,
+// and is then redelivered right away.
+// A completion event is published to the topic, if any, once m is acked.
+func (wk *worker) handle(ctx context.Context, m *pubsubReceived) {
+	stop := wk.keepAlive(m)
+	job := &exportJob{}
+	err := json.Unmarshal(m.Message.Data, job)
+	if err == nil && job.Source == "" {
+		err = errors.New("missing source")
+	}
+	ev := &exportEvent{Job: job, MessageID: m.Message.ID}
+	// malformed jobs fail without retry
+	for err == nil {
+		ev.Attempts++
+		var meta *types.Meta
+		if meta, err = wk.export(ctx, job); err == nil {
+			ev.ID = meta.ID
+			break
+		}
+		logging.With("source", job.Source).Errorf("%v", err)
+		if ev.Attempts >= wk.opts.Attempts || ctx.Err() != nil {
+			break
+		}
+		wk.sleep(workerBackoff << uint(ev.Attempts-1))
+		err = nil
+	}
+	stop()
+
+	if err != nil && ctx.Err() != nil {
+		// leave the job to another worker
+		wk.redeliver(m, 0)
+		return
+	}
+	if err == nil {
+		ev.Status = "done"
+		logging.With("source", job.Source, "id", ev.ID, "output", job.Output).Infof("exported")
+	} else {
+		ev.Status = "failed"
+		ev.Error = err.Error()
+		if wk.opts.DeadLetter == "" {
+			wk.redeliver(m, retryDelay(m))
+			return
+		}
+		dl := &pubsubMessage{Data: m.Message.Data, Attributes: map[string]string{"error": ev.Error, "messageId": m.Message.ID}}
+		if err := wk.ps.publish(wk.opts.DeadLetter, dl); err != nil {
+			logging.Errorf("%v", err)
+			wk.redeliver(m, retryDelay(m))
+			return
+		}
+	}
+	if err := wk.ps.ack(wk.opts.Subscription, m.AckID); err != nil {
+		logging.Errorf("%v", err)
+		return
+	}
+	if wk.opts.Topic == "" {
+		return
+	}
+	b, err := json.Marshal(ev)
+	if err != nil {
+		logging.Errorf("%v", err)
+		return
+	}
+	msg := &pubsubMessage{Data: b, Attributes: map[string]string{"status": ev.Status}}
+	if err := wk.ps.publish(wk.opts.Topic, msg); err != nil {
+		logging.Errorf("%v", err)
+	}
+}
+
+// keepAlive extends the ack deadline of m to workerAckDeadline right away,
+// and then every wk.extendEvery, until the returned func is called.
+func (wk *worker) keepAlive(m *pubsubReceived) (stop func()) {
+	extend := func() {
+		if err := wk.ps.modifyAckDeadline(wk.opts.Subscription, m.AckID, workerAckDeadline); err != nil {
+			logging.Errorf("%v", err)
+		}
+	}
+	extend()
+	done, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		t := time.NewTicker(wk.extendEvery)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				extend()
+			case <-done:
+				return
+			}
+		}
+	}()
+	// no extension may follow the ack or redelivery of m
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// redeliver makes m available for redelivery after delay.
+func (wk *worker) redeliver(m *pubsubReceived, delay time.Duration) {
+	if err := wk.ps.modifyAckDeadline(wk.opts.Subscription, m.AckID, delay); err != nil {
+		logging.Errorf("%v", err)
+	}
+}
+
+// retryDelay returns the delay before failed message m is redelivered,
+// workerRetryDelay doubled for each previous delivery attempt, if counted
+// by the subscription, up to maxRetryDelay. It keeps failing jobs from
+// being retried in a hot loop.
+func retryDelay(m *pubsubReceived) time.Duration {
+	d := workerRetryDelay
+	for i := 1; i < m.DeliveryAttempt && d < maxRetryDelay; i++ {
+		d *= 2
+	}
+	if d > maxRetryDelay {
+		d = maxRetryDelay
+	}
+	return d
+}
+
+// export exports the codelab of job to its output.
+func (wk *worker) export(ctx context.Context, job *exportJob) (*types.Meta, error) {
+	eo := wk.opts.Export
+	if job.Format != "" {
+		eo.Tmplout = job.Format
+	}
+	if !strings.HasPrefix(job.Output, "gs://") {
+		dir, err := jobOutput(eo.Output, job.Output)
+		if err != nil {
+			return nil, err
+		}
+		eo.Output = dir
+		return ExportCodelab(ctx, job.Source, nil, eo)
+	}
+
+	loc := strings.SplitN(strings.TrimPrefix(job.Output, "gs://"), "/", 2)
+	if loc[0] == "" {
+		return nil, fmt.Errorf("output %q has no bucket", job.Output)
+	}
+	tmp, err := ioutil.TempDir("", "claat-worker")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	eo.Output = tmp
+	meta, err := ExportCodelab(ctx, job.Source, nil, eo)
+	if err != nil {
+		return nil, err
+	}
+	up := wk.up
+	up.bucket = loc[0]
+	var prefix string
+	if len(loc) == 2 {
+		prefix = strings.Trim(loc[1], "/")
+	}
+	return meta, up.uploadDir(tmp, prefix, time.Time{})
+}
+
+// jobOutput returns the directory of job output out under root.
+// Jobs may not write outside of root.
+func jobOutput(root, out string) (string, error) {
+	p := filepath.Clean(filepath.FromSlash(out))
+	if filepath.IsAbs(p) || p == ".." || strings.HasPrefix(p, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("output %q is outside of the output directory", out)
+	}
+	return filepath.Join(root, p), nil
+}
+
+// pubsubClient is a client of the Pub/Sub REST API.
+type pubsubClient struct {
+	client  *http.Client
+	baseURL string // API endpoint, with a trailing slash
+}
+
+// pubsubMessage is a Pub/Sub message.
+type pubsubMessage struct {
+	Data       []byte            `json:"data"` // base64 in JSON
+	Attributes map[string]string `json:"attributes,omitempty"`
+	ID         string            `json:"messageId,omitempty"`
+}
+
+// pubsubReceived is a message pulled from a subscription.
+type pubsubReceived struct {
+	AckID   string        `json:"ackId"`
+	Message pubsubMessage `json:"message"`
+	// DeliveryAttempt counts the deliveries of the message, starting at 1,
+	// if the subscription has a dead-letter policy, or is 0.
+	DeliveryAttempt int `json:"deliveryAttempt,omitempty"`
+}
+
+// pull pulls at most one message of subscription sub, until ctx is done.
+// It may return no message.
+func (ps *pubsubClient) pull(ctx context.Context, sub string) ([]*pubsubReceived, error) {
+	var res struct {
+		ReceivedMessages []*pubsubReceived `json:"receivedMessages"`
+	}
+	err := ps.call(ctx, sub, "pull", map[string]interface{}{"maxMessages": 1}, &res)
+	return res.ReceivedMessages, err
+}
+
+// ack acknowledges the message of ackID of subscription sub.
+func (ps *pubsubClient) ack(sub, ackID string) error {
+	return ps.call(context.Background(), sub, "acknowledge", map[string]interface{}{"ackIds": []string{ackID}}, nil)
+}
+
+// modifyAckDeadline sets the ack deadline of the message of ackID of
+// subscription sub to d from now, in seconds. Zero makes it available
+// for redelivery right away.
+func (ps *pubsubClient) modifyAckDeadline(sub, ackID string, d time.Duration) error {
+	req := map[string]interface{}{"ackIds": []string{ackID}, "ackDeadlineSeconds": int(d / time.Second)}
+	return ps.call(context.Background(), sub, "modifyAckDeadline", req, nil)
+}
+
+// publish publishes m to topic.
+func (ps *pubsubClient) publish(topic string, m *pubsubMessage) error {
+	return ps.call(context.Background(), topic, "publish", map[string]interface{}{"messages": []*pubsubMessage{m}}, nil)
+}
+
+// call calls method of resource name with JSON request req, until ctx is done,
+// decoding the JSON response into res unless nil.
+func (ps *pubsubClient) call(ctx context.Context, name, method string, req, res interface{}) error {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, ps.baseURL+name+":"+method, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	hreq.Header.Set("Content-Type", "application/json")
+	r, err := ps.client.Do(hreq)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(r.Body, 1<<10))
+		return fmt.Errorf("%s %s: %s: %s", method, name, r.Status, bytes.TrimSpace(msg))
+	}
+	if res == nil {
+		return nil
+	}
+	return json.NewDecoder(r.Body).Decode(res)
+}
@@ -0,0 +1,286 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakePubSub records the Pub/Sub calls and Cloud Storage uploads it serves.
+type fakePubSub struct {
+	mu        sync.Mutex
+	calls     []string                    // method of each call
+	deadlines []int                       // ackDeadlineSeconds of each modifyAckDeadline call
+	published map[string][]*pubsubMessage // by topic
+	objects   []string                    // uploaded object names
+}
+
+func (f *fakePubSub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.URL.Path == "/upload/bucket/o" {
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		part, err := multipart.NewReader(r.Body, params["boundary"]).NextPart()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var obj gcsObject
+		if err := json.NewDecoder(part).Decode(&obj); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.objects = append(f.objects, obj.Name)
+		return
+	}
+	i := strings.LastIndex(r.URL.Path, ":")
+	name, method := strings.TrimPrefix(r.URL.Path[:i], "/"), r.URL.Path[i+1:]
+	f.calls = append(f.calls, method)
+	if method == "modifyAckDeadline" {
+		var req struct {
+			AckDeadlineSeconds int `json:"ackDeadlineSeconds"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.deadlines = append(f.deadlines, req.AckDeadlineSeconds)
+	}
+	if method != "publish" {
+		w.Write([]byte("{}"))
+		return
+	}
+	var req struct {
+		Messages []*pubsubMessage `json:"messages"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.published[name] = append(f.published[name], req.Messages...)
+	w.Write([]byte(`{"messageIds":["1"]}`))
+}
+
+func TestWorkerHandle(t *testing.T) {
+	tests := []struct {
+		name        string
+		job         string
+		deadLetter  string
+		attempt     int // delivery attempt of the job
+		calls       []string
+		deadlines   []int
+		event       *exportEvent
+		deadLetters int
+		file        string // expected output file
+		objects     bool   // whether objects are uploaded
+	}{
+		{
+			name:      "local",
+			job:       `{"source":"testdata/simple-2-steps.md","output":"labs"}`,
+			calls:     []string{"modifyAckDeadline", "acknowledge", "publish"},
+			deadlines: []int{60},
+			event:     &exportEvent{Job: &exportJob{Source: "testdata/simple-2-steps.md", Output: "labs"}, MessageID: "m1", Status: "done", ID: "example", Attempts: 1},
+			file:      "labs/example/index.html",
+		},
+		{
+			name:      "gcs",
+			job:       `{"source":"testdata/simple-2-steps.md","format":"md","output":"gs://bucket/labs"}`,
+			calls:     []string{"modifyAckDeadline", "acknowledge", "publish"},
+			deadlines: []int{60},
+			event:     &exportEvent{Job: &exportJob{Source: "testdata/simple-2-steps.md", Format: "md", Output: "gs://bucket/labs"}, MessageID: "m1", Status: "done", ID: "example", Attempts: 1},
+			objects:   true,
+		},
+		{
+			name:        "dead letter",
+			job:         `{"source":"testdata/fragments"}`,
+			deadLetter:  "projects/p/topics/dead",
+			calls:       []string{"modifyAckDeadline", "publish", "acknowledge", "publish"},
+			event:       &exportEvent{Job: &exportJob{Source: "testdata/fragments"}, MessageID: "m1", Status: "failed", Attempts: 2},
+			deadLetters: 1,
+		},
+		{
+			name:      "retry",
+			job:       `{"source":"testdata/fragments"}`,
+			calls:     []string{"modifyAckDeadline", "modifyAckDeadline"},
+			deadlines: []int{60, 60},
+		},
+		{
+			name:      "retry backoff",
+			job:       `{"source":"testdata/fragments"}`,
+			attempt:   3,
+			calls:     []string{"modifyAckDeadline", "modifyAckDeadline"},
+			deadlines: []int{60, 240},
+		},
+		{
+			name:      "retry max",
+			job:       `{"source":"testdata/fragments"}`,
+			attempt:   10,
+			calls:     []string{"modifyAckDeadline", "modifyAckDeadline"},
+			deadlines: []int{60, 600},
+		},
+		{
+			name:        "outside output",
+			job:         `{"source":"testdata/simple-2-steps.md","output":"../labs"}`,
+			deadLetter:  "projects/p/topics/dead",
+			calls:       []string{"modifyAckDeadline", "publish", "acknowledge", "publish"},
+			event:       &exportEvent{Job: &exportJob{Source: "testdata/simple-2-steps.md", Output: "../labs"}, MessageID: "m1", Status: "failed", Attempts: 2},
+			deadLetters: 1,
+		},
+		{
+			name:        "malformed",
+			job:         `{"format":"md"}`,
+			deadLetter:  "projects/p/topics/dead",
+			calls:       []string{"modifyAckDeadline", "publish", "acknowledge", "publish"},
+			event:       &exportEvent{Job: &exportJob{Format: "md"}, MessageID: "m1", Status: "failed"},
+			deadLetters: 1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &fakePubSub{published: map[string][]*pubsubMessage{}}
+			ts := httptest.NewServer(f)
+			defer ts.Close()
+			out := t.TempDir()
+			var slept []time.Duration
+			wk := &worker{
+				ps: &pubsubClient{client: ts.Client(), baseURL: ts.URL + "/"},
+				up: gcsUploader{client: ts.Client(), uploadURL: ts.URL + "/upload/"},
+				opts: CmdWorkerOptions{
+					Attempts:     2,
+					DeadLetter:   tc.deadLetter,
+					Export:       CmdExportOptions{Output: out, Tmplout: "html"},
+					Subscription: "projects/p/subscriptions/jobs",
+					Topic:        "projects/p/topics/done",
+				},
+				sleep:       func(d time.Duration) { slept = append(slept, d) },
+				extendEvery: time.Hour,
+			}
+			wk.handle(context.Background(), &pubsubReceived{AckID: "a1", Message: pubsubMessage{Data: []byte(tc.job), ID: "m1"}, DeliveryAttempt: tc.attempt})
+
+			if diff := cmp.Diff(tc.calls, f.calls); diff != "" {
+				t.Errorf("calls mismatch (-want +got):\n%s", diff)
+			}
+			want := tc.deadlines
+			if want == nil {
+				want = []int{60}
+			}
+			if diff := cmp.Diff(want, f.deadlines); diff != "" {
+				t.Errorf("deadlines mismatch (-want +got):\n%s", diff)
+			}
+			if n := len(f.published["projects/p/topics/dead"]); n != tc.deadLetters {
+				t.Errorf("dead letters = %d; want %d", n, tc.deadLetters)
+			}
+			var event *exportEvent
+			if done := f.published["projects/p/topics/done"]; len(done) == 1 {
+				if err := json.Unmarshal(done[0].Data, &event); err != nil {
+					t.Fatal(err)
+				}
+				if done[0].Attributes["status"] != event.Status {
+					t.Errorf("status attribute = %q; want %q", done[0].Attributes["status"], event.Status)
+				}
+				// errors are system dependent
+				event.Error = ""
+			}
+			if diff := cmp.Diff(tc.event, event); diff != "" {
+				t.Errorf("event mismatch (-want +got):\n%s", diff)
+			}
+			if tc.event != nil && tc.event.Attempts == 2 && len(slept) != 1 {
+				t.Errorf("slept %v; want one backoff", slept)
+			}
+			if tc.file != "" {
+				if _, err := os.Stat(filepath.Join(out, tc.file)); err != nil {
+					t.Error(err)
+				}
+			}
+			if tc.objects != (len(f.objects) > 0) {
+				t.Errorf("objects = %v; want uploads: %v", f.objects, tc.objects)
+			}
+			for _, name := range f.objects {
+				if !strings.HasPrefix(name, "labs/example/") {
+					t.Errorf("object %q not under labs/example/", name)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkerKeepAlive(t *testing.T) {
+	f := &fakePubSub{published: map[string][]*pubsubMessage{}}
+	ts := httptest.NewServer(f)
+	defer ts.Close()
+	wk := &worker{
+		ps:          &pubsubClient{client: ts.Client(), baseURL: ts.URL + "/"},
+		opts:        CmdWorkerOptions{Subscription: "projects/p/subscriptions/jobs"},
+		extendEvery: time.Millisecond,
+	}
+	stop := wk.keepAlive(&pubsubReceived{AckID: "a1"})
+	time.Sleep(20 * time.Millisecond)
+	stop()
+	f.mu.Lock()
+	n := len(f.deadlines)
+	f.mu.Unlock()
+	if n < 2 {
+		t.Errorf("deadline extended %d times; want at least 2", n)
+	}
+	time.Sleep(5 * time.Millisecond)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.deadlines) != n {
+		t.Errorf("deadline extended %d times after stop", len(f.deadlines)-n)
+	}
+	for _, d := range f.deadlines {
+		if d != 60 {
+			t.Errorf("deadline = %ds; want 60s", d)
+		}
+	}
+}
+
+func TestJobOutput(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+		ok  bool
+	}{
+		{"", "root", true},
+		{"labs/cloud", filepath.Join("root", "labs", "cloud"), true},
+		{"labs/../cloud", filepath.Join("root", "cloud"), true},
+		{"..", "", false},
+		{"../other", "", false},
+		{"/abs", "", false},
+	}
+	for _, tc := range tests {
+		out, err := jobOutput("root", tc.in)
+		if (err == nil) != tc.ok || out != tc.out {
+			t.Errorf("jobOutput(%q) = %q, %v; want %q, ok: %v", tc.in, out, err, tc.out, tc.ok)
+		}
+	}
+}
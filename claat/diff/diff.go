@@ -0,0 +1,382 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kind is the kind of a Change.
+type Kind string
+
+// Kinds of changes.
+const (
+	StepAdded   Kind = "step added"
+	StepRemoved Kind = "step removed"
+	StepRenamed Kind = "step renamed"
+	StepMoved   Kind = "step moved"
+	TextAdded   Kind = "text added"
+	TextRemoved Kind = "text removed"
+	TextChanged Kind = "text changed"
+	CodeAdded   Kind = "code added"
+	CodeRemoved Kind = "code removed"
+	CodeChanged Kind = "code changed"
+)
+
+// Change is a difference between two versions of a codelab.
+type Change struct {
+	Kind Kind   `json:"kind"`
+	Step string `json:"step"`          // Step title, in the new version unless removed
+	Old  string `json:"old,omitempty"` // Old title, position, text or code, depending on Kind
+	New  string `json:"new,omitempty"` // New title, position, text or code, depending on Kind
+}
+
+// String returns a one line description of c, with text and code shortened.
+func (c *Change) String() string {
+	switch c.Kind {
+	case StepAdded:
+		return fmt.Sprintf("Added step %q", c.Step)
+	case StepRemoved:
+		return fmt.Sprintf("Removed step %q", c.Step)
+	case StepRenamed:
+		return fmt.Sprintf("Renamed step %q to %q", c.Old, c.New)
+	case StepMoved:
+		return fmt.Sprintf("Moved step %q from position %s to %s", c.Step, c.Old, c.New)
+	case TextAdded:
+		return fmt.Sprintf("Added text to step %q: %q", c.Step, shorten(c.New))
+	case TextRemoved:
+		return fmt.Sprintf("Removed text from step %q: %q", c.Step, shorten(c.Old))
+	case TextChanged:
+		return fmt.Sprintf("Changed text of step %q: %q to %q", c.Step, shorten(c.Old), shorten(c.New))
+	case CodeAdded:
+		return fmt.Sprintf("Added code to step %q: %q", c.Step, shorten(c.New))
+	case CodeRemoved:
+		return fmt.Sprintf("Removed code from step %q: %q", c.Step, shorten(c.Old))
+	case CodeChanged:
+		return fmt.Sprintf("Changed code of step %q: %q to %q", c.Step, shorten(c.Old), shorten(c.New))
+	}
+	return fmt.Sprintf("%s in step %q", c.Kind, c.Step)
+}
+
+// maxShort is the maximum number of runes of text shortened by shorten.
+const maxShort = 60
+
+// shorten returns the first line of s, trimmed and truncated to maxShort runes,
+// with an ellipsis if anything was left out.
+func shorten(s string) string {
+	s = strings.TrimSpace(s)
+	line := s
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		line = s[:i]
+	}
+	if utf8.RuneCountInString(line) > maxShort {
+		line = string([]rune(line)[:maxShort])
+	}
+	if line != s {
+		line = strings.TrimSpace(line) + "…"
+	}
+	return line
+}
+
+// Compare returns the changes from codelab old to codelab new.
+//
+// Steps are matched by title, then unmatched steps of the same content
+// are reported as renamed. Removed steps come first, then the changes
+// of each step of new, in order: added, renamed or moved steps, followed
+// by changes of their blocks of text and code.
+// Steps are moved if they are not part of the longest sequence of steps
+// in the same order in both versions.
+func Compare(old, new *Outline) []*Change {
+	match, renamed := matchSteps(old, new)
+	used := make([]bool, len(old.Steps))
+	for _, j := range match {
+		if j >= 0 {
+			used[j] = true
+		}
+	}
+	var res []*Change
+	for j, ost := range old.Steps {
+		if !used[j] {
+			res = append(res, &Change{Kind: StepRemoved, Step: ost.Title})
+		}
+	}
+	inOrder := longestIncreasing(match)
+	for i, st := range new.Steps {
+		j := match[i]
+		if j < 0 {
+			res = append(res, &Change{Kind: StepAdded, Step: st.Title})
+			continue
+		}
+		if renamed[i] {
+			res = append(res, &Change{Kind: StepRenamed, Step: st.Title, Old: old.Steps[j].Title, New: st.Title})
+		}
+		if !inOrder[i] {
+			res = append(res, &Change{Kind: StepMoved, Step: st.Title, Old: strconv.Itoa(j + 1), New: strconv.Itoa(i + 1)})
+		}
+		ost := old.Steps[j]
+		res = append(res, compareBlocks(st.Title, ost.Text, st.Text, TextAdded, TextRemoved, TextChanged)...)
+		res = append(res, compareBlocks(st.Title, ost.Code, st.Code, CodeAdded, CodeRemoved, CodeChanged)...)
+	}
+	return res
+}
+
+// matchSteps returns the index of the step of old matching each step
+// of new, or -1 if there is none, and whether the steps were renamed.
+// Steps match by title first, then by content.
+func matchSteps(old, new *Outline) (match []int, renamed []bool) {
+	match = make([]int, len(new.Steps))
+	renamed = make([]bool, len(new.Steps))
+	used := make([]bool, len(old.Steps))
+	for i, st := range new.Steps {
+		match[i] = -1
+		for j, ost := range old.Steps {
+			if !used[j] && ost.Title == st.Title {
+				match[i], used[j] = j, true
+				break
+			}
+		}
+	}
+	for i, st := range new.Steps {
+		if match[i] >= 0 {
+			continue
+		}
+		for j, ost := range old.Steps {
+			if !used[j] && sameContent(ost, st) {
+				match[i], used[j], renamed[i] = j, true, true
+				break
+			}
+		}
+	}
+	return match, renamed
+}
+
+// sameContent reports whether steps a and b have the same text and code.
+func sameContent(a, b *StepOutline) bool {
+	return equal(a.Text, b.Text) && equal(a.Code, b.Code) && (len(a.Text) > 0 || len(a.Code) > 0)
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// longestIncreasing reports for each element of idx whether it is part of
+// the longest increasing subsequence of its non-negative elements,
+// found by patience sorting in O(n log n). Of sequences of the same
+// length, that ending first is kept.
+func longestIncreasing(idx []int) []bool {
+	n := len(idx)
+	prev := make([]int, n)
+	var tails []int // index of the smallest tail of the sequences of each length
+	best := -1
+	for i, v := range idx {
+		prev[i] = -1
+		if v < 0 {
+			continue
+		}
+		k := sort.Search(len(tails), func(k int) bool { return idx[tails[k]] >= v })
+		if k > 0 {
+			prev[i] = tails[k-1]
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+			best = i
+		} else {
+			tails[k] = i
+		}
+	}
+	res := make([]bool, n)
+	for i := best; i >= 0; i = prev[i] {
+		res[i] = true
+	}
+	return res
+}
+
+// compareBlocks returns the changes from blocks a to blocks b of step.
+// A run of removed blocks followed by a run of added blocks are changed
+// blocks, pairwise.
+func compareBlocks(step string, a, b []string, added, removed, changed Kind) []*Change {
+	var res []*Change
+	var dels, ins []string
+	flush := func() {
+		for k := 0; k < len(dels) || k < len(ins); k++ {
+			switch {
+			case k >= len(ins):
+				res = append(res, &Change{Kind: removed, Step: step, Old: dels[k]})
+			case k >= len(dels):
+				res = append(res, &Change{Kind: added, Step: step, New: ins[k]})
+			default:
+				res = append(res, &Change{Kind: changed, Step: step, Old: dels[k], New: ins[k]})
+			}
+		}
+		dels, ins = nil, nil
+	}
+	for _, e := range Edits(a, b) {
+		switch e.Op {
+		case Delete:
+			if len(ins) > 0 {
+				flush()
+			}
+			dels = append(dels, a[e.Old])
+		case Insert:
+			ins = append(ins, b[e.New])
+		default:
+			flush()
+		}
+	}
+	flush()
+	return res
+}
+
+// Op is the operation of an Edit.
+type Op int
+
+// Edit operations.
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Edit is an operation of an edit script from a sequence to another.
+// Old is the index of the element in the old sequence, for Equal and Delete,
+// and New is the index in the new sequence, for Equal and Insert.
+type Edit struct {
+	Op       Op
+	Old, New int
+}
+
+// maxEditCells bounds the size of the table of Edits, the product
+// of the lengths of the sequences once their common prefix and suffix
+// are trimmed.
+const maxEditCells = 1 << 20
+
+// Edits returns the shortest edit script from a to b, based on their
+// longest common subsequence. Deletions come before insertions
+// between elements kept.
+//
+// Past their common prefix and suffix, sequences too long to compare
+// within maxEditCells are edited by deleting all the elements of a
+// and inserting all those of b.
+func Edits(a, b []string) []Edit {
+	var res []Edit
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		res = append(res, Edit{Equal, pre, pre})
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	ma, mb := a[pre:len(a)-suf], b[pre:len(b)-suf]
+	if len(ma)*len(mb) > maxEditCells {
+		for i := range ma {
+			res = append(res, Edit{Op: Delete, Old: pre + i, New: -1})
+		}
+		for j := range mb {
+			res = append(res, Edit{Op: Insert, Old: -1, New: pre + j})
+		}
+	} else {
+		res = append(res, lcsEdits(ma, mb, pre)...)
+	}
+	for k := suf; k > 0; k-- {
+		res = append(res, Edit{Equal, len(a) - k, len(b) - k})
+	}
+	return res
+}
+
+// lcsEdits returns the edits of Edits from a to b, from their longest
+// common subsequence, with indices offset by off.
+func lcsEdits(a, b []string, off int) []Edit {
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var res []Edit
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			res = append(res, Edit{Equal, off + i, off + j})
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			res = append(res, Edit{Op: Delete, Old: off + i, New: -1})
+			i++
+		default:
+			res = append(res, Edit{Op: Insert, Old: -1, New: off + j})
+			j++
+		}
+	}
+	return res
+}
+
+// Format writes changes to w as a Markdown list, followed by a line
+// reading "No changes." if there are none. Changed code is followed by
+// a diff of its lines.
+func Format(w io.Writer, changes []*Change) error {
+	if len(changes) == 0 {
+		_, err := io.WriteString(w, "No changes.\n")
+		return err
+	}
+	var b strings.Builder
+	for _, c := range changes {
+		fmt.Fprintf(&b, "- %s\n", c)
+		if c.Kind != CodeChanged {
+			continue
+		}
+		b.WriteString("\n  ```diff\n")
+		old := strings.Split(strings.TrimSuffix(c.Old, "\n"), "\n")
+		new := strings.Split(strings.TrimSuffix(c.New, "\n"), "\n")
+		for _, e := range Edits(old, new) {
+			switch e.Op {
+			case Equal:
+				fmt.Fprintf(&b, "   %s\n", old[e.Old])
+			case Delete:
+				fmt.Fprintf(&b, "  -%s\n", old[e.Old])
+			case Insert:
+				fmt.Fprintf(&b, "  +%s\n", new[e.New])
+			}
+		}
+		b.WriteString("  ```\n\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
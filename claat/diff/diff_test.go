@@ -0,0 +1,195 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func text(s string) nodes.Node {
+	return nodes.NewTextNode(nodes.NewTextNodeOptions{Value: s})
+}
+
+func TestNewOutline(t *testing.T) {
+	clab := &types.Codelab{Meta: types.Meta{ID: "lab", Title: "Lab"}}
+	st := clab.NewStep("Setup")
+	st.Duration = 5 * time.Minute
+	list := nodes.NewItemsListNode("", 0)
+	list.NewItem(text("Open  the\nconsole."))
+	list.NewItem(text("Run:"), nodes.NewCodeNode("gcloud init\n", true, ""))
+	st.Content.Append(nodes.NewListNode(text("Before you begin")), list, nodes.NewListNode())
+	clab.NewStep("Done")
+
+	want := &Outline{
+		ID:    "lab",
+		Title: "Lab",
+		Steps: []*StepOutline{
+			{
+				Title:    "Setup",
+				Duration: 5,
+				Text:     []string{"Before you begin", "Open the console.", "Run:"},
+				Code:     []string{"gcloud init\n"},
+			},
+			{Title: "Done"},
+		},
+	}
+	if diff := cmp.Diff(want, NewOutline(clab)); diff != "" {
+		t.Errorf("NewOutline() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	step := func(title string, text ...string) *StepOutline {
+		return &StepOutline{Title: title, Text: text}
+	}
+	tests := []struct {
+		name     string
+		old, new []*StepOutline
+		want     []*Change
+	}{
+		{
+			name: "same",
+			old:  []*StepOutline{step("A", "a"), step("B", "b")},
+			new:  []*StepOutline{step("A", "a"), step("B", "b")},
+		},
+		{
+			name: "added and removed",
+			old:  []*StepOutline{step("A", "a"), step("B", "b")},
+			new:  []*StepOutline{step("A", "a"), step("C", "c")},
+			want: []*Change{
+				{Kind: StepRemoved, Step: "B"},
+				{Kind: StepAdded, Step: "C"},
+			},
+		},
+		{
+			name: "renamed",
+			old:  []*StepOutline{step("A", "a"), step("B", "b")},
+			new:  []*StepOutline{step("A", "a"), step("Bee", "b")},
+			want: []*Change{{Kind: StepRenamed, Step: "Bee", Old: "B", New: "Bee"}},
+		},
+		{
+			name: "moved",
+			old:  []*StepOutline{step("A"), step("B"), step("C"), step("D")},
+			new:  []*StepOutline{step("A"), step("D"), step("B"), step("C")},
+			want: []*Change{{Kind: StepMoved, Step: "D", Old: "4", New: "2"}},
+		},
+		{
+			name: "text",
+			old:  []*StepOutline{step("A", "a", "b", "c", "d")},
+			new:  []*StepOutline{step("A", "a", "B", "c", "e", "f")},
+			want: []*Change{
+				{Kind: TextChanged, Step: "A", Old: "b", New: "B"},
+				{Kind: TextChanged, Step: "A", Old: "d", New: "e"},
+				{Kind: TextAdded, Step: "A", New: "f"},
+			},
+		},
+		{
+			name: "code",
+			old:  []*StepOutline{{Title: "A", Code: []string{"ls\n", "rm -rf tmp\n"}}},
+			new:  []*StepOutline{{Title: "A", Code: []string{"ls -l\n"}}},
+			want: []*Change{
+				{Kind: CodeChanged, Step: "A", Old: "ls\n", New: "ls -l\n"},
+				{Kind: CodeRemoved, Step: "A", Old: "rm -rf tmp\n"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Compare(&Outline{Steps: tc.old}, &Outline{Steps: tc.new})
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Compare() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEdits(t *testing.T) {
+	got := Edits([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"})
+	want := []Edit{
+		{Equal, 0, 0},
+		{Delete, 1, -1},
+		{Insert, -1, 1},
+		{Equal, 2, 2},
+		{Insert, -1, 3},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Edits() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestEditsLarge(t *testing.T) {
+	const n = 2000
+	a := make([]string, n)
+	b := make([]string, n)
+	for i := range a {
+		a[i], b[i] = strconv.Itoa(i), strconv.Itoa(-i)
+	}
+	a[0], b[0] = "same", "same"
+	a[n-1], b[n-1] = "end", "end"
+	got := Edits(a, b)
+	if len(got) != 2*n-2 {
+		t.Fatalf("len(Edits()) = %d; want %d", len(got), 2*n-2)
+	}
+	if want := (Edit{Equal, 0, 0}); got[0] != want {
+		t.Errorf("Edits()[0] = %v; want %v", got[0], want)
+	}
+	if want := (Edit{Delete, 1, -1}); got[1] != want {
+		t.Errorf("Edits()[1] = %v; want %v", got[1], want)
+	}
+	if want := (Edit{Insert, -1, 1}); got[n-1] != want {
+		t.Errorf("Edits()[%d] = %v; want %v", n-1, got[n-1], want)
+	}
+	if want := (Edit{Equal, n - 1, n - 1}); got[len(got)-1] != want {
+		t.Errorf("last of Edits() = %v; want %v", got[len(got)-1], want)
+	}
+}
+
+func TestLongestIncreasing(t *testing.T) {
+	got := longestIncreasing([]int{3, -1, 0, 4, 1, 2, -1, 5})
+	want := []bool{false, false, true, false, true, true, false, true}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("longestIncreasing() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	changes := []*Change{
+		{Kind: StepAdded, Step: "Deploy"},
+		{Kind: TextAdded, Step: "Deploy", New: "A very long paragraph which goes on and on, well past the length of a line."},
+		{Kind: CodeChanged, Step: "Deploy", Old: "gcloud run deploy\n", New: "gcloud run deploy \\\n  --region us-central1\n"},
+	}
+	want := "- Added step \"Deploy\"\n" +
+		"- Added text to step \"Deploy\": \"A very long paragraph which goes on and on, well past the le…\"\n" +
+		"- Changed code of step \"Deploy\": \"gcloud run deploy\" to \"gcloud run deploy \\\\…\"\n" +
+		"\n  ```diff\n  -gcloud run deploy\n  +gcloud run deploy \\\n  +  --region us-central1\n  ```\n\n"
+	var buf bytes.Buffer
+	if err := Format(&buf, changes); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("Format() diff (-want +got):\n%s", diff)
+	}
+	buf.Reset()
+	if err := Format(&buf, nil); err != nil || buf.String() != "No changes.\n" {
+		t.Errorf("Format(nil) = %q, %v; want %q", buf.String(), err, "No changes.\n")
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diff compares versions of a codelab at the level of its parsed
+// content, such as step titles, blocks of text and code, rather than
+// the text of its rendered output.
+package diff
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// Outline is the content of a codelab compared by Compare.
+// It is small enough to be stored along with an export, so that a later
+// version can be compared to it without the source of the earlier one.
+type Outline struct {
+	ID    string         `json:"id"`
+	Title string         `json:"title"`
+	Steps []*StepOutline `json:"steps"`
+}
+
+// StepOutline is the content of a codelab step.
+type StepOutline struct {
+	Title    string   `json:"title"`
+	Duration int      `json:"duration"`       // Duration in minutes
+	Text     []string `json:"text,omitempty"` // Plain text of the blocks of the step, in order
+	Code     []string `json:"code,omitempty"` // Code blocks of the step, in order
+}
+
+// NewOutline returns the outline of clab.
+// Each top-level block of a step, or item of a top-level list, is a block
+// of text, with consecutive whitespace collapsed. Code blocks at any level
+// are blocks of code, and are not part of the text.
+func NewOutline(clab *types.Codelab) *Outline {
+	o := &Outline{ID: clab.ID, Title: clab.Title}
+	for _, st := range clab.Steps {
+		so := &StepOutline{Title: st.Title, Duration: int(st.Duration.Minutes())}
+		for _, n := range st.Content.Nodes {
+			for _, b := range Blocks(n) {
+				if t := Text(b); t != "" {
+					so.Text = append(so.Text, t)
+				}
+			}
+		}
+		nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			if cn, ok := n.(*nodes.CodeNode); ok && entering {
+				so.Code = append(so.Code, cn.Value)
+			}
+			return n, nil
+		})
+		o.Steps = append(o.Steps, so)
+	}
+	return o
+}
+
+// Blocks returns the blocks of text of top-level node n: the items of
+// a list, or n itself.
+func Blocks(n nodes.Node) []nodes.Node {
+	l, ok := n.(*nodes.ItemsListNode)
+	if !ok {
+		return []nodes.Node{n}
+	}
+	res := make([]nodes.Node, len(l.Items))
+	for i, it := range l.Items {
+		res[i] = it
+	}
+	return res
+}
+
+// Text returns the plain text of block n, as in an Outline.
+func Text(n nodes.Node) string {
+	return strings.Join(strings.Fields(nodes.PlainText(n)), " ")
+}
+
+// ReadOutline reads the outline stored in file, as written by WriteOutline.
+func ReadOutline(file string) (*Outline, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	o := &Outline{}
+	return o, json.Unmarshal(b, o)
+}
+
+// WriteOutline stores o in file, in JSON format.
+func WriteOutline(file string, o *Outline) error {
+	b, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, append(b, '\n'), 0644)
+}
@@ -0,0 +1,190 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// Review marks the content of clab changed since the version outlined
+// by old with review nodes, for reviewers to focus on the changes.
+//
+// The content of steps added since old is marked as inserted. In other
+// steps, blocks of text and code which are not in the matching step of old,
+// as Compare finds them, are marked as inserted, and those of old which
+// are not in clab anymore are written back where they were, marked as
+// deleted. Removed steps are marked as deleted at the start of the step
+// which follows them, or at the end of the last step.
+func Review(old *Outline, clab *types.Codelab) {
+	if len(clab.Steps) == 0 {
+		return
+	}
+	match, _ := matchSteps(old, NewOutline(clab))
+	used := make([]bool, len(old.Steps))
+	for _, j := range match {
+		if j >= 0 {
+			used[j] = true
+		}
+	}
+	// removed steps by the index of the step which follows them
+	removed := make(map[int][]nodes.Node)
+	for j, ost := range old.Steps {
+		if used[j] {
+			continue
+		}
+		next := len(clab.Steps)
+		for i, m := range match {
+			if m > j {
+				next = i
+				break
+			}
+		}
+		removed[next] = append(removed[next], deletedStep(ost)...)
+	}
+
+	for i, st := range clab.Steps {
+		if j := match[i]; j >= 0 {
+			reviewStep(old.Steps[j], st)
+		} else if !st.Content.Empty() {
+			st.Content.Nodes = []nodes.Node{nodes.NewReviewNode(nodes.ReviewInserted, st.Content.Nodes...)}
+		}
+		if dels := removed[i]; len(dels) > 0 {
+			st.Content.Nodes = append([]nodes.Node{nodes.NewReviewNode(nodes.ReviewDeleted, dels...)}, st.Content.Nodes...)
+		}
+	}
+	if dels := removed[len(clab.Steps)]; len(dels) > 0 {
+		last := clab.Steps[len(clab.Steps)-1]
+		last.Content.Append(nodes.NewReviewNode(nodes.ReviewDeleted, dels...))
+	}
+}
+
+// deletedStep returns the nodes of removed step st: its title,
+// followed by its blocks of text and code.
+func deletedStep(st *StepOutline) []nodes.Node {
+	res := []nodes.Node{paragraph("Removed step: " + st.Title)}
+	for _, t := range st.Text {
+		res = append(res, paragraph(t))
+	}
+	for _, c := range st.Code {
+		res = append(res, nodes.NewCodeNode(c, false, ""))
+	}
+	return res
+}
+
+func paragraph(s string) nodes.Node {
+	return nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: s}))
+}
+
+// reviewStep marks the blocks of st changed since step old.
+func reviewStep(old *StepOutline, st *types.Step) {
+	top := st.Content.Nodes
+	// blocks of text of st and the index of their top-level node;
+	// items are nil for top-level blocks
+	var texts []string
+	var textTop []int
+	var items []*nodes.ListNode
+	// code blocks of st and the index of their top-level node
+	var codes []*nodes.CodeNode
+	var codeTop []int
+	for k, n := range top {
+		for _, b := range Blocks(n) {
+			t := Text(b)
+			if t == "" {
+				continue
+			}
+			texts = append(texts, t)
+			textTop = append(textTop, k)
+			item, _ := b.(*nodes.ListNode)
+			if b == n {
+				item = nil
+			}
+			items = append(items, item)
+		}
+		nodes.WalkNodes([]nodes.Node{n}, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			if cn, ok := n.(*nodes.CodeNode); ok && entering {
+				codes = append(codes, cn)
+				codeTop = append(codeTop, k)
+			}
+			return n, nil
+		})
+	}
+
+	// deleted nodes by the index of the top-level node which follows them
+	dels := make(map[int][]nodes.Node)
+	insTop := make(map[int]bool)
+	insCode := make(map[*nodes.CodeNode]bool)
+	var pending []nodes.Node
+	flush := func(k int) {
+		dels[k] = append(dels[k], pending...)
+		pending = nil
+	}
+	for _, e := range Edits(old.Text, texts) {
+		switch e.Op {
+		case Delete:
+			pending = append(pending, paragraph(old.Text[e.Old]))
+			continue
+		case Insert:
+			if items[e.New] != nil {
+				items[e.New].Nodes = []nodes.Node{nodes.NewReviewNode(nodes.ReviewInserted, items[e.New].Nodes...)}
+			} else {
+				insTop[textTop[e.New]] = true
+			}
+		}
+		flush(textTop[e.New])
+	}
+	flush(len(top))
+	for _, e := range Edits(old.Code, codeValues(codes)) {
+		switch e.Op {
+		case Delete:
+			pending = append(pending, nodes.NewCodeNode(old.Code[e.Old], false, ""))
+			continue
+		case Insert:
+			insCode[codes[e.New]] = true
+		}
+		flush(codeTop[e.New])
+	}
+	flush(len(top))
+
+	var res []nodes.Node
+	for k, n := range top {
+		if len(dels[k]) > 0 {
+			res = append(res, nodes.NewReviewNode(nodes.ReviewDeleted, dels[k]...))
+		}
+		if insTop[k] {
+			res = append(res, nodes.NewReviewNode(nodes.ReviewInserted, n))
+			continue
+		}
+		nn, _ := nodes.WalkNodes([]nodes.Node{n}, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			if cn, ok := n.(*nodes.CodeNode); ok && entering && insCode[cn] {
+				return nodes.NewReviewNode(nodes.ReviewInserted, cn), nodes.SkipChildren
+			}
+			return n, nil
+		})
+		res = append(res, nn...)
+	}
+	if end := dels[len(top)]; len(end) > 0 {
+		res = append(res, nodes.NewReviewNode(nodes.ReviewDeleted, end...))
+	}
+	st.Content.Nodes = res
+}
+
+func codeValues(codes []*nodes.CodeNode) []string {
+	res := make([]string, len(codes))
+	for i, cn := range codes {
+		res[i] = cn.Value
+	}
+	return res
+}
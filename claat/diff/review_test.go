@@ -0,0 +1,94 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// marks returns the review nodes of st as kind: text, with the text
+// and code of their content.
+func marks(st *types.Step) []string {
+	var res []string
+	nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		rn, ok := n.(*nodes.ReviewNode)
+		if !ok || !entering {
+			return n, nil
+		}
+		var parts []string
+		nodes.WalkNodes(rn.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			if !entering {
+				return n, nil
+			}
+			switch n := n.(type) {
+			case *nodes.TextNode:
+				parts = append(parts, n.Value)
+			case *nodes.CodeNode:
+				parts = append(parts, strings.TrimSpace(n.Value))
+			}
+			return n, nil
+		})
+		res = append(res, string(rn.Kind)+": "+strings.Join(parts, "|"))
+		return n, nodes.SkipChildren
+	})
+	return res
+}
+
+func TestReview(t *testing.T) {
+	para := func(s string) nodes.Node {
+		return nodes.NewListNode(text(s))
+	}
+	old := &Outline{Steps: []*StepOutline{
+		{Title: "Setup", Text: []string{"Open the console.", "Create a project.", "Enable billing."}, Code: []string{"gcloud init\n"}},
+		{Title: "Cleanup", Text: []string{"Delete the project."}},
+		{Title: "Deploy", Text: []string{"Deploy it."}, Code: []string{"gcloud run deploy\n"}},
+	}}
+	clab := &types.Codelab{}
+	setup := clab.NewStep("Setup")
+	list := nodes.NewItemsListNode("", 0)
+	list.NewItem(text("Create a project."))
+	list.NewItem(text("Name it."))
+	setup.Content.Append(para("Open the console."), list, nodes.NewCodeNode("gcloud init\n", true, ""), nodes.NewCodeNode("gcloud config list\n", true, ""))
+	deploy := clab.NewStep("Deploy")
+	deploy.Content.Append(para("Deploy it."), nodes.NewCodeNode("gcloud run deploy --region us-central1\n", true, ""))
+	verify := clab.NewStep("Verify")
+	verify.Content.Append(para("Open the URL."))
+
+	Review(old, clab)
+	want := map[string][]string{
+		// deleted list items are marked before the list
+		"Setup": {
+			"del: Enable billing.",
+			"ins: Name it.",
+			"ins: gcloud config list",
+		},
+		"Deploy": {
+			"del: Removed step: Cleanup|Delete the project.",
+			"del: gcloud run deploy",
+			"ins: gcloud run deploy --region us-central1",
+		},
+		"Verify": {"ins: Open the URL."},
+	}
+	for _, st := range clab.Steps {
+		if diff := cmp.Diff(want[st.Title], marks(st)); diff != "" {
+			t.Errorf("Review() step %q marks diff (-want +got):\n%s", st.Title, diff)
+		}
+	}
+}
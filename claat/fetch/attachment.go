@@ -0,0 +1,105 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/util"
+)
+
+// SlurpAttachments downloads or copies the files of attachments of nn
+// to dir and rewrites their Src relative to the codelab directory.
+// Relative locations are resolved against codelab src, local files
+// must be in its directory. Slurped files are recorded in files,
+// mapping file names to the original locations.
+//
+// It returns an error if two different files have the same file name.
+func (f *Fetcher) SlurpAttachments(ctx context.Context, src, dir string, nn []nodes.Node, files map[string]string) error {
+	aa := nodes.AttachmentNodes(nn)
+	if len(aa) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, an := range aa {
+		name := an.FileName()
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return fmt.Errorf("%s: invalid attachment file name %q", an.Src, name)
+		}
+		orig, ok := files[name]
+		if ok && orig != an.Src {
+			return fmt.Errorf("attachments %s and %s have the same file name %q", orig, an.Src, name)
+		}
+		if !ok {
+			b, err := f.slurpFile(ctx, src, an.Src)
+			if err != nil {
+				return fmt.Errorf("%s: %v", an.Src, err)
+			}
+			if err := ioutil.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+				return err
+			}
+			files[name] = an.Src
+		}
+		an.Src = path.Join(util.FilesDirname, name)
+	}
+	return nil
+}
+
+// slurpFile returns the content of the file at ref, referenced by codelab src,
+// such as an attachment or included code. Files of GitHub repositories
+// may be referenced by their github.com page URL.
+func (f *Fetcher) slurpFile(ctx context.Context, src, ref string) ([]byte, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+	// Resolve ref like the codelab images, against a remote codelab source.
+	if srcURL, err := url.Parse(src); err == nil && srcURL.Host != "" {
+		u = srcURL.ResolveReference(u)
+	}
+	if u.Host != "" {
+		return f.slurpRemoteBytes(ctx, githubRawURL(u).String(), 5)
+	}
+	p, err := restrictPathToParent(ref, filepath.Dir(src))
+	if err != nil {
+		return nil, err
+	}
+	return f.readFile(p)
+}
+
+// githubRawURL returns the raw content URL of a file page URL u
+// of a GitHub repository, https://github.com/owner/repo/blob/ref/path.
+// Other URLs are returned as is.
+func githubRawURL(u *url.URL) *url.URL {
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 4)
+	if u.Host != "github.com" || len(parts) < 4 || parts[2] != "blob" {
+		return u
+	}
+	return &url.URL{
+		Scheme: "https",
+		Host:   "raw.githubusercontent.com",
+		Path:   "/" + parts[0] + "/" + parts[1] + "/" + parts[3],
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+const attachmentCodelab = `---
+id: lab
+summary: Files
+
+---
+
+# Files
+
+## Step 1
+
+<ql-file-download url="data/starter.zip">
+Starter code
+</ql-file-download>
+
+<ql-file-download url="https://example.com/dl?id=1" name="data.csv">
+</ql-file-download>
+`
+
+func TestSlurpCodelabAttachments(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"main.md":          attachmentCodelab,
+		"data/starter.zip": "zip",
+	})
+	rt := &testTransport{func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("a,b")),
+		}, nil
+	}}
+	f, err := NewFetcher("", nil, rt, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	clab, err := f.SlurpCodelab(context.Background(), filepath.Join(dir, "main.md"), out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aa := nodes.AttachmentNodes(clab.Steps[0].Content.Nodes)
+	if len(aa) != 2 || aa[0].Src != "files/starter.zip" || aa[1].Src != "files/data.csv" {
+		t.Fatalf("attachments = %v; want files/starter.zip and files/data.csv", aa)
+	}
+	for name, want := range map[string]string{"starter.zip": "zip", "data.csv": "a,b"} {
+		b, err := ioutil.ReadFile(filepath.Join(out, "lab", "files", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != want {
+			t.Errorf("%s = %q; want %q", name, b, want)
+		}
+		if _, ok := clab.Files[name]; !ok {
+			t.Errorf("Files has no %s: %v", name, clab.Files)
+		}
+	}
+}
+
+func TestSlurpAttachmentsSameName(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"a/x.zip": "a", "b/x.zip": "b"})
+	f, err := NewFetcher("", nil, nil, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nn := []nodes.Node{nodes.NewAttachmentNode("a/x.zip", ""), nodes.NewAttachmentNode("b/x.zip", "")}
+	err = f.SlurpAttachments(context.Background(), filepath.Join(dir, "main.md"), t.TempDir(), nn, make(map[string]string))
+	if err == nil || !strings.Contains(err.Error(), "same file name") {
+		t.Errorf("SlurpAttachments() error = %v; want same file name error", err)
+	}
+}
+
+func TestSlurpAttachmentsOutsideSource(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"secret.txt": "s", "lab/main.md": ""})
+	f, err := NewFetcher("", nil, nil, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nn := []nodes.Node{nodes.NewAttachmentNode("../secret.txt", "")}
+	err = f.SlurpAttachments(context.Background(), filepath.Join(dir, "lab", "main.md"), t.TempDir(), nn, make(map[string]string))
+	if err == nil {
+		t.Errorf("SlurpAttachments(../secret.txt) error = nil; want an error")
+	}
+}
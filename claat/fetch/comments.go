@@ -0,0 +1,234 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Comment is an open comment or suggested edit of a Google Doc.
+type Comment struct {
+	ID string `json:"id"`
+	// Author is the display name of the comment author.
+	// It is empty for suggestions, which the Docs API does not attribute.
+	Author string `json:"author,omitempty"`
+	// Content is the comment text, or the text a suggestion inserts.
+	Content string `json:"content,omitempty"`
+	// Quote is the document text the comment is anchored to,
+	// or the text a suggestion deletes.
+	Quote string `json:"quote,omitempty"`
+	// Replies is the number of replies in the comment thread.
+	Replies int `json:"replies,omitempty"`
+	// Suggestion reports whether this is a suggested edit rather than a comment.
+	Suggestion bool `json:"suggestion,omitempty"`
+}
+
+// String returns a one line description of c suitable for logs and errors.
+func (c *Comment) String() string {
+	if c.Suggestion {
+		switch {
+		case c.Quote == "":
+			return fmt.Sprintf("suggestion to insert %q", c.Content)
+		case c.Content == "":
+			return fmt.Sprintf("suggestion to delete %q", c.Quote)
+		}
+		return fmt.Sprintf("suggestion to replace %q with %q", c.Quote, c.Content)
+	}
+	s := "comment"
+	if c.Author != "" {
+		s += " by " + c.Author
+	}
+	if c.Quote != "" {
+		s += fmt.Sprintf(" on %q", c.Quote)
+	}
+	return s + ": " + c.Content
+}
+
+// IsGoogleDoc reports whether src is fetched from Google Docs,
+// i.e. it is neither a local file, a Notion page, a Drive folder nor another URL.
+func IsGoogleDoc(src string) bool {
+	return SourceScheme(src) == SchemeGoogleDoc
+}
+
+// OpenComments returns unresolved comments and pending suggestions of the Google Doc src,
+// Suggestions follow the comments, in document order.
+// It returns nil for sources which are not Google Docs.
+func (f *Fetcher) OpenComments(ctx context.Context, src string) ([]*Comment, error) {
+	if !IsGoogleDoc(src) {
+		return nil, nil
+	}
+	if err := f.initAuth(); err != nil {
+		return nil, err
+	}
+	id := gdocID(src)
+	cc, err := f.driveComments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sc, err := f.docSuggestions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return append(cc, sc...), nil
+}
+
+type driveComment struct {
+	ID       string `json:"id"`
+	Content  string `json:"content"`
+	Resolved bool   `json:"resolved"`
+	Deleted  bool   `json:"deleted"`
+	Author   struct {
+		DisplayName string `json:"displayName"`
+	} `json:"author"`
+	QuotedFileContent struct {
+		Value string `json:"value"`
+	} `json:"quotedFileContent"`
+	Replies []struct {
+		ID string `json:"id"`
+	} `json:"replies"`
+}
+
+// driveComments lists unresolved comments of the doc id, following all result pages.
+func (f *Fetcher) driveComments(ctx context.Context, id string) ([]*Comment, error) {
+	var comments []*Comment
+	var pageToken string
+	for {
+		q := url.Values{
+			"fields":   {"nextPageToken,comments(id,content,resolved,deleted,author(displayName),quotedFileContent(value),replies(id))"},
+			"pageSize": {"100"},
+		}
+		if pageToken != "" {
+			q.Set("pageToken", pageToken)
+		}
+		u := fmt.Sprintf("%s/files/%s/comments?%s", driveAPI, id, q.Encode())
+		res, err := f.retryGet(ctx, f.client(), u, 7)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			NextPageToken string          `json:"nextPageToken"`
+			Comments      []*driveComment `json:"comments"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&page)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: comments: %v", id, err)
+		}
+		for _, c := range page.Comments {
+			if c.Resolved || c.Deleted {
+				continue
+			}
+			comments = append(comments, &Comment{
+				ID:      c.ID,
+				Author:  c.Author.DisplayName,
+				Content: c.Content,
+				Quote:   c.QuotedFileContent.Value,
+				Replies: len(c.Replies),
+			})
+		}
+		if page.NextPageToken == "" {
+			return comments, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// docSuggestions returns pending suggested edits of the doc id.
+// Text runs sharing a suggestion ID are joined into a single Comment.
+func (f *Fetcher) docSuggestions(ctx context.Context, id string) ([]*Comment, error) {
+	q := url.Values{"suggestionsViewMode": {"SUGGESTIONS_INLINE"}}
+	u := fmt.Sprintf("%s/documents/%s?%s", docsAPI, id, q.Encode())
+	res, err := f.retryGet(ctx, f.client(), u, 7)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	var doc interface{}
+	if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%s: suggestions: %v", id, err)
+	}
+	var list []*Comment
+	byID := map[string]*Comment{}
+	suggestion := func(sid string) *Comment {
+		c := byID[sid]
+		if c == nil {
+			c = &Comment{ID: sid, Suggestion: true}
+			byID[sid] = c
+			list = append(list, c)
+		}
+		return c
+	}
+	walkTextRuns(doc, func(run map[string]interface{}) {
+		text, _ := run["content"].(string)
+		for _, sid := range stringList(run["suggestedInsertionIds"]) {
+			suggestion(sid).Content += text
+		}
+		for _, sid := range stringList(run["suggestedDeletionIds"]) {
+			suggestion(sid).Quote += text
+		}
+	})
+	for _, c := range list {
+		c.Content = strings.TrimSpace(c.Content)
+		c.Quote = strings.TrimSpace(c.Quote)
+	}
+	return list, nil
+}
+
+// walkTextRuns calls fn for each textRun object of a Docs API document,
+// in document order, including those in tables, headers and footnotes.
+func walkTextRuns(v interface{}, fn func(map[string]interface{})) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if run, ok := v["textRun"].(map[string]interface{}); ok {
+			fn(run)
+			return
+		}
+		// Visit the document body first, then other fields by name,
+		// so that the order of suggestions is stable.
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if (keys[i] == "body") != (keys[j] == "body") {
+				return keys[i] == "body"
+			}
+			return keys[i] < keys[j]
+		})
+		for _, k := range keys {
+			walkTextRuns(v[k], fn)
+		}
+	case []interface{}:
+		for _, e := range v {
+			walkTextRuns(e, fn)
+		}
+	}
+}
+
+func stringList(v interface{}) []string {
+	a, _ := v.([]interface{})
+	var s []string
+	for _, e := range a {
+		if e, ok := e.(string); ok {
+			s = append(s, e)
+		}
+	}
+	return s
+}
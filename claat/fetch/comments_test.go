@@ -0,0 +1,114 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOpenComments(t *testing.T) {
+	comments := []string{
+		`{"nextPageToken": "p2", "comments": [
+			{"id": "c1", "content": "Typo?", "author": {"displayName": "Ann"}, "quotedFileContent": {"value": "teh"}, "replies": [{"id": "r1"}]},
+			{"id": "c2", "content": "Done", "resolved": true}
+		]}`,
+		`{"comments": [
+			{"id": "c3", "content": "Gone", "deleted": true},
+			{"id": "c4", "content": "Add a diagram", "author": {"displayName": "Bob"}}
+		]}`,
+	}
+	doc := `{"body": {"content": [
+		{"paragraph": {"elements": [
+			{"textRun": {"content": "Run "}},
+			{"textRun": {"content": "gcloud ", "suggestedInsertionIds": ["s1"]}},
+			{"textRun": {"content": "init", "suggestedInsertionIds": ["s1"]}},
+			{"textRun": {"content": " old", "suggestedDeletionIds": ["s2"]}},
+			{"textRun": {"content": " new", "suggestedInsertionIds": ["s2"]}}
+		]}},
+		{"table": {"tableRows": [{"tableCells": [{"content": [
+			{"paragraph": {"elements": [{"textRun": {"content": "cell\n", "suggestedDeletionIds": ["s3"]}}]}}
+		]}]}]}}
+	]}}`
+	rt := &testTransport{func(r *http.Request) (*http.Response, error) {
+		var body string
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/files/doc1/comments"):
+			body = comments[0]
+			if r.URL.Query().Get("pageToken") == "p2" {
+				body = comments[1]
+			}
+		case strings.HasSuffix(r.URL.Path, "/documents/doc1"):
+			body = doc
+		default:
+			return &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}, nil
+	}}
+	f, err := NewFetcher("token", nil, rt, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := f.OpenComments(context.Background(), "https://docs.google.com/document/d/doc1/edit")
+	if err != nil {
+		t.Fatalf("OpenComments() = %v", err)
+	}
+	want := []*Comment{
+		{ID: "c1", Author: "Ann", Content: "Typo?", Quote: "teh", Replies: 1},
+		{ID: "c4", Author: "Bob", Content: "Add a diagram"},
+		{ID: "s1", Content: "gcloud init", Suggestion: true},
+		{ID: "s2", Content: "new", Quote: "old", Suggestion: true},
+		{ID: "s3", Quote: "cell", Suggestion: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("OpenComments() got diff (-want +got): %s", diff)
+	}
+}
+
+func TestOpenCommentsNotGoogleDoc(t *testing.T) {
+	f, _ := NewFetcher("token", nil, nil, FetcherOptions{})
+	for _, src := range []string{"testdata", "https://example.com/lab.md", "drive://folder"} {
+		got, err := f.OpenComments(context.Background(), src)
+		if err != nil || got != nil {
+			t.Errorf("OpenComments(%q) = %v, %v; want nil, nil", src, got, err)
+		}
+	}
+}
+
+func TestCommentString(t *testing.T) {
+	tests := []struct {
+		c    *Comment
+		want string
+	}{
+		{&Comment{Author: "Ann", Quote: "teh", Content: "Typo?"}, `comment by Ann on "teh": Typo?`},
+		{&Comment{Content: "Add a diagram"}, "comment: Add a diagram"},
+		{&Comment{Content: "init", Suggestion: true}, `suggestion to insert "init"`},
+		{&Comment{Quote: "cell", Suggestion: true}, `suggestion to delete "cell"`},
+		{&Comment{Quote: "old", Content: "new", Suggestion: true}, `suggestion to replace "old" with "new"`},
+	}
+	for _, tc := range tests {
+		if got := tc.c.String(); got != tc.want {
+			t.Errorf("%+v.String() = %q; want %q", tc.c, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+	"github.com/googlecodelabs/tools/claat/util"
+)
+
+// Manifest is a codelab composed of several source docs, such as
+// an introduction, modules written by different authors and a cleanup,
+// whose steps are concatenated in order. Its metadata fields, if set,
+// override those of the first source.
+//
+// A manifest is a JSON file, such as:
+//
+//	{
+//	  "id": "cloud-run-course",
+//	  "title": "Cloud Run from zero to production",
+//	  "sources": ["intro.md", "1AbC...docID", "cleanup.md"]
+//	}
+type Manifest struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Summary    string   `json:"summary"`
+	Authors    string   `json:"authors"`
+	Categories []string `json:"categories"`
+	Tags       []string `json:"tags"`
+	Feedback   string   `json:"feedback_link"`
+	// Sources are the sources of the codelab parts, in order. Local files
+	// and URLs are relative to the manifest.
+	Sources []string `json:"sources"`
+}
+
+// IsManifest reports whether codelab source src is a Manifest,
+// which is a local or remote file with a .json extension.
+func IsManifest(src string) bool {
+	if u, err := url.Parse(src); err == nil && u.Host != "" {
+		src = u.Path
+	}
+	return strings.EqualFold(filepath.Ext(src), ".json")
+}
+
+// ReadManifest decodes the Manifest in r.
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	m := &Manifest{}
+	if err := json.NewDecoder(r).Decode(m); err != nil {
+		return nil, fmt.Errorf("manifest: %v", err)
+	}
+	if len(m.Sources) == 0 {
+		return nil, fmt.Errorf("manifest: no sources")
+	}
+	for _, src := range m.Sources {
+		if IsManifest(src) {
+			return nil, fmt.Errorf("manifest: source %s is a manifest, which cannot be nested", src)
+		}
+	}
+	return m, nil
+}
+
+// apply overrides the fields of meta with those set in m.
+func (m *Manifest) apply(meta *types.Meta) {
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(&meta.ID, m.ID)
+	set(&meta.Title, m.Title)
+	set(&meta.Summary, m.Summary)
+	set(&meta.Authors, m.Authors)
+	set(&meta.Feedback, m.Feedback)
+	if len(m.Categories) > 0 {
+		meta.Categories = m.Categories
+		meta.Theme = m.Categories[0]
+	}
+	if len(m.Tags) > 0 {
+		meta.Tags = m.Tags
+	}
+}
+
+// slurpManifest is slurpCodelab of a Manifest. Each of its sources is
+// slurped with the ID of the composed codelab, so that all assets are
+// stored in its output directory, and their steps are concatenated.
+// Explicit activity tracking numbers and survey IDs are renumbered
+// in the composed codelab.
+//
+// All sources are parsed, and adjust is called with the metadata of each,
+// before any asset is stored, so that a rejected part stops the fetch
+// of the whole codelab.
+func (f *Fetcher) slurpManifest(ctx context.Context, src, output string, adjust func(*types.Meta) error) (*codelab, error) {
+	res, err := f.fetch(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	m, err := ReadManifest(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	parts := make([]*parsedCodelab, len(m.Sources))
+	for i, ref := range m.Sources {
+		p, err := f.parseCodelab(ctx, ResolveSource(src, ref), func(meta *types.Meta) error {
+			if i == 0 {
+				m.apply(meta)
+			} else {
+				meta.ID = parts[0].clab.ID
+				meta.URL = parts[0].clab.URL
+			}
+			if adjust != nil {
+				return adjust(meta)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ref, err)
+		}
+		parts[i] = p
+	}
+	var v *codelab
+	for i, p := range parts {
+		part, err := p.slurpAssets(ctx, output)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", m.Sources[i], err)
+		}
+		if v == nil {
+			v = part
+			v.Translations = nil // translations of parts are not composed
+			continue
+		}
+		v.merge(part)
+	}
+	if res.Mod.After(v.Mod) {
+		v.Mod = res.Mod
+	}
+	revs := make([]string, len(parts))
+	for i, p := range parts {
+		revs[i] = p.clab.Revision
+	}
+	v.Revision = composedRevision(res.Rev, revs)
+	renumberSurveys(v.Codelab)
+	return v, nil
+}
+
+// manifestRevision returns the revision of the codelab composed by
+// manifest src, as slurpManifest sets it, from the current revisions
+// of the manifest and its parts.
+func (f *Fetcher) manifestRevision(ctx context.Context, src string) (string, error) {
+	res, err := f.fetch(ctx, src)
+	if err != nil {
+		return "", err
+	}
+	m, err := ReadManifest(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	revs := make([]string, len(m.Sources))
+	for i, ref := range m.Sources {
+		part := ResolveSource(src, ref)
+		if revs[i], err = source(part).Revision(ctx, f, part); err != nil {
+			return "", fmt.Errorf("%s: %w", ref, err)
+		}
+	}
+	return composedRevision(res.Rev, revs), nil
+}
+
+// composedRevision returns the revision of a codelab composed by a manifest
+// of revision rev from parts of revisions parts, in order, which changes
+// whenever any of them does.
+func composedRevision(rev string, parts []string) string {
+	h := sha256.New()
+	io.WriteString(h, rev)
+	for _, p := range parts {
+		io.WriteString(h, "\n"+p)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// merge appends the steps of part to c, offsetting explicit activity
+// tracking numbers by the number of tracking blocks of c, and adds
+// the assets, environments, glossary and duration of part to c,
+// whose duration is declared if that of any part is.
+// Other metadata of part is ignored.
+func (c *codelab) merge(part *codelab) {
+	n := len(activities(c.Steps))
+	for _, an := range activities(part.Steps) {
+		if an.Step != 0 {
+			an.Step += n
+		}
+	}
+	c.Steps = append(c.Steps, part.Steps...)
+	c.Duration += part.Duration
+	c.DurationDeclared = c.DurationDeclared || part.DurationDeclared
+	c.Tags = util.Unique(append(c.Tags, part.Tags...))
+	if part.Mod.After(c.Mod) {
+		c.Mod = part.Mod
+	}
+	for k, v := range part.Imgs {
+		c.Imgs[k] = v
+	}
+	for k, v := range part.Files {
+		c.Files[k] = v
+	}
+	for t, d := range part.Glossary {
+		if _, ok := c.Glossary[t]; ok {
+			continue
+		}
+		if c.Glossary == nil {
+			c.Glossary = types.Glossary{}
+		}
+		c.Glossary[t] = d
+	}
+}
+
+// activities returns the activity tracking blocks of steps.
+func activities(steps []*types.Step) []*nodes.ActivityNode {
+	var res []*nodes.ActivityNode
+	for _, st := range steps {
+		nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			if an, ok := n.(*nodes.ActivityNode); ok && entering {
+				res = append(res, an)
+			}
+			return n, nil
+		})
+	}
+	return res
+}
+
+// renumberSurveys sets the IDs of the surveys of c in order, as parsers
+// number them, so that surveys of different parts do not share an ID.
+func renumberSurveys(c *types.Codelab) {
+	var n int
+	for _, st := range c.Steps {
+		nodes.WalkNodes(st.Content.Nodes, func(nd nodes.Node, entering bool) (nodes.Node, error) {
+			if sn, ok := nd.(*nodes.SurveyNode); ok && entering {
+				n++
+				sn.ID = fmt.Sprintf("%s-%d", c.ID, n)
+			}
+			return nd, nil
+		})
+	}
+}
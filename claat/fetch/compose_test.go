@@ -0,0 +1,185 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+	"github.com/googlecodelabs/tools/claat/util"
+)
+
+func TestIsManifest(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"course.json", true},
+		{"dir/Course.JSON", true},
+		{"https://example.com/course.json?v=2", true},
+		{"lab.md", false},
+		{"1AbCdEfGhIjKlMnOpQrStUvWxYz", false},
+	}
+	for _, tc := range tests {
+		if got := IsManifest(tc.src); got != tc.want {
+			t.Errorf("IsManifest(%q) = %v; want %v", tc.src, got, tc.want)
+		}
+	}
+}
+
+func TestReadManifest(t *testing.T) {
+	for _, in := range []string{
+		`{"id": "course"`,
+		`{"id": "course", "sources": []}`,
+		`{"id": "course", "sources": ["intro.md", "other.json"]}`,
+	} {
+		if _, err := ReadManifest(strings.NewReader(in)); err == nil {
+			t.Errorf("ReadManifest(%s) = nil error; want error", in)
+		}
+	}
+}
+
+func TestSlurpManifest(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"course.json": `{"id": "course", "title": "Course", "sources": ["intro.md", "parts/deploy.md"]}`,
+		"intro.md": "id: intro\nsummary: Learn\ntags: web\n\n# Intro\n\n## Welcome\nDuration: 2\n\n" +
+			"<ql-activity-tracking step=1>\nStart\n</ql-activity-tracking>\n\n" +
+			"<form>\n<name>Level</name>\n<input value=\"Novice\">\n</form>\n",
+		"parts/deploy.md": "id: deploy\ntags: kiosk\n\n# Deploy\n\n## Build\nDuration: 3\n\n" +
+			"<ql-activity-tracking step=1>\nBuild it\n</ql-activity-tracking>\n\n" +
+			"## Ship\nDuration: 5\n\n<form>\n<name>Done</name>\n<input value=\"Yes\">\n</form>\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := NewFetcher("", nil, nil, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clab, err := f.SlurpCodelab(context.Background(), filepath.Join(dir, "course.json"), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clab.ID != "course" || clab.Title != "Course" || clab.Summary != "Learn" {
+		t.Errorf("ID, Title, Summary = %q, %q, %q; want course, Course, Learn", clab.ID, clab.Title, clab.Summary)
+	}
+	if clab.Duration != 10 {
+		t.Errorf("Duration = %d; want 10", clab.Duration)
+	}
+	if diff := cmp.Diff([]string{"web", "kiosk"}, clab.Tags); diff != "" {
+		t.Errorf("Tags got diff (-want +got):\n%s", diff)
+	}
+	var titles, ids []string
+	var tracked []int
+	for _, st := range clab.Steps {
+		titles = append(titles, st.Title)
+		nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			switch n := n.(type) {
+			case *nodes.ActivityNode:
+				if entering {
+					tracked = append(tracked, n.Step)
+				}
+			case *nodes.SurveyNode:
+				if entering {
+					ids = append(ids, n.ID)
+				}
+			}
+			return n, nil
+		})
+	}
+	if diff := cmp.Diff([]string{"Welcome", "Build", "Ship"}, titles); diff != "" {
+		t.Errorf("step titles got diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{1, 2}, tracked); diff != "" {
+		t.Errorf("activity tracking steps got diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"course-1", "course-2"}, ids); diff != "" {
+		t.Errorf("survey IDs got diff (-want +got):\n%s", diff)
+	}
+
+	course := filepath.Join(dir, "course.json")
+	if rev, err := f.Revision(context.Background(), course); err != nil || rev != clab.Revision {
+		t.Errorf("Revision(%s) = %q, %v; want %q", course, rev, err, clab.Revision)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "parts/deploy.md"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if rev, err := f.Revision(context.Background(), course); err != nil || rev == clab.Revision {
+		t.Errorf("Revision(%s) after a part changed = %q, %v; want a new revision", course, rev, err)
+	}
+}
+
+func TestSlurpAccept(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"draft.md":     "id: draft\nstatus: draft\n\n# Draft\n\n## Step\n\nText.\n",
+		"course.json":  `{"id": "course", "sources": ["published.md", "draft.md"]}`,
+		"final.json":   `{"id": "final", "sources": ["published.md", "published.md"]}`,
+		"img.png":      "\x89PNG\r\n\x1a\n",
+		"published.md": "id: published\nstatus: published\n\n# Published\n\n## Step\n\n![img](img.png)\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	errDraft := errors.New("draft")
+	f, err := NewFetcher("", nil, nil, FetcherOptions{Accept: func(m *types.Meta) error {
+		if m.Status.Has("draft") {
+			return errDraft
+		}
+		return nil
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	if _, err := f.SlurpCodelab(context.Background(), filepath.Join(dir, "draft.md"), out); !errors.Is(err, errDraft) {
+		t.Errorf("SlurpCodelab(draft.md) = %v; want %v", err, errDraft)
+	}
+	if _, err := os.Stat(filepath.Join(out, "draft")); !os.IsNotExist(err) {
+		t.Errorf("assets of a rejected codelab were stored: %v", err)
+	}
+	// every part of a composed codelab is checked, before any asset is stored
+	if _, err := f.SlurpCodelab(context.Background(), filepath.Join(dir, "course.json"), out); !errors.Is(err, errDraft) {
+		t.Errorf("SlurpCodelab(course.json) = %v; want %v", err, errDraft)
+	}
+	if _, err := os.Stat(filepath.Join(out, "course")); !os.IsNotExist(err) {
+		t.Errorf("assets of a rejected composed codelab were stored: %v", err)
+	}
+	if _, err := f.SlurpCodelab(context.Background(), filepath.Join(dir, "final.json"), out); err != nil {
+		t.Errorf("SlurpCodelab(final.json) = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(out, "final", util.ImgDirname)); err != nil {
+		t.Errorf("assets of final.json were not stored: %v", err)
+	}
+}
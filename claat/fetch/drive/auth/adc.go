@@ -0,0 +1,195 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+)
+
+const (
+	// credentialsEnv is the environment variable pointing to
+	// a service account or user credentials JSON file.
+	credentialsEnv = "GOOGLE_APPLICATION_CREDENTIALS"
+	// metadataHostEnv overrides the GCE metadata server host.
+	metadataHostEnv = "GCE_METADATA_HOST"
+	// metadataHost is the default GCE metadata server host,
+	// also serving GKE workload identity and Cloud Build credentials.
+	metadataHost = "metadata.google.internal"
+
+	googleTokenURL = "https://oauth2.googleapis.com/token"
+
+	// ScopePubSub is the auth scope to pull and publish Pub/Sub messages.
+	ScopePubSub = "https://www.googleapis.com/auth/pubsub"
+	// ScopeStorageReadWrite is the auth scope to upload Cloud Storage objects.
+	ScopeStorageReadWrite = "https://www.googleapis.com/auth/devstorage.read_write"
+)
+
+// credentialsFile is the JSON format of service account keys
+// and of gcloud application default user credentials.
+type credentialsFile struct {
+	Type string `json:"type"`
+
+	// service_account
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	TokenURL     string `json:"token_uri"`
+
+	// authorized_user
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// adcTokenSource returns a token source of Application Default Credentials,
+// looked up in the following order:
+//
+//  1. A JSON file named by the GOOGLE_APPLICATION_CREDENTIALS environment variable.
+//  2. The gcloud well-known file, written by "gcloud auth application-default login".
+//  3. The metadata server of GCE, GKE (workload identity), Cloud Run and Cloud Build.
+func adcTokenSource(ctx context.Context, rt http.RoundTripper, scopes []string) (oauth2.TokenSource, error) {
+	if name := os.Getenv(credentialsEnv); name != "" {
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", credentialsEnv, err)
+		}
+		return credentialsTokenSource(ctx, b, scopes)
+	}
+	if b, err := ioutil.ReadFile(wellKnownFile()); err == nil {
+		return credentialsTokenSource(ctx, b, scopes)
+	}
+	host := os.Getenv(metadataHostEnv)
+	if host == "" {
+		host = metadataHost
+	}
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	ts := &metadataTokenSource{
+		client: &http.Client{Transport: rt, Timeout: 10 * time.Second},
+		host:   host,
+		scopes: scopes,
+	}
+	return oauth2.ReuseTokenSource(nil, ts), nil
+}
+
+// NewADCClient returns an HTTP client authorized for scopes with
+// Application Default Credentials.
+// If rt is nil, http.DefaultTransport is used.
+func NewADCClient(rt http.RoundTripper, scopes ...string) (*http.Client, error) {
+	ts, err := adcTokenSource(context.Background(), rt, scopes)
+	if err != nil {
+		return nil, err
+	}
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &http.Client{Transport: &oauth2.Transport{Source: ts, Base: rt}}, nil
+}
+
+// credentialsTokenSource creates a token source from a JSON credentials file content b.
+func credentialsTokenSource(ctx context.Context, b []byte, scopes []string) (oauth2.TokenSource, error) {
+	var f credentialsFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("invalid credentials file: %v", err)
+	}
+	switch f.Type {
+	case "service_account":
+		conf := &jwt.Config{
+			Email:        f.ClientEmail,
+			PrivateKey:   []byte(f.PrivateKey),
+			PrivateKeyID: f.PrivateKeyID,
+			Scopes:       scopes,
+			TokenURL:     f.TokenURL,
+		}
+		if conf.TokenURL == "" {
+			conf.TokenURL = googleTokenURL
+		}
+		return conf.TokenSource(ctx), nil
+	case "authorized_user":
+		conf := &oauth2.Config{
+			ClientID:     f.ClientID,
+			ClientSecret: f.ClientSecret,
+			Scopes:       scopes,
+			Endpoint:     oauth2.Endpoint{TokenURL: googleTokenURL},
+		}
+		return conf.TokenSource(ctx, &oauth2.Token{RefreshToken: f.RefreshToken}), nil
+	}
+	return nil, fmt.Errorf("unsupported credentials type %q", f.Type)
+}
+
+// wellKnownFile returns the path of gcloud application default credentials.
+func wellKnownFile() string {
+	if d := os.Getenv("APPDATA"); d != "" {
+		return filepath.Join(d, "gcloud", "application_default_credentials.json")
+	}
+	return filepath.Join(homedir(), ".config", "gcloud", "application_default_credentials.json")
+}
+
+// metadataTokenSource retrieves access tokens of the default service account
+// from the metadata server.
+type metadataTokenSource struct {
+	client *http.Client
+	host   string
+	scopes []string
+}
+
+func (m *metadataTokenSource) Token() (*oauth2.Token, error) {
+	u := fmt.Sprintf("http://%s/computeMetadata/v1/instance/service-accounts/default/token", m.host)
+	if len(m.scopes) > 0 {
+		u += "?" + url.Values{"scopes": {strings.Join(m.scopes, ",")}}.Encode()
+	}
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	res, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("metadata server: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("metadata server: %s", res.Status)
+	}
+	var v struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&v); err != nil {
+		return nil, fmt.Errorf("metadata server: %v", err)
+	}
+	if v.AccessToken == "" {
+		return nil, fmt.Errorf("metadata server: empty access token")
+	}
+	return &oauth2.Token{
+		AccessToken: v.AccessToken,
+		TokenType:   v.TokenType,
+		Expiry:      time.Now().Add(time.Duration(v.ExpiresIn) * time.Second),
+	}, nil
+}
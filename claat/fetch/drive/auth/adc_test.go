@@ -0,0 +1,91 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestCredentialsTokenSource(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		ok   bool
+	}{
+		{"ServiceAccount", `{"type": "service_account", "client_email": "sa@example.iam.gserviceaccount.com", "private_key": "key"}`, true},
+		{"AuthorizedUser", `{"type": "authorized_user", "client_id": "id", "client_secret": "secret", "refresh_token": "rt"}`, true},
+		{"Unsupported", `{"type": "external_account"}`, false},
+		{"Invalid", `{`, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts, err := credentialsTokenSource(context.Background(), []byte(tc.json), []string{scopeDriveReadOnly})
+			if ok := err == nil && ts != nil; ok != tc.ok {
+				t.Errorf("credentialsTokenSource(%s) = %v, %v; want ok = %v", tc.json, ts, err, tc.ok)
+			}
+		})
+	}
+}
+
+func TestADCTokenSourceEnv(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "creds.json")
+	if err := os.WriteFile(name, []byte(`{"type": "unknown"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(credentialsEnv, name)
+	if _, err := adcTokenSource(context.Background(), nil, nil); err == nil {
+		t.Errorf("adcTokenSource() with unsupported %s file returned nil error", credentialsEnv)
+	}
+}
+
+func TestADCTokenSourceMetadata(t *testing.T) {
+	var scopes string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "missing Metadata-Flavor header", http.StatusForbidden)
+			return
+		}
+		scopes = r.URL.Query().Get("scopes")
+		w.Write([]byte(`{"access_token": "tok", "expires_in": 3600, "token_type": "Bearer"}`))
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	t.Setenv(credentialsEnv, "")
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("APPDATA", "")
+	t.Setenv(metadataHostEnv, u.Host)
+	ts, err := adcTokenSource(context.Background(), nil, []string{scopeDriveReadOnly, scopeDocsReadOnly})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tok, err := ts.Token()
+	if err != nil {
+		t.Fatalf("Token() = %v", err)
+	}
+	if tok.AccessToken != "tok" || !tok.Valid() {
+		t.Errorf("Token() = %+v, want valid access token %q", tok, "tok")
+	}
+	if want := scopeDriveReadOnly + "," + scopeDocsReadOnly; scopes != want {
+		t.Errorf("metadata server got scopes %q, want %q", scopes, want)
+	}
+}
@@ -17,7 +17,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net"
 	"net/http"
 	"os"
@@ -25,11 +24,14 @@ import (
 
 	"golang.org/x/net/context"
 	"golang.org/x/oauth2"
+
+	"github.com/googlecodelabs/tools/claat/logging"
 )
 
 const (
 	// auth scopes needed by the program
 	scopeDriveReadOnly = "https://www.googleapis.com/auth/drive.readonly"
+	scopeDocsReadOnly  = "https://www.googleapis.com/auth/documents.readonly"
 
 	// program credentials for installed apps
 	googClient = "183908478743-e8rth9fbo7juk9eeivgp23asnt791g63.apps.googleusercontent.com"
@@ -37,13 +39,16 @@ const (
 
 	// token providers
 	ProviderGoogle = "goog"
+	// ProviderADC uses Google Application Default Credentials,
+	// suitable for non-interactive environments such as CI.
+	ProviderADC = "adc"
 )
 
 var (
 	googleAuthConfig = oauth2.Config{
 		ClientID:     googClient,
 		ClientSecret: googSecret,
-		Scopes:       []string{scopeDriveReadOnly},
+		Scopes:       []string{scopeDriveReadOnly, scopeDocsReadOnly},
 		RedirectURL:  "http://localhost:8091",
 		Endpoint: oauth2.Endpoint{
 			AuthURL:  "https://accounts.google.com/o/oauth2/auth",
@@ -111,7 +116,7 @@ func (h *Helper) DriveClient() *http.Client {
 }
 
 func (h *Helper) produceDriveClient(rt http.RoundTripper) (*http.Client, error) {
-	ts, err := h.tokenSource()
+	ts, err := h.tokenSource(rt)
 	if err != nil {
 		return nil, err
 	}
@@ -130,15 +135,19 @@ func (h *Helper) produceDriveClient(rt http.RoundTripper) (*http.Client, error)
 
 // tokenSource creates a new oauth2.TokenSource backed by tokenRefresher,
 // using previously stored user credentials if available.
-// If authToken is not given at Helper init, we use the Google provider.
+// If authToken is not given at Helper init, we use the Google provider,
+// or Application Default Credentials with ProviderADC.
 // Otherwise, we use the auth config for the given provider.
-func (h *Helper) tokenSource() (oauth2.TokenSource, error) {
+func (h *Helper) tokenSource(rt http.RoundTripper) (oauth2.TokenSource, error) {
 	// Create a static token source if we have an auth token.
 	if h.authToken != "" {
 		return oauth2.StaticTokenSource(&oauth2.Token{
 			AccessToken: h.authToken,
 		}), nil
 	}
+	if h.provider == ProviderADC {
+		return adcTokenSource(context.Background(), rt, googleAuthConfig.Scopes)
+	}
 
 	// Otherwise, use the Google provider.
 	t, err := readToken(h.provider)
@@ -192,7 +201,7 @@ func writeToken(provider string, tok *oauth2.Token) error {
 func tokenLocation(provider string) (string, error) {
 	d := homedir()
 	if d == "" {
-		log.Printf("WARNING: unable to identify user home dir")
+		logging.Warnf("unable to identify user home dir")
 	}
 	d = path.Join(d, ".config", "claat")
 	if err := os.MkdirAll(d, 0700); err != nil {
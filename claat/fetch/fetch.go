@@ -14,14 +14,14 @@
 package fetch
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"hash/crc64"
 	"io"
 	"io/ioutil"
-	"math"
-	"math/rand"
 	"net/http"
 	"net/url"
 	"os"
@@ -41,11 +41,16 @@ const (
 	// TODO: define these in claat/parser/..., e.g. in parser/gdoc
 	// alternate TODO: make this an iota-based enum?
 	SrcInvalid   srcType = ""
-	SrcGoogleDoc srcType = "gdoc" // Google Docs doc
-	SrcMarkdown  srcType = "md"   // Markdown text
+	SrcGoogleDoc srcType = "gdoc"    // Google Docs doc
+	SrcDocsAPI   srcType = "gdocapi" // Google Docs doc, as Docs API JSON
+	SrcMarkdown  srcType = "md"      // Markdown text
+	SrcHTML      srcType = "html"    // Previously exported codelab HTML
+	SrcNotion    srcType = "notion"  // Notion page, as assembled by fetchNotion
 
 	// driveAPI is a base URL for Drive API
 	driveAPI = "https://www.googleapis.com/drive/v3"
+	// docsAPI is a base URL for Docs API
+	docsAPI = "https://docs.googleapis.com/v1"
 
 	// Minimum image size in bytes for extension detection.
 	minImageSize = 11
@@ -56,24 +61,34 @@ const (
 // srcType is codelab source type
 type srcType string
 
-// resource is a codelab resource, loaded from local file
+// Resource is a codelab resource, loaded from local file
 // or fetched from remote location.
-type resource struct {
-	typ  srcType       // source type
-	body io.ReadCloser // resource body
-	mod  time.Time     // last update of content
+type Resource struct {
+	Type srcType       // source type
+	Body io.ReadCloser // resource body
+	Mod  time.Time     // last update of content
+	Rev  string        // revision of content, if known
 }
 
 // codelab wraps types.Codelab, while adding source type
 // and modified timestamp fields.
 type codelab struct {
 	*types.Codelab
-	Typ  srcType           //  source type
-	Mod  time.Time         // last modified timestamp
-	Imgs map[string]string // Slurped local image paths
+	Typ   srcType           //  source type
+	Mod   time.Time         // last modified timestamp
+	Imgs  map[string]string // Slurped local image paths
+	Files map[string]string // Slurped attachment file names
 }
 
 type MemoryFetcher struct {
+	// IframeDomains are domains allowed in iframes in addition
+	// to nodes.IframeAllowlist.
+	IframeDomains []string
+	// MaxSize, if positive, fails sources larger than MaxSize bytes.
+	MaxSize int64
+	// StrictMeta fails parsing of codelabs with invalid metadata.
+	StrictMeta bool
+
 	passMetadata map[string]bool
 }
 
@@ -83,26 +98,30 @@ func NewMemoryFetcher(pm map[string]bool) *MemoryFetcher {
 	}
 }
 
-func (m *MemoryFetcher) SlurpCodelab(rc io.ReadCloser) (*codelab, error) {
-	r := &resource{
-		body: rc,
-		typ:  SrcMarkdown,
-		mod:  time.Now(),
+// SlurpCodelab parses the Markdown codelab of rc,
+// stopping with the error of ctx once done.
+func (m *MemoryFetcher) SlurpCodelab(ctx context.Context, rc io.ReadCloser) (*codelab, error) {
+	r := &Resource{
+		Body: limitSize(rc, "source", m.MaxSize),
+		Type: SrcMarkdown,
+		Mod:  time.Now(),
 	}
-	defer r.body.Close()
+	defer r.Body.Close()
 
 	opts := *parser.NewOptions()
+	opts.IframeDomains = m.IframeDomains
 	opts.PassMetadata = m.passMetadata
+	opts.StrictMeta = m.StrictMeta
 
-	clab, err := parser.Parse(string(r.typ), r.body, opts)
+	clab, err := parser.Parse(ctx, string(r.Type), r.Body, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	return &codelab{
 		Codelab: clab,
-		Typ:     r.typ,
-		Mod:     r.mod,
+		Typ:     r.Type,
+		Mod:     r.Mod,
 	}, nil
 }
 
@@ -110,107 +129,306 @@ type Fetcher struct {
 	authHelper   *auth.Helper
 	authToken    string
 	crcTable     *crc64.Table
+	inputs       *inputs         // revisions of the sources read, if recorded
+	opts         FetcherOptions
 	passMetadata map[string]bool
 	roundTripper http.RoundTripper
 }
 
+// FetcherOptions configures how a Fetcher retrieves remote sources.
+type FetcherOptions struct {
+	// Accept, if not nil, is called by SlurpCodelab with the metadata
+	// of the codelab once parsed. Its error stops the fetch before any
+	// asset is stored. Translations are not checked.
+	Accept func(*types.Meta) error
+	// ADC authorizes with Google Application Default Credentials
+	// instead of the interactive user authorization flow.
+	ADC bool
+	// DocsAPI retrieves Google Docs with the Docs API
+	// instead of exporting them as HTML with the Drive API.
+	DocsAPI bool
+	// Fixtures is the directory of the responses recorded with Record,
+	// and served with Offline. Empty means DefaultFixtures.
+	Fixtures string
+	// GitCache is the directory of the checkouts of git sources.
+	// Empty means claat-git in the temporary directory.
+	// Checkouts of commits unused for a day are removed.
+	GitCache string
+	// ImportCache is a directory where imported remote fragments are cached.
+	// Caching is disabled if empty.
+	ImportCache string
+	// ImportCacheTTL is how long a cached import is reused.
+	// Zero means DefaultImportCacheTTL.
+	ImportCacheTTL time.Duration
+	// IframeDomains are domains allowed in iframes in addition
+	// to nodes.IframeAllowlist.
+	IframeDomains []string
+	// MaxNodes, if positive, fails codelabs of more than MaxNodes content
+	// nodes before their assets are fetched.
+	MaxNodes int
+	// MaxSize, if positive, fails codelab sources, images and attachments
+	// larger than MaxSize bytes, such as Google Docs too big for a shared
+	// exporter.
+	MaxSize int64
+	// NoExternal fails codelabs fetching other files or URLs, with imports,
+	// code includes, attachments, a manifest, or images of other hosts
+	// than the codelab and Google Docs. It hardens exports of untrusted
+	// sources, which could otherwise read local files or internal URLs.
+	NoExternal bool
+	// Offline serves all remote fetches with the responses recorded
+	// in Fixtures, without network access nor Google authorization.
+	Offline bool
+	// Progress, if not nil, is called with each phase of a codelab
+	// as it is fetched: PhaseFetch, PhaseParse and PhaseAssets.
+	Progress func(phase string)
+	// Record records the responses of remote fetches in Fixtures,
+	// so that they can be fetched Offline later on.
+	Record bool
+	// Retry is the policy of retrying failed remote fetches.
+	Retry RetryPolicy
+	// StrictMeta fails parsing of codelabs with invalid metadata.
+	// See types.Meta.Validate.
+	StrictMeta bool
+}
+
+// Phases of fetching a codelab, reported to FetcherOptions.Progress.
+const (
+	PhaseFetch  = "fetching"
+	PhaseParse  = "parsing"
+	PhaseAssets = "assets"
+)
+
 // NewFetcher creates an instance of Fetcher.
-func NewFetcher(at string, pm map[string]bool, rt http.RoundTripper) (*Fetcher, error) {
+// With opts.Offline or opts.Record, rt is replaced or wrapped
+// to serve or record the responses of opts.Fixtures.
+func NewFetcher(at string, pm map[string]bool, rt http.RoundTripper, opts FetcherOptions) (*Fetcher, error) {
+	fixtures := opts.Fixtures
+	if fixtures == "" {
+		fixtures = DefaultFixtures
+	}
+	switch {
+	case opts.Offline && opts.Record:
+		return nil, errors.New("cannot record responses offline")
+	case opts.Offline:
+		rt = &replayer{dir: fixtures}
+	case opts.Record:
+		rt = &recorder{dir: fixtures, base: rt}
+	}
 	return &Fetcher{
 		authHelper:   nil,
 		authToken:    at,
 		crcTable:     crc64.MakeTable(crc64.ECMA),
+		opts:         opts,
 		passMetadata: pm,
 		roundTripper: rt,
 	}, nil
 }
 
+// progress reports phase to f.opts.Progress, if any.
+func (f *Fetcher) progress(phase string) {
+	if f.opts.Progress != nil {
+		f.opts.Progress(phase)
+	}
+}
+
+// initAuth sets up f.authHelper, unless it has already been done
+// or f is offline, in which case recorded responses need no authorization.
+func (f *Fetcher) initAuth() error {
+	if f.authHelper != nil || f.opts.Offline {
+		return nil
+	}
+	provider := auth.ProviderGoogle
+	if f.opts.ADC {
+		provider = auth.ProviderADC
+	}
+	var err error
+	f.authHelper, err = auth.NewHelper(f.authToken, provider, f.roundTripper)
+	return err
+}
+
 // SlurpCodelab retrieves and parses codelab source.
 // It takes the source, plus an auth token and a set of extra metadata to pass along.
 // It returns parsed codelab and its source type.
 //
 // The function will also fetch and parse fragments included
-// with nodes.ImportNode.
-func (f *Fetcher) SlurpCodelab(src string, output string) (*codelab, error) {
-	_, err := os.Stat(src)
-	// Only setup oauth if this source is not a local file.
-	if os.IsNotExist(err) {
-		if f.authHelper == nil {
-			f.authHelper, err = auth.NewHelper(f.authToken, auth.ProviderGoogle, f.roundTripper)
-			if err != nil {
-				return nil, err
-			}
+// with nodes.ImportNode, recursively. Fetches are canceled,
+// and parsing stops, once ctx is done.
+func (f *Fetcher) SlurpCodelab(ctx context.Context, src string, output string) (*codelab, error) {
+	return f.recordInputs(src, func(f *Fetcher) (*codelab, error) {
+		return f.slurpCodelab(ctx, src, output, f.accept)
+	})
+}
+
+// accept calls the Accept option of f, if any, with m.
+func (f *Fetcher) accept(m *types.Meta) error {
+	if f.opts.Accept == nil {
+		return nil
+	}
+	return f.opts.Accept(m)
+}
+
+// SlurpTranslation is like SlurpCodelab for src, the translation of codelab
+// orig to locale. The translation gets the ID and the translations of orig,
+// so that its output directory is next to the other translations.
+// If orig is nil, it is the same as SlurpCodelab.
+func (f *Fetcher) SlurpTranslation(ctx context.Context, src, output string, orig *types.Meta, locale string) (*codelab, error) {
+	if orig == nil {
+		return f.recordInputs(src, func(f *Fetcher) (*codelab, error) {
+			return f.slurpCodelab(ctx, src, output, nil)
+		})
+	}
+	return f.recordInputs(src, func(f *Fetcher) (*codelab, error) {
+		return f.slurpCodelab(ctx, src, output, func(m *types.Meta) error {
+			m.ID = orig.ID
+			m.URL = orig.URL
+			m.Locale = locale
+			m.Translations = orig.Translations
+			return nil
+		})
+	})
+}
+
+// slurpCodelab implements SlurpCodelab. If not nil, adjust is called
+// with the parsed metadata, before assets are stored, and its error
+// stops the fetch.
+func (f *Fetcher) slurpCodelab(ctx context.Context, src, output string, adjust func(*types.Meta) error) (*codelab, error) {
+	if IsManifest(src) {
+		if f.opts.NoExternal {
+			return nil, fmt.Errorf("%s: manifests are not allowed", src)
+		}
+		return f.slurpManifest(ctx, src, output, adjust)
+	}
+	p, err := f.parseCodelab(ctx, src, adjust)
+	if err != nil {
+		return nil, err
+	}
+	return p.slurpAssets(ctx, output)
+}
+
+// parsedCodelab is a codelab parsed by parseCodelab,
+// whose assets are not fetched yet.
+type parsedCodelab struct {
+	f    *Fetcher // fetcher of the assets
+	src  string   // source, or its local copy
+	clab *types.Codelab
+	typ  srcType
+	mod  time.Time
+}
+
+// parseCodelab fetches and parses codelab src, the first phase
+// of slurpCodelab. If not nil, adjust is called with the parsed metadata,
+// and its error stops the fetch.
+func (f *Fetcher) parseCodelab(ctx context.Context, src string, adjust func(*types.Meta) error) (*parsedCodelab, error) {
+	// Only setup oauth if this source is fetched with Google credentials.
+	if googleSource(src) {
+		if err := f.initAuth(); err != nil {
+			return nil, err
+		}
+	}
+	f.progress(PhaseFetch)
+	var rev string
+	if ls, ok := source(src).(localSource); ok {
+		// fetch a local copy, to resolve relative references against
+		orig := src
+		var err error
+		if src, rev, err = ls.Local(ctx, f, src); err != nil {
+			return nil, err
 		}
+		// the revision of src covers the other files of its local copy
+		f.inputs.add(orig, rev)
+		f2 := *f
+		f2.inputs = nil
+		f = &f2
 	}
-	res, err := f.fetch(src)
+	res, err := f.fetch(ctx, src)
 	if err != nil {
 		return nil, err
 	}
-	defer res.body.Close()
+	defer res.Body.Close()
+	res.Body = limitSize(res.Body, "source", f.opts.MaxSize)
 
+	f.progress(PhaseParse)
 	opts := *parser.NewOptions()
+	opts.IframeDomains = f.opts.IframeDomains
 	opts.PassMetadata = f.passMetadata
+	opts.StrictMeta = f.opts.StrictMeta
 
-	clab, err := parser.Parse(string(res.typ), res.body, opts)
+	clab, err := parser.Parse(ctx, string(res.Type), res.Body, opts)
 	if err != nil {
 		return nil, err
 	}
+	if rev == "" {
+		rev = res.Rev
+	}
+	clab.Revision = rev
+	if adjust != nil {
+		if err := adjust(&clab.Meta); err != nil {
+			return nil, err
+		}
+	}
+	return &parsedCodelab{f: f, src: src, clab: clab, typ: res.Type, mod: res.Mod}, nil
+}
+
+// slurpAssets fetches the assets of p to output,
+// the second phase of slurpCodelab.
+func (p *parsedCodelab) slurpAssets(ctx context.Context, output string) (*codelab, error) {
+	f, src, clab := p.f, p.src, p.clab
+	f.progress(PhaseAssets)
 	images := make(map[string]string)
 	dir := codelabDir(output, &clab.Meta)
 	imgDir := filepath.Join(dir, util.ImgDirname)
-	if !isStdout(output) {
-		// download or copy codelab assets to disk, and rewrite image URLs
-		var nodes []nodes.Node
-		for _, step := range clab.Steps {
-			nodes = append(nodes, step.Content.Nodes...)
+	var nn []nodes.Node
+	for _, step := range clab.Steps {
+		nn = append(nn, step.Content.Nodes...)
+	}
+	// before any asset is fetched
+	if err := CheckNodes(ctx, nn, f.opts.MaxNodes); err != nil {
+		return nil, err
+	}
+	if f.opts.NoExternal {
+		if err := checkExternal(src, nn); err != nil {
+			return nil, err
 		}
-		err := f.SlurpImages(src, imgDir, nodes, images)
+	}
+	if isStdout(output) {
+		imgDir = ""
+	} else {
+		// download or copy codelab assets to disk, and rewrite image URLs
+		err := f.SlurpImages(ctx, src, imgDir, nn, images)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	// fetch imports and parse them as fragments
-	var imports []*nodes.ImportNode
-	for _, st := range clab.Steps {
-		imports = append(imports, nodes.ImportNodes(st.Content.Nodes)...)
+	// fetch imports and parse them as fragments, recursively
+	if err := f.slurpImports(ctx, src, nn, []string{src}, imgDir, images); err != nil {
+		return nil, err
 	}
-	ch := make(chan error, len(imports))
-	defer close(ch)
-	for _, imp := range imports {
-		go func(n *nodes.ImportNode) {
-			frag, err := f.slurpFragment(n.URL)
-			if err != nil {
-				ch <- fmt.Errorf("%s: %v", n.URL, err)
-				return
-			}
-			if !isStdout(output) {
-				// download or copy codelab assets to disk, and rewrite image URLs
-				err = f.SlurpImages(gdocID(n.URL), imgDir, frag, images)
-				if err != nil {
-					return
-				}
-			}
-			n.Content.Nodes = frag
-			ch <- nil
-		}(imp)
+
+	// fill in code included from files, including that of imports
+	if err := f.SlurpCodeIncludes(ctx, src, nn); err != nil {
+		return nil, err
 	}
-	for range imports {
-		if err := <-ch; err != nil {
+
+	files := make(map[string]string)
+	if !isStdout(output) {
+		// download or copy attachments, including those of imports, to disk
+		if err := f.SlurpAttachments(ctx, src, filepath.Join(dir, util.FilesDirname), nn, files); err != nil {
 			return nil, err
 		}
 	}
 
 	v := &codelab{
 		Codelab: clab,
-		Typ:     res.typ,
-		Mod:     res.mod,
+		Typ:     p.typ,
+		Mod:     p.mod,
 		Imgs:    images,
+		Files:   files,
 	}
 	return v, nil
 }
 
-func (f *Fetcher) SlurpImages(src, dir string, n []nodes.Node, images map[string]string) error {
+func (f *Fetcher) SlurpImages(ctx context.Context, src, dir string, n []nodes.Node, images map[string]string) error {
 	// make sure img dir exists
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
@@ -227,14 +445,29 @@ func (f *Fetcher) SlurpImages(src, dir string, n []nodes.Node, images map[string
 	imageNodes := nodes.ImageNodes(n)
 	count += len(imageNodes)
 	for _, imageNode := range imageNodes {
+		if imageNode.Dark == "" && len(imageNode.Bytes) == 0 {
+			imageNode.Dark = darkVariant(src, imageNode.Src)
+		}
 		go func(imageNode *nodes.ImageNode) {
 			url := imageNode.Src
-			file, err := f.slurpBytes(src, dir, url, imageNode.Bytes)
+			file, err := f.slurpBytes(ctx, src, dir, url, imageNode.Bytes)
 			if err == nil {
 				imageNode.Src = filepath.Join(util.ImgDirname, file)
 			}
 			ch <- &res{url, file, err}
 		}(imageNode)
+		if imageNode.Dark == "" {
+			continue
+		}
+		count++
+		go func(imageNode *nodes.ImageNode) {
+			url := imageNode.Dark
+			file, err := f.slurpBytes(ctx, src, dir, url, nil)
+			if err == nil {
+				imageNode.Dark = filepath.Join(util.ImgDirname, file)
+			}
+			ch <- &res{url, file, err}
+		}(imageNode)
 	}
 	var errStr string
 	for i := 0; i < count; i++ {
@@ -251,7 +484,29 @@ func (f *Fetcher) SlurpImages(src, dir string, n []nodes.Node, images map[string
 	return nil
 }
 
-func (f *Fetcher) slurpBytes(codelabSrc, dir, imgURL string, imgBytes []byte) (string, error) {
+// darkVariant returns the variant for dark color schemes of local image
+// imgURL of a local codelabSrc, named after it with a .dark suffix such as
+// console.dark.png for console.png, if it exists.
+func darkVariant(codelabSrc, imgURL string) string {
+	if u, err := url.Parse(imgURL); err != nil || u.Scheme != "" || u.Host != "" {
+		return ""
+	}
+	if u, err := url.Parse(codelabSrc); err == nil && u.Host != "" {
+		return ""
+	}
+	ext := filepath.Ext(imgURL)
+	dark := strings.TrimSuffix(imgURL, ext) + ".dark" + ext
+	p, err := restrictPathToParent(dark, filepath.Dir(codelabSrc))
+	if err != nil {
+		return ""
+	}
+	if fi, err := os.Stat(p); err != nil || fi.IsDir() {
+		return ""
+	}
+	return dark
+}
+
+func (f *Fetcher) slurpBytes(ctx context.Context, codelabSrc, dir, imgURL string, imgBytes []byte) (string, error) {
 	// images can be data URLs, local in Markdown cases or remote.
 	// Only proceed a simple copy on local reference.
 	var b []byte
@@ -282,12 +537,12 @@ func (f *Fetcher) slurpBytes(codelabSrc, dir, imgURL string, imgBytes []byte) (s
 			if imgURL, err = restrictPathToParent(imgURL, filepath.Dir(codelabSrc)); err != nil {
 				return "", err
 			}
-			if b, err = ioutil.ReadFile(imgURL); err != nil {
+			if b, err = f.readFile(imgURL); err != nil {
 				return "", err
 			}
 			ext = filepath.Ext(imgURL)
 		} else {
-			if b, err = f.slurpRemoteBytes(u.String(), 5); err != nil {
+			if b, err = f.slurpRemoteBytes(ctx, u.String(), 5); err != nil {
 				return "", fmt.Errorf("Error downloading image at %s: %v", u.String(), err)
 			}
 			if ext, err = imgExtFromBytes(b); err != nil {
@@ -303,61 +558,36 @@ func (f *Fetcher) slurpBytes(codelabSrc, dir, imgURL string, imgBytes []byte) (s
 	return file, ioutil.WriteFile(dst, b, 0644)
 }
 
-func (f *Fetcher) slurpFragment(url string) ([]nodes.Node, error) {
-	res, err := f.fetch(url)
+func (f *Fetcher) slurpFragment(ctx context.Context, url string) ([]nodes.Node, error) {
+	res, err := f.fetchImport(ctx, url)
 	if err != nil {
 		return nil, err
 	}
-	defer res.body.Close()
+	defer res.Body.Close()
 
 	opts := *parser.NewOptions()
+	opts.IframeDomains = f.opts.IframeDomains
 	opts.PassMetadata = f.passMetadata
 
-	return parser.ParseFragment(string(res.typ), res.body, opts)
-}
-
-// fetch retrieves codelab doc either from local disk
-// or a remote location.
-// The caller is responsible for closing returned stream.
-func (f *Fetcher) fetch(name string) (*resource, error) {
-	fi, err := os.Stat(name)
-	if os.IsNotExist(err) {
-		return f.fetchRemote(name, false)
-	}
-	r, err := os.Open(name)
-	if err != nil {
-		return nil, err
-	}
-	return &resource{
-		body: r,
-		typ:  SrcMarkdown,
-		mod:  fi.ModTime(),
-	}, nil
+	return parser.ParseFragment(ctx, string(res.Type), res.Body, opts)
 }
 
-// fetchRemote retrieves resource r from the network.
-//
-// If urlStr is not a URL, i.e. does not have the host part, it is considered to be
-// a Google Doc ID and fetched accordingly. Otherwise, a simple GET request
-// is used to retrieve the contents.
-//
-// The caller is responsible for closing returned stream.
-// If nometa is true, resource.mod may have zero value.
-func (f *Fetcher) fetchRemote(urlStr string, nometa bool) (*resource, error) {
-	u, err := url.Parse(urlStr)
-	if err != nil {
-		return nil, err
+// fileSrcType returns the source type of a local or remote file
+// based on its name extension. It defaults to SrcMarkdown.
+func fileSrcType(name string) srcType {
+	if u, err := url.Parse(name); err == nil && u.Host != "" {
+		name = u.Path
 	}
-	if u.Host == "" || u.Host == "docs.google.com" {
-		return f.fetchDriveFile(urlStr, nometa)
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".html", ".htm":
+		return SrcHTML
 	}
-	return f.fetchRemoteFile(urlStr)
+	return SrcMarkdown
 }
 
 // fetchRemoteFile retrieves codelab resource from url.
-// It is a special case of fetchRemote function.
-func (f *Fetcher) fetchRemoteFile(url string) (*resource, error) {
-	res, err := retryGet(f.authHelper.DriveClient(), url, 3)
+func (f *Fetcher) fetchRemoteFile(ctx context.Context, url string) (*Resource, error) {
+	res, err := f.retryGet(ctx, f.client(), url, 3)
 	if err != nil {
 		return nil, err
 	}
@@ -365,10 +595,26 @@ func (f *Fetcher) fetchRemoteFile(url string) (*resource, error) {
 	if err != nil {
 		t = time.Now()
 	}
-	return &resource{
-		body: res.Body,
-		mod:  t,
-		typ:  SrcMarkdown,
+	etag := res.Header.Get("ETag")
+	if etag != "" {
+		return &Resource{
+			Body: res.Body,
+			Mod:  t,
+			Rev:  etag,
+			Type: fileSrcType(url),
+		}, nil
+	}
+	// the revision is the hash of the content, as httpSource.Revision returns it
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(limitSize(res.Body, url, f.opts.MaxSize))
+	if err != nil {
+		return nil, err
+	}
+	return &Resource{
+		Body: ioutil.NopCloser(bytes.NewReader(b)),
+		Mod:  t,
+		Rev:  contentRevision("", b),
+		Type: fileSrcType(url),
 	}, nil
 }
 
@@ -376,111 +622,145 @@ func (f *Fetcher) fetchRemoteFile(url string) (*resource, error) {
 // See https://developers.google.com/drive/web/manage-downloads#downloading_google_documents
 // for more details.
 //
-// If nometa is true, resource.mod will have zero value.
-func (f *Fetcher) fetchDriveFile(id string, nometa bool) (*resource, error) {
+// If nometa is true, Resource.Mod will have zero value.
+//
+// If the fetcher was created with DocsAPI option, the structured document
+// is retrieved from the Docs API instead.
+func (f *Fetcher) fetchDriveFile(ctx context.Context, id string, nometa bool) (*Resource, error) {
 	id = gdocID(id)
 	exportURL := gdocExportURL(id)
+	typ := SrcGoogleDoc
+	if f.opts.DocsAPI {
+		exportURL = fmt.Sprintf("%s/documents/%s", docsAPI, id)
+		typ = SrcDocsAPI
+	}
 
 	if nometa {
-		res, err := retryGet(f.authHelper.DriveClient(), exportURL, 7)
+		res, err := f.retryGet(ctx, f.client(), exportURL, 7)
 		if err != nil {
 			return nil, err
 		}
-		return &resource{body: res.Body, typ: SrcGoogleDoc}, nil
+		return &Resource{Body: res.Body, Type: typ}, nil
+	}
+
+	meta, err := f.driveDocMeta(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	res, err := f.retryGet(ctx, f.client(), exportURL, 7)
+	if err != nil {
+		return nil, err
 	}
+	return &Resource{
+		Body: res.Body,
+		Mod:  meta.Modified,
+		Rev:  revisionTime(meta.Modified),
+		Type: typ,
+	}, nil
+}
+
+// driveMeta is the Drive metadata of a Google Doc.
+type driveMeta struct {
+	ID       string    `json:"id"`
+	MimeType string    `json:"mimeType"`
+	Modified time.Time `json:"modifiedTime"`
+}
 
+// driveDocMeta retrieves the Drive metadata of Google Doc id.
+func (f *Fetcher) driveDocMeta(ctx context.Context, id string) (*driveMeta, error) {
 	q := url.Values{
 		"fields":             {"id,mimeType,modifiedTime"},
 		"supportsTeamDrives": {"true"},
 	}
 	u := fmt.Sprintf("%s/files/%s?%s", driveAPI, id, q.Encode())
-	res, err := retryGet(f.authHelper.DriveClient(), u, 7)
+	res, err := f.retryGet(ctx, f.client(), u, 7)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
-	meta := &struct {
-		ID       string    `json:"id"`
-		MimeType string    `json:"mimeType"`
-		Modified time.Time `json:"modifiedTime"`
-	}{}
+	meta := &driveMeta{}
 	if err := json.NewDecoder(res.Body).Decode(meta); err != nil {
 		return nil, err
 	}
 	if meta.MimeType != "application/vnd.google-apps.document" {
 		return nil, fmt.Errorf("%s: invalid mime type: %s", id, meta.MimeType)
 	}
+	return meta, nil
+}
 
-	if res, err = retryGet(f.authHelper.DriveClient(), exportURL, 7); err != nil {
-		return nil, err
+// revisionTime returns the revision of content last modified at t.
+func revisionTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// client returns the authorized Drive client, or a plain HTTP client
+// if auth has not been set up, e.g. for images of a local Markdown file
+// or when offline.
+func (f *Fetcher) client() *http.Client {
+	if f.authHelper != nil {
+		return f.authHelper.DriveClient()
 	}
-	return &resource{
-		body: res.Body,
-		mod:  meta.Modified,
-		typ:  SrcGoogleDoc,
-	}, nil
+	return &http.Client{Transport: f.roundTripper}
 }
 
-func (f *Fetcher) slurpRemoteBytes(url string, n int) ([]byte, error) {
-	res, err := retryGet(f.authHelper.DriveClient(), url, n)
+func (f *Fetcher) slurpRemoteBytes(ctx context.Context, url string, n int) ([]byte, error) {
+	res, err := f.retryGet(ctx, f.client(), url, n)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
-	return ioutil.ReadAll(res.Body)
+	b, err := ioutil.ReadAll(limitSize(res.Body, url, f.opts.MaxSize))
+	if err != nil {
+		return nil, err
+	}
+	f.inputs.add(url, contentRevision(res.Header.Get("ETag"), b))
+	return b, nil
 }
 
-// retryGet tries to GET specified url up to n times.
-// Attempts are spaced out with exponential backoff.
-// Default client will be used if not provided.
-func retryGet(client *http.Client, url string, n int) (*http.Response, error) {
-	if client == nil {
-		client = http.DefaultClient
+// readFile reads local file name, of at most the MaxSize option of f.
+func (f *Fetcher) readFile(name string) ([]byte, error) {
+	r, err := os.Open(name)
+	if err != nil {
+		return nil, err
 	}
-	for i := 0; i <= n; i++ {
-		if i > 0 {
-			t := time.Duration((math.Pow(2, float64(i)) + rand.Float64()) * float64(time.Second))
-			time.Sleep(t)
-		}
-		res, err := client.Get(url)
-		// return early with a good response
-		// the rest is error handling
-		if err == nil && res.StatusCode == http.StatusOK {
-			return res, nil
-		}
+	defer r.Close()
+	b, err := ioutil.ReadAll(limitSize(r, name, f.opts.MaxSize))
+	if err != nil {
+		return nil, err
+	}
+	if rev, err := fileRevision(name); err == nil {
+		f.inputs.add(name, rev)
+	}
+	return b, nil
+}
 
-		// sometimes Drive API wouldn't even start a response,
-		// we get net/http: TLS handshake timeout instead:
-		// consider this a temporary failure and retry again
-		if err != nil {
-			continue
-		}
-		// otherwise, decode error response and check for "rate limit"
-		defer res.Body.Close()
-		var erres struct {
-			Error struct {
-				Errors []struct{ Reason string }
-			}
-		}
-		b, _ := ioutil.ReadAll(res.Body)
-		json.Unmarshal(b, &erres)
-		var rateLimit bool
-		for _, e := range erres.Error.Errors {
-			if e.Reason == "rateLimitExceeded" || e.Reason == "userRateLimitExceeded" {
-				rateLimit = true
-				break
-			}
-		}
-		// this is neither a rate limit error, nor a server error:
-		// retrying is useless
-		if !rateLimit && res.StatusCode < http.StatusInternalServerError {
-			return nil, fmt.Errorf("fetch %s: %s; %s", url, res.Status, b)
-		}
+// headerTransport sets header fields of all requests,
+// such as API credentials which must not appear in URLs and error messages.
+type headerTransport struct {
+	header http.Header
+	base   http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	for k, v := range t.header {
+		r.Header[k] = v
+	}
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
 	}
-	return nil, fmt.Errorf("%s: failed after %d retries", url, n)
+	return base.RoundTrip(r)
+}
+
+// retryGet tries to GET url with client up to n times, or as set by
+// the retry policy of f, until ctx is done. See RetryPolicy.get.
+func (f *Fetcher) retryGet(ctx context.Context, client *http.Client, url string, n int) (*http.Response, error) {
+	return f.opts.Retry.get(ctx, client, url, n)
 }
 
 func gdocID(url string) string {
+	url = strings.TrimPrefix(url, SchemeGoogleDoc+"://")
 	const s = "/document/d/"
 	if i := strings.Index(url, s); i >= 0 {
 		url = url[i+len(s):]
@@ -511,6 +791,17 @@ func restrictPathToParent(assetPath, parent string) (string, error) {
 	if !strings.HasPrefix(assetPath, parent) {
 		return "", fmt.Errorf("%s isn't a subdirectory of %s", assetPath, parent)
 	}
+	// A symbolic link in parent, such as one of a git repository,
+	// must not lead out of it either.
+	if real, err := filepath.EvalSymlinks(assetPath); err == nil {
+		realParent, err := filepath.EvalSymlinks(parent)
+		if err != nil {
+			return "", err
+		}
+		if real != realParent && !strings.HasPrefix(real, realParent+string(filepath.Separator)) {
+			return "", fmt.Errorf("%s links out of %s", assetPath, parent)
+		}
+	}
 	return assetPath, nil
 }
 
@@ -523,7 +814,7 @@ func isStdout(filename string) bool {
 // codelabDir returns codelab root directory.
 // The base argument is codelab parent directory.
 func codelabDir(base string, m *types.Meta) string {
-	return filepath.Join(base, m.ID)
+	return filepath.Join(base, filepath.FromSlash(m.Dir()))
 }
 
 func imgExtFromBytes(b []byte) (string, error) {
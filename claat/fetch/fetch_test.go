@@ -14,13 +14,17 @@
 package fetch
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"testing/quick"
 
+	"github.com/googlecodelabs/tools/claat/nodes"
 	_ "github.com/googlecodelabs/tools/claat/parser/gdoc" // Explicitly register gdoc parser
 )
 
@@ -144,3 +148,57 @@ func safeAbs(t *testing.T, p string) string {
 	}
 	return p
 }
+
+func TestSlurpImagesDark(t *testing.T) {
+	dir, err := ioutil.TempDir("", "claat-dark")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for name, b := range map[string]string{
+		"a.png":       "a",
+		"a.dark.png":  "a dark",
+		"b.png":       "b",
+		"c.png":       "c",
+		"c-night.png": "c night",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(b), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := NewFetcher("", nil, nil, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := nodes.NewImageNode(nodes.NewImageNodeOptions{Src: "a.png"})
+	b := nodes.NewImageNode(nodes.NewImageNodeOptions{Src: "b.png"})
+	c := nodes.NewImageNode(nodes.NewImageNodeOptions{Src: "c.png"})
+	c.Dark = "c-night.png"
+	images := make(map[string]string)
+	src := filepath.Join(dir, "codelab.md")
+	if err := f.SlurpImages(context.Background(), src, filepath.Join(dir, "out"), []nodes.Node{a, b, c}, images); err != nil {
+		t.Fatal(err)
+	}
+	srcs := make(map[string]string)
+	for file, url := range images {
+		srcs[url] = filepath.Join("img", file)
+	}
+	for _, tc := range []struct {
+		img       *nodes.ImageNode
+		src, dark string
+	}{
+		{a, "a.png", "a.dark.png"},
+		{b, "b.png", ""},
+		{c, "c.png", "c-night.png"},
+	} {
+		if tc.img.Src != srcs[tc.src] {
+			t.Errorf("%s: Src = %q; want %q", tc.src, tc.img.Src, srcs[tc.src])
+		}
+		if tc.img.Dark != srcs[tc.dark] {
+			t.Errorf("%s: Dark = %q; want %q", tc.src, tc.img.Dark, srcs[tc.dark])
+		}
+	}
+	if len(images) != 5 {
+		t.Errorf("images = %v; want 5 of them", images)
+	}
+}
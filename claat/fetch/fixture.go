@@ -0,0 +1,135 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// DefaultFixtures is the default directory of recorded responses.
+const DefaultFixtures = "claat-fixtures"
+
+// ErrNotRecorded is the error of an Offline fetch whose response
+// was not recorded. It is not retried.
+var ErrNotRecorded = errors.New("response not recorded")
+
+// fixtureHeaders are the response headers kept in fixtures.
+// Others, such as cookies, are left out.
+var fixtureHeaders = []string{"Content-Type", "Last-Modified"}
+
+// fixture is a recorded response, stored as JSON.
+type fixture struct {
+	URL        string      `json:"url"`
+	Status     string      `json:"status"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body"` // base64 in JSON
+}
+
+// fixtureFile returns the file of the recorded response of GET url in dir.
+func fixtureFile(dir, url string) string {
+	return filepath.Join(dir, fmt.Sprintf("%x.json", sha1.Sum([]byte(url))))
+}
+
+// recorder records the responses of GET requests made with base in dir,
+// unless retrying them may succeed. Other requests, such as OAuth2 token
+// requests, are not recorded.
+type recorder struct {
+	dir  string
+	base http.RoundTripper
+}
+
+func (rt *recorder) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := rt.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	res, err := base.RoundTrip(r)
+	if err != nil || r.Method != http.MethodGet {
+		return res, err
+	}
+	b, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(b))
+	if retryable(res.StatusCode, b) {
+		return res, nil
+	}
+	fx := &fixture{URL: r.URL.String(), Status: res.Status, StatusCode: res.StatusCode, Header: http.Header{}, Body: b}
+	for _, k := range fixtureHeaders {
+		if v := res.Header.Get(k); v != "" {
+			fx.Header.Set(k, v)
+		}
+	}
+	j, err := json.MarshalIndent(fx, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeCacheFile(fixtureFile(rt.dir, fx.URL), j); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// replayer serves GET requests with the responses recorded in dir,
+// without network access. Other requests fail.
+type replayer struct {
+	dir string
+}
+
+func (rt *replayer) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Body != nil {
+		r.Body.Close()
+	}
+	u := r.URL.String()
+	if r.Method != http.MethodGet {
+		return nil, fmt.Errorf("offline: %s %s: %w", r.Method, u, ErrNotRecorded)
+	}
+	b, err := ioutil.ReadFile(fixtureFile(rt.dir, u))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("offline: %s: %w in %s; record it with -record", u, ErrNotRecorded, rt.dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var fx fixture
+	if err := json.Unmarshal(b, &fx); err != nil {
+		return nil, fmt.Errorf("offline: %s: %v", fixtureFile(rt.dir, u), err)
+	}
+	if fx.Header == nil {
+		fx.Header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fx.Status,
+		StatusCode:    fx.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        fx.Header,
+		Body:          ioutil.NopCloser(bytes.NewReader(fx.Body)),
+		ContentLength: int64(len(fx.Body)),
+		Request:       r,
+	}, nil
+}
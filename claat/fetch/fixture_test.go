@@ -0,0 +1,117 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fixtureServer returns a transport serving a codelab at /lab.md,
+// and failing with 404 at /missing and 503 at /flaky.
+func fixtureServer() http.RoundTripper {
+	return &testTransport{func(r *http.Request) (*http.Response, error) {
+		var res *http.Response
+		switch r.URL.Path {
+		case "/lab.md":
+			res = testResponse(http.StatusOK, "id: lab\n\n# Lab\n\n## Setup\n\nText\n")
+			res.Header.Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+			res.Header.Set("Set-Cookie", "session=secret")
+		case "/flaky":
+			res = testResponse(http.StatusServiceUnavailable, "")
+		default:
+			res = testResponse(http.StatusNotFound, "")
+		}
+		res.Request = r
+		return res, nil
+	}}
+}
+
+func TestRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	rec := &http.Client{Transport: &recorder{dir: dir, base: fixtureServer()}}
+	for _, p := range []string{"/lab.md", "/missing", "/flaky"} {
+		res, err := rec.Get("https://example.com" + p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 2 {
+		t.Errorf("recorded %d fixtures; want 2, without the 503 response", len(files))
+	}
+
+	rep := &http.Client{Transport: &replayer{dir: dir}}
+	res, err := rep.Get("https://example.com/lab.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK || !strings.HasPrefix(string(b), "id: lab") {
+		t.Errorf("replayed /lab.md = %d %q; want 200 and the recorded body", res.StatusCode, b)
+	}
+	if v := res.Header.Get("Last-Modified"); v == "" {
+		t.Error("replayed /lab.md has no Last-Modified header")
+	}
+	if v := res.Header.Get("Set-Cookie"); v != "" {
+		t.Errorf("replayed /lab.md Set-Cookie = %q; want none recorded", v)
+	}
+	if res, err = rep.Get("https://example.com/missing"); err != nil || res.StatusCode != http.StatusNotFound {
+		t.Errorf("replayed /missing = %v, %v; want 404", res, err)
+	}
+	if _, err := rep.Get("https://example.com/flaky"); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("replayed /flaky error = %v; want %v", err, ErrNotRecorded)
+	}
+	if _, err := rep.Post("https://example.com/lab.md", "text/plain", nil); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("replayed POST error = %v; want %v", err, ErrNotRecorded)
+	}
+}
+
+func TestFetcherOffline(t *testing.T) {
+	dir := t.TempDir()
+	f, err := NewFetcher("", nil, fixtureServer(), FetcherOptions{Fixtures: dir, Record: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.slurpRemoteBytes(context.Background(), "https://example.com/lab.md", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err = NewFetcher("", nil, nil, FetcherOptions{Fixtures: dir, Offline: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clab, err := f.SlurpCodelab(context.Background(), "https://example.com/lab.md", t.TempDir())
+	if err != nil {
+		t.Fatalf("SlurpCodelab() offline: %v", err)
+	}
+	if clab.Meta.ID != "lab" {
+		t.Errorf("SlurpCodelab() offline ID = %q; want %q", clab.Meta.ID, "lab")
+	}
+	if _, err := f.SlurpCodelab(context.Background(), "https://example.com/other.md", t.TempDir()); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("SlurpCodelab() of an unrecorded source error = %v; want %v", err, ErrNotRecorded)
+	}
+
+	if _, err := NewFetcher("", nil, nil, FetcherOptions{Offline: true, Record: true}); err == nil {
+		t.Error("NewFetcher() with Offline and Record = nil error; want an error")
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	// DriveFolderPrefix prefixes a Google Drive folder ID in a source argument,
+	// e.g. drive://0B1x2y3z.
+	DriveFolderPrefix = "drive://"
+
+	mimeFolder   = "application/vnd.google-apps.folder"
+	mimeDocument = "application/vnd.google-apps.document"
+)
+
+// DriveDoc is a Google Doc found in a Drive folder tree.
+type DriveDoc struct {
+	ID   string // Google Doc ID
+	Name string // Doc title
+	Dir  string // Slash-separated path of the containing folder, relative to the listed folder
+}
+
+// IsDriveFolder reports whether src refers to a Drive folder.
+func IsDriveFolder(src string) bool {
+	return strings.HasPrefix(src, DriveFolderPrefix)
+}
+
+// DriveFolderDocs lists Google Docs in the Drive folder src, refered to as
+// drive://folderID, and all of its subfolders.
+//
+// If match is not empty, only the docs whose name matches the glob pattern
+// are returned. See path.Match for the pattern syntax.
+func (f *Fetcher) DriveFolderDocs(ctx context.Context, src, match string) ([]*DriveDoc, error) {
+	if !IsDriveFolder(src) {
+		return nil, fmt.Errorf("%s: not a %s folder", src, DriveFolderPrefix)
+	}
+	if _, err := path.Match(match, ""); err != nil {
+		return nil, fmt.Errorf("%q: %v", match, err)
+	}
+	if err := f.initAuth(); err != nil {
+		return nil, err
+	}
+	id := strings.Trim(strings.TrimPrefix(src, DriveFolderPrefix), "/")
+	var docs []*DriveDoc
+	err := f.walkDriveFolder(ctx, id, "", map[string]bool{}, func(d *DriveDoc) {
+		if match == "" {
+			docs = append(docs, d)
+			return
+		}
+		if ok, _ := path.Match(match, d.Name); ok {
+			docs = append(docs, d)
+		}
+	})
+	return docs, err
+}
+
+// walkDriveFolder calls fn for each doc in folder id, recursively.
+// The seen map prevents infinite loops with folder shortcuts to ancestors.
+func (f *Fetcher) walkDriveFolder(ctx context.Context, id, dir string, seen map[string]bool, fn func(*DriveDoc)) error {
+	if seen[id] {
+		return nil
+	}
+	seen[id] = true
+	files, err := f.listDriveFolder(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		switch file.MimeType {
+		case mimeDocument:
+			fn(&DriveDoc{ID: file.ID, Name: file.Name, Dir: dir})
+		case mimeFolder:
+			if err := f.walkDriveFolder(ctx, file.ID, path.Join(dir, folderDirname(file.Name)), seen, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// folderDirname returns a Drive folder name usable as a single path element.
+func folderDirname(name string) string {
+	name = strings.NewReplacer("/", "-", "\\", "-").Replace(strings.TrimSpace(name))
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
+
+type driveFile struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	MimeType string `json:"mimeType"`
+}
+
+// listDriveFolder returns direct children of a folder, following all result pages.
+func (f *Fetcher) listDriveFolder(ctx context.Context, id string) ([]*driveFile, error) {
+	var files []*driveFile
+	var pageToken string
+	for {
+		q := url.Values{
+			"q":                         {fmt.Sprintf("'%s' in parents and trashed = false", id)},
+			"fields":                    {"nextPageToken,files(id,name,mimeType)"},
+			"orderBy":                   {"name"},
+			"pageSize":                  {"1000"},
+			"supportsAllDrives":         {"true"},
+			"includeItemsFromAllDrives": {"true"},
+		}
+		if pageToken != "" {
+			q.Set("pageToken", pageToken)
+		}
+		u := fmt.Sprintf("%s/files?%s", driveAPI, q.Encode())
+		res, err := f.retryGet(ctx, f.client(), u, 7)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			NextPageToken string       `json:"nextPageToken"`
+			Files         []*driveFile `json:"files"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&page)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, page.Files...)
+		if page.NextPageToken == "" {
+			return files, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
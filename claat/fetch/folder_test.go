@@ -0,0 +1,101 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDriveFolderDocs(t *testing.T) {
+	// folder ID => pages of files JSON
+	folders := map[string][]string{
+		"root": {
+			`{"nextPageToken": "p2", "files": [{"id": "d1", "name": "Lab 1", "mimeType": "application/vnd.google-apps.document"}]}`,
+			`{"files": [
+				{"id": "sub", "name": "Course/A", "mimeType": "application/vnd.google-apps.folder"},
+				{"id": "x", "name": "Sheet", "mimeType": "application/vnd.google-apps.spreadsheet"}
+			]}`,
+		},
+		"sub": {
+			`{"files": [
+				{"id": "d2", "name": "Lab 2", "mimeType": "application/vnd.google-apps.document"},
+				{"id": "d3", "name": "Notes", "mimeType": "application/vnd.google-apps.document"},
+				{"id": "root", "name": "loop", "mimeType": "application/vnd.google-apps.folder"}
+			]}`,
+		},
+	}
+	rt := &testTransport{func(r *http.Request) (*http.Response, error) {
+		q := r.URL.Query()
+		id := strings.Split(q.Get("q"), "'")[1]
+		page := 0
+		if q.Get("pageToken") == "p2" {
+			page = 1
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(folders[id][page])),
+		}, nil
+	}}
+
+	tests := []struct {
+		match string
+		want  []*DriveDoc
+	}{
+		{
+			match: "",
+			want: []*DriveDoc{
+				{ID: "d1", Name: "Lab 1"},
+				{ID: "d2", Name: "Lab 2", Dir: "Course-A"},
+				{ID: "d3", Name: "Notes", Dir: "Course-A"},
+			},
+		},
+		{
+			match: "Lab *",
+			want: []*DriveDoc{
+				{ID: "d1", Name: "Lab 1"},
+				{ID: "d2", Name: "Lab 2", Dir: "Course-A"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		f, err := NewFetcher("token", nil, rt, FetcherOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs, err := f.DriveFolderDocs(context.Background(), "drive://root", tc.match)
+		if err != nil {
+			t.Fatalf("DriveFolderDocs(%q) = %v", tc.match, err)
+		}
+		if diff := cmp.Diff(tc.want, docs); diff != "" {
+			t.Errorf("DriveFolderDocs(%q) got diff (-want +got): %s", tc.match, diff)
+		}
+	}
+}
+
+func TestDriveFolderDocsInvalid(t *testing.T) {
+	f, _ := NewFetcher("token", nil, nil, FetcherOptions{})
+	if _, err := f.DriveFolderDocs(context.Background(), "root", ""); err == nil {
+		t.Errorf("DriveFolderDocs() of a non-folder source returned nil error")
+	}
+	if _, err := f.DriveFolderDocs(context.Background(), "drive://root", "["); err == nil {
+		t.Errorf("DriveFolderDocs() with a malformed pattern returned nil error")
+	}
+}
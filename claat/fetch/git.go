@@ -0,0 +1,298 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/util"
+)
+
+const (
+	// GitTokenEnv is the environment variable holding a token
+	// authorizing fetches of private git repositories over HTTPS,
+	// such as a GitHub personal access token.
+	GitTokenEnv = "GIT_TOKEN"
+	// GitTokenHostEnv is the environment variable holding the host
+	// the token of GitTokenEnv is sent to, defaultGitTokenHost if empty.
+	// Fetches from other hosts go without it.
+	GitTokenHostEnv = "GIT_TOKEN_HOST"
+
+	defaultGitTokenHost = "github.com"
+
+	// gitTreeTTL is how long the checkout of a commit is kept in the cache
+	// once it is no longer used.
+	gitTreeTTL = 24 * time.Hour
+)
+
+// gitRemote returns the URL of git repository repo, host/owner/repo.
+// It is a variable for tests to fetch local repositories.
+var gitRemote = func(repo string) string {
+	return "https://" + repo
+}
+
+// gitLocks serialize the fetches into each checkout directory.
+var gitLocks sync.Map // checkout directory => *sync.Mutex
+
+// gitSources is the Source of files of git repositories.
+type gitSources struct{}
+
+func (gitSources) Resolve(ctx context.Context, f *Fetcher, src, match string) ([]*Entry, error) {
+	return single(src), nil
+}
+
+func (s gitSources) Fetch(ctx context.Context, f *Fetcher, src string) (*Resource, error) {
+	p, _, err := s.Local(ctx, f, src)
+	if err != nil {
+		return nil, err
+	}
+	return fileSource{}.Fetch(ctx, f, p)
+}
+
+// Revision returns the commit of the ref of the source, without fetching it.
+func (gitSources) Revision(ctx context.Context, f *Fetcher, src string) (string, error) {
+	g, ok := util.ParseGitSource(src)
+	if !ok {
+		return "", fmt.Errorf("%s: not a git source", src)
+	}
+	if err := checkRef(ctx, f, g.Ref); err != nil {
+		return "", err
+	}
+	ref := g.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	out, err := runGit(ctx, f, "", "ls-remote", gitRemote(g.Repo), ref)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(out)
+	if len(fields) == 0 {
+		// a commit, which ls-remote does not list
+		return ref, nil
+	}
+	return fields[0], nil
+}
+
+// GitTag returns the git tag of the commit of codelab src: that of the
+// commit checked out in the repository of a local file, or a tag of the
+// commit of the ref of a git source, the ref itself if it is one.
+// It is an error if src is not in a git repository or its commit
+// is not tagged.
+func (f *Fetcher) GitTag(ctx context.Context, src string) (string, error) {
+	switch SourceScheme(src) {
+	case SchemeFile:
+		out, err := runGit(ctx, f, filepath.Dir(filePath(src)), "describe", "--tags", "--exact-match", "HEAD")
+		if err != nil {
+			return "", fmt.Errorf("%s: no git tag: %v", src, err)
+		}
+		return strings.TrimSpace(out), nil
+	case SchemeGit:
+		return gitSources{}.tag(ctx, f, src)
+	}
+	return "", fmt.Errorf("%s: not in a git repository", src)
+}
+
+// tag returns the tag of the commit of the ref of the source,
+// the ref itself if it is a tag, or the first tag in name order.
+func (s gitSources) tag(ctx context.Context, f *Fetcher, src string) (string, error) {
+	rev, err := s.Revision(ctx, f, src)
+	if err != nil {
+		return "", err
+	}
+	g, _ := util.ParseGitSource(src)
+	out, err := runGit(ctx, f, "", "ls-remote", "--tags", gitRemote(g.Repo))
+	if err != nil {
+		return "", err
+	}
+	var tags []string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != rev {
+			continue
+		}
+		// annotated tags are listed as refs/tags/name^{} with their commit
+		name := strings.TrimSuffix(strings.TrimPrefix(fields[1], "refs/tags/"), "^{}")
+		if name == g.Ref {
+			return name, nil
+		}
+		tags = append(tags, name)
+	}
+	if len(tags) == 0 {
+		return "", fmt.Errorf("%s: no git tag of commit %s", src, rev)
+	}
+	sort.Strings(tags)
+	return tags[0], nil
+}
+
+// Local fetches the ref of the source, shallowly, in a repository
+// of FetcherOptions.GitCache, and returns the local file of the source
+// along with the commit fetched. Each commit is checked out in its own
+// directory of the cache, which is never modified once written, so that
+// the files of a codelab, such as its images and imports, are read from
+// the same commit while other exports fetch the ref again.
+func (gitSources) Local(ctx context.Context, f *Fetcher, src string) (string, string, error) {
+	g, ok := util.ParseGitSource(src)
+	if !ok {
+		return "", "", fmt.Errorf("%s: not a git source", src)
+	}
+	if err := checkRef(ctx, f, g.Ref); err != nil {
+		return "", "", err
+	}
+	cache := f.opts.GitCache
+	if cache == "" {
+		cache = filepath.Join(os.TempDir(), "claat-git")
+	}
+	dir := filepath.Join(cache, fmt.Sprintf("%x", sha1.Sum([]byte(g.Repo+"@"+g.Ref))))
+	mu, _ := gitLocks.LoadOrStore(dir, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	defer mu.(*sync.Mutex).Unlock()
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		if _, err := runGit(ctx, f, "", "init", "-q", dir); err != nil {
+			return "", "", err
+		}
+	}
+	ref := g.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if _, err := runGit(ctx, f, dir, "fetch", "-q", "--depth", "1", gitRemote(g.Repo), ref); err != nil {
+		return "", "", err
+	}
+	out, err := runGit(ctx, f, dir, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return "", "", err
+	}
+	sha := strings.TrimSpace(out)
+	prefix := filepath.Join(cache, fmt.Sprintf("%x-", sha1.Sum([]byte(g.Repo))))
+	tree := prefix + sha
+	if err := checkoutTree(ctx, f, dir, sha, tree); err != nil {
+		return "", "", err
+	}
+	pruneTrees(prefix, tree)
+	p, err := restrictPathToParent(filepath.FromSlash(g.Path), tree)
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := os.Stat(p); err != nil {
+		return "", "", fmt.Errorf("%s: no %s in %s at %s", src, g.Path, g.Repo, ref)
+	}
+	return p, sha, nil
+}
+
+// checkoutTree checks out commit sha of the repository in dir to directory
+// tree, unless it already is, and marks it as used. The files are checked out
+// in a temporary directory first, renamed to tree once complete.
+// Symbolic links are checked out as plain files holding their target,
+// so that they cannot lead to files out of the repository.
+func checkoutTree(ctx context.Context, f *Fetcher, dir, sha, tree string) error {
+	if _, err := os.Stat(tree); err == nil {
+		now := time.Now()
+		return os.Chtimes(tree, now, now)
+	}
+	tmp, err := ioutil.TempDir(filepath.Dir(tree), "checkout-")
+	if err != nil {
+		return err
+	}
+	if _, err := runGit(ctx, f, dir, "-c", "core.symlinks=false", "--work-tree", tmp, "checkout", "-q", "--force", sha, "--", "."); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, tree); err != nil {
+		os.RemoveAll(tmp)
+		// checked out meanwhile by an export of another ref of the commit
+		if _, serr := os.Stat(tree); serr == nil {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// pruneTrees removes the checkouts of other commits of a repository than
+// tree, named prefix followed by their commit, unused for gitTreeTTL.
+// Errors are ignored: the checkouts are removed on a later fetch.
+func pruneTrees(prefix, tree string) {
+	trees, _ := filepath.Glob(prefix + "*")
+	for _, t := range trees {
+		if t == tree {
+			continue
+		}
+		if fi, err := os.Stat(t); err == nil && time.Since(fi.ModTime()) > gitTreeTTL {
+			os.RemoveAll(t)
+		}
+	}
+}
+
+// checkRef returns an error if ref, of a source, is not a valid git ref name,
+// such as a ref starting with "-", which git would read as an option.
+func checkRef(ctx context.Context, f *Fetcher, ref string) error {
+	if ref == "" {
+		return nil
+	}
+	if strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("invalid git ref %q", ref)
+	}
+	if _, err := runGit(ctx, f, "", "check-ref-format", "--allow-onelevel", ref); err != nil {
+		return fmt.Errorf("invalid git ref %q", ref)
+	}
+	return nil
+}
+
+// runGit runs git with args in dir, returning its standard output.
+// The token of GitTokenEnv, if any, authorizes HTTPS fetches from
+// the host of GitTokenHostEnv only, through the environment, so that
+// it does not appear in the command line and error messages.
+func runGit(ctx context.Context, f *Fetcher, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if token := os.Getenv(GitTokenEnv); token != "" {
+		auth := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+		cmd.Env = append(cmd.Env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0="+gitTokenKey(),
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+auth)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// gitTokenKey returns the git configuration key of the header
+// authorizing HTTPS fetches from the host of GitTokenHostEnv.
+func gitTokenKey() string {
+	host := os.Getenv(GitTokenHostEnv)
+	if host == "" {
+		host = defaultGitTokenHost
+	}
+	return "http.https://" + host + "/.extraHeader"
+}
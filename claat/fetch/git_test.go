@@ -0,0 +1,307 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+// gitRepo creates a git repository of files, with a single commit
+// on branch main, and returns its directory and the commit.
+func gitRepo(t *testing.T, files map[string]string) (string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := writeFiles(t, files)
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=claat", "-c", "user.email=claat@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v: %s", args[0], err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q", "-b", "main")
+	git("add", ".")
+	git("commit", "-q", "-m", "labs")
+	return dir, git("rev-parse", "HEAD")
+}
+
+func TestSlurpCodelabGit(t *testing.T) {
+	repo, sha := gitRepo(t, map[string]string{
+		"labs/intro.md":        "---\nid: lab\nsummary: Code\n\n---\n\n# Code\n\n## Step 1\n\n```include samples/main.go#L3-L5\n```\n",
+		"labs/samples/main.go": "package main\n\nfunc main() {\n\tfmt.Println()\n}\n",
+	})
+	remote := gitRemote
+	defer func() { gitRemote = remote }()
+	gitRemote = func(r string) string {
+		if r != "example.com/org/repo" {
+			t.Errorf("remote of %q", r)
+		}
+		return repo
+	}
+
+	f, err := NewFetcher("", nil, nil, FetcherOptions{GitCache: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const src = "example.com/org/repo//labs/intro.md@main"
+	if s := SourceScheme(src); s != SchemeGit {
+		t.Errorf("SourceScheme(%q) = %q; want %q", src, s, SchemeGit)
+	}
+	rev, err := f.Revision(context.Background(), src)
+	if err != nil || rev != sha {
+		t.Errorf("Revision = %q, %v; want %q", rev, err, sha)
+	}
+
+	clab, err := f.SlurpCodelab(context.Background(), src, "-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clab.Revision != sha {
+		t.Errorf("clab.Revision = %q; want %q", clab.Revision, sha)
+	}
+	cc := nodes.CodeIncludes(clab.Steps[0].Content.Nodes)
+	if len(cc) == 0 || cc[0].Value != "func main() {\n\tfmt.Println()\n}\n" {
+		t.Errorf("code includes of the checkout = %v; want main.go lines", cc)
+	}
+
+	if _, err := f.SlurpCodelab(context.Background(), "example.com/org/repo//labs/missing.md@main", "-"); err == nil {
+		t.Error("SlurpCodelab of a missing file succeeded; want an error")
+	}
+	if _, err := f.SlurpCodelab(context.Background(), filepath.ToSlash("example.com/org/repo//../../etc/passwd"), "-"); err == nil {
+		t.Error("SlurpCodelab of a file out of the repository succeeded; want an error")
+	}
+}
+
+func TestCheckRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	f, err := NewFetcher("", nil, nil, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		ref string
+		ok  bool
+	}{
+		{"", true},
+		{"main", true},
+		{"release/v1", true},
+		{"0123456789abcdef0123456789abcdef01234567", true},
+		{"--upload-pack=touch /tmp/x", false},
+		{"-q", false},
+		{"main:refs/heads/x", false},
+		{"a..b", false},
+	}
+	for _, tc := range tests {
+		if err := checkRef(context.Background(), f, tc.ref); (err == nil) != tc.ok {
+			t.Errorf("checkRef(%q) = %v; want ok %v", tc.ref, err, tc.ok)
+		}
+	}
+	if _, err := f.SlurpCodelab(context.Background(), "example.com/org/repo//intro.md@--upload-pack=x", "-"); err == nil {
+		t.Error("SlurpCodelab of a ref starting with - succeeded; want an error")
+	}
+}
+
+func TestGitTokenKey(t *testing.T) {
+	t.Setenv(GitTokenHostEnv, "")
+	if got, want := gitTokenKey(), "http.https://github.com/.extraHeader"; got != want {
+		t.Errorf("gitTokenKey() = %q; want %q", got, want)
+	}
+	t.Setenv(GitTokenHostEnv, "gitlab.example.com")
+	if got, want := gitTokenKey(), "http.https://gitlab.example.com/.extraHeader"; got != want {
+		t.Errorf("gitTokenKey() = %q; want %q", got, want)
+	}
+}
+
+func TestLocalGitCommits(t *testing.T) {
+	repo, first := gitRepo(t, map[string]string{"intro.md": "one"})
+	remote := gitRemote
+	defer func() { gitRemote = remote }()
+	gitRemote = func(string) string { return repo }
+
+	f, err := NewFetcher("", nil, nil, FetcherOptions{GitCache: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const src = "example.com/org/repo//intro.md@main"
+	p1, rev, err := gitSources{}.Local(context.Background(), f, src)
+	if err != nil || rev != first {
+		t.Fatalf("Local = %q, %v; want %q", rev, err, first)
+	}
+	cmd := exec.Command("git", "-c", "user.name=claat", "-c", "user.email=claat@example.com", "commit", "-q", "--allow-empty", "-m", "two")
+	cmd.Dir = repo
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v: %s", err, out)
+	}
+	if err := ioutil.WriteFile(filepath.Join(repo, "intro.md"), []byte("two"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd = exec.Command("git", "-c", "user.name=claat", "-c", "user.email=claat@example.com", "commit", "-q", "-am", "three")
+	cmd.Dir = repo
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v: %s", err, out)
+	}
+	p2, rev2, err := gitSources{}.Local(context.Background(), f, src)
+	if err != nil || rev2 == first {
+		t.Fatalf("Local after a commit = %q, %v; want a new commit", rev2, err)
+	}
+	if p1 == p2 {
+		t.Errorf("commits %s and %s are checked out in the same directory %s", first, rev2, p1)
+	}
+	// the checkout of the first commit is left untouched for its readers
+	for p, want := range map[string]string{p1: "one", p2: "two"} {
+		b, err := ioutil.ReadFile(p)
+		if err != nil || string(b) != want {
+			t.Errorf("ReadFile(%s) = %q, %v; want %q", p, b, err, want)
+		}
+	}
+}
+
+func TestGitTag(t *testing.T) {
+	repo, _ := gitRepo(t, map[string]string{"labs/intro.md": "one"})
+	remote := gitRemote
+	defer func() { gitRemote = remote }()
+	gitRemote = func(string) string { return repo }
+
+	f, err := NewFetcher("", nil, nil, FetcherOptions{GitCache: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := filepath.Join(repo, "labs", "intro.md")
+	if tag, err := f.GitTag(context.Background(), local); err == nil {
+		t.Errorf("GitTag of an untagged commit = %q; want an error", tag)
+	}
+	for _, args := range [][]string{{"tag", "v2"}, {"tag", "-a", "-m", "first", "v1"}} {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=claat", "-c", "user.email=claat@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git tag: %v: %s", err, out)
+		}
+	}
+	tests := []struct{ src, want string }{
+		{local, "v1"},
+		{"example.com/org/repo//labs/intro.md@main", "v1"},
+		{"example.com/org/repo//labs/intro.md@v2", "v2"},
+		{"example.com/org/repo//labs/intro.md@v1", "v1"},
+	}
+	for _, tc := range tests {
+		tag, err := f.GitTag(context.Background(), tc.src)
+		if err != nil || tag != tc.want {
+			t.Errorf("GitTag(%q) = %q, %v; want %q", tc.src, tag, err, tc.want)
+		}
+	}
+	if tag, err := f.GitTag(context.Background(), "https://example.com/intro.md"); err == nil {
+		t.Errorf("GitTag of a URL = %q; want an error", tag)
+	}
+}
+
+func TestSlurpCodelabGitSymlink(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	secret := filepath.Join(t.TempDir(), "secret.txt")
+	if err := ioutil.WriteFile(secret, []byte("host secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repo := writeFiles(t, map[string]string{
+		"labs/intro.md": "---\nid: lab\nsummary: Leak\n\n---\n\n# Leak\n\n## Step 1\n\n```include leak.txt\n```\n",
+	})
+	if err := os.Symlink(secret, filepath.Join(repo, "labs", "leak.txt")); err != nil {
+		t.Skip(err)
+	}
+	for _, args := range [][]string{{"init", "-q", "-b", "main"}, {"add", "."}, {"commit", "-q", "-m", "leak"}} {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=claat", "-c", "user.email=claat@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v: %s", args[0], err, out)
+		}
+	}
+	remote := gitRemote
+	defer func() { gitRemote = remote }()
+	gitRemote = func(string) string { return repo }
+
+	f, err := NewFetcher("", nil, nil, FetcherOptions{GitCache: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clab, err := f.SlurpCodelab(context.Background(), "example.com/org/repo//labs/intro.md@main", "-")
+	if err != nil {
+		return // failing is fine, as long as the file is not read
+	}
+	for _, cn := range nodes.CodeIncludes(clab.Steps[0].Content.Nodes) {
+		if strings.Contains(cn.Value, "host secret") {
+			t.Errorf("code include of a symlink out of the repository = %q", cn.Value)
+		}
+	}
+}
+
+func TestRestrictPathToParentSymlink(t *testing.T) {
+	parent := t.TempDir()
+	out := filepath.Join(t.TempDir(), "out.txt")
+	if err := ioutil.WriteFile(out, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(out, filepath.Join(parent, "link.txt")); err != nil {
+		t.Skip(err)
+	}
+	if _, err := restrictPathToParent("link.txt", parent); err == nil {
+		t.Error("restrictPathToParent(link out of parent) = nil error; want an error")
+	}
+	if err := ioutil.WriteFile(filepath.Join(parent, "in.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("in.txt", filepath.Join(parent, "inlink.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := restrictPathToParent("inlink.txt", parent); err != nil {
+		t.Errorf("restrictPathToParent(link in parent) = %v; want nil", err)
+	}
+}
+
+func TestPruneTrees(t *testing.T) {
+	cache := t.TempDir()
+	prefix := filepath.Join(cache, "repo-")
+	old := time.Now().Add(-2 * gitTreeTTL)
+	for _, sha := range []string{"a", "b", "c"} {
+		if err := os.Mkdir(prefix+sha, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, sha := range []string{"a", "c"} {
+		if err := os.Chtimes(prefix+sha, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pruneTrees(prefix, prefix+"c")
+	for sha, want := range map[string]bool{"a": false, "b": true, "c": true} {
+		if _, err := os.Stat(prefix + sha); (err == nil) != want {
+			t.Errorf("tree %s exists: %v; want %v", sha, err == nil, want)
+		}
+	}
+}
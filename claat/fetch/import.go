@@ -0,0 +1,240 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/util"
+)
+
+const (
+	// maxImportDepth is the maximum nesting level of imported fragments.
+	maxImportDepth = 8
+	// DefaultImportCacheTTL is how long a cached import is reused
+	// if FetcherOptions.ImportCacheTTL is zero.
+	DefaultImportCacheTTL = time.Hour
+)
+
+// slurpImports fetches and parses, as fragments, the content of all import nodes
+// found in nn, which is content of the src document.
+// Imported fragments may import other fragments in turn, up to maxImportDepth levels.
+//
+// The chain argument is the list of documents importing src, src included,
+// which is used to detect import cycles.
+// If imgDir is not empty, images of imported fragments are slurped into it,
+// and images map is populated as with SlurpImages.
+func (f *Fetcher) slurpImports(ctx context.Context, src string, nn []nodes.Node, chain []string, imgDir string, images map[string]string) error {
+	imports := nodes.ImportNodes(nn)
+	if len(imports) == 0 {
+		return nil
+	}
+	if len(chain) > maxImportDepth {
+		return fmt.Errorf("%s: imports nested deeper than %d levels", src, maxImportDepth)
+	}
+	// auth helper is not safe for concurrent init
+	for _, imp := range imports {
+		if googleSource(ResolveSource(src, imp.URL)) {
+			if err := f.initAuth(); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	type result struct {
+		imgs map[string]string
+		err  error
+	}
+	ch := make(chan *result, len(imports))
+	for _, imp := range imports {
+		go func(n *nodes.ImportNode) {
+			r := &result{imgs: make(map[string]string)}
+			if err := f.slurpImport(ctx, src, n, chain, imgDir, r.imgs); err != nil {
+				r.err = fmt.Errorf("%s: %v", n.URL, err)
+			}
+			ch <- r
+		}(imp)
+	}
+	var err error
+	for range imports {
+		r := <-ch
+		for k, v := range r.imgs {
+			images[k] = v
+		}
+		if r.err != nil && err == nil {
+			err = r.err
+		}
+	}
+	return err
+}
+
+// slurpImport fetches and parses a single import n of the base document,
+// storing the result in n.Content.
+func (f *Fetcher) slurpImport(ctx context.Context, base string, n *nodes.ImportNode, chain []string, imgDir string, images map[string]string) error {
+	src := ResolveSource(base, n.URL)
+	key := importKey(src)
+	for _, s := range chain {
+		if importKey(s) == key {
+			return fmt.Errorf("import cycle: %s", strings.Join(append(chain, src), " -> "))
+		}
+	}
+	frag, err := f.slurpFragment(ctx, src)
+	if err != nil {
+		return err
+	}
+	if imgDir != "" {
+		// download or copy codelab assets to disk, and rewrite image URLs
+		if err := f.SlurpImages(ctx, gdocID(src), imgDir, frag, images); err != nil {
+			return err
+		}
+	}
+	chain = append(chain[:len(chain):len(chain)], src)
+	if err := f.slurpImports(ctx, src, frag, chain, imgDir, images); err != nil {
+		return err
+	}
+	n.Content.Nodes = frag
+	return nil
+}
+
+// ResolveSource returns the location of ref, referenced by the base document,
+// such as an imported fragment or a translation.
+// Relative file references are resolved against the directory of a local base,
+// or the URL of a remote base, if a file exists at the resolved location.
+// Any other ref, such as a Google Doc ID, is returned as is.
+func ResolveSource(base, ref string) string {
+	if isNotionSource(ref) || filepath.IsAbs(ref) {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil || u.Host != "" {
+		return ref
+	}
+	if g, ok := util.ParseGitSource(base); ok && !isLocal(base) && filepath.Ext(ref) != "" {
+		return g.Resolve(ref)
+	}
+	if isLocal(base) {
+		if p := filepath.Join(filepath.Dir(base), ref); isLocal(p) {
+			return p
+		}
+		return ref
+	}
+	b, err := url.Parse(base)
+	if err != nil || b.Host == "" || b.Host == "docs.google.com" || filepath.Ext(ref) == "" {
+		return ref
+	}
+	return b.ResolveReference(u).String()
+}
+
+// importKey returns a key identifying the document at src,
+// so that different references to the same document compare equal.
+func importKey(src string) string {
+	if isLocal(src) {
+		if p, err := filepath.Abs(src); err == nil {
+			return p
+		}
+		return src
+	}
+	if id, err := notionPageID(src); err == nil && isNotionSource(src) {
+		return NotionPrefix + strings.ReplaceAll(id, "-", "")
+	}
+	if u, err := url.Parse(src); err == nil && (u.Host == "" || u.Host == "docs.google.com") {
+		return gdocID(src)
+	}
+	return src
+}
+
+// isLocal reports whether name is an existing local file.
+func isLocal(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+// fetchImport retrieves imported resource src, like fetch does,
+// using the on-disk cache of FetcherOptions.ImportCache for remote resources.
+func (f *Fetcher) fetchImport(ctx context.Context, src string) (*Resource, error) {
+	if f.opts.ImportCache == "" || isLocal(src) {
+		return f.fetch(ctx, src)
+	}
+	ttl := f.opts.ImportCacheTTL
+	if ttl == 0 {
+		ttl = DefaultImportCacheTTL
+	}
+	key := filepath.Join(f.opts.ImportCache, fmt.Sprintf("%x", sha1.Sum([]byte(importKey(src)))))
+	// the cache file extension is the source type
+	matches, _ := filepath.Glob(key + ".*")
+	for _, m := range matches {
+		fi, err := os.Stat(m)
+		if err != nil || time.Since(fi.ModTime()) > ttl {
+			continue
+		}
+		r, err := os.Open(m)
+		if err != nil {
+			continue
+		}
+		return &Resource{
+			Body: r,
+			Type: srcType(strings.TrimPrefix(filepath.Ext(m), ".")),
+			Mod:  fi.ModTime(),
+		}, nil
+	}
+
+	res, err := f.fetch(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if err := writeCacheFile(key+"."+string(res.Type), b); err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return res, nil
+}
+
+// writeCacheFile atomically writes b to the file name,
+// creating its directory if needed.
+func writeCacheFile(name string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(name), ".import-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
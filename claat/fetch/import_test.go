@@ -0,0 +1,181 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	_ "github.com/googlecodelabs/tools/claat/parser/md"
+)
+
+const importCodelab = `---
+id: imports
+summary: Imports
+
+---
+
+# Imports
+
+## Step 1
+<<a.md>>
+`
+
+// writeFiles writes files, name => content, into a temp dir and returns the dir.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// importedText returns text of all text nodes in nn, including imported content.
+func importedText(nn []nodes.Node) string {
+	var b strings.Builder
+	for _, n := range nn {
+		switch n := n.(type) {
+		case *nodes.TextNode:
+			b.WriteString(n.Value)
+		case *nodes.ListNode:
+			b.WriteString(importedText(n.Nodes))
+		case *nodes.ImportNode:
+			b.WriteString(importedText(n.Content.Nodes))
+		}
+	}
+	return b.String()
+}
+
+func TestSlurpCodelabNestedImports(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"main.md":     importCodelab,
+		"a.md":        "From a.\n\n<<sub/b.md>>\n",
+		"sub/b.md":    "From b.\n\n<<c.md>>\n",
+		"sub/c.md":    "From c.\n",
+		"unused/c.md": "Wrong c.\n",
+	})
+	f, err := NewFetcher("", nil, nil, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clab, err := f.SlurpCodelab(context.Background(), filepath.Join(dir, "main.md"), "-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := importedText(clab.Steps[0].Content.Nodes)
+	if want := "From a.From b.From c."; got != want {
+		t.Errorf("imported text = %q; want %q", got, want)
+	}
+}
+
+func TestSlurpCodelabImportCycle(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"main.md": importCodelab,
+		"a.md":    "<<b.md>>\n",
+		"b.md":    "<<a.md>>\n",
+	})
+	f, err := NewFetcher("", nil, nil, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.SlurpCodelab(context.Background(), filepath.Join(dir, "main.md"), "-")
+	if err == nil || !strings.Contains(err.Error(), "import cycle") {
+		t.Errorf("SlurpCodelab() error = %v; want import cycle", err)
+	}
+}
+
+func TestSlurpCodelabImportDepth(t *testing.T) {
+	files := map[string]string{"main.md": importCodelab, "a.md": "<<0.md>>\n"}
+	for i := 0; i < maxImportDepth+1; i++ {
+		files[fmt.Sprintf("%d.md", i)] = fmt.Sprintf("<<%d.md>>\n", i+1)
+	}
+	files[fmt.Sprintf("%d.md", maxImportDepth+1)] = "Too deep.\n"
+	dir := writeFiles(t, files)
+	f, err := NewFetcher("", nil, nil, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.SlurpCodelab(context.Background(), filepath.Join(dir, "main.md"), "-")
+	if err == nil || !strings.Contains(err.Error(), "nested deeper") {
+		t.Errorf("SlurpCodelab() error = %v; want nesting depth error", err)
+	}
+}
+
+func TestFetchImportCache(t *testing.T) {
+	var requests int
+	rt := &testTransport{func(r *http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("<p>Remote</p>")),
+		}, nil
+	}}
+	f, err := NewFetcher("", nil, rt, FetcherOptions{ImportCache: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		res, err := f.fetchImport(context.Background(), "https://example.com/fragment.html")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != "<p>Remote</p>" || res.Type != SrcHTML {
+			t.Errorf("%d: fetchImport() = %q, %q; want %q, %q", i, b, res.Type, "<p>Remote</p>", SrcHTML)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d; want 1", requests)
+	}
+}
+
+func TestResolveSource(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"lab/main.md": "", "lab/frag.md": ""})
+	base := filepath.Join(dir, "lab", "main.md")
+	tests := []struct{ base, ref, want string }{
+		{base, "frag.md", filepath.Join(dir, "lab", "frag.md")},
+		{base, "missing.md", "missing.md"},
+		{base, "1a2b3c", "1a2b3c"},
+		{"https://example.com/labs/main.md", "frag.md", "https://example.com/labs/frag.md"},
+		{"https://example.com/labs/main.md", "1a2b3c", "1a2b3c"},
+		{"1a2b3c", "frag.md", "frag.md"},
+		{"github.com/org/repo//labs/main.md@v1", "frag.md", "github.com/org/repo//labs/frag.md@v1"},
+		{"github.com/org/repo//main.md", "../frag.md", "github.com/org/repo//frag.md"},
+		{"github.com/org/repo//main.md", "1a2b3c", "1a2b3c"},
+	}
+	for _, test := range tests {
+		if got := ResolveSource(test.base, test.ref); got != test.want {
+			t.Errorf("ResolveSource(%q, %q) = %q; want %q", test.base, test.ref, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+// lineRangeRegexp matches a "L10-L40" or "L10" line range of a code include.
+var lineRangeRegexp = regexp.MustCompile(`^L(\d+)(?:-L?(\d+))?$`)
+
+// SlurpCodeIncludes replaces the value of code nodes of nn including code
+// from a file with the content of the file, or with the lines of its
+// "#L10-L40" line range. Common indentation of the lines is removed.
+// Relative locations are resolved against codelab src, like attachments.
+func (f *Fetcher) SlurpCodeIncludes(ctx context.Context, src string, nn []nodes.Node) error {
+	for _, cn := range nodes.CodeIncludes(nn) {
+		ref, frag := cn.Src, ""
+		if i := strings.IndexByte(ref, '#'); i >= 0 {
+			ref, frag = ref[:i], ref[i+1:]
+		}
+		b, err := f.slurpFile(ctx, src, ref)
+		if err != nil {
+			return fmt.Errorf("%s: %v", cn.Src, err)
+		}
+		v, err := codeLines(string(b), frag)
+		if err != nil {
+			return fmt.Errorf("%s: %v", cn.Src, err)
+		}
+		cn.Value = v
+	}
+	return nil
+}
+
+// codeLines returns the lines of code selected by line range frag,
+// or all lines if frag is empty, without their common indentation.
+func codeLines(code, frag string) (string, error) {
+	lines := strings.Split(strings.TrimSuffix(strings.ReplaceAll(code, "\r\n", "\n"), "\n"), "\n")
+	if frag != "" {
+		m := lineRangeRegexp.FindStringSubmatch(frag)
+		if m == nil {
+			return "", fmt.Errorf("invalid line range %q, want L10-L40 or L10", frag)
+		}
+		start, _ := strconv.Atoi(m[1])
+		end := start
+		if m[2] != "" {
+			end, _ = strconv.Atoi(m[2])
+		}
+		if start < 1 || end < start {
+			return "", fmt.Errorf("invalid line range %q", frag)
+		}
+		if end > len(lines) {
+			return "", fmt.Errorf("line range %q is past the end of the file, which has %d lines", frag, len(lines))
+		}
+		lines = lines[start-1 : end]
+	}
+	return strings.Join(dedent(lines), "\n") + "\n", nil
+}
+
+// dedent removes the longest whitespace prefix common to all non-blank lines.
+func dedent(lines []string) []string {
+	var prefix string
+	first := true
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		indent := l[:len(l)-len(strings.TrimLeftFunc(l, unicode.IsSpace))]
+		if first {
+			prefix, first = indent, false
+			continue
+		}
+		for !strings.HasPrefix(indent, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	res := make([]string, len(lines))
+	for i, l := range lines {
+		res[i] = strings.TrimPrefix(l, prefix)
+	}
+	return res
+}
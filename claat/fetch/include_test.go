@@ -0,0 +1,127 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+const includeCodelab = "---\nid: lab\nsummary: Code\n\n---\n\n# Code\n\n## Step 1\n\n" +
+	"```include samples/main.go#L3-L5\n```\n\n" +
+	"```include https://github.com/owner/repo/blob/main/app.py\n```\n"
+
+func TestSlurpCodelabCodeIncludes(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"main.md":         includeCodelab,
+		"samples/main.go": "package main\n\nfunc main() {\n\tfmt.Println()\n}\n",
+	})
+	var got string
+	rt := &testTransport{func(r *http.Request) (*http.Response, error) {
+		got = r.URL.String()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("print()\n")),
+		}, nil
+	}}
+	f, err := NewFetcher("", nil, rt, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clab, err := f.SlurpCodelab(context.Background(), filepath.Join(dir, "main.md"), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "https://raw.githubusercontent.com/owner/repo/main/app.py"; got != want {
+		t.Errorf("fetched %q; want %q", got, want)
+	}
+	var values []string
+	for _, cn := range nodes.CodeIncludes(clab.Steps[0].Content.Nodes) {
+		values = append(values, cn.Value)
+	}
+	want := []string{"func main() {\n\tfmt.Println()\n}\n", "print()\n"}
+	if diff := cmp.Diff(want, values); diff != "" {
+		t.Errorf("included code got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestSlurpCodeIncludesOutsideSource(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"secret.go": "s", "lab/main.md": ""})
+	f, err := NewFetcher("", nil, nil, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cn := nodes.NewCodeNode("", false, "go")
+	cn.Src = "../secret.go"
+	if err := f.SlurpCodeIncludes(context.Background(), filepath.Join(dir, "lab", "main.md"), []nodes.Node{cn}); err == nil {
+		t.Errorf("SlurpCodeIncludes(../secret.go) error = nil; want an error")
+	}
+}
+
+func TestCodeLines(t *testing.T) {
+	const code = "func f() {\n\tif x {\n\t\treturn\n\t}\n}\n"
+	tests := []struct {
+		frag    string
+		out     string
+		wantErr bool
+	}{
+		{frag: "", out: code},
+		{frag: "L2-L4", out: "if x {\n\treturn\n}\n"},
+		{frag: "L3", out: "return\n"},
+		{frag: "L2-4", out: "if x {\n\treturn\n}\n"},
+		{frag: "L4-L6", wantErr: true},
+		{frag: "L3-L2", wantErr: true},
+		{frag: "L0", wantErr: true},
+		{frag: "line3", wantErr: true},
+	}
+	for _, tc := range tests {
+		out, err := codeLines(code, tc.frag)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("codeLines(%q) error = %v; want error %v", tc.frag, err, tc.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(tc.out, out); diff != "" {
+			t.Errorf("codeLines(%q) got diff (-want +got):\n%s", tc.frag, diff)
+		}
+	}
+}
+
+func TestGithubRawURL(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"https://github.com/owner/repo/blob/v1.2/src/main.go", "https://raw.githubusercontent.com/owner/repo/v1.2/src/main.go"},
+		{"https://github.com/owner/repo", "https://github.com/owner/repo"},
+		{"https://example.com/owner/repo/blob/main/x.go", "https://example.com/owner/repo/blob/main/x.go"},
+	}
+	for _, tc := range tests {
+		u, err := url.Parse(tc.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := githubRawURL(u).String(); out != tc.out {
+			t.Errorf("githubRawURL(%q) = %q; want %q", tc.in, out, tc.out)
+		}
+	}
+}
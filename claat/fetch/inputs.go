@@ -0,0 +1,77 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// inputs records the revisions of the sources read while slurping a codelab,
+// such as the parts of a manifest, imports, code includes, images and
+// attachments, as the Revision of their Source returns them, so that
+// a later export can tell whether any of them changed without fetching them.
+type inputs struct {
+	mu   sync.Mutex
+	revs map[string]string
+}
+
+// add records rev as the revision of src. It does nothing on nil in.
+func (in *inputs) add(src, rev string) {
+	if in == nil {
+		return
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.revs[src] = rev
+}
+
+// recordInputs calls slurp with a copy of f recording the revisions of
+// the sources it reads, and sets the Inputs of the slurped codelab of src
+// to those of the other sources.
+func (f *Fetcher) recordInputs(src string, slurp func(*Fetcher) (*codelab, error)) (*codelab, error) {
+	f2 := *f
+	f2.inputs = &inputs{revs: make(map[string]string)}
+	c, err := slurp(&f2)
+	if err != nil {
+		return nil, err
+	}
+	delete(f2.inputs.revs, src)
+	if len(f2.inputs.revs) > 0 {
+		c.Inputs = f2.inputs.revs
+	}
+	return c, nil
+}
+
+// fileRevision returns the revision of local file name,
+// as fileSource.Revision does.
+func fileRevision(name string) (string, error) {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return "", err
+	}
+	return revisionTime(fi.ModTime()), nil
+}
+
+// contentRevision returns the revision of remote content b served
+// with etag, as httpSource.Revision does.
+func contentRevision(etag string, b []byte) string {
+	if etag != "" {
+		return etag
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b))
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+// sizeLimiter is a body of what, such as a source, failing reads
+// past max bytes.
+type sizeLimiter struct {
+	io.ReadCloser
+	what string
+	max  int64
+	left int64
+}
+
+// limitSize returns rc, the body of what, failing reads past max bytes,
+// or rc itself if max is not positive.
+func limitSize(rc io.ReadCloser, what string, max int64) io.ReadCloser {
+	if max <= 0 {
+		return rc
+	}
+	return &sizeLimiter{ReadCloser: rc, what: what, max: max, left: max}
+}
+
+func (l *sizeLimiter) Read(p []byte) (int, error) {
+	// read one byte past the limit to tell an exact fit from an overflow
+	if int64(len(p)) > l.left+1 {
+		p = p[:l.left+1]
+	}
+	n, err := l.ReadCloser.Read(p)
+	l.left -= int64(n)
+	if l.left < 0 {
+		return 0, fmt.Errorf("%s larger than %d bytes", l.what, l.max)
+	}
+	return n, err
+}
+
+// imageHosts are the hosts, and parents of hosts, of the images
+// of Google Docs, fetched from codelab sources without external content.
+var imageHosts = []string{"googleusercontent.com"}
+
+// checkExternal returns an error if nn, the nodes of codelab src,
+// reference content fetched from other files or URLs: imports,
+// code includes, attachments or images of other hosts than src
+// and imageHosts.
+func checkExternal(src string, nn []nodes.Node) error {
+	for _, n := range nodes.ImageNodes(nn) {
+		if len(n.Bytes) > 0 {
+			continue
+		}
+		for _, u := range []string{n.Src, n.Dark} {
+			if u != "" && !localImage(src, u) {
+				return fmt.Errorf("%s: remote images are not allowed", u)
+			}
+		}
+	}
+	if imp := nodes.ImportNodes(nn); len(imp) > 0 {
+		return fmt.Errorf("%s: imports are not allowed", imp[0].URL)
+	}
+	if cn := nodes.CodeIncludes(nn); len(cn) > 0 {
+		return fmt.Errorf("%s: code includes are not allowed", cn[0].Src)
+	}
+	if an := nodes.AttachmentNodes(nn); len(an) > 0 {
+		return fmt.Errorf("%s: attachments are not allowed", an[0].Src)
+	}
+	return nil
+}
+
+// localImage reports whether image imgURL of codelab src is read
+// from the files of src, the host of src or imageHosts.
+func localImage(src, imgURL string) bool {
+	u, err := url.Parse(imgURL)
+	if err != nil {
+		return false
+	}
+	srcURL, err := url.Parse(src)
+	if err == nil && srcURL.Host != "" {
+		u = srcURL.ResolveReference(u)
+	}
+	if u.Host == "" {
+		return u.Scheme == ""
+	}
+	if srcURL != nil && strings.EqualFold(u.Host, srcURL.Host) {
+		return true
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, h := range imageHosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckNodes returns an error if nn have more than max content nodes,
+// recursively, or ctx is done while they are counted.
+// Zero max is not limited.
+func CheckNodes(ctx context.Context, nn []nodes.Node, max int) error {
+	if max <= 0 {
+		return nil
+	}
+	var n int
+	_, err := nodes.WalkNodes(nn, func(nd nodes.Node, entering bool) (nodes.Node, error) {
+		if !entering {
+			return nd, nil
+		}
+		if n++; n > max {
+			return nd, fmt.Errorf("more than %d content nodes", max)
+		}
+		if n%1000 == 0 {
+			return nd, ctx.Err()
+		}
+		return nd, nil
+	})
+	return err
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLimitSize(t *testing.T) {
+	tests := []struct {
+		name string
+		max  int64
+		ok   bool
+	}{
+		{"under", 10, true},
+		{"exact", 5, true},
+		{"over", 4, false},
+		{"unlimited", 0, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := ioutil.ReadAll(limitSize(ioutil.NopCloser(strings.NewReader("hello")), "source", tc.max))
+			if tc.ok != (err == nil) {
+				t.Fatalf("read err = %v; want ok %v", err, tc.ok)
+			}
+			if tc.ok && string(b) != "hello" {
+				t.Errorf("read %q; want %q", b, "hello")
+			}
+		})
+	}
+}
+
+func TestSlurpCodelabHardened(t *testing.T) {
+	const head = "---\nid: lab\nsummary: s\n\n---\n\n# Lab\n\n## Step 1\n\n"
+	dir := writeFiles(t, map[string]string{
+		"plain.md":      head + "Some text.\n",
+		"include.md":    head + "```include /etc/passwd\n```\n",
+		"attachment.md": head + "<ql-file-download url=\"/etc/passwd\">\n</ql-file-download>\n",
+		"lab.json":      `{"sources": ["plain.md"]}`,
+		"remote.md":     head + "![meta](http://169.254.169.254/latest/meta-data)\n",
+		"dark.md":       head + "![meta {dark http://169.254.169.254/dark.png}](local.png)\n",
+		"gdoc.md":       head + "![doc](https://lh3.googleusercontent.com/abc)\n",
+	})
+	tests := []struct {
+		src      string
+		maxSize  int64
+		maxNodes int
+		err      string
+	}{
+		{"plain.md", 0, 0, ""},
+		{"plain.md", 10, 0, "source larger than 10 bytes"},
+		{"plain.md", 0, 1, "more than 1 content nodes"},
+		{"include.md", 0, 0, "code includes are not allowed"},
+		{"attachment.md", 0, 0, "attachments are not allowed"},
+		{"lab.json", 0, 0, "manifests are not allowed"},
+		{"remote.md", 0, 0, "remote images are not allowed"},
+		{"dark.md", 0, 0, "remote images are not allowed"},
+		{"gdoc.md", 0, 0, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.src, func(t *testing.T) {
+			f, err := NewFetcher("", nil, nil, FetcherOptions{MaxSize: tc.maxSize, MaxNodes: tc.maxNodes, NoExternal: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = f.SlurpCodelab(context.Background(), filepath.Join(dir, tc.src), "-")
+			if tc.err == "" {
+				if err != nil {
+					t.Errorf("SlurpCodelab: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("SlurpCodelab err = %v; want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestSlurpImagesMaxSize(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"lab.md": "---\nid: lab\nsummary: s\n\n---\n\n# Lab\n\n## Step 1\n\n![big](big.png)\n",
+		"big.png": strings.Repeat("x", 1000),
+	})
+	f, err := NewFetcher("", nil, nil, FetcherOptions{MaxSize: 500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.SlurpCodelab(context.Background(), filepath.Join(dir, "lab.md"), t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "larger than 500 bytes") {
+		t.Errorf("SlurpCodelab err = %v; want larger than 500 bytes", err)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// NotionPrefix prefixes a Notion page ID in a source argument,
+	// e.g. notion://0123456789abcdef0123456789abcdef.
+	NotionPrefix = "notion://"
+	// NotionTokenEnv is the environment variable holding
+	// a Notion integration token.
+	NotionTokenEnv = "NOTION_TOKEN"
+
+	// notionAPI is a base URL for Notion API
+	notionAPI = "https://api.notion.com/v1"
+	// notionVersion is the Notion API version the notion parser understands.
+	notionVersion = "2022-06-28"
+)
+
+// notionIDRegexp matches the page ID at the end of a Notion page URL path.
+var notionIDRegexp = regexp.MustCompile(`([0-9a-f]{32}|[0-9a-f-]{36})$`)
+
+// isNotionSource reports whether src is a Notion page,
+// either as notion://pageID or a notion.so or notion.site page URL.
+func isNotionSource(src string) bool {
+	if strings.HasPrefix(src, NotionPrefix) {
+		return true
+	}
+	u, err := url.Parse(src)
+	if err != nil {
+		return false
+	}
+	h := u.Hostname()
+	return h == "notion.so" || h == "www.notion.so" || strings.HasSuffix(h, ".notion.site")
+}
+
+// notionPageID extracts page ID from a Notion source.
+func notionPageID(src string) (string, error) {
+	if strings.HasPrefix(src, NotionPrefix) {
+		return strings.Trim(strings.TrimPrefix(src, NotionPrefix), "/"), nil
+	}
+	u, err := url.Parse(src)
+	if err != nil {
+		return "", err
+	}
+	id := notionIDRegexp.FindString(strings.TrimSuffix(u.Path, "/"))
+	if id == "" {
+		return "", fmt.Errorf("%s: no Notion page ID found", src)
+	}
+	return id, nil
+}
+
+// fetchNotion retrieves a Notion page and all its blocks, recursively,
+// from the Notion API, using the integration token of NotionTokenEnv.
+// The resulting resource body is the JSON document expected by
+// the notion parser.
+func (f *Fetcher) fetchNotion(ctx context.Context, src string) (*Resource, error) {
+	page, err := f.notionPage(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	id, _ := notionPageID(src)
+	blocks, err := f.notionChildren(ctx, f.notionClient(), id)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(map[string]interface{}{"page": page, "blocks": blocks})
+	if err != nil {
+		return nil, err
+	}
+	rev, _ := page["last_edited_time"].(string)
+	mod, _ := time.Parse(time.RFC3339, rev)
+	return &Resource{
+		Body: ioutil.NopCloser(bytes.NewReader(b)),
+		Mod:  mod,
+		Rev:  rev,
+		Type: SrcNotion,
+	}, nil
+}
+
+// notionPage retrieves the properties of Notion page src.
+func (f *Fetcher) notionPage(ctx context.Context, src string) (map[string]interface{}, error) {
+	if os.Getenv(NotionTokenEnv) == "" {
+		return nil, fmt.Errorf("%s: %s environment variable is not set", src, NotionTokenEnv)
+	}
+	id, err := notionPageID(src)
+	if err != nil {
+		return nil, err
+	}
+	res, err := f.retryGet(ctx, f.notionClient(), fmt.Sprintf("%s/pages/%s", notionAPI, id), 3)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	var page map[string]interface{}
+	if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// notionClient returns a client of the Notion API, authorized with
+// the integration token of NotionTokenEnv.
+func (f *Fetcher) notionClient() *http.Client {
+	h := http.Header{}
+	h.Set("Authorization", "Bearer "+os.Getenv(NotionTokenEnv))
+	h.Set("Notion-Version", notionVersion)
+	return &http.Client{Transport: &headerTransport{header: h, base: f.roundTripper}}
+}
+
+// notionChildren retrieves child blocks of block id, following all result pages,
+// and recursively sets "children" of the blocks which have any.
+// Child pages and databases are not descended into.
+func (f *Fetcher) notionChildren(ctx context.Context, client *http.Client, id string) ([]map[string]interface{}, error) {
+	var blocks []map[string]interface{}
+	var cursor string
+	for {
+		q := url.Values{"page_size": {"100"}}
+		if cursor != "" {
+			q.Set("start_cursor", cursor)
+		}
+		res, err := f.retryGet(ctx, client, fmt.Sprintf("%s/blocks/%s/children?%s", notionAPI, id, q.Encode()), 3)
+		if err != nil {
+			return nil, err
+		}
+		var list struct {
+			Results    []map[string]interface{} `json:"results"`
+			HasMore    bool                     `json:"has_more"`
+			NextCursor string                   `json:"next_cursor"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&list)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range list.Results {
+			if has, _ := b["has_children"].(bool); !has {
+				continue
+			}
+			switch b["type"] {
+			case "child_page", "child_database":
+				continue
+			}
+			bid, ok := b["id"].(string)
+			if !ok {
+				return nil, errors.New("notion block without an id")
+			}
+			children, err := f.notionChildren(ctx, client, bid)
+			if err != nil {
+				return nil, err
+			}
+			b["children"] = children
+		}
+		blocks = append(blocks, list.Results...)
+		if !list.HasMore || list.NextCursor == "" {
+			return blocks, nil
+		}
+		cursor = list.NextCursor
+	}
+}
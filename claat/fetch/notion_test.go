@@ -0,0 +1,128 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNotionPageID(t *testing.T) {
+	tests := []struct{ in, out string }{
+		{"notion://0123456789abcdef0123456789abcdef", "0123456789abcdef0123456789abcdef"},
+		{"https://www.notion.so/team/My-Lab-0123456789abcdef0123456789abcdef", "0123456789abcdef0123456789abcdef"},
+		{"https://team.notion.site/My-Lab-0123456789abcdef0123456789abcdef?pvs=4", "0123456789abcdef0123456789abcdef"},
+	}
+	for _, test := range tests {
+		if !isNotionSource(test.in) {
+			t.Errorf("isNotionSource(%q) = false; want true", test.in)
+		}
+		out, err := notionPageID(test.in)
+		if err != nil {
+			t.Errorf("notionPageID(%q): %v", test.in, err)
+		}
+		if out != test.out {
+			t.Errorf("notionPageID(%q) = %q; want %q", test.in, out, test.out)
+		}
+	}
+	for _, src := range []string{"codelab.md", "https://docs.google.com/document/d/foo", "https://example.com/abc"} {
+		if isNotionSource(src) {
+			t.Errorf("isNotionSource(%q) = true; want false", src)
+		}
+	}
+}
+
+func TestFetchNotion(t *testing.T) {
+	t.Setenv(NotionTokenEnv, "secret")
+	responses := map[string]string{
+		"/v1/pages/p1": `{"id": "p1", "last_edited_time": "2026-01-02T03:04:05.000Z"}`,
+		"/v1/blocks/p1/children?page_size=100": `{"has_more": true, "next_cursor": "c2", "results": [
+			{"id": "b1", "type": "paragraph", "has_children": false}
+		]}`,
+		"/v1/blocks/p1/children?page_size=100&start_cursor=c2": `{"results": [
+			{"id": "b2", "type": "toggle", "has_children": true},
+			{"id": "b3", "type": "child_page", "has_children": true}
+		]}`,
+		"/v1/blocks/b2/children?page_size=100": `{"results": [{"id": "b4", "type": "paragraph"}]}`,
+	}
+	rt := &testTransport{func(r *http.Request) (*http.Response, error) {
+		if v := r.Header.Get("Authorization"); v != "Bearer secret" {
+			t.Errorf("Authorization = %q", v)
+		}
+		if v := r.Header.Get("Notion-Version"); v != notionVersion {
+			t.Errorf("Notion-Version = %q", v)
+		}
+		body, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			t.Errorf("unexpected request %s", r.URL)
+			return &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	}}
+	f, err := NewFetcher("", nil, rt, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := f.fetch(context.Background(), "notion://p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.Type != SrcNotion {
+		t.Errorf("typ = %q; want %q", res.Type, SrcNotion)
+	}
+	if got := res.Mod.Format("2006-01-02"); got != "2026-01-02" {
+		t.Errorf("mod = %v", res.Mod)
+	}
+	var doc struct {
+		Blocks []struct {
+			ID       string `json:"id"`
+			Children []struct {
+				ID string `json:"id"`
+			} `json:"children"`
+		} `json:"blocks"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, b := range doc.Blocks {
+		got = append(got, b.ID)
+		for _, c := range b.Children {
+			got = append(got, b.ID+"/"+c.ID)
+		}
+	}
+	want := []string{"b1", "b2", "b2/b4", "b3"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("blocks got diff (-want +got): %s", diff)
+	}
+}
+
+func TestFetchNotionNoToken(t *testing.T) {
+	t.Setenv(NotionTokenEnv, "")
+	f, err := NewFetcher("", nil, nil, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.fetch(context.Background(), "notion://p1"); err == nil {
+		t.Errorf("fetch() without %s returned nil error", NotionTokenEnv)
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRetryBackoff is the delay before the first retry of a fetch.
+	DefaultRetryBackoff = 2 * time.Second
+	// DefaultMaxRetryBackoff is the longest delay between retries of a fetch.
+	DefaultMaxRetryBackoff = time.Minute
+)
+
+// RetryPolicy configures how remote fetches are retried.
+// The zero value retries Google Drive and Docs requests up to 7 times,
+// other requests up to 3 times, with the default backoff.
+type RetryPolicy struct {
+	// Retries is the maximum number of retries of a fetch.
+	// Zero means the default of the fetch, negative disables retries.
+	Retries int
+	// Backoff is the delay before the first retry, doubled for each next retry,
+	// plus a random jitter of up to half of it.
+	// Zero means DefaultRetryBackoff.
+	Backoff time.Duration
+	// MaxBackoff is the longest delay between retries,
+	// including delays requested by servers with a Retry-After header.
+	// Zero means DefaultMaxRetryBackoff.
+	MaxBackoff time.Duration
+}
+
+// StatusError is the error of a fetch which got an unexpected HTTP response.
+type StatusError struct {
+	URL        string
+	Status     string // e.g. "404 Not Found"
+	StatusCode int
+	Body       []byte // beginning of the response body
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("fetch %s: %s; %s", e.URL, e.Status, e.Body)
+}
+
+// Temporary reports whether retrying the fetch may succeed:
+// rate limit and server errors are temporary, other errors are permanent.
+func (e *StatusError) Temporary() bool {
+	return retryable(e.StatusCode, e.Body)
+}
+
+// retryable reports whether a response of status code with body
+// is worth retrying: 429, 5xx, and 403 rate limit errors of Google APIs.
+func retryable(code int, body []byte) bool {
+	if code == http.StatusTooManyRequests || code >= http.StatusInternalServerError {
+		return true
+	}
+	if code != http.StatusForbidden {
+		return false
+	}
+	var erres struct {
+		Error struct {
+			Errors []struct{ Reason string }
+		}
+	}
+	json.Unmarshal(body, &erres)
+	for _, e := range erres.Error.Errors {
+		if e.Reason == "rateLimitExceeded" || e.Reason == "userRateLimitExceeded" {
+			return true
+		}
+	}
+	return false
+}
+
+// retries returns the number of retries of a fetch whose default is n.
+func (p RetryPolicy) retries(n int) int {
+	switch {
+	case p.Retries < 0:
+		return 0
+	case p.Retries > 0:
+		return p.Retries
+	}
+	return n
+}
+
+// backoff returns the delay before retry i, starting at 1,
+// or after, if longer, as requested by the server.
+func (p RetryPolicy) backoff(i int, after time.Duration) time.Duration {
+	base, max := p.Backoff, p.MaxBackoff
+	if base <= 0 {
+		base = DefaultRetryBackoff
+	}
+	if max <= 0 {
+		max = DefaultMaxRetryBackoff
+	}
+	d := max
+	if i < 32 && base<<uint(i-1) < max {
+		d = base << uint(i-1)
+	}
+	d += time.Duration(rand.Int63n(int64(base/2) + 1))
+	if after > d {
+		d = after
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// get tries to GET url with client, up to the retries of p, or n by default.
+// Temporary failures are retried with exponential backoff,
+// permanent ones fail with a *StatusError right away.
+// Requests to Google APIs wait for the global rate limit, if any.
+// Default client will be used if not provided.
+// It stops with the error of ctx once it is done.
+func (p RetryPolicy) get(ctx context.Context, client *http.Client, url string, n int) (*http.Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	n = p.retries(n)
+	var after time.Duration // requested by the last response
+	var lastErr error
+	for i := 0; i <= n; i++ {
+		if i > 0 {
+			select {
+			case <-time.After(p.backoff(i, after)):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if isGoogleAPI(url) {
+			if err := googleLimiter.wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		res, err := client.Do(req)
+		if ctx.Err() != nil {
+			if err == nil {
+				res.Body.Close()
+			}
+			return nil, ctx.Err()
+		}
+		if errors.Is(err, ErrNotRecorded) {
+			return nil, err
+		}
+		// sometimes Drive API wouldn't even start a response,
+		// we get net/http: TLS handshake timeout instead:
+		// consider this a temporary failure and retry again
+		if err != nil {
+			lastErr, after = err, 0
+			continue
+		}
+		if res.StatusCode == http.StatusOK {
+			return res, nil
+		}
+		b, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		serr := &StatusError{URL: url, Status: res.Status, StatusCode: res.StatusCode, Body: b}
+		if !serr.Temporary() {
+			return nil, serr
+		}
+		lastErr, after = serr, retryAfter(res.Header)
+	}
+	if n == 0 {
+		return nil, lastErr
+	}
+	return nil, fmt.Errorf("%s: failed after %d retries: %v", url, n, lastErr)
+}
+
+// retryAfter returns the delay of a Retry-After header h,
+// in seconds or as an HTTP date, or zero if there is none.
+func retryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// isGoogleAPI reports whether u is a request to a Google API,
+// which counts against the quotas of Google Drive and Docs.
+func isGoogleAPI(u string) bool {
+	pu, err := url.Parse(u)
+	return err == nil && strings.HasSuffix(pu.Hostname(), ".googleapis.com")
+}
+
+// googleLimiter limits the rate of all requests to Google APIs,
+// across fetchers. See SetRateLimit.
+var googleLimiter = &rateLimiter{}
+
+// SetRateLimit limits requests to Google APIs, such as Google Docs exports,
+// to qps requests per second across all fetches of the process,
+// so that batch exports do not exceed API quotas.
+// Zero or negative qps removes the limit.
+func SetRateLimit(qps float64) {
+	googleLimiter.setRate(qps)
+}
+
+// rateLimiter spaces out events at a maximum rate.
+// Its zero value has no limit.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // between events, or 0 for no limit
+	next     time.Time     // earliest time of the next event
+	now      func() time.Time
+}
+
+// setRate sets the maximum rate to qps events per second.
+func (l *rateLimiter) setRate(qps float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.interval = 0
+	if qps > 0 {
+		l.interval = time.Duration(float64(time.Second) / qps)
+	}
+}
+
+// reserve reserves the next event and returns how long to wait for it.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.interval == 0 {
+		return 0
+	}
+	now := time.Now()
+	if l.now != nil {
+		now = l.now()
+	}
+	if l.next.Before(now) {
+		l.next = now
+	}
+	d := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	return d
+}
+
+// wait blocks until the next event may happen, or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	d := l.reserve()
+	if d == 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
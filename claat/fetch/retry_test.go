@@ -0,0 +1,228 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testResponse returns a response of status code with body.
+func testResponse(code int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Status:     http.StatusText(code),
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		code int
+		body string
+		want bool
+	}{
+		{http.StatusTooManyRequests, "", true},
+		{http.StatusInternalServerError, "", true},
+		{http.StatusServiceUnavailable, "", true},
+		{http.StatusForbidden, `{"error": {"errors": [{"reason": "userRateLimitExceeded"}]}}`, true},
+		{http.StatusForbidden, `{"error": {"errors": [{"reason": "insufficientPermissions"}]}}`, false},
+		{http.StatusNotFound, "", false},
+		{http.StatusBadRequest, "", false},
+	}
+	for _, tc := range tests {
+		if got := retryable(tc.code, []byte(tc.body)); got != tc.want {
+			t.Errorf("retryable(%d, %q) = %v; want %v", tc.code, tc.body, got, tc.want)
+		}
+	}
+}
+
+func TestRetryPolicyGet(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    RetryPolicy
+		responses []int // status codes of consecutive responses, 0 for a network error
+		calls     int
+		ok        bool
+		permanent bool
+	}{
+		{name: "OK", responses: []int{200}, calls: 1, ok: true},
+		{name: "RetryServerError", responses: []int{503, 0, 200}, calls: 3, ok: true},
+		{name: "RetryRateLimit", responses: []int{429, 200}, calls: 2, ok: true},
+		{name: "Permanent", responses: []int{404, 200}, calls: 1, permanent: true},
+		{name: "Exhausted", responses: []int{500, 500, 500, 500, 500}, calls: 4},
+		{name: "Retries", policy: RetryPolicy{Retries: 1}, responses: []int{500, 500, 200}, calls: 2},
+		{name: "NoRetries", policy: RetryPolicy{Retries: -1}, responses: []int{500, 200}, calls: 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+			client := &http.Client{Transport: &testTransport{func(r *http.Request) (*http.Response, error) {
+				code := tc.responses[calls]
+				calls++
+				if code == 0 {
+					return nil, errors.New("TLS handshake timeout")
+				}
+				return testResponse(code, ""), nil
+			}}}
+			p := tc.policy
+			p.Backoff = time.Millisecond
+			res, err := p.get(context.Background(), client, "https://example.com/doc", 3)
+			if calls != tc.calls {
+				t.Errorf("get() made %d requests; want %d", calls, tc.calls)
+			}
+			if tc.ok != (err == nil) {
+				t.Fatalf("get() = %v; want ok %v", err, tc.ok)
+			}
+			if err == nil {
+				res.Body.Close()
+			}
+			var serr *StatusError
+			if permanent := errors.As(err, &serr) && !serr.Temporary(); permanent != tc.permanent {
+				t.Errorf("get() = %v; want permanent %v", err, tc.permanent)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{Backoff: time.Second, MaxBackoff: 10 * time.Second}
+	tests := []struct {
+		i     int
+		after time.Duration
+		min   time.Duration
+		max   time.Duration
+	}{
+		{1, 0, time.Second, 1500 * time.Millisecond},
+		{3, 0, 4 * time.Second, 4500 * time.Millisecond},
+		{5, 0, 10 * time.Second, 10 * time.Second},
+		{40, 0, 10 * time.Second, 10 * time.Second},
+		{1, 5 * time.Second, 5 * time.Second, 5 * time.Second},
+		{1, time.Hour, 10 * time.Second, 10 * time.Second},
+	}
+	for _, tc := range tests {
+		if d := p.backoff(tc.i, tc.after); d < tc.min || d > tc.max {
+			t.Errorf("backoff(%d, %v) = %v; want within [%v, %v]", tc.i, tc.after, d, tc.min, tc.max)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"soon", 0},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0}, // in the past
+	}
+	for _, tc := range tests {
+		h := http.Header{"Retry-After": {tc.in}}
+		if got := retryAfter(h); got > tc.want {
+			t.Errorf("retryAfter(%q) = %v; want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestRetryPolicyGetContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		cancel  bool // cancel during the request
+		want    error
+	}{
+		{name: "Canceled", cancel: true, want: context.Canceled},
+		{name: "DeadlineInBackoff", timeout: 10 * time.Millisecond, want: context.DeadlineExceeded},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if tc.timeout > 0 {
+				ctx, cancel = context.WithTimeout(context.Background(), tc.timeout)
+			}
+			defer cancel()
+			var calls int
+			client := &http.Client{Transport: &testTransport{func(r *http.Request) (*http.Response, error) {
+				calls++
+				if tc.cancel {
+					cancel()
+				}
+				return nil, errors.New("TLS handshake timeout")
+			}}}
+			start := time.Now()
+			res, err := RetryPolicy{}.get(ctx, client, "https://example.com/doc", 3)
+			if res != nil || err != tc.want {
+				t.Errorf("get() = %v, %v; want nil, %v", res, err, tc.want)
+			}
+			if calls != 1 {
+				t.Errorf("get() made %d requests; want 1", calls)
+			}
+			if d := time.Since(start); d > time.Second {
+				t.Errorf("get() took %v to stop", d)
+			}
+		})
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := &rateLimiter{now: func() time.Time { return now }}
+	if d := l.reserve(); d != 0 {
+		t.Errorf("reserve() without limit = %v; want 0", d)
+	}
+	l.setRate(4)
+	var got []time.Duration
+	for i := 0; i < 3; i++ {
+		got = append(got, l.reserve())
+	}
+	want := []time.Duration{0, 250 * time.Millisecond, 500 * time.Millisecond}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("reserve() #%d = %v; want %v", i, got[i], want[i])
+		}
+	}
+	// an idle limiter does not accumulate a burst
+	now = now.Add(time.Minute)
+	if d1, d2 := l.reserve(), l.reserve(); d1 != 0 || d2 != 250*time.Millisecond {
+		t.Errorf("reserve() after idle = %v, %v; want 0, 250ms", d1, d2)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.wait(ctx); err != context.Canceled {
+		t.Errorf("wait() canceled = %v; want %v", err, context.Canceled)
+	}
+}
+
+func TestIsGoogleAPI(t *testing.T) {
+	tests := map[string]bool{
+		"https://www.googleapis.com/drive/v3/files/abc":  true,
+		"https://docs.googleapis.com/v1/documents/abc":   true,
+		"https://lh3.googleusercontent.com/image.png":    false,
+		"https://example.com/googleapis.com/fragment.md": false,
+	}
+	for u, want := range tests {
+		if got := isGoogleAPI(u); got != want {
+			t.Errorf("isGoogleAPI(%q) = %v; want %v", u, got, want)
+		}
+	}
+}
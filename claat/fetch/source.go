@@ -0,0 +1,318 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/googlecodelabs/tools/claat/util"
+)
+
+// Source is a backend of codelab sources, such as Google Docs or local files.
+// The Fetcher selects the Source of a codelab by the URI scheme of its source,
+// as registered with RegisterSource, so that new content sources need
+// no change to the exporter.
+type Source interface {
+	// Resolve returns the codelabs of src: src itself, or those of
+	// a collection, such as the docs of a Drive folder. If match is
+	// not empty, only codelabs of collections whose name matches
+	// the glob pattern are returned.
+	Resolve(ctx context.Context, f *Fetcher, src, match string) ([]*Entry, error)
+	// Fetch retrieves the content of codelab src.
+	// The caller is responsible for closing its body.
+	Fetch(ctx context.Context, f *Fetcher, src string) (*Resource, error)
+	// Revision returns an identifier of the current content of src,
+	// which changes whenever its content does, such as a version number
+	// or a modification time.
+	Revision(ctx context.Context, f *Fetcher, src string) (string, error)
+}
+
+// localSource is implemented by Sources which fetch codelabs to local files,
+// such as checkouts of git repositories, so that the relative references of
+// codelabs, such as images and imports, are resolved as those of local files.
+type localSource interface {
+	// Local returns the local file of src, along with its revision.
+	Local(ctx context.Context, f *Fetcher, src string) (file, rev string, err error)
+}
+
+// Entry is a codelab resolved from a source by Source.Resolve.
+type Entry struct {
+	Src string // codelab source
+	Dir string // slash-separated directory of Src in its collection, if any
+}
+
+// Schemes of the built-in sources. Sources without a scheme are local
+// files if they exist, files of git repositories if they are of the form
+// host/owner/repo//path/to/lab.md@ref, Google Docs if they are an ID or
+// a docs.google.com URL, and HTTP URLs otherwise.
+const (
+	SchemeDriveFolder = "drive"
+	SchemeFile        = "file"
+	SchemeGit         = "git"
+	SchemeGoogleDoc   = "gdoc"
+	SchemeHTTP        = "http"
+	SchemeNotion      = "notion"
+)
+
+var (
+	sourcesMu sync.RWMutex
+	sources   = map[string]Source{
+		SchemeDriveFolder: driveFolderSource{},
+		SchemeFile:        fileSource{},
+		SchemeGit:         gitSources{},
+		SchemeGoogleDoc:   gdocSource{},
+		SchemeHTTP:        httpSource{},
+		"https":           httpSource{},
+		SchemeNotion:      notionSource{},
+	}
+)
+
+// RegisterSource makes s the Source of codelab sources with URI scheme,
+// such as scheme://path, replacing the Source of scheme if any.
+func RegisterSource(scheme string, s Source) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	sources[strings.ToLower(scheme)] = s
+}
+
+// SourceScheme returns the scheme of the Source of codelab src.
+func SourceScheme(src string) string {
+	u, err := url.Parse(src)
+	if err == nil && u.Scheme != "" {
+		sourcesMu.RLock()
+		_, ok := sources[strings.ToLower(u.Scheme)]
+		sourcesMu.RUnlock()
+		if ok {
+			if isNotionSource(src) {
+				return SchemeNotion
+			}
+			if strings.ToLower(u.Host) == "docs.google.com" {
+				return SchemeGoogleDoc
+			}
+			return strings.ToLower(u.Scheme)
+		}
+	}
+	if isLocal(src) {
+		return SchemeFile
+	}
+	if _, ok := util.ParseGitSource(src); ok {
+		return SchemeGit
+	}
+	if err == nil && u.Host == "" {
+		return SchemeGoogleDoc
+	}
+	return SchemeHTTP
+}
+
+// googleSource reports whether src is fetched by a built-in Source
+// with the Google authorization of the Fetcher, rather than from disk,
+// with other credentials or by a registered Source.
+func googleSource(src string) bool {
+	switch SourceScheme(src) {
+	case SchemeGoogleDoc, SchemeHTTP, "https":
+		return true
+	}
+	return false
+}
+
+// source returns the Source of codelab src.
+func source(src string) Source {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	return sources[SourceScheme(src)]
+}
+
+// Resolve returns the codelabs of src, with the Source of its scheme.
+// See Source.Resolve.
+func (f *Fetcher) Resolve(ctx context.Context, src, match string) ([]*Entry, error) {
+	return source(src).Resolve(ctx, f, src, match)
+}
+
+// Revision returns the current revision of codelab src,
+// with the Source of its scheme. See Source.Revision.
+// The revision of a Manifest covers those of its parts.
+func (f *Fetcher) Revision(ctx context.Context, src string) (string, error) {
+	if IsManifest(src) {
+		return f.manifestRevision(ctx, src)
+	}
+	return source(src).Revision(ctx, f, src)
+}
+
+// fetch retrieves codelab doc with the Source of its scheme,
+// either from local disk or a remote location.
+// The caller is responsible for closing returned stream.
+func (f *Fetcher) fetch(ctx context.Context, name string) (*Resource, error) {
+	res, err := source(name).Fetch(ctx, f, name)
+	if err == nil {
+		f.inputs.add(name, res.Rev)
+	}
+	return res, err
+}
+
+// single returns src as the only codelab of a Resolve.
+func single(src string) []*Entry {
+	return []*Entry{{Src: src}}
+}
+
+// fileSource is the Source of local files, as paths or file:// URLs.
+type fileSource struct{}
+
+func (fileSource) Resolve(ctx context.Context, f *Fetcher, src, match string) ([]*Entry, error) {
+	return single(src), nil
+}
+
+func (fileSource) Fetch(ctx context.Context, f *Fetcher, src string) (*Resource, error) {
+	name := filePath(src)
+	fi, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	r, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Resource{
+		Body: r,
+		Type: fileSrcType(name),
+		Mod:  fi.ModTime(),
+		Rev:  revisionTime(fi.ModTime()),
+	}, nil
+}
+
+// Revision returns the modification time of the file.
+func (fileSource) Revision(ctx context.Context, f *Fetcher, src string) (string, error) {
+	fi, err := os.Stat(filePath(src))
+	if err != nil {
+		return "", err
+	}
+	return revisionTime(fi.ModTime()), nil
+}
+
+// filePath returns the local path of file source src.
+func filePath(src string) string {
+	if u, err := url.Parse(src); err == nil && u.Scheme == SchemeFile {
+		return u.Path
+	}
+	return src
+}
+
+// gdocSource is the Source of Google Docs, as IDs or docs.google.com URLs.
+type gdocSource struct{}
+
+func (gdocSource) Resolve(ctx context.Context, f *Fetcher, src, match string) ([]*Entry, error) {
+	return single(src), nil
+}
+
+func (gdocSource) Fetch(ctx context.Context, f *Fetcher, src string) (*Resource, error) {
+	return f.fetchDriveFile(ctx, src, false)
+}
+
+// Revision returns the modification time of the doc in Drive.
+func (gdocSource) Revision(ctx context.Context, f *Fetcher, src string) (string, error) {
+	if err := f.initAuth(); err != nil {
+		return "", err
+	}
+	meta, err := f.driveDocMeta(ctx, gdocID(src))
+	if err != nil {
+		return "", err
+	}
+	return revisionTime(meta.Modified), nil
+}
+
+// httpSource is the Source of files at HTTP URLs.
+type httpSource struct{}
+
+func (httpSource) Resolve(ctx context.Context, f *Fetcher, src, match string) ([]*Entry, error) {
+	return single(src), nil
+}
+
+func (httpSource) Fetch(ctx context.Context, f *Fetcher, src string) (*Resource, error) {
+	return f.fetchRemoteFile(ctx, src)
+}
+
+// Revision returns the ETag of the file if the server sets one,
+// or the SHA-256 hash of its content.
+func (httpSource) Revision(ctx context.Context, f *Fetcher, src string) (string, error) {
+	res, err := f.retryGet(ctx, f.client(), src, 3)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if etag := res.Header.Get("ETag"); etag != "" {
+		return etag, nil
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, res.Body); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// driveFolderSource is the Source of the docs of Drive folders,
+// as drive://folderID sources.
+type driveFolderSource struct{}
+
+// Resolve returns the docs of the folder and its subfolders,
+// in the directories mirroring the folder structure.
+func (driveFolderSource) Resolve(ctx context.Context, f *Fetcher, src, match string) ([]*Entry, error) {
+	docs, err := f.DriveFolderDocs(ctx, src, match)
+	if err != nil {
+		return nil, err
+	}
+	ee := make([]*Entry, len(docs))
+	for i, d := range docs {
+		ee[i] = &Entry{Src: d.ID, Dir: d.Dir}
+	}
+	return ee, nil
+}
+
+func (driveFolderSource) Fetch(ctx context.Context, f *Fetcher, src string) (*Resource, error) {
+	return nil, fmt.Errorf("%s: cannot fetch a folder, only its docs", src)
+}
+
+func (driveFolderSource) Revision(ctx context.Context, f *Fetcher, src string) (string, error) {
+	return "", fmt.Errorf("%s: folders have no revision, only their docs", src)
+}
+
+// notionSource is the Source of Notion pages.
+type notionSource struct{}
+
+func (notionSource) Resolve(ctx context.Context, f *Fetcher, src, match string) ([]*Entry, error) {
+	return single(src), nil
+}
+
+func (notionSource) Fetch(ctx context.Context, f *Fetcher, src string) (*Resource, error) {
+	return f.fetchNotion(ctx, src)
+}
+
+// Revision returns the last edit time of the page.
+func (notionSource) Revision(ctx context.Context, f *Fetcher, src string) (string, error) {
+	page, err := f.notionPage(ctx, src)
+	if err != nil {
+		return "", err
+	}
+	t, ok := page["last_edited_time"].(string)
+	if !ok {
+		return "", fmt.Errorf("%s: no last edit time", src)
+	}
+	return t, nil
+}
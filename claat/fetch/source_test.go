@@ -0,0 +1,137 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSourceScheme(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"lab.md": "# Lab\n"})
+	local := filepath.Join(dir, "lab.md")
+	tests := []struct{ src, want string }{
+		{local, SchemeFile},
+		{"file://" + local, SchemeFile},
+		{"1aBcD-eFgH", SchemeGoogleDoc},
+		{"https://docs.google.com/document/d/1aBcD/edit", SchemeGoogleDoc},
+		{"gdoc://1aBcD", SchemeGoogleDoc},
+		{"https://example.com/lab.md", "https"},
+		{"http://example.com/lab.md", SchemeHTTP},
+		{"drive://0B1x2y3z", SchemeDriveFolder},
+		{"notion://0123456789abcdef0123456789abcdef", SchemeNotion},
+		{"https://www.notion.so/Lab-0123456789abcdef0123456789abcdef", SchemeNotion},
+	}
+	for _, tc := range tests {
+		if got := SourceScheme(tc.src); got != tc.want {
+			t.Errorf("SourceScheme(%q) = %q; want %q", tc.src, got, tc.want)
+		}
+	}
+}
+
+// memSource is a Source of codelabs held in memory, by name.
+type memSource map[string]string
+
+func (s memSource) Resolve(ctx context.Context, f *Fetcher, src, match string) ([]*Entry, error) {
+	if src != "mem://all" {
+		return single(src), nil
+	}
+	return []*Entry{{Src: "mem://a", Dir: "x"}, {Src: "mem://b", Dir: "y"}}, nil
+}
+
+func (s memSource) Fetch(ctx context.Context, f *Fetcher, src string) (*Resource, error) {
+	return &Resource{
+		Body: ioutil.NopCloser(strings.NewReader(s[src])),
+		Type: SrcMarkdown,
+		Mod:  time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+func (s memSource) Revision(ctx context.Context, f *Fetcher, src string) (string, error) {
+	return "r1", nil
+}
+
+func TestRegisterSource(t *testing.T) {
+	RegisterSource("mem", memSource{"mem://a": "---\nid: mem-lab\nsummary: s\n\n---\n\n# Mem\n\n## Step 1\n\nText.\n"})
+	f, err := NewFetcher("", nil, nil, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clab, err := f.SlurpCodelab(context.Background(), "mem://a", "-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clab.ID != "mem-lab" || clab.Mod.Day() != 2 {
+		t.Errorf("SlurpCodelab = %q modified %v; want mem-lab modified 2026-01-02", clab.ID, clab.Mod)
+	}
+	ee, err := f.Resolve(context.Background(), "mem://all", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Entry{{Src: "mem://a", Dir: "x"}, {Src: "mem://b", Dir: "y"}}
+	if diff := cmp.Diff(want, ee); diff != "" {
+		t.Errorf("Resolve got diff (-want +got):\n%s", diff)
+	}
+	if rev, err := f.Revision(context.Background(), "mem://a"); err != nil || rev != "r1" {
+		t.Errorf("Revision = %q, %v; want r1", rev, err)
+	}
+}
+
+func TestRevision(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"lab.md": "# Lab\n"})
+	rt := &testTransport{func(r *http.Request) (*http.Response, error) {
+		h := http.Header{}
+		if r.URL.Path == "/etag.md" {
+			h.Set("ETag", `"abc"`)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     h,
+			Body:       ioutil.NopCloser(strings.NewReader("# Lab\n")),
+		}, nil
+	}}
+	f, err := NewFetcher("", nil, rt, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct{ src, want string }{
+		{"https://example.com/etag.md", `"abc"`},
+		{"https://example.com/lab.md", "a07550bcff3a0b4f9271f9eaf6abcb9b62636e8784134be164e2667080517a76"},
+	}
+	for _, tc := range tests {
+		if got, err := f.Revision(context.Background(), tc.src); err != nil || got != tc.want {
+			t.Errorf("Revision(%q) = %q, %v; want %q", tc.src, got, err, tc.want)
+		}
+	}
+
+	src := filepath.Join(dir, "lab.md")
+	rev, err := f.Revision(context.Background(), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, rev); err != nil {
+		t.Errorf("Revision(%q) = %q; want a modification time", src, rev)
+	}
+	if _, err := f.Revision(context.Background(), "drive://0B1x2y3z"); err == nil {
+		t.Error("Revision of a Drive folder succeeded; want an error")
+	}
+}
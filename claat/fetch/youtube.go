@@ -0,0 +1,100 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	// YouTubeKeyEnv is the environment variable holding
+	// a YouTube Data API key.
+	YouTubeKeyEnv = "YOUTUBE_API_KEY"
+
+	// youtubeAPI is a base URL for YouTube Data API
+	youtubeAPI = "https://www.googleapis.com/youtube/v3"
+)
+
+// isoDurationRegexp matches an ISO 8601 duration as used by YouTube, e.g. PT1H4M13S.
+var isoDurationRegexp = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// HasYouTubeKey reports whether a YouTube Data API key is set
+// in the YouTubeKeyEnv environment variable.
+func HasYouTubeKey() bool {
+	return os.Getenv(YouTubeKeyEnv) != ""
+}
+
+// VideoLength retrieves the length of YouTube video id with the YouTube Data API,
+// using the key of YouTubeKeyEnv.
+func (f *Fetcher) VideoLength(ctx context.Context, id string) (time.Duration, error) {
+	key := os.Getenv(YouTubeKeyEnv)
+	if key == "" {
+		return 0, fmt.Errorf("%s environment variable is not set", YouTubeKeyEnv)
+	}
+	q := url.Values{
+		"part": {"contentDetails"},
+		"id":   {id},
+	}
+	h := http.Header{}
+	h.Set("X-Goog-Api-Key", key)
+	client := &http.Client{Transport: &headerTransport{header: h, base: f.roundTripper}}
+	res, err := f.retryGet(ctx, client, fmt.Sprintf("%s/videos?%s", youtubeAPI, q.Encode()), 3)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	var v struct {
+		Items []struct {
+			ContentDetails struct {
+				Duration string `json:"duration"`
+			} `json:"contentDetails"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&v); err != nil {
+		return 0, err
+	}
+	if len(v.Items) == 0 {
+		return 0, fmt.Errorf("video %s not found", id)
+	}
+	return parseISODuration(v.Items[0].ContentDetails.Duration)
+}
+
+// parseISODuration parses an ISO 8601 duration of days, hours, minutes and seconds.
+func parseISODuration(s string) (time.Duration, error) {
+	m := isoDurationRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(n) * unit
+	}
+	return d, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseISODuration(t *testing.T) {
+	tests := []struct {
+		in  string
+		out time.Duration
+	}{
+		{"PT4M13S", 4*time.Minute + 13*time.Second},
+		{"PT1H", time.Hour},
+		{"P1DT2S", 24*time.Hour + 2*time.Second},
+		{"P0D", 0},
+	}
+	for _, test := range tests {
+		out, err := parseISODuration(test.in)
+		if err != nil {
+			t.Errorf("parseISODuration(%q): %v", test.in, err)
+		}
+		if out != test.out {
+			t.Errorf("parseISODuration(%q) = %v; want %v", test.in, out, test.out)
+		}
+	}
+	if _, err := parseISODuration("4:13"); err == nil {
+		t.Errorf("parseISODuration(%q) returned nil error", "4:13")
+	}
+}
+
+func TestVideoLength(t *testing.T) {
+	t.Setenv(YouTubeKeyEnv, "key")
+	rt := &testTransport{func(r *http.Request) (*http.Response, error) {
+		if k := r.Header.Get("X-Goog-Api-Key"); k != "key" {
+			t.Errorf("X-Goog-Api-Key = %q", k)
+		}
+		if id := r.URL.Query().Get("id"); id != "vid1" {
+			t.Errorf("id = %q", id)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"items": [{"contentDetails": {"duration": "PT2M5S"}}]}`)),
+		}, nil
+	}}
+	f, err := NewFetcher("", nil, rt, FetcherOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := f.VideoLength(context.Background(), "vid1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != 2*time.Minute+5*time.Second {
+		t.Errorf("VideoLength() = %v; want 2m5s", d)
+	}
+}
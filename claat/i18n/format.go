@@ -0,0 +1,110 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Catalog file formats.
+const (
+	FormatJSON  = "json"
+	FormatXLIFF = "xliff"
+)
+
+// xliff is an XLIFF 1.2 document with a single file.
+type xliff struct {
+	XMLName xml.Name  `xml:"urn:oasis:names:tc:xliff:document:1.2 xliff"`
+	Version string    `xml:"version,attr"`
+	File    xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	Original       string      `xml:"original,attr"`
+	SourceLanguage string      `xml:"source-language,attr"`
+	TargetLanguage string      `xml:"target-language,attr,omitempty"`
+	Datatype       string      `xml:"datatype,attr"`
+	Units          []xliffUnit `xml:"body>trans-unit"`
+}
+
+type xliffUnit struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source"`
+	Target string `xml:"target,omitempty"`
+}
+
+// Write writes c to w in the given format, FormatJSON or FormatXLIFF.
+func (c *Catalog) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(c)
+	case FormatXLIFF:
+		x := xliff{
+			Version: "1.2",
+			File: xliffFile{
+				Original:       c.ID,
+				SourceLanguage: c.SourceLocale,
+				TargetLanguage: c.TargetLocale,
+				Datatype:       "plaintext",
+			},
+		}
+		for _, m := range c.Messages {
+			x.File.Units = append(x.File.Units, xliffUnit{ID: m.Key, Source: m.Source, Target: m.Translation})
+		}
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		enc := xml.NewEncoder(w)
+		enc.Indent("", "  ")
+		if err := enc.Encode(x); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+	return fmt.Errorf("unknown catalog format %q", format)
+}
+
+// Read reads a catalog from r in the given format, FormatJSON or FormatXLIFF.
+func Read(r io.Reader, format string) (*Catalog, error) {
+	switch format {
+	case FormatJSON:
+		c := &Catalog{}
+		if err := json.NewDecoder(r).Decode(c); err != nil {
+			return nil, err
+		}
+		return c, nil
+	case FormatXLIFF:
+		var x xliff
+		if err := xml.NewDecoder(r).Decode(&x); err != nil {
+			return nil, err
+		}
+		c := &Catalog{
+			ID:           x.File.Original,
+			SourceLocale: x.File.SourceLanguage,
+			TargetLocale: x.File.TargetLanguage,
+		}
+		for _, u := range x.File.Units {
+			c.Messages = append(c.Messages, &Message{Key: u.ID, Source: u.Source, Translation: u.Target})
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("unknown catalog format %q", format)
+}
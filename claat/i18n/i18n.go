@@ -0,0 +1,222 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package i18n extracts translatable strings of a codelab into a catalog
+// and applies translated catalogs back to codelabs.
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// protectedRegexp matches markup which must be kept untouched by translations:
+// Qwiklabs ql-* elements and {{{...}}} runtime expressions.
+var protectedRegexp = regexp.MustCompile(`</?ql-[^>]*>|\{\{\{[^}]*\}\}\}`)
+
+// Catalog is a set of translatable strings of a codelab.
+type Catalog struct {
+	ID           string     `json:"id"`                     // Codelab ID
+	SourceLocale string     `json:"sourceLocale"`           // Locale of the source strings
+	TargetLocale string     `json:"targetLocale,omitempty"` // Locale of the translations
+	Messages     []*Message `json:"messages"`
+}
+
+// Message is a translatable string.
+//
+// Its key is the path of the string in the codelab: "title" and "summary"
+// for metadata, "3/title" for the title of step 3, and "3/0/2" for the text
+// node at index 2 of the children of the node at index 0 of step 3 content.
+// Children of items lists and tables are numbered consecutively,
+// item after item and cell after cell.
+type Message struct {
+	Key         string `json:"key"`
+	Source      string `json:"source"`
+	Translation string `json:"translation,omitempty"`
+}
+
+// Extract returns a catalog of all translatable strings of clab:
+// its title and summary, step titles and text, except code.
+// Strings consisting of protected markup only are left out.
+func Extract(clab *types.Codelab) *Catalog {
+	c := &Catalog{ID: clab.ID, SourceLocale: clab.LocaleOrDefault()}
+	add := func(key, s string) {
+		if translatable(s) {
+			c.Messages = append(c.Messages, &Message{Key: key, Source: s})
+		}
+	}
+	add("title", clab.Title)
+	add("summary", clab.Summary)
+	for i, st := range clab.Steps {
+		prefix := strconv.Itoa(i + 1)
+		add(prefix+"/title", st.Title)
+		if st.Content == nil {
+			continue
+		}
+		walkText(prefix, st.Content.Nodes, func(key string, t *nodes.TextNode) {
+			add(key, t.Value)
+		})
+	}
+	return c
+}
+
+// Apply replaces strings of clab with their translations in c
+// and sets the clab locale to c.TargetLocale.
+//
+// Messages without a translation are skipped. So are messages whose source
+// no longer matches clab, which are reported as warnings.
+// It returns an error if a translation does not keep the protected markup
+// of its source untouched.
+func (c *Catalog) Apply(clab *types.Codelab) ([]string, error) {
+	if c.TargetLocale == "" {
+		return nil, fmt.Errorf("catalog has no target locale")
+	}
+	tr := make(map[string]*Message, len(c.Messages))
+	var errs []string
+	for _, m := range c.Messages {
+		if m.Translation == "" {
+			continue
+		}
+		if !sameProtected(m.Source, m.Translation) {
+			errs = append(errs, fmt.Sprintf("%s: translation does not preserve markup %q", m.Key, protectedRegexp.FindAllString(m.Source, -1)))
+			continue
+		}
+		tr[m.Key] = m
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+
+	var warns []string
+	apply := func(key string, s *string) {
+		m, ok := tr[key]
+		if !ok {
+			return
+		}
+		if m.Source != *s {
+			warns = append(warns, fmt.Sprintf("%s: source has changed, translation skipped", key))
+			return
+		}
+		*s = m.Translation
+	}
+	apply("title", &clab.Title)
+	apply("summary", &clab.Summary)
+	for i, st := range clab.Steps {
+		prefix := strconv.Itoa(i + 1)
+		apply(prefix+"/title", &st.Title)
+		if st.Content == nil {
+			continue
+		}
+		walkText(prefix, st.Content.Nodes, func(key string, t *nodes.TextNode) {
+			apply(key, &t.Value)
+		})
+	}
+	clab.Locale = c.TargetLocale
+	sort.Strings(warns)
+	return warns, nil
+}
+
+// walkText calls fn for each non-code text node of nn, recursively,
+// with its key relative to prefix.
+func walkText(prefix string, nn []nodes.Node, fn func(key string, t *nodes.TextNode)) {
+	for i, n := range nn {
+		key := prefix + "/" + strconv.Itoa(i)
+		switch n := n.(type) {
+		case *nodes.TextNode:
+			if !n.Code {
+				fn(key, n)
+			}
+		case *nodes.CodeNode:
+			// code is never translated
+		default:
+			walkText(key, children(n), fn)
+		}
+	}
+}
+
+// children returns the child nodes of n, flattening items and table cells.
+func children(n nodes.Node) []nodes.Node {
+	content := func(l *nodes.ListNode) []nodes.Node {
+		if l == nil {
+			return nil
+		}
+		return l.Nodes
+	}
+	switch n := n.(type) {
+	case *nodes.ListNode:
+		return n.Nodes
+	case *nodes.HeaderNode:
+		return content(n.Content)
+	case *nodes.URLNode:
+		return content(n.Content)
+	case *nodes.ButtonNode:
+		return content(n.Content)
+	case *nodes.InfoboxNode:
+		return content(n.Content)
+	case *nodes.ActivityNode:
+		return content(n.Content)
+	case *nodes.AttachmentNode:
+		return content(n.Content)
+	case *nodes.ImportNode:
+		return content(n.Content)
+	case *nodes.XrefNode:
+		return content(n.Content)
+	case *nodes.TermNode:
+		return content(n.Content)
+	case *nodes.ItemsListNode:
+		var nn []nodes.Node
+		for _, it := range n.Items {
+			nn = append(nn, content(it)...)
+		}
+		return nn
+	case *nodes.GridNode:
+		var nn []nodes.Node
+		for _, r := range n.Rows {
+			for _, c := range r {
+				nn = append(nn, content(c.Content)...)
+			}
+		}
+		return nn
+	}
+	return nil
+}
+
+// translatable reports whether s has text other than protected markup.
+func translatable(s string) bool {
+	return strings.TrimSpace(protectedRegexp.ReplaceAllString(s, "")) != ""
+}
+
+// sameProtected reports whether s and t have the same protected markup,
+// in any order.
+func sameProtected(s, t string) bool {
+	a := protectedRegexp.FindAllString(s, -1)
+	b := protectedRegexp.FindAllString(t, -1)
+	if len(a) != len(b) {
+		return false
+	}
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
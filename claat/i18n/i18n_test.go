@@ -0,0 +1,149 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func text(s string) *nodes.TextNode {
+	return nodes.NewTextNode(nodes.NewTextNodeOptions{Value: s})
+}
+
+func testCodelab() *types.Codelab {
+	clab := types.NewCodelab()
+	clab.ID = "lab"
+	clab.Title = "Deploy"
+	clab.Summary = "Learn to deploy"
+	clab.Steps = []*types.Step{
+		{Title: "Setup", Content: nodes.NewListNode(
+			nodes.NewHeaderNode(2, text("Install")),
+			nodes.NewListNode(text("Run "), nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "make", Code: true}), text(" in {{{project_0.project_id}}}.")),
+			nodes.NewCodeNode("make install", true, ""),
+			nodes.NewItemsListNode("", 0),
+		)},
+		{Title: "Done", Content: nodes.NewListNode(
+			nodes.NewListNode(text("<ql-infobox>")),
+		)},
+	}
+	items := clab.Steps[0].Content.Nodes[3].(*nodes.ItemsListNode)
+	items.NewItem().Append(text("one"))
+	items.NewItem().Append(text("two"))
+	return clab
+}
+
+func TestExtract(t *testing.T) {
+	want := &Catalog{
+		ID:           "lab",
+		SourceLocale: "en",
+		Messages: []*Message{
+			{Key: "title", Source: "Deploy"},
+			{Key: "summary", Source: "Learn to deploy"},
+			{Key: "1/title", Source: "Setup"},
+			{Key: "1/0/0", Source: "Install"},
+			{Key: "1/1/0", Source: "Run "},
+			{Key: "1/1/2", Source: " in {{{project_0.project_id}}}."},
+			{Key: "1/3/0", Source: "one"},
+			{Key: "1/3/1", Source: "two"},
+			{Key: "2/title", Source: "Done"},
+		},
+	}
+	if diff := cmp.Diff(want, Extract(testCodelab())); diff != "" {
+		t.Errorf("Extract got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestApply(t *testing.T) {
+	c := &Catalog{
+		ID:           "lab",
+		SourceLocale: "en",
+		TargetLocale: "es",
+		Messages: []*Message{
+			{Key: "title", Source: "Deploy", Translation: "Desplegar"},
+			{Key: "summary", Source: "Learn to deploy"},
+			{Key: "1/1/2", Source: " in {{{project_0.project_id}}}.", Translation: " en {{{project_0.project_id}}}."},
+			{Key: "1/3/1", Source: "two", Translation: "dos"},
+			{Key: "2/title", Source: "Finished", Translation: "Terminado"},
+		},
+	}
+	clab := testCodelab()
+	warns, err := c.Apply(clab)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if diff := cmp.Diff([]string{"2/title: source has changed, translation skipped"}, warns); diff != "" {
+		t.Errorf("Apply warnings got diff (-want +got):\n%s", diff)
+	}
+	if clab.Title != "Desplegar" || clab.Summary != "Learn to deploy" || clab.Steps[1].Title != "Done" || clab.Locale != "es" {
+		t.Errorf("Apply metadata: title %q, summary %q, step 2 title %q, locale %q", clab.Title, clab.Summary, clab.Steps[1].Title, clab.Locale)
+	}
+	got := nodes.PlainText(clab.Steps[0].Content.Nodes...)
+	want := "InstallRun make en {{{project_0.project_id}}}.onedos"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Apply content got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestApplyProtected(t *testing.T) {
+	c := &Catalog{
+		ID:           "lab",
+		SourceLocale: "en",
+		TargetLocale: "es",
+		Messages: []*Message{
+			{Key: "title", Source: "Deploy", Translation: "Desplegar"},
+			{Key: "1/1/2", Source: " in {{{project_0.project_id}}}.", Translation: " en {{{proyecto}}}."},
+		},
+	}
+	clab := testCodelab()
+	_, err := c.Apply(clab)
+	if err == nil || !strings.Contains(err.Error(), "1/1/2") {
+		t.Errorf("Apply err = %v; want error about 1/1/2", err)
+	}
+	if clab.Title != "Deploy" {
+		t.Errorf("Apply changed title to %q despite error", clab.Title)
+	}
+	c.TargetLocale = ""
+	if _, err := c.Apply(testCodelab()); err == nil {
+		t.Errorf("Apply without target locale: no error")
+	}
+}
+
+func TestCatalogFormats(t *testing.T) {
+	c := Extract(testCodelab())
+	c.TargetLocale = "ja"
+	c.Messages[0].Translation = "デプロイ <b>&</b>"
+	for _, format := range []string{FormatJSON, FormatXLIFF} {
+		var buf bytes.Buffer
+		if err := c.Write(&buf, format); err != nil {
+			t.Fatalf("%s: Write: %v", format, err)
+		}
+		got, err := Read(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: Read: %v", format, err)
+		}
+		if diff := cmp.Diff(c, got); diff != "" {
+			t.Errorf("%s: round trip got diff (-want +got):\n%s", format, diff)
+		}
+	}
+	if err := c.Write(&bytes.Buffer{}, "po"); err == nil {
+		t.Errorf("Write(po): no error")
+	}
+}
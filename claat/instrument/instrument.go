@@ -0,0 +1,126 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package instrument sets up the OpenTelemetry SDK to export the spans
+// and metrics of claat, recorded with the global providers of
+// go.opentelemetry.io/otel, to a collector with OTLP over HTTP.
+//
+// Until Init is called, the global providers are no-ops.
+package instrument
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// DefaultInterval is how often metrics are exported by default.
+	DefaultInterval = 10 * time.Second
+	// ScopeName is the instrumentation scope of the spans and metrics of claat.
+	ScopeName = "github.com/googlecodelabs/tools/claat"
+)
+
+// DurationBounds are the explicit bucket bounds of duration histograms,
+// whose names end with ".duration", in seconds.
+var DurationBounds = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// DurationView aggregates duration histograms with DurationBounds.
+var DurationView = sdkmetric.NewView(
+	sdkmetric.Instrument{Name: "*.duration", Kind: sdkmetric.InstrumentKindHistogram},
+	sdkmetric.Stream{Aggregation: aggregation.ExplicitBucketHistogram{Boundaries: DurationBounds}},
+)
+
+// Options configures the exporters of Init.
+type Options struct {
+	// Endpoint is the base URL of the OTLP/HTTP collector,
+	// such as http://localhost:4318. Empty means the collector
+	// of the standard OTEL_EXPORTER_OTLP_* environment variables,
+	// which also set headers such as API keys of hosted collectors.
+	Endpoint string
+	// Interval is how often metrics are exported. Zero means DefaultInterval.
+	Interval time.Duration
+	// Service is the service.name resource attribute.
+	Service string
+	// Version is the service.version resource attribute.
+	Version string
+}
+
+// Init sets the global trace and meter providers to export spans and
+// metrics as configured by opts, and the global propagator to the W3C
+// traceparent and baggage headers. The returned func exports the remaining
+// data and stops the providers.
+func Init(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	if opts.Interval == 0 {
+		opts.Interval = DefaultInterval
+	}
+	var topts []otlptracehttp.Option
+	var mopts []otlpmetrichttp.Option
+	if opts.Endpoint != "" {
+		u, err := url.Parse(opts.Endpoint)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("invalid OTLP endpoint %q", opts.Endpoint)
+		}
+		base := strings.TrimSuffix(u.Path, "/")
+		topts = append(topts, otlptracehttp.WithEndpoint(u.Host), otlptracehttp.WithURLPath(base+"/v1/traces"))
+		mopts = append(mopts, otlpmetrichttp.WithEndpoint(u.Host), otlpmetrichttp.WithURLPath(base+"/v1/metrics"))
+		if u.Scheme == "http" {
+			topts = append(topts, otlptracehttp.WithInsecure())
+			mopts = append(mopts, otlpmetrichttp.WithInsecure())
+		}
+	}
+	texp, err := otlptracehttp.New(ctx, topts...)
+	if err != nil {
+		return nil, err
+	}
+	mexp, err := otlpmetrichttp.New(ctx, mopts...)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", opts.Service),
+		attribute.String("service.version", opts.Version),
+	))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(texp), sdktrace.WithResource(res))
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(mexp, sdkmetric.WithInterval(opts.Interval))),
+		sdkmetric.WithResource(res),
+		sdkmetric.WithView(DurationView),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetMeterProvider(mp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		if merr := mp.Shutdown(ctx); err == nil {
+			err = merr
+		}
+		return err
+	}, nil
+}
@@ -0,0 +1,264 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logging implements the leveled logging of claat, with fields
+// carrying context such as the codelab source, in text or JSON format.
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log entry.
+type Level int
+
+// Log levels, in increasing severity.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+// String returns the name of l, such as "info".
+func (l Level) String() string {
+	if l < 0 || int(l) >= len(levelNames) {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel returns the level of name, such as "debug".
+func ParseLevel(name string) (Level, error) {
+	for i, n := range levelNames {
+		if strings.EqualFold(name, n) {
+			return Level(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q, want one of %s", name, strings.Join(levelNames, ", "))
+}
+
+// Log formats.
+const (
+	// FormatText is a line of the level, message and key=value fields
+	// of each entry, for terminals.
+	FormatText = "text"
+	// FormatJSON is a JSON object per line of the time, level, msg
+	// and fields of each entry, for log processors.
+	FormatJSON = "json"
+)
+
+// output is the destination shared by a logger and its derived loggers.
+type output struct {
+	mu     sync.Mutex
+	w      io.Writer
+	level  Level  // minimum level written
+	format string // FormatText or FormatJSON
+	now    func() time.Time
+}
+
+// field is a key value pair of a Logger.
+type field struct {
+	key   string
+	value interface{}
+}
+
+// Logger writes leveled log entries with fields.
+// It is safe for concurrent use.
+type Logger struct {
+	out    *output
+	fields []field
+}
+
+// New returns a logger writing entries of level and above to w,
+// in format FormatText or FormatJSON.
+func New(w io.Writer, level Level, format string) (*Logger, error) {
+	if format != FormatText && format != FormatJSON {
+		return nil, fmt.Errorf("unknown log format %q, want %s or %s", format, FormatText, FormatJSON)
+	}
+	return &Logger{out: &output{w: w, level: level, format: format, now: time.Now}}, nil
+}
+
+// std is the default logger of the package functions.
+var std, _ = New(os.Stderr, LevelInfo, FormatText)
+
+// SetDefault replaces the default logger of the package functions.
+func SetDefault(l *Logger) {
+	std = l
+}
+
+// With returns a logger adding fields kv, key value pairs with string keys,
+// to the entries of l.
+func (l *Logger) With(kv ...interface{}) *Logger {
+	fields := make([]field, len(l.fields), len(l.fields)+len(kv)/2)
+	copy(fields, l.fields)
+	for i := 0; i+1 < len(kv); i += 2 {
+		fields = append(fields, field{fmt.Sprint(kv[i]), kv[i+1]})
+	}
+	return &Logger{out: l.out, fields: fields}
+}
+
+// Enabled reports whether entries of level are written.
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.out.level
+}
+
+// Debugf logs a debug entry, formatted as with fmt.Sprintf.
+func (l *Logger) Debugf(format string, args ...interface{}) { l.log(LevelDebug, format, args) }
+
+// Infof logs an info entry, formatted as with fmt.Sprintf.
+func (l *Logger) Infof(format string, args ...interface{}) { l.log(LevelInfo, format, args) }
+
+// Warnf logs a warning entry, formatted as with fmt.Sprintf.
+func (l *Logger) Warnf(format string, args ...interface{}) { l.log(LevelWarn, format, args) }
+
+// Errorf logs an error entry, formatted as with fmt.Sprintf.
+func (l *Logger) Errorf(format string, args ...interface{}) { l.log(LevelError, format, args) }
+
+// Fatalf logs an error entry, formatted as with fmt.Sprintf, and exits with code 1.
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.log(LevelError, format, args)
+	Exit(1)
+}
+
+func (l *Logger) log(level Level, format string, args []interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	o := l.out
+	msg := fmt.Sprintf(format, args...)
+	var b bytes.Buffer
+	if o.format == FormatJSON {
+		writeJSON(&b, o.now(), level, msg, l.fields)
+	} else {
+		writeText(&b, level, msg, l.fields)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.w.Write(b.Bytes())
+}
+
+// writeText writes an entry as a line like:
+// warn<tab>message key=value key="quoted value"
+func writeText(b *bytes.Buffer, level Level, msg string, fields []field) {
+	b.WriteString(level.String())
+	b.WriteByte('\t')
+	b.WriteString(msg)
+	for _, f := range fields {
+		v := fmt.Sprint(f.value)
+		if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+			v = fmt.Sprintf("%q", v)
+		}
+		fmt.Fprintf(b, " %s=%s", f.key, v)
+	}
+	b.WriteByte('\n')
+}
+
+// writeJSON writes an entry as a line of a JSON object, with fields
+// in order after the time, level and msg. Errors are written as their
+// message, and values which cannot be marshaled as their fmt.Sprint string.
+func writeJSON(b *bytes.Buffer, t time.Time, level Level, msg string, fields []field) {
+	b.WriteByte('{')
+	writeJSONField(b, "time", t.Format(time.RFC3339Nano))
+	b.WriteByte(',')
+	writeJSONField(b, "level", level.String())
+	b.WriteByte(',')
+	writeJSONField(b, "msg", msg)
+	for _, f := range fields {
+		b.WriteByte(',')
+		writeJSONField(b, f.key, f.value)
+	}
+	b.WriteString("}\n")
+}
+
+func writeJSONField(b *bytes.Buffer, key string, value interface{}) {
+	k, _ := json.Marshal(key)
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+	v, err := json.Marshal(value)
+	if err != nil {
+		v, _ = json.Marshal(fmt.Sprint(value))
+	}
+	b.Write(k)
+	b.WriteByte(':')
+	b.Write(v)
+}
+
+// SetOutput sets the writer of the default logger, and of the loggers
+// derived from it, to w. It returns the previous writer.
+func SetOutput(w io.Writer) io.Writer {
+	o := std.out
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	prev := o.w
+	o.w = w
+	return prev
+}
+
+// With returns a logger adding fields kv to the entries of the default logger.
+func With(kv ...interface{}) *Logger { return std.With(kv...) }
+
+// Debugf logs a debug entry with the default logger.
+func Debugf(format string, args ...interface{}) { std.log(LevelDebug, format, args) }
+
+// Infof logs an info entry with the default logger.
+func Infof(format string, args ...interface{}) { std.log(LevelInfo, format, args) }
+
+// Warnf logs a warning entry with the default logger.
+func Warnf(format string, args ...interface{}) { std.log(LevelWarn, format, args) }
+
+// Errorf logs an error entry with the default logger.
+func Errorf(format string, args ...interface{}) { std.log(LevelError, format, args) }
+
+// Fatalf logs an error entry with the default logger and exits with code 1.
+func Fatalf(format string, args ...interface{}) {
+	std.log(LevelError, format, args)
+	Exit(1)
+}
+
+var (
+	exitMu    sync.Mutex
+	exitHooks []func(code int)
+)
+
+// OnExit registers f to be called with the exit code by Exit,
+// including the exits of Fatalf.
+func OnExit(f func(code int)) {
+	exitMu.Lock()
+	defer exitMu.Unlock()
+	exitHooks = append(exitHooks, f)
+}
+
+// Exit calls the functions registered with OnExit, in order,
+// and exits the program with code.
+func Exit(code int) {
+	exitMu.Lock()
+	hooks := exitHooks
+	exitHooks = nil
+	exitMu.Unlock()
+	for _, f := range hooks {
+		f(code)
+	}
+	os.Exit(code)
+}
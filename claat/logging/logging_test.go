@@ -0,0 +1,123 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLogger(t *testing.T) {
+	now := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		level  Level
+		format string
+		out    string
+	}{
+		{
+			name:   "text",
+			level:  LevelInfo,
+			format: FormatText,
+			out: "info\texported source=doc id=lab\n" +
+				"warn\tno duration source=doc id=lab step=2\n" +
+				"error\tfailed source=doc err=\"not found\"\n",
+		},
+		{
+			name:   "text debug",
+			level:  LevelDebug,
+			format: FormatText,
+			out: "debug\tfetched 3 steps source=doc\n" +
+				"info\texported source=doc id=lab\n" +
+				"warn\tno duration source=doc id=lab step=2\n" +
+				"error\tfailed source=doc err=\"not found\"\n",
+		},
+		{
+			name:   "json",
+			level:  LevelWarn,
+			format: FormatJSON,
+			out: `{"time":"2026-10-15T09:30:00Z","level":"warn","msg":"no duration","source":"doc","id":"lab","step":2}` + "\n" +
+				`{"time":"2026-10-15T09:30:00Z","level":"error","msg":"failed","source":"doc","err":"not found"}` + "\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var b bytes.Buffer
+			l, err := New(&b, tc.level, tc.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.out.now = func() time.Time { return now }
+			src := l.With("source", "doc")
+			src.Debugf("fetched %d steps", 3)
+			lab := src.With("id", "lab")
+			lab.Infof("exported")
+			lab.With("step", 2).Warnf("no duration")
+			src.With("err", errors.New("not found")).Errorf("failed")
+			if diff := cmp.Diff(tc.out, b.String()); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewUnknownFormat(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, LevelInfo, "xml"); err == nil {
+		t.Error("New(xml) = nil error; want error")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, name := range []string{"debug", "info", "WARN", "error"} {
+		l, err := ParseLevel(name)
+		if err != nil {
+			t.Errorf("ParseLevel(%q): %v", name, err)
+			continue
+		}
+		if !bytes.EqualFold([]byte(l.String()), []byte(name)) {
+			t.Errorf("ParseLevel(%q) = %v", name, l)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("ParseLevel(verbose) = nil error; want error")
+	}
+}
+
+func TestFatalfOnExit(t *testing.T) {
+	if os.Getenv("LOGGING_TEST_FATALF") == "1" {
+		SetOutput(ioutil.Discard)
+		OnExit(func(code int) { fmt.Printf("exit %d\n", code) })
+		Fatalf("failed")
+		return
+	}
+	c := exec.Command(os.Args[0], "-test.run=^TestFatalfOnExit$")
+	c.Env = append(os.Environ(), "LOGGING_TEST_FATALF=1")
+	out, err := c.Output()
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) || ee.ExitCode() != 1 {
+		t.Fatalf("Fatalf exited with %v; want exit code 1", err)
+	}
+	if string(out) != "exit 1\n" {
+		t.Errorf("Fatalf output = %q; want the exit hook to print %q", out, "exit 1\n")
+	}
+}
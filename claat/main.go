@@ -20,42 +20,121 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
-	"log"
 	"math/rand"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/googlecodelabs/tools/claat/cmd"
+	"github.com/googlecodelabs/tools/claat/fetch"
+	"github.com/googlecodelabs/tools/claat/i18n"
+	"github.com/googlecodelabs/tools/claat/instrument"
+	"github.com/googlecodelabs/tools/claat/logging"
+	"github.com/googlecodelabs/tools/claat/telemetry"
+	"github.com/googlecodelabs/tools/claat/transform"
+	"github.com/googlecodelabs/tools/claat/types"
+	"github.com/googlecodelabs/tools/claat/util"
 
 	// allow parsers to register themselves
 	_ "github.com/googlecodelabs/tools/claat/parser/gdoc"
+	_ "github.com/googlecodelabs/tools/claat/parser/html"
 	_ "github.com/googlecodelabs/tools/claat/parser/md"
+	_ "github.com/googlecodelabs/tools/claat/parser/notion"
 )
 
 var (
 	version string // set by linker -X
 
 	// Flags.
+	adc          = flag.Bool("adc", false, "Authorize with Application Default Credentials instead of the interactive user flow.")
 	addr         = flag.String("addr", "localhost:9090", "hostname and port to bind web server to")
+	analytics    = flag.String("analytics", "", "JSON file of analytics profiles to inject into HTML output, replacing -ga.")
+	api          = flag.Bool("api", false, "Serve the HTTP API rendering codelabs instead of the current directory.")
+	audience     = flag.String("audience", "", "Audience of the export: instructor to show the instructor notes of codelabs, tagged with the instructor environment, or student to strip them.")
 	authToken    = flag.String("auth", "", "OAuth2 Bearer token; alternative credentials override.")
+	bucket       = flag.String("bucket", "", "Cloud Storage bucket to upload previews to.")
+	categories   = flag.String("category", "", "Only update codelabs in one of these categories. Comma-delimited list of categories.")
+	cloudShell   = flag.String("cloudshell", "", "Follow terminal code blocks of html and offline output with an Open in Cloud Shell link, or the first of each step with an embedded Cloud Shell terminal: link or embed.")
+	comments     = flag.Bool("comments", false, "Write the open comments and suggestions of Google Docs to comments.json in their output directory; with -review, also add them to the content.")
+	deadLetter   = flag.String("dead_letter_topic", "", "Pub/Sub topic the worker publishes failed jobs to, as projects/<project>/topics/<topic>.")
+	docsAPI      = flag.Bool("docs_api", false, "Fetch Google Docs with the Docs API instead of the Drive HTML export.")
+	driveMatch   = flag.String("drive_match", "", "Glob pattern of doc names to export from drive:// folders.")
+	durationTol  = flag.Duration("duration_tolerance", transform.DefaultDurationTolerance, "Warn if the declared codelab duration differs more from the sum of step durations.")
+	emojiImages  = flag.String("emoji_images", "", "URL prefix of emoji images, such as Twemoji's, to replace :name: emoji shortcodes of text with in the emoji pass, instead of emoji, for targets without emoji fonts.")
+	estimateDur  = flag.Bool("estimate_durations", false, "Set durations of steps without one to an estimate of their reading and execution time.")
 	expenv       = flag.String("e", "web", "codelab environment")
 	extra        = flag.String("extra", "", "Additional arguments to pass to format templates. JSON object of string,string key values.")
+	figureLabel  = flag.String("figure_label", "", "Label of figures numbered with -number_figures, with {n} replaced by the number, such as \"Fig. {n}\"; that of the codelab language if empty.")
+	fixtures     = flag.String("fixtures", fetch.DefaultFixtures, "Directory of the responses of remote fetches recorded with -record and served with -offline.")
+	gaProfile    = flag.String("analytics_profile", "default", "Profile of the -analytics file to use.")
 	globalGA     = flag.String("ga", "UA-49880327-14", "global Google Analytics account")
+	grpc         = flag.Bool("grpc", false, "Serve the Converter gRPC service instead of the current directory.")
+	hardened     = flag.Bool("hardened", false, "Limit the size, content and export time of each codelab, and reject imports, code includes, attachments and manifests, for untrusted sources.")
+	hookToken    = flag.String("webhook_token", "", "Shared secret authenticating webhooks to the webhook command.")
+	iconImages   = flag.String("icon_images", "", "URL of the images of {icon:name} icons in md output, with {name} replaced by the Material icon name; text if empty.")
+	iframeAllow  = flag.String("iframe_domains", "", "Additional domains allowed to be embedded in iframes. Comma-delimited list of domains.")
+	importCache  = flag.String("import_cache", "", "Directory to cache imported remote fragments in; no caching if empty.")
+	importTTL    = flag.Duration("import_cache_ttl", fetch.DefaultImportCacheTTL, "How long a cached import is reused.")
+	keepRtVars   = flag.Bool("keep_runtime_vars", false, "Leave Qwiklabs {{{...}}} runtime expressions unescaped in Markdown and HTML output.")
+	lightboxMin  = flag.Int("lightbox", 0, "Zoom in on click on images at least this many pixels wide, or of unknown width, in html and offline output; 0 disables it.")
+	logFormat    = flag.String("log_format", logging.FormatText, "Format of log entries written to stderr: text, or json for log processors.")
+	maxAttempts  = flag.Int("max_attempts", cmd.DefaultWorkerAttempts, "How many times the worker tries a job before it fails.")
+	maxTestedAge = flag.Int("max_tested_age", 0, "Fail if the Last Tested watermark date is older than this many days; 0 disables the check.")
+	mdTOC        = flag.Bool("md_toc", false, "Emit a linked table of contents of steps and headings at the top of md format output.")
+	noComments   = flag.Bool("no_open_comments", false, "Fail the export of Google Docs with unresolved comments or pending suggestions.")
+	numFigures   = flag.Bool("number_figures", false, "Number images captioned by the captions pass as figures, as in Figure 3: caption.")
+	offline      = flag.Bool("offline", false, "Serve all remote fetches from the responses recorded in -fixtures, without network access nor Google authorization.")
+	onlyStatus   = flag.String("only_status", "", "Only export codelabs with one of these statuses, e.g. published. Comma-delimited list of statuses.")
+	otlpURL      = flag.String("otlp_endpoint", "", "OTLP/HTTP collector URL to export OpenTelemetry traces and metrics to, e.g. http://localhost:4318; defaults to $OTEL_EXPORTER_OTLP_ENDPOINT.")
 	output       = flag.String("o", ".", "output directory or '-' for stdout")
+	paper        = flag.String("paper", "", "Paper size of printed html and offline output: a4 or letter; the printer default if empty.")
 	passMetadata = flag.String("pass_metadata", "", "Metadata fields to pass through to the output. Comma-delimited list of field names.")
+	passes       = flag.String("passes", "", "Transform passes to apply to codelab content before rendering, in order. Comma-delimited list of pass names.")
 	prefix       = flag.String("prefix", "https://storage.googleapis.com", "URL prefix for html format")
+	profile      = flag.String("profile", "", "pprof profiles to write, as kind=path pairs: cpu for CPU time, mem for heap allocations. Comma-delimited list.")
+	progressBar  = flag.Bool("progress", false, "Draw a progress bar of the exported codelabs on stderr, if it is a terminal.")
+	pwa          = flag.Bool("pwa", false, "Make html and offline output an installable progressive web app working offline once visited, with a web app manifest and a service worker.")
+	quiet        = flag.Bool("quiet", false, "Only log warnings and errors, without progress.")
+	previewTTL   = flag.Duration("preview_ttl", cmd.DefaultPreviewTTL, "How long a preview is kept before it expires.")
+	rateLimit    = flag.Float64("rate_limit", 0, "Maximum Google API requests per second, such as Google Docs exports, across all fetches; 0 for no limit.")
+	record       = flag.Bool("record", false, "Record the responses of remote fetches in -fixtures, for later -offline runs.")
+	registry     = flag.String("registry", "", "JSON registry file of the sources, output directories and render profiles of the codelabs to update.")
+	redact       = flag.String("redact", "", "JSON file of patterns of sensitive content, such as project IDs and internal hostnames, to fail the export on or mask, along with well-known credentials.")
+	release      = flag.String("release", "", "Export codelabs to this release directory under their output directory, with a changelog; \"auto\" for the next vN release, \"tag\" for the git tag of their source.")
+	retries      = flag.Int("retries", 0, "Maximum retries of failed remote fetches; 0 for the defaults (7 for Google Drive, 3 otherwise), -1 to disable retries.")
+	retryWait    = flag.Duration("retry_backoff", fetch.DefaultRetryBackoff, "Delay before the first retry of a failed remote fetch, doubled for each next retry.")
+	review       = flag.String("review", "", "Mark content changed since this previous version: a release directory, its outline.json or a codelab source.")
+	sanitize     = flag.String("sanitize", "", "JSON file of HTML elements, attributes and URL schemes to allow in rendered codelab content, in addition to those claat renders.")
+	siteURL      = flag.String("site_url", "", "URL the output directory is published at; if set, export and index also write sitemap.xml and an Atom feed.xml of its codelabs.")
+	snippets     = flag.String("snippets", "", "Directory of shared snippets to expand {{> name key=value}} references with.")
+	split        = flag.Bool("split", false, "Write each step of md and html format output to its own file, along with an index of the steps.")
+	strict       = flag.Bool("strict", false, "Fail the export of codelabs with content the output format cannot render, rather than warning about it.")
+	strictMeta   = flag.Bool("strict_meta", false, "Fail on invalid codelab metadata, such as a missing summary or an unknown status.")
+	suggest      = flag.Bool("suggest", false, "Replace categories and tags not in the -taxonomy with their canonical values, such as kuberenetes with kubernetes.")
+	subscription = flag.String("subscription", "", "Pub/Sub subscription the worker pulls export jobs from, as projects/<project>/subscriptions/<subscription>.")
+	telemetryURL = flag.String("telemetry", "", "opt-in endpoint to report anonymous usage to; disabled if empty")
+	testedAt     = flag.String("tested_at", "", "Date to set in Last Tested watermarks, as YYYY-MM-DD.")
+	tags         = flag.String("tag", "", "Only update codelabs with one of these tags. Comma-delimited list of tags.")
+	taxonomy     = flag.String("taxonomy", "", "JSON file of the allowed categories and tags of codelabs, and aliases of them, to fail the export of codelabs with others.")
+	theme        = flag.String("theme", "", "Bundled theme, such as dark, or directory of a theme of stylesheets, scripts, assets and header.html and footer.html fragments, branding html and offline output.")
 	tmplout      = flag.String("f", "html", "output format")
+	topic        = flag.String("topic", "", "Pub/Sub topic the worker publishes completion events to, as projects/<project>/topics/<topic>.")
+	updatedAt    = flag.String("updated_at", "", "Date to set in Last Updated watermarks, as YYYY-MM-DD; defaults to the source modification date.")
+	varsFile     = flag.String("vars", "", "JSON file of string,string key values to substitute for {{key}} references in codelab content.")
+	verbosity    = flag.String("verbosity", "info", "Minimum level of log entries written: debug, info, warn or error.")
+	verifiers    = flag.String("verifiers", "", "JSON file of language,command key values to verify runnable code blocks with.")
 )
 
 func main() {
-	log.SetFlags(0)
 	rand.Seed(time.Now().UnixNano())
 	if len(os.Args) == 1 {
-		log.Fatalf("Need subcommand. Try '-h' for options.")
+		logging.Fatalf("Need subcommand. Try '-h' for options.")
 	}
 	if os.Args[1] == "-h" || os.Args[1] == "--help" {
 		usage()
@@ -63,48 +142,411 @@ func main() {
 	}
 
 	flag.Usage = usage
-	flag.CommandLine.Parse(os.Args[2:])
+	args := os.Args[2:]
+	// subcommands of course, i18n and quiz precede their options
+	var sub string
+	if (os.Args[1] == "course" || os.Args[1] == "i18n" || os.Args[1] == "quiz") && len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+	flag.CommandLine.Parse(args)
+
+	level, err := logging.ParseLevel(*verbosity)
+	if err != nil {
+		logging.Fatalf("%v", err)
+	}
+	if *quiet && level < logging.LevelWarn {
+		level = logging.LevelWarn
+	}
+	logger, err := logging.New(os.Stderr, level, *logFormat)
+	if err != nil {
+		logging.Fatalf("%v", err)
+	}
+	logging.SetDefault(logger)
+	start := time.Now()
+	// canceled by signals once the command runs
+	ctx := context.Background()
+	// Fatalf exits through logging.Exit too, so that failures are reported
+	logging.OnExit(func(code int) {
+		reportUsage(os.Args[1], start, telemetry.ErrorClass(code, started, ctx.Err()))
+	})
+	stopOTel, err := initOTel()
+	if err != nil {
+		logging.Fatalf("%v", err)
+	}
 
 	extraVars, err := ParseExtraVars(*extra)
 	if err != nil {
-		os.Exit(1)
+		logging.Exit(1)
+	}
+
+	vars := map[string]string{}
+	if *varsFile != "" {
+		if vars, err = transform.ReadVars(*varsFile); err != nil {
+			logging.Fatalf("Error reading %s: %v", *varsFile, err)
+		}
+	}
+
+	updated, err := parseDateFlag("updated_at", *updatedAt)
+	if err != nil {
+		logging.Fatalf("%v", err)
+	}
+	tested, err := parseDateFlag("tested_at", *testedAt)
+	if err != nil {
+		logging.Fatalf("%v", err)
 	}
+	maxAge := time.Duration(*maxTestedAge) * 24 * time.Hour
 
+	var redaction *transform.Redaction
+	if *redact != "" {
+		if redaction, err = transform.ReadRedaction(*redact); err != nil {
+			logging.Fatalf("Error reading %s: %v", *redact, err)
+		}
+	}
+
+	var tax *types.Taxonomy
+	if *taxonomy != "" {
+		if tax, err = cmd.ReadTaxonomy(*taxonomy); err != nil {
+			logging.Fatalf("Error reading %s: %v", *taxonomy, err)
+		}
+	}
+
+	var ga *types.Analytics
+	if *analytics != "" {
+		if ga, err = cmd.ReadAnalytics(*analytics, *gaProfile); err != nil {
+			logging.Fatalf("Error reading %s: %v", *analytics, err)
+		}
+	}
+
+	if version != "" {
+		cmd.Version = version
+	}
 	pm := parsePassMetadata(*passMetadata)
+	passNames := util.NormalizedSplit(*passes)
+	if *offline && *record {
+		logging.Fatalf("-offline and -record are mutually exclusive")
+	}
+	profiles, err := cmd.ParseProfiles(*profile)
+	if err != nil {
+		logging.Fatalf("%v", err)
+	}
+	retry := fetch.RetryPolicy{Retries: *retries, Backoff: *retryWait}
+	var limits *cmd.Limits
+	if *hardened {
+		limits = &cmd.DefaultLimits
+	}
+	fetch.SetRateLimit(*rateLimit)
 
+	exportOpts := cmd.CmdExportOptions{
+		ADC:               *adc,
+		Analytics:         ga,
+		Audience:          *audience,
+		AuthToken:         *authToken,
+		CloudShell:        *cloudShell,
+		Comments:          *comments,
+		DocsAPI:           *docsAPI,
+		DriveMatch:        *driveMatch,
+		DurationTolerance: *durationTol,
+		EmojiImages:       *emojiImages,
+		EstimateDurations: *estimateDur,
+		Expenv:            *expenv,
+		ExtraVars:         extraVars,
+		FigureLabel:       *figureLabel,
+		Fixtures:          *fixtures,
+		GlobalGA:          *globalGA,
+		IframeDomains:     util.NormalizedSplit(*iframeAllow),
+		IconImages:        *iconImages,
+		ImportCache:       *importCache,
+		ImportCacheTTL:    *importTTL,
+		KeepRuntimeVars:   *keepRtVars,
+		Lightbox:          *lightboxMin,
+		Limits:            limits,
+		MaxTestedAge:      maxAge,
+		NoOpenComments:    *noComments,
+		NumberFigures:     *numFigures,
+		Offline:           *offline,
+		OnlyStatus:        util.NormalizedSplit(*onlyStatus),
+		Output:            *output,
+		Paper:             *paper,
+		PassMetadata:      pm,
+		Passes:            passNames,
+		Prefix:            *prefix,
+		ProgressBar:       *progressBar && !*quiet,
+		PWA:               *pwa,
+		Record:            *record,
+		Redaction:         redaction,
+		Release:           *release,
+		Retry:             retry,
+		SanitizePolicy:    *sanitize,
+		Review:            *review,
+		SiteURL:           *siteURL,
+		Snippets:          *snippets,
+		Split:             *split,
+		Srcs:              flag.Args(),
+		Strict:            *strict,
+		StrictMeta:        *strictMeta,
+		Suggest:           *suggest,
+		Taxonomy:          tax,
+		TestedAt:          tested,
+		TOC:               *mdTOC,
+		Theme:             *theme,
+		Tmplout:           *tmplout,
+		UpdatedAt:         updated,
+		Vars:              vars,
+	}
+	updateOpts := cmd.CmdUpdateOptions{
+		ADC:               *adc,
+		Analytics:         ga,
+		AuthToken:         *authToken,
+		Categories:        util.NormalizedSplit(*categories),
+		DocsAPI:           *docsAPI,
+		DurationTolerance: *durationTol,
+		EmojiImages:       *emojiImages,
+		EstimateDurations: *estimateDur,
+		ExtraVars:         extraVars,
+		FigureLabel:       *figureLabel,
+		Fixtures:          *fixtures,
+		GlobalGA:          *globalGA,
+		IconImages:        *iconImages,
+		ImportCache:       *importCache,
+		ImportCacheTTL:    *importTTL,
+		KeepRuntimeVars:   *keepRtVars,
+		MaxTestedAge:      maxAge,
+		NumberFigures:     *numFigures,
+		Offline:           *offline,
+		PassMetadata:      pm,
+		Passes:            passNames,
+		Prefix:            *prefix,
+		Record:            *record,
+		Redaction:         redaction,
+		Registry:          *registry,
+		Retry:             retry,
+		SanitizePolicy:    *sanitize,
+		Snippets:          *snippets,
+		Strict:            *strict,
+		StrictMeta:        *strictMeta,
+		Suggest:           *suggest,
+		Tags:              util.NormalizedSplit(*tags),
+		Taxonomy:          tax,
+		TestedAt:          tested,
+		UpdatedAt:         updated,
+		Vars:              vars,
+	}
+
+	// Ctrl-C and SIGTERM cancel in-flight fetches and stop servers;
+	// a second signal kills the process.
+	var stop context.CancelFunc
+	ctx, stop = signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	stopProfiles, err := cmd.StartProfiles(profiles)
+	if err != nil {
+		logging.Fatalf("Error starting profiles: %v", err)
+	}
+	started = true
 	exitCode := 0
 	switch os.Args[1] {
+	case "course":
+		if sub != "export" {
+			usageFatalf("Unknown course subcommand %q, want export. Try '-h' for options.", sub)
+		}
+		exitCode = cmd.CmdCourseExport(ctx, exportOpts)
+	case "diff":
+		format := "text"
+		if *tmplout == "json" {
+			format = "json"
+		}
+		exitCode = cmd.CmdDiff(ctx, cmd.CmdDiffOptions{
+			ADC:          *adc,
+			AuthToken:    *authToken,
+			DocsAPI:      *docsAPI,
+			Format:       format,
+			PassMetadata: pm,
+			Srcs:         flag.Args(),
+		})
 	case "export":
-		exitCode = cmd.CmdExport(cmd.CmdExportOptions{
+		exitCode = cmd.CmdExport(ctx, exportOpts)
+	case "i18n":
+		switch sub {
+		case "extract":
+			format := i18n.FormatJSON
+			if *tmplout == "xliff" {
+				format = i18n.FormatXLIFF
+			}
+			exitCode = cmd.CmdI18nExtract(ctx, cmd.CmdI18nExtractOptions{
+				ADC:          *adc,
+				AuthToken:    *authToken,
+				DocsAPI:      *docsAPI,
+				Format:       format,
+				Output:       *output,
+				PassMetadata: pm,
+				Srcs:         flag.Args(),
+			})
+		case "apply":
+			exitCode = cmd.CmdI18nApply(ctx, exportOpts)
+		default:
+			usageFatalf("Unknown i18n subcommand %q, want extract or apply. Try '-h' for options.", sub)
+		}
+	case "index":
+		exitCode = cmd.CmdIndex(cmd.CmdIndexOptions{
+			ExtraVars: extraVars,
+			Output:    *output,
+			SiteURL:   *siteURL,
+			Srcs:      flag.Args(),
+			Tmplout:   *tmplout,
+		})
+	case "lint":
+		format := "text"
+		if *tmplout == "json" {
+			format = "json"
+		}
+		exitCode = cmd.CmdLint(ctx, cmd.CmdLintOptions{
+			ADC:          *adc,
 			AuthToken:    *authToken,
-			Expenv:       *expenv,
-			ExtraVars:    extraVars,
-			GlobalGA:     *globalGA,
-			Output:       *output,
+			DocsAPI:      *docsAPI,
+			Format:       format,
+			PassMetadata: pm,
+			Srcs:         flag.Args(),
+		})
+	case "preview":
+		exitCode = cmd.CmdPreview(ctx, cmd.CmdPreviewOptions{
+			Bucket: *bucket,
+			Export: exportOpts,
+			TTL:    *previewTTL,
+		})
+	case "quiz":
+		if sub != "validate" {
+			usageFatalf("Unknown quiz subcommand %q, want validate. Try '-h' for options.", sub)
+		}
+		exitCode = cmd.CmdQuizValidate(ctx, cmd.CmdQuizOptions{
+			ADC:          *adc,
+			AuthToken:    *authToken,
+			DocsAPI:      *docsAPI,
 			PassMetadata: pm,
-			Prefix:       *prefix,
 			Srcs:         flag.Args(),
-			Tmplout:      *tmplout,
 		})
 	case "serve":
-		exitCode = cmd.CmdServe(*addr)
+		if *api {
+			exitCode = cmd.CmdServeAPI(ctx, *addr, exportOpts)
+			break
+		}
+		if *grpc {
+			exitCode = cmd.CmdServeGRPC(ctx, *addr, exportOpts)
+			break
+		}
+		exitCode = cmd.CmdServe(ctx, *addr)
+	case "stats":
+		format := "table"
+		if *tmplout == "json" {
+			format = "json"
+		}
+		exitCode = cmd.CmdStats(ctx, cmd.CmdStatsOptions{
+			ADC:          *adc,
+			AuthToken:    *authToken,
+			DocsAPI:      *docsAPI,
+			Format:       format,
+			PassMetadata: pm,
+			Srcs:         flag.Args(),
+		})
 	case "update":
-		exitCode = cmd.CmdUpdate(cmd.CmdUpdateOptions{
+		updateOpts.Export = exportOpts
+		exitCode = cmd.CmdUpdate(ctx, updateOpts)
+	case "verify":
+		if *verifiers == "" {
+			usageFatalf("Need -verifiers. Try '-h' for options.")
+		}
+		vv, err := cmd.ReadVerifiers(*verifiers)
+		if err != nil {
+			logging.Fatalf("Error reading %s: %v", *verifiers, err)
+		}
+		exitCode = cmd.CmdVerify(ctx, cmd.CmdVerifyOptions{
+			ADC:          *adc,
 			AuthToken:    *authToken,
-			ExtraVars:    extraVars,
-			GlobalGA:     *globalGA,
+			DocsAPI:      *docsAPI,
 			PassMetadata: pm,
-			Prefix:       *prefix,
+			Srcs:         flag.Args(),
+			Verifiers:    vv,
 		})
 	case "help":
 		usage()
 	case "version":
 		fmt.Println(version)
+	case "webhook":
+		exitCode = cmd.CmdWebhook(ctx, cmd.CmdWebhookOptions{
+			Addr:   *addr,
+			Output: *output,
+			Token:  *hookToken,
+			Update: updateOpts,
+		})
+	case "worker":
+		exitCode = cmd.CmdWorker(ctx, cmd.CmdWorkerOptions{
+			Attempts:     *maxAttempts,
+			DeadLetter:   *deadLetter,
+			Export:       exportOpts,
+			Subscription: *subscription,
+			Topic:        *topic,
+		})
 	default:
-		log.Fatalf("Unknown subcommand. Try '-h' for options.")
+		usageFatalf("Unknown subcommand. Try '-h' for options.")
 	}
 
-	os.Exit(exitCode)
+	if err := stopProfiles(); err != nil {
+		logging.Warnf("%v", err)
+	}
+	if err := stopOTel(); err != nil {
+		logging.Warnf("%v", err)
+	}
+	logging.Exit(exitCode)
+}
+
+// initOTel enables OpenTelemetry recording if an OTLP endpoint is set
+// with the -otlp_endpoint flag or the standard environment variables.
+// The returned func exports the remaining data.
+func initOTel() (func() error, error) {
+	noop := func() error { return nil }
+	if *otlpURL == "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" &&
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT") == "" {
+		return noop, nil
+	}
+	shutdown, err := instrument.Init(context.Background(), instrument.Options{
+		Endpoint: *otlpURL,
+		Service:  "claat",
+		Version:  version,
+	})
+	if err != nil {
+		return noop, err
+	}
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return shutdown(ctx)
+	}, nil
+}
+
+// started is set once the command runs, to report its failures
+// apart from usage errors.
+var started bool
+
+// usageFatalf logs a usage error of the command, formatted as with
+// fmt.Sprintf, and exits with code 1, as logging.Fatalf does.
+func usageFatalf(format string, args ...interface{}) {
+	started = false
+	logging.Fatalf(format, args...)
+}
+
+// reportUsage sends an anonymous usage event if telemetry has been opted into
+// with the -telemetry flag. Failures are logged and otherwise ignored.
+func reportUsage(command string, start time.Time, class string) {
+	if *telemetryURL == "" {
+		return
+	}
+	e := telemetry.NewEvent(command, *tmplout, version, start)
+	e.ErrorClass = class
+	if err := telemetry.Report(*telemetryURL, e); err != nil {
+		logging.Warnf("telemetry: %v", err)
+	}
 }
 
 // parsePassMetadata parses metadata fields to parse that are not explicitly handled elsewhere.
@@ -117,6 +559,19 @@ func parsePassMetadata(passMeta string) map[string]bool {
 	return fields
 }
 
+// parseDateFlag parses a YYYY-MM-DD date value v of flag name.
+// It returns zero time if v is empty.
+func parseDateFlag(name, v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("-%s: %q is not a YYYY-MM-DD date", name, v)
+	}
+	return t, nil
+}
+
 // ParseExtraVars parses extra template variables from command line.
 // extra is any additional arguments to pass to format templates. Should be formatted as JSON objects of string:string KV pairs.
 func ParseExtraVars(extra string) (map[string]string, error) {
@@ -127,7 +582,7 @@ func ParseExtraVars(extra string) (map[string]string, error) {
 	b := []byte(extra)
 	err := json.Unmarshal(b, &vars)
 	if err != nil {
-		log.Printf("Error parsing additional template data: %v", err)
+		logging.Errorf("Error parsing additional template data: %v", err)
 		return nil, err
 	}
 	return vars, nil
@@ -141,7 +596,7 @@ func usage() {
 
 const usageText = `Usage: claat <cmd> [options] src [src ...]
 
-Available commands are: export, serve, update, version.
+Available commands are: course, diff, export, i18n, index, lint, preview, quiz, serve, stats, update, verify, version, webhook, worker.
 
 ## Export command
 
@@ -166,16 +621,589 @@ Source formats currently supported are:
 
 - Google Doc (Codelab Format, go/codelab-guide)
 - Markdown
+- HTML previously exported with "-f html" (files ending in .html or .htm)
+- Notion page
+- Manifest of a codelab composed of several of the above (files ending in .json)
 
 When 'src' is a Google Doc, it must be specified as a doc ID,
-omitting https://docs.google.com/... part.
+omitting https://docs.google.com/... part, or as gdoc://docID.
+Local files may also be specified as file:///path/to/lab.md.
+A 'src' of the form drive://folderID exports all Google Docs in a Drive
+folder and its subfolders. The output directory mirrors the folder tree.
+Use -drive_match to export only docs whose name matches a glob pattern,
+e.g. -drive_match 'Lab *'.
+
+Add -docs_api to read the doc structure with the Google Docs API
+rather than from its HTML export, which loses some formatting.
+Credentials cached before -docs_api was available lack the Docs API
+scope; remove ~/.config/claat/goog-cred.json to authorize again.
+
+Google Docs are fetched on behalf of the user, authorized interactively
+in a browser on first use. In CI and other non-interactive environments,
+use -adc to authorize with Application Default Credentials instead:
+a service account key file named by GOOGLE_APPLICATION_CREDENTIALS,
+credentials of "gcloud auth application-default login", or the metadata
+server of GCE, GKE workload identity, Cloud Run and Cloud Build.
+The docs must be shared with the service account.
+
+A file of a git repository is specified as host/owner/repo//path@ref,
+e.g. github.com/org/repo//labs/intro.md@main, where ref is a branch, a tag
+or a commit, and defaults to the default branch. The ref is fetched with
+git, shallowly, so that images, imports and code includes relative to the
+file are read from the same commit, which is recorded as the revision
+of the codelab in codelab.json. Symbolic links are checked out as plain
+files, and checkouts of commits unused for a day are removed. Private repositories are fetched over HTTPS
+with the token in the GIT_TOKEN environment variable, such as a GitHub
+personal access token. The token is only sent to github.com, or to the host
+in the GIT_TOKEN_HOST environment variable.
+
+A Notion page is specified as notion://pageID or as its notion.so URL.
+It is fetched with the Notion API using the integration token in
+the NOTION_TOKEN environment variable; share the page with the integration.
+Page properties provide codelab metadata, each Heading 1 starts a step,
+callouts become info boxes and toggles become collapsible FAQ items.
+
+A manifest composes one codelab from several source docs, such as an
+introduction, modules written by different authors and a cleanup:
+
+    {
+      "id": "cloud-run-course",
+      "title": "Cloud Run from zero to production",
+      "sources": ["intro.md", "1AbC...docID", "cleanup.md"]
+    }
+
+The steps of the sources are concatenated in order. The metadata of the
+first source is used, unless set in the manifest: id, title, summary,
+authors, categories, tags and feedback_link. Local and remote sources are
+relative to the manifest. Activity tracking numbers and survey IDs of
+later sources are renumbered; cross-references such as "see Step 4"
+refer to the step numbers of the composed codelab.
+
+The "provenance" of codelab.json traces each export back to its exact
+source: the source, its revision, the revisions of the other sources read,
+such as the parts of a manifest, imports, code includes, images and
+attachments, the claat version, the render profile (format, environment,
+audience, prefix and transform passes) and the export time. Revisions are
+the commit of git sources, the modification time of Google Docs, local
+files and Notion pages, and the ETag or content hash of other URLs. HTML output
+has the same provenance in claat:source, claat:revision, claat:version,
+claat:profile and claat:exported meta tags.
+
+The codelab duration is the sum of step durations, unless declared
+in metadata. A warning is reported for steps without a duration and when
+the declared duration differs from the sum by more than -duration_tolerance.
+
+Use -estimate_durations to set the duration of steps without one to
+an estimate based on the number of words, lines of code and videos.
+Video lengths are retrieved with the YouTube Data API if a key is set in
+the YOUTUBE_API_KEY environment variable.
+
+Use -strict_meta to fail the export of a codelab with invalid metadata:
+a missing id, title, summary, category or step durations, an id that is not
+URL friendly, an unknown status, a feedback link which is not an http(s) URL,
+or malformed analytics accounts. All invalid fields are reported at once.
+
+Use -taxonomy to fail the export of a codelab with categories or tags
+other than those listed in a JSON file, so that typos such as "kuberenetes"
+do not break catalog filters:
+
+  {
+    "categories": ["cloud", "web"],
+    "tags": ["kubernetes", "serverless"],
+    "aliases": {"k8s": "kubernetes"}
+  }
+
+Either list may be omitted to allow any value. Errors suggest the canonical
+value of an alias or a near miss, and -suggest replaces such values with it,
+logging a warning, instead of failing the export.
+
+Dates of "Last Updated: date" and "Last Tested: date" watermark lines
+in codelab content are rewritten, keeping their format: Last Updated with
+-updated_at, or the source modification date by default, and Last Tested
+with -tested_at, if set. Use -max_tested_age to fail the export if Last Tested
+is older than the given number of days, or missing.
+
+Use -snippets to expand references to a library of shared snippets,
+such as setup or cleanup instructions. Each Markdown or HTML file of
+the directory is a snippet named after the file, e.g. activate-shell.md.
+A paragraph consisting of {{> activate-shell zone=us-central1-a}} is
+replaced by the snippet content, with {{zone}} substituted by the given
+value, which overrides the -vars value of the same name.
+
+Imports, such as <<fragment.md>> in Markdown, are fetched and inlined
+at export time. Imported fragments may import other fragments, up to 8 levels
+deep; import cycles are reported as errors. Relative paths are resolved
+against the importing file. Use -import_cache to cache remote imports
+in a directory, reused for -import_cache_ttl (1h by default).
+
+A reference to another step in the form of "see Step 4" or
+"see Step 4: Deploy the app" is rendered as a link to the step.
+A reference to a step which does not exist, or with a different title,
+is left as text with a warning.
+
+A glossary is declared in the codelab metadata as a semicolon separated
+list of terms and definitions, e.g. "glossary: VPC: Virtual Private Cloud;
+IAM: Identity and Access Management". The first occurrence of each term
+shows its definition as a tooltip in HTML output, and links to a Glossary
+step in md output, which is added after the last step unless there is one.
+
+A codelab may declare translations in its metadata as a comma separated list
+of locale=source entries, e.g. "translations: es=codelab.es.md, ja=1AbC...".
+Relative file sources are resolved against the codelab source. The codelab
+and its translations are then exported together to <output>/<locale>/<id>,
+where the locale of the codelab is its "locale" metadata, "en" by default.
+The codelab.json file of each lists the directories of all translations
+in "locales", relative to its own, for a language switch.
+
+A codelab whose locale is written from right to left, such as ar, he or fa,
+is rendered with dir="rtl" in HTML output, with code kept left to right.
+In md output, blocks which do not start with a right-to-left letter,
+such as a paragraph starting with a command, start with an invisible
+right-to-left mark so that Markdown viewers lay them out right to left.
+
+Use -md_toc with "-f md" to start the output with a linked table of contents
+of steps and their headings. Anchors are generated as GitHub does for Markdown
+headings and match the id attributes of headings in HTML output.
+
+Use -split with "-f md" or "-f html" to write each step to its own file,
+step-1.md, step-2.md, and so on, along with an index.md listing the steps,
+for static site generators and platforms showing a step per page.
+Markdown step files start with YAML front matter: the step title, codelab ID,
+step number as weight, duration in minutes, and the prev and next step files.
+HTML step files link to the previous and next ones with rel="prev" and
+rel="next". The "steps" of codelab.json list the file of each step, and
+cross-references to steps link to their files. Steps of other environments
+than -e are not written. A codelab exported with -split is split again by
+the update command.
+
+Use -release to export each codelab to a release directory under its
+output directory, such as <output>/<id>/v1, and to record the changes since
+the previous release: "auto" for the next vN release, "tag" for the git
+tag of the commit of the codelab source, either the commit checked out
+in the repository of a local file or that of the ref of a git source,
+or any other name. The releases of
+a codelab are listed in order in its releases.json, and its CHANGELOG.md
+describes the changes of each release: steps added, removed, renamed or
+moved, and text and code blocks added, removed or changed. Changes are
+found by comparing the content of codelabs rather than their output,
+using the outline.json written to each release directory. Exporting to
+an existing release replaces it. The index command only lists the latest
+release of a codelab.
+
+Use -review to export a codelab for review, with the content changed since
+a previous version marked, so that reviewers can focus on the changes:
+blocks of text and code inserted in <ins> elements, and those deleted,
+along with removed steps, in <del> elements of html and offline output, and
+between <!-- inserted --> comments and in <!-- deleted: ... --> comments
+of md output. The previous version is a release directory exported with
+-release, its outline.json, or any source, e.g.
+
+    claat export -review out/my-codelab/v3 -o review 1AbC...
+
+Use -only_status to only export codelabs with one of the given statuses,
+e.g. "-only_status published" in production pipelines, so that drafts and
+codelabs in review do not end up in the catalog. The status is set with the
+Status metadata field, one of draft, review, published, hidden or deprecated.
+Codelabs with another status, or none, are skipped without failing the export.
+Codelabs composed with a manifest are skipped unless each of their parts
+has one of the given statuses.
+
+Use -comments to report the unresolved comments and pending suggested edits
+of Google Docs in comments.json, next to the exported codelab, and to log
+their number. Along with -review, they are also added to the content as
+warnings, after the block of text they refer to. Use -no_open_comments to
+fail the export of docs with any, e.g. before publishing.
 
 Instead of writing to an output directory, use "-o -" to specify
 stdout. In this case images and metadata are not exported.
 When writing to a directory, existing files will be overwritten.
 
+Remote fetches failing with a network error, a rate limit (429 or a 403
+rate limit error of Google APIs) or a server error (5xx) are retried with
+exponential backoff and jitter, from -retry_backoff (2s by default) up to 1m,
+or as requested by a Retry-After header. Google Drive fetches are retried
+up to 7 times, others up to 3 times, unless set with -retries.
+Other errors, such as 404 Not Found, fail right away.
+Use -rate_limit to space out Google API requests, such as Docs exports,
+across all the codelabs of a batch run, e.g. -rate_limit 5 for up to
+5 requests per second, so that they stay within API quotas.
+
+Use -record to record the responses of remote fetches, such as Google Docs,
+images and imports, in the -fixtures directory ("claat-fixtures" by default),
+then -offline to export the same sources later on from these responses only,
+without network access nor Google authorization, e.g. in hermetic tests:
+
+    claat export -record 1AbC...
+    claat export -offline 1AbC...
+
+Offline fetches of responses which were not recorded fail right away.
+Fixtures only hold response bodies and content types, not credentials,
+but they do hold the content of private docs: keep them out of public repos.
+
+Ctrl-C or SIGTERM cancels in-flight fetches and exports, which fail
+with "context canceled". Press Ctrl-C again to exit immediately.
+
+Use -vars to substitute {{key}} references in codelab text and code blocks
+with values from a JSON file, e.g. a project ID or region. Unknown keys are
+left as is, and so are Qwiklabs {{{...}}} expressions, which are resolved
+by the lab runtime instead. Markdown and HTML output escape the braces of both,
+unless -keep_runtime_vars is set, which leaves {{{...}}} expressions
+unescaped for the lab runtime.
+
+Embedded iframes are only kept if the host of their https URL is a domain
+of the built-in allowlist, such as codepen.io or glitch.com. Subdomains
+must be listed too. Use -iframe_domains to allow more domains.
+
+Codelab content rendered as HTML, in the html and offline formats, is
+sanitized so that a source document cannot inject scripts into the page:
+elements and attributes other than those claat renders are removed, such
+as script elements and event handler attributes, along with URLs of schemes
+other than http, https and mailto, such as javascript: links. Use -sanitize
+to allow more, e.g. for custom templates, with a JSON file of "tags" mapping
+element names to their attributes, "attrs" allowed on all elements, where
+"data-*" allows a prefix, and URL "schemes", e.g.
+
+    {"tags": {"kbd": [], "details": ["open"], "summary": []}, "schemes": ["tel"]}
+
+Content which the output format cannot render, such as iframes in the
+offline format, is dropped with a warning naming the step and the kind of
+content. Use -strict to fail the export of such codelabs instead.
+
+The html and offline formats include Open Graph and Twitter card tags,
+so that shared links to a codelab show its title, summary and an image:
+the "image" metadata, or else the first image of the codelab. Social networks
+only fetch absolute URLs: use -site_url, the URL the output directory is
+published at, to make relative image URLs absolute and to set the URL
+of the codelab page.
+
+Use -analytics to inject analytics snippets into html and offline output,
+in place of the legacy -ga account. It is a JSON file of named profiles,
+such as one per site the codelabs are published on; -analytics_profile
+selects one, "default" by default. A profile has a GA4 measurement ID "ga4",
+the https URLs of custom analytics "scripts", and the name of the GA4 event
+sent when a step is shown, "stepEvent", "codelab_step" by default, e.g.
+
+    {"default": {"ga4": "G-XXXXXXXXXX", "scripts": ["https://example.com/a.js"]}}
+
+Custom scripts can listen to the codelab-step event dispatched on window
+when a step is shown, with the codelab ID, step number and title in its
+detail. The profile is kept in codelab.json and used by the update command,
+unless overridden with -analytics.
+
+Use -redact to check exported and updated codelabs for sensitive content
+before rendering their metadata, text, code, links, images and embeds, as
+well as review marks and comments, including comments.json. It is a JSON
+file of named "rules", each a regular expression "pattern" of sensitive
+content, such as project IDs or internal hostnames, with "allow" patterns
+of its matches which are fine, such as sample project IDs; strings always
+fine are listed in a top-level "allow". Well-known credentials, such as
+Google API keys, AWS access keys, GitHub and Slack tokens and private keys,
+are always looked for, also across differently formatted runs of text.
+The export fails with the location of each occurrence, unless "action" is
+"mask", to replace them with [REDACTED] and log a warning instead, e.g.
+
+    {"action": "fail", "allow": ["example.corp.com"],
+     "rules": [{"name": "project", "pattern": "\\bacme-prod-[a-z0-9-]+",
+                "allow": ["acme-prod-sample"]}]}
+
+Use -passes to apply transform passes to the codelab content between
+parsing and rendering. Every export runs the built-in snippets, vars,
+icons, kbd and annotations passes first, then the passes of -passes,
+in order, and the built-in audience, lab-sequence, xrefs,
+number-activities and glossary passes last. Other built-in passes are:
+
+- captions (captions images as figures, see below)
+- emoji (replaces emoji shortcodes such as :rocket: with their emoji,
+  see below)
+- normalize-headings (strips trailing punctuation such as "." or ":" from
+  step titles, demotes h1 headers, reserved for step titles, and closes
+  gaps in heading levels, e.g. h2 followed by h4, with a warning reporting
+  each change)
+- normalize-prompts (rewrites leading "$" and "%" shell prompts of terminal
+  blocks as "$ ", and "#" prompts of root shells started with a command
+  such as "sudo -i", where "#" does not start a comment)
+- strip-prompts (removes leading shell prompts of terminal blocks, so that
+  copied commands do not include them, except in nocopy blocks)
+
+Forks may register additional passes with transform.Register, or
+transform.RegisterCodelab for passes of whole codelabs.
+
+Reference user interface elements with their Material icon name,
+e.g. "click the {icon:menu} menu" or "{icon:more_vert} > Settings".
+Icons are rendered with the Material Icons font in html and offline output,
+and as text such as ☰ in md output, or images with -icon_images, e.g.
+-icon_images https://fonts.gstatic.com/s/i/materialicons/{name}/v1/24px.svg.
+
+Write keyboard shortcuts as [[Ctrl+C]], with keys separated by "+",
+or as <kbd>Ctrl</kbd>+<kbd>C</kbd> in Markdown. They are rendered with
+<kbd> elements in html and offline output, and as inline code keys joined
+with "+" in md output.
+
+Annotate screenshots in the alt text of an image with callouts such as
+{box 10 20 40 30 Open the menu}, {arrow 50 50 35 25} or {marker 80 10 1},
+where x y, or x y x2 y2 for boxes and arrows, are percents of the image size
+and the label is optional. Unlabeled markers are numbered in order. They are
+drawn as an overlay over the image in html and offline output, and are kept
+in the alt text of md output.
+
+With -passes captions, caption an image with italic text following it in
+its paragraph, or with the next paragraph if all of it is italic, or in
+Google Docs with an alt title starting with "Caption:". Captioned images
+are rendered as figures in html and offline output, and followed by an
+italic caption line in md output. Use -number_figures to number them,
+as in "Figure 3: The console", with the label of the language of the
+codelab, e.g. "Abbildung 3" in German, or that of -figure_label, such as
+-figure_label "Fig. {n}".
+
+Use -lightbox with a width in pixels, such as -lightbox 600, to zoom in on
+click on dense screenshots at least that wide, and on images of unknown width,
+in an overlay of html and offline output. Their links are also compatible with
+lightbox scripts such as Lightbox2, and open the image in a new tab otherwise.
+Images in links are left as is.
+
+Provide a variant of an image for dark color schemes, for viewers with a dark
+theme, with {dark console-dark.png} in its alt text, or for local images of
+Markdown codelabs by naming it after the image with a .dark suffix, such as
+console.dark.png for console.png. It is shown instead of the image in html
+and offline output when the color scheme of the viewer is dark; md output
+has the image only, without its variant.
+
+Brand html and offline output with -theme, either a theme bundled with claat,
+such as dark, or a directory of a theme. All .css and .js files of a theme
+are linked from the pages of its codelabs, after the built-in stylesheets,
+and copied along with its other files, such as logos and fonts, to the theme
+directory of their output. The header.html and footer.html fragments of
+a theme, if any, are written at the start and the end of the body of pages.
+Codelabs updated later keep their theme.
+
+Pages of html and offline output print cleanly: each step of html output
+starts a new page, navigation is hidden, links are followed by their URL, and
+headings, code blocks, images and tables are not split across pages. Use
+-paper a4 or -paper letter to set the paper size and margins of printed pages.
+
+Use -pwa for workshops with flaky networks: html and offline output is then
+a progressive web app, with a web app manifest, an icon and a service worker,
+sw.js. Once a codelab is visited, its pages, images and theme, as well as the
+remote scripts and stylesheets of its pages, are cached, so that it works
+offline and may be installed. The cache is refreshed when a codelab is
+exported again. Service workers require codelabs to be served over HTTPS,
+or from localhost.
+
+Use -cloudshell link to follow terminal code blocks with an Open in Cloud Shell
+link, or -cloudshell embed to follow the first terminal code block of each step
+with an embedded Cloud Shell terminal. Cloud Shell clones the repository of the
+codelab and opens its tutorial: for git sources such as
+github.com/owner/repo//labs/intro.md@main, the repository and branch of the
+source, and the tutorial written by "-f tutorial" next to the source, e.g.
+labs/<id>/index.tutorial.md. The cloudshell_git_repo, cloudshell_git_branch,
+cloudshell_tutorial and cloudshell_workspace metadata fields, passed along
+with -pass_metadata, set them for other sources or override them.
+
+Use "-f tutorial" to write the codelab as a Cloud Shell tutorial,
+index.tutorial.md, so that the same source drives both a codelab and a guided
+tutorial in the Cloud Console: each step is a page of the tutorial, terminal
+code blocks are bash blocks Cloud Shell copies to its terminal, and the tutorial
+ends with a trophy. Links to spotlight:<id>, such as
+[Activate Cloud Shell](spotlight:devshell-activate-button), point at a part of
+the console, and links to editor:<path>, such as [main.go](editor:src/main.go),
+open a file of the repository in the Cloud Shell Editor.
+
+Use "-f slides" to write a companion slide deck of the codelab, a reveal.js
+presentation in slides.html: a title slide with its summary, authors and
+duration, then a slide for each step with its title, its first five commands
+of terminal code blocks and its first image at least 200 pixels wide, if any.
+The deck is written next to the codelab exported in another format, whose
+index.html and codelab.json are kept, so that both share its images.
+
+Use -audience instructor and -audience student to export an instructor guide
+and a student handout of the same source. Instructor notes, such as answers,
+common pitfalls and timing guidance, are the content tagged with the instructor
+environment, e.g. with an "Environment: instructor" line in Markdown. Instructor
+guides show them in an "Instructor notes" box, whatever the -e environment,
+and student handouts strip them, along with steps of instructor notes only.
+Without -audience, they are only shown when exporting with -e instructor.
+
+With -passes emoji, emoji shortcodes such as :warning:, :rocket: or
+:white_check_mark: in text, titles and summaries are replaced with their
+emoji; text of code and unknown names are left as is. Use -emoji_images to replace those of paragraphs and
+lists with inline images instead, named after the code points of the emoji,
+e.g. -emoji_images https://cdn.jsdelivr.net/gh/twitter/twemoji@14.0.2/assets/72x72/
+for .../1f680.png. Emoji typed as such are always kept as they are.
+
+A survey with scoring metadata is a graded quiz: points of its questions,
+their correct answers and the score needed to pass. In Markdown, set them with
+<form pass="N">, <name points="N"> and a correct attribute on correct inputs.
+In Google Docs, end a question with [points N] or [pass N] and an option
+with [correct]. Questions are worth 1 point by default. Quizzes are listed
+in "quizzes" of codelab.json for automatic grading, and the md output keeps
+the metadata; the HTML output never reveals correct answers.
+
+Downloadable files, such as starter code archives or datasets, are attached
+in Markdown with <ql-file-download url="data/starter.zip">, the link text and
+</ql-file-download> on separate lines, and an optional name attribute
+for the name of the downloaded file. Local files, relative to the codelab
+source, and remote files are copied to the files directory of the codelab
+output, and shown as a download button in HTML output.
+
+Qwiklabs activity tracking blocks let learners check their progress.
+In Markdown, write <ql-activity-tracking step=N>, the objective title and
+</ql-activity-tracking> on separate lines. In Google Docs, write a step meta
+instruction "Activity tracking: title", or "Activity tracking N: title".
+Blocks without a step number are numbered after the previous block.
+The export fails if step numbers are not unique and sequential from 1.
+
+Code can be included from sample files at export time, so that it never
+drifts from working code. In Markdown, write a fenced code block with
+"include path/to/main.go#L10-L40" in place of the language, and nothing
+inside. The optional #L10-L40 or #L10 suffix selects lines of the file,
+and their common indentation is removed. Local files are relative to the
+codelab source and must be in its directory; GitHub file page URLs are
+fetched from raw.githubusercontent.com. The language is the file extension.
+
+Diff code blocks show changes to make: in Markdown, write "diff" after the
+language of a fenced code block, as in "go diff", or as its language.
+Lines starting with + and - are styled as added and removed in HTML output,
+and kept in a <ql-code-block diff> wrapper in md output. Similarly, write
+{hl_lines=3-5,8} after the language to highlight lines, and {linenos}
+to show line numbers; md output keeps them as highlight="3-5,8" and
+line-numbers attributes of the wrapper. Code blocks are copyable, unless
+tagged nocopy for display-only code, or output for the expected output of
+a command, as in "console output". These are emitted as noCopy and output
+attributes in HTML and md output. In Google Docs, expected output is
+a terminal font (Consolas) table cell with a light gray #efefef background.
+Expected output is read rather than run: it counts as text in duration
+estimates, and claat verify skips it.
+
+Each exported codelab directory also gets a resources.json manifest of
+the external resources the lab depends on: Cloud Storage objects, GitHub
+repositories and Google APIs mentioned in text, links and code blocks,
+with the numbers of the steps referencing them.
+
 The program exits with non-zero code if at least one src could not be exported.
 
+## Course command
+
+"claat course export course.yaml [course.yaml ...]" exports a course:
+an ordered set of codelabs, such as a learning track. The course file
+declares its id, title, summary and labs, in order. Like a codelab ID,
+the course id only contains lowercase letters, digits, dashes and
+underscores, e.g.
+
+    id: cloud-run-track
+    title: Cloud Run from zero to production
+    labs:
+      - source: intro.md
+      - source: 1AbC...
+        prerequisites: [cloud-run-intro]
+
+Each lab is exported like the export command does, with the same options;
+local and remote sources are relative to the course file. A course
+overview page is then written to <output>/<id>/index.html, or index.md
+with "-f md", listing the labs in order with their summaries, durations,
+the cumulative duration of the course and their prerequisites: those
+listed in the course file, which are IDs of earlier labs of the course or
+URLs, and those of the codelab metadata. The export fails if a lab requires
+a lab of the course which does not come before it. The course record, with
+the ID, title and duration of each lab, is written to course.json.
+
+## Diff command
+
+"claat diff old new" parses codelabs 'old' and 'new', which can be any source
+accepted by the export command, and reports how they differ in content rather
+than in rendered output: steps added, removed, renamed or moved, and blocks
+of text and code added, removed or changed within steps. Steps are matched
+by title, or by content if renamed. Either source may be the outline.json
+of a release exported with -release, e.g. to review the changes of a Google
+Doc since it was last published:
+
+    claat diff out/my-codelab/v3/outline.json 1AbC...
+
+The report is a Markdown list, with a diff of the lines of changed code,
+or a JSON array with "-f json".
+
+## I18n command
+
+"claat i18n extract" writes a catalog of the translatable strings of one or
+more 'src' codelabs to <id>.json in the output directory, or to <id>.xlf in
+XLIFF 1.2 format with "-f xliff". Strings are the title, the summary, step
+titles and text, keyed by their path in the codelab. Code is left out.
+
+"claat i18n apply src catalog [catalog ...]" exports a copy of codelab 'src'
+for each translated catalog, like the export command does, with its strings
+replaced by their translations, to <output>/<locale>/<id>, where locale is
+the target locale of the catalog. Code, Qwiklabs ql-* markup and {{{...}}}
+expressions are kept untouched: a translation which does not keep the markup
+of its source is an error. Translations of strings which have changed in
+'src' since extraction are skipped with a warning.
+
+## Index command
+
+Index scans one or more 'src' local directories for codelab.json metadata
+files of exported codelabs, recursively, and writes a catalog page listing
+them to index.html in the output directory, or to stdout with "-o -".
+Current directory is assumed if no 'src' argument is given.
+
+Codelabs are listed by title with their summary and duration, and can be
+filtered by category, duration and the products their steps require.
+Hidden codelabs are left out. Links are relative to the output directory.
+
+Use -f to render the page with a custom Go template file instead. It is
+executed with the Codelabs, each with the fields of its codelab.json, a Link
+and the Products of its steps, and all their Categories and Products.
+
+Use -site_url with the URL the output directory is published at to also
+write sitemap.xml, listing every codelab with its last update date, and
+feed.xml, an Atom feed of the 20 most recently updated codelabs. With
+-site_url, the export command writes them as well, for all codelabs in
+the output directory.
+
+## Lint command
+
+Lint checks one or more 'src' codelabs and reports its findings, one per
+line, or as a JSON array with "-f json", e.g. for security review sign-off.
+A 'src' can be any source accepted by the export command. Local directories
+are scanned for Markdown sources, recursively. The checks are:
+
+    secrets   credentials in code blocks, such as AWS access keys, Google
+              Cloud service account keys, Google API keys, OAuth tokens,
+              GitHub and Slack tokens, JWTs and private keys (error)
+    quizzes   invalid quizzes, as with "claat quiz validate" (error)
+    buttons   console buttons not linking to the Cloud console (warning)
+    duration  steps without a duration, or a codelab duration differing
+              from the sum of step durations (warning)
+
+Findings only show the start of credentials. The secrets check is independent
+of -redact, which does not apply. The exit code is 1 if there is any error.
+
+## Preview command
+
+Preview exports one or more 'src' codelabs, like the export command does,
+and uploads them to a new directory with a random name in the Cloud Storage
+bucket given with -bucket, under previews/. It prints a shareable URL
+of each codelab for reviewers, along with its expiry. The status of each
+codelab, such as draft or review, is stamped at the top of its first step.
+
+Uploads are authorized with Application Default Credentials, as with -adc.
+The bucket must be readable by reviewers, e.g. publicly. Previews expire
+after -preview_ttl, 72h by default: uploaded objects have their custom time
+set to the expiry, and are deleted by a lifecycle rule of the bucket
+with a daysSinceCustomTime condition of 0, which has to be set once:
+
+    gsutil lifecycle set lifecycle.json gs://bucket
+
+where lifecycle.json is
+{"rule": [{"action": {"type": "Delete"}, "condition": {"daysSinceCustomTime": 0}}]}.
+
+## Quiz command
+
+"claat quiz validate" checks the quizzes of one or more 'src' codelabs offline,
+without exporting them: every question must have exactly one correct answer,
+or at least one if it is multi-select, free text questions cannot be graded,
+and the pass threshold cannot exceed the maximum score. Local directories
+are scanned for Markdown sources, recursively. The program exits with
+non-zero code if any quiz has a problem. Export reports the same problems
+as warnings.
+
 ## Serve command
 
 Serve provides a simple web server for viewing exported codelabs.
@@ -187,6 +1215,48 @@ it would appear in production.
 The serve command takes a -addr host:port option, to specify the
 desired hostname or IP address and port number to bind to.
 
+With -api, serve provides an HTTP API rendering codelabs instead,
+for services which would otherwise run claat for each codelab:
+
+- POST /render?fmt=md renders the Markdown codelab in the request body
+- GET /export?doc=<id>&fmt=md exports the Google Doc <id>
+
+The fmt parameter is html, the default, or md. Codelabs are rendered with
+the other export options, such as -e, -prefix and -passes, but images and
+metadata are not stored. Google Docs are fetched with the credentials of
+the server, e.g. with -adc. The API has no authentication of its own:
+bind it to a private address or put it behind an authenticating proxy.
+
+Specify -hardened when the API or gRPC service renders codelabs of
+untrusted users, so that a single malicious doc cannot exhaust it.
+Sources and images larger than 5 MB, codelabs of more than 50000 content
+nodes and exports taking more than a minute fail, and so do codelabs with
+imports, code includes, attachments, manifests or images of other hosts
+than the codelab and Google Docs, which would fetch other URLs or read
+files of the server. Timed out exports respond 503 Service Unavailable
+over HTTP and DEADLINE_EXCEEDED over gRPC. -hardened applies to the other
+commands as well, such as the worker.
+
+With -grpc, serve provides the same rendering as the claat.v1.Converter
+gRPC service defined in rpc/claat.proto, over HTTP/2 without TLS, as used
+on Cloud Run (use -addr :$PORT there). RenderStream takes the source in chunks and
+streams the output back in chunks, for large docs. Messages may be gzip
+compressed, and the deadline of calls bounds their export.
+
+On Ctrl-C or SIGTERM, servers stop accepting connections, cancel
+the exports of in-flight requests, and exit once they have completed.
+
+## Stats command
+
+Stats reports content statistics of one or more 'src' codelabs: the number
+of steps, images, code blocks, external links, info boxes and videos, along
+with the declared and the estimated duration in minutes.
+A 'src' can be any source accepted by the export command. Local directories
+are scanned for Markdown sources, recursively.
+
+The report is a table, or a JSON array with "-f json".
+Video lengths are included in the estimate if YOUTUBE_API_KEY is set.
+
 ## Update command
 
 Update scans one or more 'src' local directories for codelab.json metadata
@@ -203,12 +1273,148 @@ In the latter case, where codelab ID has changed, the new directory
 will be placed alongside the old one. In other words, it will have the same ancestor
 as the old one.
 
-While -prefix and -ga can override existing codelab metadata, the other
-arguments have no effect during update.
+The output format, environment and other output options of the metadata
+are kept, while -prefix and -ga can override them. Content options, such as
+limits, -passes and redaction, apply as they do to exports,
+and the provenance of updated codelabs is recorded anew.
 
 The program does not follow symbolic links and exits with non-zero code
 if no metadata found or at least one src could not be updated.
 
+With -registry, update exports the codelabs listed in a JSON file instead,
+regardless of the metadata found in their directories:
+
+  {
+    "profiles": {
+      "offline": {"format": "offline", "env": "kiosk", "passes": ["a", "b"]}
+    },
+    "codelabs": [
+      {"source": "drive://folderID", "output": "site", "tags": ["web"]},
+      {"source": "labs/intro.md", "output": "kiosk", "profile": "offline"}
+    ]
+  }
+
+Outputs are relative to the registry file, and the fields of a profile,
+all optional, replace the -f, -e, -passes, -prefix and -audience arguments.
+The instructor and student profiles are built in, unless the registry
+defines profiles of the same name.
+A codelab is exported again only if it is stale: not exported yet to its
+output, or its source or any other source it was read from has a new
+revision, or its profile has changed since, according to the provenance
+recorded in its codelab.json. Images and attachments of previous exports
+which are no longer used are then removed. Up to 8 codelabs are checked
+and exported at once.
+
+-category and -tag only update the codelabs in one of the categories and
+with one of the tags, respectively, with or without -registry. The categories
+and tags of a registered source apply until it is first exported, after
+which those of its codelabs do.
+
+## Verify command
+
+Verify checks the code blocks of one or more 'src' codelabs tagged as
+runnable, such as Markdown fenced code with a "bash runnable" info string,
+by running a verifier command for the language of each block.
+Terminal blocks are of language "console".
+The -verifiers flag names a JSON file mapping languages to shell commands:
+
+    {"bash": "bash -n \"$SNIPPET\"", "go": "gofmt -e \"$SNIPPET\""}
+
+Each command runs in a new temporary directory holding the code of the block
+in a file, named snippet followed by the language as extension, whose path is
+in the SNIPPET environment variable. Local directories are scanned for Markdown
+sources, recursively. Failures are reported per step, and the program exits
+with non-zero code if any verifier command fails. Runnable code of languages
+without a verifier is reported as a warning.
+
+## Webhook command
+
+Webhook is a long-running mode re-exporting codelabs when notified that
+their source has changed, for near-real-time publishing of doc edits.
+It listens on -addr for POST requests and re-exports the codelabs found
+in the -o directory, recursively, like the update command does. Serve or
+sync that directory to publish them.
+
+Webhooks are authenticated with the -webhook_token shared secret:
+
+- POST /drive takes Drive push notifications, of files.watch channels
+  created with the secret as channel token
+- POST /export?src=<source> takes generic webhooks, with the secret
+  as "Authorization: Bearer" token, such as from CI
+
+Only codelabs previously exported to the -o directory are re-exported,
+by their doc ID or source path. A codelab changed again while being
+re-exported is re-exported once more when done.
+
+## Worker command
+
+Worker is a long-running mode exporting codelabs of jobs pulled from the
+-subscription Pub/Sub subscription, one at a time, such as:
+
+    {"source": "<doc id>", "format": "md", "output": "gs://bucket/labs"}
+
+The format defaults to -f. The output is a directory relative to -o, or a
+gs://<bucket>/<prefix> Cloud Storage location. Jobs are exported with the
+other export options and authorized with Application Default Credentials,
+which also need Pub/Sub and Cloud Storage access. The ack deadline of the job
+being exported is extended every 30 seconds until it is done.
+
+A job failing is tried up to -max_attempts times, with exponential backoff.
+It is then published to the -dead_letter_topic topic, with an "error"
+attribute, or redelivered if there is none, e.g. to use the dead-letter policy
+of the subscription: after a minute, doubled for each delivery attempt counted
+by the dead-letter policy, up to 10 minutes.
+
+Once a job is done or has failed, a completion event is published to the
+-topic topic, if any, with a "status" attribute of "done" or "failed":
+
+    {"job": {...}, "messageId": "...", "status": "done", "id": "<codelab id>", "attempts": 1}
+
+## Logging
+
+Log entries are written to stderr, with fields such as the source and ID
+of the codelab and the step a warning is about. Specify -verbosity debug
+to also log the progress of each codelab, or warn or error to log less.
+Specify -log_format json to write a JSON object per entry instead, with
+its time, level, msg and fields, for searchable pipeline logs.
+
+Export logs the progress of each codelab once exported, such as 3/120,
+and the time it spent fetching, parsing, fetching assets, transforming
+and writing. Specify -progress to also draw a progress bar of the export
+on stderr, if it is a terminal, and -quiet to only log warnings and errors.
+
+## OpenTelemetry
+
+Specify -otlp_endpoint, or the OTEL_EXPORTER_OTLP_ENDPOINT environment
+variable, with the URL of an OpenTelemetry collector to export traces and
+metrics to, with OTLP over HTTP in protobuf. The other standard
+OTEL_EXPORTER_OTLP_* variables apply, such as OTEL_EXPORTER_OTLP_HEADERS
+for API keys. Each codelab export is a claat.export span, with a child span
+for each phase. Requests to serve -api and -grpc continue the trace of their
+W3C traceparent header. Metrics are the claat.codelabs.exported
+and claat.export.errors counters, whose error.type is the phase the export
+failed in, and the claat.render.duration histogram. Data is exported every
+10 seconds and when the command exits, for long-running commands such as
+serve -api, webhook and worker.
+
+## Profiling
+
+Specify -profile to write pprof profiles of a run, such as a slow or
+memory-hungry export, without rebuilding claat: cpu for the CPU time of
+the whole run, and mem for the heap allocations at its end.
+
+    claat export -profile cpu=cpu.pprof,mem=mem.pprof 1AbC...
+    go tool pprof -top cpu.pprof
+
+## Telemetry
+
+Usage reporting is off by default. Specify -telemetry with an endpoint URL
+to opt in. Each command run then posts a single anonymous JSON report
+containing the command, output format, duration, error class (usage for
+invalid flags or arguments, canceled for interrupted commands, or command
+for failed ones), claat version, OS and architecture. No source IDs, file
+names or content are ever sent.
+
 ## Flags
 
 `
@@ -0,0 +1,40 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodes
+
+// NewActivityNode creates a new Qwiklabs activity tracking block
+// for tracked step number step, starting at 1, or 0 to number it
+// automatically, with content n, usually the title of the objective.
+func NewActivityNode(step int, n ...Node) *ActivityNode {
+	return &ActivityNode{
+		node:    node{typ: NodeActivity},
+		Step:    step,
+		Content: NewListNode(n...),
+	}
+}
+
+// ActivityNode is a Qwiklabs activity tracking block, rendered as
+// a <ql-activity-tracking step=N> element, which lets learners
+// check their progress on the objective it describes.
+type ActivityNode struct {
+	node
+	Step    int // Tracked step number, starting at 1; 0 if not numbered yet
+	Content *ListNode
+}
+
+// Empty returns false: a tracking block is a checkpoint even without content.
+func (an *ActivityNode) Empty() bool {
+	return false
+}
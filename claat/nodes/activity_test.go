@@ -0,0 +1,23 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewActivityNode(t *testing.T) {
+	text := NewTextNode(NewTextNodeOptions{Value: "Create a bucket"})
+	got := NewActivityNode(2, text)
+	want := &ActivityNode{
+		node:    node{typ: NodeActivity},
+		Step:    2,
+		Content: NewListNode(text),
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(ActivityNode{}, node{}, ListNode{}, TextNode{})); diff != "" {
+		t.Errorf("NewActivityNode(2, %v) got diff (-want +got):\n%s", text, diff)
+	}
+	if NewActivityNode(0).Empty() {
+		t.Errorf("NewActivityNode(0).Empty() = true; want false")
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodes
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kinds of image annotations.
+const (
+	AnnotationArrow  = "arrow"  // An arrow from X, Y to X2, Y2
+	AnnotationBox    = "box"    // A callout box from X, Y to X2, Y2
+	AnnotationMarker = "marker" // A numbered marker centered on X, Y
+)
+
+// Annotation is a callout drawn over an image, such as a box around
+// a button of a screenshot, rather than baked into the image itself.
+// Coordinates are percentages of the image width and height, from its
+// top left corner, so that annotations follow the image when it is resized.
+type Annotation struct {
+	Kind   string  // AnnotationArrow, AnnotationBox or AnnotationMarker
+	X, Y   float64 // Top left corner of a box, tail of an arrow or center of a marker
+	X2, Y2 float64 // Bottom right corner of a box or head of an arrow
+	Label  string  // Text of a box or marker
+}
+
+// annotationRegexp matches an {arrow x y x2 y2}, {box x y x2 y2 label}
+// or {marker x y label} annotation in image alt text.
+var annotationRegexp = regexp.MustCompile(`\{(arrow|box|marker)((?:\s+[0-9.]+)+)(?:\s+([^{}]*?))?\s*\}`)
+
+// ParseAnnotations returns the annotations of image alt text, and the alt
+// text without them. Malformed annotations are left in the text.
+// Markers without a label are numbered from 1, in order.
+func ParseAnnotations(alt string) (string, []Annotation) {
+	var aa []Annotation
+	markers := 0
+	rest := annotationRegexp.ReplaceAllStringFunc(alt, func(m string) string {
+		sm := annotationRegexp.FindStringSubmatch(m)
+		a := Annotation{Kind: sm[1], Label: sm[3]}
+		coords := strings.Fields(sm[2])
+		want := 4
+		if a.Kind == AnnotationMarker {
+			want = 2
+		}
+		if len(coords) < want {
+			return m
+		}
+		if len(coords) > want {
+			// a numeric label, such as the number of a marker
+			a.Label = strings.TrimSpace(strings.Join(coords[want:], " ") + " " + a.Label)
+		}
+		vv := make([]float64, 4)
+		for i, c := range coords[:want] {
+			v, err := strconv.ParseFloat(c, 64)
+			if err != nil || v < 0 || v > 100 {
+				return m
+			}
+			vv[i] = v
+		}
+		a.X, a.Y, a.X2, a.Y2 = vv[0], vv[1], vv[2], vv[3]
+		switch a.Kind {
+		case AnnotationArrow:
+			a.Label = ""
+		case AnnotationMarker:
+			markers++
+			if a.Label == "" {
+				a.Label = strconv.Itoa(markers)
+			}
+		}
+		aa = append(aa, a)
+		return ""
+	})
+	if aa == nil {
+		return alt, nil
+	}
+	return strings.Join(strings.Fields(rest), " "), aa
+}
+
+// String returns a in the alt text syntax of ParseAnnotations.
+func (a Annotation) String() string {
+	f := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	s := fmt.Sprintf("{%s %s %s", a.Kind, f(a.X), f(a.Y))
+	if a.Kind != AnnotationMarker {
+		s += fmt.Sprintf(" %s %s", f(a.X2), f(a.Y2))
+	}
+	if a.Label != "" && a.Kind != AnnotationArrow {
+		s += " " + a.Label
+	}
+	return s + "}"
+}
@@ -0,0 +1,68 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseAnnotations(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		alt  string
+		aa   []Annotation
+	}{
+		{
+			name: "None",
+			in:   "Cloud console",
+			alt:  "Cloud console",
+		},
+		{
+			name: "All",
+			in:   "Cloud console {box 10 20 40 30 Open the menu} {arrow 50 50 35.5 25} {marker 80 10} {marker 90 10 B}",
+			alt:  "Cloud console",
+			aa: []Annotation{
+				{Kind: AnnotationBox, X: 10, Y: 20, X2: 40, Y2: 30, Label: "Open the menu"},
+				{Kind: AnnotationArrow, X: 50, Y: 50, X2: 35.5, Y2: 25},
+				{Kind: AnnotationMarker, X: 80, Y: 10, Label: "1"},
+				{Kind: AnnotationMarker, X: 90, Y: 10, Label: "B"},
+			},
+		},
+		{
+			name: "NumericLabel",
+			in:   "{marker 5 5 3} Menu",
+			alt:  "Menu",
+			aa:   []Annotation{{Kind: AnnotationMarker, X: 5, Y: 5, Label: "3"}},
+		},
+		{
+			name: "Malformed",
+			in:   "Menu {box 10 20} {arrow 10 20 30 140} {circle 1 2}",
+			alt:  "Menu {box 10 20} {arrow 10 20 30 140} {circle 1 2}",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			alt, aa := ParseAnnotations(tc.in)
+			if alt != tc.alt {
+				t.Errorf("ParseAnnotations(%q) alt = %q; want %q", tc.in, alt, tc.alt)
+			}
+			if diff := cmp.Diff(tc.aa, aa); diff != "" {
+				t.Errorf("ParseAnnotations(%q) got diff (-want +got):\n%s", tc.in, diff)
+			}
+		})
+	}
+}
+
+func TestAnnotationString(t *testing.T) {
+	in := "{box 10 20 40 30 Open the menu} {arrow 50 50 35.5 25} {marker 80 10 1}"
+	_, aa := ParseAnnotations(in)
+	var got []string
+	for _, a := range aa {
+		got = append(got, a.String())
+	}
+	want := []string{"{box 10 20 40 30 Open the menu}", "{arrow 50 50 35.5 25}", "{marker 80 10 1}"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("String got diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodes
+
+import "strings"
+
+// NewAttachmentNode creates a new downloadable file attachment
+// located at src, saved as file name, with link content n.
+func NewAttachmentNode(src, name string, n ...Node) *AttachmentNode {
+	return &AttachmentNode{
+		node:    node{typ: NodeAttachment},
+		Src:     src,
+		Name:    name,
+		Content: NewListNode(n...),
+	}
+}
+
+// AttachmentNode is a downloadable file, such as a starter code archive
+// or a dataset. Export copies the file next to the codelab and rewrites Src.
+type AttachmentNode struct {
+	node
+	Src     string // File URL or local path
+	Name    string // File name of the download; base name of Src if empty
+	Content *ListNode
+}
+
+// Empty returns true if an's Src is zero, excluding space runes.
+func (an *AttachmentNode) Empty() bool {
+	return strings.TrimSpace(an.Src) == ""
+}
+
+// FileName returns the file name of the download.
+func (an *AttachmentNode) FileName() string {
+	if an.Name != "" {
+		return an.Name
+	}
+	src := an.Src
+	if i := strings.IndexAny(src, "?#"); i >= 0 {
+		src = src[:i]
+	}
+	return src[strings.LastIndex(src, "/")+1:]
+}
+
+// AttachmentNodes returns all attachment nodes of nn, recursively.
+func AttachmentNodes(nn []Node) []*AttachmentNode {
+	var res []*AttachmentNode
+	WalkNodes(nn, func(n Node, entering bool) (Node, error) {
+		if an, ok := n.(*AttachmentNode); ok && entering {
+			res = append(res, an)
+			return n, SkipChildren
+		}
+		return n, nil
+	})
+	return res
+}
@@ -0,0 +1,57 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewAttachmentNode(t *testing.T) {
+	text := NewTextNode(NewTextNodeOptions{Value: "Starter code"})
+	got := NewAttachmentNode("starter.zip", "code.zip", text)
+	want := &AttachmentNode{
+		node:    node{typ: NodeAttachment},
+		Src:     "starter.zip",
+		Name:    "code.zip",
+		Content: NewListNode(text),
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(AttachmentNode{}, node{}, ListNode{}, TextNode{})); diff != "" {
+		t.Errorf("NewAttachmentNode() got diff (-want +got):\n%s", diff)
+	}
+	if got.Empty() {
+		t.Errorf("NewAttachmentNode(%q).Empty() = true; want false", got.Src)
+	}
+	if n := NewAttachmentNode(" ", ""); !n.Empty() {
+		t.Errorf("NewAttachmentNode(%q).Empty() = false; want true", n.Src)
+	}
+}
+
+func TestAttachmentFileName(t *testing.T) {
+	tests := []struct {
+		src, name, out string
+	}{
+		{"data/set.csv", "", "set.csv"},
+		{"https://example.com/dl/starter.zip?v=2#top", "", "starter.zip"},
+		{"https://example.com/dl?id=1", "data.csv", "data.csv"},
+		{"starter.zip", "", "starter.zip"},
+	}
+	for _, tc := range tests {
+		if got := NewAttachmentNode(tc.src, tc.name).FileName(); got != tc.out {
+			t.Errorf("NewAttachmentNode(%q, %q).FileName() = %q; want %q", tc.src, tc.name, got, tc.out)
+		}
+	}
+}
+
+func TestAttachmentNodes(t *testing.T) {
+	a1 := NewAttachmentNode("a.zip", "")
+	a2 := NewAttachmentNode("b.csv", "")
+	nn := []Node{
+		a1,
+		NewInfoboxNode(InfoboxPositive, NewListNode(a2)),
+		NewTextNode(NewTextNodeOptions{Value: "text"}),
+	}
+	got := AttachmentNodes(nn)
+	if len(got) != 2 || got[0] != a1 || got[1] != a2 {
+		t.Errorf("AttachmentNodes() = %v; want [%v %v]", got, a1, a2)
+	}
+}
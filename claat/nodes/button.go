@@ -1,5 +1,7 @@
 package nodes
 
+import "strings"
+
 // TODO this is a long arg signature. Maybe use an options type?
 // NewButtonNode creates a new button with optional content nodes n.
 func NewButtonNode(raise, color, download bool, n ...Node) *ButtonNode {
@@ -18,9 +20,45 @@ type ButtonNode struct {
 	Raise    bool
 	Color    bool
 	Download bool
+	Variant  ButtonVariant
 	Content  *ListNode
 }
 
+// ButtonVariant is the style of a button.
+type ButtonVariant string
+
+// Button variants. The default variant is the zero value.
+const (
+	ButtonPrimary   ButtonVariant = "primary"
+	ButtonSecondary ButtonVariant = "secondary"
+	// ButtonConsole opens the Google Cloud console,
+	// e.g. "Open Google Cloud console".
+	ButtonConsole ButtonVariant = "console"
+)
+
+// ParseButtonVariant returns the button variant named s, ignoring case,
+// or the default variant if s is not a known variant.
+func ParseButtonVariant(s string) ButtonVariant {
+	switch v := ButtonVariant(strings.ToLower(strings.TrimSpace(s))); v {
+	case ButtonPrimary, ButtonSecondary, ButtonConsole:
+		return v
+	}
+	return ""
+}
+
+// ButtonVariantOf returns the variant of a button with text s:
+// ButtonConsole for "Open Google Cloud console" or "Open console",
+// the default variant otherwise.
+func ButtonVariantOf(s string) ButtonVariant {
+	t := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	switch t {
+	case "open console", "open the console", "open cloud console",
+		"open google cloud console", "open the google cloud console":
+		return ButtonConsole
+	}
+	return ""
+}
+
 // Empty returns true if its content is empty.
 func (bn *ButtonNode) Empty() bool {
 	return bn.Content.Empty()
@@ -128,3 +128,39 @@ func TestButtonNodeEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestParseButtonVariant(t *testing.T) {
+	tests := []struct {
+		in  string
+		out ButtonVariant
+	}{
+		{"primary", ButtonPrimary},
+		{" Secondary ", ButtonSecondary},
+		{"console", ButtonConsole},
+		{"fancy", ""},
+		{"", ""},
+	}
+	for _, tc := range tests {
+		if out := ParseButtonVariant(tc.in); out != tc.out {
+			t.Errorf("ParseButtonVariant(%q) = %q, want %q", tc.in, out, tc.out)
+		}
+	}
+}
+
+func TestButtonVariantOf(t *testing.T) {
+	tests := []struct {
+		in  string
+		out ButtonVariant
+	}{
+		{"Open Google Cloud console", ButtonConsole},
+		{"open  console", ButtonConsole},
+		{"Open the Google Cloud Console", ButtonConsole},
+		{"Download SDK", ""},
+		{"Open console tips", ""},
+	}
+	for _, tc := range tests {
+		if out := ButtonVariantOf(tc.in); out != tc.out {
+			t.Errorf("ButtonVariantOf(%q) = %q, want %q", tc.in, out, tc.out)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package nodes
 
-import "strings"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // NewCodeNode creates a new Node of type NodeCode.
 // Use term argument to specify a terminal output.
@@ -20,9 +24,91 @@ type CodeNode struct {
 	Term  bool
 	Lang  string
 	Value string
+	// Src is the location of a file to include the code from, with an optional
+	// "#L10-L40" line range. The fetch package replaces Value with its content.
+	Src string
+	// Diff tags code showing changes, with lines added and removed
+	// starting with "+" and "-".
+	Diff bool
+	// Runnable tags code meant to be copied and run by learners,
+	// which "claat verify" checks.
+	Runnable bool
+	// Highlight is the lines of code to highlight.
+	Highlight []LineRange
+	// LineNumbers shows line numbers next to the code.
+	LineNumbers bool
+	// NoCopy tags display-only code, which learners should not copy.
+	NoCopy bool
+	// Output tags the expected output of a command, which is display-only.
+	Output bool
 }
 
-// Empty returns true if cn.Value is zero, exluding space runes.
+// Highlighted reports whether line number line, from 1, of cn is highlighted.
+func (cn *CodeNode) Highlighted(line int) bool {
+	for _, r := range cn.Highlight {
+		if line >= r.Start && line <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// LineRange is a range of lines, numbered from 1, End included.
+type LineRange struct {
+	Start, End int
+}
+
+// ParseLineRanges parses comma-separated line numbers and ranges,
+// such as "3-5,8".
+func ParseLineRanges(s string) ([]LineRange, error) {
+	var res []LineRange
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		start, end := f, f
+		if i := strings.IndexByte(f, '-'); i >= 0 {
+			start, end = f[:i], f[i+1:]
+		}
+		var r LineRange
+		var err1, err2 error
+		r.Start, err1 = strconv.Atoi(strings.TrimSpace(start))
+		r.End, err2 = strconv.Atoi(strings.TrimSpace(end))
+		if err1 != nil || err2 != nil || r.Start < 1 || r.End < r.Start {
+			return nil, fmt.Errorf("invalid line range %q", f)
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// FormatLineRanges formats rr as ParseLineRanges parses them.
+func FormatLineRanges(rr []LineRange) string {
+	ss := make([]string, len(rr))
+	for i, r := range rr {
+		ss[i] = strconv.Itoa(r.Start)
+		if r.End != r.Start {
+			ss[i] += "-" + strconv.Itoa(r.End)
+		}
+	}
+	return strings.Join(ss, ",")
+}
+
+// Empty returns true if cn.Value is zero, exluding space runes,
+// and cn is not a code include.
 func (cn *CodeNode) Empty() bool {
-	return strings.TrimSpace(cn.Value) == ""
+	return cn.Src == "" && strings.TrimSpace(cn.Value) == ""
+}
+
+// CodeIncludes returns the code nodes of nn with a Src, recursively.
+func CodeIncludes(nn []Node) []*CodeNode {
+	var res []*CodeNode
+	WalkNodes(nn, func(n Node, entering bool) (Node, error) {
+		if cn, ok := n.(*CodeNode); ok && entering && cn.Src != "" {
+			res = append(res, cn)
+		}
+		return n, nil
+	})
+	return res
 }
@@ -104,3 +104,61 @@ func TestCodeNodeEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestCodeIncludes(t *testing.T) {
+	inc := NewCodeNode("", false, "go")
+	inc.Src = "main.go#L1-L2"
+	if inc.Empty() {
+		t.Errorf("CodeNode{Src: %q}.Empty() = true; want false", inc.Src)
+	}
+	nn := []Node{
+		NewCodeNode("fmt.Println()", false, "go"),
+		NewListNode(NewInfoboxNode(InfoboxPositive, inc)),
+	}
+	got := CodeIncludes(nn)
+	if diff := cmp.Diff([]*CodeNode{inc}, got, cmp.AllowUnexported(CodeNode{}, node{})); diff != "" {
+		t.Errorf("CodeIncludes got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseLineRanges(t *testing.T) {
+	tests := []struct {
+		in      string
+		out     []LineRange
+		wantErr bool
+	}{
+		{in: "3", out: []LineRange{{3, 3}}},
+		{in: "3-5, 8", out: []LineRange{{3, 5}, {8, 8}}},
+		{in: "", out: nil},
+		{in: "5-3", wantErr: true},
+		{in: "0", wantErr: true},
+		{in: "a-b", wantErr: true},
+	}
+	for _, tc := range tests {
+		out, err := ParseLineRanges(tc.in)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseLineRanges(%q) error = %v; want error %v", tc.in, err, tc.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(tc.out, out); diff != "" {
+			t.Errorf("ParseLineRanges(%q) got diff (-want +got):\n%s", tc.in, diff)
+		}
+		if !tc.wantErr && tc.in != "" {
+			if s, _ := ParseLineRanges(FormatLineRanges(out)); !cmp.Equal(out, s) {
+				t.Errorf("FormatLineRanges(%v) = %q does not parse back", out, FormatLineRanges(out))
+			}
+		}
+	}
+}
+
+func TestHighlighted(t *testing.T) {
+	n := NewCodeNode("a\nb\nc\nd\n", false, "")
+	n.Highlight = []LineRange{{2, 3}}
+	var got []bool
+	for line := 1; line <= 4; line++ {
+		got = append(got, n.Highlighted(line))
+	}
+	if diff := cmp.Diff([]bool{false, true, true, false}, got); diff != "" {
+		t.Errorf("Highlighted got diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodes
+
+import (
+	"path"
+	"strings"
+)
+
+// EnvNegate is the prefix of an environment tag excluding a node
+// from the named environment, e.g. "!web".
+const EnvNegate = "!"
+
+// MatchEnv reports whether content tagged with tags is visible in env.
+//
+// Each tag is either an exact environment name, a glob pattern
+// in the syntax of path.Match, e.g. "qwiklabs-*", or either of the two
+// prefixed with EnvNegate.
+// Content is visible when env matches none of the negated tags and
+// at least one of the positive tags, if there are any.
+// Empty tags or env always match.
+func MatchEnv(tags []string, env string) bool {
+	if len(tags) == 0 || env == "" {
+		return true
+	}
+	var pos, ok bool
+	for _, t := range tags {
+		if strings.HasPrefix(t, EnvNegate) {
+			if matchEnvTag(t[len(EnvNegate):], env) {
+				return false
+			}
+			continue
+		}
+		pos = true
+		ok = ok || matchEnvTag(t, env)
+	}
+	return ok || !pos
+}
+
+// IsEnvPattern returns true if tag is a negated or glob environment tag,
+// as opposed to a concrete environment name.
+func IsEnvPattern(tag string) bool {
+	return strings.HasPrefix(tag, EnvNegate) || strings.ContainsAny(tag, `*?[\`)
+}
+
+// EnvNames returns concrete environment names of tags,
+// skipping negated and glob tags.
+func EnvNames(tags []string) []string {
+	var names []string
+	for _, t := range tags {
+		if !IsEnvPattern(t) {
+			names = append(names, t)
+		}
+	}
+	return names
+}
+
+// matchEnvTag reports whether a single non-negated tag matches env.
+// Malformed patterns only match themselves.
+func matchEnvTag(tag, env string) bool {
+	if tag == env {
+		return true
+	}
+	ok, err := path.Match(tag, env)
+	return err == nil && ok
+}
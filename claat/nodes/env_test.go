@@ -0,0 +1,115 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMatchEnv(t *testing.T) {
+	tests := []struct {
+		name   string
+		inTags []string
+		inEnv  string
+		out    bool
+	}{
+		{
+			name: "Empty",
+			out:  true,
+		},
+		{
+			name:  "NoTags",
+			inEnv: "web",
+			out:   true,
+		},
+		{
+			name:   "NoEnv",
+			inTags: []string{"web"},
+			out:    true,
+		},
+		{
+			name:   "Exact",
+			inTags: []string{"kiosk", "web"},
+			inEnv:  "web",
+			out:    true,
+		},
+		{
+			name:   "NoMatch",
+			inTags: []string{"kiosk", "web"},
+			inEnv:  "qwiklabs",
+		},
+		{
+			name:   "Negated",
+			inTags: []string{"!web"},
+			inEnv:  "web",
+		},
+		{
+			name:   "NegatedOther",
+			inTags: []string{"!web"},
+			inEnv:  "kiosk",
+			out:    true,
+		},
+		{
+			name:   "NegationWins",
+			inTags: []string{"!qwiklabs-beta", "qwiklabs-*"},
+			inEnv:  "qwiklabs-beta",
+		},
+		{
+			name:   "Glob",
+			inTags: []string{"qwiklabs-*"},
+			inEnv:  "qwiklabs-gcp",
+			out:    true,
+		},
+		{
+			name:   "GlobNoMatch",
+			inTags: []string{"qwiklabs-*"},
+			inEnv:  "web",
+		},
+		{
+			name:   "NegatedGlob",
+			inTags: []string{"!qwiklabs-*"},
+			inEnv:  "qwiklabs-gcp",
+		},
+		{
+			name:   "MalformedPattern",
+			inTags: []string{"[web"},
+			inEnv:  "web",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if out := MatchEnv(tc.inTags, tc.inEnv); out != tc.out {
+				t.Errorf("MatchEnv(%q, %q) = %t, want %t", tc.inTags, tc.inEnv, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestEnvNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		inTags []string
+		out    []string
+	}{
+		{
+			name: "Empty",
+		},
+		{
+			name:   "Names",
+			inTags: []string{"kiosk", "web"},
+			out:    []string{"kiosk", "web"},
+		},
+		{
+			name:   "Patterns",
+			inTags: []string{"!web", "kiosk", "qwiklabs-*", "qwiklabs-?", "[ab]"},
+			out:    []string{"kiosk"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.out, EnvNames(tc.inTags)); diff != "" {
+				t.Errorf("EnvNames(%q) got diff (-want +got): %s", tc.inTags, diff)
+			}
+		})
+	}
+}
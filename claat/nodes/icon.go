@@ -0,0 +1,36 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodes
+
+// NewIconNode creates a new icon of a user interface element,
+// named after its Material icon, such as "menu".
+func NewIconNode(name string) *IconNode {
+	return &IconNode{
+		node: node{typ: NodeIcon},
+		Name: name,
+	}
+}
+
+// IconNode is an inline icon of a user interface element, such as
+// the ☰ menu, referenced in instructions.
+type IconNode struct {
+	node
+	Name string // Material icon name, such as "more_vert"
+}
+
+// Empty returns true if in has no icon name.
+func (in *IconNode) Empty() bool {
+	return in.Name == ""
+}
@@ -0,0 +1,21 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewIconNode(t *testing.T) {
+	got := NewIconNode("menu")
+	want := &IconNode{node: node{typ: NodeIcon}, Name: "menu"}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(IconNode{}, node{})); diff != "" {
+		t.Errorf("NewIconNode got diff (-want +got):\n%s", diff)
+	}
+	if got.Empty() {
+		t.Error("NewIconNode(menu).Empty() = true; want false")
+	}
+	if !IsInline(got.Type()) {
+		t.Error("IsInline(NodeIcon) = false; want true")
+	}
+}
@@ -1,7 +1,13 @@
 package nodes
 
-// iframe allowlist - set of domains allow to embed iframes in a codelab.
-// TODO make this configurable somehow
+import (
+	"net/url"
+	"strings"
+)
+
+// IframeAllowlist is the set of domains allowed to be embedded in iframes
+// of a codelab by default. Parsers drop iframes of other domains,
+// unless they are allowed by parser.Options.IframeDomains.
 var IframeAllowlist = []string{
 	"carto.com",
 	"codepen.io",
@@ -27,7 +33,26 @@ func NewIframeNode(url string) *IframeNode {
 	}
 }
 
-// IframeNode is an embeddes iframe.
+// IframeAllowed reports whether u is an https URL whose host is
+// one of the domains of IframeAllowlist or of the additional domains.
+// Hosts must match exactly: subdomains are not allowed implicitly.
+func IframeAllowed(u *url.URL, domains []string) bool {
+	if u.Scheme != "https" {
+		return false
+	}
+	host := u.Hostname()
+	for _, list := range [][]string{IframeAllowlist, domains} {
+		for _, domain := range list {
+			if strings.EqualFold(host, domain) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IframeNode is an embedded iframe, such as a demo, a form
+// or an interactive widget.
 type IframeNode struct {
 	node
 	URL string
@@ -1,6 +1,7 @@
 package nodes
 
 import (
+	"net/url"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -64,3 +65,31 @@ func TestIframeNodeEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestIframeAllowed(t *testing.T) {
+	tests := []struct {
+		in      string
+		domains []string
+		out     bool
+	}{
+		{"https://codepen.io/pen/abc", nil, true},
+		{"https://CodePen.io/pen", nil, true},
+		{"http://codepen.io/pen", nil, false},
+		{"https://player.vimeo.com/video/1", nil, false},
+		{"https://example.com/frame", nil, false},
+		{"https://notcodepen.io/pen", nil, false},
+		{"https://codepen.io.example.com/pen", nil, false},
+		{"https://example.com/frame", []string{"example.com"}, true},
+		{"https://sub.example.com/frame", []string{"example.com"}, false},
+		{"http://example.com/frame", []string{"example.com"}, false},
+	}
+	for _, tc := range tests {
+		u, err := url.Parse(tc.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := IframeAllowed(u, tc.domains); out != tc.out {
+			t.Errorf("IframeAllowed(%q, %q) = %t, want %t", tc.in, tc.domains, out, tc.out)
+		}
+	}
+}
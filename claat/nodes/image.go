@@ -1,6 +1,10 @@
 package nodes
 
-import "strings"
+import (
+	"html"
+	"regexp"
+	"strings"
+)
 
 type NewImageNodeOptions struct {
 	Src   string
@@ -26,11 +30,31 @@ func NewImageNode(opts NewImageNodeOptions) *ImageNode {
 // ImageNode represents a single image.
 type ImageNode struct {
 	node
-	Src   string
-	Width float32
-	Alt   string
-	Title string
-	Bytes []byte
+	Src         string
+	Width       float32
+	Alt         string
+	Title       string
+	Bytes       []byte
+	Annotations []Annotation // Callouts drawn over the image
+	Caption     string       // Caption of the image as a figure, if any
+	Figure      string       // Numbered label of the figure, such as "Figure 3", if any
+	Dark        string       // Variant of Src for dark color schemes, if any
+}
+
+// darkRegexp matches the {dark src} variant of an image in its alt text,
+// with src in group 1.
+var darkRegexp = regexp.MustCompile(`\{dark\s+([^\s{}]+)\s*\}`)
+
+// ParseDark returns alt text without the variant of its image for dark
+// color schemes, written as {dark console-dark.png}, and that variant,
+// if any.
+func ParseDark(alt string) (string, string) {
+	m := darkRegexp.FindStringSubmatchIndex(alt)
+	if m == nil {
+		return alt, ""
+	}
+	dark := html.UnescapeString(alt[m[2]:m[3]])
+	return strings.Join(strings.Fields(alt[:m[0]]+" "+alt[m[1]:]), " "), dark
 }
 
 // Empty returns true if its Src is zero, excluding space runes.
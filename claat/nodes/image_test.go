@@ -226,3 +226,22 @@ func TestImageNodes(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDark(t *testing.T) {
+	tests := []struct {
+		in   string
+		alt  string
+		dark string
+	}{
+		{"Console", "Console", ""},
+		{"Console {dark console-dark.png}", "Console", "console-dark.png"},
+		{"{dark https://example.com/a.png?x=1&amp;y=2} Console  menu", "Console menu", "https://example.com/a.png?x=1&y=2"},
+		{"Console {dark}", "Console {dark}", ""},
+	}
+	for _, tc := range tests {
+		alt, dark := ParseDark(tc.in)
+		if alt != tc.alt || dark != tc.dark {
+			t.Errorf("ParseDark(%q) = %q, %q; want %q, %q", tc.in, alt, dark, tc.alt, tc.dark)
+		}
+	}
+}
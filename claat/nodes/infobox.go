@@ -7,6 +7,8 @@ type InfoboxKind string
 const (
 	InfoboxPositive InfoboxKind = "special"
 	InfoboxNegative InfoboxKind = "warning"
+	// InfoboxInstructor holds the instructor notes of instructor guides.
+	InfoboxInstructor InfoboxKind = "instructor"
 )
 
 // InfoboxNode is any regular header, a checklist header, or an FAQ header.
@@ -0,0 +1,35 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodes
+
+// NewKbdNode creates a new keyboard shortcut of keys, pressed together,
+// such as "Ctrl" and "C".
+func NewKbdNode(keys ...string) *KbdNode {
+	return &KbdNode{
+		node: node{typ: NodeKbd},
+		Keys: keys,
+	}
+}
+
+// KbdNode is a keyboard key or shortcut, such as Ctrl+C.
+type KbdNode struct {
+	node
+	Keys []string // Keys pressed together, in order
+}
+
+// Empty returns true if kn has no keys.
+func (kn *KbdNode) Empty() bool {
+	return len(kn.Keys) == 0
+}
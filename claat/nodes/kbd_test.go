@@ -0,0 +1,18 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewKbdNode(t *testing.T) {
+	got := NewKbdNode("Ctrl", "C")
+	want := &KbdNode{node: node{typ: NodeKbd}, Keys: []string{"Ctrl", "C"}}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(KbdNode{}, node{})); diff != "" {
+		t.Errorf("NewKbdNode got diff (-want +got):\n%s", diff)
+	}
+	if !NewKbdNode().Empty() {
+		t.Error("NewKbdNode().Empty() = false; want true")
+	}
+}
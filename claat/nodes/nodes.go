@@ -16,6 +16,7 @@ package nodes
 
 import (
 	"sort"
+	"strconv"
 )
 
 // NodeType is type for parsed codelab nodes tree.
@@ -42,8 +43,55 @@ const (
 	NodeYouTube              // YouTube video
 	NodeIframe               // Embedded iframe
 	NodeImport               // A node which holds content imported from another resource
+	NodeXref                 // A link to another step of the codelab
+	NodeTerm                 // An occurrence of a glossary term
+	NodeActivity             // Qwiklabs activity tracking block
+	NodeAttachment           // A downloadable file
+	NodeVideo                // Video other than YouTube
+	NodeReview               // Content inserted or deleted since a previous version
+	NodeIcon                 // Icon of a user interface element
+	NodeKbd                  // Keyboard key or shortcut
 )
 
+// nodeTypeNames are the names of node types, as written in messages.
+var nodeTypeNames = map[NodeType]string{
+	NodeInvalid:     "invalid",
+	NodeList:        "list",
+	NodeGrid:        "grid",
+	NodeText:        "text",
+	NodeCode:        "code",
+	NodeInfobox:     "infobox",
+	NodeSurvey:      "survey",
+	NodeURL:         "url",
+	NodeImage:       "image",
+	NodeButton:      "button",
+	NodeItemsList:   "items list",
+	NodeItemsCheck:  "checklist",
+	NodeItemsFAQ:    "faq",
+	NodeHeader:      "header",
+	NodeHeaderCheck: "checklist header",
+	NodeHeaderFAQ:   "faq header",
+	NodeYouTube:     "youtube",
+	NodeIframe:      "iframe",
+	NodeImport:      "import",
+	NodeXref:        "xref",
+	NodeTerm:        "term",
+	NodeActivity:    "activity",
+	NodeAttachment:  "attachment",
+	NodeVideo:       "video",
+	NodeReview:      "review",
+	NodeIcon:        "icon",
+	NodeKbd:         "kbd",
+}
+
+// String returns the name of t, such as "iframe".
+func (t NodeType) String() string {
+	if s, ok := nodeTypeNames[t]; ok {
+		return s
+	}
+	return "NodeType(" + strconv.FormatUint(uint64(t), 10) + ")"
+}
+
 // Node is an interface common to all node types.
 type Node interface {
 	// Type returns node type.
@@ -59,15 +107,19 @@ type Node interface {
 	MutateBlock(interface{})
 	// Empty returns true if the node has no content.
 	Empty() bool
-	// Env returns node environment
+	// Env returns node environment tags. See MatchEnv for the tag syntax.
 	Env() []string
 	// MutateEnv replaces current node environment tags with env.
 	MutateEnv(env []string)
+	// Pos returns the position of the node in its source document.
+	Pos() Pos
+	// MutatePos sets the position of the node in its source document.
+	MutatePos(Pos)
 }
 
 // IsInline returns true if t is an inline node type.
 func IsInline(t NodeType) bool {
-	return t&(NodeText|NodeURL|NodeImage|NodeButton) != 0
+	return t&(NodeText|NodeURL|NodeImage|NodeButton|NodeXref|NodeTerm|NodeIcon|NodeKbd) != 0
 }
 
 // EmptyNodes returns true if all of nodes are empty.
@@ -84,6 +136,7 @@ type node struct {
 	typ   NodeType
 	block interface{}
 	env   []string
+	pos   Pos
 }
 
 func (b *node) Type() NodeType {
@@ -112,3 +165,11 @@ func (b *node) MutateEnv(e []string) {
 	copy(b.env, e)
 	sort.Strings(b.env)
 }
+
+func (b *node) Pos() Pos {
+	return b.pos
+}
+
+func (b *node) MutatePos(p Pos) {
+	b.pos = p
+}
@@ -101,3 +101,23 @@ func TestMutateEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeTypeString(t *testing.T) {
+	tests := []struct {
+		in  NodeType
+		out string
+	}{
+		{NodeIframe, "iframe"},
+		{NodeItemsCheck, "checklist"},
+		{NodeVideo, "video"},
+		{NodeReview, "review"},
+		{NodeIcon, "icon"},
+		{NodeKbd, "kbd"},
+		{NodeKbd << 1, "NodeType(134217728)"},
+	}
+	for _, tc := range tests {
+		if out := tc.in.String(); out != tc.out {
+			t.Errorf("NodeType(%d).String() = %q; want %q", uint32(tc.in), out, tc.out)
+		}
+	}
+}
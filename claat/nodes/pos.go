@@ -0,0 +1,85 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodes
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Pos is the position of a node in its source document, for authors
+// to find the content a message is about: a line of a Markdown source,
+// or a paragraph of a Google Doc. The zero Pos is an unknown position.
+type Pos struct {
+	Line      int // line of a Markdown source, from 1
+	Paragraph int // paragraph of a Google Doc, from 1
+}
+
+// IsValid reports whether p is a known position.
+func (p Pos) IsValid() bool {
+	return p.Line > 0 || p.Paragraph > 0
+}
+
+// String returns p as "line 12" or "paragraph 3", or "" if unknown.
+func (p Pos) String() string {
+	switch {
+	case p.Line > 0:
+		return "line " + strconv.Itoa(p.Line)
+	case p.Paragraph > 0:
+		return "paragraph " + strconv.Itoa(p.Paragraph)
+	}
+	return ""
+}
+
+// SetPos sets the position of each of nn and of their descendants
+// which have none to p. Positions already set, such as those of nested
+// blocks parsed before their parent, are kept.
+func SetPos(p Pos, nn ...Node) {
+	if !p.IsValid() {
+		return
+	}
+	WalkNodes(nn, func(n Node, entering bool) (Node, error) {
+		if entering && !n.Pos().IsValid() {
+			n.MutatePos(p)
+		}
+		return n, nil
+	})
+}
+
+// errFound stops a walk once a node is found.
+var errFound = errors.New("found")
+
+// FillPos sets the position of each of nn and of their descendants
+// which have none to the position of their first descendant with one,
+// such as that of a list grouping inline nodes after parsing.
+func FillPos(nn ...Node) {
+	WalkNodes(nn, func(n Node, entering bool) (Node, error) {
+		if entering || n.Pos().IsValid() {
+			return n, nil
+		}
+		var p Pos
+		walkChildren(n, func(c Node, entering bool) (Node, error) {
+			if entering && c.Pos().IsValid() {
+				p = c.Pos()
+				return c, errFound
+			}
+			return c, nil
+		})
+		if p.IsValid() {
+			n.MutatePos(p)
+		}
+		return n, nil
+	})
+}
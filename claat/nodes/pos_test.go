@@ -0,0 +1,57 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPosString(t *testing.T) {
+	tests := []struct {
+		in  Pos
+		out string
+	}{
+		{Pos{}, ""},
+		{Pos{Line: 12}, "line 12"},
+		{Pos{Paragraph: 3}, "paragraph 3"},
+	}
+	for _, tc := range tests {
+		if out := tc.in.String(); out != tc.out {
+			t.Errorf("%#v.String() = %q; want %q", tc.in, out, tc.out)
+		}
+		if valid := tc.in.IsValid(); valid != (tc.out != "") {
+			t.Errorf("%#v.IsValid() = %v; want %v", tc.in, valid, tc.out != "")
+		}
+	}
+}
+
+func TestSetPos(t *testing.T) {
+	inner := NewTextNode(NewTextNodeOptions{Value: "inner"})
+	inner.MutatePos(Pos{Line: 5})
+	text := NewTextNode(NewTextNodeOptions{Value: "text"})
+	list := NewListNode(text, NewInfoboxNode(InfoboxPositive, NewListNode(inner)))
+	SetPos(Pos{Line: 3}, list)
+
+	got := []Pos{list.Pos(), text.Pos(), inner.Pos()}
+	want := []Pos{{Line: 3}, {Line: 3}, {Line: 5}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SetPos got diff (-want +got): %s", diff)
+	}
+}
+
+func TestFillPos(t *testing.T) {
+	first := NewTextNode(NewTextNodeOptions{Value: "first"})
+	second := NewTextNode(NewTextNodeOptions{Value: "second"})
+	second.MutatePos(Pos{Paragraph: 4})
+	third := NewTextNode(NewTextNodeOptions{Value: "third"})
+	third.MutatePos(Pos{Paragraph: 5})
+	list := NewListNode(first, NewURLNode("https://example.com", second), third)
+	empty := NewListNode()
+	FillPos(list, empty)
+
+	got := []Pos{list.Pos(), first.Pos(), empty.Pos()}
+	want := []Pos{{Paragraph: 4}, {}, {}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("FillPos got diff (-want +got): %s", diff)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodes
+
+// ReviewKind is the kind of change a ReviewNode marks.
+type ReviewKind string
+
+// ReviewNode variants.
+const (
+	ReviewInserted ReviewKind = "ins"
+	ReviewDeleted  ReviewKind = "del"
+)
+
+// ReviewNode marks content inserted since, or deleted from, a previous
+// version of a codelab, in exports for review.
+type ReviewNode struct {
+	node
+	Kind    ReviewKind
+	Content *ListNode
+}
+
+// NewReviewNode creates a new review node of kind k with content n.
+func NewReviewNode(k ReviewKind, n ...Node) *ReviewNode {
+	return &ReviewNode{
+		node:    node{typ: NodeReview},
+		Kind:    k,
+		Content: NewListNode(n...),
+	}
+}
+
+// Empty returns true if rn content is empty.
+func (rn *ReviewNode) Empty() bool {
+	return rn.Content.Empty()
+}
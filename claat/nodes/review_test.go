@@ -0,0 +1,23 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewReviewNode(t *testing.T) {
+	text := NewTextNode(NewTextNodeOptions{Value: "New text"})
+	got := NewReviewNode(ReviewInserted, text)
+	want := &ReviewNode{
+		node:    node{typ: NodeReview},
+		Kind:    ReviewInserted,
+		Content: NewListNode(text),
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(ReviewNode{}, node{}, ListNode{}, TextNode{})); diff != "" {
+		t.Errorf("NewReviewNode(ins, %v) got diff (-want +got):\n%s", text, diff)
+	}
+	if !NewReviewNode(ReviewDeleted).Empty() {
+		t.Errorf("NewReviewNode(del).Empty() = false; want true")
+	}
+}
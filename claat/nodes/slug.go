@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodes
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Slugger generates heading anchors the way GitHub does for Markdown headings,
+// so that a deep link to a heading works in every output format.
+// Repeated anchors get a numeric suffix: "intro", "intro-1", "intro-2".
+//
+// Anchors depend on all preceding headings of a document. Renderers should
+// generate them for the whole document in order, using a single Slugger.
+// The zero value is ready to use.
+type Slugger struct {
+	seen map[string]int
+}
+
+// Slug returns a unique anchor for heading text s.
+func (sl *Slugger) Slug(s string) string {
+	if sl.seen == nil {
+		sl.seen = make(map[string]int)
+	}
+	base := Slugify(s)
+	id := base
+	if n := sl.seen[base]; n > 0 {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	sl.seen[base]++
+	return id
+}
+
+// Slugify lowercases s, replaces spaces with dashes and drops
+// everything but letters, digits, dashes and underscores.
+// Unlike Slugger.Slug, it does not make the result unique.
+func Slugify(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// PlainText returns the concatenated text of nn and their descendants,
+// such as the text of a heading to generate its anchor from.
+func PlainText(nn ...Node) string {
+	var b strings.Builder
+	WalkNodes(nn, func(n Node, entering bool) (Node, error) {
+		if t, ok := n.(*TextNode); ok && entering {
+			b.WriteString(t.Value)
+		}
+		return n, nil
+	})
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSlugger(t *testing.T) {
+	var sl Slugger
+	var got []string
+	for _, s := range []string{
+		"Overview",
+		"Step 4: Deploy the app",
+		"What you'll learn",
+		"  Foo & Bar  ",
+		"snake_case and-dashes",
+		"Überblick",
+		"Overview",
+		"Overview",
+	} {
+		got = append(got, sl.Slug(s))
+	}
+	want := []string{
+		"overview",
+		"step-4-deploy-the-app",
+		"what-youll-learn",
+		"foo--bar",
+		"snake_case-and-dashes",
+		"überblick",
+		"overview-1",
+		"overview-2",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("slugs got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	text := func(s string) Node {
+		return NewTextNode(NewTextNodeOptions{Value: s})
+	}
+	nn := []Node{
+		text("Run "),
+		NewURLNode("https://example.com", text("gcloud")),
+		NewListNode(text(" now")),
+	}
+	if got, want := PlainText(nn...), "Run gcloud now"; got != want {
+		t.Errorf("PlainText = %q; want %q", got, want)
+	}
+}
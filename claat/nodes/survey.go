@@ -1,18 +1,54 @@
 package nodes
 
+import "strconv"
+
 // TODO general refactor?
 
 // SurveyNode contains groups of questions. Each group name is the Survey key.
+// A survey with scoring metadata is a quiz, graded by the codelab platform.
 type SurveyNode struct {
 	node
 	ID     string
 	Groups []*SurveyGroup
+	Pass   int // Minimum score to pass a quiz, in points; 0 if none
 }
 
+// SurveyKind is the kind of answer to a survey question.
+type SurveyKind string
+
+// Survey question kinds.
+const (
+	SurveyChoice SurveyKind = ""       // One of the options
+	SurveyMulti  SurveyKind = "multi"  // Any number of the options
+	SurveyRating SurveyKind = "rating" // A point of a scale; options are the scale points
+	SurveyText   SurveyKind = "text"   // Free text; there are no options
+)
+
 // SurveyGroup contains group name/question and possible answers.
 type SurveyGroup struct {
 	Name    string
+	Kind    SurveyKind
 	Options []string
+	Points  int      // Score of a correct answer to a quiz question; 1 if 0
+	Correct []string // Correct options of a quiz question
+}
+
+// MaxRatingPoints is the maximum number of points of a rating scale,
+// as in a 0 to 10 scale.
+const MaxRatingPoints = 11
+
+// RatingOptions returns the points of a rating scale from min to max,
+// as the options of a SurveyRating group. It returns nil if min is negative,
+// or the scale is empty or has more than MaxRatingPoints points.
+func RatingOptions(min, max int) []string {
+	if min < 0 || max < min || max-min >= MaxRatingPoints {
+		return nil
+	}
+	var opt []string
+	for i := min; i <= max; i++ {
+		opt = append(opt, strconv.Itoa(i))
+	}
+	return opt
 }
 
 // NewSurveyNode creates a new survey node with optional questions.
@@ -26,10 +62,25 @@ func NewSurveyNode(id string, groups ...*SurveyGroup) *SurveyNode {
 	}
 }
 
-// Empty returns true if each group has 0 options.
+// Graded reports whether sn is a quiz: whether it has a pass threshold,
+// or questions with points or correct answers.
+func (sn *SurveyNode) Graded() bool {
+	if sn.Pass > 0 {
+		return true
+	}
+	for _, g := range sn.Groups {
+		if g.Points > 0 || len(g.Correct) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Empty returns true if each group has 0 options
+// and none of them is a free text question.
 func (sn *SurveyNode) Empty() bool {
 	for _, g := range sn.Groups {
-		if len(g.Options) > 0 {
+		if len(g.Options) > 0 || g.Kind == SurveyText {
 			return false
 		}
 	}
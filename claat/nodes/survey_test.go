@@ -205,6 +205,16 @@ func TestSurveyNodeEmpty(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "OneGroupFreeText",
+			inID: "id",
+			inGroups: []*SurveyGroup{
+				&SurveyGroup{
+					Name: "comments",
+					Kind: SurveyText,
+				},
+			},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -217,3 +227,56 @@ func TestSurveyNodeEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestRatingOptions(t *testing.T) {
+	if diff := cmp.Diff([]string{"1", "2", "3", "4", "5"}, RatingOptions(1, 5)); diff != "" {
+		t.Errorf("RatingOptions(1, 5) got diff (-want +got): %s", diff)
+	}
+	if out := RatingOptions(5, 1); len(out) != 0 {
+		t.Errorf("RatingOptions(5, 1) = %v; want none", out)
+	}
+	if out := RatingOptions(0, 10); len(out) != MaxRatingPoints {
+		t.Errorf("RatingOptions(0, 10) = %v; want %d points", out, MaxRatingPoints)
+	}
+	if out := RatingOptions(1, 1000000000); len(out) != 0 {
+		t.Errorf("RatingOptions(1, 1000000000) has %d points; want none", len(out))
+	}
+	if out := RatingOptions(-3, 3); len(out) != 0 {
+		t.Errorf("RatingOptions(-3, 3) = %v; want none", out)
+	}
+}
+
+func TestSurveyNodeGraded(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *SurveyNode
+		out  bool
+	}{
+		{
+			name: "Survey",
+			in:   NewSurveyNode("id", &SurveyGroup{Name: "one", Options: []string{"a", "b"}}),
+		},
+		{
+			name: "Pass",
+			in:   &SurveyNode{ID: "id", Pass: 1},
+			out:  true,
+		},
+		{
+			name: "Points",
+			in:   NewSurveyNode("id", &SurveyGroup{Name: "one"}, &SurveyGroup{Name: "two", Points: 2}),
+			out:  true,
+		},
+		{
+			name: "Correct",
+			in:   NewSurveyNode("id", &SurveyGroup{Name: "one", Options: []string{"a", "b"}, Correct: []string{"b"}}),
+			out:  true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if out := tc.in.Graded(); out != tc.out {
+				t.Errorf("SurveyNode.Graded() = %t, want %t", out, tc.out)
+			}
+		})
+	}
+}
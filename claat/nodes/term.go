@@ -0,0 +1,39 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodes
+
+// NewTermNode creates a new occurrence of glossary term with its definition
+// and optional content n.
+func NewTermNode(term, definition string, n ...Node) *TermNode {
+	return &TermNode{
+		node:       node{typ: NodeTerm},
+		Term:       term,
+		Definition: definition,
+		Content:    NewListNode(n...),
+	}
+}
+
+// TermNode is an occurrence of a glossary term in the text.
+type TermNode struct {
+	node
+	Term       string // Glossary term
+	Definition string // Definition of the term
+	Content    *ListNode
+}
+
+// Empty returns true if tn content is empty.
+func (tn *TermNode) Empty() bool {
+	return tn.Content.Empty()
+}
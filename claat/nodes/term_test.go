@@ -0,0 +1,21 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewTermNode(t *testing.T) {
+	text := NewTextNode(NewTextNodeOptions{Value: "vpc"})
+	got := NewTermNode("VPC", "Virtual Private Cloud", text)
+	want := &TermNode{
+		node:       node{typ: NodeTerm},
+		Term:       "VPC",
+		Definition: "Virtual Private Cloud",
+		Content:    NewListNode(text),
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(TermNode{}, node{}, ListNode{}, TextNode{})); diff != "" {
+		t.Errorf("NewTermNode got diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodes
+
+import (
+	"net/url"
+	"strings"
+)
+
+// NewVideoNode creates a new video hosted at src.
+func NewVideoNode(src string) *VideoNode {
+	return &VideoNode{
+		node: node{typ: NodeVideo},
+		Src:  src,
+	}
+}
+
+// VideoNode is a video hosted elsewhere than on YouTube: a video file,
+// e.g. an mp4 in Cloud Storage or on a self-hosted server, or a Vimeo video.
+type VideoNode struct {
+	node
+	Src          string // Video file or Vimeo URL
+	Poster       string // Image shown before the video plays
+	Captions     string // URL of a WebVTT captions track
+	CaptionsLang string // Language code of Captions, e.g. "en"
+}
+
+// Empty returns true if vn's Src is zero, excluding space runes.
+func (vn *VideoNode) Empty() bool {
+	return strings.TrimSpace(vn.Src) == ""
+}
+
+// VimeoID returns the ID of a Vimeo video at vn.Src, or an empty string
+// if Src is not a vimeo.com or player.vimeo.com video URL.
+func (vn *VideoNode) VimeoID() string {
+	u, err := url.Parse(vn.Src)
+	if err != nil {
+		return ""
+	}
+	p := strings.Trim(u.Path, "/")
+	switch strings.TrimPrefix(u.Hostname(), "www.") {
+	case "vimeo.com":
+	case "player.vimeo.com":
+		if !strings.HasPrefix(p, "video/") {
+			return ""
+		}
+		p = strings.TrimPrefix(p, "video/")
+	default:
+		return ""
+	}
+	if p == "" || strings.Trim(p, "0123456789") != "" {
+		return ""
+	}
+	return p
+}
@@ -0,0 +1,52 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewVideoNode(t *testing.T) {
+	out := NewVideoNode("https://storage.googleapis.com/bucket/intro.mp4")
+	want := &VideoNode{
+		node: node{typ: NodeVideo},
+		Src:  "https://storage.googleapis.com/bucket/intro.mp4",
+	}
+	if diff := cmp.Diff(want, out, cmp.AllowUnexported(VideoNode{}, node{})); diff != "" {
+		t.Errorf("NewVideoNode() got diff (-want +got): %s", diff)
+	}
+}
+
+func TestVideoNodeEmpty(t *testing.T) {
+	tests := []struct {
+		src string
+		out bool
+	}{
+		{"", true},
+		{"  ", true},
+		{"intro.mp4", false},
+	}
+	for _, tc := range tests {
+		if out := NewVideoNode(tc.src).Empty(); out != tc.out {
+			t.Errorf("NewVideoNode(%q).Empty() = %t, want %t", tc.src, out, tc.out)
+		}
+	}
+}
+
+func TestVideoNodeVimeoID(t *testing.T) {
+	tests := []struct {
+		src string
+		out string
+	}{
+		{"https://vimeo.com/76979871", "76979871"},
+		{"https://player.vimeo.com/video/76979871?h=abc", "76979871"},
+		{"https://vimeo.com/channels/staffpicks", ""},
+		{"https://example.com/76979871", ""},
+		{"videos/intro.mp4", ""},
+	}
+	for _, tc := range tests {
+		if out := NewVideoNode(tc.src).VimeoID(); out != tc.out {
+			t.Errorf("NewVideoNode(%q).VimeoID() = %q, want %q", tc.src, out, tc.out)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodes
+
+import "errors"
+
+// SkipChildren is used as a return value from a VisitFunc when entering
+// a node to indicate that its children are to be skipped.
+// It is not returned as an error by any function.
+var SkipChildren = errors.New("skip children")
+
+// VisitFunc is called by Walk for each node n, first with entering set
+// to true, before visiting any children of n, and then again with entering
+// set to false, after all children have been visited.
+//
+// The returned node replaces n in the tree. Returning nil removes n
+// from its parent, in which case n is not visited on exit.
+// When entering, a replacement node's children are walked instead of n's.
+//
+// A non-nil error, other than SkipChildren, stops the walk.
+type VisitFunc func(n Node, entering bool) (Node, error)
+
+// Walk traverses a nodes tree rooted at n in depth-first order,
+// calling fn for each node.
+// It returns the, possibly replaced, root node.
+//
+// Children are the nodes of ListNode.Nodes, the nodes of the Content
+// of headers, links, cross-references, glossary terms, buttons, infoboxes,
+// activity tracking blocks, attachments, review marks and imports, the nodes of each ItemsListNode item and of each GridNode cell.
+// The ListNode containers holding them are not visited themselves.
+func Walk(n Node, fn VisitFunc) (Node, error) {
+	n, err := fn(n, true)
+	if err == SkipChildren {
+		return n, nil
+	}
+	if err != nil || n == nil {
+		return n, err
+	}
+	if err := walkChildren(n, fn); err != nil {
+		return n, err
+	}
+	return fn(n, false)
+}
+
+// WalkNodes calls Walk for each of nn and returns the resulting nodes,
+// excluding the removed ones.
+func WalkNodes(nn []Node, fn VisitFunc) ([]Node, error) {
+	res := make([]Node, 0, len(nn))
+	for _, n := range nn {
+		n, err := Walk(n, fn)
+		if err != nil {
+			return nil, err
+		}
+		if n != nil {
+			res = append(res, n)
+		}
+	}
+	return res, nil
+}
+
+func walkChildren(n Node, fn VisitFunc) error {
+	var err error
+	switch n := n.(type) {
+	case *ListNode:
+		err = walkList(n, fn)
+	case *ImportNode:
+		err = walkList(n.Content, fn)
+	case *HeaderNode:
+		err = walkList(n.Content, fn)
+	case *URLNode:
+		err = walkList(n.Content, fn)
+	case *ButtonNode:
+		err = walkList(n.Content, fn)
+	case *XrefNode:
+		err = walkList(n.Content, fn)
+	case *TermNode:
+		err = walkList(n.Content, fn)
+	case *InfoboxNode:
+		err = walkList(n.Content, fn)
+	case *ActivityNode:
+		err = walkList(n.Content, fn)
+	case *AttachmentNode:
+		err = walkList(n.Content, fn)
+	case *ReviewNode:
+		err = walkList(n.Content, fn)
+	case *ItemsListNode:
+		for _, i := range n.Items {
+			if err = walkList(i, fn); err != nil {
+				break
+			}
+		}
+	case *GridNode:
+		for _, r := range n.Rows {
+			for _, c := range r {
+				if err = walkList(c.Content, fn); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return err
+}
+
+func walkList(l *ListNode, fn VisitFunc) error {
+	if l == nil {
+		return nil
+	}
+	nn, err := WalkNodes(l.Nodes, fn)
+	if err != nil {
+		return err
+	}
+	l.Nodes = nn
+	return nil
+}
@@ -0,0 +1,134 @@
+package nodes
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWalk(t *testing.T) {
+	text := func(v string) *TextNode {
+		return NewTextNode(NewTextNodeOptions{Value: v})
+	}
+	items := NewItemsListNode("", 0)
+	items.NewItem(text("item"))
+	grid := NewGridNode([]*GridCell{{Colspan: 1, Rowspan: 1, Content: NewListNode(text("cell"))}})
+	root := NewListNode(
+		NewHeaderNode(2, text("header")),
+		NewInfoboxNode(InfoboxPositive, text("infobox")),
+		NewActivityNode(1, text("activity")),
+		NewURLNode("https://example.com", NewButtonNode(true, true, false, text("button"))),
+		items,
+		grid,
+	)
+
+	var got []string
+	_, err := Walk(root, func(n Node, entering bool) (Node, error) {
+		if tn, ok := n.(*TextNode); ok && entering {
+			got = append(got, tn.Value)
+		}
+		return n, nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() = %v", err)
+	}
+	want := []string{"header", "infobox", "activity", "button", "item", "cell"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Walk() visited text got diff (-want +got): %s", diff)
+	}
+}
+
+func TestWalkOrder(t *testing.T) {
+	root := NewListNode(NewHeaderNode(2, NewTextNode(NewTextNodeOptions{Value: "a"})))
+	var got []string
+	Walk(root, func(n Node, entering bool) (Node, error) {
+		prefix := "exit "
+		if entering {
+			prefix = "enter "
+		}
+		switch n.(type) {
+		case *ListNode:
+			got = append(got, prefix+"list")
+		case *HeaderNode:
+			got = append(got, prefix+"header")
+		case *TextNode:
+			got = append(got, prefix+"text")
+		}
+		return n, nil
+	})
+	want := []string{"enter list", "enter header", "enter text", "exit text", "exit header", "exit list"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Walk() order got diff (-want +got): %s", diff)
+	}
+}
+
+func TestWalkReplace(t *testing.T) {
+	code := NewCodeNode("ls", true, "")
+	root := NewListNode(
+		NewTextNode(NewTextNodeOptions{Value: "remove"}),
+		NewInfoboxNode(InfoboxPositive, code),
+	)
+	_, err := Walk(root, func(n Node, entering bool) (Node, error) {
+		if !entering {
+			return n, nil
+		}
+		switch n := n.(type) {
+		case *TextNode:
+			return nil, nil
+		case *CodeNode:
+			return NewTextNode(NewTextNodeOptions{Value: n.Value, Code: true}), nil
+		}
+		return n, nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() = %v", err)
+	}
+	if len(root.Nodes) != 1 {
+		t.Fatalf("Walk() left %d nodes, want 1", len(root.Nodes))
+	}
+	ib := root.Nodes[0].(*InfoboxNode)
+	if tn, ok := ib.Content.Nodes[0].(*TextNode); !ok || tn.Value != "ls" || !tn.Code {
+		t.Errorf("Walk() replacement = %+v, want inline code text node", ib.Content.Nodes[0])
+	}
+}
+
+func TestWalkSkipChildren(t *testing.T) {
+	root := NewListNode(NewInfoboxNode(InfoboxPositive, NewTextNode(NewTextNodeOptions{Value: "a"})))
+	var texts int
+	Walk(root, func(n Node, entering bool) (Node, error) {
+		switch n.(type) {
+		case *InfoboxNode:
+			return n, SkipChildren
+		case *TextNode:
+			texts++
+		}
+		return n, nil
+	})
+	if texts != 0 {
+		t.Errorf("Walk() visited %d skipped children, want 0", texts)
+	}
+}
+
+func TestWalkError(t *testing.T) {
+	stop := errors.New("stop")
+	a := NewTextNode(NewTextNodeOptions{Value: "a"})
+	root := NewListNode(a, NewTextNode(NewTextNodeOptions{Value: "b"}))
+	var visited int
+	_, err := Walk(root, func(n Node, entering bool) (Node, error) {
+		if _, ok := n.(*TextNode); ok && entering {
+			visited++
+			return nil, stop
+		}
+		return n, nil
+	})
+	if err != stop {
+		t.Errorf("Walk() = %v, want %v", err, stop)
+	}
+	if visited != 1 {
+		t.Errorf("Walk() visited %d nodes after an error, want 1", visited)
+	}
+	if len(root.Nodes) != 2 {
+		t.Errorf("Walk() modified the tree on error: %d nodes, want 2", len(root.Nodes))
+	}
+}
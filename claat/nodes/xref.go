@@ -0,0 +1,38 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodes
+
+// NewXrefNode creates a new cross-reference to step number step, 1-based,
+// with optional content n.
+func NewXrefNode(step int, n ...Node) *XrefNode {
+	return &XrefNode{
+		node:    node{typ: NodeXref},
+		Step:    step,
+		Content: NewListNode(n...),
+	}
+}
+
+// XrefNode is a link to another step of the same codelab,
+// such as "Step 4: Deploy the app".
+type XrefNode struct {
+	node
+	Step    int // Target step number, starting at 1
+	Content *ListNode
+}
+
+// Empty returns true if xn content is empty.
+func (xn *XrefNode) Empty() bool {
+	return xn.Content.Empty()
+}
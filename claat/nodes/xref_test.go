@@ -0,0 +1,23 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewXrefNode(t *testing.T) {
+	text := NewTextNode(NewTextNodeOptions{Value: "Step 4: Deploy"})
+	got := NewXrefNode(4, text)
+	want := &XrefNode{
+		node:    node{typ: NodeXref},
+		Step:    4,
+		Content: NewListNode(text),
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(XrefNode{}, node{}, ListNode{}, TextNode{})); diff != "" {
+		t.Errorf("NewXrefNode(4, %v) got diff (-want +got):\n%s", text, diff)
+	}
+	if got.Empty() {
+		t.Errorf("NewXrefNode(4, %v).Empty() = true; want false", text)
+	}
+}
@@ -1,5 +1,13 @@
 package nodes
 
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // NewYouTubeNode creates a new YouTube video node.
 func NewYouTubeNode(vid string) *YouTubeNode {
 	return &YouTubeNode{
@@ -12,9 +20,89 @@ func NewYouTubeNode(vid string) *YouTubeNode {
 type YouTubeNode struct {
 	node
 	VideoID string
+	// Start is the offset to start playing the video at, in seconds.
+	Start int
+	// Captions is the language code of captions to show, e.g. "fr".
+	// Captions are not shown by default if empty.
+	Captions string
+	// NoCookie embeds the video from the privacy-enhanced
+	// youtube-nocookie.com domain.
+	NoCookie bool
 }
 
 // Empty returns true if yt's VideoID field is zero.
 func (yt *YouTubeNode) Empty() bool {
 	return yt.VideoID == ""
 }
+
+// EmbedURL returns the URL of the YouTube player of yt, for an iframe.
+func (yt *YouTubeNode) EmbedURL() string {
+	host := "www.youtube.com"
+	if yt.NoCookie {
+		host = "www.youtube-nocookie.com"
+	}
+	s := fmt.Sprintf("https://%s/embed/%s?rel=0", host, yt.VideoID)
+	if yt.Start > 0 {
+		s += "&start=" + strconv.Itoa(yt.Start)
+	}
+	if yt.Captions != "" {
+		s += "&cc_load_policy=1&cc_lang_pref=" + url.QueryEscape(yt.Captions)
+	}
+	return s
+}
+
+// ParseYouTubeURL returns a YouTube video node of a watch, embed or short
+// link URL, with the start offset of its "t" or "start" query parameter
+// and the captions language of its "cc_lang_pref" query parameter.
+// It returns nil if s is not a YouTube video URL.
+func ParseYouTubeURL(s string) *YouTubeNode {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil
+	}
+	q := u.Query()
+	var id string
+	var nocookie bool
+	switch strings.TrimPrefix(u.Hostname(), "www.") {
+	case "youtube.com", "m.youtube.com":
+		id = q.Get("v")
+		if id == "" && strings.HasPrefix(u.Path, "/embed/") {
+			id = strings.TrimPrefix(u.Path, "/embed/")
+		}
+	case "youtube-nocookie.com":
+		if strings.HasPrefix(u.Path, "/embed/") {
+			id = strings.TrimPrefix(u.Path, "/embed/")
+		}
+		nocookie = true
+	case "youtu.be":
+		id = strings.TrimPrefix(u.Path, "/")
+	}
+	if id == "" || strings.Contains(id, "/") {
+		return nil
+	}
+	n := NewYouTubeNode(id)
+	n.NoCookie = nocookie
+	n.Captions = q.Get("cc_lang_pref")
+	if t := q.Get("start"); t != "" {
+		n.Start = ParseYouTubeTime(t)
+	} else {
+		n.Start = ParseYouTubeTime(q.Get("t"))
+	}
+	return n
+}
+
+// ParseYouTubeTime parses a video offset in seconds, such as "90" or "90s",
+// or a duration such as "1m30s". It returns 0 if s is not a valid offset.
+func ParseYouTubeTime(s string) int {
+	if s == "" {
+		return 0
+	}
+	if v, err := strconv.Atoi(s); err == nil && v > 0 {
+		return v
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return int(d / time.Second)
+}
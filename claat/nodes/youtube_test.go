@@ -64,3 +64,45 @@ func TestYouTubeNodeEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestParseYouTubeURL(t *testing.T) {
+	yt := func(id string, start int, captions string, nocookie bool) *YouTubeNode {
+		n := NewYouTubeNode(id)
+		n.Start = start
+		n.Captions = captions
+		n.NoCookie = nocookie
+		return n
+	}
+	tests := []struct {
+		in  string
+		out *YouTubeNode
+	}{
+		{"https://www.youtube.com/watch?v=vid", yt("vid", 0, "", false)},
+		{"https://www.youtube.com/watch?v=vid&t=1m30s", yt("vid", 90, "", false)},
+		{"https://youtu.be/vid?t=90", yt("vid", 90, "", false)},
+		{"https://www.youtube.com/embed/vid?start=12&cc_lang_pref=fr", yt("vid", 12, "fr", false)},
+		{"https://www.youtube-nocookie.com/embed/vid?rel=0", yt("vid", 0, "", true)},
+		{"https://www.youtube.com/watch?v=vid&t=soon", yt("vid", 0, "", false)},
+		{"https://www.youtube.com/channel/abc", nil},
+		{"https://example.com/watch?v=vid", nil},
+	}
+	for _, tc := range tests {
+		out := ParseYouTubeURL(tc.in)
+		if diff := cmp.Diff(tc.out, out, cmp.AllowUnexported(YouTubeNode{}, node{})); diff != "" {
+			t.Errorf("ParseYouTubeURL(%q) got diff (-want +got): %s", tc.in, diff)
+		}
+	}
+}
+
+func TestYouTubeNodeEmbedURL(t *testing.T) {
+	n := NewYouTubeNode("vid")
+	if got, want := n.EmbedURL(), "https://www.youtube.com/embed/vid?rel=0"; got != want {
+		t.Errorf("EmbedURL() = %q, want %q", got, want)
+	}
+	n.Start = 90
+	n.Captions = "fr"
+	n.NoCookie = true
+	if got, want := n.EmbedURL(), "https://www.youtube-nocookie.com/embed/vid?rel=0&start=90&cc_load_policy=1&cc_lang_pref=fr"; got != want {
+		t.Errorf("EmbedURL() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,26 @@
+#!/bin/bash -eu
+# Copyright 2026 Google LLC. All Rights Reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# OSS-Fuzz build script of the claat fuzz targets, which are native
+# Go fuzz tests. Run from the claat directory, i.e. $SRC/tools/claat.
+# Locally, run a target with e.g. go test ./render -fuzz FuzzRender.
+
+MODULE=github.com/googlecodelabs/tools/claat
+
+compile_native_go_fuzzer $MODULE/parser/md FuzzParse fuzz_md_parse
+compile_native_go_fuzzer $MODULE/parser/gdoc FuzzParse fuzz_gdoc_parse
+compile_native_go_fuzzer $MODULE/parser/gdoc FuzzAPIParse fuzz_gdoc_api_parse
+compile_native_go_fuzzer $MODULE/render FuzzRender fuzz_render
+compile_native_go_fuzzer $MODULE/render FuzzText fuzz_render_text
@@ -33,6 +33,7 @@ const (
 	ibPositiveColor = "#d9ead3"     // positive infobox background
 	ibNegativeColor = "#fce5cd"     // negative infobox background
 	surveyColor     = "#cfe2f3"     // survey background color
+	outputColor     = "#efefef"     // expected output background, in terminal font
 )
 
 // cssStyle represents styles of an exported Google Doc.
@@ -0,0 +1,648 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdoc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/parser"
+	"github.com/googlecodelabs/tools/claat/types"
+	"github.com/googlecodelabs/tools/claat/util"
+	"github.com/stoewer/go-strcase"
+)
+
+func init() {
+	parser.Register("gdocapi", &APIParser{})
+}
+
+// APIParser is a Google Doc parser of the structured JSON document
+// returned by the Google Docs API documents.get method.
+//
+// It follows the same authoring conventions as Parser, which parses
+// the HTML export of a doc, but does not lose formatting to CSS scraping.
+type APIParser struct {
+}
+
+// Parse parses a codelab doc in Google Docs API JSON format.
+func (p *APIParser) Parse(ctx context.Context, r io.Reader, opts parser.Options) (*types.Codelab, error) {
+	doc, err := decodeAPIDoc(r)
+	if err != nil {
+		return nil, err
+	}
+	ds := newDocState()
+	ds.iframeDomains = opts.IframeDomains
+	ds.passMetadata = opts.PassMetadata
+	as := &apiState{ds: ds, doc: doc}
+	for i, el := range doc.Body.Content {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		ds.pos = nodes.Pos{Paragraph: i + 1}
+		switch {
+		case el.Paragraph != nil && el.Paragraph.style() == "TITLE" && ds.step == nil:
+			if v := strings.TrimSpace(plainText(el.Paragraph)); v != "" {
+				ds.clab.Title = v
+			}
+		case el.Table != nil && ds.step == nil:
+			as.metaTable(el.Table)
+		case el.Paragraph != nil && el.Paragraph.style() == "HEADING_1":
+			if t := strings.TrimSpace(plainText(el.Paragraph)); t != "" {
+				as.flushList()
+				finalizeStep(ds.step)
+				ds.step = ds.clab.NewStep(t)
+				ds.env = nil
+			}
+		case ds.step != nil:
+			// ignore everything else before the first step
+			as.element(el)
+		}
+	}
+	as.flushList()
+	finalizeStep(ds.step)
+
+	if ds.clab.Title == "" {
+		ds.clab.Title = doc.Title
+	}
+	if ds.clab.ID == "" {
+		ds.clab.ID = slug(ds.clab.Title)
+	}
+	ds.clab.Tags = util.Unique(ds.clab.Tags)
+	sort.Strings(ds.clab.Tags)
+	ds.clab.Duration = int(ds.totdur.Minutes())
+	return ds.clab, nil
+}
+
+// ParseFragment parses a codelab fragment in Google Docs API JSON format.
+func (p *APIParser) ParseFragment(ctx context.Context, r io.Reader, opts parser.Options) ([]nodes.Node, error) {
+	doc, err := decodeAPIDoc(r)
+	if err != nil {
+		return nil, err
+	}
+	ds := newDocState()
+	ds.iframeDomains = opts.IframeDomains
+	ds.step = ds.clab.NewStep("fragment")
+	as := &apiState{ds: ds, doc: doc}
+	for i, el := range doc.Body.Content {
+		ds.pos = nodes.Pos{Paragraph: i + 1}
+		as.element(el)
+	}
+	as.flushList()
+	finalizeStep(ds.step)
+	return ds.step.Content.Nodes, nil
+}
+
+func decodeAPIDoc(r io.Reader) (*apiDocument, error) {
+	doc := &apiDocument{}
+	if err := json.NewDecoder(r).Decode(doc); err != nil {
+		return nil, err
+	}
+	if doc.Body == nil {
+		return nil, fmt.Errorf("document without a body")
+	}
+	return doc, nil
+}
+
+// apiDocument is the subset of the Docs API Document resource
+// used by APIParser.
+// See https://developers.google.com/docs/api/reference/rest/v1/documents.
+type apiDocument struct {
+	Title string `json:"title"`
+	Body  *struct {
+		Content []*apiElement `json:"content"`
+	} `json:"body"`
+	Lists         map[string]*apiList         `json:"lists"`
+	InlineObjects map[string]*apiInlineObject `json:"inlineObjects"`
+}
+
+// apiElement is a Docs API StructuralElement.
+type apiElement struct {
+	Paragraph *apiParagraph `json:"paragraph"`
+	Table     *apiTable     `json:"table"`
+}
+
+type apiParagraph struct {
+	Elements       []*apiParagraphElement `json:"elements"`
+	ParagraphStyle *struct {
+		NamedStyleType string `json:"namedStyleType"`
+	} `json:"paragraphStyle"`
+	Bullet *struct {
+		ListID       string `json:"listId"`
+		NestingLevel int    `json:"nestingLevel"`
+	} `json:"bullet"`
+}
+
+func (p *apiParagraph) style() string {
+	if p.ParagraphStyle == nil {
+		return ""
+	}
+	return p.ParagraphStyle.NamedStyleType
+}
+
+type apiParagraphElement struct {
+	TextRun *struct {
+		Content   string        `json:"content"`
+		TextStyle *apiTextStyle `json:"textStyle"`
+	} `json:"textRun"`
+	InlineObjectElement *struct {
+		InlineObjectID string `json:"inlineObjectId"`
+	} `json:"inlineObjectElement"`
+}
+
+type apiTextStyle struct {
+	Bold   bool `json:"bold"`
+	Italic bool `json:"italic"`
+	Link   *struct {
+		URL string `json:"url"`
+	} `json:"link"`
+	WeightedFontFamily *struct {
+		FontFamily string `json:"fontFamily"`
+	} `json:"weightedFontFamily"`
+	ForegroundColor *apiColor `json:"foregroundColor"`
+	BackgroundColor *apiColor `json:"backgroundColor"`
+}
+
+func (s *apiTextStyle) font() string {
+	if s == nil || s.WeightedFontFamily == nil {
+		return ""
+	}
+	return strings.ToLower(s.WeightedFontFamily.FontFamily)
+}
+
+// apiColor is a Docs API OptionalColor.
+type apiColor struct {
+	Color *struct {
+		RGBColor *struct {
+			Red   float64 `json:"red"`
+			Green float64 `json:"green"`
+			Blue  float64 `json:"blue"`
+		} `json:"rgbColor"`
+	} `json:"color"`
+}
+
+// hex returns c in the "#rrggbb" format used by the doc CSS styles,
+// or an empty string if c is not set.
+func (c *apiColor) hex() string {
+	if c == nil || c.Color == nil || c.Color.RGBColor == nil {
+		return ""
+	}
+	rgb := c.Color.RGBColor
+	b := func(v float64) int { return int(math.Round(v * 255)) }
+	return fmt.Sprintf("#%02x%02x%02x", b(rgb.Red), b(rgb.Green), b(rgb.Blue))
+}
+
+type apiTable struct {
+	TableRows []*struct {
+		TableCells []*apiTableCell `json:"tableCells"`
+	} `json:"tableRows"`
+}
+
+type apiTableCell struct {
+	Content        []*apiElement `json:"content"`
+	TableCellStyle *struct {
+		RowSpan         int       `json:"rowSpan"`
+		ColumnSpan      int       `json:"columnSpan"`
+		BackgroundColor *apiColor `json:"backgroundColor"`
+	} `json:"tableCellStyle"`
+}
+
+func (c *apiTableCell) background() string {
+	if c.TableCellStyle == nil {
+		return ""
+	}
+	return c.TableCellStyle.BackgroundColor.hex()
+}
+
+type apiList struct {
+	ListProperties *struct {
+		NestingLevels []*struct {
+			GlyphType   string `json:"glyphType"`
+			StartNumber int    `json:"startNumber"`
+		} `json:"nestingLevels"`
+	} `json:"listProperties"`
+}
+
+type apiInlineObject struct {
+	InlineObjectProperties *struct {
+		EmbeddedObject *struct {
+			Title           string `json:"title"`
+			Description     string `json:"description"`
+			ImageProperties *struct {
+				ContentURI string `json:"contentUri"`
+			} `json:"imageProperties"`
+			Size *struct {
+				Width *struct {
+					Magnitude float64 `json:"magnitude"`
+					Unit      string  `json:"unit"`
+				} `json:"width"`
+			} `json:"size"`
+		} `json:"embeddedObject"`
+	} `json:"inlineObjectProperties"`
+}
+
+// glyphListType maps Docs API ordered list glyph types to HTML list types.
+var glyphListType = map[string]string{
+	"DECIMAL":      "1",
+	"ZERO_DECIMAL": "1",
+	"ALPHA":        "a",
+	"UPPER_ALPHA":  "A",
+	"ROMAN":        "i",
+	"UPPER_ROMAN":  "I",
+}
+
+// apiState is the parsing state of an apiDocument.
+// Step content is accumulated in the embedded docState.
+type apiState struct {
+	ds     *docState
+	doc    *apiDocument
+	list   *nodes.ItemsListNode // list being accumulated from bullet paragraphs
+	listID string               // Docs API ID of list
+}
+
+// element parses a top level structural element of the current step.
+func (as *apiState) element(el *apiElement) {
+	if el.Paragraph != nil && el.Paragraph.Bullet != nil {
+		as.listItem(el.Paragraph)
+		return
+	}
+	as.flushList()
+	switch {
+	case el.Table != nil:
+		if n := as.table(el.Table); n != nil {
+			as.appendNodes(n)
+		}
+	case el.Paragraph != nil && isAPIMeta(el.Paragraph):
+		if n := stepMeta(as.ds, plainText(el.Paragraph)); n != nil {
+			as.appendNodes(n)
+		}
+	case el.Paragraph != nil:
+		as.appendNodes(as.paragraph(el.Paragraph)...)
+	}
+}
+
+func (as *apiState) appendNodes(nn ...nodes.Node) {
+	if len(nn) == 0 {
+		return
+	}
+	as.ds.appendNodes(nn...)
+	if nodes.IsHeader(nn[0].Type()) {
+		// a header resets the step environment
+		as.ds.env = nil
+	}
+}
+
+// paragraph converts a non-list paragraph into a header or inline nodes.
+// Inline nodes are marked with p as their block, to be squashed
+// into a single ListNode by finalizeStep.
+func (as *apiState) paragraph(p *apiParagraph) []nodes.Node {
+	nn := parser.CompactNodes(as.inline(p))
+	if len(nn) == 0 {
+		return nil
+	}
+	var level int
+	if _, err := fmt.Sscanf(p.style(), "HEADING_%d", &level); err == nil && level > 1 {
+		hn := nodes.NewHeaderNode(level, nn...)
+		switch strings.ToLower(strings.TrimSpace(plainText(p))) {
+		case headerLearn, headerCover:
+			hn.MutateType(nodes.NodeHeaderCheck)
+		case headerFAQ:
+			hn.MutateType(nodes.NodeHeaderFAQ)
+		}
+		return []nodes.Node{hn}
+	}
+	for _, n := range nn {
+		n.MutateBlock(p)
+	}
+	return nn
+}
+
+// listItem adds a bullet paragraph to the current list,
+// starting a new one if p belongs to a different list.
+func (as *apiState) listItem(p *apiParagraph) {
+	if as.list == nil || as.listID != p.Bullet.ListID {
+		as.flushList()
+		var typ string
+		var start int
+		if l := as.doc.Lists[p.Bullet.ListID]; l != nil && l.ListProperties != nil && len(l.ListProperties.NestingLevels) > 0 {
+			lvl := l.ListProperties.NestingLevels[0]
+			typ = glyphListType[lvl.GlyphType]
+			if typ != "" && lvl.StartNumber > 1 {
+				start = lvl.StartNumber
+			}
+		}
+		as.list = nodes.NewItemsListNode(typ, start)
+		as.listID = p.Bullet.ListID
+	}
+	if nn := parser.CompactNodes(as.inline(p)); len(nn) > 0 {
+		nodes.SetPos(as.ds.pos, nn...)
+		as.list.NewItem(nn...)
+	}
+}
+
+// flushList appends the list being accumulated, if any, to the current step.
+func (as *apiState) flushList() {
+	l := as.list
+	as.list = nil
+	if l == nil || len(l.Items) == 0 {
+		return
+	}
+	if last := as.ds.lastNode; last != nil {
+		switch last.Type() {
+		case nodes.NodeHeaderCheck:
+			l.MutateType(nodes.NodeItemsCheck)
+		case nodes.NodeHeaderFAQ:
+			l.MutateType(nodes.NodeItemsFAQ)
+		}
+	}
+	// the list is flushed while parsing the element after it,
+	// so it takes the position of its first item
+	nodes.FillPos(l)
+	as.appendNodes(l)
+}
+
+// inline converts paragraph elements into text, link, button and image nodes.
+func (as *apiState) inline(p *apiParagraph) []nodes.Node {
+	var nn []nodes.Node
+	for i, pe := range p.Elements {
+		if pe.InlineObjectElement != nil {
+			if n := as.image(pe.InlineObjectElement.InlineObjectID); n != nil {
+				nn = append(nn, n)
+			}
+			continue
+		}
+		if pe.TextRun == nil {
+			continue
+		}
+		v := textCleaner.Replace(pe.TextRun.Content)
+		if i == len(p.Elements)-1 {
+			// paragraphs always end with a newline
+			v = strings.TrimSuffix(v, "\n")
+		}
+		// vertical tabs are soft line breaks
+		v = strings.Replace(v, "\v", "\n", -1)
+		if v == "" {
+			continue
+		}
+		ts := pe.TextRun.TextStyle
+		if ts == nil {
+			ts = &apiTextStyle{}
+		}
+		f := ts.font()
+		t := nodes.NewTextNode(nodes.NewTextNodeOptions{
+			Value:  v,
+			Bold:   ts.Bold,
+			Italic: ts.Italic,
+			Code:   f == fontCode || f == fontConsole,
+		})
+		if ts.Link == nil {
+			nn = append(nn, t)
+			continue
+		}
+		href := cleanURL(ts.Link.URL)
+		if strings.HasPrefix(href, commentPrefix) {
+			continue
+		}
+		if ts.BackgroundColor.hex() == buttonColor {
+			dl := strings.HasPrefix(strings.ToLower(strings.TrimSpace(v)), "download ")
+			btn := nodes.NewButtonNode(true, true, dl, t)
+			btn.Variant = nodes.ButtonVariantOf(v)
+			nn = append(nn, nodes.NewURLNode(href, btn))
+			continue
+		}
+		nn = append(nn, nodes.NewURLNode(href, t))
+	}
+	return nn
+}
+
+// image creates an ImageNode out of an inline object.
+// Like the HTML export parser, it returns a YouTubeNode or an IframeNode
+// if the image description is a video or an embeddable URL.
+func (as *apiState) image(id string) nodes.Node {
+	obj := as.doc.InlineObjects[id]
+	if obj == nil || obj.InlineObjectProperties == nil || obj.InlineObjectProperties.EmbeddedObject == nil {
+		return nil
+	}
+	eo := obj.InlineObjectProperties.EmbeddedObject
+	if eo.ImageProperties == nil || eo.ImageProperties.ContentURI == "" {
+		return nil
+	}
+	alt := strings.Replace(eo.Description, "\n", " ", -1)
+	if strings.Contains(alt, "youtube.com/watch") {
+		if yt := nodes.ParseYouTubeURL(alt); yt != nil {
+			return yt
+		}
+	}
+	if u, err := url.Parse(alt); err == nil && nodes.IframeAllowed(u, as.ds.iframeDomains) {
+		return nodes.NewIframeNode(u.String())
+	}
+	n := nodes.NewImageNode(nodes.NewImageNodeOptions{
+		Src:   eo.ImageProperties.ContentURI,
+		Alt:   alt,
+		Title: eo.Title,
+	})
+	if eo.Size != nil && eo.Size.Width != nil {
+		w := eo.Size.Width.Magnitude
+		if eo.Size.Width.Unit == "PT" {
+			// docs HTML export uses 96dpi pixels
+			w = w * 4 / 3
+		}
+		n.Width = float32(w)
+	}
+	return n
+}
+
+// table converts a table into a code block, an infobox, a survey or a grid,
+// following the same color and font conventions as the HTML export parser.
+func (as *apiState) table(t *apiTable) nodes.Node {
+	if len(t.TableRows) == 1 && len(t.TableRows[0].TableCells) == 1 {
+		cell := t.TableRows[0].TableCells[0]
+		switch bg := cell.background(); {
+		case bg == ibPositiveColor:
+			return as.infobox(nodes.InfoboxPositive, cell)
+		case bg == ibNegativeColor:
+			return as.infobox(nodes.InfoboxNegative, cell)
+		case bg == surveyColor:
+			return as.survey(cell)
+		}
+		if f := cellFont(cell); f == fontCode || f == fontConsole {
+			var lines []string
+			for _, el := range cell.Content {
+				if el.Paragraph != nil {
+					lines = append(lines, strings.Replace(plainText(el.Paragraph), "\v", "\n", -1))
+				}
+			}
+			n := nodes.NewCodeNode(strings.Join(lines, "\n"), f == fontConsole, "")
+			n.Output = n.Term && cell.background() == outputColor
+			return n
+		}
+	}
+
+	var rows [][]*nodes.GridCell
+	for _, tr := range t.TableRows {
+		var r []*nodes.GridCell
+		for _, c := range tr.TableCells {
+			gc := &nodes.GridCell{
+				Colspan: 1,
+				Rowspan: 1,
+				Content: nodes.NewListNode(as.cellNodes(c)...),
+			}
+			if s := c.TableCellStyle; s != nil {
+				if s.ColumnSpan > 0 {
+					gc.Colspan = s.ColumnSpan
+				}
+				if s.RowSpan > 0 {
+					gc.Rowspan = s.RowSpan
+				}
+			}
+			r = append(r, gc)
+		}
+		if len(r) > 0 {
+			rows = append(rows, r)
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return nodes.NewGridNode(rows...)
+}
+
+// cellNodes parses content of a table cell, which may itself contain lists.
+func (as *apiState) cellNodes(c *apiTableCell) []nodes.Node {
+	sub := &apiState{ds: newDocState(), doc: as.doc}
+	sub.ds.iframeDomains = as.ds.iframeDomains
+	sub.ds.step = sub.ds.clab.NewStep("cell")
+	for _, el := range c.Content {
+		sub.element(el)
+	}
+	sub.flushList()
+	nn := parser.BlockNodes(sub.ds.step.Content.Nodes)
+	return parser.CompactNodes(nn)
+}
+
+func (as *apiState) infobox(kind nodes.InfoboxKind, c *apiTableCell) nodes.Node {
+	nn := as.cellNodes(c)
+	if len(nn) == 0 {
+		return nil
+	}
+	return nodes.NewInfoboxNode(kind, nn...)
+}
+
+// survey parses a survey table, where non-list paragraphs are questions
+// and list items are their options. See surveyGroup for question tags.
+func (as *apiState) survey(c *apiTableCell) nodes.Node {
+	var questions []*nodes.SurveyGroup
+	for _, el := range c.Content {
+		if el.Paragraph == nil {
+			continue
+		}
+		v := strings.TrimSpace(plainText(el.Paragraph))
+		if v == "" {
+			continue
+		}
+		if el.Paragraph.Bullet == nil || len(questions) == 0 {
+			questions = append(questions, &nodes.SurveyGroup{Name: v})
+			continue
+		}
+		q := questions[len(questions)-1]
+		q.Options = append(q.Options, v)
+	}
+	sn := surveyNode(fmt.Sprintf("%s-%d", as.ds.clab.ID, as.ds.survey+1), questions)
+	if sn == nil {
+		return nil
+	}
+	as.ds.survey++
+	return sn
+}
+
+// metaTable parses the top table of a codelab doc.
+func (as *apiState) metaTable(t *apiTable) {
+	for _, tr := range t.TableRows {
+		if len(tr.TableCells) < 2 {
+			continue
+		}
+		fieldName := strcase.SnakeCase(strings.TrimSpace(cellText(tr.TableCells[0])))
+		metaField(as.ds, fieldName, strings.TrimSpace(cellText(tr.TableCells[1])))
+	}
+	if len(as.ds.clab.Categories) > 0 {
+		as.ds.clab.Theme = slug(as.ds.clab.Categories[0])
+	}
+}
+
+// isAPIMeta reports whether all text of p is in the step meta instruction color.
+func isAPIMeta(p *apiParagraph) bool {
+	var found bool
+	for _, pe := range p.Elements {
+		if pe.TextRun == nil || strings.TrimSpace(pe.TextRun.Content) == "" {
+			continue
+		}
+		ts := pe.TextRun.TextStyle
+		if ts == nil || ts.ForegroundColor.hex() != metaColor {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// cellFont returns the font family of all text in c,
+// or an empty string if c is empty or uses multiple fonts.
+func cellFont(c *apiTableCell) string {
+	var font string
+	for _, el := range c.Content {
+		if el.Paragraph == nil {
+			return ""
+		}
+		for _, pe := range el.Paragraph.Elements {
+			if pe.TextRun == nil || strings.TrimSpace(pe.TextRun.Content) == "" {
+				continue
+			}
+			f := pe.TextRun.TextStyle.font()
+			if font != "" && f != font {
+				return ""
+			}
+			font = f
+		}
+	}
+	return font
+}
+
+// plainText returns text content of p without the trailing newline.
+func plainText(p *apiParagraph) string {
+	var b strings.Builder
+	for _, pe := range p.Elements {
+		if pe.TextRun != nil {
+			b.WriteString(pe.TextRun.Content)
+		}
+	}
+	return strings.TrimSuffix(textCleaner.Replace(b.String()), "\n")
+}
+
+// cellText returns text content of all paragraphs in c, separated by newlines.
+func cellText(c *apiTableCell) string {
+	var lines []string
+	for _, el := range c.Content {
+		if el.Paragraph != nil {
+			lines = append(lines, plainText(el.Paragraph))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdoc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/parser"
+	"github.com/googlecodelabs/tools/claat/render"
+)
+
+// apiDocJSON is a codelab doc as returned by the Docs API documents.get method.
+const apiDocJSON = `{
+  "title": "Doc file name",
+  "body": {"content": [
+    {"paragraph": {"paragraphStyle": {"namedStyleType": "TITLE"},
+      "elements": [{"textRun": {"content": "Codelab Title\n"}}]}},
+    {"table": {"tableRows": [
+      {"tableCells": [
+        {"content": [{"paragraph": {"elements": [{"textRun": {"content": "Categories\n"}}]}}]},
+        {"content": [{"paragraph": {"elements": [{"textRun": {"content": "Web, Android\n"}}]}}]}
+      ]},
+      {"tableCells": [
+        {"content": [{"paragraph": {"elements": [{"textRun": {"content": "Feedback Link\n"}}]}}]},
+        {"content": [{"paragraph": {"elements": [{"textRun": {"content": "https://example.com/bugs\n"}}]}}]}
+      ]}
+    ]}},
+    {"paragraph": {"paragraphStyle": {"namedStyleType": "HEADING_1"},
+      "elements": [{"textRun": {"content": "Overview\n"}}]}},
+    {"paragraph": {"elements": [{"textRun": {"content": "Duration: 2:30\n",
+      "textStyle": {"foregroundColor": {"color": {"rgbColor": {"red": 0.7176471, "green": 0.7176471, "blue": 0.7176471}}}}}}]}},
+    {"paragraph": {"elements": [
+      {"textRun": {"content": "Hello "}},
+      {"textRun": {"content": "bold", "textStyle": {"bold": true}}},
+      {"textRun": {"content": " and "}},
+      {"textRun": {"content": "link", "textStyle": {"link": {"url": "https://example.com"}}}},
+      {"textRun": {"content": "\n"}}
+    ]}},
+    {"paragraph": {"paragraphStyle": {"namedStyleType": "HEADING_2"},
+      "elements": [{"textRun": {"content": "What you'll learn\n"}}]}},
+    {"paragraph": {"bullet": {"listId": "l1"}, "elements": [{"textRun": {"content": "One\n"}}]}},
+    {"paragraph": {"bullet": {"listId": "l1"}, "elements": [{"textRun": {"content": "Two\n"}}]}},
+    {"paragraph": {"paragraphStyle": {"namedStyleType": "HEADING_1"},
+      "elements": [{"textRun": {"content": "Setup\n"}}]}},
+    {"paragraph": {"bullet": {"listId": "l2"}, "elements": [{"textRun": {"content": "First\n"}}]}},
+    {"table": {"tableRows": [{"tableCells": [{"content": [
+      {"paragraph": {"elements": [{"textRun": {"content": "func main() {\u000b}\n",
+        "textStyle": {"weightedFontFamily": {"fontFamily": "Courier New"}}}}]}}
+    ]}]}]}},
+    {"table": {"tableRows": [{"tableCells": [{
+      "tableCellStyle": {"backgroundColor": {"color": {"rgbColor": {"red": 0.8509804, "green": 0.91764706, "blue": 0.827451}}}},
+      "content": [{"paragraph": {"elements": [{"textRun": {"content": "Tip\n"}}]}}]
+    }]}]}},
+    {"table": {"tableRows": [{"tableCells": [{
+      "tableCellStyle": {"backgroundColor": {"color": {"rgbColor": {"red": 0.9372549, "green": 0.9372549, "blue": 0.9372549}}}},
+      "content": [{"paragraph": {"elements": [{"textRun": {"content": "Hello\n",
+        "textStyle": {"weightedFontFamily": {"fontFamily": "Consolas"}}}}]}}]
+    }]}]}},
+    {"paragraph": {"elements": [{"textRun": {"content": "Activity tracking: Deploy\n",
+      "textStyle": {"foregroundColor": {"color": {"rgbColor": {"red": 0.7176471, "green": 0.7176471, "blue": 0.7176471}}}}}}]}}
+  ]},
+  "lists": {
+    "l2": {"listProperties": {"nestingLevels": [{"glyphType": "DECIMAL", "startNumber": 1}]}}
+  }
+}`
+
+func TestAPIParse(t *testing.T) {
+	clab, err := (&APIParser{}).Parse(context.Background(), strings.NewReader(apiDocJSON), *parser.NewOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clab.Title != "Codelab Title" || clab.ID != "codelab-title" {
+		t.Errorf("Title, ID = %q, %q; want %q, %q", clab.Title, clab.ID, "Codelab Title", "codelab-title")
+	}
+	if diff := cmp.Diff([]string{"web", "android"}, clab.Categories); diff != "" {
+		t.Errorf("Categories got diff (-want +got): %s", diff)
+	}
+	if clab.Feedback != "https://example.com/bugs" {
+		t.Errorf("Feedback = %q", clab.Feedback)
+	}
+	if len(clab.Steps) != 2 {
+		t.Fatalf("len(Steps) = %d, want 2", len(clab.Steps))
+	}
+	if d := clab.Steps[0].Duration; d != 3*time.Minute {
+		t.Errorf("Steps[0].Duration = %v, want 3m", d)
+	}
+
+	want := []string{
+		"<p>Hello <strong>bold</strong> and <a href=\"https://example.com\" target=\"_blank\">link</a></p>\n" +
+			"<h2 class=\"checklist\" id=\"what-youll-learn\" is-upgraded>What you&#39;ll learn</h2>\n" +
+			"<ul class=\"checklist\">\n<li>One</li>\n<li>Two</li>\n</ul>\n",
+		"<ol type=\"1\">\n<li>First</li>\n</ol>\n" +
+			"<pre><code>func main() {\n}</code></pre>\n" +
+			"<aside class=\"special\"><p>Tip</p>\n</aside>\n" +
+			"<pre output>Hello</pre>\n" +
+			"<ql-activity-tracking><p>Deploy</p>\n</ql-activity-tracking>\n",
+	}
+	for i, st := range clab.Steps {
+		var buf bytes.Buffer
+		if err := render.WriteHTML(&buf, "", "html", st.Content.Nodes...); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want[i], buf.String()); diff != "" {
+			t.Errorf("Steps[%d] content got diff (-want +got): %s", i, diff)
+		}
+	}
+}
+
+func TestAPIParsePos(t *testing.T) {
+	clab, err := (&APIParser{}).Parse(context.Background(), strings.NewReader(apiDocJSON), *parser.NewOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, st := range clab.Steps {
+		for _, n := range st.Content.Nodes {
+			got = append(got, fmt.Sprintf("%s %s", n.Type(), n.Pos()))
+		}
+	}
+	want := []string{
+		"list paragraph 5",
+		"checklist header paragraph 6",
+		"checklist paragraph 7",
+		"items list paragraph 10",
+		"code paragraph 11",
+		"infobox paragraph 12",
+		"code paragraph 13",
+		"activity paragraph 14",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("positions got diff (-want +got): %s", diff)
+	}
+}
+
+func TestAPIParseNoBody(t *testing.T) {
+	if _, err := (&APIParser{}).Parse(context.Background(), strings.NewReader(`{"title": "t"}`), *parser.NewOptions()); err == nil {
+		t.Errorf("Parse() of a doc without body returned nil error")
+	}
+}
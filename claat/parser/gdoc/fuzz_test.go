@@ -0,0 +1,52 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdoc
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/parser"
+)
+
+// FuzzParse checks that parsing any HTML export of a doc does not panic.
+// Run it with go test ./parser/gdoc -fuzz FuzzParse.
+func FuzzParse(f *testing.F) {
+	f.Add([]byte(`<html><head><style>.c1{color:#b7b7b7}</style></head><body>` +
+		`<p class="title"><span>Lab</span></p>` +
+		`<table><tr><td><p>Summary</p></td><td><p>s</p></td></tr></table>` +
+		`<h1><span>Setup</span></h1><p><span class="c1">Duration: 1:30</span></p>` +
+		`<p><span>Text</span><a href="https://example.com">link</a></p>` +
+		`<ul><li>one</li></ul><table><tr><td><p>code</p></td></tr></table></body></html>`))
+	f.Add([]byte(`<h1>Step</h1><p><img src="x.png" alt="alt"></p><table><tr><td colspan="2" rowspan="0"></td></tr></table>`))
+	f.Fuzz(func(t *testing.T, src []byte) {
+		p := &Parser{}
+		p.Parse(context.Background(), bytes.NewReader(src), *parser.NewOptions())
+		p.ParseFragment(context.Background(), bytes.NewReader(src), *parser.NewOptions())
+	})
+}
+
+// FuzzAPIParse checks that parsing any Docs API document does not panic.
+// Run it with go test ./parser/gdoc -fuzz FuzzAPIParse.
+func FuzzAPIParse(f *testing.F) {
+	f.Add([]byte(apiDocJSON))
+	f.Add([]byte(`{"body": {"content": [{"paragraph": {"elements": [{"textRun": {"content": "x"}}]}}]}}`))
+	f.Fuzz(func(t *testing.T, src []byte) {
+		p := &APIParser{}
+		p.Parse(context.Background(), bytes.NewReader(src), *parser.NewOptions())
+		p.ParseFragment(context.Background(), bytes.NewReader(src), *parser.NewOptions())
+	})
+}
@@ -103,6 +103,18 @@ func isSurvey(css cssStyle, hn *html.Node) bool {
 	return hasClassStyle(css, hn, "background-color", surveyColor)
 }
 
+// paragraphs returns the index, from 1, of each element child of body,
+// which are the paragraphs, lists and tables of an exported doc.
+func paragraphs(body *html.Node) map[*html.Node]int {
+	paras := make(map[*html.Node]int)
+	for hn := body.FirstChild; hn != nil; hn = hn.NextSibling {
+		if hn.Type == html.ElementNode {
+			paras[hn] = len(paras) + 1
+		}
+	}
+	return paras
+}
+
 func isComment(css cssStyle, hn *html.Node) bool {
 	if hn.DataAtom != atom.Div {
 		return false
@@ -16,11 +16,11 @@ package gdoc
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"fmt"
 	"io"
 	"net/url"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -29,6 +29,7 @@ import (
 	"golang.org/x/net/html"
 	"golang.org/x/net/html/atom"
 
+	"github.com/googlecodelabs/tools/claat/logging"
 	"github.com/googlecodelabs/tools/claat/nodes"
 	"github.com/googlecodelabs/tools/claat/parser"
 	"github.com/googlecodelabs/tools/claat/types"
@@ -45,32 +46,35 @@ type Parser struct {
 }
 
 // Parse parses a codelab exported in HTML from Google Docs.
-func (p *Parser) Parse(r io.Reader, opts parser.Options) (*types.Codelab, error) {
+func (p *Parser) Parse(ctx context.Context, r io.Reader, opts parser.Options) (*types.Codelab, error) {
 	// TODO: use html.Tokenizer instead
 	doc, err := html.Parse(r)
 	if err != nil {
 		return nil, err
 	}
-	return parseDoc(doc, opts)
+	return parseDoc(ctx, doc, opts)
 }
 
 // ParseFragment parses a codelab fragment exported in HTML from Google Docs.
-func (p *Parser) ParseFragment(r io.Reader, opts parser.Options) ([]nodes.Node, error) {
+func (p *Parser) ParseFragment(ctx context.Context, r io.Reader, opts parser.Options) ([]nodes.Node, error) {
 	// TODO: use html.Tokenizer instead
 	doc, err := html.Parse(r)
 	if err != nil {
 		return nil, err
 	}
-	return parseFragment(doc)
+	return parseFragment(doc, opts)
 }
 
 const (
-	metaSep         = ":"           // step instruction format, key:value
-	metaDuration    = "duration"    // step duration instruction
-	metaEnvironment = "environment" // step environment instruction
-	metaTagOpen     = "[["          // start of tag-based meta instruction
-	metaTagClose    = "]]"          // end of tag-based meta instruction
-	metaTagImport   = "import"      // import remote resource instruction
+	metaSep         = ":"                 // step instruction format, key:value
+	metaDuration    = "duration"          // step duration instruction
+	metaEnvironment = "environment"       // step environment instruction
+	metaOptional    = "optional"          // optional step instruction
+	metaProducts    = "products"          // step required products instruction
+	metaActivity    = "activity tracking" // Qwiklabs activity tracking block instruction
+	metaTagOpen     = "[["                // start of tag-based meta instruction
+	metaTagClose    = "]]"                // end of tag-based meta instruction
+	metaTagImport   = "import"            // import remote resource instruction
 
 	// possible content of special header nodes in lower case.
 	headerLearn = "what you'll learn"
@@ -127,6 +131,9 @@ type docState struct {
 	flags        stateFlag       // current flags
 	stack        []*stackItem    // cur and flags stack
 	passMetadata map[string]bool // set of metadata fields to pass along.
+	pos          nodes.Pos       // position of the top level element being parsed
+	// additional domains allowed in iframes
+	iframeDomains []string
 }
 
 type stackItem struct {
@@ -171,11 +178,12 @@ func (ds *docState) appendNodes(nn ...nodes.Node) {
 			n.MutateEnv(append(n.Env(), ds.env...))
 		}
 	}
+	nodes.SetPos(ds.pos, nn...)
 	ds.step.Content.Append(nn...)
 	ds.lastNode = nn[len(nn)-1]
 }
 
-func parseFragment(doc *html.Node) ([]nodes.Node, error) {
+func parseFragment(doc *html.Node, opts parser.Options) ([]nodes.Node, error) {
 	body := findAtom(doc, atom.Body)
 	if body == nil {
 		return nil, fmt.Errorf("document without a body")
@@ -187,12 +195,15 @@ func parseFragment(doc *html.Node) ([]nodes.Node, error) {
 
 	ds := newDocState()
 	ds.css = style
+	ds.iframeDomains = opts.IframeDomains
 	ds.step = ds.clab.NewStep("fragment")
+	paras := paragraphs(body)
 	for ds.cur = body.FirstChild; ds.cur != nil; ds.cur = ds.cur.NextSibling {
 		if isComment(ds.css, ds.cur) {
 			// docs export comments at the end of the body
 			break
 		}
+		ds.pos = nodes.Pos{Paragraph: paras[ds.cur]}
 		parseTop(ds)
 	}
 	finalizeStep(ds.step)
@@ -201,7 +212,7 @@ func parseFragment(doc *html.Node) ([]nodes.Node, error) {
 
 // parseDoc parses codelab doc exported as text/html.
 // The doc must contain CSS styles and <body> as exported from Google Doc.
-func parseDoc(doc *html.Node, opts parser.Options) (*types.Codelab, error) {
+func parseDoc(ctx context.Context, doc *html.Node, opts parser.Options) (*types.Codelab, error) {
 	body := findAtom(doc, atom.Body)
 	if body == nil {
 		return nil, fmt.Errorf("document without a body")
@@ -213,13 +224,19 @@ func parseDoc(doc *html.Node, opts parser.Options) (*types.Codelab, error) {
 
 	ds := newDocState()
 	ds.css = style
+	ds.iframeDomains = opts.IframeDomains
 	ds.passMetadata = opts.PassMetadata
 
+	paras := paragraphs(body)
 	for ds.cur = body.FirstChild; ds.cur != nil; ds.cur = ds.cur.NextSibling {
 		if isComment(ds.css, ds.cur) {
 			// docs export comments at the end of the body
 			break
 		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		ds.pos = nodes.Pos{Paragraph: paras[ds.cur]}
 		switch {
 		case hasClass(ds.cur, "title") && ds.step == nil:
 			if v := stringifyNode(ds.cur, true, false); v != "" {
@@ -257,6 +274,7 @@ func finalizeStep(s *types.Step) {
 	sort.Strings(s.Tags)
 	s.Content.Nodes = parser.BlockNodes(s.Content.Nodes)
 	s.Content.Nodes = parser.CompactNodes(s.Content.Nodes)
+	nodes.FillPos(s.Content.Nodes...)
 	// TODO: find a better place for the code below
 	// find [[directive]] instructions and act accordingly
 	for i, n := range s.Content.Nodes {
@@ -345,8 +363,7 @@ func parseSubtree(ds *docState) []nodes.Node {
 func parseNode(ds *docState) (nodes.Node, bool) {
 	switch {
 	case isMeta(ds.css, ds.cur):
-		metaStep(ds)
-		return nil, true
+		return metaStep(ds), true
 	case ds.cur.Type == html.TextNode || ds.cur.DataAtom == atom.Br:
 		return text(ds), true
 	case ds.cur.DataAtom == atom.A:
@@ -391,42 +408,61 @@ func metaTable(ds *docState) {
 		if tr.FirstChild == nil || tr.FirstChild.NextSibling == nil {
 			continue
 		}
-		s := stringifyNode(tr.FirstChild.NextSibling, true, false)
 		fieldName := strcase.SnakeCase(stringifyNode(tr.FirstChild, true, false))
-		switch fieldName {
-		case "id", "url":
-			ds.clab.ID = s
-		case "author", "authors":
-			ds.clab.Authors = s
-		case "summary":
-			ds.clab.Summary = stringifyNode(tr.FirstChild.NextSibling, true, true)
-		case "category", "categories":
-			ds.clab.Categories = util.NormalizedSplit(s)
-			toLowerSlice(ds.clab.Categories)
-		case "environment", "environments", "tags":
-			ds.clab.Tags = util.NormalizedSplit(s)
-		case "status", "state":
-			v := util.NormalizedSplit(s)
-			sv := types.LegacyStatus(v)
-			ds.clab.Status = &sv
-		case "feedback", "feedback_link":
-			ds.clab.Feedback = s
-		case "analytics", "analytics_account", "google_analytics":
-			ds.clab.GA = s
-		default:
-			// If not explicitly parsed, it might be a pass_metadata value.
-			if _, ok := ds.passMetadata[fieldName]; ok {
-				ds.clab.Extra[fieldName] = s
-			}
-		}
+		lineBreak := fieldName == "summary"
+		metaField(ds, fieldName, stringifyNode(tr.FirstChild.NextSibling, true, lineBreak))
 	}
 	if len(ds.clab.Categories) > 0 {
 		ds.clab.Theme = slug(ds.clab.Categories[0])
 	}
 }
 
+// metaField sets a codelab metadata field from a meta table row.
+// The fieldName is expected in snake case.
+func metaField(ds *docState, fieldName, s string) {
+	switch fieldName {
+	case "id", "url":
+		ds.clab.ID = s
+	case "author", "authors":
+		ds.clab.Authors = s
+	case "summary":
+		ds.clab.Summary = s
+	case "category", "categories":
+		ds.clab.Categories = util.NormalizedSplit(s)
+		toLowerSlice(ds.clab.Categories)
+	case "environment", "environments", "tags":
+		ds.clab.Tags = util.NormalizedSplit(s)
+	case "status", "state":
+		v := util.NormalizedSplit(s)
+		sv := types.LegacyStatus(v)
+		ds.clab.Status = &sv
+	case "feedback", "feedback_link":
+		ds.clab.Feedback = s
+	case "analytics", "analytics_account", "google_analytics":
+		ds.clab.GA = s
+	case "glossary":
+		ds.clab.Glossary = types.ParseGlossary(s)
+	case "locale", "language":
+		ds.clab.Locale = s
+	case "translations":
+		ds.clab.Translations = types.ParseTranslations(s)
+	case "prerequisites", "prerequisite_labs":
+		ds.clab.Prerequisites = util.Unique(stringSlice(s))
+	case "next", "next_labs":
+		ds.clab.Next = util.Unique(stringSlice(s))
+	case "image", "social_image":
+		ds.clab.Image = s
+	default:
+		// If not explicitly parsed, it might be a pass_metadata value.
+		if _, ok := ds.passMetadata[fieldName]; ok {
+			ds.clab.Extra[fieldName] = s
+		}
+	}
+}
+
 // metaStep parses a codelab step meta instructions.
-func metaStep(ds *docState) {
+// It returns the node created by the instruction, if any.
+func metaStep(ds *docState) nodes.Node {
 	var text string
 	for {
 		text += stringifyNode(ds.cur, false, false)
@@ -435,12 +471,23 @@ func metaStep(ds *docState) {
 		}
 		ds.cur = ds.cur.NextSibling
 	}
+	return stepMeta(ds, text)
+}
+
+// stepMeta applies a "key: value" step meta instruction text
+// to the current step. Instructions which add content to the step,
+// such as "Activity tracking: Create a bucket", return the new node.
+func stepMeta(ds *docState, text string) nodes.Node {
 	meta := strings.SplitN(strings.TrimSpace(text), metaSep, 2)
 	if len(meta) != 2 {
-		return
+		return nil
 	}
 	value := strings.TrimSpace(meta[1])
-	switch strings.ToLower(strings.TrimSpace(meta[0])) {
+	key := strings.ToLower(strings.TrimSpace(meta[0]))
+	if strings.HasPrefix(key, metaActivity) {
+		return activity(key, value)
+	}
+	switch key {
 	case metaDuration:
 		parts := strings.SplitN(value, ":", len(durFactor))
 		if len(parts) == 1 {
@@ -460,11 +507,57 @@ func metaStep(ds *docState) {
 		ds.env = util.NormalizedSplit(value)
 		toLowerSlice(ds.env)
 		ds.step.Tags = append(ds.step.Tags, ds.env...)
-		ds.clab.Tags = append(ds.clab.Tags, ds.env...)
+		ds.clab.Tags = append(ds.clab.Tags, nodes.EnvNames(ds.env)...)
 		if ds.lastNode != nil && nodes.IsHeader(ds.lastNode.Type()) {
 			ds.lastNode.MutateEnv(ds.env)
 		}
+	case metaOptional:
+		ds.step.Optional = isYes(value)
+	case metaProducts:
+		ds.step.Products = util.Unique(stringSlice(value))
+	}
+	return nil
+}
+
+// stringSlice splits comma-separated v, dropping empty elements.
+func stringSlice(v string) []string {
+	var a []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			a = append(a, s)
+		}
 	}
+	return a
+}
+
+// isYes reports whether a step instruction value is "yes" or "true".
+func isYes(v string) bool {
+	switch strings.ToLower(v) {
+	case "yes", "true":
+		return true
+	}
+	return false
+}
+
+// activity returns a Qwiklabs activity tracking block with title value,
+// for a step meta instruction key of "activity tracking", numbered
+// at export time, or "activity tracking N" for tracked step number N.
+// It returns nil if key has anything else after the instruction name.
+func activity(key, value string) nodes.Node {
+	var step int
+	if num := strings.TrimSpace(strings.TrimPrefix(key, metaActivity)); num != "" {
+		var err error
+		if step, err = strconv.Atoi(num); err != nil {
+			return nil
+		}
+	}
+	var nn []nodes.Node
+	if value != "" {
+		l := nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: value}))
+		l.MutateBlock(true)
+		nn = append(nn, l)
+	}
+	return nodes.NewActivityNode(step, nn...)
 }
 
 // header creates a HeaderNode out of hn.
@@ -556,7 +649,8 @@ func tableRow(ds *docState) []*nodes.GridCell {
 	return row
 }
 
-// survey expects a header followed by 1 or more lists.
+// survey expects a header followed by 1 or more lists,
+// or by none for a free text question. See surveyGroup for question tags.
 func survey(ds *docState) nodes.Node {
 	// find direct parent of the survey elements
 	hn := findAtom(ds.cur, atom.Ul)
@@ -572,20 +666,18 @@ func survey(ds *docState) nodes.Node {
 			continue
 		}
 		opt, next := surveyOpt(c.NextSibling)
-		if len(opt) > 0 {
-			gg = append(gg, &nodes.SurveyGroup{
-				Name:    stringifyNode(c, true, false),
-				Options: opt,
-			})
-		}
+		gg = append(gg, &nodes.SurveyGroup{
+			Name:    stringifyNode(c, true, false),
+			Options: opt,
+		})
 		c = next
 	}
-	if len(gg) == 0 {
+	sn := surveyNode(fmt.Sprintf("%s-%d", ds.clab.ID, ds.survey+1), gg)
+	if sn == nil {
 		return nil
 	}
 	ds.survey++
-	id := fmt.Sprintf("%s-%d", ds.clab.ID, ds.survey)
-	return nodes.NewSurveyNode(id, gg...)
+	return sn
 }
 
 func surveyOpt(hn *html.Node) ([]string, *html.Node) {
@@ -627,6 +719,7 @@ func code(ds *docState, term bool) nodes.Node {
 	}
 	var lang string
 	n := nodes.NewCodeNode(v, term, lang)
+	n.Output = term && hasClassStyle(ds.css, td, "background-color", outputColor)
 	n.MutateBlock(td)
 	return n
 }
@@ -685,18 +778,11 @@ func image(ds *docState) nodes.Node {
 			return nil
 		}
 		// For iframe, make sure URL ends in allowlisted domain.
-		ok := false
-		for _, domain := range nodes.IframeAllowlist {
-			if u.Hostname() == domain {
-				ok = true
-				break
-			}
-		}
-		if ok {
+		if nodes.IframeAllowed(u, ds.iframeDomains) {
 			return iframe(ds)
 		}
 		errorAlt = "The domain of the requested iframe (" + u.Hostname() + ") has not been whitelisted."
-		fmt.Fprint(os.Stderr, errorAlt+"\n")
+		logging.Warnf("%s", errorAlt)
 	}
 
 	var imageBytes []byte
@@ -707,12 +793,12 @@ func image(ds *docState) nodes.Node {
 	} else if strings.HasPrefix(s, "data:") {
 		_, data, ok := strings.Cut(s, ",")
 		if !ok {
-			fmt.Fprint(os.Stderr, "Failed to decode data URL: "+s+" \n")
+			logging.Warnf("Failed to decode data URL: %s", s)
 			return nil
 		}
 		b, err := base64.StdEncoding.DecodeString(data)
 		if err != nil {
-			fmt.Fprint(os.Stderr, "Failed to decode data URL: "+s+"\n"+err.Error()+"\n")
+			logging.Warnf("Failed to decode data URL: %s: %v", s, err)
 			return nil
 		}
 		imageSrc = ""
@@ -738,15 +824,10 @@ func image(ds *docState) nodes.Node {
 }
 
 func youtube(ds *docState) nodes.Node {
-	u, err := url.Parse(nodeAttr(ds.cur, "alt"))
-	if err != nil {
-		return nil
-	}
-	v := u.Query().Get("v")
-	if v == "" {
+	n := nodes.ParseYouTubeURL(nodeAttr(ds.cur, "alt"))
+	if n == nil {
 		return nil
 	}
-	n := nodes.NewYouTubeNode(v)
 	n.MutateBlock(true)
 	return n
 }
@@ -788,6 +869,7 @@ func button(ds *docState) nodes.Node {
 	s := strings.ToLower(stringifyNode(a, true, false))
 	dl := strings.HasPrefix(s, "download ")
 	btn := nodes.NewButtonNode(true, true, dl, subtree...)
+	btn.Variant = nodes.ButtonVariantOf(s)
 
 	ln := nodes.NewURLNode(href, btn)
 	ln.MutateBlock(findBlockParent(ds.cur))
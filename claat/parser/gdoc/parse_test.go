@@ -16,6 +16,7 @@ package gdoc
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"io"
 	"reflect"
@@ -73,6 +74,54 @@ func TestParseStepDuration(t *testing.T) {
 	}
 }
 
+func TestParseStepActivity(t *testing.T) {
+	tests := []struct {
+		markup string
+		step   int
+		title  string
+	}{
+		{`<p><span class="c9">Activity tracking: Create a bucket</span></p>`, 0, "Create a bucket"},
+		{`<p><span class="c9">Activity Tracking 3: Upload an object</span></p>`, 3, "Upload an object"},
+		{`<p><span class="c9">Activity tracking:</span></p>`, 0, ""},
+	}
+	for i, test := range tests {
+		doc, err := html.Parse(strings.NewReader(test.markup))
+		if err != nil {
+			t.Errorf("%d: Parse(%q): %v", i, test.markup, err)
+		}
+		ds := &docState{
+			step: &types.Step{Content: nodes.NewListNode()},
+			css:  cssStyle{".c9": {"color": metaColor}},
+			cur:  doc.FirstChild,
+		}
+		parseTop(ds)
+		var got []*nodes.ActivityNode
+		nodes.WalkNodes(ds.step.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			if an, ok := n.(*nodes.ActivityNode); ok && entering {
+				got = append(got, an)
+			}
+			return n, nil
+		})
+		if len(got) != 1 {
+			t.Errorf("%d: %d activity tracking blocks; want 1", i, len(got))
+			continue
+		}
+		if title := nodes.PlainText(got[0].Content.Nodes...); got[0].Step != test.step || title != test.title {
+			t.Errorf("%d: activity = (%d, %q); want (%d, %q)", i, got[0].Step, title, test.step, test.title)
+		}
+	}
+	doc, _ := html.Parse(strings.NewReader(`<p><span class="c9">Activity tracking x: Bad</span></p>`))
+	ds := &docState{
+		step: &types.Step{Content: nodes.NewListNode()},
+		css:  cssStyle{".c9": {"color": metaColor}},
+		cur:  doc.FirstChild,
+	}
+	parseTop(ds)
+	if len(ds.step.Content.Nodes) != 0 {
+		t.Errorf("invalid activity tracking step number: content = %v; want none", ds.step.Content.Nodes)
+	}
+}
+
 func TestParseTopCodeBlock(t *testing.T) {
 	const markup = `
 	<table cellpadding="0" cellspacing="0"><tbody><tr>
@@ -121,6 +170,39 @@ func TestParseTopCodeBlock(t *testing.T) {
 	}
 }
 
+func TestParseOutputBlock(t *testing.T) {
+	const markup = `
+	<table cellpadding="0" cellspacing="0"><tbody><tr>
+	<td class="out" colspan="1" rowspan="1"><p><span class="term">Hello</span></p></td>
+	</tr></tbody></table>
+	<table cellpadding="0" cellspacing="0"><tbody><tr>
+	<td colspan="1" rowspan="1"><p><span class="term">echo Hello</span></p></td>
+	</tr></tbody></table>
+	`
+	doc, err := html.Parse(markupReader(markup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := &docState{
+		step: &types.Step{Content: nodes.NewListNode()},
+		css: cssStyle{
+			".out":  {"background-color": outputColor},
+			".term": {"font-family": fontConsole},
+		},
+		cur: doc.FirstChild,
+	}
+	parseTop(ds)
+	var got []bool
+	for _, n := range ds.step.Content.Nodes {
+		if cn, ok := n.(*nodes.CodeNode); ok {
+			got = append(got, cn.Output)
+		}
+	}
+	if want := []bool{true, false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Output = %v; want %v", got, want)
+	}
+}
+
 func TestMetaTable(t *testing.T) {
 	const markup = `
 	<html>
@@ -160,7 +242,7 @@ func TestMetaTable(t *testing.T) {
 	`
 
 	p := &Parser{}
-	clab, err := p.Parse(markupReader(markup), *parser.NewOptions())
+	clab, err := p.Parse(context.Background(), markupReader(markup), *parser.NewOptions())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -241,7 +323,7 @@ func TestMetaTablePassMetadata(t *testing.T) {
 		"extra_field_one": true,
 	}
 
-	clab, err := p.Parse(markupReader(markup), opts)
+	clab, err := p.Parse(context.Background(), markupReader(markup), opts)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -366,7 +448,7 @@ func TestParseDoc(t *testing.T) {
 	`
 
 	p := &Parser{}
-	c, err := p.Parse(markupReader(markup), *parser.NewOptions())
+	c, err := p.Parse(context.Background(), markupReader(markup), *parser.NewOptions())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -578,7 +660,7 @@ func TestParseFragment(t *testing.T) {
 
 	p := &Parser{}
 	opts := *parser.NewOptions()
-	fragmentNodes, err := p.ParseFragment(markupReader(markup), opts)
+	fragmentNodes, err := p.ParseFragment(context.Background(), markupReader(markup), opts)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -615,3 +697,31 @@ func TestParseFragment(t *testing.T) {
 		t.Errorf("nodes:\n\n%s\nwant:\n\n%s", html1, html2)
 	}
 }
+
+func TestParseFragmentPos(t *testing.T) {
+	const markup = `
+	<html><head><style>
+		.code { font-family: "Courier New" }
+	</style></head>
+	<body>
+		<p><span>First</span></p>
+		<p></p>
+		<h3><span>Header</span></h3>
+		<ul><li><span>One</span></li><li><span>Two</span></li></ul>
+		<table><tr><td><p><span class="code">x := 1</span></p></td></tr></table>
+	</body>
+	</html>
+	`
+	fragmentNodes, err := (&Parser{}).ParseFragment(context.Background(), markupReader(markup), *parser.NewOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, n := range fragmentNodes {
+		got = append(got, n.Type().String()+" "+n.Pos().String())
+	}
+	want := []string{"list paragraph 1", "header paragraph 3", "items list paragraph 4", "code paragraph 5"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("positions = %q; want %q", got, want)
+	}
+}
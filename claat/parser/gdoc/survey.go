@@ -0,0 +1,106 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdoc
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+var (
+	// surveyTagRegexp matches a tag at the end of the text of a survey question:
+	// its kind, as in "Topics? [multi]", "How was it? [rating 1-5]" and
+	// "Comments? [text]", its points, as in "[points 2]", or the pass threshold
+	// of the quiz, as in "[pass 3]". Numbers are in groups 2 and 3.
+	surveyTagRegexp = regexp.MustCompile(`(?i)\s*\[(multi|rating(?:\s+(\d+)\s*-\s*(\d+))?|text|points\s+(\d+)|pass\s+(\d+))\]\s*$`)
+	// surveyCorrectRegexp matches the tag of a correct quiz option,
+	// as in "ls [correct]".
+	surveyCorrectRegexp = regexp.MustCompile(`(?i)\s*\[correct\]\s*$`)
+)
+
+// surveyNode returns a survey node with id and questions qq, whose names and
+// options may end with tags setting their kind and scoring metadata.
+// Questions which need options and have none are left out.
+// It returns nil if there are no questions left.
+func surveyNode(id string, qq []*nodes.SurveyGroup) *nodes.SurveyNode {
+	var gg []*nodes.SurveyGroup
+	var pass int
+	for _, q := range qq {
+		g, p := surveyGroup(q.Name, q.Options)
+		if p > 0 {
+			pass = p
+		}
+		if g != nil {
+			gg = append(gg, g)
+		}
+	}
+	if len(gg) == 0 {
+		return nil
+	}
+	sn := nodes.NewSurveyNode(id, gg...)
+	sn.Pass = pass
+	return sn
+}
+
+// surveyGroup returns a survey group of question name, with its tags removed,
+// and options opt, along with the quiz pass threshold set by a tag, if any.
+// It returns a nil group if the question needs options and there are none.
+// Options of a rating question are its scale points, from 1 to 5 by default.
+func surveyGroup(name string, opt []string) (*nodes.SurveyGroup, int) {
+	g := &nodes.SurveyGroup{Name: name}
+	var pass int
+	for {
+		m := surveyTagRegexp.FindStringSubmatch(g.Name)
+		if m == nil {
+			break
+		}
+		g.Name = g.Name[:len(g.Name)-len(m[0])]
+		switch tag := strings.ToLower(strings.Fields(m[1])[0]); tag {
+		case "multi":
+			g.Kind = nodes.SurveyMulti
+		case "rating":
+			g.Kind = nodes.SurveyRating
+			min, max := 1, 5
+			if m[2] != "" {
+				min, _ = strconv.Atoi(m[2])
+				max, _ = strconv.Atoi(m[3])
+			}
+			opt = nodes.RatingOptions(min, max)
+		case "text":
+			g.Kind = nodes.SurveyText
+		case "points":
+			g.Points, _ = strconv.Atoi(m[4])
+		case "pass":
+			pass, _ = strconv.Atoi(m[5])
+		}
+	}
+	if g.Kind == nodes.SurveyText {
+		opt = nil
+	}
+	for _, o := range opt {
+		if loc := surveyCorrectRegexp.FindStringIndex(o); loc != nil {
+			o = o[:loc[0]]
+			g.Correct = append(g.Correct, o)
+		}
+		g.Options = append(g.Options, o)
+	}
+	if len(g.Options) == 0 && g.Kind != nodes.SurveyText {
+		return nil, pass
+	}
+	return g, pass
+}
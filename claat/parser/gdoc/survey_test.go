@@ -0,0 +1,99 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdoc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+func TestSurveyGroup(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  []string
+		out  *nodes.SurveyGroup
+		pass int
+	}{
+		{
+			name: "Level?",
+			opt:  []string{"Novice", "Expert"},
+			out:  &nodes.SurveyGroup{Name: "Level?", Options: []string{"Novice", "Expert"}},
+		},
+		{
+			name: "Level?",
+		},
+		{
+			name: "Topics? [multi]",
+			opt:  []string{"Go", "Web"},
+			out:  &nodes.SurveyGroup{Name: "Topics?", Kind: nodes.SurveyMulti, Options: []string{"Go", "Web"}},
+		},
+		{
+			name: "How was it? [Rating]",
+			out:  &nodes.SurveyGroup{Name: "How was it?", Kind: nodes.SurveyRating, Options: []string{"1", "2", "3", "4", "5"}},
+		},
+		{
+			name: "How was it? [rating 0 - 2]",
+			opt:  []string{"ignored"},
+			out:  &nodes.SurveyGroup{Name: "How was it?", Kind: nodes.SurveyRating, Options: []string{"0", "1", "2"}},
+		},
+		{
+			name: "Comments? [text]",
+			out:  &nodes.SurveyGroup{Name: "Comments?", Kind: nodes.SurveyText},
+		},
+		{
+			name: "Pick [one] of",
+			opt:  []string{"a"},
+			out:  &nodes.SurveyGroup{Name: "Pick [one] of", Options: []string{"a"}},
+		},
+		{
+			name: "2 + 2? [points 2] [pass 3]",
+			opt:  []string{"3", "4 [correct]"},
+			out:  &nodes.SurveyGroup{Name: "2 + 2?", Points: 2, Options: []string{"3", "4"}, Correct: []string{"4"}},
+			pass: 3,
+		},
+		{
+			name: "Even? [multi] [Points 3]",
+			opt:  []string{"1", "2 [Correct]", "4 [correct]"},
+			out:  &nodes.SurveyGroup{Name: "Even?", Kind: nodes.SurveyMulti, Points: 3, Options: []string{"1", "2", "4"}, Correct: []string{"2", "4"}},
+		},
+	}
+	for _, tc := range tests {
+		out, pass := surveyGroup(tc.name, tc.opt)
+		if diff := cmp.Diff(tc.out, out); diff != "" {
+			t.Errorf("surveyGroup(%q, %v) got diff (-want +got):\n%s", tc.name, tc.opt, diff)
+		}
+		if pass != tc.pass {
+			t.Errorf("surveyGroup(%q, %v) pass = %d; want %d", tc.name, tc.opt, pass, tc.pass)
+		}
+	}
+}
+
+func TestSurveyNodePass(t *testing.T) {
+	sn := surveyNode("quiz-1", []*nodes.SurveyGroup{
+		{Name: "Empty?"},
+		{Name: "2 + 2? [pass 1]", Options: []string{"3", "4 [correct]"}},
+	})
+	if sn == nil {
+		t.Fatal("surveyNode returned nil")
+	}
+	if sn.ID != "quiz-1" || sn.Pass != 1 || len(sn.Groups) != 1 {
+		t.Errorf("surveyNode = {ID: %q, Pass: %d, %d groups}; want {ID: \"quiz-1\", Pass: 1, 1 group}", sn.ID, sn.Pass, len(sn.Groups))
+	}
+	if sn := surveyNode("quiz-2", []*nodes.SurveyGroup{{Name: "Empty?"}}); sn != nil {
+		t.Errorf("surveyNode with no options = %+v; want nil", sn)
+	}
+}
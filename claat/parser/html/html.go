@@ -0,0 +1,113 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package html
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// findAtom returns first descendant of root which matches a, nil otherwise.
+// It returns root if it is the same Atom as a.
+func findAtom(root *html.Node, a atom.Atom) *html.Node {
+	return find(root, func(hn *html.Node) bool { return hn.DataAtom == a })
+}
+
+// findAllAtom returns all descendants of root which match a, in document order.
+func findAllAtom(root *html.Node, a atom.Atom) []*html.Node {
+	return findAll(root, func(hn *html.Node) bool { return hn.DataAtom == a })
+}
+
+// findElem is like findAtom for elements unknown to the atom package,
+// such as custom elements.
+func findElem(root *html.Node, name string) *html.Node {
+	return find(root, func(hn *html.Node) bool { return isElem(hn, name) })
+}
+
+// findAllElem is like findAllAtom for elements unknown to the atom package.
+func findAllElem(root *html.Node, name string) []*html.Node {
+	return findAll(root, func(hn *html.Node) bool { return isElem(hn, name) })
+}
+
+func isElem(hn *html.Node, name string) bool {
+	return hn.Type == html.ElementNode && hn.Data == name
+}
+
+func find(root *html.Node, match func(*html.Node) bool) *html.Node {
+	if match(root) {
+		return root
+	}
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		if v := find(c, match); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+func findAll(root *html.Node, match func(*html.Node) bool) []*html.Node {
+	var res []*html.Node
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		if match(c) {
+			res = append(res, c)
+		}
+		res = append(res, findAll(c, match)...)
+	}
+	return res
+}
+
+// attr returns the value of hn attribute key, or an empty string.
+func attr(hn *html.Node, key string) string {
+	for _, a := range hn.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// hasAttr reports whether hn has attribute key, regardless of its value.
+func hasAttr(hn *html.Node, key string) bool {
+	for _, a := range hn.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// hasClass reports whether the class attribute of hn contains name.
+func hasClass(hn *html.Node, name string) bool {
+	for _, c := range strings.Fields(attr(hn, "class")) {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// textContent returns concatenated values of all text nodes under root.
+func textContent(root *html.Node) string {
+	if root.Type == html.TextNode {
+		return root.Data
+	}
+	var b strings.Builder
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
@@ -0,0 +1,470 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package html implements a parser for codelabs previously exported
+// in the "html" format, so that they can be migrated back into
+// a Google Doc or Markdown source.
+//
+// Documents without a google-codelab element, such as plain HTML labs,
+// are parsed on a best effort basis: the first h1 is the codelab title
+// and each h2 starts a new step.
+package html
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/parser"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// Custom elements of the codelab-elements viewer.
+const (
+	elemCodelab = "google-codelab"
+	elemStep    = "google-codelab-step"
+	elemSurvey  = "google-codelab-survey"
+	elemButton  = "paper-button"
+	elemRadio   = "paper-radio-button"
+	elemCheck   = "paper-checkbox"
+	elemText    = "paper-textarea"
+	elemIcon    = "iron-icon"
+	// Qwiklabs activity tracking block.
+	elemActivity = "ql-activity-tracking"
+)
+
+var (
+	widthRegexp = regexp.MustCompile(`width:\s*([0-9.]+)px`)
+)
+
+// init registers this parser so it is available to CLaaT.
+func init() {
+	parser.Register("html", &Parser{})
+}
+
+// Parser is a parser of exported codelab HTML.
+type Parser struct {
+}
+
+// Parse parses a codelab exported as HTML.
+func (p *Parser) Parse(ctx context.Context, r io.Reader, opts parser.Options) (*types.Codelab, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	clab := types.NewCodelab()
+	root := findElem(doc, elemCodelab)
+	if root != nil {
+		parseMeta(clab, root)
+		for _, hn := range findAllElem(root, elemStep) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			st := clab.NewStep(attr(hn, "label"))
+			if d, err := strconv.ParseFloat(attr(hn, "duration"), 64); err == nil {
+				st.Duration = time.Duration(d * float64(time.Minute))
+			}
+			st.Optional = hasAttr(hn, "optional")
+			if p := attr(hn, "products"); p != "" {
+				st.Products = strings.Split(p, ",")
+			}
+			st.Content.Append(parseChildren(hn, style{})...)
+		}
+	} else {
+		if err := parsePlain(clab, doc); err != nil {
+			return nil, err
+		}
+	}
+	for _, st := range clab.Steps {
+		st.Content.Nodes = parser.DropIframes(st.Content.Nodes, opts.IframeDomains)
+	}
+	if clab.Title == "" {
+		if t := findAtom(doc, atom.Title); t != nil {
+			clab.Title = strings.TrimSpace(textContent(t))
+		}
+	}
+	if clab.ID == "" {
+		clab.ID = slug(clab.Title)
+	}
+	if clab.Duration == 0 {
+		var total time.Duration
+		for _, st := range clab.Steps {
+			total += st.Duration
+		}
+		clab.Duration = int(total.Minutes())
+	}
+	return clab, nil
+}
+
+// ParseFragment parses exported HTML markup without codelab metadata or steps.
+func (p *Parser) ParseFragment(ctx context.Context, r io.Reader, opts parser.Options) ([]nodes.Node, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	body := findAtom(doc, atom.Body)
+	if body == nil {
+		return nil, fmt.Errorf("document without a body")
+	}
+	return parser.DropIframes(parseChildren(body, style{}), opts.IframeDomains), nil
+}
+
+// parseMeta populates codelab metadata from the google-codelab element attributes.
+func parseMeta(clab *types.Codelab, hn *html.Node) {
+	clab.ID = attr(hn, "id")
+	clab.Title = attr(hn, "title")
+	clab.Feedback = attr(hn, "feedback-link")
+	clab.GA = attr(hn, "codelab-gaid")
+	clab.GA4 = attr(hn, "codelab-ga4id")
+	if d, err := strconv.Atoi(attr(hn, "duration")); err == nil {
+		clab.Duration = d
+		clab.DurationDeclared = d > 0
+	}
+	if env := attr(hn, "environment"); env != "" {
+		clab.Tags = []string{env}
+	}
+}
+
+// parsePlain parses a document without codelab custom elements.
+func parsePlain(clab *types.Codelab, doc *html.Node) error {
+	body := findAtom(doc, atom.Body)
+	if body == nil {
+		return fmt.Errorf("document without a body")
+	}
+	var st *types.Step
+	for hn := body.FirstChild; hn != nil; hn = hn.NextSibling {
+		switch {
+		case hn.DataAtom == atom.H1 && clab.Title == "":
+			clab.Title = strings.TrimSpace(textContent(hn))
+		case hn.DataAtom == atom.H2:
+			st = clab.NewStep(strings.TrimSpace(textContent(hn)))
+		case st != nil:
+			st.Content.Append(parseNode(hn, style{})...)
+		}
+	}
+	return nil
+}
+
+// style is inline text formatting inherited from ancestor elements.
+type style struct {
+	bold, italic, code bool
+}
+
+func parseChildren(hn *html.Node, s style) []nodes.Node {
+	var nn []nodes.Node
+	for c := hn.FirstChild; c != nil; c = c.NextSibling {
+		nn = append(nn, parseNode(c, s)...)
+	}
+	return nn
+}
+
+// parseNode converts hn into zero or more nodes,
+// reversing what render.WriteHTML does.
+func parseNode(hn *html.Node, s style) []nodes.Node {
+	switch hn.Type {
+	case html.TextNode:
+		return text(hn.Data, s)
+	case html.ElementNode:
+	default:
+		return nil
+	}
+
+	switch hn.Data {
+	case elemSurvey:
+		return one(survey(hn))
+	case elemActivity:
+		step, _ := strconv.Atoi(attr(hn, "step"))
+		return one(nodes.NewActivityNode(step, parseChildren(hn, style{})...))
+	case elemButton:
+		return one(button(hn, s))
+	case elemIcon, "script", "style":
+		return nil
+	}
+
+	switch hn.DataAtom {
+	case atom.Strong, atom.B:
+		s.bold = true
+		return parseChildren(hn, s)
+	case atom.Em, atom.I:
+		s.italic = true
+		return parseChildren(hn, s)
+	case atom.Code:
+		s.code = true
+		return parseChildren(hn, s)
+	case atom.Br:
+		return []nodes.Node{newText("\n", s)}
+	case atom.P:
+		if hasClass(hn, "attachment") {
+			return one(attachment(hn))
+		}
+		l := nodes.NewListNode(parseChildren(hn, s)...)
+		l.MutateBlock(true)
+		return one(l)
+	case atom.A:
+		return one(link(hn, s))
+	case atom.Img:
+		return one(image(hn))
+	case atom.Pre:
+		return one(code(hn))
+	case atom.Ul, atom.Ol:
+		return one(itemsList(hn))
+	case atom.Table:
+		return one(grid(hn))
+	case atom.Aside:
+		return one(infobox(hn))
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		return one(header(hn))
+	case atom.Iframe:
+		return one(iframe(hn))
+	case atom.Video:
+		return one(video(hn))
+	}
+	// Unknown wrappers, e.g. div or span: keep their content.
+	return parseChildren(hn, s)
+}
+
+// one returns a slice of n, or nil if n is nil.
+func one(n nodes.Node) []nodes.Node {
+	if n == nil || n.Empty() {
+		return nil
+	}
+	return []nodes.Node{n}
+}
+
+func text(v string, s style) []nodes.Node {
+	// Skip markup indentation between elements.
+	if strings.TrimSpace(v) == "" && strings.Contains(v, "\n") && !s.code {
+		return nil
+	}
+	return []nodes.Node{newText(v, s)}
+}
+
+func newText(v string, s style) *nodes.TextNode {
+	return nodes.NewTextNode(nodes.NewTextNodeOptions{
+		Value:  v,
+		Bold:   s.bold,
+		Italic: s.italic,
+		Code:   s.code,
+	})
+}
+
+func link(hn *html.Node, s style) nodes.Node {
+	n := nodes.NewURLNode(attr(hn, "href"), parseChildren(hn, s)...)
+	n.Name = attr(hn, "name")
+	n.Target = attr(hn, "target")
+	return n
+}
+
+func button(hn *html.Node, s style) nodes.Node {
+	dl := findElem(hn, elemIcon) != nil && attr(findElem(hn, elemIcon), "icon") == "file-download"
+	n := nodes.NewButtonNode(hasAttr(hn, "raised"), hasClass(hn, "colored"), dl, parseChildren(hn, s)...)
+	for _, v := range []nodes.ButtonVariant{nodes.ButtonPrimary, nodes.ButtonSecondary, nodes.ButtonConsole} {
+		if hasClass(hn, string(v)) {
+			n.Variant = v
+		}
+	}
+	return n
+}
+
+// attachment converts a download button of an attached file,
+// in a paragraph of class "attachment", into an attachment node.
+func attachment(hn *html.Node) nodes.Node {
+	a := findAtom(hn, atom.A)
+	if a == nil {
+		return nil
+	}
+	var nn []nodes.Node
+	if b := findElem(a, elemButton); b != nil {
+		nn = parseChildren(b, style{})
+	}
+	n := nodes.NewAttachmentNode(attr(a, "href"), attr(a, "download"), nn...)
+	n.MutateBlock(true)
+	return n
+}
+
+func image(hn *html.Node) nodes.Node {
+	n := nodes.NewImageNode(nodes.NewImageNodeOptions{
+		Src:   attr(hn, "src"),
+		Alt:   attr(hn, "alt"),
+		Title: attr(hn, "title"),
+	})
+	if m := widthRegexp.FindStringSubmatch(attr(hn, "style")); m != nil {
+		if w, err := strconv.ParseFloat(m[1], 32); err == nil {
+			n.Width = float32(w)
+		}
+	}
+	return n
+}
+
+func code(hn *html.Node) nodes.Node {
+	var n *nodes.CodeNode
+	if c := findAtom(hn, atom.Code); c != nil {
+		n = nodes.NewCodeNode(textContent(c), false, attr(c, "language"))
+		n.Diff = hasAttr(c, "diff")
+		n.Highlight, _ = nodes.ParseLineRanges(attr(c, "highlight"))
+		n.LineNumbers = hasAttr(c, "line-numbers")
+	} else {
+		n = nodes.NewCodeNode(textContent(hn), true, "")
+	}
+	n.NoCopy = hasAttr(hn, "nocopy")
+	n.Output = hasAttr(hn, "output")
+	return n
+}
+
+func itemsList(hn *html.Node) nodes.Node {
+	start, _ := strconv.Atoi(attr(hn, "start"))
+	typ := attr(hn, "type")
+	if hn.DataAtom == atom.Ol && typ == "" && start == 0 {
+		typ = "1"
+	}
+	l := nodes.NewItemsListNode(typ, start)
+	switch {
+	case hasClass(hn, "checklist"):
+		l.MutateType(nodes.NodeItemsCheck)
+	case hasClass(hn, "faq"):
+		l.MutateType(nodes.NodeItemsFAQ)
+	}
+	for li := hn.FirstChild; li != nil; li = li.NextSibling {
+		if li.DataAtom == atom.Li {
+			l.NewItem(parseChildren(li, style{})...)
+		}
+	}
+	return l
+}
+
+func grid(hn *html.Node) nodes.Node {
+	var rows [][]*nodes.GridCell
+	for _, tr := range findAllAtom(hn, atom.Tr) {
+		var row []*nodes.GridCell
+		for td := tr.FirstChild; td != nil; td = td.NextSibling {
+			if td.DataAtom != atom.Td && td.DataAtom != atom.Th {
+				continue
+			}
+			cs, err := strconv.Atoi(attr(td, "colspan"))
+			if err != nil {
+				cs = 1
+			}
+			rs, err := strconv.Atoi(attr(td, "rowspan"))
+			if err != nil {
+				rs = 1
+			}
+			row = append(row, &nodes.GridCell{
+				Colspan: cs,
+				Rowspan: rs,
+				Content: nodes.NewListNode(parseChildren(td, style{})...),
+			})
+		}
+		rows = append(rows, row)
+	}
+	return nodes.NewGridNode(rows...)
+}
+
+func infobox(hn *html.Node) nodes.Node {
+	kind := nodes.InfoboxPositive
+	if hasClass(hn, string(nodes.InfoboxNegative)) {
+		kind = nodes.InfoboxNegative
+	}
+	return nodes.NewInfoboxNode(kind, parseChildren(hn, style{})...)
+}
+
+func survey(hn *html.Node) nodes.Node {
+	var groups []*nodes.SurveyGroup
+	for _, h := range findAllAtom(hn, atom.H4) {
+		g := &nodes.SurveyGroup{Name: strings.TrimSpace(textContent(h))}
+		for sib := h.NextSibling; sib != nil && sib.DataAtom != atom.H4; sib = sib.NextSibling {
+			switch {
+			case isElem(sib, elemText):
+				g.Kind = nodes.SurveyText
+			case hasClass(sib, "survey-multi"):
+				g.Kind = nodes.SurveyMulti
+			case hasClass(sib, "survey-rating"):
+				g.Kind = nodes.SurveyRating
+			}
+			for _, o := range findAll(sib, func(hn *html.Node) bool { return isElem(hn, elemRadio) || isElem(hn, elemCheck) }) {
+				g.Options = append(g.Options, strings.TrimSpace(textContent(o)))
+			}
+		}
+		groups = append(groups, g)
+	}
+	return nodes.NewSurveyNode(attr(hn, "survey-id"), groups...)
+}
+
+func header(hn *html.Node) nodes.Node {
+	level, _ := strconv.Atoi(hn.Data[1:])
+	n := nodes.NewHeaderNode(level, parseChildren(hn, style{})...)
+	switch {
+	case hasClass(hn, "checklist"):
+		n.MutateType(nodes.NodeHeaderCheck)
+	case hasClass(hn, "faq"):
+		n.MutateType(nodes.NodeHeaderFAQ)
+	}
+	return n
+}
+
+func iframe(hn *html.Node) nodes.Node {
+	src := attr(hn, "src")
+	if yt := nodes.ParseYouTubeURL(src); yt != nil {
+		return yt
+	}
+	if v := nodes.NewVideoNode(src); v.VimeoID() != "" {
+		return v
+	}
+	// the domain is checked once parsed, see parser.DropIframes
+	u, err := url.Parse(src)
+	if err != nil || u.Scheme != "https" {
+		return nil
+	}
+	return nodes.NewIframeNode(u.String())
+}
+
+// video parses a <video> element and its captions track, if any.
+func video(hn *html.Node) nodes.Node {
+	n := nodes.NewVideoNode(attr(hn, "src"))
+	n.Poster = attr(hn, "poster")
+	for c := hn.FirstChild; c != nil; c = c.NextSibling {
+		if c.DataAtom == atom.Track && attr(c, "kind") == "captions" {
+			n.Captions = attr(c, "src")
+			n.CaptionsLang = attr(c, "srclang")
+			break
+		}
+	}
+	return n
+}
+
+// slug converts s into a codelab ID.
+func slug(s string) string {
+	var b strings.Builder
+	dash := true
+	for _, r := range strings.ToLower(s) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,247 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package html
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/parser"
+	"github.com/googlecodelabs/tools/claat/render"
+)
+
+func TestParse(t *testing.T) {
+	const src = `<!doctype html>
+<html>
+<head><title>Ignored</title></head>
+<body>
+<google-codelab codelab-gaid="UA-123" codelab-ga4id="G-123" id="my-lab" title="My Lab" environment="web" feedback-link="https://example.com/bugs">
+<google-codelab-step label="Overview" duration="2.5">
+<p>Hello <strong>world</strong></p>
+</google-codelab-step>
+<google-codelab-step label="Setup" duration="10" optional products="Cloud Storage,BigQuery">
+<pre><code language="go" class="go">fmt.Println()</code></pre>
+</google-codelab-step>
+</google-codelab>
+</body>
+</html>`
+	clab, err := (&Parser{}).Parse(context.Background(), strings.NewReader(src), *parser.NewOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clab.ID != "my-lab" || clab.Title != "My Lab" || clab.GA != "UA-123" || clab.GA4 != "G-123" {
+		t.Errorf("Parse() meta = %+v", clab.Meta)
+	}
+	if clab.Feedback != "https://example.com/bugs" {
+		t.Errorf("Parse() Feedback = %q", clab.Feedback)
+	}
+	if clab.Duration != 12 {
+		t.Errorf("Parse() Duration = %d, want 12", clab.Duration)
+	}
+	var titles []string
+	var durations []time.Duration
+	for _, st := range clab.Steps {
+		titles = append(titles, st.Title)
+		durations = append(durations, st.Duration)
+	}
+	if diff := cmp.Diff([]string{"Overview", "Setup"}, titles); diff != "" {
+		t.Errorf("Parse() step titles got diff (-want +got): %s", diff)
+	}
+	if diff := cmp.Diff([]time.Duration{150 * time.Second, 10 * time.Minute}, durations); diff != "" {
+		t.Errorf("Parse() step durations got diff (-want +got): %s", diff)
+	}
+	if st := clab.Steps[1]; !st.Optional || !cmp.Equal(st.Products, []string{"Cloud Storage", "BigQuery"}) {
+		t.Errorf("Parse() step 2 optional, products = %v, %q; want true, [Cloud Storage BigQuery]", st.Optional, st.Products)
+	}
+	if clab.Steps[0].Optional {
+		t.Errorf("Parse() step 1 is optional")
+	}
+}
+
+func TestParsePlain(t *testing.T) {
+	const src = `<html><body>
+<h1>Plain Lab</h1>
+<p>Intro is dropped.</p>
+<h2>First</h2>
+<p>One</p>
+<h2>Second</h2>
+<h3>Sub</h3>
+<p>Two</p>
+</body></html>`
+	clab, err := (&Parser{}).Parse(context.Background(), strings.NewReader(src), *parser.NewOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clab.Title != "Plain Lab" || clab.ID != "plain-lab" {
+		t.Errorf("Parse() Title, ID = %q, %q; want %q, %q", clab.Title, clab.ID, "Plain Lab", "plain-lab")
+	}
+	if len(clab.Steps) != 2 {
+		t.Fatalf("Parse() returned %d steps, want 2", len(clab.Steps))
+	}
+	if n := len(clab.Steps[1].Content.Nodes); n != 2 {
+		t.Errorf("Parse() second step has %d nodes, want 2", n)
+	}
+}
+
+// TestParseFragmentRoundtrip checks that markup produced by render.WriteHTML
+// is parsed back into the same nodes.
+func TestParseFragmentRoundtrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{
+			name: "Text",
+			in:   "<p>a <strong>b</strong> <em>c</em> <code>d</code><br>e</p>\n",
+		},
+		{
+			name: "Link",
+			in:   "<p><a href=\"https://example.com\" target=\"_blank\">link</a></p>\n",
+		},
+		{
+			name: "Image",
+			in:   "<p class=\"image-container\"><img alt=\"alt\" style=\"width: 20.00px\" src=\"img/a.png\"></p>\n",
+		},
+		{
+			name: "Button",
+			in:   "<p><paper-button class=\"colored\" raised><iron-icon icon=\"file-download\"></iron-icon>Get</paper-button></p>\n",
+		},
+		{
+			name: "ButtonVariant",
+			in:   "<p><a href=\"https://console.cloud.google.com/\"><paper-button class=\"colored console\" raised>Open console</paper-button></a></p>\n",
+		},
+		{
+			name: "Code",
+			in:   "<pre><code language=\"go\" class=\"go\">a := 1\nb := 2</code></pre>\n",
+		},
+		{
+			name: "Diff",
+			in:   "<pre><code language=\"go\" class=\"go\" diff>a := 1\n<span class=\"diff-remove\">-f(a)</span>\n<span class=\"diff-add\">+g(a)</span></code></pre>\n",
+		},
+		{
+			name: "Highlight",
+			in:   "<pre><code language=\"go\" class=\"go\" highlight=\"2\" line-numbers><span data-line=\"1\">a := 1</span>\n<span class=\"highlight\" data-line=\"2\">f(a)</span></code></pre>\n",
+		},
+		{
+			name: "Term",
+			in:   "<pre>ls -l</pre>\n",
+		},
+		{
+			name: "Output",
+			in:   "<pre output>total 0</pre>\n",
+		},
+		{
+			name: "NoCopy",
+			in:   "<pre noCopy><code language=\"go\" class=\"go\">f()</code></pre>\n",
+		},
+		{
+			name: "List",
+			in:   "<ol type=\"a\" start=\"3\">\n<li>one</li>\n<li>two</li>\n</ol>\n",
+		},
+		{
+			name: "Checklist",
+			in:   "<ul class=\"checklist\">\n<li>one</li>\n</ul>\n",
+		},
+		{
+			name: "Grid",
+			in:   "<table>\n<tr><td colspan=\"2\" rowspan=\"1\">a</td></tr>\n</table>\n",
+		},
+		{
+			name: "Infobox",
+			in:   "<aside class=\"warning\"><p>careful</p>\n</aside>\n",
+		},
+		{
+			name: "Survey",
+			in:   "<google-codelab-survey survey-id=\"s1\">\n<h4>How?</h4>\n<paper-radio-group>\n<paper-radio-button>Well</paper-radio-button>\n<paper-radio-button>Bad</paper-radio-button>\n</paper-radio-group>\n</google-codelab-survey>\n",
+		},
+		{
+			name: "SurveyKinds",
+			in:   "<google-codelab-survey survey-id=\"s2\">\n<h4>Topics?</h4>\n<div class=\"survey-multi\">\n<paper-checkbox>Go</paper-checkbox>\n<paper-checkbox>Web</paper-checkbox>\n</div>\n<h4>Rate</h4>\n<paper-radio-group class=\"survey-rating\">\n<paper-radio-button>1</paper-radio-button>\n<paper-radio-button>2</paper-radio-button>\n</paper-radio-group>\n<h4>Comments?</h4>\n<paper-textarea></paper-textarea>\n</google-codelab-survey>\n",
+		},
+		{
+			name: "Activity",
+			in:   "<ql-activity-tracking step=\"2\"><p>Create a bucket</p>\n</ql-activity-tracking>\n",
+		},
+		{
+			name: "Attachment",
+			in:   "<p class=\"attachment\"><a href=\"files/starter.zip\" download=\"starter.zip\"><paper-button class=\"colored\" raised><iron-icon icon=\"file-download\"></iron-icon>Starter code</paper-button></a></p>\n",
+		},
+		{
+			name: "Header",
+			in:   "<h3 class=\"faq\" id=\"faq\" is-upgraded>FAQ</h3>\n",
+		},
+		{
+			name: "YouTube",
+			in:   "<iframe class=\"youtube-video\" src=\"https://www.youtube.com/embed/abc?rel=0\" allow=\"accelerometer; autoplay; encrypted-media; gyroscope; picture-in-picture\" allowfullscreen></iframe>\n",
+		},
+		{
+			name: "YouTubeOptions",
+			in:   "<iframe class=\"youtube-video\" src=\"https://www.youtube-nocookie.com/embed/abc?rel=0&amp;start=90&amp;cc_load_policy=1&amp;cc_lang_pref=fr\" allow=\"accelerometer; autoplay; encrypted-media; gyroscope; picture-in-picture\" allowfullscreen></iframe>\n",
+		},
+		{
+			name: "Video",
+			in:   "<video class=\"video\" src=\"intro.mp4\" poster=\"intro.png\" controls><track kind=\"captions\" src=\"intro.vtt\" srclang=\"en\" default></video>\n",
+		},
+		{
+			name: "Vimeo",
+			in:   "<iframe class=\"vimeo-video\" src=\"https://player.vimeo.com/video/76979871\" allow=\"autoplay; fullscreen; picture-in-picture\" allowfullscreen></iframe>\n",
+		},
+		{
+			name: "Iframe",
+			in:   "<iframe class=\"embedded-iframe\" src=\"https://codepen.io/frame\"></iframe>\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nn, err := (&Parser{}).ParseFragment(context.Background(), strings.NewReader(tc.in), *parser.NewOptions())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := render.WriteHTML(&buf, "", "html", nn...); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.in, buf.String()); diff != "" {
+				t.Errorf("ParseFragment(%q) rendered back got diff (-want +got): %s", tc.in, diff)
+			}
+		})
+	}
+}
+
+func TestParseIframeAllowlist(t *testing.T) {
+	in := "<iframe class=\"embedded-iframe\" src=\"https://example.com/frame\"></iframe>\n"
+	nn, err := (&Parser{}).ParseFragment(context.Background(), strings.NewReader(in), *parser.NewOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nn) != 0 {
+		t.Errorf("ParseFragment(%q) = %v; want no nodes", in, nn)
+	}
+
+	opts := *parser.NewOptions()
+	opts.IframeDomains = []string{"example.com"}
+	nn, err = (&Parser{}).ParseFragment(context.Background(), strings.NewReader(in), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nn) != 1 || nn[0].Type() != nodes.NodeIframe {
+		t.Errorf("ParseFragment(%q) with IframeDomains %q = %v; want an iframe", in, opts.IframeDomains, nn)
+	}
+}
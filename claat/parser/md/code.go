@@ -0,0 +1,166 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package md
+
+import (
+	"path"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+	"golang.org/x/net/html"
+)
+
+// fencedCodeRenderer renders fenced code blocks like the goldmark HTML
+// renderer does, and keeps their whole info string in a data-info attribute,
+// since the language class only has its first word.
+type fencedCodeRenderer struct{}
+
+// RegisterFuncs implements renderer.NodeRenderer.
+func (r *fencedCodeRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.render)
+}
+
+func (r *fencedCodeRenderer) render(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.FencedCodeBlock)
+	if !entering {
+		w.WriteString("</code></pre>\n")
+		return ast.WalkContinue, nil
+	}
+	w.WriteString("<pre")
+	w.Write(blockLineAttr(n))
+	w.WriteString("><code")
+	if lang := n.Language(source); lang != nil {
+		w.WriteString(` class="language-`)
+		w.Write(util.EscapeHTML(lang))
+		w.WriteString(`"`)
+	}
+	if n.Info != nil {
+		w.WriteString(` data-info="`)
+		w.Write(util.EscapeHTML(n.Info.Segment.Value(source)))
+		w.WriteString(`"`)
+	}
+	w.WriteByte('>')
+	for i := 0; i < n.Lines().Len(); i++ {
+		line := n.Lines().At(i)
+		w.Write(util.EscapeHTML(line.Value(source)))
+	}
+	return ast.WalkContinue, nil
+}
+
+// codeInfo is the information of a fenced code block info string,
+// such as "go diff", "bash runnable", "include main.go#L10-L40"
+// or "go {hl_lines=3-5 linenos}".
+type codeInfo struct {
+	lang        string            // code language
+	src         string            // file to include the code from
+	diff        bool              // code is a diff
+	runnable    bool              // code is tagged as runnable
+	highlight   []nodes.LineRange // lines to highlight
+	lineNumbers bool              // show line numbers
+	noCopy      bool              // code is display-only
+	output      bool              // code is expected output
+}
+
+// parseCodeInfo parses the info string of a fenced code block.
+// The first word is the language, or "include" followed by the file
+// to include code from, in which case the language is the file extension.
+// Other words are tags and attributes, optionally in curly braces:
+// hl_lines=3-5,8 highlights lines and linenos shows line numbers.
+// Tags are diff, runnable, nocopy and output.
+// A lone "diff" language is a diff of unknown language.
+// Invalid attributes are ignored.
+func parseCodeInfo(info string) codeInfo {
+	var ci codeInfo
+	ff := strings.Fields(info)
+	if len(ff) == 0 {
+		return ci
+	}
+	ci.lang, ff = ff[0], ff[1:]
+	if ci.lang == "include" && len(ff) > 0 {
+		ci.src, ff = ff[0], ff[1:]
+		ci.lang = includeLang(ci.src)
+	}
+	if ci.lang == "diff" {
+		ci.lang, ci.diff = "", true
+	}
+	for _, f := range ff {
+		f = strings.Trim(f, "{}")
+		key, value := f, ""
+		if i := strings.IndexByte(f, '='); i >= 0 {
+			key, value = f[:i], strings.Trim(f[i+1:], `"'`)
+		}
+		switch strings.ToLower(key) {
+		case "diff":
+			ci.diff = true
+		case "runnable":
+			ci.runnable = true
+		case "nocopy":
+			ci.noCopy = true
+		case "output":
+			ci.output = true
+		case "hl_lines":
+			if rr, err := nodes.ParseLineRanges(value); err == nil {
+				ci.highlight = rr
+			}
+		case "linenos":
+			ci.lineNumbers = value != "false"
+		}
+	}
+	return ci
+}
+
+// includeLang returns the language of code included from file src,
+// named after its extension.
+func includeLang(src string) string {
+	if i := strings.IndexByte(src, '#'); i >= 0 {
+		src = src[:i]
+	}
+	return strings.TrimPrefix(path.Ext(src), ".")
+}
+
+// isCodeBlock reports whether hn is a Qwiklabs code block wrapping
+// a fenced code block, <ql-code-block diff highlight="3-5" line-numbers>.
+func isCodeBlock(hn *html.Node) bool {
+	return hn.Type == html.ElementNode && strings.ToLower(hn.Data) == "ql-code-block"
+}
+
+// codeBlock parses a Qwiklabs code block, applying its attributes
+// to the code it wraps. It returns nil if the block has no code.
+func codeBlock(ds *docState) nodes.Node {
+	ds.push(nil)
+	nn := parseSubtree(ds)
+	ds.pop()
+	var cn *nodes.CodeNode
+	nodes.WalkNodes(nn, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if c, ok := n.(*nodes.CodeNode); ok && cn == nil {
+			cn = c
+		}
+		return n, nil
+	})
+	if cn == nil {
+		return nil
+	}
+	cn.Diff = cn.Diff || hasNodeAttr(ds.cur, "diff")
+	cn.LineNumbers = cn.LineNumbers || hasNodeAttr(ds.cur, "line-numbers")
+	cn.NoCopy = cn.NoCopy || hasNodeAttr(ds.cur, "nocopy")
+	cn.Output = cn.Output || hasNodeAttr(ds.cur, "output")
+	if rr, err := nodes.ParseLineRanges(nodeAttr(ds.cur, "highlight")); err == nil && len(rr) > 0 {
+		cn.Highlight = rr
+	}
+	return cn
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package md
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/parser"
+)
+
+// FuzzParse checks that parsing any input does not panic.
+// Run it with go test ./parser/md -fuzz FuzzParse.
+func FuzzParse(f *testing.F) {
+	f.Add([]byte("id: lab\nsummary: s\n\n# Lab\n\n## Setup\nDuration: 1:00\n\nText with **bold** and [link](https://example.com).\n"))
+	f.Add([]byte("# Lab\n\n## Step\n\n* a\n* b\n\n1. c\n\n```go\nx\n```\n\n> aside positive\n> Note\n\n| a | b |\n| - | - |\n| 1 | 2 |\n"))
+	f.Add([]byte("# Lab\n\n## Quiz\n\n<form>\n<name>Q</name>\n<input value=\"A\" correct>\n</form>\n\n<video id=\"x\"></video>\n\n<<frag.md>>\n"))
+	f.Add([]byte("<button>[a](b)</button>\n<img src=\"\" width=\"-1\">\n"))
+	f.Fuzz(func(t *testing.T, src []byte) {
+		p := &Parser{}
+		p.Parse(context.Background(), bytes.NewReader(src), *parser.NewOptions())
+		p.ParseFragment(context.Background(), bytes.NewReader(src), *parser.NewOptions())
+	})
+}
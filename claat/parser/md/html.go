@@ -43,7 +43,12 @@ func isHeader(hn *html.Node) bool {
 // TODO rename, it only captures some meta. Maybe redo the meta system?
 func isMeta(hn *html.Node) bool {
 	elem := strings.ToLower(hn.Data)
-	return strings.HasPrefix(elem, metaDuration+metaSep) || strings.HasPrefix(elem, metaEnvironment+metaSep)
+	for _, k := range []string{metaDuration, metaEnvironment, metaOptional, metaProducts} {
+		if strings.HasPrefix(elem, k+metaSep) {
+			return true
+		}
+	}
+	return false
 }
 
 func isBold(hn *html.Node) bool {
@@ -118,6 +123,10 @@ func isButton(hn *html.Node) bool {
 	return hn.DataAtom == atom.Button
 }
 
+func isKbd(hn *html.Node) bool {
+	return hn.DataAtom == atom.Kbd
+}
+
 func isAside(hn *html.Node) bool {
 	return hn.DataAtom == atom.Aside
 }
@@ -131,7 +140,8 @@ func isNewAside(hn *html.Node) bool {
 	}
 
 	asideText := strings.ToLower(hn.FirstChild.NextSibling.FirstChild.Data)
-	return strings.HasPrefix(asideText, "aside positive") || strings.HasPrefix(asideText, "aside negative")
+	return strings.HasPrefix(asideText, "aside positive") || strings.HasPrefix(asideText, "aside negative") ||
+		strings.HasPrefix(asideText, "aside instructor")
 }
 
 func isInfobox(hn *html.Node) bool {
@@ -174,6 +184,27 @@ func isList(hn *html.Node) bool {
 	return hn.DataAtom == atom.Ul || hn.DataAtom == atom.Ol
 }
 
+// isActivity reports whether hn is a Qwiklabs activity tracking block,
+// <ql-activity-tracking step=N>.
+func isActivity(hn *html.Node) bool {
+	return hn.Type == html.ElementNode && strings.ToLower(hn.Data) == "ql-activity-tracking"
+}
+
+// isAttachment reports whether hn is a Qwiklabs file download block,
+// <ql-file-download url="...">.
+func isAttachment(hn *html.Node) bool {
+	return hn.Type == html.ElementNode && strings.ToLower(hn.Data) == "ql-file-download"
+}
+
+// isVideo reports whether hn is a Qwiklabs video block, <ql-video src="...">,
+// or a <video> element with a src attribute.
+func isVideo(hn *html.Node) bool {
+	if hn.Type != html.ElementNode {
+		return false
+	}
+	return strings.ToLower(hn.Data) == "ql-video" || hn.DataAtom == atom.Video && hasNodeAttr(hn, "src")
+}
+
 func isYoutube(hn *html.Node) bool {
 	return hn.DataAtom == atom.Video
 }
@@ -293,6 +324,18 @@ func nodeAttr(n *html.Node, key string) string {
 	return ""
 }
 
+// hasNodeAttr reports whether the given node has the given HTML attribute,
+// regardless of its value. Keys are case insensitive.
+func hasNodeAttr(n *html.Node, key string) bool {
+	key = strings.ToLower(key)
+	for _, attr := range n.Attr {
+		if strings.ToLower(attr.Key) == key {
+			return true
+		}
+	}
+	return false
+}
+
 // TODO divide into smaller functions
 // TODO redo comment, more than just text nodes are handled and atom.A
 // TODO should we really have trim?
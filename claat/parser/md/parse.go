@@ -19,6 +19,7 @@ package md
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -40,7 +41,10 @@ import (
 	"github.com/googlecodelabs/tools/claat/util"
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/extension"
+	gmparser "github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
 	gmhtml "github.com/yuin/goldmark/renderer/html"
+	gmutil "github.com/yuin/goldmark/util"
 )
 
 // Metadata constants for the YAML header
@@ -57,12 +61,20 @@ const (
 	MetaTags                = "tags"
 	MetaSource              = "source"
 	MetaDuration            = "duration"
+	MetaGlossary            = "glossary"
+	MetaLocale              = "locale"
+	MetaTranslations        = "translations"
+	MetaPrerequisites       = "prerequisites"
+	MetaNextLabs            = "next_labs"
+	MetaImage               = "image"
 )
 
 const (
 	metaSep         = ":"           // step instruction format, key:value
 	metaDuration    = "duration"    // step duration instruction
 	metaEnvironment = "environment" // step environment instruction
+	metaOptional    = "optional"    // optional step instruction
+	metaProducts    = "products"    // step required products instruction
 	metaTagImport   = "import"      // import remote resource instruction
 
 	// possible content of special header nodes in lower case.
@@ -97,6 +109,9 @@ var (
 
 var (
 	// ErrForbiddenFragmentImports means importing another markdown file in a markdown fragment is forbidden.
+	//
+	// Deprecated: fragments may import other fragments, which the fetch package
+	// resolves recursively. This error is no longer returned.
 	ErrForbiddenFragmentImports = errors.New("importing content in a fragment is forbidden")
 	// ErrForbiddenFragmentSteps means declaring extra codelabs step in a markdown fragment is forbidden.
 	ErrForbiddenFragmentSteps = errors.New("defining steps in a fragment is forbidden")
@@ -112,7 +127,7 @@ type Parser struct {
 }
 
 // Parse parses a codelab written in Markdown.
-func (p *Parser) Parse(r io.Reader, opts parser.Options) (*types.Codelab, error) {
+func (p *Parser) Parse(ctx context.Context, r io.Reader, opts parser.Options) (*types.Codelab, error) {
 	// Convert Markdown to HTML for easy parsing.
 	b, err := ioutil.ReadAll(r)
 	if err != nil {
@@ -128,11 +143,11 @@ func (p *Parser) Parse(r io.Reader, opts parser.Options) (*types.Codelab, error)
 		return nil, err
 	}
 	// Parse the markup.
-	return parseMarkup(doc, opts)
+	return parseMarkup(ctx, doc, opts)
 }
 
 // ParseFragment parses a codelab fragment written in Markdown.
-func (p *Parser) ParseFragment(r io.Reader, opts parser.Options) ([]nodes.Node, error) {
+func (p *Parser) ParseFragment(ctx context.Context, r io.Reader, opts parser.Options) ([]nodes.Node, error) {
 	b, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
@@ -141,22 +156,25 @@ func (p *Parser) ParseFragment(r io.Reader, opts parser.Options) ([]nodes.Node,
 	if err != nil {
 		return nil, err
 	}
-	h := bytes.NewBuffer(b)
+	// An explicit body start tag keeps a leading import, which is an HTML comment,
+	// from being placed before the body element.
+	h := bytes.NewBuffer(append([]byte("<body>"), b...))
 	doc, err := html.Parse(h)
 	if err != nil {
 		return nil, err
 	}
 
-	return parsePartialMarkup(doc)
+	return parsePartialMarkup(doc, opts)
 }
 
-func parsePartialMarkup(root *html.Node) ([]nodes.Node, error) {
+func parsePartialMarkup(root *html.Node, opts parser.Options) ([]nodes.Node, error) {
 	body := findAtom(root, atom.Body)
 	if body == nil {
 		return nil, fmt.Errorf("document without a body")
 	}
 
 	ds := newDocState()
+	ds.iframeDomains = opts.IframeDomains
 	ds.step = ds.clab.NewStep("fragment")
 	for ds.cur = body.FirstChild; ds.cur != nil; ds.cur = ds.cur.NextSibling {
 		switch {
@@ -170,9 +188,6 @@ func parsePartialMarkup(root *html.Node) ([]nodes.Node, error) {
 	}
 
 	finalizeStep(ds.step)
-	if hasImport(ds) {
-		return nil, ErrForbiddenFragmentImports
-	}
 
 	return ds.step.Content.Nodes, nil
 }
@@ -186,6 +201,8 @@ type docState struct {
 	env      []string       // current environment
 	cur      *html.Node     // current HTML node
 	stack    []*stackItem   // cur and flags stack
+	// additional domains allowed in iframes
+	iframeDomains []string
 }
 
 type stackItem struct {
@@ -233,7 +250,13 @@ func (ds *docState) appendNodes(nn ...nodes.Node) {
 // It takes a raw markdown bytes and outputs parsed xhtml in bytes.
 func renderToHTML(b []byte) ([]byte, error) {
 	b = convertImports(b)
-	gmParser := goldmark.New(goldmark.WithRendererOptions(gmhtml.WithUnsafe()), goldmark.WithExtensions(extension.Typographer, extension.Table))
+	gmParser := goldmark.New(
+		goldmark.WithParserOptions(gmparser.WithASTTransformers(gmutil.Prioritized(&lineTransformer{}, 100))),
+		goldmark.WithRendererOptions(gmhtml.WithUnsafe(), renderer.WithNodeRenderers(
+			gmutil.Prioritized(&fencedCodeRenderer{}, 100),
+			gmutil.Prioritized(&htmlBlockRenderer{}, 100),
+			gmutil.Prioritized(&blockquoteRenderer{}, 100))),
+		goldmark.WithExtensions(extension.Typographer, extension.Table))
 	var out bytes.Buffer
 	if err := gmParser.Convert(b, &out); err != nil {
 		panic(err)
@@ -242,15 +265,19 @@ func renderToHTML(b []byte) ([]byte, error) {
 }
 
 // parseMarkup accepts html nodes to markup created by the Markdown parser. It returns a pointer to a codelab object, or an error if one occurs.
-func parseMarkup(markup *html.Node, opts parser.Options) (*types.Codelab, error) {
+func parseMarkup(ctx context.Context, markup *html.Node, opts parser.Options) (*types.Codelab, error) {
 	body := findAtom(markup, atom.Body)
 	if body == nil {
 		return nil, fmt.Errorf("document without a body")
 	}
 
 	ds := newDocState()
+	ds.iframeDomains = opts.IframeDomains
 
 	for ds.cur = body.FirstChild; ds.cur != nil; ds.cur = ds.cur.NextSibling {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		switch {
 		// metadata first
 		case ds.cur.DataAtom == atom.H1 && ds.clab.Title == "":
@@ -276,7 +303,10 @@ func parseMarkup(markup *html.Node, opts parser.Options) (*types.Codelab, error)
 	finalizeStep(ds.step) // TODO: last ds.step is never finalized in newStep
 	ds.clab.Tags = util.Unique(ds.clab.Tags)
 	sort.Strings(ds.clab.Tags)
-	ds.clab.Duration = int(ds.totdur.Minutes())
+	if ds.clab.Duration == 0 {
+		// no total duration declared in metadata
+		ds.clab.Duration = int(ds.totdur.Minutes())
+	}
 	return ds.clab, nil
 }
 
@@ -288,6 +318,7 @@ func finalizeStep(s *types.Step) {
 	sort.Strings(s.Tags)
 	s.Content.Nodes = parser.BlockNodes(s.Content.Nodes)
 	s.Content.Nodes = parser.CompactNodes(s.Content.Nodes)
+	nodes.FillPos(s.Content.Nodes...)
 }
 
 // parseTop parses nodes tree starting at, and including, ds.cur.
@@ -330,7 +361,18 @@ func parseSubtree(ds *docState) []nodes.Node {
 // but resuling nodes.Node is nil.
 //
 // The flag argument modifies default behavour of the func.
+// The returned node and its descendants take the Markdown line of ds.cur.
 func parseNode(ds *docState) (nodes.Node, bool) {
+	hn := ds.cur
+	n, ok := parseElement(ds)
+	if n != nil {
+		nodes.SetPos(linePos(hn), n)
+	}
+	return n, ok
+}
+
+// parseElement implements parseNode.
+func parseElement(ds *docState) (nodes.Node, bool) {
 	// we have \n end of line nodes after each tag from the blackfriday parser.
 	// We just want to ignore them as it makes previous node detection fuzzy.
 	if ds.cur.Type == html.TextNode && ds.cur.Data == "\n" {
@@ -348,6 +390,8 @@ func parseNode(ds *docState) (nodes.Node, bool) {
 		return image(ds), true
 	case isButton(ds.cur):
 		return button(ds), true
+	case isKbd(ds.cur):
+		return kbd(ds), true
 	case isHeader(ds.cur):
 		return header(ds), true
 	case isList(ds.cur):
@@ -364,8 +408,16 @@ func parseNode(ds *docState) (nodes.Node, bool) {
 		return infobox(ds), true
 	case isSurvey(ds.cur):
 		return survey(ds), true
+	case isActivity(ds.cur):
+		return activity(ds), true
+	case isAttachment(ds.cur):
+		return attachment(ds), true
+	case isCodeBlock(ds.cur):
+		return codeBlock(ds), true
 	case isTable(ds.cur):
 		return table(ds), true
+	case isVideo(ds.cur):
+		return video(ds), true
 	case isYoutube(ds.cur):
 		return youtube(ds), true
 	case isFragmentImport(ds.cur):
@@ -390,7 +442,10 @@ func newStep(ds *docState) {
 func parseMetadata(ds *docState, opts parser.Options) error {
 	m := map[string]string{}
 	// Split the keys from values.
-	d := ds.cur.FirstChild.Data
+	var d string
+	if c := ds.cur.FirstChild; c != nil {
+		d = c.Data
+	}
 	scanner := bufio.NewScanner(strings.NewReader(d))
 	for scanner.Scan() {
 		s := metadataRegexp.FindStringSubmatch(scanner.Text())
@@ -431,7 +486,8 @@ func addMetadataToCodelab(m map[string]string, c *types.Codelab, opts parser.Opt
 			c.Tags = append(c.Tags, util.NormalizedSplit(v)...)
 		case MetaStatus:
 			// Standardize the statuses and append to the codelab field.
-			statuses := util.NormalizedSplit(v)
+			// Status may be written back as a list, e.g. "[draft]", by the md template.
+			statuses := util.NormalizedSplit(strings.Trim(v, "[]"))
 			statusesAsLegacy := types.LegacyStatus(statuses)
 			c.Status = &statusesAsLegacy
 		case MetaFeedbackLink:
@@ -449,11 +505,30 @@ func addMetadataToCodelab(m map[string]string, c *types.Codelab, opts parser.Opt
 		case MetaSource:
 			// Directly assign the source doc ID to the source field.
 			c.Source = v
+		case MetaGlossary:
+			// Parse "term: definition; ..." entries into the glossary.
+			c.Glossary = types.ParseGlossary(v)
+		case MetaLocale:
+			// Directly assign the locale to the codelab field.
+			c.Locale = v
+		case MetaTranslations:
+			// Parse "locale=source, ..." entries into the translations.
+			c.Translations = types.ParseTranslations(v)
+		case MetaPrerequisites:
+			// Split the lab IDs or URLs, keeping their case.
+			c.Prerequisites = util.Unique(stringSlice(v))
+		case MetaNextLabs:
+			// Split the lab IDs or URLs, keeping their case.
+			c.Next = util.Unique(stringSlice(v))
+		case MetaImage:
+			// Directly assign the social card image URL to the codelab field.
+			c.Image = v
 		case MetaDuration:
 			// Convert the duration to an integer and assign to the duration field.
 			duration, err := strconv.Atoi(v)
 			if err == nil {
 				c.Duration = duration
+				c.DurationDeclared = duration > 0
 			}
 		default:
 			// If not explicitly parsed, it might be a pass_metadata value.
@@ -500,13 +575,26 @@ func metaStep(ds *docState) {
 		ds.env = util.Unique(stringSlice(value))
 		toLowerSlice(ds.env)
 		ds.step.Tags = append(ds.step.Tags, ds.env...)
-		ds.clab.Tags = append(ds.clab.Tags, ds.env...)
+		ds.clab.Tags = append(ds.clab.Tags, nodes.EnvNames(ds.env)...)
 		if ds.lastNode != nil && nodes.IsHeader(ds.lastNode.Type()) {
 			ds.lastNode.MutateEnv(ds.env)
 		}
+	case metaOptional:
+		ds.step.Optional = isYes(value)
+	case metaProducts:
+		ds.step.Products = util.Unique(stringSlice(value))
 	}
 }
 
+// isYes reports whether a step instruction value is "yes" or "true".
+func isYes(v string) bool {
+	switch strings.ToLower(v) {
+	case "yes", "true":
+		return true
+	}
+	return false
+}
+
 // header creates a HeaderNode out of hn.
 // It returns nil if header content is empty.
 // A non-empty header will always reset ds.env to nil.
@@ -539,6 +627,9 @@ func aside(ds *docState) nodes.Node {
 		if v.Key == "class" && v.Val == "negative" {
 			kind = nodes.InfoboxNegative
 		}
+		if v.Key == "class" && v.Val == string(nodes.InfoboxInstructor) {
+			kind = nodes.InfoboxInstructor
+		}
 	}
 
 	ds.push(nil)
@@ -557,11 +648,17 @@ func newAside(ds *docState) nodes.Node {
 	kind := nodes.InfoboxPositive
 	s := ds.cur.FirstChild.NextSibling.FirstChild.Data
 	if strings.HasPrefix(s, "aside negative") {
-		ds.cur.FirstChild.NextSibling.FirstChild.Data = strings.TrimPrefix(s, "aside negative")
+		s = strings.TrimPrefix(s, "aside negative")
 		kind = nodes.InfoboxNegative
+	} else if strings.HasPrefix(s, "aside instructor") {
+		s = strings.TrimPrefix(s, "aside instructor")
+		kind = nodes.InfoboxInstructor
 	} else {
-		ds.cur.FirstChild.NextSibling.FirstChild.Data = strings.TrimPrefix(s, "aside positive")
+		s = strings.TrimPrefix(s, "aside positive")
 	}
+	// Drop the line break following the aside kind so that the content
+	// does not start with a space when rendered back to Markdown.
+	ds.cur.FirstChild.NextSibling.FirstChild.Data = strings.TrimLeft(s, "\n")
 
 	ds.push(nil)
 	nn := parseSubtree(ds)
@@ -594,6 +691,49 @@ func infobox(ds *docState) nodes.Node {
 	return nodes.NewInfoboxNode(kind, nn...)
 }
 
+// activity parses a Qwiklabs activity tracking block. A block without
+// a valid step attribute is numbered at export time.
+func activity(ds *docState) nodes.Node {
+	step, _ := strconv.Atoi(nodeAttr(ds.cur, "step"))
+	trimLineBreaks(ds.cur)
+	ds.push(nil)
+	nn := parseSubtree(ds)
+	nn = parser.BlockNodes(nn)
+	nn = parser.CompactNodes(nn)
+	ds.pop()
+	n := nodes.NewActivityNode(step, nn...)
+	n.MutateBlock(true)
+	return n
+}
+
+// attachment parses a Qwiklabs file download block, whose content
+// is the text of the download link.
+// It returns nil if the block has no url attribute.
+func attachment(ds *docState) nodes.Node {
+	src := strings.TrimSpace(nodeAttr(ds.cur, "url"))
+	if src == "" {
+		return nil
+	}
+	trimLineBreaks(ds.cur)
+	ds.push(nil)
+	nn := parser.CompactNodes(parseSubtree(ds))
+	ds.pop()
+	n := nodes.NewAttachmentNode(src, nodeAttr(ds.cur, "name"), nn...)
+	n.MutateBlock(true)
+	return n
+}
+
+// trimLineBreaks drops the line breaks around the content of hn,
+// so that it does not start or end with a space.
+func trimLineBreaks(hn *html.Node) {
+	if c := hn.FirstChild; c != nil && c.Type == html.TextNode {
+		c.Data = strings.TrimLeft(c.Data, "\n")
+	}
+	if c := hn.LastChild; c != nil && c.Type == html.TextNode {
+		c.Data = strings.TrimRight(c.Data, "\n")
+	}
+}
+
 // table parses an arbitrary <table> element and its children.
 // It may return other elements if the table is just a wrap.
 func table(ds *docState) nodes.Node {
@@ -655,7 +795,14 @@ func tableRow(ds *docState) []*nodes.GridCell {
 }
 
 // survey expects 1 or more name Nodes followed by 1 or more input Nodes.
-// Each input node is expected to have a value attribute.
+// The type of the first input of a name sets the kind of the question:
+// "checkbox" for multi-select, "range" with min and max attributes
+// for a rating scale, "text" for free text, and single choice otherwise.
+// Each input node of a choice is expected to have a value attribute.
+//
+// A quiz has scoring metadata: a pass attribute of the form with the minimum
+// score to pass, points attributes of names, and a correct attribute on
+// the inputs of correct options, or with the correct point of a rating.
 func survey(ds *docState) nodes.Node {
 	var gg []*nodes.SurveyGroup
 	ns := findChildAtoms(ds.cur, atom.Name)
@@ -668,11 +815,19 @@ func survey(ds *docState) nodes.Node {
 				break
 			}
 		}
-		opt := surveyOpt(inputs)
-		if len(opt) > 0 {
+		// a name without text, such as <name></name>, has no child
+		if n.FirstChild == nil {
+			continue
+		}
+		kind, opt := surveyOpt(inputs)
+		if len(opt) > 0 || kind == nodes.SurveyText {
+			points, _ := strconv.Atoi(nodeAttr(n, "points"))
 			gg = append(gg, &nodes.SurveyGroup{
 				Name:    strings.TrimSpace(n.FirstChild.Data),
+				Kind:    kind,
 				Options: opt,
+				Points:  points,
+				Correct: surveyCorrect(kind, inputs),
 			})
 		}
 	}
@@ -681,10 +836,54 @@ func survey(ds *docState) nodes.Node {
 	}
 	ds.survey++
 	id := fmt.Sprintf("%s-%d", ds.clab.ID, ds.survey)
-	return nodes.NewSurveyNode(id, gg...)
+	sn := nodes.NewSurveyNode(id, gg...)
+	sn.Pass, _ = strconv.Atoi(nodeAttr(ds.cur, "pass"))
+	return sn
 }
 
-func surveyOpt(inputs []*html.Node) []string {
+// surveyCorrect returns the correct options of a question of the given kind
+// with inputs: the values of inputs with a correct attribute, or the value
+// of the correct attribute of a rating.
+func surveyCorrect(kind nodes.SurveyKind, inputs []*html.Node) []string {
+	var correct []string
+	for _, input := range inputs {
+		if !hasNodeAttr(input, "correct") {
+			continue
+		}
+		if kind == nodes.SurveyRating {
+			correct = append(correct, nodeAttr(input, "correct"))
+		} else {
+			correct = append(correct, nodeAttr(input, "value"))
+		}
+	}
+	return correct
+}
+
+func surveyOpt(inputs []*html.Node) (nodes.SurveyKind, []string) {
+	if len(inputs) == 0 {
+		return nodes.SurveyChoice, nil
+	}
+	switch strings.ToLower(nodeAttr(inputs[0], "type")) {
+	case "checkbox":
+		return nodes.SurveyMulti, surveyValues(inputs)
+	case "range":
+		min, err := strconv.Atoi(nodeAttr(inputs[0], "min"))
+		if err != nil {
+			min = 1
+		}
+		max, err := strconv.Atoi(nodeAttr(inputs[0], "max"))
+		if err != nil {
+			max = 5
+		}
+		return nodes.SurveyRating, nodes.RatingOptions(min, max)
+	case "text":
+		return nodes.SurveyText, nil
+	}
+	return nodes.SurveyChoice, surveyValues(inputs)
+}
+
+// surveyValues returns the value attributes of inputs.
+func surveyValues(inputs []*html.Node) []string {
 	var opt []string
 	for _, input := range inputs {
 		for _, attr := range input.Attr {
@@ -716,14 +915,26 @@ func code(ds *docState, term bool) nodes.Node {
 	}
 	// get the language hint
 	var lan string
-	if !term {
-		for _, a := range ds.cur.Attr {
-			if a.Key == "class" && strings.HasPrefix(a.Val, "language-") {
-				lan = strings.Replace(a.Val, "language-", "", 0)
-			}
+	for _, a := range ds.cur.Attr {
+		if a.Key == "class" && strings.HasPrefix(a.Val, "language-") {
+			lan = strings.TrimPrefix(a.Val, "language-")
 		}
 	}
-	n := nodes.NewCodeNode(v, term, lan)
+	ci := codeInfo{lang: lan}
+	if info := nodeAttr(ds.cur, "data-info"); info != "" {
+		ci = parseCodeInfo(info)
+	}
+	if term {
+		ci.lang = ""
+	}
+	n := nodes.NewCodeNode(v, term, ci.lang)
+	n.Src = ci.src
+	n.Diff = ci.diff
+	n.Runnable = ci.runnable
+	n.Highlight = ci.highlight
+	n.LineNumbers = ci.lineNumbers
+	n.NoCopy = ci.noCopy
+	n.Output = ci.output
 	n.MutateBlock(elem)
 	return n
 }
@@ -778,14 +989,7 @@ func image(ds *docState) nodes.Node {
 			return nil
 		}
 		// For iframe, make sure URL ends in allowlisted domain.
-		ok := false
-		for _, domain := range nodes.IframeAllowlist {
-			if u.Hostname() == domain {
-				ok = true
-				break
-			}
-		}
-		if ok {
+		if nodes.IframeAllowed(u, ds.iframeDomains) {
 			return iframe(ds)
 		}
 	}
@@ -817,15 +1021,43 @@ func image(ds *docState) nodes.Node {
 	return n
 }
 
+// youtube creates a YouTubeNode out of a <video id="..."> element,
+// with optional start, captions and nocookie attributes, or of an image
+// with a YouTube video URL in its alt text.
 func youtube(ds *docState) nodes.Node {
-	for _, attr := range ds.cur.Attr {
-		if attr.Key == "id" {
-			n := nodes.NewYouTubeNode(attr.Val)
-			n.MutateBlock(true)
-			return n
+	if ds.cur.DataAtom == atom.Img {
+		n := nodes.ParseYouTubeURL(nodeAttr(ds.cur, "alt"))
+		if n == nil {
+			return nil
 		}
+		n.MutateBlock(true)
+		return n
 	}
-	return nil
+	id := nodeAttr(ds.cur, "id")
+	if id == "" {
+		return nil
+	}
+	n := nodes.NewYouTubeNode(id)
+	n.Start = nodes.ParseYouTubeTime(nodeAttr(ds.cur, "start"))
+	n.Captions = nodeAttr(ds.cur, "captions")
+	n.NoCookie = hasNodeAttr(ds.cur, "nocookie")
+	n.MutateBlock(true)
+	return n
+}
+
+// video creates a VideoNode out of a video block, with an optional poster
+// image and captions track. It returns nil if the block has no src attribute.
+func video(ds *docState) nodes.Node {
+	src := strings.TrimSpace(nodeAttr(ds.cur, "src"))
+	if src == "" {
+		return nil
+	}
+	n := nodes.NewVideoNode(src)
+	n.Poster = nodeAttr(ds.cur, "poster")
+	n.Captions = nodeAttr(ds.cur, "captions")
+	n.CaptionsLang = nodeAttr(ds.cur, "srclang")
+	n.MutateBlock(true)
+	return n
 }
 
 func fragmentImport(ds *docState) nodes.Node {
@@ -873,12 +1105,28 @@ func button(ds *docState) nodes.Node {
 	s := strings.ToLower(stringifyNode(a, true))
 	dl := strings.HasPrefix(s, "download ")
 	btn := nodes.NewButtonNode(true, true, dl, n...)
+	btn.Variant = nodes.ButtonVariantOf(s)
+	if v := nodeAttr(ds.cur, "variant"); v != "" {
+		btn.Variant = nodes.ParseButtonVariant(v)
+	}
 
 	ln := nodes.NewURLNode(href, btn)
 	ln.MutateBlock(findNearestBlockAncestor(ds.cur))
 	return ln
 }
 
+// kbd creates a KbdNode of the key of a <kbd> element.
+// It returns nil if the element is empty.
+func kbd(ds *docState) nodes.Node {
+	key := stringifyNode(ds.cur, true)
+	if key == "" {
+		return nil
+	}
+	n := nodes.NewKbdNode(key)
+	n.MutateBlock(findNearestBlockAncestor(ds.cur))
+	return n
+}
+
 // Link creates a URLNode out of hn, parsing href and name attributes.
 // It returns nil if hn contents is empty.
 // The resuling link's content is always a single text node.
@@ -1018,13 +1266,3 @@ func convertImports(content []byte) []byte {
 
 	return bytes.Join(escaped, []byte("\n"))
 }
-
-func hasImport(ds *docState) bool {
-	for _, step := range ds.clab.Steps {
-		if len(nodes.ImportNodes(step.Content.Nodes)) > 0 {
-			return true
-		}
-	}
-
-	return false
-}
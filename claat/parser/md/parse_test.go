@@ -14,6 +14,7 @@
 package md
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"reflect"
@@ -50,7 +51,7 @@ func parseCodelab(markup string, opts parser.Options) (*types.Codelab, error) {
 	r := strings.NewReader(markup)
 	p := &Parser{}
 
-	return p.Parse(r, opts)
+	return p.Parse(context.Background(), r, opts)
 }
 
 func parseFragment(markup string) ([]nodes.Node, error) {
@@ -58,7 +59,7 @@ func parseFragment(markup string) ([]nodes.Node, error) {
 	p := &Parser{}
 
 	opts := *parser.NewOptions()
-	return p.ParseFragment(r, opts)
+	return p.ParseFragment(context.Background(), r, opts)
 }
 
 func stringify(nodesToStringify []nodes.Node, level string) string {
@@ -157,6 +158,17 @@ Duration: %v
 		if c.Duration != tc.out {
 			t.Errorf("%d: wanted duration %d but got %d", i, c.Duration, tc.out)
 		}
+		if c.DurationDeclared {
+			t.Errorf("%d: summed duration is declared", i)
+		}
+	}
+}
+
+func TestDeclaredDuration(t *testing.T) {
+	content := "---\nid: lab\nduration: 30\n\n---\n# Title\n\n## Step Title\nDuration: 5:00\n"
+	c := mustParseCodelab(content, *parser.NewOptions())
+	if c.Duration != 30 || !c.DurationDeclared {
+		t.Errorf("Duration, DurationDeclared = %d, %v; want 30, true", c.Duration, c.DurationDeclared)
 	}
 }
 
@@ -266,11 +278,10 @@ func TestParseFragment(t *testing.T) {
 `,
 		},
 		{
-			name: "Forbidden Nested Imports",
+			name: "Nested Imports",
 			input: `
 I want nested imports
 <</path/to/something else.md>>`,
-			wantErr: ErrForbiddenFragmentImports,
 		},
 		{
 			name: "If something looks like metadata, it is treated as text content",
@@ -416,3 +427,229 @@ I'm going to inject some HTML
 		})
 	}
 }
+
+func TestParseSurveyKinds(t *testing.T) {
+	in := stdHeader + `
+## Feedback
+
+<form>
+<name>Level</name>
+<input value="Novice">
+<input value="Expert">
+<name>Topics</name>
+<input type="checkbox" value="Go">
+<input type="checkbox" value="Web">
+<name>Rate</name>
+<input type="range" min="0" max="3">
+<name>Comments</name>
+<input type="text">
+</form>
+`
+	lab := mustParseCodelab(in, *parser.NewOptions())
+	var got []nodes.SurveyGroup
+	for _, n := range lab.Steps[0].Content.Nodes {
+		if sn, ok := n.(*nodes.SurveyNode); ok {
+			for _, g := range sn.Groups {
+				got = append(got, *g)
+			}
+		}
+	}
+	want := []nodes.SurveyGroup{
+		{Name: "Level", Options: []string{"Novice", "Expert"}},
+		{Name: "Topics", Kind: nodes.SurveyMulti, Options: []string{"Go", "Web"}},
+		{Name: "Rate", Kind: nodes.SurveyRating, Options: []string{"0", "1", "2", "3"}},
+		{Name: "Comments", Kind: nodes.SurveyText},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("survey groups = %+v; want %+v", got, want)
+	}
+}
+
+func TestParseQuiz(t *testing.T) {
+	in := stdHeader + `
+## Quiz
+
+<form pass="3">
+<name points="2">Lists files?</name>
+<input value="ls" correct>
+<input value="cd">
+<name>Shells?</name>
+<input type="checkbox" value="bash" correct>
+<input type="checkbox" value="vim">
+<input type="checkbox" value="zsh" correct>
+<name>Rate</name>
+<input type="range" min="1" max="3" correct="3">
+</form>
+`
+	lab := mustParseCodelab(in, *parser.NewOptions())
+	sn, ok := lab.Steps[0].Content.Nodes[0].(*nodes.SurveyNode)
+	if !ok {
+		t.Fatalf("first node is %T, want *nodes.SurveyNode", lab.Steps[0].Content.Nodes[0])
+	}
+	if sn.Pass != 3 {
+		t.Errorf("Pass = %d; want 3", sn.Pass)
+	}
+	var got []nodes.SurveyGroup
+	for _, g := range sn.Groups {
+		got = append(got, *g)
+	}
+	want := []nodes.SurveyGroup{
+		{Name: "Lists files?", Options: []string{"ls", "cd"}, Points: 2, Correct: []string{"ls"}},
+		{Name: "Shells?", Kind: nodes.SurveyMulti, Options: []string{"bash", "vim", "zsh"}, Correct: []string{"bash", "zsh"}},
+		{Name: "Rate", Kind: nodes.SurveyRating, Options: []string{"1", "2", "3"}, Correct: []string{"3"}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("survey groups = %+v; want %+v", got, want)
+	}
+}
+
+func TestParseCodeInclude(t *testing.T) {
+	in := stdHeader + "\n## Code\n\n```include samples/main.go#L10-L40\n```\n\n~~~ include app.py\n~~~\n"
+	lab := mustParseCodelab(in, *parser.NewOptions())
+	var got [][2]string
+	for _, n := range lab.Steps[0].Content.Nodes {
+		if cn, ok := n.(*nodes.CodeNode); ok {
+			got = append(got, [2]string{cn.Src, cn.Lang})
+		}
+	}
+	want := [][2]string{{"samples/main.go#L10-L40", "go"}, {"app.py", "py"}}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("code includes = %q; want %q", got, want)
+	}
+}
+
+func TestParseCodeRunnable(t *testing.T) {
+	in := stdHeader + "\n## Code\n\n```bash runnable\nls\n```\n\n```go\nx()\n```\n\n```include run.sh runnable\n```\n\n```console runnable\n$ ls\n```\n"
+	lab := mustParseCodelab(in, *parser.NewOptions())
+	var got []string
+	for _, n := range lab.Steps[0].Content.Nodes {
+		if cn, ok := n.(*nodes.CodeNode); ok {
+			got = append(got, fmt.Sprintf("%s %s %v %v", cn.Lang, cn.Src, cn.Term, cn.Runnable))
+		}
+	}
+	want := []string{"bash  false true", "go  false false", "sh run.sh false true", "  true true"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("code blocks = %q; want %q", got, want)
+	}
+}
+
+func TestParseCodeDiff(t *testing.T) {
+	in := stdHeader + "\n## Code\n\n```diff\n-a\n+b\n```\n\n<ql-code-block diff>\n\n```go\n+x()\n```\n\n</ql-code-block>\n\n```go\n-x\n```\n"
+	lab := mustParseCodelab(in, *parser.NewOptions())
+	var got []string
+	nodes.WalkNodes(lab.Steps[0].Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if cn, ok := n.(*nodes.CodeNode); ok && entering {
+			got = append(got, fmt.Sprintf("%s %v", cn.Lang, cn.Diff))
+		}
+		return n, nil
+	})
+	want := []string{" true", "go true", "go false"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("code blocks = %q; want %q", got, want)
+	}
+}
+
+func TestParseCodeHighlight(t *testing.T) {
+	in := stdHeader + "\n## Code\n\n```go {hl_lines=1,3-4 linenos}\na\n```\n\n<ql-code-block highlight=\"2\" line-numbers>\n\n```go\nb\n```\n\n</ql-code-block>\n\n```go hl_lines=x\nc\n```\n"
+	lab := mustParseCodelab(in, *parser.NewOptions())
+	var got []string
+	nodes.WalkNodes(lab.Steps[0].Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if cn, ok := n.(*nodes.CodeNode); ok && entering {
+			got = append(got, fmt.Sprintf("%s %s %v", cn.Lang, nodes.FormatLineRanges(cn.Highlight), cn.LineNumbers))
+		}
+		return n, nil
+	})
+	want := []string{"go 1,3-4 true", "go 2 true", "go  false"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("code blocks = %q; want %q", got, want)
+	}
+}
+
+func TestParseCodeCopy(t *testing.T) {
+	in := stdHeader + "\n## Code\n\n```console output\ntotal 0\n```\n\n```go noCopy\nf()\n```\n\n<ql-code-block noCopy output>\n\n```console\nok\n```\n\n</ql-code-block>\n"
+	lab := mustParseCodelab(in, *parser.NewOptions())
+	var got []string
+	nodes.WalkNodes(lab.Steps[0].Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if cn, ok := n.(*nodes.CodeNode); ok && entering {
+			got = append(got, fmt.Sprintf("%v %v %v", cn.Term, cn.NoCopy, cn.Output))
+		}
+		return n, nil
+	})
+	want := []string{"true false true", "false true false", "true true true"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("code blocks = %q; want %q", got, want)
+	}
+}
+
+func TestParseYouTube(t *testing.T) {
+	in := stdHeader + "\n## Video\n\n<video id=\"vid\" start=\"1m30s\" captions=\"fr\" nocookie></video>\n\n![https://www.youtube.com/watch?v=vid2&t=12](https://img.youtube.com/vi/vid2/0.jpg)\n"
+	lab := mustParseCodelab(in, *parser.NewOptions())
+	var got []string
+	nodes.WalkNodes(lab.Steps[0].Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if yt, ok := n.(*nodes.YouTubeNode); ok && entering {
+			got = append(got, fmt.Sprintf("%s %d %s %v", yt.VideoID, yt.Start, yt.Captions, yt.NoCookie))
+		}
+		return n, nil
+	})
+	want := []string{"vid 90 fr true", "vid2 12  false"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("videos = %q; want %q", got, want)
+	}
+}
+
+func TestParseVideo(t *testing.T) {
+	in := stdHeader + "\n## Video\n\n<ql-video src=\"intro.mp4\" poster=\"intro.png\" captions=\"intro.vtt\" srclang=\"en\"></ql-video>\n\n<video src=\"https://vimeo.com/76979871\"></video>\n"
+	lab := mustParseCodelab(in, *parser.NewOptions())
+	var got []string
+	nodes.WalkNodes(lab.Steps[0].Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if v, ok := n.(*nodes.VideoNode); ok && entering {
+			got = append(got, fmt.Sprintf("%s %s %s %s", v.Src, v.Poster, v.Captions, v.CaptionsLang))
+		}
+		return n, nil
+	})
+	want := []string{"intro.mp4 intro.png intro.vtt en", "https://vimeo.com/76979871   "}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("videos = %q; want %q", got, want)
+	}
+}
+
+func TestParsePos(t *testing.T) {
+	in := `id: pos
+
+# Positions
+
+## Step
+
+Some text.
+
+` + "```go" + `
+fmt.Println()
+` + "```" + `
+
+* one
+* two
+
+> aside positive
+>
+> A note.
+
+<button>[Download](https://example.com/file.zip)</button>
+
+| A | B |
+| - | - |
+| 1 | 2 |
+`
+	lab := mustParseCodelab(in, *parser.NewOptions())
+	var got []string
+	for _, n := range lab.Steps[0].Content.Nodes {
+		got = append(got, fmt.Sprintf("%s %s", n.Type(), n.Pos()))
+	}
+	want := []string{"list line 7", "code line 9", "items list line 13", "infobox line 16", "list line 20", "grid line 22"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("positions = %q; want %q", got, want)
+	}
+	items := lab.Steps[0].Content.Nodes[2].(*nodes.ItemsListNode).Items
+	if p := items[1].Nodes[0].Pos(); p != (nodes.Pos{Line: 14}) {
+		t.Errorf("second item text position = %v; want line 14", p)
+	}
+}
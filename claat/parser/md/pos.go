@@ -0,0 +1,181 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package md
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/yuin/goldmark/ast"
+	gmparser "github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	gmtext "github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"golang.org/x/net/html"
+)
+
+// lineAttr is the attribute of the HTML elements of Markdown blocks
+// holding the line of the block in the Markdown source.
+const lineAttr = "data-line"
+
+// lineTransformer sets the lineAttr attribute of Markdown blocks,
+// which the goldmark renderers write along with other attributes.
+type lineTransformer struct{}
+
+// Transform implements parser.ASTTransformer.
+func (t *lineTransformer) Transform(doc *ast.Document, reader gmtext.Reader, pc gmparser.Context) {
+	starts := lineStarts(reader.Source())
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || n.Type() != ast.TypeBlock || n.Kind() == ast.KindDocument {
+			return ast.WalkContinue, nil
+		}
+		line, ok := blockLine(starts, n)
+		if ok {
+			n.SetAttributeString(lineAttr, []byte(strconv.Itoa(line)))
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// lineStarts returns the offsets of the lines of src.
+func lineStarts(src []byte) []int {
+	starts := []int{0}
+	for i, b := range src {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// blockLine returns the line, from 1, block n starts at.
+func blockLine(starts []int, n ast.Node) (int, bool) {
+	off, ok := startOffset(n)
+	if !ok {
+		return 0, false
+	}
+	line := sort.Search(len(starts), func(i int) bool { return starts[i] > off })
+	if fc, ok := n.(*ast.FencedCodeBlock); ok && fc.Info == nil && line > 1 {
+		line-- // the opening fence precedes the code lines
+	}
+	return line, true
+}
+
+// startOffset returns the offset of the first source line of n,
+// or of its first descendant with one, such as the first paragraph
+// of a list. The offset of a fenced code block is that of its info string.
+func startOffset(n ast.Node) (int, bool) {
+	if fc, ok := n.(*ast.FencedCodeBlock); ok && fc.Info != nil {
+		return fc.Info.Segment.Start, true
+	}
+	if t, ok := n.(*ast.Text); ok {
+		return t.Segment.Start, true
+	}
+	if n.Type() == ast.TypeBlock && n.Lines().Len() > 0 {
+		return n.Lines().At(0).Start, true
+	}
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if off, ok := startOffset(c); ok {
+			return off, true
+		}
+	}
+	return 0, false
+}
+
+// blockLineAttr returns the lineAttr attribute of block n, if any,
+// for renderers writing their own markup.
+func blockLineAttr(n ast.Node) []byte {
+	v, ok := n.AttributeString(lineAttr)
+	if !ok {
+		return nil
+	}
+	return []byte(` ` + lineAttr + `="` + string(v.([]byte)) + `"`)
+}
+
+// startTag matches the name of the first start tag of a raw HTML block.
+var startTag = regexp.MustCompile(`^\s*<[a-zA-Z][a-zA-Z0-9-]*`)
+
+// htmlBlockRenderer renders raw HTML blocks like the goldmark HTML renderer
+// does with unsafe HTML, and adds the lineAttr attribute to their first
+// start tag, such as that of an <aside> or a <form>.
+type htmlBlockRenderer struct{}
+
+// RegisterFuncs implements renderer.NodeRenderer.
+func (r *htmlBlockRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindHTMLBlock, r.render)
+}
+
+func (r *htmlBlockRenderer) render(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.HTMLBlock)
+	if !entering {
+		if n.HasClosure() {
+			closure := n.ClosureLine
+			w.Write(closure.Value(source))
+		}
+		return ast.WalkContinue, nil
+	}
+	for i := 0; i < n.Lines().Len(); i++ {
+		seg := n.Lines().At(i)
+		line := seg.Value(source)
+		if i == 0 {
+			if loc := startTag.FindIndex(line); loc != nil {
+				w.Write(line[:loc[1]])
+				w.Write(blockLineAttr(n))
+				line = line[loc[1]:]
+			}
+		}
+		w.Write(line)
+	}
+	return ast.WalkContinue, nil
+}
+
+// blockquoteRenderer renders blockquotes with the lineAttr attribute.
+// Unlike the goldmark HTML renderer with attributes, it keeps the newline
+// after the start tag which isNewAside expects.
+type blockquoteRenderer struct{}
+
+// RegisterFuncs implements renderer.NodeRenderer.
+func (r *blockquoteRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindBlockquote, r.render)
+}
+
+func (r *blockquoteRenderer) render(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		w.WriteString("</blockquote>\n")
+		return ast.WalkContinue, nil
+	}
+	w.WriteString("<blockquote")
+	w.Write(blockLineAttr(n))
+	w.WriteString(">\n")
+	return ast.WalkContinue, nil
+}
+
+// linePos returns the source position of hn: the line of the nearest
+// Markdown block it is part of, or an unknown position.
+func linePos(hn *html.Node) nodes.Pos {
+	for ; hn != nil; hn = hn.Parent {
+		if hn.Type != html.ElementNode {
+			continue
+		}
+		if v := nodeAttr(hn, lineAttr); v != "" {
+			if line, err := strconv.Atoi(v); err == nil {
+				return nodes.Pos{Line: line}
+			}
+		}
+	}
+	return nodes.Pos{}
+}
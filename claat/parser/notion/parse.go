@@ -0,0 +1,532 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notion implements a parser of Notion pages.
+//
+// The input is a JSON object with a "page" field, holding the page object,
+// and a "blocks" field, holding the page blocks with their nested "children",
+// as assembled by the fetch package from the Notion API responses.
+//
+// Each Heading 1 block starts a new step. Page properties named after
+// codelab metadata, e.g. Summary or Categories, populate the metadata.
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/parser"
+	"github.com/googlecodelabs/tools/claat/types"
+	"github.com/googlecodelabs/tools/claat/util"
+	"github.com/stoewer/go-strcase"
+)
+
+var (
+	// durationRegexp matches a step duration paragraph, e.g. "Duration: 5:00".
+	durationRegexp = regexp.MustCompile(`(?i)^\s*duration\s*:\s*([0-9:]+)\s*$`)
+
+	// durFactor is a slice of duration parser multipliers,
+	// ordered after the usage in codelab docs
+	durFactor = []time.Duration{time.Hour, time.Minute, time.Second}
+
+	// negativeEmoji are callout icons making an infobox negative.
+	negativeEmoji = map[string]bool{"⚠️": true, "⚠": true, "❗": true, "🚫": true, "⛔": true, "❌": true, "🔥": true}
+)
+
+// init registers this parser so it is available to CLaaT.
+func init() {
+	parser.Register("notion", &Parser{})
+}
+
+// Parser is a Notion page parser.
+type Parser struct {
+}
+
+// Parse parses a Notion page into a codelab.
+func (p *Parser) Parse(ctx context.Context, r io.Reader, opts parser.Options) (*types.Codelab, error) {
+	doc, err := decode(r)
+	if err != nil {
+		return nil, err
+	}
+	clab := types.NewCodelab()
+	pageMeta(clab, doc.Page, opts)
+	var totdur time.Duration
+	var step *types.Step
+	var content []*block
+	flush := func() {
+		if step == nil {
+			return
+		}
+		if d, ok := stepDuration(content); ok {
+			step.Duration = d
+			totdur += d
+			content = content[1:]
+		}
+		step.Content.Append(blocks(content)...)
+	}
+	for _, b := range doc.Blocks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if b.Type != "heading_1" {
+			content = append(content, b)
+			continue
+		}
+		flush()
+		step = clab.NewStep(strings.TrimSpace(plainText(b.Data.RichText)))
+		// toggleable headings keep step content as children
+		content = b.Children
+	}
+	flush()
+	for _, st := range clab.Steps {
+		st.Content.Nodes = parser.DropIframes(st.Content.Nodes, opts.IframeDomains)
+	}
+
+	if clab.ID == "" {
+		clab.ID = slug(clab.Title)
+	}
+	clab.Tags = util.Unique(clab.Tags)
+	sort.Strings(clab.Tags)
+	if clab.Duration == 0 {
+		clab.Duration = int(totdur.Minutes())
+	}
+	return clab, nil
+}
+
+// ParseFragment parses Notion page blocks without codelab metadata and steps.
+func (p *Parser) ParseFragment(ctx context.Context, r io.Reader, opts parser.Options) ([]nodes.Node, error) {
+	doc, err := decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return parser.DropIframes(blocks(doc.Blocks), opts.IframeDomains), nil
+}
+
+func decode(r io.Reader) (*document, error) {
+	doc := &document{}
+	if err := json.NewDecoder(r).Decode(doc); err != nil {
+		return nil, err
+	}
+	if doc.Page == nil {
+		return nil, fmt.Errorf("document without a page")
+	}
+	return doc, nil
+}
+
+// document is a Notion page with its content blocks.
+type document struct {
+	Page   *page    `json:"page"`
+	Blocks []*block `json:"blocks"`
+}
+
+// page is a subset of the Notion page object.
+type page struct {
+	ID         string               `json:"id"`
+	Properties map[string]*property `json:"properties"`
+}
+
+// property is a Notion page property value.
+type property struct {
+	Type        string      `json:"type"`
+	Title       []*richText `json:"title"`
+	RichText    []*richText `json:"rich_text"`
+	Select      *option     `json:"select"`
+	MultiSelect []*option   `json:"multi_select"`
+	URL         string      `json:"url"`
+	Number      *float64    `json:"number"`
+}
+
+type option struct {
+	Name string `json:"name"`
+}
+
+// text returns the property value as a string.
+// Multi select options are separated by commas.
+func (p *property) text() string {
+	switch p.Type {
+	case "title":
+		return plainText(p.Title)
+	case "rich_text":
+		return plainText(p.RichText)
+	case "select":
+		if p.Select != nil {
+			return p.Select.Name
+		}
+	case "multi_select":
+		var v []string
+		for _, o := range p.MultiSelect {
+			v = append(v, o.Name)
+		}
+		return strings.Join(v, ",")
+	case "url":
+		return p.URL
+	case "number":
+		if p.Number != nil {
+			return strconv.FormatFloat(*p.Number, 'f', -1, 64)
+		}
+	}
+	return ""
+}
+
+// block is a Notion block object.
+// Data is the type specific part of the block, e.g. the "paragraph" field
+// of a paragraph block.
+type block struct {
+	ID       string
+	Type     string
+	Children []*block
+	Data     blockData
+}
+
+// blockData is a union of the type specific block fields used by the parser.
+type blockData struct {
+	RichText []*richText `json:"rich_text"`
+	Color    string      `json:"color"`
+	Checked  bool        `json:"checked"`
+	Language string      `json:"language"`
+	Icon     *struct {
+		Emoji string `json:"emoji"`
+	} `json:"icon"`
+	// file objects, e.g. image and video
+	Type     string `json:"type"`
+	External *struct {
+		URL string `json:"url"`
+	} `json:"external"`
+	File *struct {
+		URL string `json:"url"`
+	} `json:"file"`
+	Caption []*richText `json:"caption"`
+	// embed and bookmark
+	URL string `json:"url"`
+	// table_row
+	Cells [][]*richText `json:"cells"`
+}
+
+// fileURL returns URL of a file object block.
+func (d *blockData) fileURL() string {
+	switch {
+	case d.External != nil:
+		return d.External.URL
+	case d.File != nil:
+		return d.File.URL
+	}
+	return d.URL
+}
+
+func (b *block) UnmarshalJSON(data []byte) error {
+	var v struct {
+		ID       string   `json:"id"`
+		Type     string   `json:"type"`
+		Children []*block `json:"children"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	b.ID, b.Type, b.Children = v.ID, v.Type, v.Children
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if d, ok := raw[v.Type]; ok {
+		return json.Unmarshal(d, &b.Data)
+	}
+	return nil
+}
+
+// richText is a Notion rich text object.
+type richText struct {
+	PlainText   string `json:"plain_text"`
+	Href        string `json:"href"`
+	Annotations struct {
+		Bold   bool `json:"bold"`
+		Italic bool `json:"italic"`
+		Code   bool `json:"code"`
+	} `json:"annotations"`
+}
+
+// pageMeta populates codelab metadata from page properties.
+func pageMeta(clab *types.Codelab, p *page, opts parser.Options) {
+	for name, prop := range p.Properties {
+		v := strings.TrimSpace(prop.text())
+		if prop.Type == "title" {
+			clab.Title = v
+			continue
+		}
+		if v == "" {
+			continue
+		}
+		switch key := strcase.SnakeCase(name); key {
+		case "id", "url":
+			clab.ID = v
+		case "author", "authors":
+			clab.Authors = v
+		case "summary":
+			clab.Summary = v
+		case "category", "categories":
+			clab.Categories = util.NormalizedSplit(v)
+		case "environment", "environments", "tags":
+			clab.Tags = append(clab.Tags, util.NormalizedSplit(v)...)
+		case "status":
+			sv := types.LegacyStatus(util.NormalizedSplit(v))
+			clab.Status = &sv
+		case "feedback", "feedback_link":
+			clab.Feedback = v
+		case "analytics_account":
+			clab.GA = v
+		case "analytics_ga4_account":
+			clab.GA4 = v
+		case "duration":
+			if d, err := strconv.Atoi(v); err == nil {
+				clab.Duration = d
+				clab.DurationDeclared = d > 0
+			}
+		default:
+			if opts.PassMetadata[key] {
+				clab.Extra[key] = v
+			}
+		}
+	}
+	if len(clab.Categories) > 0 {
+		clab.Theme = slug(clab.Categories[0])
+	}
+}
+
+// stepDuration parses the duration of a step from its first block,
+// a paragraph of the form "Duration: mm:ss".
+func stepDuration(bb []*block) (time.Duration, bool) {
+	if len(bb) == 0 || bb[0].Type != "paragraph" {
+		return 0, false
+	}
+	m := durationRegexp.FindStringSubmatch(plainText(bb[0].Data.RichText))
+	if m == nil {
+		return 0, false
+	}
+	parts := strings.SplitN(m[1], ":", len(durFactor))
+	if len(parts) == 1 {
+		parts = append(parts, "0") // default number is minutes
+	}
+	var d time.Duration
+	for i, v := range parts {
+		if vi, err := strconv.Atoi(v); err == nil {
+			d += time.Duration(vi) * durFactor[len(durFactor)-len(parts)+i]
+		}
+	}
+	return d.Round(time.Minute), true
+}
+
+// blocks converts Notion blocks into nodes.
+// Consecutive list items of the same kind are grouped into a single list.
+func blocks(bb []*block) []nodes.Node {
+	var nn []nodes.Node
+	var list *nodes.ItemsListNode
+	var listType string
+	for _, b := range bb {
+		switch b.Type {
+		case "bulleted_list_item", "numbered_list_item", "to_do", "toggle":
+			if list == nil || listType != b.Type {
+				list = newList(b.Type)
+				listType = b.Type
+				nn = append(nn, list)
+			}
+			item := inline(b.Data.RichText)
+			if b.Type == "toggle" && len(item) > 0 {
+				// the toggle summary is the FAQ question
+				item = []nodes.Node{paragraph(item...)}
+			}
+			list.NewItem(append(item, blocks(b.Children)...)...)
+			continue
+		}
+		list = nil
+		if n := convert(b); n != nil && !n.Empty() {
+			nn = append(nn, n)
+		}
+	}
+	return nn
+}
+
+// newList creates an items list for a list item block type.
+// Toggles are rendered as a FAQ list, which is collapsible.
+func newList(typ string) *nodes.ItemsListNode {
+	switch typ {
+	case "numbered_list_item":
+		return nodes.NewItemsListNode("1", 0)
+	case "to_do":
+		l := nodes.NewItemsListNode("", 0)
+		l.MutateType(nodes.NodeItemsCheck)
+		return l
+	case "toggle":
+		l := nodes.NewItemsListNode("", 0)
+		l.MutateType(nodes.NodeItemsFAQ)
+		return l
+	}
+	return nodes.NewItemsListNode("", 0)
+}
+
+// convert converts a non list item block into a node.
+// It returns nil for unsupported blocks.
+func convert(b *block) nodes.Node {
+	d := &b.Data
+	switch b.Type {
+	case "paragraph", "quote":
+		nn := append(inline(d.RichText), blocks(b.Children)...)
+		if len(nn) == 0 {
+			return nil
+		}
+		return paragraph(nn...)
+	case "heading_2", "heading_3":
+		level, _ := strconv.Atoi(b.Type[len("heading_"):])
+		n := nodes.NewHeaderNode(level, inline(d.RichText)...)
+		switch strings.ToLower(strings.TrimSpace(plainText(d.RichText))) {
+		case "what you'll learn", "what we've covered":
+			n.MutateType(nodes.NodeHeaderCheck)
+		case "frequently asked questions":
+			n.MutateType(nodes.NodeHeaderFAQ)
+		}
+		return n
+	case "callout":
+		kind := nodes.InfoboxPositive
+		if strings.HasPrefix(d.Color, "red") || strings.HasPrefix(d.Color, "orange") ||
+			(d.Icon != nil && negativeEmoji[d.Icon.Emoji]) {
+			kind = nodes.InfoboxNegative
+		}
+		var nn []nodes.Node
+		if t := inline(d.RichText); len(t) > 0 {
+			nn = append(nn, paragraph(t...))
+		}
+		return nodes.NewInfoboxNode(kind, append(nn, blocks(b.Children)...)...)
+	case "code":
+		lang := d.Language
+		if lang == "plain text" {
+			lang = ""
+		}
+		return nodes.NewCodeNode(plainText(d.RichText), false, lang)
+	case "image":
+		n := nodes.NewImageNode(nodes.NewImageNodeOptions{
+			Src: d.fileURL(),
+			Alt: plainText(d.Caption),
+		})
+		if n.Src == "" {
+			return nil
+		}
+		return paragraph(n)
+	case "video":
+		if yt := nodes.ParseYouTubeURL(d.fileURL()); yt != nil {
+			return yt
+		}
+	case "embed":
+		u, err := url.Parse(d.URL)
+		if err != nil || u.Scheme != "https" {
+			return nil
+		}
+		if yt := nodes.ParseYouTubeURL(d.URL); yt != nil {
+			return yt
+		}
+		// the domain is checked once parsed, see parser.DropIframes
+		return nodes.NewIframeNode(u.String())
+	case "bookmark":
+		if d.URL == "" {
+			return nil
+		}
+		t := plainText(d.Caption)
+		if t == "" {
+			t = d.URL
+		}
+		return paragraph(nodes.NewURLNode(d.URL, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: t})))
+	case "table":
+		var rows [][]*nodes.GridCell
+		for _, r := range b.Children {
+			if r.Type != "table_row" {
+				continue
+			}
+			var row []*nodes.GridCell
+			for _, c := range r.Data.Cells {
+				row = append(row, &nodes.GridCell{
+					Colspan: 1,
+					Rowspan: 1,
+					Content: nodes.NewListNode(inline(c)...),
+				})
+			}
+			rows = append(rows, row)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return nodes.NewGridNode(rows...)
+	}
+	return nil
+}
+
+// paragraph wraps nn in a block list node.
+func paragraph(nn ...nodes.Node) nodes.Node {
+	l := nodes.NewListNode(nn...)
+	l.MutateBlock(true)
+	return l
+}
+
+// inline converts rich text into text and link nodes.
+func inline(rt []*richText) []nodes.Node {
+	var nn []nodes.Node
+	for _, t := range rt {
+		if t.PlainText == "" {
+			continue
+		}
+		var n nodes.Node = nodes.NewTextNode(nodes.NewTextNodeOptions{
+			Value:  t.PlainText,
+			Bold:   t.Annotations.Bold,
+			Italic: t.Annotations.Italic,
+			Code:   t.Annotations.Code,
+		})
+		if t.Href != "" {
+			n = nodes.NewURLNode(t.Href, n)
+		}
+		nn = append(nn, n)
+	}
+	return parser.CompactNodes(nn)
+}
+
+// plainText concatenates plain text of rt.
+func plainText(rt []*richText) string {
+	var b strings.Builder
+	for _, t := range rt {
+		b.WriteString(t.PlainText)
+	}
+	return b.String()
+}
+
+// slug converts s into a codelab ID.
+func slug(s string) string {
+	var b strings.Builder
+	dash := true
+	for _, r := range strings.ToLower(s) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
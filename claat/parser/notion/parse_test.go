@@ -0,0 +1,107 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notion
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/parser"
+	"github.com/googlecodelabs/tools/claat/render"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// pageJSON is a Notion page as assembled by the fetch package.
+const pageJSON = `{
+  "page": {"id": "p1", "properties": {
+    "Name": {"type": "title", "title": [{"plain_text": "Notion Codelab"}]},
+    "Categories": {"type": "multi_select", "multi_select": [{"name": "Web"}, {"name": "Cloud"}]},
+    "Feedback Link": {"type": "url", "url": "https://example.com/bugs"},
+    "Status": {"type": "select", "select": {"name": "Draft"}}
+  }},
+  "blocks": [
+    {"type": "heading_1", "heading_1": {"rich_text": [{"plain_text": "Overview"}]}},
+    {"type": "paragraph", "paragraph": {"rich_text": [{"plain_text": "Duration: 2:30"}]}},
+    {"type": "paragraph", "paragraph": {"rich_text": [
+      {"plain_text": "Hello "},
+      {"plain_text": "bold", "annotations": {"bold": true}},
+      {"plain_text": " and "},
+      {"plain_text": "link", "href": "https://example.com"}
+    ]}},
+    {"type": "bulleted_list_item", "bulleted_list_item": {"rich_text": [{"plain_text": "One"}]}},
+    {"type": "bulleted_list_item", "bulleted_list_item": {"rich_text": [{"plain_text": "Two"}]}},
+    {"type": "callout", "callout": {"rich_text": [{"plain_text": "Careful"}], "icon": {"emoji": "⚠️"}, "color": "default"}},
+    {"type": "heading_1", "heading_1": {"rich_text": [{"plain_text": "Setup"}]}},
+    {"type": "numbered_list_item", "numbered_list_item": {"rich_text": [{"plain_text": "First"}]}},
+    {"type": "code", "code": {"language": "go", "rich_text": [{"plain_text": "func main() {\n}"}]}},
+    {"type": "toggle", "toggle": {"rich_text": [{"plain_text": "Why?"}]},
+      "children": [{"type": "paragraph", "paragraph": {"rich_text": [{"plain_text": "Because."}]}}]},
+    {"type": "callout", "callout": {"rich_text": [{"plain_text": "Tip"}], "color": "green_background"}}
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	clab, err := (&Parser{}).Parse(context.Background(), strings.NewReader(pageJSON), *parser.NewOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clab.Title != "Notion Codelab" || clab.ID != "notion-codelab" {
+		t.Errorf("Title, ID = %q, %q; want %q, %q", clab.Title, clab.ID, "Notion Codelab", "notion-codelab")
+	}
+	if diff := cmp.Diff([]string{"web", "cloud"}, clab.Categories); diff != "" {
+		t.Errorf("Categories got diff (-want +got): %s", diff)
+	}
+	if clab.Feedback != "https://example.com/bugs" {
+		t.Errorf("Feedback = %q", clab.Feedback)
+	}
+	if clab.Status == nil || cmp.Diff(types.LegacyStatus{"draft"}, *clab.Status) != "" {
+		t.Errorf("Status = %v; want [draft]", clab.Status)
+	}
+	if len(clab.Steps) != 2 {
+		t.Fatalf("len(Steps) = %d, want 2", len(clab.Steps))
+	}
+	if d := clab.Steps[0].Duration; d != 3*time.Minute {
+		t.Errorf("Steps[0].Duration = %v, want 3m", d)
+	}
+
+	want := []string{
+		"<p>Hello <strong>bold</strong> and <a href=\"https://example.com\" target=\"_blank\">link</a></p>\n" +
+			"<ul>\n<li>One</li>\n<li>Two</li>\n</ul>\n" +
+			"<aside class=\"warning\"><p>Careful</p>\n</aside>\n",
+		"<ol type=\"1\">\n<li>First</li>\n</ol>\n" +
+			"<pre><code language=\"go\" class=\"go\">func main() {\n}</code></pre>\n" +
+			"<ul class=\"faq\">\n<li><p>Why?</p>\n<p>Because.</p>\n</li>\n</ul>\n" +
+			"<aside class=\"special\"><p>Tip</p>\n</aside>\n",
+	}
+	for i, st := range clab.Steps {
+		var buf bytes.Buffer
+		if err := render.WriteHTML(&buf, "", "html", st.Content.Nodes...); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want[i], buf.String()); diff != "" {
+			t.Errorf("Steps[%d] content got diff (-want +got): %s", i, diff)
+		}
+	}
+}
+
+func TestParseNoPage(t *testing.T) {
+	if _, err := (&Parser{}).Parse(context.Background(), strings.NewReader(`{"blocks": []}`), *parser.NewOptions()); err == nil {
+		t.Errorf("Parse() of a document without page returned nil error")
+	}
+}
@@ -15,26 +15,38 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"net/url"
 
 	"github.com/googlecodelabs/tools/claat/nodes"
 	"github.com/googlecodelabs/tools/claat/types"
+	"github.com/googlecodelabs/tools/claat/util"
 )
 
 // Parser parses a codelab in specific resource format.
 // Each parser needs to call Register to become a known parser.
+//
+// Parsers check ctx as they go, so that a canceled parse stops early
+// with the error of ctx.
 type Parser interface {
 	// Parse parses source r into a Codelab for the specified environment env.
-	Parse(r io.Reader, opts Options) (*types.Codelab, error)
+	Parse(ctx context.Context, r io.Reader, opts Options) (*types.Codelab, error)
 
 	// ParseFragment is similar to Parse except it doesn't parse codelab metadata.
-	ParseFragment(r io.Reader, opts Options) ([]nodes.Node, error)
+	ParseFragment(ctx context.Context, r io.Reader, opts Options) ([]nodes.Node, error)
 }
 
 // Container for parsing options.
 type Options struct {
-	PassMetadata map[string]bool
+	// IframeDomains are domains allowed to be embedded in iframes
+	// in addition to nodes.IframeAllowlist.
+	IframeDomains []string
+	PassMetadata  map[string]bool
+	// StrictMeta makes Parse fail if codelab metadata is invalid.
+	// See types.Meta.Validate.
+	StrictMeta bool
 }
 
 func NewOptions() *Options {
@@ -64,26 +76,74 @@ func Parsers() []string {
 }
 
 // Parse parses source r into a Codelab using a parser registered with
-// the specified name.
-func Parse(name string, r io.Reader, opts Options) (*types.Codelab, error) {
+// the specified name. It stops with the error of ctx once done.
+func Parse(ctx context.Context, name string, r io.Reader, opts Options) (*types.Codelab, error) {
 	p, ok := parsers[name]
 	if !ok {
 		return nil, fmt.Errorf("no parser named %q", name)
 	}
-	c, err := p.Parse(r, opts)
+	c, err := p.Parse(ctx, util.ContextReader(ctx, r), opts)
 	if err != nil {
 		return nil, err
 	}
+	// parsers may have read r before cancellation
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	c.URL = c.ID
+	for _, st := range c.Steps {
+		if st.Content != nil {
+			parseDarkImages(st.Content.Nodes)
+		}
+	}
+	if opts.StrictMeta {
+		if err := c.Meta.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	return c, err
 }
 
+// DropIframes removes the iframes of nn, at any depth, which
+// nodes.IframeAllowed does not allow with domains. It is used by parsers
+// which check embedded iframes once their content is parsed.
+func DropIframes(nn []nodes.Node, domains []string) []nodes.Node {
+	nn, _ = nodes.WalkNodes(nn, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		in, ok := n.(*nodes.IframeNode)
+		if !ok || !entering {
+			return n, nil
+		}
+		if u, err := url.Parse(in.URL); err != nil || !nodes.IframeAllowed(u, domains) {
+			return nil, nil
+		}
+		return n, nil
+	})
+	return nn
+}
+
 // ParseFragment parses a codelab fragment provided in r, using a parser
-// registered with the specified name.
-func ParseFragment(name string, r io.Reader, opts Options) ([]nodes.Node, error) {
+// registered with the specified name. It stops with the error of ctx
+// once done.
+func ParseFragment(ctx context.Context, name string, r io.Reader, opts Options) ([]nodes.Node, error) {
 	p, ok := parsers[name]
 	if !ok {
 		return nil, fmt.Errorf("no parser named %q", name)
 	}
-	return p.ParseFragment(r, opts)
+	nn, err := p.ParseFragment(ctx, util.ContextReader(ctx, r), opts)
+	if err != nil {
+		return nil, err
+	}
+	parseDarkImages(nn)
+	return nn, nil
+}
+
+// parseDarkImages sets the dark variants of the images of nn
+// written in their alt text, as parsed by nodes.ParseDark,
+// so that they are fetched along with the images.
+func parseDarkImages(nn []nodes.Node) {
+	for _, img := range nodes.ImageNodes(nn) {
+		if img.Dark == "" {
+			img.Alt, img.Dark = nodes.ParseDark(img.Alt)
+		}
+	}
 }
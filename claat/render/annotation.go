@@ -0,0 +1,145 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// annotationColor is the color of image annotations.
+const annotationColor = "#ea4335"
+
+// Styles of annotated images and their annotations, inline so that
+// they do not depend on the stylesheet of the page.
+const (
+	annotatedStyle = "position: relative; display: inline-block"
+	overlayStyle   = "position: absolute; left: 0; top: 0; width: 100%; height: 100%; pointer-events: none"
+	boxStyle       = "position: absolute; left: %s%%; top: %s%%; width: %s%%; height: %s%%; box-sizing: border-box; border: 3px solid " + annotationColor + "; border-radius: 4px"
+	boxLabelStyle  = "position: absolute; left: -3px; top: 100%; padding: 2px 6px; background: " + annotationColor + "; color: #fff; font-size: 12px; white-space: nowrap"
+	markerStyle    = "position: absolute; left: %s%%; top: %s%%; transform: translate(-50%%, -50%%); min-width: 24px; height: 24px; border-radius: 12px; background: " + annotationColor + "; color: #fff; font: bold 14px/24px sans-serif; text-align: center"
+)
+
+// arrowHead is the length of the sides of arrow heads, and arrowAngle
+// their angle with the arrow, in the 100x100 coordinates of the overlay.
+const (
+	arrowHead  = 4
+	arrowAngle = math.Pi / 7
+)
+
+// annotated returns a span holding img, an image node, and the overlay
+// of its annotations aa.
+func annotated(img *html.Node, aa []nodes.Annotation) *html.Node {
+	top := &html.Node{
+		Type: html.ElementNode,
+		Data: atom.Span.String(),
+		Attr: []html.Attribute{
+			{Key: "class", Val: "image-annotated"},
+			{Key: "style", Val: annotatedStyle},
+		},
+	}
+	top.AppendChild(img)
+	for _, n := range annotationOverlay(aa) {
+		top.AppendChild(n)
+	}
+	return top
+}
+
+// annotationOverlay returns the elements drawing annotations aa over their
+// image: an svg of the arrows, if any, followed by boxes and markers.
+func annotationOverlay(aa []nodes.Annotation) []*html.Node {
+	var res []*html.Node
+	var svg *html.Node
+	for _, a := range aa {
+		switch a.Kind {
+		case nodes.AnnotationArrow:
+			if svg == nil {
+				svg = &html.Node{
+					Type: html.ElementNode,
+					Data: "svg",
+					Attr: []html.Attribute{
+						{Key: "class", Val: "image-annotation image-annotation--arrows"},
+						{Key: "viewBox", Val: "0 0 100 100"},
+						{Key: "preserveAspectRatio", Val: "none"},
+						{Key: "style", Val: overlayStyle},
+					},
+				}
+				res = append([]*html.Node{svg}, res...)
+			}
+			angle := math.Atan2(a.Y2-a.Y, a.X2-a.X)
+			svg.AppendChild(svgLine(a.X, a.Y, a.X2, a.Y2))
+			for _, d := range []float64{-arrowAngle, arrowAngle} {
+				x := a.X2 - arrowHead*math.Cos(angle+d)
+				y := a.Y2 - arrowHead*math.Sin(angle+d)
+				svg.AppendChild(svgLine(x, y, a.X2, a.Y2))
+			}
+		case nodes.AnnotationBox:
+			x, x2 := math.Min(a.X, a.X2), math.Max(a.X, a.X2)
+			y, y2 := math.Min(a.Y, a.Y2), math.Max(a.Y, a.Y2)
+			box := annotationSpan("box", fmt.Sprintf(boxStyle, pct(x), pct(y), pct(x2-x), pct(y2-y)))
+			if a.Label != "" {
+				label := annotationSpan("label", boxLabelStyle)
+				label.AppendChild(&html.Node{Type: html.TextNode, Data: a.Label})
+				box.AppendChild(label)
+			}
+			res = append(res, box)
+		case nodes.AnnotationMarker:
+			marker := annotationSpan("marker", fmt.Sprintf(markerStyle, pct(a.X), pct(a.Y)))
+			marker.AppendChild(&html.Node{Type: html.TextNode, Data: a.Label})
+			res = append(res, marker)
+		}
+	}
+	return res
+}
+
+// annotationSpan returns a span of an annotation of kind, with style.
+func annotationSpan(kind, style string) *html.Node {
+	return &html.Node{
+		Type: html.ElementNode,
+		Data: atom.Span.String(),
+		Attr: []html.Attribute{
+			{Key: "class", Val: "image-annotation image-annotation--" + kind},
+			{Key: "style", Val: style},
+		},
+	}
+}
+
+// svgLine returns an svg line from x, y to x2, y2.
+func svgLine(x, y, x2, y2 float64) *html.Node {
+	return &html.Node{
+		Type: html.ElementNode,
+		Data: "line",
+		Attr: []html.Attribute{
+			{Key: "x1", Val: pct(x)},
+			{Key: "y1", Val: pct(y)},
+			{Key: "x2", Val: pct(x2)},
+			{Key: "y2", Val: pct(y2)},
+			{Key: "stroke", Val: annotationColor},
+			{Key: "stroke-width", Val: "3"},
+			{Key: "stroke-linecap", Val: "round"},
+			{Key: "vector-effect", Val: "non-scaling-stroke"},
+		},
+	}
+}
+
+// pct formats percentage v with at most two decimals.
+func pct(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
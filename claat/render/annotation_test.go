@@ -0,0 +1,74 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+func annotatedImage() *nodes.ImageNode {
+	img := nodes.NewImageNode(nodes.NewImageNodeOptions{Src: "console.png", Alt: "Console"})
+	img.Annotations = []nodes.Annotation{
+		{Kind: nodes.AnnotationArrow, X: 50, Y: 50, X2: 30, Y2: 50},
+		{Kind: nodes.AnnotationBox, X: 40, Y: 20, X2: 10, Y2: 30, Label: "Menu"},
+		{Kind: nodes.AnnotationMarker, X: 80, Y: 10, Label: "1"},
+	}
+	return img
+}
+
+func TestHTMLAnnotations(t *testing.T) {
+	img := annotatedImage()
+	h, err := HTML(Context{}, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := Lite(Context{}, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, out := range []string{string(h), string(l)} {
+		for _, want := range []string{
+			`<span class="image-annotated" style="position: relative; display: inline-block">`,
+			`<svg class="image-annotation image-annotation--arrows" viewBox="0 0 100 100" preserveAspectRatio="none"`,
+			`<line x1="50" y1="50" x2="30" y2="50" stroke="#ea4335"`,
+			`<span class="image-annotation image-annotation--box" style="position: absolute; left: 10%; top: 20%; width: 30%; height: 10%;`,
+			`>Menu</span></span>`,
+			`<span class="image-annotation image-annotation--marker" style="position: absolute; left: 80%; top: 10%;`,
+			`>1</span></span>`,
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("output does not contain %s:\n%s", want, out)
+			}
+		}
+		if n := strings.Count(out, "<line "); n != 3 {
+			t.Errorf("output has %d lines; want an arrow of 3:\n%s", n, out)
+		}
+	}
+}
+
+func TestMDAnnotations(t *testing.T) {
+	out, err := MD(Context{}, annotatedImage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ` <img src="console.png" alt="Console {arrow 50 50 30 50} {box 40 20 10 30 Menu} {marker 80 10 1}" />`
+	if diff := cmp.Diff(want, out); diff != "" {
+		t.Errorf("MD got diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/parser"
+	mdParse "github.com/googlecodelabs/tools/claat/parser/md"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// largeCodelab parses a representative codelab of n copies
+// of the steps of testdata/roundtrip.md, which has every kind of node.
+func largeCodelab(b *testing.B, n int) *types.Codelab {
+	b.Helper()
+	src, err := ioutil.ReadFile("testdata/roundtrip.md")
+	if err != nil {
+		b.Fatal(err)
+	}
+	i := bytes.Index(src, []byte("\n## "))
+	var buf bytes.Buffer
+	buf.Write(src[:i])
+	for k := 0; k < n; k++ {
+		buf.WriteString(strings.Replace(string(src[i:]), "\n## ", fmt.Sprintf("\n## %d. ", k), -1))
+	}
+	clab, err := (&mdParse.Parser{}).Parse(context.Background(), &buf, *parser.NewOptions())
+	if err != nil {
+		b.Fatal(err)
+	}
+	return clab
+}
+
+func BenchmarkExecute(b *testing.B) {
+	clab := largeCodelab(b, 50)
+	for _, format := range []string{"html", "md", "offline"} {
+		b.Run(format, func(b *testing.B) {
+			data := &struct {
+				Context
+				Current *types.Step
+				StepNum int
+				Prev    *types.Step
+				Next    *types.Step
+			}{Context: Context{Env: "web", Format: format, Meta: &clab.Meta, Steps: clab.Steps}.WithAnchors(), Current: clab.Steps[0], StepNum: 1}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := Execute(ioutil.Discard, format, data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
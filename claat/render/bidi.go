@@ -0,0 +1,69 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// rlm is the invisible RIGHT-TO-LEFT MARK.
+const rlm = "\u200f"
+
+// rtlScripts are the scripts of languages written from right to left.
+var rtlScripts = []*unicode.RangeTable{
+	unicode.Arabic,
+	unicode.Hebrew,
+	unicode.Nko,
+	unicode.Syriac,
+	unicode.Thaana,
+}
+
+// isRTL reports whether the codelab is in a language written from right to left.
+func (ctx Context) isRTL() bool {
+	return ctx.Meta != nil && types.IsRTL(ctx.Meta.Locale)
+}
+
+// needsRLM reports whether block text s of a codelab written from right
+// to left should start with a right-to-left mark. Markdown renderers,
+// such as GitHub, set the direction of each block from its first letter:
+// a paragraph starting with a command or a product name would be laid out
+// from left to right, and one without letters defaults to left to right.
+func needsRLM(s string) bool {
+	if strings.TrimSpace(s) == "" {
+		return false
+	}
+	for _, r := range s {
+		if string(r) == rlm || unicode.In(r, rtlScripts...) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return true
+}
+
+// bidi returns text s of a block, such as a step title, prefixed with
+// a right-to-left mark if ctx is a codelab written from right to left
+// and s does not start with a right-to-left letter.
+func bidi(ctx Context, s string) string {
+	if ctx.isRTL() && needsRLM(s) {
+		return rlm + s
+	}
+	return s
+}
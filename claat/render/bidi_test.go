@@ -0,0 +1,140 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestNeedsRLM(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"", false},
+		{"  ", false},
+		{"مرحبا", false},
+		{"1. מה", false},
+		{rlm + "gcloud", false},
+		{"gcloud هو", true},
+		{"42", true},
+	}
+	for _, tc := range tests {
+		if got := needsRLM(tc.in); got != tc.want {
+			t.Errorf("needsRLM(%q) = %v; want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestRTL(t *testing.T) {
+	text := func(s string) *nodes.TextNode {
+		return nodes.NewTextNode(nodes.NewTextNodeOptions{Value: s})
+	}
+	code := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "ls", Code: true})
+	para := nodes.NewListNode(text("شغّل "), code)
+	block := nodes.NewCodeNode("ls -l", false, "")
+	cmd := nodes.NewListNode(text("gcloud يعمل"))
+	para.MutateBlock(true)
+	cmd.MutateBlock(true)
+
+	tests := []struct {
+		name   string
+		locale string
+		render func(Context, ...nodes.Node) (string, error)
+		out    string
+	}{
+		{
+			name:   "HTML",
+			locale: "ar",
+			render: func(ctx Context, nn ...nodes.Node) (string, error) {
+				s, err := HTML(ctx, nn...)
+				return string(s), err
+			},
+			out: "<p>شغّل <code dir=\"ltr\">ls</code></p>\n<pre dir=\"ltr\"><code>ls -l</code></pre>\n<p>gcloud يعمل</p>\n",
+		},
+		{
+			name:   "HTML LTR",
+			locale: "en",
+			render: func(ctx Context, nn ...nodes.Node) (string, error) {
+				s, err := HTML(ctx, nn...)
+				return string(s), err
+			},
+			out: "<p>شغّل <code>ls</code></p>\n<pre><code>ls -l</code></pre>\n<p>gcloud يعمل</p>\n",
+		},
+		{
+			name:   "Lite",
+			locale: "fa",
+			render: func(ctx Context, nn ...nodes.Node) (string, error) {
+				s, err := Lite(ctx, nn...)
+				return string(s), err
+			},
+			out: "<p>شغّل <code dir=\"ltr\">ls</code></p><pre dir=\"ltr\"><code>ls -l</code></pre><p>gcloud يعمل</p>",
+		},
+		{
+			name:   "MD",
+			locale: "he",
+			render: MD,
+			out:    "\n\nشغّل `ls`\n\n```\nls -l\n```\n\n" + rlm + "gcloud يعمل\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := Context{Env: "web", Meta: &types.Meta{Locale: tc.locale}}
+			out, err := tc.render(ctx, para, block, cmd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.out, out); diff != "" {
+				t.Errorf("render got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRTLTemplates(t *testing.T) {
+	steps := []*types.Step{{Title: "Cloud Shell", Content: nodes.NewListNode()}}
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"html", []string{`<html lang="ar" dir="rtl">`, `<body class="rtl">`}},
+		{"offline", []string{`<html lang="ar" dir="rtl">`, `class="codelab-takeover rtl"`}},
+		{"md", []string{"# " + rlm + "Codelab\n", "## " + rlm + "Cloud Shell\n"}},
+	}
+	for _, tc := range tests {
+		var buf bytes.Buffer
+		data := &struct {
+			Context
+			Current *types.Step
+			StepNum int
+			Prev    bool
+			Next    bool
+		}{Context: Context{Env: "web", Format: tc.format, Meta: &types.Meta{Title: "Codelab", Locale: "ar"}, Steps: steps}, Current: steps[0], StepNum: 1}
+		if err := Execute(&buf, tc.format, data); err != nil {
+			t.Fatalf("%s: Execute: %v", tc.format, err)
+		}
+		for _, s := range tc.want {
+			if !strings.Contains(buf.String(), s) {
+				t.Errorf("%s: output does not contain %q:\n%s", tc.format, s, buf.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/types"
+	"github.com/googlecodelabs/tools/claat/util"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Cloud Shell modes of HTML output: terminal code blocks are followed
+// by a link opening Cloud Shell, or by an embedded Cloud Shell terminal.
+const (
+	CloudShellEmbed = "embed"
+	CloudShellLink  = "link"
+)
+
+// CloudShellModes are the Cloud Shell modes of HTML output.
+var CloudShellModes = []string{CloudShellEmbed, CloudShellLink}
+
+// Metadata fields, passed along with pass_metadata, setting the repository,
+// branch, tutorial file and workspace directory Cloud Shell opens.
+// The repository and branch default to those of git sources, and the tutorial
+// to the tutorial output of the codelab exported next to its source.
+const (
+	MetaCloudShellRepo      = "cloudshell_git_repo"
+	MetaCloudShellBranch    = "cloudshell_git_branch"
+	MetaCloudShellTutorial  = "cloudshell_tutorial"
+	MetaCloudShellWorkspace = "cloudshell_workspace"
+)
+
+// cloudShellOpen is the URL of Cloud Shell deep links,
+// and cloudShellLabel the text of links opening it.
+const (
+	cloudShellOpen  = "https://shell.cloud.google.com/cloudshell/open"
+	cloudShellLabel = "Open in Cloud Shell"
+)
+
+// cloudShellClass is the class of Cloud Shell links,
+// and cloudShellFrame the class of embedded Cloud Shell terminals.
+const (
+	cloudShellClass = "cloudshell-link"
+	cloudShellFrame = "cloudshell-terminal"
+)
+
+// CloudShellURL returns the deep link opening Cloud Shell with the repository
+// and tutorial file of codelab meta, as set by the MetaCloudShell* metadata
+// fields or, for git sources such as github.com/owner/repo//labs/codelab.md@main,
+// by the source: the tutorial is then labs/<id>/index.tutorial.md, as written
+// by "-f tutorial" to the directory of the source. Without a repository,
+// Cloud Shell opens on its home directory.
+func CloudShellURL(meta *types.Meta) string {
+	q := url.Values{}
+	if meta != nil {
+		var repo, branch, tutorial string
+		if g, ok := util.ParseGitSource(meta.Source); ok {
+			repo, branch = "https://"+g.Repo, g.Ref
+			tutorial = path.Join(path.Dir(g.Path), meta.ID, "index."+TutorialExt)
+		}
+		if v := meta.Extra[MetaCloudShellRepo]; v != "" {
+			repo, branch, tutorial = v, "", ""
+		}
+		if v := meta.Extra[MetaCloudShellBranch]; v != "" {
+			branch = v
+		}
+		if v := meta.Extra[MetaCloudShellTutorial]; v != "" {
+			tutorial = v
+		}
+		if repo != "" {
+			q.Set(MetaCloudShellRepo, repo)
+		}
+		if branch != "" {
+			q.Set(MetaCloudShellBranch, branch)
+		}
+		if tutorial != "" {
+			q.Set(MetaCloudShellTutorial, tutorial)
+		}
+		if v := meta.Extra[MetaCloudShellWorkspace]; v != "" {
+			q.Set(MetaCloudShellWorkspace, v)
+		}
+	}
+	if len(q) == 0 {
+		return cloudShellOpen
+	}
+	return cloudShellOpen + "?" + q.Encode()
+}
+
+// cloudShell is how terminal code blocks open Cloud Shell.
+type cloudShell struct {
+	url      string // deep link of the codelab
+	embed    bool   // embed a terminal rather than link to it
+	embedded bool   // a terminal is already embedded
+}
+
+// cloudShell returns how terminal code blocks open Cloud Shell,
+// or nil if they do not.
+func (ctx Context) cloudShell() *cloudShell {
+	if ctx.CloudShell != CloudShellEmbed && ctx.CloudShell != CloudShellLink {
+		return nil
+	}
+	return &cloudShell{url: CloudShellURL(ctx.Meta), embed: ctx.CloudShell == CloudShellEmbed}
+}
+
+// frameURL returns the deep link of embedded terminals,
+// showing the terminal without the editor.
+func (c *cloudShell) frameURL() string {
+	sep := "?"
+	if strings.Contains(c.url, "?") {
+		sep = "&"
+	}
+	return c.url + sep + "show=terminal"
+}
+
+// node returns the link or embedded terminal following terminal code blocks.
+// A single terminal is embedded per rendering, which is a step or a page,
+// following its first terminal code block; node returns nil for others.
+func (c *cloudShell) node() *html.Node {
+	if c.embed {
+		if c.embedded {
+			return nil
+		}
+		c.embedded = true
+		return &html.Node{Type: html.ElementNode, Data: atom.Iframe.String(), Attr: []html.Attribute{
+			{Key: "class", Val: cloudShellFrame},
+			{Key: "src", Val: c.frameURL()},
+		}}
+	}
+	a := &html.Node{Type: html.ElementNode, Data: atom.A.String(), Attr: []html.Attribute{
+		{Key: "class", Val: cloudShellClass},
+		{Key: "href", Val: c.url},
+		{Key: "target", Val: "_blank"},
+	}}
+	a.AppendChild(&html.Node{Type: html.TextNode, Data: cloudShellLabel})
+	p := &html.Node{Type: html.ElementNode, Data: atom.P.String()}
+	p.AppendChild(a)
+	return p
+}
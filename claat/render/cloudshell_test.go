@@ -0,0 +1,155 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestCloudShellURL(t *testing.T) {
+	tests := []struct {
+		name string
+		meta *types.Meta
+		out  string
+	}{
+		{
+			name: "GitSource",
+			meta: &types.Meta{ID: "intro", Source: "github.com/owner/repo//labs/intro.md@main"},
+			out:  "https://shell.cloud.google.com/cloudshell/open?cloudshell_git_branch=main&cloudshell_git_repo=https%3A%2F%2Fgithub.com%2Fowner%2Frepo&cloudshell_tutorial=labs%2Fintro%2Findex.tutorial.md",
+		},
+		{
+			name: "DefaultBranch",
+			meta: &types.Meta{ID: "intro", Source: "github.com/owner/repo//intro.md"},
+			out:  "https://shell.cloud.google.com/cloudshell/open?cloudshell_git_repo=https%3A%2F%2Fgithub.com%2Fowner%2Frepo&cloudshell_tutorial=intro%2Findex.tutorial.md",
+		},
+		{
+			name: "Metadata",
+			meta: &types.Meta{Source: "1abcDoc", Extra: map[string]string{
+				"cloudshell_git_repo":  "https://github.com/owner/samples",
+				"cloudshell_tutorial":  "tutorial.md",
+				"cloudshell_workspace": "python",
+			}},
+			out: "https://shell.cloud.google.com/cloudshell/open?cloudshell_git_repo=https%3A%2F%2Fgithub.com%2Fowner%2Fsamples&cloudshell_tutorial=tutorial.md&cloudshell_workspace=python",
+		},
+		{
+			name: "OverrideRepo",
+			meta: &types.Meta{Source: "github.com/owner/repo//intro.md@main", Extra: map[string]string{
+				"cloudshell_git_repo": "https://github.com/owner/samples",
+			}},
+			out: "https://shell.cloud.google.com/cloudshell/open?cloudshell_git_repo=https%3A%2F%2Fgithub.com%2Fowner%2Fsamples",
+		},
+		{
+			name: "NoRepo",
+			meta: &types.Meta{Source: "codelab.md"},
+			out:  "https://shell.cloud.google.com/cloudshell/open",
+		},
+		{
+			name: "NoMeta",
+			out:  "https://shell.cloud.google.com/cloudshell/open",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.out, CloudShellURL(tc.meta)); diff != "" {
+				t.Errorf("CloudShellURL got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCloudShell(t *testing.T) {
+	meta := &types.Meta{ID: "intro", Source: "github.com/owner/repo//intro.md"}
+	link := "https://shell.cloud.google.com/cloudshell/open?cloudshell_git_repo=https%3A%2F%2Fgithub.com%2Fowner%2Frepo&amp;cloudshell_tutorial=intro%2Findex.tutorial.md"
+	term := nodes.NewCodeNode("ls", true, "")
+	code := nodes.NewCodeNode("ls", false, "go")
+	tests := []struct {
+		name       string
+		in         nodes.Node
+		cloudshell string
+		html       string
+		lite       string
+	}{
+		{
+			name:       "Link",
+			in:         term,
+			cloudshell: CloudShellLink,
+			html:       "<pre>ls</pre>\n" + `<p><a class="cloudshell-link" href="` + link + `" target="_blank">Open in Cloud Shell</a></p>` + "\n",
+			lite:       `<div><pre>ls</pre><p><a class="cloudshell-link" href="` + link + `" target="_blank">Open in Cloud Shell</a></p></div>`,
+		},
+		{
+			name:       "Embed",
+			in:         term,
+			cloudshell: CloudShellEmbed,
+			html:       "<pre>ls</pre>\n" + `<iframe class="cloudshell-terminal" src="` + link + `&amp;show=terminal"></iframe>` + "\n",
+			lite:       `<div><pre>ls</pre><iframe class="cloudshell-terminal" src="` + link + `&amp;show=terminal"></iframe></div>`,
+		},
+		{
+			name:       "NotTerminal",
+			in:         code,
+			cloudshell: CloudShellLink,
+			html:       `<pre><code language="go" class="go">ls</code></pre>` + "\n",
+			lite:       `<pre><code language="go" class="go">ls</code></pre>`,
+		},
+		{
+			name: "Disabled",
+			in:   term,
+			html: "<pre>ls</pre>\n",
+			lite: `<pre>ls</pre>`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := Context{Meta: meta, CloudShell: tc.cloudshell}
+			h, err := HTML(ctx, tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.html, string(h)); diff != "" {
+				t.Errorf("HTML got diff (-want +got):\n%s", diff)
+			}
+			l, err := Lite(ctx, tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.lite, string(l)); diff != "" {
+				t.Errorf("Lite got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCloudShellEmbedOnce(t *testing.T) {
+	ctx := Context{Meta: &types.Meta{ID: "intro"}, CloudShell: CloudShellEmbed}
+	term := func() nodes.Node { return nodes.NewCodeNode("ls", true, "") }
+	h, err := HTML(ctx, term(), term())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(h), "<iframe"); n != 1 {
+		t.Errorf("HTML of two terminal blocks has %d terminals; want 1:\n%s", n, h)
+	}
+	l, err := Lite(ctx, term(), term())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(l), "<iframe"); n != 1 {
+		t.Errorf("Lite of two terminal blocks has %d terminals; want 1:\n%s", n, l)
+	}
+}
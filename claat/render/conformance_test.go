@@ -0,0 +1,257 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/parser"
+	mdParse "github.com/googlecodelabs/tools/claat/parser/md"
+	"github.com/googlecodelabs/tools/claat/render/rendertest"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// semantics are the features of content which all formats must keep.
+type semantics struct {
+	Headings []string
+	Links    []string
+	Code     []string
+}
+
+// semanticText returns s with collapsed whitespace.
+func semanticText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// nodeSemantics returns the semantics of content nn.
+// Questions of surveys are headings and attachments are links.
+func nodeSemantics(nn []nodes.Node) semantics {
+	var s semantics
+	nodes.WalkNodes(nn, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if !entering {
+			return n, nil
+		}
+		switch n := n.(type) {
+		case *nodes.HeaderNode:
+			s.Headings = append(s.Headings, semanticText(nodes.PlainText(n.Content.Nodes...)))
+		case *nodes.SurveyNode:
+			for _, g := range n.Groups {
+				s.Headings = append(s.Headings, semanticText(g.Name))
+			}
+		case *nodes.URLNode:
+			s.Links = append(s.Links, n.URL)
+		case *nodes.AttachmentNode:
+			s.Links = append(s.Links, n.Src)
+		case *nodes.CodeNode:
+			s.Code = append(s.Code, strings.TrimRight(n.Value, "\n"))
+		}
+		return n, nil
+	})
+	return s
+}
+
+// htmlSemantics returns the semantics of HTML markup.
+func htmlSemantics(t *testing.T, markup string) semantics {
+	t.Helper()
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nn, err := html.ParseFragment(strings.NewReader(markup), body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s semantics
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch n.DataAtom {
+		case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			s.Headings = append(s.Headings, semanticText(htmlText(n)))
+		case atom.A:
+			for _, a := range n.Attr {
+				if a.Key == "href" {
+					s.Links = append(s.Links, a.Val)
+				}
+			}
+		case atom.Pre:
+			s.Code = append(s.Code, strings.TrimRight(htmlText(n), "\n"))
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	for _, n := range nn {
+		walk(n)
+	}
+	return s
+}
+
+// htmlText returns the text content of n.
+func htmlText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(htmlText(c))
+	}
+	return b.String()
+}
+
+// mdSemantics returns the semantics of Markdown content md,
+// as parsed back by the Markdown parser.
+func mdSemantics(t *testing.T, md string) semantics {
+	t.Helper()
+	nn, err := (&mdParse.Parser{}).ParseFragment(context.Background(), strings.NewReader(md), *parser.NewOptions())
+	if err != nil {
+		t.Fatalf("ParseFragment: %v\n%s", err, md)
+	}
+	return nodeSemantics(nn)
+}
+
+// checkConformance renders content nn in md, HTML and Lite formats,
+// and checks that they have the semantics of nn.
+func checkConformance(t *testing.T, ctx Context, nn []nodes.Node) {
+	t.Helper()
+	md, err := MD(ctx, nn...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := HTML(ctx, nn...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := Lite(ctx, nn...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	formats := []struct {
+		name string
+		got  semantics
+		out  string
+	}{
+		{"md", mdSemantics(t, md), md},
+		{"html", htmlSemantics(t, string(h)), string(h)},
+		{"lite", htmlSemantics(t, string(l)), string(l)},
+	}
+	for _, f := range formats {
+		want := nodeSemantics(nn)
+		if diff := cmp.Diff(want, f.got); diff != "" {
+			t.Errorf("%s output differs from content (-want +got):\n%s\noutput:\n%s", f.name, diff, f.out)
+		}
+	}
+}
+
+func conformanceText(s string) *nodes.TextNode {
+	return nodes.NewTextNode(nodes.NewTextNodeOptions{Value: s})
+}
+
+// conformanceCorpus returns content of every node type, alone and nested.
+func conformanceCorpus() map[string][]nodes.Node {
+	bold := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "bold", Bold: true})
+	link := func(u string) *nodes.URLNode {
+		return nodes.NewURLNode(u, conformanceText("link"))
+	}
+	para := func(nn ...nodes.Node) *nodes.ListNode {
+		l := nodes.NewListNode(nn...)
+		l.MutateBlock(true)
+		return l
+	}
+	items := func(start int, nn ...nodes.Node) *nodes.ItemsListNode {
+		il := nodes.NewItemsListNode("", start)
+		for _, n := range nn {
+			il.NewItem(n)
+		}
+		return il
+	}
+	cell := func(nn ...nodes.Node) *nodes.GridCell {
+		return &nodes.GridCell{Colspan: 1, Rowspan: 1, Content: nodes.NewListNode(nn...)}
+	}
+	img := nodes.NewImageNode(nodes.NewImageNodeOptions{Src: "https://example.com/img.png", Alt: "diagram"})
+
+	return map[string][]nodes.Node{
+		"text":       {para(conformanceText("plain "), bold)},
+		"link":       {para(conformanceText("see "), link("https://example.com/a"))},
+		"boldLink":   {para(nodes.NewURLNode("https://example.com/b", bold))},
+		"image":      {para(img)},
+		"imageLink":  {para(nodes.NewURLNode("https://example.com/c", img))},
+		"headers":    {nodes.NewHeaderNode(2, conformanceText("Setup")), nodes.NewHeaderNode(3, conformanceText("Sub "), bold)},
+		"list":       {items(0, conformanceText("one"), link("https://example.com/d"))},
+		"ordered":    {items(1, conformanceText("first"), nodes.NewCodeNode("x := 1", false, "go"))},
+		"code":       {nodes.NewCodeNode("fmt.Println(\"<hi>\")\n", false, "go")},
+		"console":    {nodes.NewCodeNode("$ ls -l\n", true, "")},
+		"infobox":    {nodes.NewInfoboxNode(nodes.InfoboxPositive, para(conformanceText("Note with "), link("https://example.com/e")))},
+		"infoboxNeg": {nodes.NewInfoboxNode(nodes.InfoboxNegative, items(0, conformanceText("careful")))},
+		"button":     {para(nodes.NewButtonNode(true, true, true, nodes.NewURLNode("https://example.com/sdk.zip", conformanceText("Download"))))},
+		"table": {nodes.NewGridNode(
+			[]*nodes.GridCell{cell(conformanceText("name")), cell(conformanceText("link"))},
+			[]*nodes.GridCell{cell(conformanceText("docs")), cell(link("https://example.com/f"))},
+		)},
+		"tableCode": {nodes.NewGridNode(
+			[]*nodes.GridCell{cell(conformanceText("command")), cell(nodes.NewCodeNode("gcloud init", false, ""))},
+		)},
+		"infoboxCode": {nodes.NewInfoboxNode(nodes.InfoboxPositive, para(conformanceText("Run")), nodes.NewCodeNode("make test\n", true, ""))},
+		"listLinks": {items(0,
+			para(link("https://example.com/g"), conformanceText(" and "), nodes.NewURLNode("https://example.com/h", bold)),
+			para(conformanceText("then "), nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "run", Code: true})),
+		)},
+		"youtube":  {nodes.NewYouTubeNode("dQw4w9WgXcQ")},
+		"iframe":   {nodes.NewIframeNode("https://codepen.io/pen/abc")},
+		"video":    {nodes.NewVideoNode("https://storage.googleapis.com/bucket/intro.mp4")},
+		"import":   {nodes.NewImportNode("frag.md")},
+		"activity": {nodes.NewActivityNode(1, para(conformanceText("Create a bucket")))},
+		"attach":   {nodes.NewAttachmentNode("files/starter.zip", "starter.zip", conformanceText("Starter code"))},
+		"review":   {nodes.NewReviewNode(nodes.ReviewInserted, para(link("https://example.com/i")), nodes.NewCodeNode("make\n", true, ""))},
+		"survey": {nodes.NewSurveyNode("lab-1", &nodes.SurveyGroup{
+			Name:    "Which command lists files?",
+			Kind:    nodes.SurveyChoice,
+			Options: []string{"ls", "cd"},
+		})},
+	}
+}
+
+// TestConformance checks that md, HTML and Lite output of every node type
+// keep the same headings, link targets and code.
+func TestConformance(t *testing.T) {
+	for name, nn := range conformanceCorpus() {
+		t.Run(name, func(t *testing.T) {
+			checkConformance(t, Context{Env: "web"}, nn)
+		})
+	}
+}
+
+// TestConformanceFixtures checks the conformance of the steps
+// of the fixtures of testdata.
+func TestConformanceFixtures(t *testing.T) {
+	files, err := filepath.Glob("testdata/golden/*.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files = append(files, "testdata/roundtrip.md")
+	for _, file := range files {
+		clab := rendertest.Load(t, file)
+		ctx := Context{Env: "web", Meta: &clab.Meta, Steps: clab.Steps}
+		for _, st := range clab.Steps {
+			st := st
+			t.Run(filepath.Base(file)+"/"+st.Title, func(t *testing.T) {
+				checkConformance(t, ctx, st.Content.Nodes)
+			})
+		}
+	}
+}
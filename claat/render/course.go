@@ -0,0 +1,77 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/googlecodelabs/tools/claat/types"
+
+	_ "embed" // embeding template files
+)
+
+// CourseContext is the template context of a course overview page.
+type CourseContext struct {
+	Course *types.Course
+	Labs   []*CourseEntry    // Labs of the course, in order
+	Extra  map[string]string // Extra variables passed from the command line.
+}
+
+// CourseEntry is a codelab listed in a course overview page.
+type CourseEntry struct {
+	*types.Meta
+	Num      int          // Position of the lab in the course, from 1
+	Link     string       // Codelab page, relative to the course page
+	Requires []*CourseRef // Labs to complete before this one
+	Elapsed  int          // Cumulative duration of the course up to and including this lab, in minutes
+}
+
+// CourseRef is a link to a lab required by a course entry.
+// Link is empty if the lab is neither part of the course nor a URL.
+type CourseRef struct {
+	Title string
+	Link  string
+}
+
+//go:embed template-course.html
+var newHTMLCourseTemplate []byte
+
+//go:embed template-course.md
+var newMDCourseTemplate []byte
+
+// ExecuteCourse renders a course overview page into w.
+//
+// The name argument is either "html" or "md", the built-in pages,
+// or a path to a local template file, as in Execute.
+func ExecuteCourse(w io.Writer, name string, data *CourseContext) error {
+	var tmpl *template
+	switch name {
+	case "html":
+		tmpl = &template{bytes: newHTMLCourseTemplate, html: true}
+	case "md":
+		tmpl = &template{bytes: newMDCourseTemplate}
+	default:
+		var err error
+		if tmpl, err = readTemplate(name); err != nil {
+			return fmt.Errorf("course template: %v", err)
+		}
+	}
+	t, err := tmpl.parse(name, nil)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, data)
+}
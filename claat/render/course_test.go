@@ -0,0 +1,54 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestExecuteCourse(t *testing.T) {
+	data := &CourseContext{
+		Course: &types.Course{ID: "track", Title: "Track & more", Duration: 30},
+		Labs: []*CourseEntry{
+			{Meta: &types.Meta{ID: "intro", Title: "Intro", Duration: 10}, Num: 1, Link: "../intro/", Elapsed: 10},
+			{
+				Meta: &types.Meta{ID: "deploy", Title: "Deploy", Duration: 20}, Num: 2, Link: "../deploy/", Elapsed: 30,
+				Requires: []*CourseRef{{Title: "Intro", Link: "../intro/"}, {Title: "gcs-basics"}},
+			},
+		},
+	}
+	var buf bytes.Buffer
+	if err := ExecuteCourse(&buf, "html", data); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"<title>Track &amp; more</title>",
+		`<li class="lab" id="deploy">`,
+		`<h2><a href="../deploy/">Deploy</a></h2>`,
+		`<a href="../intro/">Intro</a>, gcs-basics`,
+		"20 min &middot; 30 min total",
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("ExecuteCourse(html) does not contain %q:\n%s", s, buf.String())
+		}
+	}
+	if err := ExecuteCourse(&buf, "nonexistent.tmpl", data); err == nil {
+		t.Error("ExecuteCourse(nonexistent.tmpl) = nil error; want error")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// figureClass is the class of the figure elements of captioned images.
+const figureClass = "image-figure"
+
+// figureCaption returns the caption of image n, labeled with its figure
+// number, if any.
+func figureCaption(n *nodes.ImageNode) string {
+	if n.Figure != "" {
+		return n.Figure + ": " + n.Caption
+	}
+	return n.Caption
+}
+
+// figure returns a figure element holding img, the image node of n,
+// followed by its caption.
+func figure(img *html.Node, n *nodes.ImageNode) *html.Node {
+	top := &html.Node{
+		Type: html.ElementNode,
+		Data: atom.Figure.String(),
+		Attr: []html.Attribute{{Key: "class", Val: figureClass}},
+	}
+	top.AppendChild(img)
+	fc := &html.Node{Type: html.ElementNode, Data: atom.Figcaption.String()}
+	fc.AppendChild(&html.Node{Type: html.TextNode, Data: figureCaption(n)})
+	top.AppendChild(fc)
+	return top
+}
+
+// isFigure reports whether paragraph nodes nn are a captioned image,
+// along with space, which is rendered as a figure instead of a paragraph.
+func isFigure(nn []nodes.Node) bool {
+	var fig bool
+	for _, n := range nn {
+		switch n := n.(type) {
+		case *nodes.ImageNode:
+			if fig || n.Caption == "" {
+				return false
+			}
+			fig = true
+		case *nodes.TextNode:
+			if strings.TrimSpace(n.Value) != "" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return fig
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+func TestFigure(t *testing.T) {
+	img := nodes.NewImageNode(nodes.NewImageNodeOptions{Src: "console.png", Alt: "Console"})
+	img.Caption = "The <b> console"
+	img.Figure = "Figure 3"
+	p := nodes.NewListNode(img)
+	p.MutateBlock(true)
+
+	tests := []struct {
+		name   string
+		render func(Context, ...nodes.Node) (string, error)
+		out    string
+	}{
+		{
+			name: "HTML",
+			render: func(ctx Context, nn ...nodes.Node) (string, error) {
+				h, err := HTML(ctx, nn...)
+				return string(h), err
+			},
+			out: `<figure class="image-figure"><img alt="Console" src="console.png"><figcaption>Figure 3: The &lt;b&gt; console</figcaption></figure>` + "\n",
+		},
+		{
+			name: "Lite",
+			render: func(ctx Context, nn ...nodes.Node) (string, error) {
+				h, err := Lite(ctx, nn...)
+				return string(h), err
+			},
+			out: `<div><figure class="image-figure"><img src="console.png"/><figcaption>Figure 3: The &lt;b&gt; console</figcaption></figure></div>`,
+		},
+		{
+			name:   "MD",
+			render: MD,
+			out:    "\n\n<img src=\"console.png\" alt=\"Console\" />\n\n*Figure 3: The &lt;b&gt; console*\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := tc.render(Context{}, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.out, out); diff != "" {
+				t.Errorf("got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/parser"
+	mdParse "github.com/googlecodelabs/tools/claat/parser/md"
+)
+
+// FuzzRender checks that rendering any parsed Markdown codelab
+// does not panic, in all formats.
+// Run it with go test ./render -fuzz FuzzRender.
+func FuzzRender(f *testing.F) {
+	for _, file := range []string{"testdata/roundtrip.md", "testdata/golden/basic.md"} {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+	f.Add([]byte("# Lab\n\n## Step\n\n[](https://example.com)\n"))
+	f.Fuzz(func(t *testing.T, src []byte) {
+		clab, err := (&mdParse.Parser{}).Parse(context.Background(), bytes.NewReader(src), *parser.NewOptions())
+		if err != nil {
+			return
+		}
+		ctx := Context{Env: "web", Meta: &clab.Meta, Steps: clab.Steps}
+		for _, st := range clab.Steps {
+			MD(ctx, st.Content.Nodes...)
+			HTML(ctx, st.Content.Nodes...)
+			Lite(ctx, st.Content.Nodes...)
+		}
+		for _, format := range []string{"html", "md", "offline"} {
+			data := &struct{ Context }{ctx}
+			data.Format = format
+			Execute(ioutil.Discard, format, data)
+		}
+	})
+}
+
+// FuzzText checks that rendering any text node does not panic.
+// Run it with go test ./render -fuzz FuzzText.
+func FuzzText(f *testing.F) {
+	f.Add(" bold ", true, false, false)
+	f.Add("　太字　", true, true, false)
+	f.Add("<a>", false, false, true)
+	f.Add("\xe3\x81", true, false, false)
+	f.Fuzz(func(t *testing.T, s string, bold, italic, code bool) {
+		n := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: s, Bold: bold, Italic: italic, Code: code})
+		ctx := Context{Env: "web"}
+		MD(ctx, n)
+		HTML(ctx, n)
+		Lite(ctx, n)
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+// glossaryTitle is the title of a codelab glossary step.
+const glossaryTitle = "Glossary"
+
+// hasGlossarySection reports whether the md template renders a glossary
+// section for ctx: the codelab has a glossary but no glossary step.
+func hasGlossarySection(ctx Context) bool {
+	if ctx.Meta == nil || len(ctx.Meta.Glossary) == 0 {
+		return false
+	}
+	for _, st := range ctx.Steps {
+		if strings.EqualFold(st.Title, glossaryTitle) && nodes.MatchEnv(st.Tags, ctx.Env) {
+			return false
+		}
+	}
+	return true
+}
+
+// GlossarySection renders the codelab glossary as a final Markdown step
+// for glossary terms to link to, unless the codelab has a glossary step.
+// Terms are listed in alphabetical order.
+func GlossarySection(ctx Context) string {
+	if !hasGlossarySection(ctx) {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", glossaryTitle)
+	for _, t := range ctx.Meta.Glossary.Terms() {
+		fmt.Fprintf(&b, "**%s**: %s\n\n", t, ctx.Meta.Glossary[t])
+	}
+	return b.String()
+}
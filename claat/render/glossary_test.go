@@ -0,0 +1,106 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestGlossarySection(t *testing.T) {
+	meta := &types.Meta{Title: "Codelab", Glossary: types.Glossary{"VPC": "Virtual Private Cloud", "IAM": "Identity"}}
+	tests := []struct {
+		name  string
+		steps []*types.Step
+		out   string
+	}{
+		{
+			name:  "Section",
+			steps: []*types.Step{{Title: "Intro"}},
+			out:   "## Glossary\n\n**IAM**: Identity\n\n**VPC**: Virtual Private Cloud\n\n",
+		},
+		{
+			name:  "GlossaryStep",
+			steps: []*types.Step{{Title: "Intro"}, {Title: "glossary"}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := Context{Env: "web", Meta: meta, Steps: tc.steps}
+			if diff := cmp.Diff(tc.out, GlossarySection(ctx)); diff != "" {
+				t.Errorf("GlossarySection got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTerm(t *testing.T) {
+	term := nodes.NewTermNode("VPC", `A "virtual" network`, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "vpc"}))
+	a := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "a "})
+	tests := []struct {
+		name   string
+		render func(Context, ...nodes.Node) (string, error)
+		steps  []*types.Step
+		out    string
+	}{
+		{
+			name: "HTML",
+			render: func(ctx Context, nn ...nodes.Node) (string, error) {
+				s, err := HTML(ctx, nn...)
+				return string(s), err
+			},
+			out: `a <abbr class="glossary-term" title="A &#34;virtual&#34; network">vpc</abbr>`,
+		},
+		{
+			name: "Lite",
+			render: func(ctx Context, nn ...nodes.Node) (string, error) {
+				s, err := Lite(ctx, nn...)
+				return string(s), err
+			},
+			out: `a <abbr class="glossary-term" title="A &#34;virtual&#34; network">vpc</abbr>`,
+		},
+		{
+			name:   "MDSection",
+			render: MD,
+			steps:  []*types.Step{{Title: "Intro"}},
+			out:    "a [vpc](#glossary)",
+		},
+		{
+			name:   "MDStep",
+			render: MD,
+			steps:  []*types.Step{{Title: "Intro"}, {Title: "Glossary"}, {Title: "Glossary"}},
+			out:    "a [vpc](#glossary)",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := Context{
+				Env:   "web",
+				Meta:  &types.Meta{Title: "Codelab", Glossary: types.Glossary{"VPC": "network"}},
+				Steps: tc.steps,
+			}
+			out, err := tc.render(ctx, a, term)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.out, out); diff != "" {
+				t.Errorf("render got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
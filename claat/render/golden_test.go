@@ -0,0 +1,46 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/render/rendertest"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+var update = flag.Bool("update", false, "write golden files instead of comparing output with them")
+
+// TestGolden renders the fixtures of testdata/golden in md and html formats,
+// and compares them with their golden files. Update them with -update.
+func TestGolden(t *testing.T) {
+	rendertest.Run(t, "testdata/golden/*.md", "md", *update, func(w io.Writer, clab *types.Codelab) error {
+		data := &struct{ Context }{Context{Env: "web", Format: "md", Meta: &clab.Meta, Steps: clab.Steps}}
+		return Execute(w, "md", data)
+	})
+	rendertest.Run(t, "testdata/golden/*.md", "html", *update, func(w io.Writer, clab *types.Codelab) error {
+		for _, st := range clab.Steps {
+			fmt.Fprintf(w, "<!-- %s -->\n", st.Title)
+			if err := WriteHTML(w, "web", "html", st.Content.Nodes...); err != nil {
+				return err
+			}
+			fmt.Fprintln(w)
+		}
+		return nil
+	})
+}
@@ -15,32 +15,36 @@
 package render
 
 import (
-	"bytes"
 	"fmt"
 	htmlTemplate "html/template"
 	"io"
-	"sort"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/googlecodelabs/tools/claat/nodes"
+	"golang.org/x/net/html"
 )
 
 // TODO: render HTML using golang/x/net/html or template.
 
 // HTML renders nodes as the markup for the target env.
 func HTML(ctx Context, nodes ...nodes.Node) (htmlTemplate.HTML, error) {
-	var buf bytes.Buffer
-	if err := WriteHTML(&buf, ctx.Env, ctx.Format, nodes...); err != nil {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	hw := htmlWriter{w: buf, env: ctx.Env, format: ctx.Format, anchors: docAnchors(ctx), rtl: ctx.isRTL(), lightbox: ctx.Lightbox, cloudshell: ctx.cloudShell(), keepRuntimeVars: ctx.KeepRuntimeVars}
+	if err := hw.write(nodes...); err != nil {
 		return "", err
 	}
-	return htmlTemplate.HTML(buf.String()), nil
+	return htmlTemplate.HTML(ctx.sanitizer().Sanitize(buf.String())), nil
 }
 
 // WriteHTML does the same as HTML but outputs rendered markup to w.
 func WriteHTML(w io.Writer, env string, fmt string, nodes ...nodes.Node) error {
-	hw := htmlWriter{w: w, env: env, format: fmt}
-	return hw.write(nodes...)
+	return writeSanitized(w, func(w io.Writer) error {
+		hw := htmlWriter{w: w, env: env, format: fmt}
+		return hw.write(nodes...)
+	})
 }
 
 // ReplaceDoubleCurlyBracketsWithEntity replaces Double Curly Brackets with their charater entity.
@@ -48,19 +52,44 @@ func ReplaceDoubleCurlyBracketsWithEntity(s string) string {
 	return strings.Replace(s, "{{", "&#123;&#123;", -1)
 }
 
+// runtimeExprRegexp matches a Qwiklabs {{{...}}} runtime expression.
+var runtimeExprRegexp = regexp.MustCompile(`\{\{\{.*?\}\}\}`)
+
+// replaceDoubleCurlyBracketsKeepingRuntimeExprs is the same as
+// ReplaceDoubleCurlyBracketsWithEntity but leaves Qwiklabs {{{...}}}
+// runtime expressions of s untouched.
+func replaceDoubleCurlyBracketsKeepingRuntimeExprs(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range runtimeExprRegexp.FindAllStringIndex(s, -1) {
+		b.WriteString(ReplaceDoubleCurlyBracketsWithEntity(s[last:loc[0]]))
+		b.WriteString(s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(ReplaceDoubleCurlyBracketsWithEntity(s[last:]))
+	return b.String()
+}
+
 type htmlWriter struct {
-	w      io.Writer // output writer
-	env    string    // target environment
-	format string    // target template
-	err    error     // error during any writeXxx methods
+	w       io.Writer     // output writer
+	env     string        // target environment
+	format  string        // target template
+	err     error         // error during any writeXxx methods
+	anchors *anchors      // codelab heading anchors, if any
+	slugs   nodes.Slugger // anchors of headings not in anchors
+	rtl     bool          // text is written from right to left
+	// lightbox is the minimum width of images zooming in on click, if not 0
+	lightbox int
+	link     bool // writing the content of a link
+	// cloudshell is how terminal code blocks open Cloud Shell, if they do
+	cloudshell *cloudShell
+	// keepRuntimeVars leaves Qwiklabs {{{...}}} runtime expressions
+	// of text and code unescaped
+	keepRuntimeVars bool
 }
 
 func (hw *htmlWriter) matchEnv(v []string) bool {
-	if len(v) == 0 || hw.env == "" {
-		return true
-	}
-	i := sort.SearchStrings(v, hw.env)
-	return i < len(v) && v[i] == hw.env
+	return nodes.MatchEnv(v, hw.env)
 }
 
 func (hw *htmlWriter) write(nodesToWrite ...nodes.Node) error {
@@ -77,9 +106,20 @@ func (hw *htmlWriter) write(nodesToWrite ...nodes.Node) error {
 			hw.url(n)
 		case *nodes.ButtonNode:
 			hw.button(n)
+		case *nodes.XrefNode:
+			hw.xref(n)
+		case *nodes.TermNode:
+			hw.term(n)
+		case *nodes.IconNode:
+			hw.icon(n)
+		case *nodes.KbdNode:
+			hw.kbd(n)
 		case *nodes.CodeNode:
 			hw.code(n)
 			hw.writeString("\n")
+			if n.Term && hw.cloudshell != nil {
+				hw.cloudShell()
+			}
 		case *nodes.ListNode:
 			hw.list(n)
 			hw.writeString("\n")
@@ -101,6 +141,15 @@ func (hw *htmlWriter) write(nodesToWrite ...nodes.Node) error {
 		case *nodes.SurveyNode:
 			hw.survey(n)
 			hw.writeString("\n")
+		case *nodes.ActivityNode:
+			hw.activity(n)
+			hw.writeString("\n")
+		case *nodes.AttachmentNode:
+			hw.attachment(n)
+			hw.writeString("\n")
+		case *nodes.ReviewNode:
+			hw.review(n)
+			hw.writeString("\n")
 		case *nodes.HeaderNode:
 			hw.header(n)
 			hw.writeString("\n")
@@ -110,6 +159,9 @@ func (hw *htmlWriter) write(nodesToWrite ...nodes.Node) error {
 		case *nodes.IframeNode:
 			hw.iframe(n)
 			hw.writeString("\n")
+		case *nodes.VideoNode:
+			hw.video(n)
+			hw.writeString("\n")
 		}
 		if hw.err != nil {
 			return hw.err
@@ -124,12 +176,15 @@ func (hw *htmlWriter) writeString(s string) {
 	if hw.err != nil {
 		return
 	}
-	_, hw.err = hw.w.Write([]byte(s))
+	_, hw.err = io.WriteString(hw.w, s)
 }
 
 // Same as writeString, but with fmt.Sprintf arguments/semantics.
 func (hw *htmlWriter) writeFmt(f string, a ...interface{}) {
-	hw.writeString(fmt.Sprintf(f, a...))
+	if hw.err != nil {
+		return
+	}
+	_, hw.err = fmt.Fprintf(hw.w, f, a...)
 }
 
 func escape(s string) string {
@@ -140,7 +195,17 @@ func escape(s string) string {
 
 // Same as writeString, but performs HTML escaping and double curly bracket escaping.
 func (hw *htmlWriter) writeEscape(s string) {
-	hw.writeString(escape(s))
+	hw.writeString(hw.replaceDoubleCurlyBrackets(htmlTemplate.HTMLEscapeString(s)))
+}
+
+// replaceDoubleCurlyBrackets replaces the double curly brackets of s
+// with their character entity, except those of Qwiklabs {{{...}}}
+// runtime expressions if hw.keepRuntimeVars is set.
+func (hw *htmlWriter) replaceDoubleCurlyBrackets(s string) string {
+	if hw.keepRuntimeVars {
+		return replaceDoubleCurlyBracketsKeepingRuntimeExprs(s)
+	}
+	return ReplaceDoubleCurlyBracketsWithEntity(s)
 }
 
 func (hw *htmlWriter) text(n *nodes.TextNode) {
@@ -153,7 +218,9 @@ func (hw *htmlWriter) text(n *nodes.TextNode) {
 		hw.writeString("<em>")
 	}
 	if n.Code {
-		hw.writeString("<code>")
+		hw.writeString("<code")
+		hw.ltr()
+		hw.writeString(">")
 		shouldEsc = false
 	}
 	if shouldEsc {
@@ -161,7 +228,7 @@ func (hw *htmlWriter) text(n *nodes.TextNode) {
 		// Remove whitespace we added to divide adjacent bold and italic nodes.
 		s = strings.Trim(s, string('\uFEFF'))
 	}
-	s = ReplaceDoubleCurlyBracketsWithEntity(s)
+	s = hw.replaceDoubleCurlyBrackets(s)
 	hw.writeString(strings.Replace(s, "\n", "<br>", -1))
 	if n.Code {
 		hw.writeString("</code>")
@@ -175,6 +242,26 @@ func (hw *htmlWriter) text(n *nodes.TextNode) {
 }
 
 func (hw *htmlWriter) image(n *nodes.ImageNode) {
+	if n.Caption != "" {
+		hw.writeFmt(`<figure class=%q>`, figureClass)
+		defer hw.figcaption(n)
+	}
+	if zoomable(n, hw.lightbox, hw.link) {
+		hw.writeString("<a")
+		for _, a := range lightboxAttrs(n) {
+			hw.writeFmt(" %s=%q", a.Key, escape(a.Val))
+		}
+		hw.writeString(">")
+		defer hw.writeString("</a>")
+	}
+	if len(n.Annotations) > 0 {
+		hw.writeFmt(`<span class="image-annotated" style=%q>`, annotatedStyle)
+		defer hw.annotations(n.Annotations)
+	}
+	if n.Dark != "" {
+		hw.writeFmt(`<picture><source srcset=%q media=%q>`, escape(n.Dark), darkMedia)
+		defer hw.writeString("</picture>")
+	}
 	hw.writeString("<img")
 	if n.Alt != "" {
 		hw.writeFmt(" alt=%q", n.Alt)
@@ -188,6 +275,60 @@ func (hw *htmlWriter) image(n *nodes.ImageNode) {
 	hw.writeFmt(" src=%q>", n.Src)
 }
 
+// figcaption writes the caption of image n and closes its figure.
+func (hw *htmlWriter) figcaption(n *nodes.ImageNode) {
+	hw.writeString("<figcaption>")
+	hw.writeEscape(figureCaption(n))
+	hw.writeString("</figcaption></figure>")
+}
+
+// icon is written with the Material Icons font of the codelab page.
+func (hw *htmlWriter) icon(n *nodes.IconNode) {
+	hw.writeFmt(`<span class="%s" title=%q>%s</span>`, iconClass, n.Name, n.Name)
+}
+
+// kbd writes each key in a kbd element, joined with "+".
+func (hw *htmlWriter) kbd(n *nodes.KbdNode) {
+	for i, k := range n.Keys {
+		if i > 0 {
+			hw.writeString("+")
+		}
+		hw.writeString("<kbd>")
+		hw.writeEscape(k)
+		hw.writeString("</kbd>")
+	}
+}
+
+// annotations writes the overlay of image annotations aa,
+// and closes the span of the annotated image.
+func (hw *htmlWriter) annotations(aa []nodes.Annotation) {
+	var b strings.Builder
+	for _, hn := range annotationOverlay(aa) {
+		if err := html.Render(&b, hn); err != nil {
+			hw.err = err
+			return
+		}
+	}
+	hw.writeString(ReplaceDoubleCurlyBracketsWithEntity(b.String()))
+	hw.writeString("</span>")
+}
+
+// cloudShell writes the link or embedded terminal opening Cloud Shell
+// after a terminal code block.
+func (hw *htmlWriter) cloudShell() {
+	hn := hw.cloudshell.node()
+	if hn == nil {
+		return
+	}
+	var b strings.Builder
+	if err := html.Render(&b, hn); err != nil {
+		hw.err = err
+		return
+	}
+	hw.writeString(b.String())
+	hw.writeString("\n")
+}
+
 func (hw *htmlWriter) url(n *nodes.URLNode) {
 	hw.writeString("<a")
 	if n.URL != "" {
@@ -200,14 +341,53 @@ func (hw *htmlWriter) url(n *nodes.URLNode) {
 		hw.writeFmt(" target=%q", escape(n.Target))
 	}
 	hw.writeString(">")
-	hw.write(n.Content.Nodes...)
+	hw.linkContent(n.Content.Nodes...)
 	hw.writeString("</a>")
 }
 
+// linkContent writes nodes nn as the content of a link.
+func (hw *htmlWriter) linkContent(nn ...nodes.Node) {
+	link := hw.link
+	hw.link = true
+	hw.write(nn...)
+	hw.link = link
+}
+
+// xref links to the step by its position, which is how
+// the codelab elements navigate between steps.
+func (hw *htmlWriter) xref(n *nodes.XrefNode) {
+	index := n.Step - 1
+	id, i, ok := hw.anchors.step(n.Step)
+	if ok {
+		index = i
+	}
+	if ok && hw.anchors.split != "" {
+		hw.writeFmt(`<a href="%s">`, hw.anchors.href(hw.anchors.all[n.Step-1], id))
+	} else {
+		hw.writeFmt(`<a href="#%d">`, index)
+	}
+	hw.linkContent(n.Content.Nodes...)
+	hw.writeString("</a>")
+}
+
+// term shows the definition of a glossary term as a tooltip.
+func (hw *htmlWriter) term(n *nodes.TermNode) {
+	hw.writeFmt(`<abbr class="glossary-term" title=%q>`, escape(n.Definition))
+	hw.write(n.Content.Nodes...)
+	hw.writeString("</abbr>")
+}
+
 func (hw *htmlWriter) button(n *nodes.ButtonNode) {
 	hw.writeString("<paper-button")
+	var cls []string
 	if n.Color {
-		hw.writeString(` class="colored"`)
+		cls = append(cls, "colored")
+	}
+	if n.Variant != "" {
+		cls = append(cls, string(n.Variant))
+	}
+	if len(cls) > 0 {
+		hw.writeFmt(" class=%q", strings.Join(cls, " "))
 	}
 	if n.Raise {
 		hw.writeString(" raised")
@@ -220,24 +400,110 @@ func (hw *htmlWriter) button(n *nodes.ButtonNode) {
 	hw.writeString("</paper-button>")
 }
 
+// ltr keeps the text of an element written from left to right,
+// such as code, in a codelab written from right to left.
+func (hw *htmlWriter) ltr() {
+	if hw.rtl {
+		hw.writeString(` dir="ltr"`)
+	}
+}
+
 func (hw *htmlWriter) code(n *nodes.CodeNode) {
-	hw.writeString("<pre>")
+	hw.writeString("<pre")
+	hw.ltr()
+	if n.NoCopy {
+		hw.writeString(" noCopy")
+	}
+	if n.Output {
+		hw.writeString(" output")
+	}
+	hw.writeString(">")
 	if !n.Term {
 		hw.writeString("<code")
 		if n.Lang != "" {
 			hw.writeFmt(" language=%q class=%q", n.Lang, n.Lang)
 		}
+		if n.Diff {
+			hw.writeString(" diff")
+		}
+		if len(n.Highlight) > 0 {
+			hw.writeFmt(" highlight=%q", nodes.FormatLineRanges(n.Highlight))
+		}
+		if n.LineNumbers {
+			hw.writeString(" line-numbers")
+		}
 		hw.writeString(">")
 	}
-	hw.writeEscape(n.Value)
+	if !hasLineMarkup(n) {
+		hw.writeEscape(n.Value)
+	}
+	for _, l := range codeLines(n) {
+		if len(l.classes) == 0 && !n.LineNumbers {
+			hw.writeEscape(l.text)
+			continue
+		}
+		hw.writeString("<span")
+		if len(l.classes) > 0 {
+			hw.writeFmt(" class=%q", strings.Join(l.classes, " "))
+		}
+		if n.LineNumbers {
+			hw.writeFmt(` data-line="%d"`, l.num)
+		}
+		hw.writeString(">")
+		hw.writeEscape(strings.TrimSuffix(l.text, "\n"))
+		hw.writeString("</span>")
+		if strings.HasSuffix(l.text, "\n") {
+			hw.writeString("\n")
+		}
+	}
 	if !n.Term {
 		hw.writeString("</code>")
 	}
 	hw.writeString("</pre>")
 }
 
+// hasLineMarkup reports whether lines of code n are marked up individually,
+// for diffs, highlighting or line numbers.
+func hasLineMarkup(n *nodes.CodeNode) bool {
+	return n.Diff || len(n.Highlight) > 0 || n.LineNumbers
+}
+
+// codeLine is a line of a code block, with its line break if any.
+type codeLine struct {
+	text    string
+	num     int      // line number, from 1
+	classes []string // diff-add, diff-remove or highlight
+}
+
+// codeLines splits the value of code n into lines marked up individually,
+// or returns nil if n has no line markup. Lines starting with "+" or "-"
+// of a diff are added or removed lines.
+func codeLines(n *nodes.CodeNode) []codeLine {
+	if !hasLineMarkup(n) {
+		return nil
+	}
+	var res []codeLine
+	for i, l := range strings.SplitAfter(n.Value, "\n") {
+		if l == "" {
+			continue
+		}
+		cl := codeLine{text: l, num: i + 1}
+		switch {
+		case n.Diff && l[0] == '+':
+			cl.classes = append(cl.classes, "diff-add")
+		case n.Diff && l[0] == '-':
+			cl.classes = append(cl.classes, "diff-remove")
+		}
+		if n.Highlighted(cl.num) {
+			cl.classes = append(cl.classes, "highlight")
+		}
+		res = append(res, cl)
+	}
+	return res
+}
+
 func (hw *htmlWriter) list(n *nodes.ListNode) {
-	wrap := n.Block() == true
+	wrap := n.Block() == true && !isFigure(n.Nodes)
 	if wrap {
 		if onlyImages(n.Nodes...) {
 			hw.writeString(`<p class="image-container">`)
@@ -321,14 +587,63 @@ func (hw *htmlWriter) infobox(n *nodes.InfoboxNode) {
 	hw.writeString("</aside>")
 }
 
+// activity writes a Qwiklabs activity tracking element,
+// without a step attribute if n is not numbered yet.
+func (hw *htmlWriter) activity(n *nodes.ActivityNode) {
+	hw.writeString("<ql-activity-tracking")
+	if n.Step > 0 {
+		hw.writeFmt(` step="%d"`, n.Step)
+	}
+	hw.writeString(">")
+	hw.write(n.Content.Nodes...)
+	hw.writeString("</ql-activity-tracking>")
+}
+
+// review writes an ins or del element marking content changed
+// since a previous version.
+func (hw *htmlWriter) review(n *nodes.ReviewNode) {
+	hw.writeFmt("<%s class=\"review\">", n.Kind)
+	hw.write(n.Content.Nodes...)
+	hw.writeFmt("</%s>", n.Kind)
+}
+
+// attachment writes a download button linking to the attached file.
+// The file name is the button text if n has no content.
+func (hw *htmlWriter) attachment(n *nodes.AttachmentNode) {
+	hw.writeFmt(`<p class="attachment"><a href=%q download=%q>`, n.Src, escape(n.FileName()))
+	hw.writeString(`<paper-button class="colored" raised><iron-icon icon="file-download"></iron-icon>`)
+	if n.Content.Empty() {
+		hw.writeString(escape(n.FileName()))
+	} else {
+		hw.linkContent(n.Content.Nodes...)
+	}
+	hw.writeString("</paper-button></a></p>")
+}
+
 func (hw *htmlWriter) survey(n *nodes.SurveyNode) {
 	hw.writeFmt("<google-codelab-survey survey-id=%q>\n", n.ID)
 	for _, g := range n.Groups {
-		hw.writeFmt("<h4>%s</h4>\n<paper-radio-group>\n", g.Name)
-		for _, o := range g.Options {
-			hw.writeFmt("<paper-radio-button>%s</paper-radio-button>\n", escape(o))
+		hw.writeFmt("<h4>%s</h4>\n", g.Name)
+		switch g.Kind {
+		case nodes.SurveyMulti:
+			hw.writeString("<div class=\"survey-multi\">\n")
+			for _, o := range g.Options {
+				hw.writeFmt("<paper-checkbox>%s</paper-checkbox>\n", escape(o))
+			}
+			hw.writeString("</div>\n")
+		case nodes.SurveyText:
+			hw.writeString("<paper-textarea></paper-textarea>\n")
+		default:
+			if g.Kind == nodes.SurveyRating {
+				hw.writeString("<paper-radio-group class=\"survey-rating\">\n")
+			} else {
+				hw.writeString("<paper-radio-group>\n")
+			}
+			for _, o := range g.Options {
+				hw.writeFmt("<paper-radio-button>%s</paper-radio-button>\n", escape(o))
+			}
+			hw.writeString("</paper-radio-group>\n")
 		}
-		hw.writeString("</paper-radio-group>\n")
 	}
 	hw.writeString("</google-codelab-survey>")
 }
@@ -344,6 +659,9 @@ func (hw *htmlWriter) header(n *nodes.HeaderNode) {
 		hw.writeString(` class="faq"`)
 
 	}
+	if id := hw.anchors.header(n, &hw.slugs); id != "" {
+		hw.writeFmt(` id=%q`, id)
+	}
 	hw.writeString(` is-upgraded>`)
 	hw.write(n.Content.Nodes...)
 	hw.writeFmt("</%s>", tag)
@@ -351,11 +669,36 @@ func (hw *htmlWriter) header(n *nodes.HeaderNode) {
 
 func (hw *htmlWriter) youtube(n *nodes.YouTubeNode) {
 	hw.writeFmt(`<iframe class="youtube-video" `+
-		`src="https://www.youtube.com/embed/%s?rel=0" allow="accelerometer; `+
+		`src="%s" allow="accelerometer; `+
 		`autoplay; encrypted-media; gyroscope; picture-in-picture" `+
-		`allowfullscreen></iframe>`, n.VideoID)
+		`allowfullscreen></iframe>`, escape(n.EmbedURL()))
 }
 
 func (hw *htmlWriter) iframe(n *nodes.IframeNode) {
 	hw.writeFmt(`<iframe class="embedded-iframe" src=%q></iframe>`, n.URL)
 }
+
+// video writes a Vimeo player or a <video> element with an optional
+// poster image and captions track.
+func (hw *htmlWriter) video(n *nodes.VideoNode) {
+	if id := n.VimeoID(); id != "" {
+		hw.writeFmt(`<iframe class="vimeo-video" `+
+			`src="https://player.vimeo.com/video/%s" `+
+			`allow="autoplay; fullscreen; picture-in-picture" `+
+			`allowfullscreen></iframe>`, id)
+		return
+	}
+	hw.writeFmt(`<video class="video" src=%q`, escape(n.Src))
+	if n.Poster != "" {
+		hw.writeFmt(` poster=%q`, escape(n.Poster))
+	}
+	hw.writeString(" controls>")
+	if n.Captions != "" {
+		hw.writeFmt(`<track kind="captions" src=%q`, escape(n.Captions))
+		if n.CaptionsLang != "" {
+			hw.writeFmt(` srclang=%q`, escape(n.CaptionsLang))
+		}
+		hw.writeString(" default>")
+	}
+	hw.writeString("</video>")
+}
@@ -17,6 +17,7 @@ package render
 import (
 	"bytes"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -131,10 +132,21 @@ func TestMatchEnv(t *testing.T) {
 			out:   true,
 		},
 		{
-			// TODO: should this be false?
 			name:  "MultiMatch",
 			inEnv: "foo",
 			inV:   []string{"foo", "bar", "baz"},
+			out:   true,
+		},
+		{
+			name:  "Negated",
+			inEnv: "foo",
+			inV:   []string{"!foo"},
+		},
+		{
+			name:  "Glob",
+			inEnv: "qwiklabs-gcp",
+			inV:   []string{"qwiklabs-*"},
+			out:   true,
 		},
 		{
 			name:  "NoMatch",
@@ -586,7 +598,7 @@ func TestURL(t *testing.T) {
 		{
 			name:   "MultipleContent",
 			inNode: nodes.NewURLNode("google.com", nodes.NewHeaderNode(1, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "foo"})), nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "bar"})),
-			out: `<a href="google.com" target="_blank"><h1 is-upgraded>foo</h1>
+			out: `<a href="google.com" target="_blank"><h1 id="foo" is-upgraded>foo</h1>
 bar</a>`,
 		},
 	}
@@ -654,10 +666,27 @@ func TestButton(t *testing.T) {
 			inNode: nodes.NewButtonNode(true, true, true, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "foobar"})),
 			out:    `<paper-button class="colored" raised><iron-icon icon="file-download"></iron-icon>foobar</paper-button>`,
 		},
+		{
+			name: "Variant",
+			inNode: &nodes.ButtonNode{
+				Color:   true,
+				Variant: nodes.ButtonSecondary,
+				Content: nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "foobar"})),
+			},
+			out: `<paper-button class="colored secondary">foobar</paper-button>`,
+		},
+		{
+			name: "Console",
+			inNode: &nodes.ButtonNode{
+				Variant: nodes.ButtonConsole,
+				Content: nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "foobar"})),
+			},
+			out: `<paper-button class="console">foobar</paper-button>`,
+		},
 		{
 			name:   "MultipleContent",
 			inNode: nodes.NewButtonNode(false, false, false, nodes.NewHeaderNode(2, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "foo"})), nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "bar"})),
-			out: `<paper-button><h2 is-upgraded>foo</h2>
+			out: `<paper-button><h2 id="foo" is-upgraded>foo</h2>
 bar</paper-button>`,
 		},
 	}
@@ -690,6 +719,45 @@ func TestCode(t *testing.T) {
 			inNode: nodes.NewCodeNode("foobar", false, "c"),
 			out:    `<pre><code language="c" class="c">foobar</code></pre>`,
 		},
+		{
+			name: "Diff",
+			inNode: func() *nodes.CodeNode {
+				n := nodes.NewCodeNode("x := 1\n-f(x)\n+g(x)\n", false, "go")
+				n.Diff = true
+				return n
+			}(),
+			out: "<pre><code language=\"go\" class=\"go\" diff>x := 1\n" +
+				"<span class=\"diff-remove\">-f(x)</span>\n<span class=\"diff-add\">+g(x)</span>\n</code></pre>",
+		},
+		{
+			name: "Output",
+			inNode: func() *nodes.CodeNode {
+				n := nodes.NewCodeNode("total 0", true, "")
+				n.Output = true
+				return n
+			}(),
+			out: `<pre output>total 0</pre>`,
+		},
+		{
+			name: "NoCopy",
+			inNode: func() *nodes.CodeNode {
+				n := nodes.NewCodeNode("f()", false, "")
+				n.NoCopy = true
+				return n
+			}(),
+			out: `<pre noCopy><code>f()</code></pre>`,
+		},
+		{
+			name: "HighlightLineNumbers",
+			inNode: func() *nodes.CodeNode {
+				n := nodes.NewCodeNode("a\nb\nc", false, "")
+				n.Highlight = []nodes.LineRange{{Start: 2, End: 3}}
+				n.LineNumbers = true
+				return n
+			}(),
+			out: "<pre><code highlight=\"2-3\" line-numbers><span data-line=\"1\">a</span>\n" +
+				"<span class=\"highlight\" data-line=\"2\">b</span>\n<span class=\"highlight\" data-line=\"3\">c</span></code></pre>",
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -704,6 +772,74 @@ func TestCode(t *testing.T) {
 	}
 }
 
+func TestLiteCodeDiff(t *testing.T) {
+	n := nodes.NewCodeNode("-f(x)\n+g(x)\n", false, "")
+	n.Diff = true
+	out, err := Lite(Context{}, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<pre><code data-diff=\"\"><span class=\"step__diff-remove\">-f(x)</span>\n" +
+		"<span class=\"step__diff-add\">+g(x)</span>\n</code></pre>"
+	if diff := cmp.Diff(want, string(out)); diff != "" {
+		t.Errorf("Lite(%+v) got diff (-want +got):\n%s", n, diff)
+	}
+}
+
+func TestLiteCodeHighlight(t *testing.T) {
+	n := nodes.NewCodeNode("a\n+b\n", false, "")
+	n.Diff = true
+	n.Highlight = []nodes.LineRange{{Start: 2, End: 2}}
+	out, err := Lite(Context{}, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<pre><code data-diff=\"\">a\n<span class=\"step__diff-add step__highlight\">+b</span>\n</code></pre>"
+	if diff := cmp.Diff(want, string(out)); diff != "" {
+		t.Errorf("Lite(%+v) got diff (-want +got):\n%s", n, diff)
+	}
+}
+
+func TestLiteCodeCopy(t *testing.T) {
+	out, err := Lite(Context{}, &nodes.CodeNode{Term: true, Value: "total 0", Output: true}, &nodes.CodeNode{Value: "f()", NoCopy: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<pre class=\"step__output\">total 0</pre><pre data-no-copy=\"\"><code>f()</code></pre>"
+	if diff := cmp.Diff(want, string(out)); diff != "" {
+		t.Errorf("Lite got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestLiteButtonVariant(t *testing.T) {
+	btn := nodes.NewButtonNode(true, true, false, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Open console"}))
+	btn.Variant = nodes.ButtonConsole
+	out, err := Lite(Context{}, btn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<a class="step__button button--colored button--raised button--console">Open console</a>`
+	if diff := cmp.Diff(want, string(out)); diff != "" {
+		t.Errorf("Lite got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestLiteVideo(t *testing.T) {
+	v := nodes.NewVideoNode("intro.mp4")
+	v.Poster = "intro.png"
+	v.Captions = "intro.vtt"
+	v.CaptionsLang = "en"
+	out, err := Lite(Context{}, v, nodes.NewVideoNode("https://vimeo.com/76979871"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<video class="step__video" src="intro.mp4" poster="intro.png" controls="1"><track kind="captions" src="intro.vtt" srclang="en" default="1"/></video>` +
+		`<div class="keep-ar"><div class="keep-ar__pad"><iframe src="https://player.vimeo.com/video/76979871" allow="autoplay; fullscreen; picture-in-picture" allowfullscreen="1" class="keep-ar__box"></iframe></div></div>`
+	if diff := cmp.Diff(want, string(out)); diff != "" {
+		t.Errorf("Lite got diff (-want +got):\n%s", diff)
+	}
+}
+
 func TestList(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -943,13 +1079,13 @@ func TestInfobox(t *testing.T) {
 		{
 			name:   "PositiveNonEmpty",
 			inNode: nodes.NewInfoboxNode(nodes.InfoboxPositive, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "foo"}), nodes.NewHeaderNode(3, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "bar"}))),
-			out: `<aside class="special">foo<h3 is-upgraded>bar</h3>
+			out: `<aside class="special">foo<h3 id="bar" is-upgraded>bar</h3>
 </aside>`,
 		},
 		{
 			name:   "NegativeNonEmpty",
 			inNode: nodes.NewInfoboxNode(nodes.InfoboxNegative, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "foo"}), nodes.NewHeaderNode(3, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "bar"}))),
-			out: `<aside class="warning">foo<h3 is-upgraded>bar</h3>
+			out: `<aside class="warning">foo<h3 id="bar" is-upgraded>bar</h3>
 </aside>`,
 		},
 	}
@@ -997,6 +1133,40 @@ func TestSurvey(t *testing.T) {
 <paper-radio-button>no</paper-radio-button>
 <paper-radio-button>probably</paper-radio-button>
 </paper-radio-group>
+</google-codelab-survey>`,
+		},
+		{
+			name: "Kinds",
+			inNode: nodes.NewSurveyNode("kinds",
+				&nodes.SurveyGroup{
+					Name:    "topics",
+					Kind:    nodes.SurveyMulti,
+					Options: []string{"a", "b"},
+				},
+				&nodes.SurveyGroup{
+					Name:    "rate it",
+					Kind:    nodes.SurveyRating,
+					Options: nodes.RatingOptions(1, 3),
+				},
+				&nodes.SurveyGroup{
+					Name: "comments",
+					Kind: nodes.SurveyText,
+				},
+			),
+			out: `<google-codelab-survey survey-id="kinds">
+<h4>topics</h4>
+<div class="survey-multi">
+<paper-checkbox>a</paper-checkbox>
+<paper-checkbox>b</paper-checkbox>
+</div>
+<h4>rate it</h4>
+<paper-radio-group class="survey-rating">
+<paper-radio-button>1</paper-radio-button>
+<paper-radio-button>2</paper-radio-button>
+<paper-radio-button>3</paper-radio-button>
+</paper-radio-group>
+<h4>comments</h4>
+<paper-textarea></paper-textarea>
 </google-codelab-survey>`,
 		},
 		{
@@ -1086,12 +1256,12 @@ func TestHeader(t *testing.T) {
 		{
 			name:   "SimpleH1",
 			inNode: nodes.NewHeaderNode(1, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "foobar"})),
-			out:    "<h1 is-upgraded>foobar</h1>",
+			out:    `<h1 id="foobar" is-upgraded>foobar</h1>`,
 		},
 		{
 			name:   "LevelOutOfRange",
 			inNode: nodes.NewHeaderNode(100, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "foobar"})),
-			out:    "<h100 is-upgraded>foobar</h100>",
+			out:    `<h100 id="foobar" is-upgraded>foobar</h100>`,
 		},
 		{
 			name:   "EmptyContent",
@@ -1101,7 +1271,7 @@ func TestHeader(t *testing.T) {
 		{
 			name:   "StyledText",
 			inNode: nodes.NewHeaderNode(3, a1, a2, a3),
-			out:    "<h3 is-upgraded><em>foo</em>bar<code>baz</code></h3>",
+			out:    `<h3 id="foobarbaz" is-upgraded><em>foo</em>bar<code>baz</code></h3>`,
 		},
 	}
 	for _, tc := range tests {
@@ -1117,6 +1287,44 @@ func TestHeader(t *testing.T) {
 	}
 }
 
+func TestVideo(t *testing.T) {
+	captioned := nodes.NewVideoNode("https://storage.googleapis.com/bucket/intro.mp4")
+	captioned.Poster = "intro.png"
+	captioned.Captions = "intro.vtt"
+	captioned.CaptionsLang = "en"
+	tests := []struct {
+		name   string
+		inNode *nodes.VideoNode
+		out    string
+	}{
+		{
+			name:   "File",
+			inNode: nodes.NewVideoNode("videos/intro.mp4"),
+			out:    `<video class="video" src="videos/intro.mp4" controls></video>`,
+		},
+		{
+			name:   "PosterCaptions",
+			inNode: captioned,
+			out:    `<video class="video" src="https://storage.googleapis.com/bucket/intro.mp4" poster="intro.png" controls><track kind="captions" src="intro.vtt" srclang="en" default></video>`,
+		},
+		{
+			name:   "Vimeo",
+			inNode: nodes.NewVideoNode("https://vimeo.com/76979871"),
+			out:    `<iframe class="vimeo-video" src="https://player.vimeo.com/video/76979871" allow="autoplay; fullscreen; picture-in-picture" allowfullscreen></iframe>`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			outBuffer := &bytes.Buffer{}
+			hw := &htmlWriter{w: outBuffer}
+			hw.video(tc.inNode)
+			if diff := cmp.Diff(tc.out, outBuffer.String()); diff != "" {
+				t.Errorf("hw.video(%+v) got diff (-want +got):\n%s", tc.inNode, diff)
+			}
+		})
+	}
+}
+
 func TestYouTube(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -1128,6 +1336,16 @@ func TestYouTube(t *testing.T) {
 			inNode: nodes.NewYouTubeNode("Mlk888FiI8A"),
 			out:    `<iframe class="youtube-video" src="https://www.youtube.com/embed/Mlk888FiI8A?rel=0" allow="accelerometer; autoplay; encrypted-media; gyroscope; picture-in-picture" allowfullscreen></iframe>`,
 		},
+		{
+			name: "Options",
+			inNode: &nodes.YouTubeNode{
+				VideoID:  "Mlk888FiI8A",
+				Start:    90,
+				Captions: "fr",
+				NoCookie: true,
+			},
+			out: `<iframe class="youtube-video" src="https://www.youtube-nocookie.com/embed/Mlk888FiI8A?rel=0&amp;start=90&amp;cc_load_policy=1&amp;cc_lang_pref=fr" allow="accelerometer; autoplay; encrypted-media; gyroscope; picture-in-picture" allowfullscreen></iframe>`,
+		},
 		{
 			name:   "Empty",
 			inNode: nodes.NewYouTubeNode(""),
@@ -1181,3 +1399,57 @@ func TestIframe(t *testing.T) {
 		})
 	}
 }
+
+func TestReview(t *testing.T) {
+	text := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Old text."})
+	tests := []struct {
+		name   string
+		inNode *nodes.ReviewNode
+		out    string
+	}{
+		{
+			name:   "Inserted",
+			inNode: nodes.NewReviewNode(nodes.ReviewInserted, nodes.NewCodeNode("ls\n", true, "")),
+			out:    "<ins class=\"review\"><pre>ls\n</pre>\n</ins>",
+		},
+		{
+			name:   "Deleted",
+			inNode: nodes.NewReviewNode(nodes.ReviewDeleted, text),
+			out:    `<del class="review">Old text.</del>`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			outBuffer := &bytes.Buffer{}
+			hw := &htmlWriter{w: outBuffer}
+			hw.review(tc.inNode)
+			if diff := cmp.Diff(tc.out, outBuffer.String()); diff != "" {
+				t.Errorf("hw.review(%+v) got diff (-want +got):\n%s", tc.inNode, diff)
+			}
+		})
+	}
+}
+
+func TestHTMLRuntimeVars(t *testing.T) {
+	// like in Markdown output, {{{name}}} runtime expressions of text and code
+	// are only escaped without -keep_runtime_vars
+	const in = "{{{project_0.project_id}}} in {{zone}}"
+	tests := []struct {
+		keep bool
+		out  string
+	}{
+		{false, "&#123;&#123;{project_0.project_id}}} in &#123;&#123;zone}}"},
+		{true, "{{{project_0.project_id}}} in &#123;&#123;zone}}"},
+	}
+	for _, tc := range tests {
+		text := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: in})
+		code := nodes.NewCodeNode(in, true, "")
+		got, err := HTML(Context{KeepRuntimeVars: tc.keep}, text, code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := strings.Count(string(got), tc.out); n != 2 {
+			t.Errorf("HTML(KeepRuntimeVars: %v) has %d occurrences of %q, want 2:\n%s", tc.keep, n, tc.out, got)
+		}
+	}
+}
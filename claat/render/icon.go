@@ -0,0 +1,56 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+// iconClass is the class of HTML elements written with the Material Icons font.
+const iconClass = "material-icons"
+
+// iconSize is the width, in pixels, of icon images in Markdown.
+const iconSize = 20
+
+// IconText maps Material icon names to the text written for them
+// in Markdown, without Context.IconImages. Other icons are written as their name.
+var IconText = map[string]string{
+	"add":            "+",
+	"arrow_back":     "←",
+	"arrow_downward": "↓",
+	"arrow_forward":  "→",
+	"arrow_upward":   "↑",
+	"check":          "✓",
+	"close":          "✕",
+	"content_copy":   "⧉",
+	"delete":         "🗑",
+	"edit":           "✎",
+	"expand_less":    "⌃",
+	"expand_more":    "⌄",
+	"home":           "⌂",
+	"menu":           "☰",
+	"more_horiz":     "⋯",
+	"more_vert":      "⋮",
+	"play_arrow":     "▶",
+	"refresh":        "⟳",
+	"remove":         "−",
+	"search":         "🔍",
+	"settings":       "⚙",
+	"star":           "★",
+}
+
+// iconText returns the text of icon name, as in IconText.
+func iconText(name string) string {
+	if t, ok := IconText[name]; ok {
+		return t
+	}
+	return name
+}
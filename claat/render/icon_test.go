@@ -0,0 +1,61 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+func TestIcon(t *testing.T) {
+	text := func(s string) nodes.Node {
+		return nodes.NewTextNode(nodes.NewTextNodeOptions{Value: s})
+	}
+	nn := []nodes.Node{text("Click "), nodes.NewIconNode("menu"), text(" then "), nodes.NewIconNode("cloud_upload")}
+
+	h, err := HTML(Context{}, nn...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `Click <span class="material-icons" title="menu">menu</span> then <span class="material-icons" title="cloud_upload">cloud_upload</span>`
+	if diff := cmp.Diff(want, string(h)); diff != "" {
+		t.Errorf("HTML got diff (-want +got):\n%s", diff)
+	}
+	l, err := Lite(Context{}, nn...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, string(l)); diff != "" {
+		t.Errorf("Lite got diff (-want +got):\n%s", diff)
+	}
+
+	md, err := MD(Context{}, nn...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Click ☰ then cloud_upload"; md != want {
+		t.Errorf("MD = %q; want %q", md, want)
+	}
+
+	md, err = MD(Context{IconImages: "https://example.com/{name}.svg"}, nn[:2]...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `Click <img src="https://example.com/menu.svg" alt="☰" title="menu"  width="20.00" />`; md != want {
+		t.Errorf("MD with IconImages = %q; want %q", md, want)
+	}
+}
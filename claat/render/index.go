@@ -0,0 +1,60 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"io"
+
+	"github.com/googlecodelabs/tools/claat/types"
+
+	_ "embed" // embeding template files
+)
+
+// IndexContext is the template context of a codelab catalog page.
+type IndexContext struct {
+	Codelabs   []*IndexEntry
+	Categories []string          // Categories of all codelabs, sorted
+	Products   []string          // Products required by steps of all codelabs, sorted
+	Extra      map[string]string // Extra variables passed from the command line.
+}
+
+// IndexEntry is a codelab listed in a catalog page.
+type IndexEntry struct {
+	*types.ContextMeta
+	Link     string   // Codelab page, relative to the catalog page
+	Products []string // Products required by the codelab steps
+}
+
+//go:embed template-index.html
+var newIndexTemplate []byte
+
+// ExecuteIndex renders a catalog page of codelabs into w.
+//
+// The name argument is either "html", the built-in catalog page,
+// or a path to a local template file, as in Execute.
+func ExecuteIndex(w io.Writer, name string, data *IndexContext) error {
+	tmpl := &template{bytes: newIndexTemplate, html: true}
+	if name != "html" {
+		var err error
+		if tmpl, err = readTemplate(name); err != nil {
+			return err
+		}
+	}
+	t, err := tmpl.parse(name, nil)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, data)
+}
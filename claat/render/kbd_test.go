@@ -0,0 +1,59 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+func TestKbd(t *testing.T) {
+	nn := []nodes.Node{
+		nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Press "}),
+		nodes.NewKbdNode("Ctrl", "<"),
+	}
+	h, err := HTML(Context{}, nn...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("Press <kbd>Ctrl</kbd>+<kbd>&lt;</kbd>", string(h)); diff != "" {
+		t.Errorf("HTML got diff (-want +got):\n%s", diff)
+	}
+	l, err := Lite(Context{}, nn...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("Press <span><kbd>Ctrl</kbd>+<kbd>&lt;</kbd></span>", string(l)); diff != "" {
+		t.Errorf("Lite got diff (-want +got):\n%s", diff)
+	}
+	md, err := MD(Context{}, nn...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("Press `Ctrl`+`<`", md); diff != "" {
+		t.Errorf("MD got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestMDKbdElement(t *testing.T) {
+	src := "---\nid: kbd\n\n---\n\n# Kbd\n\n## Copy\n\nPress <kbd>Ctrl</kbd>+<kbd>C</kbd> to copy.\n"
+	_, out := exportMD(t, []byte(src))
+	if want := "Press `Ctrl`+`C` to copy."; !strings.Contains(out, want) {
+		t.Errorf("md export does not contain %q:\n%s", want, out)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// lightboxClass is the class of the links zooming in on images,
+// and lightboxGroup their data-lightbox group, which lightbox scripts
+// such as Lightbox2 page through.
+const (
+	lightboxClass = "image-lightbox"
+	lightboxGroup = "codelab"
+)
+
+// zoomable reports whether image n, outside of a link, zooms in on click
+// with a lightbox of images at least min pixels wide, which is disabled if
+// min is 0. Images of unknown width, such as screenshots in Markdown,
+// zoom in as well.
+func zoomable(n *nodes.ImageNode, min int, link bool) bool {
+	return min > 0 && !link && (n.Width == 0 || n.Width >= float32(min))
+}
+
+// lightboxAttrs returns the attributes of a link zooming in on image n.
+// The link opens the image in a new tab if no lightbox script handles it.
+func lightboxAttrs(n *nodes.ImageNode) []html.Attribute {
+	attrs := []html.Attribute{
+		{Key: "class", Val: lightboxClass},
+		{Key: "href", Val: n.Src},
+		{Key: "target", Val: "_blank"},
+		{Key: "data-lightbox", Val: lightboxGroup},
+	}
+	if title := figureCaption(n); title != "" {
+		attrs = append(attrs, html.Attribute{Key: "data-title", Val: title})
+	} else if n.Alt != "" {
+		attrs = append(attrs, html.Attribute{Key: "data-title", Val: n.Alt})
+	}
+	return attrs
+}
+
+// lightbox returns a link zooming in on img, the image node of n.
+func lightbox(img *html.Node, n *nodes.ImageNode) *html.Node {
+	a := &html.Node{Type: html.ElementNode, Data: atom.A.String(), Attr: lightboxAttrs(n)}
+	a.AppendChild(img)
+	return a
+}
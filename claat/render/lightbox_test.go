@@ -0,0 +1,89 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+func TestLightbox(t *testing.T) {
+	img := func(width float32) *nodes.ImageNode {
+		return nodes.NewImageNode(nodes.NewImageNodeOptions{Src: "console.png", Alt: "Console", Width: width})
+	}
+	tests := []struct {
+		name     string
+		in       nodes.Node
+		lightbox int
+		html     string
+		lite     string
+	}{
+		{
+			name:     "Large",
+			in:       img(800),
+			lightbox: 400,
+			html:     `<a class="image-lightbox" href="console.png" target="_blank" data-lightbox="codelab" data-title="Console"><img alt="Console" style="width: 800.00px" src="console.png"></a>`,
+			lite:     `<a class="image-lightbox" href="console.png" target="_blank" data-lightbox="codelab" data-title="Console"><img src="console.png" style="width: 800.00px"/></a>`,
+		},
+		{
+			name:     "UnknownWidth",
+			in:       img(0),
+			lightbox: 400,
+			html:     `<a class="image-lightbox" href="console.png" target="_blank" data-lightbox="codelab" data-title="Console"><img alt="Console" src="console.png"></a>`,
+			lite:     `<a class="image-lightbox" href="console.png" target="_blank" data-lightbox="codelab" data-title="Console"><img src="console.png"/></a>`,
+		},
+		{
+			name:     "Small",
+			in:       img(20),
+			lightbox: 400,
+			html:     `<img alt="Console" style="width: 20.00px" src="console.png">`,
+			lite:     `<img src="console.png" style="width: 20.00px"/>`,
+		},
+		{
+			name:     "Link",
+			in:       nodes.NewURLNode("https://example.com", img(800)),
+			lightbox: 400,
+			html:     `<a href="https://example.com" target="_blank"><img alt="Console" style="width: 800.00px" src="console.png"></a>`,
+			lite:     `<a href="https://example.com" target="_blank"><img src="console.png" style="width: 800.00px"/></a>`,
+		},
+		{
+			name: "Disabled",
+			in:   img(800),
+			html: `<img alt="Console" style="width: 800.00px" src="console.png">`,
+			lite: `<img src="console.png" style="width: 800.00px"/>`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := Context{Lightbox: tc.lightbox}
+			h, err := HTML(ctx, tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.html, string(h)); diff != "" {
+				t.Errorf("HTML got diff (-want +got):\n%s", diff)
+			}
+			l, err := Lite(ctx, tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.lite, string(l)); diff != "" {
+				t.Errorf("Lite got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -15,11 +15,10 @@
 package render
 
 import (
-	"bytes"
+	"bufio"
 	"fmt"
 	htmlTemplate "html/template"
 	"io"
-	"sort"
 	"strconv"
 	"strings"
 
@@ -30,41 +29,55 @@ import (
 
 // Lite renders nodes as a standard HTML markup, without Custom Elements.
 func Lite(ctx Context, nodes ...nodes.Node) (htmlTemplate.HTML, error) {
-	var buf bytes.Buffer
-	if err := WriteLite(&buf, ctx.Env, nodes...); err != nil {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	lw := liteWriter{w: buf, env: ctx.Env, anchors: docAnchors(ctx), rtl: ctx.isRTL(), lightbox: ctx.Lightbox, cloudshell: ctx.cloudShell()}
+	if err := lw.write(nodes...); err != nil {
 		return "", err
 	}
-	return htmlTemplate.HTML(buf.String()), nil
+	return htmlTemplate.HTML(ctx.sanitizer().Sanitize(buf.String())), nil
 }
 
 // WriteLite does the same as Lite but outputs rendered markup to w.
 func WriteLite(w io.Writer, env string, nodes ...nodes.Node) error {
-	lw := liteWriter{w: w, env: env}
-	return lw.write(nodes...)
+	return writeSanitized(w, func(w io.Writer) error {
+		lw := liteWriter{w: w, env: env}
+		return lw.write(nodes...)
+	})
 }
 
 type liteWriter struct {
-	w   io.Writer // output writer
-	env string    // target environment
-	err error     // error during any writeXxx methods
+	w       io.Writer     // output writer
+	env     string        // target environment
+	err     error         // error during any writeXxx methods
+	anchors *anchors      // codelab heading anchors, if any
+	slugs   nodes.Slugger // anchors of headings not in anchors
+	rtl     bool          // text is written from right to left
+	// lightbox is the minimum width of images zooming in on click, if not 0
+	lightbox int
+	link     bool // rendering the content of a link
+	// cloudshell is how terminal code blocks open Cloud Shell, if they do
+	cloudshell *cloudShell
 }
 
 func (lw *liteWriter) matchEnv(v []string) bool {
-	if len(v) == 0 || lw.env == "" {
-		return true
-	}
-	i := sort.SearchStrings(v, lw.env)
-	return i < len(v) && v[i] == lw.env
+	return nodes.MatchEnv(v, lw.env)
 }
 
+// write renders each of nodes in turn, so that only the tree
+// of a single top-level node is held in memory at a time.
 func (lw *liteWriter) write(nodes ...nodes.Node) error {
-	doc := &html.Node{Type: html.DocumentNode}
+	bw := bufio.NewWriter(lw.w)
 	for _, n := range nodes {
-		if hn := lw.htmlnode(n); hn != nil {
-			doc.AppendChild(hn)
+		hn := lw.htmlnode(n)
+		if hn == nil {
+			continue
+		}
+		if err := html.Render(bw, hn); err != nil {
+			return err
 		}
 	}
-	return html.Render(lw.w, doc)
+	return bw.Flush()
 }
 
 func (lw *liteWriter) htmlnode(n nodes.Node) *html.Node {
@@ -81,8 +94,19 @@ func (lw *liteWriter) htmlnode(n nodes.Node) *html.Node {
 		hn = lw.alink(n)
 	case *nodes.ButtonNode:
 		hn = lw.button(n)
+	case *nodes.XrefNode:
+		hn = lw.xref(n)
+	case *nodes.TermNode:
+		hn = lw.term(n)
+	case *nodes.IconNode:
+		hn = lw.icon(n)
+	case *nodes.KbdNode:
+		hn = lw.kbd(n)
 	case *nodes.CodeNode:
 		hn = lw.code(n)
+		if n.Term && lw.cloudshell != nil {
+			hn = lw.cloudShell(hn)
+		}
 	case *nodes.ListNode:
 		hn = lw.list(n)
 	case *nodes.ImportNode:
@@ -97,10 +121,18 @@ func (lw *liteWriter) htmlnode(n nodes.Node) *html.Node {
 		hn = lw.infobox(n)
 	case *nodes.SurveyNode:
 		hn = lw.survey(n)
+	case *nodes.ActivityNode:
+		hn = lw.activity(n)
+	case *nodes.AttachmentNode:
+		hn = lw.attachment(n)
+	case *nodes.ReviewNode:
+		hn = lw.review(n)
 	case *nodes.HeaderNode:
 		hn = lw.header(n)
 	case *nodes.YouTubeNode:
 		hn = lw.youtube(n)
+	case *nodes.VideoNode:
+		hn = lw.video(n)
 	}
 	return hn
 }
@@ -119,12 +151,21 @@ func (lw *liteWriter) text(n *nodes.TextNode) *html.Node {
 	}
 	if n.Code {
 		hn := &html.Node{Type: html.ElementNode, Data: atom.Code.String()}
+		lw.ltr(hn)
 		hn.AppendChild(top)
 		top = hn
 	}
 	return top
 }
 
+// ltr keeps the text of element hn written from left to right,
+// such as code, in a codelab written from right to left.
+func (lw *liteWriter) ltr(hn *html.Node) {
+	if lw.rtl {
+		hn.Attr = append(hn.Attr, html.Attribute{Key: "dir", Val: "ltr"})
+	}
+}
+
 func (lw *liteWriter) image(n *nodes.ImageNode) *html.Node {
 	hn := &html.Node{
 		Type: html.ElementNode,
@@ -137,9 +178,50 @@ func (lw *liteWriter) image(n *nodes.ImageNode) *html.Node {
 			Val: fmt.Sprintf("width: %.2fpx", n.Width),
 		})
 	}
+	if n.Dark != "" {
+		hn = picture(hn, n)
+	}
+	if len(n.Annotations) > 0 {
+		hn = annotated(hn, n.Annotations)
+	}
+	if zoomable(n, lw.lightbox, lw.link) {
+		hn = lightbox(hn, n)
+	}
+	if n.Caption != "" {
+		return figure(hn, n)
+	}
+	return hn
+}
+
+// icon is written with the Material Icons font of the codelab page.
+func (lw *liteWriter) icon(n *nodes.IconNode) *html.Node {
+	hn := &html.Node{
+		Type: html.ElementNode,
+		Data: atom.Span.String(),
+		Attr: []html.Attribute{
+			{Key: "class", Val: iconClass},
+			{Key: "title", Val: n.Name},
+		},
+	}
+	hn.AppendChild(&html.Node{Type: html.TextNode, Data: n.Name})
 	return hn
 }
 
+// kbd writes each key in a kbd element, joined with "+",
+// in a span holding the shortcut.
+func (lw *liteWriter) kbd(n *nodes.KbdNode) *html.Node {
+	top := &html.Node{Type: html.ElementNode, Data: atom.Span.String()}
+	for i, k := range n.Keys {
+		if i > 0 {
+			top.AppendChild(&html.Node{Type: html.TextNode, Data: "+"})
+		}
+		kn := &html.Node{Type: html.ElementNode, Data: atom.Kbd.String()}
+		kn.AppendChild(&html.Node{Type: html.TextNode, Data: k})
+		top.AppendChild(kn)
+	}
+	return top
+}
+
 func (lw *liteWriter) alink(n *nodes.URLNode) *html.Node {
 	top := &html.Node{Type: html.ElementNode, Data: atom.A.String()}
 	if n.URL != "" {
@@ -151,6 +233,40 @@ func (lw *liteWriter) alink(n *nodes.URLNode) *html.Node {
 	if n.Target != "" {
 		top.Attr = append(top.Attr, html.Attribute{Key: "target", Val: n.Target})
 	}
+	lw.linkChildren(top, n.Content.Nodes)
+	return top
+}
+
+// linkChildren appends nodes nn, the content of link top, to top.
+func (lw *liteWriter) linkChildren(top *html.Node, nn []nodes.Node) {
+	link := lw.link
+	lw.link = true
+	for _, cn := range nn {
+		if hn := lw.htmlnode(cn); hn != nil {
+			top.AppendChild(hn)
+		}
+	}
+	lw.link = link
+}
+
+// xref links to the page of the target step.
+func (lw *liteWriter) xref(n *nodes.XrefNode) *html.Node {
+	href := "index.html"
+	if n.Step > 1 {
+		href = fmt.Sprintf("step-%d.html", n.Step)
+	}
+	top := &html.Node{Type: html.ElementNode, Data: atom.A.String()}
+	top.Attr = append(top.Attr, html.Attribute{Key: "href", Val: href})
+	lw.linkChildren(top, n.Content.Nodes)
+	return top
+}
+
+// term shows the definition of a glossary term as a tooltip.
+func (lw *liteWriter) term(n *nodes.TermNode) *html.Node {
+	top := &html.Node{Type: html.ElementNode, Data: atom.Abbr.String()}
+	top.Attr = append(top.Attr,
+		html.Attribute{Key: "class", Val: "glossary-term"},
+		html.Attribute{Key: "title", Val: n.Definition})
 	for _, cn := range n.Content.Nodes {
 		if hn := lw.htmlnode(cn); hn != nil {
 			top.AppendChild(hn)
@@ -170,6 +286,9 @@ func (lw *liteWriter) button(n *nodes.ButtonNode) *html.Node {
 	if n.Download {
 		cls = append(cls, "button--download")
 	}
+	if n.Variant != "" {
+		cls = append(cls, "button--"+string(n.Variant))
+	}
 	top := &html.Node{
 		Type: html.ElementNode,
 		Data: atom.A.String(),
@@ -183,9 +302,35 @@ func (lw *liteWriter) button(n *nodes.ButtonNode) *html.Node {
 	return top
 }
 
+// cloudShell returns terminal code block pre followed by the link
+// or embedded terminal opening Cloud Shell, in a div, or pre alone
+// if a terminal is already embedded.
+func (lw *liteWriter) cloudShell(pre *html.Node) *html.Node {
+	hn := lw.cloudshell.node()
+	if hn == nil {
+		return pre
+	}
+	div := &html.Node{Type: html.ElementNode, Data: atom.Div.String()}
+	div.AppendChild(pre)
+	div.AppendChild(hn)
+	return div
+}
+
 func (lw *liteWriter) code(n *nodes.CodeNode) *html.Node {
-	top := &html.Node{Type: html.TextNode, Data: n.Value}
+	content := []*html.Node{{Type: html.TextNode, Data: n.Value}}
+	if hasLineMarkup(n) {
+		content = lineNodes(n)
+	}
 
+	pre := &html.Node{Type: html.ElementNode, Data: atom.Pre.String()}
+	lw.ltr(pre)
+	if n.NoCopy {
+		pre.Attr = append(pre.Attr, html.Attribute{Key: "data-no-copy"})
+	}
+	if n.Output {
+		pre.Attr = append(pre.Attr, html.Attribute{Key: "class", Val: "step__output"})
+	}
+	parent := pre
 	if !n.Term {
 		hn := &html.Node{Type: html.ElementNode, Data: atom.Code.String()}
 		if n.Lang != "" {
@@ -198,20 +343,54 @@ func (lw *liteWriter) code(n *nodes.CodeNode) *html.Node {
 				Val: n.Lang,
 			})
 		}
-		hn.AppendChild(top)
-		top = hn
+		if n.Diff {
+			hn.Attr = append(hn.Attr, html.Attribute{Key: "data-diff"})
+		}
+		if n.LineNumbers {
+			hn.Attr = append(hn.Attr, html.Attribute{Key: "data-line-numbers"})
+		}
+		pre.AppendChild(hn)
+		parent = hn
 	}
+	for _, c := range content {
+		parent.AppendChild(c)
+	}
+	return pre
+}
 
-	hn := &html.Node{Type: html.ElementNode, Data: atom.Pre.String()}
-	hn.AppendChild(top)
-	top = hn
-
-	return top
+// lineNodes returns the lines of code n as text nodes, with marked up
+// lines in spans of step__diff-add, step__diff-remove and step__highlight
+// classes, and a data-line attribute if n has line numbers.
+func lineNodes(n *nodes.CodeNode) []*html.Node {
+	var res []*html.Node
+	for _, l := range codeLines(n) {
+		if len(l.classes) == 0 && !n.LineNumbers {
+			res = append(res, &html.Node{Type: html.TextNode, Data: l.text})
+			continue
+		}
+		span := &html.Node{Type: html.ElementNode, Data: atom.Span.String()}
+		if len(l.classes) > 0 {
+			classes := make([]string, len(l.classes))
+			for i, c := range l.classes {
+				classes[i] = "step__" + c
+			}
+			span.Attr = append(span.Attr, html.Attribute{Key: "class", Val: strings.Join(classes, " ")})
+		}
+		if n.LineNumbers {
+			span.Attr = append(span.Attr, html.Attribute{Key: "data-line", Val: strconv.Itoa(l.num)})
+		}
+		span.AppendChild(&html.Node{Type: html.TextNode, Data: strings.TrimSuffix(l.text, "\n")})
+		res = append(res, span)
+		if strings.HasSuffix(l.text, "\n") {
+			res = append(res, &html.Node{Type: html.TextNode, Data: "\n"})
+		}
+	}
+	return res
 }
 
 func (lw *liteWriter) list(n *nodes.ListNode) *html.Node {
 	a := atom.P
-	if n.Block() != true {
+	if n.Block() != true || isFigure(n.Nodes) {
 		a = atom.Div
 	}
 	top := &html.Node{Type: html.ElementNode, Data: a.String()}
@@ -314,6 +493,65 @@ func (lw *liteWriter) infobox(n *nodes.InfoboxNode) *html.Node {
 	return top
 }
 
+// activity renders a Qwiklabs activity tracking block as a progress
+// checkpoint, with the tracked step number in data-activity-step.
+func (lw *liteWriter) activity(n *nodes.ActivityNode) *html.Node {
+	top := &html.Node{
+		Type: html.ElementNode,
+		Data: atom.Div.String(),
+		Attr: []html.Attribute{{Key: "class", Val: "step__activity"}},
+	}
+	if n.Step > 0 {
+		top.Attr = append(top.Attr, html.Attribute{Key: "data-activity-step", Val: strconv.Itoa(n.Step)})
+	}
+	for _, cn := range n.Content.Nodes {
+		if hn := lw.htmlnode(cn); hn != nil {
+			top.AppendChild(hn)
+		}
+	}
+	return top
+}
+
+// review renders an ins or del element marking content changed
+// since a previous version.
+func (lw *liteWriter) review(n *nodes.ReviewNode) *html.Node {
+	top := &html.Node{
+		Type: html.ElementNode,
+		Data: string(n.Kind),
+		Attr: []html.Attribute{{Key: "class", Val: "step__review"}},
+	}
+	for _, cn := range n.Content.Nodes {
+		if hn := lw.htmlnode(cn); hn != nil {
+			top.AppendChild(hn)
+		}
+	}
+	return top
+}
+
+// attachment renders a download button linking to the attached file.
+func (lw *liteWriter) attachment(n *nodes.AttachmentNode) *html.Node {
+	a := &html.Node{
+		Type: html.ElementNode,
+		Data: atom.A.String(),
+		Attr: []html.Attribute{
+			{Key: "class", Val: "step__button button--colored button--raised button--download"},
+			{Key: "href", Val: n.Src},
+			{Key: "download", Val: n.FileName()},
+		},
+	}
+	if n.Content.Empty() {
+		a.AppendChild(&html.Node{Type: html.TextNode, Data: n.FileName()})
+	}
+	lw.linkChildren(a, n.Content.Nodes)
+	top := &html.Node{
+		Type: html.ElementNode,
+		Data: atom.P.String(),
+		Attr: []html.Attribute{{Key: "class", Val: "step__attachment"}},
+	}
+	top.AppendChild(a)
+	return top
+}
+
 func (lw *liteWriter) survey(n *nodes.SurveyNode) *html.Node {
 	top := &html.Node{
 		Type: html.ElementNode,
@@ -332,12 +570,30 @@ func (lw *liteWriter) survey(n *nodes.SurveyNode) *html.Node {
 		h4.AppendChild(&html.Node{Type: html.TextNode, Data: g.Name})
 		top.AppendChild(h4)
 		id := fmt.Sprintf("%s-%d", n.ID, i)
+		if g.Kind == nodes.SurveyText {
+			top.AppendChild(&html.Node{
+				Type: html.ElementNode,
+				Data: atom.Textarea.String(),
+				Attr: []html.Attribute{
+					{Key: "class", Val: "survey__text"},
+					{Key: "name", Val: id},
+				},
+			})
+			continue
+		}
+		typ, class := "radio", "survey__a"
+		switch g.Kind {
+		case nodes.SurveyMulti:
+			typ = "checkbox"
+		case nodes.SurveyRating:
+			class = "survey__a survey__rating"
+		}
 		for _, o := range g.Options {
 			oh := &html.Node{
 				Type: html.ElementNode,
 				Data: atom.Input.String(),
 				Attr: []html.Attribute{
-					{Key: "type", Val: "radio"},
+					{Key: "type", Val: typ},
 					{Key: "name", Val: id},
 					{Key: "value", Val: o},
 				},
@@ -345,7 +601,7 @@ func (lw *liteWriter) survey(n *nodes.SurveyNode) *html.Node {
 			lab := &html.Node{
 				Type: html.ElementNode,
 				Data: atom.Label.String(),
-				Attr: []html.Attribute{{Key: "class", Val: "survey__a"}},
+				Attr: []html.Attribute{{Key: "class", Val: class}},
 			}
 			lab.AppendChild(oh)
 			lab.AppendChild(&html.Node{Type: html.TextNode, Data: o})
@@ -370,6 +626,9 @@ func (lw *liteWriter) header(n *nodes.HeaderNode) *html.Node {
 	if cls != "" {
 		top.Attr = append(top.Attr, html.Attribute{Key: "class", Val: cls})
 	}
+	if id := lw.anchors.header(n, &lw.slugs); id != "" {
+		top.Attr = append(top.Attr, html.Attribute{Key: "id", Val: id})
+	}
 	for _, cn := range n.Content.Nodes {
 		if hn := lw.htmlnode(cn); hn != nil {
 			top.AppendChild(hn)
@@ -379,6 +638,66 @@ func (lw *liteWriter) header(n *nodes.HeaderNode) *html.Node {
 }
 
 func (lw *liteWriter) youtube(n *nodes.YouTubeNode) *html.Node {
+	return keepAspectRatio(&html.Node{
+		Type: html.ElementNode,
+		Data: atom.Iframe.String(),
+		Attr: []html.Attribute{
+			{Key: "src", Val: n.EmbedURL()},
+			{Key: "allow", Val: "accelerometer; autoplay; encrypted-media; gyroscope; picture-in-picture"},
+			{Key: "allowfullscreen", Val: "1"},
+			{Key: "class", Val: "keep-ar__box"},
+		},
+	})
+}
+
+// video returns a Vimeo player or a <video> element with an optional
+// poster image and captions track.
+func (lw *liteWriter) video(n *nodes.VideoNode) *html.Node {
+	if id := n.VimeoID(); id != "" {
+		return keepAspectRatio(&html.Node{
+			Type: html.ElementNode,
+			Data: atom.Iframe.String(),
+			Attr: []html.Attribute{
+				{Key: "src", Val: "https://player.vimeo.com/video/" + id},
+				{Key: "allow", Val: "autoplay; fullscreen; picture-in-picture"},
+				{Key: "allowfullscreen", Val: "1"},
+				{Key: "class", Val: "keep-ar__box"},
+			},
+		})
+	}
+	top := &html.Node{
+		Type: html.ElementNode,
+		Data: atom.Video.String(),
+		Attr: []html.Attribute{
+			{Key: "class", Val: "step__video"},
+			{Key: "src", Val: n.Src},
+		},
+	}
+	if n.Poster != "" {
+		top.Attr = append(top.Attr, html.Attribute{Key: "poster", Val: n.Poster})
+	}
+	top.Attr = append(top.Attr, html.Attribute{Key: "controls", Val: "1"})
+	if n.Captions != "" {
+		track := &html.Node{
+			Type: html.ElementNode,
+			Data: atom.Track.String(),
+			Attr: []html.Attribute{
+				{Key: "kind", Val: "captions"},
+				{Key: "src", Val: n.Captions},
+			},
+		}
+		if n.CaptionsLang != "" {
+			track.Attr = append(track.Attr, html.Attribute{Key: "srclang", Val: n.CaptionsLang})
+		}
+		track.Attr = append(track.Attr, html.Attribute{Key: "default", Val: "1"})
+		top.AppendChild(track)
+	}
+	return top
+}
+
+// keepAspectRatio wraps an embedded player box in elements keeping
+// its aspect ratio.
+func keepAspectRatio(box *html.Node) *html.Node {
 	top := &html.Node{
 		Type: html.ElementNode,
 		Data: atom.Div.String(),
@@ -389,16 +708,6 @@ func (lw *liteWriter) youtube(n *nodes.YouTubeNode) *html.Node {
 		Data: atom.Div.String(),
 		Attr: []html.Attribute{{Key: "class", Val: "keep-ar__pad"}},
 	}
-	box := &html.Node{
-		Type: html.ElementNode,
-		Data: atom.Iframe.String(),
-		Attr: []html.Attribute{
-			{Key: "src", Val: fmt.Sprintf("https://www.youtube.com/embed/%s?rel=0", n.VideoID)},
-			{Key: "allow", Val: "accelerometer; autoplay; encrypted-media; gyroscope; picture-in-picture"},
-			{Key: "allowfullscreen", Val: "1"},
-			{Key: "class", Val: "keep-ar__box"},
-		},
-	}
 	top.AppendChild(pad)
 	pad.AppendChild(box)
 	return top
@@ -20,17 +20,20 @@ import (
 	"html"
 	"io"
 	"path"
-	"sort"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/googlecodelabs/tools/claat/nodes"
 )
 
 // MD renders nodes as markdown for the target env.
 func MD(ctx Context, nodes ...nodes.Node) (string, error) {
-	var buf bytes.Buffer
-	if err := WriteMD(&buf, ctx.Env, ctx.Format, nodes...); err != nil {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	mw := mdWriter{w: buf, env: ctx.Env, format: ctx.Format, Prefix: []byte(""), anchors: docAnchors(ctx), rtl: ctx.isRTL(), keepRuntimeVars: ctx.KeepRuntimeVars, iconImages: ctx.IconImages}
+	if err := mw.write(nodes...); err != nil {
 		return "", err
 	}
 	return buf.String(), nil
@@ -48,33 +51,54 @@ type mdWriter struct {
 	format             string    // target template
 	err                error     // error during any writeXxx methods
 	lineStart          bool
-	isWritingTableCell bool   // used to override lineStart for correct cell formatting
-	isWritingList      bool   // used for override newblock when needed
-	Prefix             []byte // prefix for e.g. blockquote content
+	spaceEnd           bool     // last written byte is a space rune
+	isWritingTableCell bool     // used to override lineStart for correct cell formatting
+	isWritingList      bool     // used for override newblock when needed
+	Prefix             []byte   // prefix for e.g. blockquote content
+	anchors            *anchors // codelab step and heading anchors, if any
+	rtl                bool     // text is written from right to left
+	tutorial           bool     // writing a Cloud Shell tutorial, see Tutorial
+	keepRuntimeVars    bool     // leave Qwiklabs {{{...}}} runtime expressions unescaped
+	iconImages         string   // URL of icon images, see Context.IconImages
 }
 
 func (mw *mdWriter) writeBytes(b []byte) {
-	if mw.err != nil {
+	if mw.err != nil || len(b) == 0 {
 		return
 	}
 	if mw.lineStart {
 		_, mw.err = mw.w.Write(mw.Prefix)
 	}
-	mw.lineStart = len(b) > 0 && b[len(b)-1] == '\n'
+	mw.lineStart = b[len(b)-1] == '\n'
+	r, _ := utf8.DecodeLastRune(b)
+	mw.spaceEnd = unicode.IsSpace(r)
 	_, mw.err = mw.w.Write(b)
 }
 
 func (mw *mdWriter) writeString(s string) {
-	mw.writeBytes([]byte(s))
+	if mw.err != nil || len(s) == 0 {
+		return
+	}
+	if mw.lineStart {
+		_, mw.err = mw.w.Write(mw.Prefix)
+	}
+	mw.lineStart = s[len(s)-1] == '\n'
+	r, _ := utf8.DecodeLastRuneInString(s)
+	mw.spaceEnd = unicode.IsSpace(r)
+	_, mw.err = io.WriteString(mw.w, s)
 }
 
 func (mw *mdWriter) writeEscape(s string) {
 	s = html.EscapeString(s)
+	if mw.keepRuntimeVars {
+		mw.writeString(replaceDoubleCurlyBracketsKeepingRuntimeExprs(s))
+		return
+	}
 	mw.writeString(ReplaceDoubleCurlyBracketsWithEntity(s))
 }
 
 func (mw *mdWriter) space() {
-	if !mw.lineStart {
+	if !mw.lineStart && !mw.spaceEnd {
 		mw.writeString(" ")
 	}
 }
@@ -86,12 +110,16 @@ func (mw *mdWriter) newBlock() {
 	mw.writeString("\n")
 }
 
-func (mw *mdWriter) matchEnv(v []string) bool {
-	if len(v) == 0 || mw.env == "" {
-		return true
+// bidi starts a block of nn with a right-to-left mark if needed,
+// so that the block is laid out from right to left.
+func (mw *mdWriter) bidi(nn ...nodes.Node) {
+	if mw.rtl && needsRLM(nodes.PlainText(nn...)) {
+		mw.writeString(rlm)
 	}
-	i := sort.SearchStrings(v, mw.env)
-	return i < len(v) && v[i] == mw.env
+}
+
+func (mw *mdWriter) matchEnv(v []string) bool {
+	return nodes.MatchEnv(v, mw.env)
 }
 
 func (mw *mdWriter) write(nodesToWrite ...nodes.Node) error {
@@ -108,15 +136,20 @@ func (mw *mdWriter) write(nodesToWrite ...nodes.Node) error {
 			mw.url(n)
 		case *nodes.ButtonNode:
 			mw.write(n.Content.Nodes...)
+		case *nodes.XrefNode:
+			mw.xref(n)
+		case *nodes.TermNode:
+			mw.term(n)
+		case *nodes.IconNode:
+			mw.icon(n)
+		case *nodes.KbdNode:
+			mw.kbd(n)
 		case *nodes.CodeNode:
 			mw.code(n)
 		case *nodes.ListNode:
 			mw.list(n)
 		case *nodes.ImportNode:
-			if len(n.Content.Nodes) == 0 {
-				break
-			}
-			mw.write(n.Content.Nodes...)
+			mw.importNode(n)
 		case *nodes.ItemsListNode:
 			mw.itemsList(n)
 		case *nodes.GridNode:
@@ -125,10 +158,20 @@ func (mw *mdWriter) write(nodesToWrite ...nodes.Node) error {
 			mw.infobox(n)
 		case *nodes.SurveyNode:
 			mw.survey(n)
+		case *nodes.ActivityNode:
+			mw.activity(n)
+		case *nodes.AttachmentNode:
+			mw.attachment(n)
+		case *nodes.ReviewNode:
+			mw.review(n)
 		case *nodes.HeaderNode:
 			mw.header(n)
 		case *nodes.YouTubeNode:
 			mw.youtube(n)
+		case *nodes.IframeNode:
+			mw.iframe(n)
+		case *nodes.VideoNode:
+			mw.video(n)
 		}
 		if mw.err != nil {
 			return mw.err
@@ -138,9 +181,11 @@ func (mw *mdWriter) write(nodesToWrite ...nodes.Node) error {
 }
 
 func (mw *mdWriter) text(n *nodes.TextNode) {
-	tr := strings.TrimLeft(n.Value, " \t\n\r\f\v")
+	// Emphasis must not start or end with Unicode whitespace,
+	// such as the ideographic space of CJK text.
+	tr := strings.TrimLeftFunc(n.Value, unicode.IsSpace)
 	left := n.Value[0:(len(n.Value) - len(tr))]
-	t := strings.TrimRight(tr, " \t\n\r\f\v")
+	t := strings.TrimRightFunc(tr, unicode.IsSpace)
 	right := tr[len(t):len(tr)]
 
 	mw.writeString(left)
@@ -178,8 +223,14 @@ func (mw *mdWriter) image(n *nodes.ImageNode) {
 	mw.writeString("<img ")
 	mw.writeString(fmt.Sprintf("src=%q ", n.Src))
 
-	if n.Alt != "" {
-		mw.writeString(fmt.Sprintf("alt=%q ", n.Alt))
+	// annotations, which md targets go without, are kept in the alt text,
+	// as they are written; md targets get the light variant of the image only
+	alt := n.Alt
+	for _, a := range n.Annotations {
+		alt = strings.TrimSpace(alt + " " + a.String())
+	}
+	if alt != "" {
+		mw.writeString(fmt.Sprintf("alt=%q ", alt))
 	} else {
 		mw.writeString(fmt.Sprintf("alt=%q ", path.Base(n.Src)))
 	}
@@ -194,14 +245,53 @@ func (mw *mdWriter) image(n *nodes.ImageNode) {
 	}
 
 	mw.writeString("/>")
+
+	// the caption follows as an italic paragraph, which parses back as one
+	if n.Caption != "" {
+		mw.writeString("\n")
+		mw.writeString("\n")
+		mw.writeString("*" + strings.Replace(html.EscapeString(figureCaption(n)), "*", "\\*", -1) + "*")
+	}
+}
+
+// icon is written as an image of Context.IconImages, if set, or its IconText.
+func (mw *mdWriter) icon(n *nodes.IconNode) {
+	if mw.iconImages != "" {
+		mw.image(nodes.NewImageNode(nodes.NewImageNodeOptions{
+			Src:   strings.Replace(mw.iconImages, "{name}", n.Name, -1),
+			Alt:   iconText(n.Name),
+			Title: n.Name,
+			Width: iconSize,
+		}))
+		return
+	}
+	mw.space()
+	mw.writeString(iconText(n.Name))
+}
+
+// kbd writes each key as inline code, joined with "+".
+func (mw *mdWriter) kbd(n *nodes.KbdNode) {
+	for i, k := range n.Keys {
+		if i > 0 {
+			mw.writeString("+")
+		}
+		mw.writeString("`" + k + "`")
+	}
 }
 
 func (mw *mdWriter) url(n *nodes.URLNode) {
+	if mw.tutorial && mw.walkthrough(n) {
+		return
+	}
 	mw.space()
 	if n.URL != "" {
 		// Look-ahead for button syntax.
-		if _, ok := n.Content.Nodes[0].(*nodes.ButtonNode); ok {
-			mw.writeString("<button>")
+		if btn, ok := firstButton(n); ok {
+			if btn.Variant != "" {
+				mw.writeString(fmt.Sprintf("<button variant=%q>", btn.Variant))
+			} else {
+				mw.writeString("<button>")
+			}
 		}
 		mw.writeString("[")
 	}
@@ -213,25 +303,74 @@ func (mw *mdWriter) url(n *nodes.URLNode) {
 		mw.writeString("](")
 		mw.writeString(n.URL)
 		mw.writeString(")")
-		if _, ok := n.Content.Nodes[0].(*nodes.ButtonNode); ok {
+		if _, ok := firstButton(n); ok {
 			// Look-ahead for button syntax.
 			mw.writeString("</button>")
 		}
 	}
 }
 
+// firstButton returns the button starting the content of link n, if any.
+// The content of an empty link, such as [](url), has no nodes.
+func firstButton(n *nodes.URLNode) (*nodes.ButtonNode, bool) {
+	if len(n.Content.Nodes) == 0 {
+		return nil, false
+	}
+	btn, ok := n.Content.Nodes[0].(*nodes.ButtonNode)
+	return btn, ok
+}
+
+// xref links to the anchor of the target step heading.
+func (mw *mdWriter) xref(n *nodes.XrefNode) {
+	href := "#" + nodes.Slugify(nodes.PlainText(n.Content.Nodes...))
+	if id, _, ok := mw.anchors.step(n.Step); ok {
+		href = mw.anchors.href(mw.anchors.all[n.Step-1], id)
+	}
+	mw.space()
+	mw.writeString("[")
+	mw.write(n.Content.Nodes...)
+	mw.writeString("](")
+	mw.writeString(href)
+	mw.writeString(")")
+}
+
+// term links a glossary term to the glossary step, if any.
+func (mw *mdWriter) term(n *nodes.TermNode) {
+	if mw.anchors == nil || mw.anchors.glossary == "" {
+		mw.write(n.Content.Nodes...)
+		return
+	}
+	mw.space()
+	mw.writeString("[")
+	mw.write(n.Content.Nodes...)
+	mw.writeString("](")
+	mw.writeString(mw.anchors.href(mw.anchors.glossaryStep, mw.anchors.glossary))
+	mw.writeString(")")
+}
+
 func (mw *mdWriter) code(n *nodes.CodeNode) {
 	if n.Empty() {
 		return
 	}
 	mw.newBlock()
 	defer mw.writeString("\n")
+	// Code block attributes are kept by a Qwiklabs code block
+	// wrapping the fenced code, which Cloud Shell tutorials do not know.
+	if attrs := codeBlockAttrs(n); attrs != "" && !mw.tutorial {
+		mw.writeString("<ql-code-block" + attrs + ">\n\n")
+		defer mw.writeString("\n\n</ql-code-block>")
+	}
 	mw.writeString("```")
-	if n.Term {
+	if mw.tutorial {
+		mw.writeString(tutorialLang(n))
+	} else if n.Term {
 		mw.writeString("console")
 	} else {
 		mw.writeString(n.Lang)
 	}
+	if n.Runnable && !mw.tutorial {
+		mw.writeString(" runnable")
+	}
 	mw.writeString("\n")
 	mw.writeString(n.Value)
 	if !mw.lineStart {
@@ -240,9 +379,32 @@ func (mw *mdWriter) code(n *nodes.CodeNode) {
 	mw.writeString("```")
 }
 
+// codeBlockAttrs returns the attributes of a Qwiklabs code block
+// wrapping code n, with a leading space, or "" if n needs no wrapper.
+func codeBlockAttrs(n *nodes.CodeNode) string {
+	var attrs string
+	if n.Diff {
+		attrs += " diff"
+	}
+	if len(n.Highlight) > 0 {
+		attrs += fmt.Sprintf(" highlight=%q", nodes.FormatLineRanges(n.Highlight))
+	}
+	if n.LineNumbers {
+		attrs += " line-numbers"
+	}
+	if n.NoCopy {
+		attrs += " noCopy"
+	}
+	if n.Output {
+		attrs += " output"
+	}
+	return attrs
+}
+
 func (mw *mdWriter) list(n *nodes.ListNode) {
 	if n.Block() == true {
 		mw.newBlock()
+		mw.bidi(n.Nodes...)
 	}
 	mw.write(n.Nodes...)
 	if !mw.lineStart && !mw.isWritingTableCell {
@@ -250,17 +412,36 @@ func (mw *mdWriter) list(n *nodes.ListNode) {
 	}
 }
 
+// importNode writes the imported content, or the import statement itself
+// if the content has not been fetched, so that it survives a md to md export.
+func (mw *mdWriter) importNode(n *nodes.ImportNode) {
+	if len(n.Content.Nodes) > 0 {
+		mw.write(n.Content.Nodes...)
+		return
+	}
+	if n.URL == "" {
+		return
+	}
+	mw.newBlock()
+	mw.writeString("<<" + n.URL + ">>\n")
+}
+
 func (mw *mdWriter) itemsList(n *nodes.ItemsListNode) {
 	mw.isWritingList = true
 	if n.Block() == true {
 		mw.newBlock()
 	}
+	start := n.Start
+	if start < 1 {
+		start = 1
+	}
 	for i, item := range n.Items {
 		s := "* "
-		if n.Type() == nodes.NodeItemsList && n.Start > 0 {
-			s = strconv.Itoa(i+n.Start) + ". "
+		if n.Type() == nodes.NodeItemsList && (n.Start > 0 || n.ListType != "") {
+			s = strconv.Itoa(i+start) + ". "
 		}
 		mw.writeString(s)
+		mw.bidi(item.Nodes...)
 		mw.write(item.Nodes...)
 		if !mw.lineStart {
 			mw.writeString("\n")
@@ -276,8 +457,11 @@ func (mw *mdWriter) infobox(n *nodes.InfoboxNode) {
 	// directly and don't write the ListNode itself.
 	mw.newBlock()
 	k := "aside positive"
-	if n.Kind == nodes.InfoboxNegative {
+	switch n.Kind {
+	case nodes.InfoboxNegative:
 		k = "aside negative"
+	case nodes.InfoboxInstructor:
+		k = "aside instructor"
 	}
 	mw.Prefix = []byte("> ")
 	mw.writeString(k)
@@ -290,29 +474,145 @@ func (mw *mdWriter) infobox(n *nodes.InfoboxNode) {
 	mw.Prefix = []byte("")
 }
 
+// survey writes scoring metadata of quizzes along with the questions,
+// for the codelab platform to grade answers.
 func (mw *mdWriter) survey(n *nodes.SurveyNode) {
 	mw.newBlock()
-	mw.writeString("<form>")
+	mw.writeString("<form")
+	if n.Pass > 0 {
+		mw.writeString(fmt.Sprintf(" pass=\"%d\"", n.Pass))
+	}
+	mw.writeString(">")
 	mw.writeString("\n")
 	for _, g := range n.Groups {
-		mw.writeString("<name>")
+		mw.writeString("<name")
+		if g.Points > 0 {
+			mw.writeString(fmt.Sprintf(" points=\"%d\"", g.Points))
+		}
+		mw.writeString(">")
 		mw.writeEscape(g.Name)
 		mw.writeString("</name>")
 		mw.writeString("\n")
-		for _, o := range g.Options {
-			mw.writeString("<input value=\"")
-			mw.writeEscape(o)
-			mw.writeString("\">")
-			mw.writeString("\n")
+		switch g.Kind {
+		case nodes.SurveyRating:
+			if len(g.Options) > 0 {
+				mw.writeString(fmt.Sprintf("<input type=\"range\" min=%q max=%q", g.Options[0], g.Options[len(g.Options)-1]))
+				if len(g.Correct) > 0 {
+					mw.writeString(" correct=\"")
+					mw.writeEscape(g.Correct[0])
+					mw.writeString("\"")
+				}
+				mw.writeString(">\n")
+			}
+		case nodes.SurveyText:
+			mw.writeString("<input type=\"text\">\n")
+		default:
+			for _, o := range g.Options {
+				mw.writeString("<input ")
+				if g.Kind == nodes.SurveyMulti {
+					mw.writeString("type=\"checkbox\" ")
+				}
+				mw.writeString("value=\"")
+				mw.writeEscape(o)
+				mw.writeString("\"")
+				if isCorrect(g, o) {
+					mw.writeString(" correct")
+				}
+				mw.writeString(">")
+				mw.writeString("\n")
+			}
 		}
 	}
 	mw.writeString("</form>")
 }
 
+// activity writes a Qwiklabs activity tracking block.
+// The step attribute is left out if n is not numbered yet.
+func (mw *mdWriter) activity(n *nodes.ActivityNode) {
+	mw.newBlock()
+	mw.writeString("<ql-activity-tracking")
+	if n.Step > 0 {
+		mw.writeString(fmt.Sprintf(" step=%d", n.Step))
+	}
+	mw.writeString(">\n")
+	mw.rawText(n.Content.Nodes...)
+	mw.writeString("</ql-activity-tracking>")
+}
+
+// attachment writes a Qwiklabs file download block.
+// The name attribute is left out if it is the file name of the URL.
+func (mw *mdWriter) attachment(n *nodes.AttachmentNode) {
+	mw.newBlock()
+	mw.writeString(fmt.Sprintf("<ql-file-download url=%q", n.Src))
+	if n.Name != "" {
+		mw.writeString(" name=\"")
+		mw.writeEscape(n.Name)
+		mw.writeString("\"")
+	}
+	mw.writeString(">\n")
+	mw.rawText(n.Content.Nodes...)
+	mw.writeString("</ql-file-download>")
+}
+
+// review writes inserted content between "inserted" comments, and
+// the text and code of deleted content in a "deleted" comment, so that
+// it is not rendered by Markdown viewers.
+func (mw *mdWriter) review(n *nodes.ReviewNode) {
+	mw.newBlock()
+	if n.Kind == nodes.ReviewInserted {
+		mw.writeString("<!-- inserted -->\n")
+		mw.write(n.Content.Nodes...)
+		mw.newBlock()
+		mw.writeString("<!-- /inserted -->")
+		return
+	}
+	var b strings.Builder
+	nodes.WalkNodes(n.Content.Nodes, func(cn nodes.Node, entering bool) (nodes.Node, error) {
+		switch cn := cn.(type) {
+		case *nodes.ListNode:
+			if !entering {
+				b.WriteString("\n")
+			}
+		case *nodes.TextNode:
+			if entering {
+				b.WriteString(cn.Value)
+			}
+		case *nodes.CodeNode:
+			if entering {
+				b.WriteString(cn.Value)
+			}
+		}
+		return cn, nil
+	})
+	mw.writeString("<!-- deleted:\n")
+	mw.writeString(strings.ReplaceAll(strings.TrimSpace(b.String()), "--", "- -"))
+	mw.writeString("\n-->")
+}
+
+// rawText writes the plain text of nn on its own line, as the content
+// of an HTML block, which is not formatted by Markdown viewers.
+func (mw *mdWriter) rawText(nn ...nodes.Node) {
+	if s := strings.TrimSpace(nodes.PlainText(nn...)); s != "" {
+		mw.writeEscape(s)
+		mw.writeString("\n")
+	}
+}
+
+// isCorrect reports whether o is a correct option of quiz question g.
+func isCorrect(g *nodes.SurveyGroup, o string) bool {
+	for _, c := range g.Correct {
+		if c == o {
+			return true
+		}
+	}
+	return false
+}
+
 func (mw *mdWriter) header(n *nodes.HeaderNode) {
 	mw.newBlock()
 	mw.writeString(strings.Repeat("#", n.Level+1))
 	mw.writeString(" ")
+	mw.bidi(n.Content.Nodes...)
 	mw.write(n.Content.Nodes...)
 	if !mw.lineStart {
 		mw.writeString("\n")
@@ -323,7 +623,41 @@ func (mw *mdWriter) youtube(n *nodes.YouTubeNode) {
 	if !mw.isWritingList {
 		mw.newBlock()
 	}
-	mw.writeString(fmt.Sprintf(`<video id="%s"></video>`, n.VideoID))
+	mw.writeString(fmt.Sprintf(`<video id="%s"`, n.VideoID))
+	if n.Start > 0 {
+		mw.writeString(fmt.Sprintf(` start="%d"`, n.Start))
+	}
+	if n.Captions != "" {
+		mw.writeString(fmt.Sprintf(` captions="%s"`, html.EscapeString(n.Captions)))
+	}
+	if n.NoCookie {
+		mw.writeString(" nocookie")
+	}
+	mw.writeString("></video>")
+}
+
+// video writes a Qwiklabs video block.
+func (mw *mdWriter) video(n *nodes.VideoNode) {
+	mw.newBlock()
+	mw.writeString(fmt.Sprintf("<ql-video src=%q", n.Src))
+	for _, a := range [][2]string{
+		{"poster", n.Poster},
+		{"captions", n.Captions},
+		{"srclang", n.CaptionsLang},
+	} {
+		if a[1] != "" {
+			mw.writeString(fmt.Sprintf(" %s=%q", a[0], a[1]))
+		}
+	}
+	mw.writeString("></ql-video>")
+}
+
+// iframe is written as an image with the embedded URL in its alt text,
+// which is how the Markdown parser recognizes an embedded iframe.
+func (mw *mdWriter) iframe(n *nodes.IframeNode) {
+	mw.newBlock()
+	mw.writeString(fmt.Sprintf("![%s](%s)", n.URL, n.URL))
+	mw.writeString("\n")
 }
 
 func (mw *mdWriter) table(n *nodes.GridNode) {
@@ -332,32 +666,44 @@ func (mw *mdWriter) table(n *nodes.GridNode) {
 		return
 	}
 
-	mw.writeString("\n")
+	// Measure cells first, so that columns can be padded to the display width
+	// of their widest cell, counting wide CJK characters as two columns.
+	// Cells are rendered again as they are written rather than kept around,
+	// so that even huge tables are streamed to the output.
 	maxcols := maxColsInTable(n)
+	cells := make([][]mdCell, len(n.Rows))
+	widths := make([]int, maxcols)
 	for rowIndex, row := range n.Rows {
-		mw.writeString("|")
-		for _, cell := range row {
-			mw.isWritingTableCell = true
-			mw.writeString(" ")
-
-			// Check cell content for newlines and replace with inline HTML if newlines are present.
-			var nw bytes.Buffer
-			WriteMD(&nw, mw.env, mw.format, cell.Content.Nodes...)
-			if bytes.ContainsRune(nw.Bytes(), '\n') {
-				for _, cn := range cell.Content.Nodes {
-					cn.MutateBlock(false) // don't treat content as a new block
-					var nw2 bytes.Buffer
-					WriteHTML(&nw2, mw.env, mw.format, cn)
-					mw.writeBytes(bytes.Replace(nw2.Bytes(), []byte("\n"), []byte(""), -1))
-				}
-			} else {
-				mw.writeBytes(nw.Bytes())
+		cells[rowIndex] = make([]mdCell, len(row))
+		for i, cell := range row {
+			c := mw.measureCell(cell)
+			cells[rowIndex][i] = c
+			if c.width > widths[i] {
+				widths[i] = c.width
 			}
+		}
+	}
+	for i, w := range widths {
+		if w < 3 {
+			widths[i] = 3 // header separators are at least "---"
+		}
+	}
 
+	mw.writeString("\n")
+	for rowIndex, row := range n.Rows {
+		mw.isWritingTableCell = true
+		mw.writeString("|")
+		for i, cell := range row {
+			c := cells[rowIndex][i]
+			mw.writeString(" ")
+			mw.tableCell(cell, c.html)
+			mw.writeString(strings.Repeat(" ", widths[i]-c.width))
 			mw.writeString(" |")
 		}
 		if rowIndex == 0 && len(row) < maxcols {
-			for i := 0; i < maxcols-len(row); i++ {
+			for i := len(row); i < maxcols; i++ {
+				mw.writeString(" ")
+				mw.writeString(strings.Repeat(" ", widths[i]))
 				mw.writeString(" |")
 			}
 		}
@@ -367,7 +713,9 @@ func (mw *mdWriter) table(n *nodes.GridNode) {
 		if rowIndex == 0 {
 			mw.writeString("|")
 			for i := 0; i < maxcols; i++ {
-				mw.writeString(" --- |")
+				mw.writeString(" ")
+				mw.writeString(strings.Repeat("-", widths[i]))
+				mw.writeString(" |")
 			}
 			mw.writeString("\n")
 		}
@@ -376,6 +724,92 @@ func (mw *mdWriter) table(n *nodes.GridNode) {
 	}
 }
 
+// mdCell is the measure of a table cell, as written by tableCell.
+type mdCell struct {
+	width int  // display width of the cell content
+	html  bool // content is written as inline HTML
+}
+
+// measureCell renders cell without keeping the output, to find its width
+// and whether it has to be written as inline HTML to fit on a single line.
+func (mw *mdWriter) measureCell(cell *nodes.GridCell) mdCell {
+	var ww widthWriter
+	mw.cellMD(&ww, cell)
+	if !ww.newline {
+		return mdCell{width: ww.width}
+	}
+	ww = widthWriter{}
+	cellHTML(&ww, mw.env, mw.format, cell)
+	return mdCell{width: ww.width, html: true}
+}
+
+// tableCell writes the content of a table cell on a single line,
+// as inline HTML if the markdown content has newlines.
+func (mw *mdWriter) tableCell(cell *nodes.GridCell, html bool) {
+	if html {
+		cellHTML(mw, mw.env, mw.format, cell)
+		return
+	}
+	mw.cellMD(mw, cell)
+}
+
+// cellMD writes the content of cell as markdown to w.
+func (mw *mdWriter) cellMD(w io.Writer, cell *nodes.GridCell) {
+	cw := mdWriter{w: w, env: mw.env, format: mw.format, iconImages: mw.iconImages}
+	cw.write(cell.Content.Nodes...)
+}
+
+// Write makes mdWriter an io.Writer, so that nested content,
+// such as table cells, is rendered straight to the output.
+func (mw *mdWriter) Write(b []byte) (int, error) {
+	mw.writeBytes(b)
+	if mw.err != nil {
+		return 0, mw.err
+	}
+	return len(b), nil
+}
+
+func (mw *mdWriter) WriteString(s string) (int, error) {
+	mw.writeString(s)
+	if mw.err != nil {
+		return 0, mw.err
+	}
+	return len(s), nil
+}
+
+// cellHTML writes the content of cell as HTML without newlines.
+func cellHTML(w io.Writer, env, format string, cell *nodes.GridCell) {
+	nw := newlineStripper{w}
+	for _, cn := range cell.Content.Nodes {
+		cn.MutateBlock(false) // don't treat content as a new block
+		WriteHTML(nw, env, format, cn)
+	}
+}
+
+// newlineStripper writes to w everything written to it, except newlines.
+type newlineStripper struct {
+	w io.Writer
+}
+
+func (s newlineStripper) Write(b []byte) (int, error) {
+	n := len(b)
+	for len(b) > 0 {
+		line := b
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			line, b = b[:i], b[i+1:]
+		} else {
+			b = nil
+		}
+		if len(line) == 0 {
+			continue
+		}
+		if _, err := s.w.Write(line); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
 func maxColsInTable(n *nodes.GridNode) int {
 	m := 0
 	for _, row := range n.Rows {
@@ -0,0 +1,166 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/parser"
+	mdParse "github.com/googlecodelabs/tools/claat/parser/md"
+	"github.com/googlecodelabs/tools/claat/transform"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// exportMD parses Markdown src and renders it back with the md template.
+func exportMD(t *testing.T, src []byte) (*types.Codelab, string) {
+	t.Helper()
+	clab, err := (&mdParse.Parser{}).Parse(context.Background(), bytes.NewReader(src), *parser.NewOptions())
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var buf bytes.Buffer
+	data := &struct{ Context }{Context{Env: "web", Format: "md", Meta: &clab.Meta, Steps: clab.Steps}}
+	if err := Execute(&buf, "md", data); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	return clab, buf.String()
+}
+
+// nodeTypes returns node types of a step content, recursively.
+func nodeTypes(nn []nodes.Node) []nodes.NodeType {
+	var types []nodes.NodeType
+	nodes.WalkNodes(nn, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if entering {
+			types = append(types, n.Type())
+		}
+		return n, nil
+	})
+	return types
+}
+
+func TestMDRoundtrip(t *testing.T) {
+	src, err := ioutil.ReadFile("testdata/roundtrip.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clab1, out1 := exportMD(t, src)
+	clab2, out2 := exportMD(t, []byte(out1))
+
+	if diff := cmp.Diff(out1, out2); diff != "" {
+		t.Errorf("md export is not stable (-first +second):\n%s", diff)
+	}
+	if len(clab1.Steps) != len(clab2.Steps) {
+		t.Fatalf("roundtrip has %d steps, want %d", len(clab2.Steps), len(clab1.Steps))
+	}
+	for i := range clab1.Steps {
+		s1, s2 := clab1.Steps[i], clab2.Steps[i]
+		if s1.Title != s2.Title || s1.Duration != s2.Duration || s1.Optional != s2.Optional || !cmp.Equal(s1.Products, s2.Products) {
+			t.Errorf("step %d: got %q (%v), want %q (%v)", i, s2.Title, s2.Duration, s1.Title, s1.Duration)
+		}
+		if diff := cmp.Diff(nodeTypes(s1.Content.Nodes), nodeTypes(s2.Content.Nodes)); diff != "" {
+			t.Errorf("step %d node types got diff (-want +got):\n%s", i, diff)
+		}
+	}
+
+	for _, want := range []string{
+		"status: draft\n",
+		"prerequisites: gcs-basics,https://example.com/labs/iam\n",
+		"next_labs: bigquery-intro\n",
+		"image: https://example.com/card.png\n",
+		"## Cleanup\nDuration: 01:00\n\nOptional: yes\n\nProducts: Cloud Storage, BigQuery\n",
+		"1. first\n2. second\n",
+		"```go\n",
+		"```bash runnable\necho hello\n```",
+		"<ql-code-block highlight=\"1\" line-numbers>\n\n```go\nx := 1\n",
+		"<ql-code-block output>\n\n```console\nfile.txt\n```\n\n</ql-code-block>",
+		"<ql-code-block diff>\n\n```go\n-fmt.Println(\"x\")\n+fmt.Println(\"y\")\n```\n\n</ql-code-block>",
+		"![https://codepen.io/foo](https://codepen.io/foo)",
+		"<<cleanup.md>>",
+		"<name>Topics</name>\n<input type=\"checkbox\" value=\"Go\">\n",
+		"<name>Rate</name>\n<input type=\"range\" min=\"1\" max=\"5\">\n",
+		"<name>Comments</name>\n<input type=\"text\">\n",
+		"<form pass=\"2\">\n<name points=\"2\">Which command lists files?</name>\n<input value=\"ls\" correct>\n<input value=\"cd\">\n",
+		"<input type=\"checkbox\" value=\"zsh\" correct>\n",
+		"<input type=\"range\" min=\"1\" max=\"5\" correct=\"5\">\n",
+		"<ql-activity-tracking step=1>\nCreate a bucket\n</ql-activity-tracking>",
+		"<ql-activity-tracking>\nUpload an object\n</ql-activity-tracking>",
+		"<ql-file-download url=\"files/starter.zip\">\nStarter code\n</ql-file-download>",
+		"<ql-file-download url=\"https://example.com/dl?id=1\" name=\"data.csv\">\n</ql-file-download>",
+		"<button variant=\"secondary\">[Read more](https://example.com/docs)</button>",
+		"<button variant=\"console\">[Open Google Cloud console](https://console.cloud.google.com/)</button>",
+		"<video id=\"vid\" start=\"90\" captions=\"fr\" nocookie></video>",
+		"<ql-video src=\"https://storage.googleapis.com/bucket/intro.mp4\" poster=\"intro.png\" captions=\"intro.vtt\" srclang=\"en\"></ql-video>",
+	} {
+		if !strings.Contains(out1, want) {
+			t.Errorf("md export does not contain %q:\n%s", want, out1)
+		}
+	}
+}
+
+func TestMDReview(t *testing.T) {
+	text := func(s string) nodes.Node {
+		return nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: s}))
+	}
+	got, err := MD(Context{},
+		nodes.NewReviewNode(nodes.ReviewInserted, text("New text.")),
+		nodes.NewReviewNode(nodes.ReviewDeleted, text("Old text."), nodes.NewCodeNode("rm -rf tmp --force\n", true, "")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "\n\n<!-- inserted -->\nNew text.\n\n<!-- /inserted -->\n\n<!-- deleted:\nOld text.\nrm -rf tmp - -force\n-->"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MD(review) got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestMDRuntimeVars(t *testing.T) {
+	// {{{name}}} runtime expressions are left as is by -vars substitution
+	// and only escaped in Markdown output without -keep_runtime_vars.
+	tests := []struct {
+		keep bool
+		out  string
+	}{
+		{false, "\n\n<ql-activity-tracking step=1>\n&#123;&#123;{project_0.project_id}}} in &#123;&#123;zone}}\n</ql-activity-tracking>"},
+		{true, "\n\n<ql-activity-tracking step=1>\n{{{project_0.project_id}}} in &#123;&#123;zone}}\n</ql-activity-tracking>"},
+	}
+	for _, tc := range tests {
+		in := transform.SubstituteString("{{{project_0.project_id}}} in {{zone}}", transform.VarsOptions{Vars: map[string]string{"project_0.project_id": "p"}})
+		text := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: in})
+		got, err := MD(Context{KeepRuntimeVars: tc.keep}, nodes.NewActivityNode(1, text))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.out {
+			t.Errorf("MD(KeepRuntimeVars: %v) = %q; want %q", tc.keep, got, tc.out)
+		}
+	}
+}
+
+func TestMDEmptyLink(t *testing.T) {
+	got, err := MD(Context{}, nodes.NewURLNode("https://example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := " [](https://example.com)"; got != want {
+		t.Errorf("MD(empty link) = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// darkMedia is the media query of the dark variants of images.
+const darkMedia = "(prefers-color-scheme: dark)"
+
+// picture returns a picture element holding img, the image node of n,
+// preceded by the source of its dark variant.
+func picture(img *html.Node, n *nodes.ImageNode) *html.Node {
+	top := &html.Node{Type: html.ElementNode, Data: atom.Picture.String()}
+	top.AppendChild(&html.Node{
+		Type: html.ElementNode,
+		Data: atom.Source.String(),
+		Attr: []html.Attribute{
+			{Key: "srcset", Val: n.Dark},
+			{Key: "media", Val: darkMedia},
+		},
+	})
+	top.AppendChild(img)
+	return top
+}
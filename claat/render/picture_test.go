@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+func TestPicture(t *testing.T) {
+	img := nodes.NewImageNode(nodes.NewImageNodeOptions{Src: "img/light.png", Alt: "Console"})
+	img.Dark = "img/dark.png"
+
+	h, err := HTML(Context{}, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<picture><source srcset="img/dark.png" media="(prefers-color-scheme: dark)"><img alt="Console" src="img/light.png"></picture>`
+	if diff := cmp.Diff(want, string(h)); diff != "" {
+		t.Errorf("HTML got diff (-want +got):\n%s", diff)
+	}
+
+	l, err := Lite(Context{}, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `<picture><source srcset="img/dark.png" media="(prefers-color-scheme: dark)"/><img src="img/light.png"/></picture>`
+	if diff := cmp.Diff(want, string(l)); diff != "" {
+		t.Errorf("Lite got diff (-want +got):\n%s", diff)
+	}
+
+	md, err := MD(Context{}, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = ` <img src="img/light.png" alt="Console" />`
+	if diff := cmp.Diff(want, md); diff != "" {
+		t.Errorf("MD got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestPictureEscape(t *testing.T) {
+	img := nodes.NewImageNode(nodes.NewImageNodeOptions{Src: "img/light.png"})
+	img.Dark = `img/dark.png"><script>alert(1)</script>`
+	h, err := HTML(Context{}, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<picture><source srcset="img/dark.png&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;" media="(prefers-color-scheme: dark)"><img src="img/light.png"></picture>`
+	if diff := cmp.Diff(want, string(h)); diff != "" {
+		t.Errorf("HTML got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestSanitizeSrcset(t *testing.T) {
+	in := `<picture><source srcset="javascript:alert(1)" media="(prefers-color-scheme: dark)"><img src="a.png"></picture>`
+	want := `<picture><source media="(prefers-color-scheme: dark)"><img src="a.png"></picture>`
+	if diff := cmp.Diff(want, DefaultSanitizePolicy.Sanitize(in)); diff != "" {
+		t.Errorf("Sanitize got diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	htmlTemplate "html/template"
+	"sort"
+)
+
+// PaperSizes are the CSS page sizes of the paper names of printed HTML output.
+var PaperSizes = map[string]string{
+	"a4":     "A4",
+	"letter": "letter",
+}
+
+// Papers returns the names of PaperSizes, sorted.
+func Papers() []string {
+	var names []string
+	for name := range PaperSizes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// paperMargin is the margin of printed pages of a known paper size.
+const paperMargin = "2cm"
+
+// printCSS prints codelabs cleanly: each step of html output starts a page,
+// navigation is hidden, and headings, code blocks, images and other blocks
+// are not split across pages.
+const printCSS = `
+    body {
+      background: #fff;
+      color: #000;
+    }
+    google-codelab-step {
+      display: block !important;
+      break-before: page;
+    }
+    google-codelab-step:first-of-type {
+      break-before: auto;
+    }
+    google-codelab-analytics,
+    .codelab__toc,
+    .step__header,
+    .codelab-nav,
+    .codelab-pager,
+    .lightbox-overlay {
+      display: none !important;
+    }
+    h1, h2, h3, h4, h5, h6 {
+      break-after: avoid;
+    }
+    pre, table, figure, img, aside, .image-container, .image-annotated {
+      break-inside: avoid;
+    }
+    p a[href^="http"]::after,
+    li a[href^="http"]::after {
+      content: " (" attr(href) ")";
+      font-size: 0.8em;
+    }
+`
+
+// printStyle returns the print stylesheet of HTML output,
+// with pages of paper size, one of PaperSizes, if not empty.
+func printStyle(paper string) htmlTemplate.CSS {
+	css := printCSS
+	if size, ok := PaperSizes[paper]; ok {
+		css = fmt.Sprintf("\n    @page {\n      size: %s;\n      margin: %s;\n    }", size, paperMargin) + css
+	}
+	return htmlTemplate.CSS(css)
+}
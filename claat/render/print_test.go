@@ -0,0 +1,68 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestExecutePrint(t *testing.T) {
+	step := &types.Step{
+		Title:   "Set up",
+		Content: nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "text"})),
+	}
+	tests := []struct {
+		paper string
+		page  string
+	}{
+		{"", ""},
+		{"a4", "size: A4;"},
+		{"letter", "size: letter;"},
+	}
+	for _, tc := range tests {
+		data := &struct {
+			Context
+			Current *types.Step
+			StepNum int
+			Prev    bool
+			Next    bool
+		}{Context: Context{
+			Meta:  &types.Meta{ID: "lab"},
+			Steps: []*types.Step{step},
+			Paper: tc.paper,
+		}, Current: step, StepNum: 1}
+		for _, f := range []string{"html", "offline", "html-step", "html-steps"} {
+			var buf bytes.Buffer
+			if err := Execute(&buf, f, data); err != nil {
+				t.Fatalf("%s: %v", f, err)
+			}
+			out := buf.String()
+			if !strings.Contains(out, `<style media="print">`) || !strings.Contains(out, "break-inside: avoid;") {
+				t.Errorf("%s output has no print stylesheet:\n%s", f, out)
+			}
+			if got := strings.Contains(out, "@page"); got != (tc.page != "") {
+				t.Errorf("%s output with paper %q has @page: %v; want %v", f, tc.paper, got, !got)
+			}
+			if tc.page != "" && !strings.Contains(out, tc.page) {
+				t.Errorf("%s output does not contain %s", f, tc.page)
+			}
+		}
+	}
+}
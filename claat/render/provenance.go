@@ -0,0 +1,43 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// MetaTag is a <meta name content> tag of HTML output.
+type MetaTag struct {
+	Name, Content string
+}
+
+// ProvenanceMeta returns the meta tags tracing a codelab back to its source
+// revision and export, from provenance p, which may be nil.
+func ProvenanceMeta(p *types.Provenance) []MetaTag {
+	if p == nil {
+		return nil
+	}
+	tags := []MetaTag{{"claat:source", p.Source}}
+	if p.Revision != "" {
+		tags = append(tags, MetaTag{"claat:revision", p.Revision})
+	}
+	return append(tags,
+		MetaTag{"claat:version", p.Version},
+		MetaTag{"claat:profile", p.Profile},
+		MetaTag{"claat:exported", time.Time(p.Exported).Format(time.RFC3339)},
+	)
+}
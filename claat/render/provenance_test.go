@@ -0,0 +1,83 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestProvenanceMeta(t *testing.T) {
+	exported := types.ContextTime(time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC))
+	tests := []struct {
+		name string
+		p    *types.Provenance
+		want []MetaTag
+	}{
+		{"nil", nil, nil},
+		{
+			name: "no revision",
+			p:    &types.Provenance{Source: "1aBc", Version: "v2", Profile: "format=html env=web", Exported: exported},
+			want: []MetaTag{
+				{"claat:source", "1aBc"},
+				{"claat:version", "v2"},
+				{"claat:profile", "format=html env=web"},
+				{"claat:exported", "2026-05-06T07:08:09Z"},
+			},
+		},
+		{
+			name: "revision",
+			p:    &types.Provenance{Source: "lab.md", Revision: "abc123", Version: "v2", Exported: exported},
+			want: []MetaTag{
+				{"claat:source", "lab.md"},
+				{"claat:revision", "abc123"},
+				{"claat:version", "v2"},
+				{"claat:profile", ""},
+				{"claat:exported", "2026-05-06T07:08:09Z"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, ProvenanceMeta(tc.p)); diff != "" {
+				t.Errorf("ProvenanceMeta got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExecuteProvenance(t *testing.T) {
+	ctx := &Context{
+		Format: "html",
+		Meta:   &types.Meta{Title: "Lab"},
+		Provenance: &types.Provenance{
+			Source:  `https://example.com/lab.md?a=1&b="2"`,
+			Version: "v2",
+		},
+	}
+	var buf bytes.Buffer
+	if err := Execute(&buf, "html", ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := `<meta name="claat:source" content="https://example.com/lab.md?a=1&amp;b=&#34;2&#34;">`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("Execute output has no %s", want)
+	}
+}
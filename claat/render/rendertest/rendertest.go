@@ -0,0 +1,131 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rendertest provides golden-file tests of codelab renderers,
+// so that custom renderers can be tested the same way as the built-in ones.
+//
+// A test loads codelab fixtures, sources parsed to their AST, renders them,
+// and compares the output with golden files next to them. Tests usually
+// write the golden files instead when run with an -update flag of their own:
+//
+//	var update = flag.Bool("update", false, "write golden files")
+//
+//	func TestGolden(t *testing.T) {
+//		rendertest.Run(t, "testdata/*.md", "html", *update, renderHTML)
+//	}
+package rendertest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/parser"
+	"github.com/googlecodelabs/tools/claat/types"
+
+	// allow parsers to register themselves
+	_ "github.com/googlecodelabs/tools/claat/parser/gdoc"
+	_ "github.com/googlecodelabs/tools/claat/parser/html"
+	_ "github.com/googlecodelabs/tools/claat/parser/md"
+	_ "github.com/googlecodelabs/tools/claat/parser/notion"
+)
+
+// Renderer renders the AST of codelab clab into w.
+type Renderer func(w io.Writer, clab *types.Codelab) error
+
+// Load parses the codelab fixture file into its AST, with the parser named
+// after the file extension: lab.md is parsed by the "md" parser,
+// lab.html or lab.htm by "html", lab.gdocapi by "gdocapi", and so on.
+func Load(t testing.TB, file string) *types.Codelab {
+	t.Helper()
+	name := strings.TrimPrefix(filepath.Ext(file), ".")
+	if name == "htm" {
+		name = "html"
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	clab, err := parser.Parse(context.Background(), name, f, *parser.NewOptions())
+	if err != nil {
+		t.Fatalf("%s: %v", file, err)
+	}
+	return clab
+}
+
+// Golden returns the golden file of fixture file for format,
+// e.g. testdata/lab.html.golden for testdata/lab.md and html.
+func Golden(file, format string) string {
+	return strings.TrimSuffix(file, filepath.Ext(file)) + "." + format + ".golden"
+}
+
+// Compare compares got with the content of the golden file,
+// or writes got to the golden file if update is true.
+func Compare(t testing.TB, golden string, got []byte, update bool) {
+	t.Helper()
+	if err := compare(golden, got, update); err != nil {
+		t.Error(err)
+	}
+}
+
+// compare implements Compare, writing golden if update is true.
+func compare(golden string, got []byte, update bool) error {
+	if update {
+		return ioutil.WriteFile(golden, got, 0644)
+	}
+	want, err := ioutil.ReadFile(golden)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%s does not exist; run the test with -update to create it", golden)
+	}
+	if err != nil {
+		return err
+	}
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		return fmt.Errorf("%s differs (-want +got), run the test with -update if expected:\n%s", golden, diff)
+	}
+	return nil
+}
+
+// Run runs a subtest for each fixture matching pattern, e.g. "testdata/*.md",
+// comparing its AST rendered with r to its golden file for format,
+// or writing the golden file if update is true.
+func Run(t *testing.T, pattern, format string, update bool, r Renderer) {
+	t.Helper()
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no fixtures match %s", pattern)
+	}
+	for _, file := range files {
+		file := file
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			clab := Load(t, file)
+			var buf bytes.Buffer
+			if err := r(&buf, clab); err != nil {
+				t.Fatalf("render: %v", err)
+			}
+			Compare(t, Golden(file, format), buf.Bytes(), update)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rendertest
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		file, format, want string
+	}{
+		{"testdata/lab.md", "html", "testdata/lab.html.golden"},
+		{"testdata/lab.md", "md", "testdata/lab.md.golden"},
+		{"lab", "md", "lab.md.golden"},
+	}
+	for _, tc := range tests {
+		if got := Golden(tc.file, tc.format); got != tc.want {
+			t.Errorf("Golden(%q, %q) = %q; want %q", tc.file, tc.format, got, tc.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	golden := filepath.Join(t.TempDir(), "lab.md.golden")
+	if err := compare(golden, []byte("a\n"), false); err == nil || !strings.Contains(err.Error(), "-update") {
+		t.Errorf("compare() of a missing golden file = %v; want a hint to -update", err)
+	}
+	if err := compare(golden, []byte("a\n"), true); err != nil {
+		t.Fatalf("compare() with update: %v", err)
+	}
+	if b, _ := ioutil.ReadFile(golden); string(b) != "a\n" {
+		t.Errorf("golden file = %q; want %q", b, "a\n")
+	}
+	if err := compare(golden, []byte("a\n"), false); err != nil {
+		t.Errorf("compare() of the same output = %v; want nil", err)
+	}
+	if err := compare(golden, []byte("b\n"), false); err == nil || !strings.Contains(err.Error(), "differs") {
+		t.Errorf("compare() of a different output = %v; want a diff", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	fixture := filepath.Join(dir, "lab.md")
+	src := "id: lab\n\n# Lab\n\n## Setup\n\nText\n\n## Cleanup\n\nMore text\n"
+	if err := ioutil.WriteFile(fixture, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(Golden(fixture, "titles"), []byte("lab: Setup, Cleanup\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Run(t, filepath.Join(dir, "*.md"), "titles", false, renderTitles)
+}
+
+func TestRunUpdate(t *testing.T) {
+	dir := t.TempDir()
+	fixture := filepath.Join(dir, "lab.md")
+	if err := ioutil.WriteFile(fixture, []byte("id: lab\n\n# Lab\n\n## Setup\n\nText\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Run(t, fixture, "titles", true, renderTitles)
+	if b, _ := ioutil.ReadFile(Golden(fixture, "titles")); string(b) != "lab: Setup\n" {
+		t.Errorf("golden file = %q; want %q", b, "lab: Setup\n")
+	}
+}
+
+func TestLoadHTML(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "lab.html")
+	src := `<google-codelab id="lab" title="Lab"><google-codelab-step label="Setup"><p>Text</p></google-codelab-step></google-codelab>`
+	if err := ioutil.WriteFile(fixture, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	clab := Load(t, fixture)
+	if clab.Meta.ID != "lab" || len(clab.Steps) != 1 || clab.Steps[0].Title != "Setup" {
+		t.Errorf("Load(%s) = %+v with %d steps; want lab with step Setup", fixture, clab.Meta, len(clab.Steps))
+	}
+}
+
+// renderTitles writes the ID and step titles of clab to w.
+func renderTitles(w io.Writer, clab *types.Codelab) error {
+	var titles []string
+	for _, st := range clab.Steps {
+		titles = append(titles, st.Title)
+	}
+	_, err := io.WriteString(w, clab.Meta.ID+": "+strings.Join(titles, ", ")+"\n")
+	return err
+}
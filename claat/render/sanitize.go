@@ -0,0 +1,316 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// SanitizePolicy lists the markup allowed in codelab content rendered
+// as HTML, whatever its source document contains.
+type SanitizePolicy struct {
+	// Tags maps the names of allowed elements to their allowed attributes,
+	// in addition to Attrs. Other elements are dropped, keeping their text,
+	// except for elements such as script and style, which are dropped whole.
+	Tags map[string][]string `json:"tags,omitempty"`
+	// Attrs are the attributes allowed on all elements. A trailing "*"
+	// allows all attributes starting with the prefix, as in "data-*".
+	Attrs []string `json:"attrs,omitempty"`
+	// Schemes are the allowed schemes of URL attributes, such as href and src.
+	// Relative URLs are always allowed.
+	Schemes []string `json:"schemes,omitempty"`
+}
+
+// DefaultSanitizePolicy allows the markup written by the HTML and Lite renderers.
+var DefaultSanitizePolicy = &SanitizePolicy{
+	Tags: map[string][]string{
+		"a":                     {"download", "href", "target"},
+		"abbr":                  nil,
+		"aside":                 nil,
+		"br":                    nil,
+		"code":                  {"diff", "highlight", "language", "line-numbers"},
+		"del":                   nil,
+		"div":                   nil,
+		"em":                    nil,
+		"figcaption":            nil,
+		"figure":                nil,
+		"google-codelab-survey": {"survey-id"},
+		"h1":                    {"is-upgraded"},
+		"h2":                    {"is-upgraded"},
+		"h3":                    {"is-upgraded"},
+		"h4":                    {"is-upgraded"},
+		"h5":                    {"is-upgraded"},
+		"h6":                    {"is-upgraded"},
+		"iframe":                {"allow", "allowfullscreen", "src"},
+		"img":                   {"alt", "height", "src", "style", "width"},
+		"input":                 {"name", "type", "value"},
+		"ins":                   nil,
+		"iron-icon":             {"icon"},
+		"kbd":                   nil,
+		"label":                 nil,
+		"li":                    nil,
+		"line":                  {"stroke", "stroke-linecap", "stroke-width", "vector-effect", "x1", "x2", "y1", "y2"},
+		"ol":                    {"start", "type"},
+		"p":                     nil,
+		"paper-button":          {"raised"},
+		"paper-checkbox":        nil,
+		"paper-radio-button":    nil,
+		"paper-radio-group":     nil,
+		"paper-textarea":        nil,
+		"picture":               nil,
+		"pre":                   {"nocopy", "output"},
+		"ql-activity-tracking":  {"step"},
+		"source":                {"media", "srcset"},
+		"span":                  {"style"},
+		"strong":                nil,
+		"svg":                   {"preserveaspectratio", "style", "viewbox"},
+		"table":                 nil,
+		"tbody":                 nil,
+		"td":                    {"colspan", "rowspan"},
+		"textarea":              {"name"},
+		"th":                    {"colspan", "rowspan"},
+		"thead":                 nil,
+		"tr":                    nil,
+		"track":                 {"default", "kind", "src", "srclang"},
+		"ul":                    {"type"},
+		"video":                 {"controls", "poster", "src"},
+	},
+	Attrs:   []string{"class", "data-*", "dir", "id", "lang", "title"},
+	Schemes: []string{"http", "https", "mailto"},
+}
+
+// sanitizer returns the policy content is sanitized with in ctx.
+func (ctx Context) sanitizer() *SanitizePolicy {
+	if ctx.Sanitizer == nil {
+		return DefaultSanitizePolicy
+	}
+	return ctx.Sanitizer
+}
+
+// writeSanitized calls write with a writer of the HTML fragment to write to w,
+// sanitized with DefaultSanitizePolicy as it is written.
+func writeSanitized(w io.Writer, write func(io.Writer) error) error {
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := DefaultSanitizePolicy.sanitize(w, pr)
+		pr.CloseWithError(err) // fail further writes, if any
+		done <- err
+	}()
+	err := write(pw)
+	pw.CloseWithError(err)
+	if serr := <-done; err == nil {
+		err = serr
+	}
+	return err
+}
+
+// ReadSanitizePolicy reads a SanitizePolicy from a JSON file, and returns
+// DefaultSanitizePolicy extended with it.
+func ReadSanitizePolicy(path string) (*SanitizePolicy, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := &SanitizePolicy{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, err
+	}
+	return DefaultSanitizePolicy.Extend(p), nil
+}
+
+// Extend returns a copy of p also allowing the markup allowed by o.
+func (p *SanitizePolicy) Extend(o *SanitizePolicy) *SanitizePolicy {
+	res := &SanitizePolicy{
+		Tags:    make(map[string][]string, len(p.Tags)+len(o.Tags)),
+		Attrs:   append(append([]string(nil), p.Attrs...), o.Attrs...),
+		Schemes: append(append([]string(nil), p.Schemes...), o.Schemes...),
+	}
+	for _, tags := range []map[string][]string{p.Tags, o.Tags} {
+		for t, aa := range tags {
+			t = strings.ToLower(t)
+			res.Tags[t] = append(res.Tags[t], aa...)
+		}
+	}
+	return res
+}
+
+// droppedContent are the elements dropped along with their content,
+// when not allowed.
+var droppedContent = map[string]bool{
+	"noembed":  true,
+	"noframes": true,
+	"noscript": true,
+	"object":   true,
+	"script":   true,
+	"style":    true,
+	"template": true,
+	"title":    true,
+	"xmp":      true,
+}
+
+// urlAttrs are the attributes holding URLs, whose schemes are checked.
+var urlAttrs = map[string]bool{
+	"action":     true,
+	"background": true,
+	"cite":       true,
+	"formaction": true,
+	"href":       true,
+	"poster":     true,
+	"src":        true,
+	"srcset":     true,
+	"xlink:href": true,
+}
+
+// schemeRegexp matches the scheme of an absolute URL.
+var schemeRegexp = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*):`)
+
+// Sanitize returns HTML fragment s with the elements, attributes and URL
+// schemes not allowed by p removed, as well as comments. Allowed markup
+// is kept byte for byte.
+func (p *SanitizePolicy) Sanitize(s string) string {
+	var buf bytes.Buffer
+	p.sanitize(&buf, strings.NewReader(s))
+	return buf.String()
+}
+
+// sanitize writes the HTML fragment read from r to w, as Sanitize does.
+func (p *SanitizePolicy) sanitize(w io.Writer, r io.Reader) error {
+	bw := bufio.NewWriter(w)
+	z := html.NewTokenizer(r)
+	var skip string // name of a dropped element whose content is skipped
+	depth := 0      // nesting of skip elements
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err := z.Err(); err != io.EOF {
+				return err
+			}
+			return bw.Flush()
+		}
+		// copied, since z.Token unescapes attribute values in place
+		raw := append([]byte(nil), z.Raw()...)
+		switch tt {
+		case html.TextToken:
+			if skip == "" {
+				bw.Write(raw)
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			if skip != "" {
+				if tok.Data == skip && tt == html.StartTagToken {
+					depth++
+				}
+				continue
+			}
+			allowed, ok := p.Tags[tok.Data]
+			if !ok {
+				if droppedContent[tok.Data] && tt == html.StartTagToken {
+					skip, depth = tok.Data, 1
+				}
+				continue
+			}
+			if attrs, clean := p.attrs(tok.Attr, allowed); !clean {
+				tok.Attr = attrs
+				bw.WriteString(startTag(tok, tt == html.SelfClosingTagToken))
+				continue
+			}
+			bw.Write(raw)
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			if skip != "" {
+				if string(name) == skip {
+					if depth--; depth == 0 {
+						skip = ""
+					}
+				}
+				continue
+			}
+			if _, ok := p.Tags[string(name)]; ok {
+				bw.Write(raw)
+			}
+		}
+	}
+}
+
+// attrs returns the attributes of aa allowed on an element allowing attributes
+// allowed, in addition to p.Attrs, and whether they are all allowed.
+func (p *SanitizePolicy) attrs(aa []html.Attribute, allowed []string) ([]html.Attribute, bool) {
+	var res []html.Attribute
+	for _, a := range aa {
+		if a.Namespace == "" && (matchAttr(a.Key, allowed) || matchAttr(a.Key, p.Attrs)) &&
+			(!urlAttrs[a.Key] || p.safeURL(a.Val)) {
+			res = append(res, a)
+		}
+	}
+	return res, len(res) == len(aa)
+}
+
+// matchAttr reports whether attribute name is one of names,
+// which may end with "*" to match a prefix.
+func matchAttr(name string, names []string) bool {
+	for _, n := range names {
+		n = strings.ToLower(n)
+		if n == name || strings.HasSuffix(n, "*") && strings.HasPrefix(name, strings.TrimSuffix(n, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// safeURL reports whether u is relative or has a scheme of p.
+// Browsers ignore whitespace and control characters in schemes,
+// as in "java\tscript:", so they are ignored as well.
+func (p *SanitizePolicy) safeURL(u string) bool {
+	u = strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, u)
+	m := schemeRegexp.FindStringSubmatch(u)
+	if m == nil {
+		return true
+	}
+	for _, s := range p.Schemes {
+		if strings.EqualFold(s, m[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// startTag returns the start tag of tok, with escaped attribute values.
+func startTag(tok html.Token, selfClosing bool) string {
+	var b strings.Builder
+	b.WriteString("<" + tok.Data)
+	for _, a := range tok.Attr {
+		b.WriteString(fmt.Sprintf(` %s="%s"`, a.Key, html.EscapeString(a.Val)))
+	}
+	if selfClosing {
+		b.WriteString("/")
+	}
+	b.WriteString(">")
+	return b.String()
+}
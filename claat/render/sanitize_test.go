@@ -0,0 +1,119 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		// allowed markup is kept as is
+		{`<p class="x">a &amp; <strong>b</strong><br></p>`, `<p class="x">a &amp; <strong>b</strong><br></p>`},
+		{`<pre noCopy><code language="go">f()</code></pre>`, `<pre noCopy><code language="go">f()</code></pre>`},
+		{`<abbr title="A &#34;b&#34;">c</abbr>`, `<abbr title="A &#34;b&#34;">c</abbr>`},
+		{`<a href="/p/lab" data-x="1">l</a>`, `<a href="/p/lab" data-x="1">l</a>`},
+		// dropped elements keep their text, except for scripts and styles
+		{`<p>a<script>alert("</p>")</script>b</p>`, `<p>ab</p>`},
+		{`<style>p{}</style><marquee>c</marquee>`, `c`},
+		{`<p>a<!-- c --></p>`, `<p>a</p>`},
+		// attributes and URL schemes
+		{`<p onclick="x()">a</p>`, `<p>a</p>`},
+		{`<a href="javascript:alert(1)" target="_blank">l</a>`, `<a target="_blank">l</a>`},
+		{`<a href="java&#9;script:alert(1)">l</a>`, `<a>l</a>`},
+		{`<a href="JavaScript:alert(1)">l</a>`, `<a>l</a>`},
+		{`<a href="mailto:a@example.com">m</a>`, `<a href="mailto:a@example.com">m</a>`},
+		{`<img src="data:image/png;base64,AA" alt="&lt;i&gt;"/>`, `<img alt="&lt;i&gt;"/>`},
+		{`<iframe src="https://codepen.io/x" srcdoc="<script>"></iframe>`, `<iframe src="https://codepen.io/x"></iframe>`},
+	}
+	for _, tc := range tests {
+		if got := DefaultSanitizePolicy.Sanitize(tc.in); got != tc.out {
+			t.Errorf("Sanitize(%q) = %q; want %q", tc.in, got, tc.out)
+		}
+	}
+}
+
+func TestSanitizeRendered(t *testing.T) {
+	link := nodes.NewURLNode("javascript:alert(1)", nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "l"}))
+	link.Target = "_blank"
+	want := `<a target="_blank">l</a>`
+	for name, render := range map[string]func(Context, ...nodes.Node) (string, error){
+		"HTML": func(ctx Context, nn ...nodes.Node) (string, error) {
+			s, err := HTML(ctx, nn...)
+			return string(s), err
+		},
+		"Lite": func(ctx Context, nn ...nodes.Node) (string, error) {
+			s, err := Lite(ctx, nn...)
+			return string(s), err
+		},
+	} {
+		got, err := render(Context{}, link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s(javascript: link) = %q; want %q", name, got, want)
+		}
+	}
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, "", "", link); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Errorf("WriteHTML(javascript: link) = %q; want %q", buf.String(), want)
+	}
+}
+
+func TestReadSanitizePolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sanitize.json")
+	policy := `{"tags": {"SAMP": [], "a": ["rel"]}, "schemes": ["tel"]}`
+	if err := ioutil.WriteFile(path, []byte(policy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := ReadSanitizePolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := `<samp>Ctrl</samp><a href="tel:123" rel="nofollow" download="x">t</a><script>x</script>`
+	want := `<samp>Ctrl</samp><a href="tel:123" rel="nofollow" download="x">t</a>`
+	if diff := cmp.Diff(want, p.Sanitize(in)); diff != "" {
+		t.Errorf("Sanitize() with read policy diff (-want +got):\n%s", diff)
+	}
+	if got := DefaultSanitizePolicy.Sanitize(`<samp>Ctrl</samp>`); got != "Ctrl" {
+		t.Errorf("ReadSanitizePolicy changed DefaultSanitizePolicy: Sanitize(samp) = %q", got)
+	}
+
+	link := nodes.NewURLNode("tel:123", nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "t"}))
+	for s, want := range map[*SanitizePolicy]string{
+		nil: `<a target="_blank">t</a>`,
+		p:   `<a href="tel:123" target="_blank">t</a>`,
+	} {
+		got, err := Lite(Context{Sanitizer: s}, link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("Lite(tel: link) with Sanitizer %p = %q; want %q", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// SlidesFile is the file of slides output, written next to
+// the codelab exported in another format.
+const SlidesFile = "slides.html"
+
+// maxSlideCommands is the maximum number of key commands of a step slide,
+// and minHeroWidth the minimum width of its hero image, if known,
+// skipping inline images such as icons.
+const (
+	maxSlideCommands = 5
+	minHeroWidth     = 200
+)
+
+// SlideCommands returns the key commands of step st in env, shown on its
+// slide: the first lines of its terminal code blocks, other than their output,
+// blank lines and comments.
+func SlideCommands(env string, st *types.Step) []string {
+	var cmds []string
+	walkEnv(env, st, func(n nodes.Node) bool {
+		code, ok := n.(*nodes.CodeNode)
+		if !ok || !code.Term || code.Output {
+			return true
+		}
+		for _, l := range strings.Split(code.Value, "\n") {
+			l = strings.TrimSpace(l)
+			if l == "" || strings.HasPrefix(l, "#") {
+				continue
+			}
+			if cmds = append(cmds, l); len(cmds) == maxSlideCommands {
+				return false
+			}
+		}
+		return true
+	})
+	return cmds
+}
+
+// SlideImage returns the hero image of step st in env, shown on its slide:
+// its first image not known to be narrower than minHeroWidth, or nil.
+func SlideImage(env string, st *types.Step) *nodes.ImageNode {
+	var hero *nodes.ImageNode
+	walkEnv(env, st, func(n nodes.Node) bool {
+		img, ok := n.(*nodes.ImageNode)
+		if ok && (img.Width == 0 || img.Width >= minHeroWidth) {
+			hero = img
+			return false
+		}
+		return true
+	})
+	return hero
+}
+
+// walkEnv calls fn with each node of the content of step st in env,
+// until fn returns false.
+func walkEnv(env string, st *types.Step, fn func(nodes.Node) bool) {
+	if st.Content == nil {
+		return
+	}
+	nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if !entering {
+			return n, nil
+		}
+		if !nodes.MatchEnv(n.Env(), env) {
+			return n, nodes.SkipChildren
+		}
+		if !fn(n) {
+			return n, errFound
+		}
+		return n, nil
+	})
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestSlideCommands(t *testing.T) {
+	output := nodes.NewCodeNode("total 0", true, "")
+	output.Output = true
+	kiosk := nodes.NewCodeNode("kiosk-only", true, "")
+	kiosk.MutateEnv([]string{"kiosk"})
+	tests := []struct {
+		name string
+		in   []nodes.Node
+		out  []string
+	}{
+		{
+			name: "Terminal",
+			in: []nodes.Node{
+				nodes.NewCodeNode("# Set the project\ngcloud config set project lab\n\ngcloud services enable run.googleapis.com\n", true, ""),
+				output,
+				nodes.NewCodeNode("func main() {}", false, "go"),
+			},
+			out: []string{"gcloud config set project lab", "gcloud services enable run.googleapis.com"},
+		},
+		{
+			name: "Max",
+			in:   []nodes.Node{nodes.NewCodeNode("a\nb\nc", true, ""), nodes.NewCodeNode("d\ne\nf", true, "")},
+			out:  []string{"a", "b", "c", "d", "e"},
+		},
+		{
+			name: "Env",
+			in:   []nodes.Node{kiosk, nodes.NewCodeNode("ls", true, "")},
+			out:  []string{"ls"},
+		},
+		{
+			name: "None",
+			in:   []nodes.Node{nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "text"})},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			st := &types.Step{Content: nodes.NewListNode(tc.in...)}
+			if diff := cmp.Diff(tc.out, SlideCommands("web", st)); diff != "" {
+				t.Errorf("SlideCommands got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSlideImage(t *testing.T) {
+	img := func(src string, width float32) *nodes.ImageNode {
+		return nodes.NewImageNode(nodes.NewImageNodeOptions{Src: src, Width: width})
+	}
+	tests := []struct {
+		name string
+		in   []nodes.Node
+		out  string
+	}{
+		{"First", []nodes.Node{img("a.png", 600), img("b.png", 600)}, "a.png"},
+		{"SkipIcons", []nodes.Node{img("icon.png", 24), img("console.png", 800)}, "console.png"},
+		{"UnknownWidth", []nodes.Node{img("screen.png", 0)}, "screen.png"},
+		{"None", []nodes.Node{img("icon.png", 24)}, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			st := &types.Step{Content: nodes.NewListNode(nodes.NewListNode(tc.in...))}
+			var src string
+			if hero := SlideImage("web", st); hero != nil {
+				src = hero.Src
+			}
+			if src != tc.out {
+				t.Errorf("SlideImage = %q; want %q", src, tc.out)
+			}
+		})
+	}
+}
+
+func TestExecuteSlides(t *testing.T) {
+	data := &Context{
+		Env:  "web",
+		Meta: &types.Meta{Title: "Deploy", Summary: "Deploy an app.", Authors: "Ada", Duration: 20},
+		Steps: []*types.Step{
+			{Title: "Set up", Content: nodes.NewListNode(nodes.NewCodeNode("gcloud init", true, ""))},
+			{Title: "Kiosk", Tags: []string{"kiosk"}, Content: nodes.NewListNode()},
+			{Title: "Console", Content: nodes.NewListNode(nodes.NewImageNode(nodes.NewImageNodeOptions{Src: "img/console.png", Alt: "Console"}))},
+		},
+	}
+	var buf bytes.Buffer
+	if err := Execute(&buf, "slides", data); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`<h1>Deploy</h1>`,
+		`<p>Deploy an app.</p>`,
+		`<p class="meta">Ada</p>`,
+		`<p class="meta">20 min</p>`,
+		`<h2>1. Set up</h2>`,
+		`<pre><code class="language-bash" data-trim>gcloud init</code></pre>`,
+		`<h2>2. Console</h2>`,
+		`<img src="img/console.png" alt="Console">`,
+		`Reveal.initialize(`,
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("slides output does not contain %s:\n%s", s, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "Kiosk") {
+		t.Errorf("slides output has a step of another environment:\n%s", buf.String())
+	}
+}
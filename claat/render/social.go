@@ -0,0 +1,58 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// errFound stops a walk of nodes once the node looked for is found.
+var errFound = errors.New("found")
+
+// SocialImage returns the URL of the image shown in social cards
+// of a codelab with meta and steps: its "image" metadata, or else
+// its first image. A relative URL is resolved against pageURL, if not empty,
+// since social networks only fetch absolute URLs.
+func SocialImage(pageURL string, meta *types.Meta, steps []*types.Step) string {
+	src := meta.Image
+	for _, st := range steps {
+		if src != "" {
+			break
+		}
+		nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			if img, ok := n.(*nodes.ImageNode); ok && entering {
+				src = img.Src
+				return n, errFound
+			}
+			return n, nil
+		})
+	}
+	if src == "" || pageURL == "" {
+		return src
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return src
+	}
+	ref, err := url.Parse(src)
+	if err != nil {
+		return src
+	}
+	return base.ResolveReference(ref).String()
+}
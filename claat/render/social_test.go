@@ -0,0 +1,81 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestSocialImage(t *testing.T) {
+	steps := []*types.Step{
+		{Content: nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "text"}))},
+		{Content: nodes.NewListNode(
+			nodes.NewInfoboxNode(nodes.InfoboxPositive, nodes.NewImageNode(nodes.NewImageNodeOptions{Src: "img/first.png"})),
+			nodes.NewImageNode(nodes.NewImageNodeOptions{Src: "img/second.png"}),
+		)},
+	}
+	tests := []struct {
+		name    string
+		pageURL string
+		image   string
+		want    string
+	}{
+		{"first image", "", "", "img/first.png"},
+		{"absolute first image", "https://example.com/labs/lab/", "", "https://example.com/labs/lab/img/first.png"},
+		{"metadata", "https://example.com/labs/lab/", "https://cdn.example.com/card.png", "https://cdn.example.com/card.png"},
+		{"relative metadata", "https://example.com/labs/lab/", "/card.png", "https://example.com/card.png"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := SocialImage(tc.pageURL, &types.Meta{Image: tc.image}, steps); got != tc.want {
+				t.Errorf("SocialImage(%q) = %q; want %q", tc.pageURL, got, tc.want)
+			}
+		})
+	}
+	if got := SocialImage("", &types.Meta{}, steps[:1]); got != "" {
+		t.Errorf("SocialImage() without images = %q; want empty", got)
+	}
+}
+
+func TestSocialMetaTags(t *testing.T) {
+	step := &types.Step{Content: nodes.NewListNode(nodes.NewImageNode(nodes.NewImageNodeOptions{Src: "img/a.png"}))}
+	data := &struct {
+		Context
+	}{Context: Context{
+		Meta:    &types.Meta{Title: "Lab", Summary: "A lab"},
+		Steps:   []*types.Step{step},
+		PageURL: "https://example.com/lab/",
+	}}
+	var buf bytes.Buffer
+	if err := Execute(&buf, "html", data); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<meta property="og:title" content="Lab">`,
+		`<meta property="og:description" content="A lab">`,
+		`<meta property="og:url" content="https://example.com/lab/">`,
+		`<meta property="og:image" content="https://example.com/lab/img/a.png">`,
+		`<meta name="twitter:card" content="summary_large_image">`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("html output does not contain %s", want)
+		}
+	}
+}
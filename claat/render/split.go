@@ -0,0 +1,111 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// splitTemplates are the built-in templates of split output of a format:
+// the index of the codelab steps and the page of each step.
+var splitTemplates = map[string]struct{ index, step string }{
+	"html": {"html-steps", "html-step"},
+	"md":   {"md-steps", "md-step"},
+}
+
+// SplitTemplates returns the names of the built-in templates of the index
+// and of each step of split output in format, executed with the data of
+// the offline template, and false if format cannot be split.
+func SplitTemplates(format string) (index, step string, ok bool) {
+	t, ok := splitTemplates[format]
+	return t.index, t.step, ok
+}
+
+// SplitIndexFile returns the name of the index file of split output in format.
+func SplitIndexFile(format string) string {
+	return "index." + format
+}
+
+// frontMatter renders YAML front matter of split Markdown output, for
+// static site generators, from pairs of keys and values. Strings are
+// quoted, durations are written in minutes, and zero values are skipped.
+func frontMatter(kv ...interface{}) (string, error) {
+	if len(kv)%2 != 0 {
+		return "", fmt.Errorf("frontMatter: odd number of arguments %d", len(kv))
+	}
+	var b strings.Builder
+	b.WriteString("---\n")
+	for i := 0; i < len(kv); i += 2 {
+		var v string
+		switch x := kv[i+1].(type) {
+		case string:
+			if x != "" {
+				v = strconv.Quote(x)
+			}
+		case int:
+			if x != 0 {
+				v = strconv.Itoa(x)
+			}
+		case time.Duration:
+			if x != 0 {
+				v = strconv.Itoa(int(x.Minutes()))
+			}
+		case bool:
+			if x {
+				v = "true"
+			}
+		case nil:
+		default:
+			return "", fmt.Errorf("frontMatter: %v: unsupported value type %T", kv[i], x)
+		}
+		if v != "" {
+			fmt.Fprintf(&b, "%v: %s\n", kv[i], v)
+		}
+	}
+	b.WriteString("---\n")
+	return b.String(), nil
+}
+
+// StepFile returns the name of the file of step num, from 1, of split
+// output in format. Steps of other environments are not counted.
+func StepFile(num int, format string) string {
+	return fmt.Sprintf("step-%d.%s", num, format)
+}
+
+// IsStepFile reports whether name is the name of a step file
+// of split output in format, as returned by StepFile.
+func IsStepFile(name, format string) bool {
+	var num int
+	if _, err := fmt.Sscanf(name, "step-%d."+format, &num); err != nil {
+		return false
+	}
+	return StepFile(num, format) == name
+}
+
+// stepID returns the anchor of the title of step st of ctx,
+// which is the id of its heading in split HTML output.
+func stepID(ctx Context, st *types.Step) string {
+	if id, ok := docAnchors(ctx).stepID(st); ok {
+		return id
+	}
+	var sl nodes.Slugger
+	return sl.Slug(st.Title)
+}
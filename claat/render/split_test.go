@@ -0,0 +1,175 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestFrontMatter(t *testing.T) {
+	tests := []struct {
+		kv  []interface{}
+		out string
+	}{
+		{nil, "---\n---\n"},
+		{
+			[]interface{}{"title", `Say "hi"`, "step", 2, "duration", 90 * time.Second, "prev", false, "next", "step-3.md"},
+			"---\ntitle: \"Say \\\"hi\\\"\"\nstep: 2\nduration: 1\nnext: \"step-3.md\"\n---\n",
+		},
+		{[]interface{}{"title", "", "step", 0, "duration", time.Duration(0), "draft", true}, "---\ndraft: true\n---\n"},
+	}
+	for _, tc := range tests {
+		out, err := frontMatter(tc.kv...)
+		if err != nil {
+			t.Errorf("frontMatter(%v): %v", tc.kv, err)
+			continue
+		}
+		if diff := cmp.Diff(tc.out, out); diff != "" {
+			t.Errorf("frontMatter(%v) got diff (-want +got):\n%s", tc.kv, diff)
+		}
+	}
+	for _, kv := range [][]interface{}{{"title"}, {"weight", 1.5}} {
+		if _, err := frontMatter(kv...); err == nil {
+			t.Errorf("frontMatter(%v) = nil error; want error", kv)
+		}
+	}
+}
+
+func TestSplitLinks(t *testing.T) {
+	see := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "see "})
+	xref := nodes.NewXrefNode(3, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Step 3: Deploy"}))
+	term := nodes.NewTermNode("VM", "Virtual machine", nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "VM"}))
+	h := nodes.NewHeaderNode(2, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Check"}))
+	ctx := Context{
+		Env:    "web",
+		Format: "md",
+		Split:  true,
+		Meta:   &types.Meta{Title: "Codelab", Glossary: types.Glossary{"VM": "Virtual machine"}},
+		Steps: []*types.Step{
+			{Title: "Intro", Content: nodes.NewListNode()},
+			{Title: "Kiosk setup", Tags: []string{"kiosk"}, Content: nodes.NewListNode()},
+			{Title: "Deploy", Content: nodes.NewListNode(h)},
+		},
+	}
+	md, err := MD(ctx, see, xref, term)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "see [Step 3: Deploy](step-2.md#deploy) [VM](index.md#glossary)"; md != want {
+		t.Errorf("MD = %q; want %q", md, want)
+	}
+	want := "- [Intro](step-1.md#intro)\n" +
+		"- [Deploy](step-2.md#deploy)\n" +
+		"  - [Check](step-2.md#check)\n" +
+		"- [Glossary](index.md#glossary)\n"
+	if diff := cmp.Diff(want, TOC(ctx)); diff != "" {
+		t.Errorf("TOC got diff (-want +got):\n%s", diff)
+	}
+
+	ctx.Format = "html"
+	html, err := HTML(ctx, xref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<a href="step-2.html#deploy">Step 3: Deploy</a>`; string(html) != want {
+		t.Errorf("HTML = %q; want %q", html, want)
+	}
+}
+
+func TestExecuteSplit(t *testing.T) {
+	step := &types.Step{
+		Title:    "Deploy",
+		Duration: 5 * time.Minute,
+		Content:  nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Run it."})),
+	}
+	for _, format := range []string{"md", "html"} {
+		index, name, ok := SplitTemplates(format)
+		if !ok {
+			t.Fatalf("SplitTemplates(%s) = false", format)
+		}
+		data := &struct {
+			Context
+			Current *types.Step
+			StepNum int
+			Prev    bool
+			Next    bool
+		}{
+			Context: Context{
+				Format:   format,
+				Split:    true,
+				Lightbox: 600,
+				Meta: &types.Meta{ID: "lab", Title: "Lab", StepInfo: []*types.StepInfo{
+					{Title: "Setup", File: StepFile(1, format)},
+					{Title: "Deploy", Duration: 5, File: StepFile(2, format)},
+				}},
+				Steps: []*types.Step{{Title: "Setup", Content: nodes.NewListNode()}, step},
+			},
+			Current: step,
+			StepNum: 2,
+			Prev:    true,
+		}
+		var b bytes.Buffer
+		if err := Execute(&b, name, data); err != nil {
+			t.Fatalf("Execute(%s): %v", name, err)
+		}
+		want := map[string][]string{
+			"md": {
+				"---\ntitle: \"Deploy\"\ncodelab: \"lab\"\nstep: 2\nweight: 2\nduration: 5\nprev: \"step-1.md\"\n---\n\n## Deploy\nDuration: 05:00\n",
+				"Run it.",
+				"[Back](step-1.md) | [Lab](index.md)\n",
+			},
+			"html": {
+				`<link rel="prev" href="step-1.html">`,
+				`<h2 id="deploy">Deploy</h2>`,
+				`<a rel="prev" href="step-1.html">Back</a>`,
+				`<script src="/claat-public/codelab-elements.js"></script>`,
+				`<script src="/claat-public/prettify.js"></script>`,
+				`overlay.className = 'lightbox-overlay';`,
+			},
+		}[format]
+		for _, s := range want {
+			if !strings.Contains(b.String(), s) {
+				t.Errorf("%s output does not contain %q:\n%s", name, s, b.String())
+			}
+		}
+		if n := strings.Count(strings.ToLower(b.String()), "<!doctype"); n > 1 {
+			t.Errorf("%s output has %d doctypes; want at most 1", name, n)
+		}
+		if strings.Contains(b.String(), "next") {
+			t.Errorf("%s output of the last step links to a next step:\n%s", name, b.String())
+		}
+
+		b.Reset()
+		data.Current, data.StepNum, data.Prev = nil, 0, false
+		if err := Execute(&b, index, data); err != nil {
+			t.Fatalf("Execute(%s): %v", index, err)
+		}
+		for _, si := range data.Meta.StepInfo {
+			if !strings.Contains(b.String(), si.File) {
+				t.Errorf("%s output does not link to %s:\n%s", index, si.File, b.String())
+			}
+		}
+	}
+	if _, _, ok := SplitTemplates("offline"); ok {
+		t.Error("SplitTemplates(offline) = true; want false")
+	}
+}
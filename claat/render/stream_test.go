@@ -0,0 +1,127 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+// largeLab returns the nodes of a very large codelab step:
+// many paragraphs with images, followed by a huge table.
+func largeLab(images, rows int) []nodes.Node {
+	var nn []nodes.Node
+	for i := 0; i < images; i++ {
+		nn = append(nn,
+			nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: fmt.Sprintf("Paragraph %d, with an image.", i)})),
+			nodes.NewImageNode(nodes.NewImageNodeOptions{Src: fmt.Sprintf("img/%d.png", i), Alt: fmt.Sprintf("image %d", i)}),
+		)
+	}
+	cell := func(s string) *nodes.GridCell {
+		return &nodes.GridCell{Colspan: 1, Rowspan: 1, Content: nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: s}))}
+	}
+	grid := make([][]*nodes.GridCell, rows)
+	for i := range grid {
+		grid[i] = []*nodes.GridCell{cell(fmt.Sprintf("row %d", i)), cell("a cell\nspanning lines"), cell("項目")}
+	}
+	return append(nn, nodes.NewGridNode(grid...))
+}
+
+// writeRecorder discards writes, keeping the total and largest write size.
+type writeRecorder struct {
+	total int
+	max   int
+}
+
+func (w *writeRecorder) Write(b []byte) (int, error) {
+	w.total += len(b)
+	if len(b) > w.max {
+		w.max = len(b)
+	}
+	return len(b), nil
+}
+
+func TestWriteStreams(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(io.Writer, ...nodes.Node) error
+	}{
+		{"md", func(w io.Writer, nn ...nodes.Node) error { return WriteMD(w, "", "", nn...) }},
+		{"html", func(w io.Writer, nn ...nodes.Node) error { return WriteHTML(w, "", "", nn...) }},
+		{"lite", func(w io.Writer, nn ...nodes.Node) error { return WriteLite(w, "", nn...) }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var w writeRecorder
+			if err := tc.write(&w, largeLab(500, 2000)...); err != nil {
+				t.Fatal(err)
+			}
+			// Output should be written as it is rendered, at most
+			// a buffer-full at a time, not as a single document.
+			if w.total < 100000 || w.max > 4096 {
+				t.Errorf("wrote %d bytes, at most %d at once; want output written in small pieces", w.total, w.max)
+			}
+		})
+	}
+}
+
+func TestWriteMDTableMatchesMD(t *testing.T) {
+	nn := largeLab(2, 10)
+	want, err := MD(Context{}, nn...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w writeRecorder
+	if err := WriteMD(&w, "", "", nn...); err != nil {
+		t.Fatal(err)
+	}
+	if w.total != len(want) {
+		t.Errorf("WriteMD wrote %d bytes; MD returned %d", w.total, len(want))
+	}
+}
+
+func BenchmarkWriteMD(b *testing.B) {
+	nn := largeLab(500, 2000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := WriteMD(ioutil.Discard, "", "", nn...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriteHTML(b *testing.B) {
+	nn := largeLab(500, 2000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := WriteHTML(ioutil.Discard, "", "", nn...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriteLite(b *testing.B) {
+	nn := largeLab(500, 2000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := WriteLite(ioutil.Discard, "", nn...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
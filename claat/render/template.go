@@ -15,6 +15,7 @@
 package render
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -22,13 +23,16 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	htmlTemplate "html/template"
 	textTemplate "text/template"
 
+	"github.com/googlecodelabs/tools/claat/nodes"
 	mdParse "github.com/googlecodelabs/tools/claat/parser/md"
 	"github.com/googlecodelabs/tools/claat/types"
+	"github.com/googlecodelabs/tools/claat/util"
 
 	_ "embed" // embeding template files
 )
@@ -43,7 +47,33 @@ type Context struct {
 	Meta      *types.Meta
 	Steps     []*types.Step
 	Updated   string
+	TOC       bool              // Emit a table of contents, in Markdown output.
+	Split     bool              // Each step is in its own file, see StepFile.
+	PageURL   string            // Published URL of the codelab page, if known.
+	Analytics *types.Analytics  // Analytics snippets of HTML output, if any.
 	Extra     map[string]string // Extra variables passed from the command line.
+	Lightbox  int               // Minimum width of images zooming in on click in HTML output, if not 0.
+	Theme     *Theme            // Theme branding HTML output, if any.
+	Paper     string            // Paper size of printed HTML output, one of PaperSizes, if any.
+	PWA       bool              // Link HTML output to the manifest and service worker of a web app.
+	// CloudShell is how terminal code blocks of HTML output open Cloud Shell,
+	// one of CloudShellModes, if they do.
+	CloudShell string
+	// Provenance traces the codelab back to its source revision, in HTML output.
+	Provenance *types.Provenance
+	// KeepRuntimeVars leaves Qwiklabs {{{...}}} runtime expressions
+	// unescaped in Markdown and HTML output.
+	KeepRuntimeVars bool
+	// Sanitizer is the policy content rendered by HTML and Lite is
+	// sanitized with, DefaultSanitizePolicy if nil.
+	Sanitizer *SanitizePolicy
+	// IconImages is the URL of the images of icons in Markdown output,
+	// with {name} replaced by the icon name, such as
+	// https://fonts.gstatic.com/s/i/materialicons/{name}/v1/24px.svg.
+	// If empty, icons are written as their IconText.
+	IconImages string
+
+	anchors *anchors // anchors of Steps, if computed by WithAnchors
 }
 
 // Execute renders a template of the fmt format into w.
@@ -59,12 +89,15 @@ func Execute(w io.Writer, fmt string, data interface{}, opt ...Option) error {
 		switch o := o.(type) {
 		case optFuncMap:
 			funcs = o
+		case optContext:
+			w = util.ContextWriter(o.ctx, w)
 		}
 	}
 	t, err := parseTemplate(fmt, funcs)
 	if err != nil {
 		return err
 	}
+
 	if ctx, ok := data.(*Context); ok {
 		sort.Strings(ctx.Meta.Tags)
 	}
@@ -78,9 +111,18 @@ type executer interface {
 
 // funcMap are exposted to the templates.
 var funcMap = map[string]interface{}{
-	"renderLite": Lite,
-	"renderHTML": HTML,
-	"renderMD":   MD,
+	"renderLite":     Lite,
+	"renderHTML":     HTML,
+	"renderMD":       MD,
+	"renderTutorial": Tutorial,
+	"renderTOC":      TOC,
+	"renderGlossary": GlossarySection,
+	"bidi":           bidi,
+	"socialImage":    SocialImage,
+	"provenanceMeta": ProvenanceMeta,
+	"printStyle":     printStyle,
+	"slideCommands":  SlideCommands,
+	"slideImage":     SlideImage,
 	"durationStr": func(d time.Duration) string {
 		m := d / time.Minute
 		return fmt.Sprintf("%02d:00", m)
@@ -98,7 +140,7 @@ var funcMap = map[string]interface{}{
 		res += kvLine(mdParse.MetaID, meta.ID)
 		res += kvLine(mdParse.MetaSummary, meta.Summary)
 		if meta.Status != nil {
-			res += kvLine(mdParse.MetaStatus, meta.Status.String())
+			res += kvLine(mdParse.MetaStatus, strings.Join(*meta.Status, ","))
 		}
 		res += kvLine(mdParse.MetaAuthors, meta.Authors)
 		res += kvLine(mdParse.MetaCategories, strings.Join(meta.Categories, ","))
@@ -108,6 +150,12 @@ var funcMap = map[string]interface{}{
 		res += kvLine(mdParse.MetaAnalyticsGa4Account, meta.GA4)
 		res += kvLine(mdParse.MetaSource, meta.Source)
 		res += kvLine(mdParse.MetaDuration, strconv.Itoa(meta.Duration))
+		res += kvLine(mdParse.MetaGlossary, meta.Glossary.String())
+		res += kvLine(mdParse.MetaLocale, meta.Locale)
+		res += kvLine(mdParse.MetaTranslations, meta.Translations.String())
+		res += kvLine(mdParse.MetaPrerequisites, strings.Join(meta.Prerequisites, ","))
+		res += kvLine(mdParse.MetaNextLabs, strings.Join(meta.Next, ","))
+		res += kvLine(mdParse.MetaImage, meta.Image)
 
 		for k, v := range meta.Extra {
 			res += kvLine(k, v)
@@ -115,13 +163,8 @@ var funcMap = map[string]interface{}{
 
 		return res
 	},
-	"matchEnv": func(tags []string, t string) bool {
-		if len(tags) == 0 || t == "" {
-			return true
-		}
-		i := sort.SearchStrings(tags, t)
-		return i < len(tags) && tags[i] == t
-	},
+	"matchEnv": nodes.MatchEnv,
+	"join":     strings.Join,
 	// lite/offline versions; multiple step files
 	"inc": func(n int) int {
 		return n + 1
@@ -144,6 +187,11 @@ var funcMap = map[string]interface{}{
 		}
 		return fmt.Sprintf("step-%d.html", n)
 	},
+	// split output
+	"stepFile":    StepFile,
+	"stepID":      stepID,
+	"indexFile":   SplitIndexFile,
+	"frontMatter": frontMatter,
 }
 
 type template struct {
@@ -157,15 +205,49 @@ var newHTMLTemplate []byte
 //go:embed template.md
 var newMDTemplate []byte
 
+//go:embed template-tutorial.md
+var newTutorialTemplate []byte
+
+//go:embed template-slides.html
+var newSlidesTemplate []byte
+
 //go:embed template-offline.html
 var newOfflineTemplate []byte
 
+//go:embed template-step.html
+var newHTMLStepTemplate []byte
+
+//go:embed template-step.md
+var newMDStepTemplate []byte
+
+//go:embed template-steps.html
+var newHTMLStepsTemplate []byte
+
+//go:embed template-steps.md
+var newMDStepsTemplate []byte
+
+// stylesPartial defines the "styles" and "lightbox" templates of the html
+// templates, which style the content rendered by claat and zoom in on its
+// images.
+//
+//go:embed template-styles.html
+var stylesPartial []byte
+
+// builtinTemplates are the parsed built-in templates without user-supplied
+// functions, keyed by name. Templates are safe for concurrent execution.
+var builtinTemplates sync.Map
+
 // parseTemplate parses template name defined either in tmpldata
 // or a local file.
 //
 // A local file template is parsed as HTML if file extension is ".html",
 // text otherwise.
 func parseTemplate(name string, fmap map[string]interface{}) (executer, error) {
+	if len(fmap) == 0 {
+		if t, ok := builtinTemplates.Load(name); ok {
+			return t.(executer), nil
+		}
+	}
 	var tmpl *template
 	switch name {
 	case "html":
@@ -177,19 +259,55 @@ func parseTemplate(name string, fmap map[string]interface{}) (executer, error) {
 		tmpl = &template{
 			bytes: newMDTemplate,
 		}
+	case "tutorial":
+		tmpl = &template{
+			bytes: newTutorialTemplate,
+		}
+	case "slides":
+		tmpl = &template{
+			bytes: newSlidesTemplate,
+			html:  true,
+		}
 	case "offline":
 		tmpl = &template{
 			bytes: newOfflineTemplate,
 			html:  true,
 		}
+	case "html-step":
+		tmpl = &template{
+			bytes: newHTMLStepTemplate,
+			html:  true,
+		}
+	case "md-step":
+		tmpl = &template{
+			bytes: newMDStepTemplate,
+		}
+	case "html-steps":
+		tmpl = &template{
+			bytes: newHTMLStepsTemplate,
+			html:  true,
+		}
+	case "md-steps":
+		tmpl = &template{
+			bytes: newMDStepsTemplate,
+		}
 	default:
-		// TODO: add templates in-mem caching
 		var err error
 		if tmpl, err = readTemplate(name); err != nil {
 			return nil, err
 		}
+		return tmpl.parse(name, fmap)
 	}
+	t, err := tmpl.parse(name, fmap)
+	if err == nil && len(fmap) == 0 {
+		builtinTemplates.Store(name, t)
+	}
+	return t, err
+}
 
+// parse parses t as a template called name, with the funcMap functions
+// and fmap, which takes precedence.
+func (t *template) parse(name string, fmap map[string]interface{}) (executer, error) {
 	funcs := make(map[string]interface{}, len(funcMap))
 	for k, v := range funcMap {
 		funcs[k] = v
@@ -198,14 +316,19 @@ func parseTemplate(name string, fmap map[string]interface{}) (executer, error) {
 		funcs[k] = v
 	}
 
-	if tmpl.html {
-		return htmlTemplate.New(name).
+	if t.html {
+		// t may redefine the partials
+		tmpl, err := htmlTemplate.New(name).
 			Funcs(funcs).
-			Parse(string(tmpl.bytes))
+			Parse(string(stylesPartial))
+		if err != nil {
+			return nil, err
+		}
+		return tmpl.Parse(string(t.bytes))
 	}
 	return textTemplate.New(name).
 		Funcs(funcs).
-		Parse(string(tmpl.bytes))
+		Parse(string(t.bytes))
 }
 
 func readTemplate(name string) (*template, error) {
@@ -232,3 +355,15 @@ func WithFuncMap(fm map[string]interface{}) Option {
 type optFuncMap map[string]interface{}
 
 func (o optFuncMap) option() {}
+
+// WithContext creates an option stopping execution with the error of ctx
+// once it is done.
+func WithContext(ctx context.Context) Option {
+	return optContext{ctx}
+}
+
+type optContext struct {
+	ctx context.Context
+}
+
+func (o optContext) option() {}
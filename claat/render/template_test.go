@@ -16,6 +16,8 @@ package render
 
 import (
 	"bytes"
+	"context"
+	"strings"
 	"testing"
 
 	"github.com/googlecodelabs/tools/claat/nodes"
@@ -40,3 +42,146 @@ func TestExecuteBuiltin(t *testing.T) {
 		}
 	}
 }
+
+func TestExecuteCanceled(t *testing.T) {
+	step := &types.Step{
+		Title:   "Test step",
+		Content: nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "text"})),
+	}
+	data := &struct {
+		Context
+	}{Context: Context{
+		Meta:  &types.Meta{},
+		Steps: []*types.Step{step},
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, f := range []string{"html", "md"} {
+		var buf bytes.Buffer
+		if err := Execute(&buf, f, data, WithContext(ctx)); err != context.Canceled {
+			t.Errorf("%s: Execute() = %v; want %v", f, err, context.Canceled)
+		}
+	}
+}
+
+func TestExecuteAnchors(t *testing.T) {
+	step := &types.Step{
+		Title:   "First title",
+		Content: nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "text"})),
+	}
+	data := &struct {
+		Context
+	}{Context: Context{
+		Meta:  &types.Meta{},
+		Steps: []*types.Step{step},
+		TOC:   true,
+	}}
+	for _, title := range []string{"First title", "Second title"} {
+		step.Title = title
+		var buf bytes.Buffer
+		if err := Execute(&buf, "md", data); err != nil {
+			t.Fatal(err)
+		}
+		if want := "(#" + strings.Replace(strings.ToLower(title), " ", "-", -1) + ")"; !strings.Contains(buf.String(), want) {
+			t.Errorf("%s: output has no %s:\n%s", title, want, buf.String())
+		}
+	}
+}
+
+func TestWithAnchors(t *testing.T) {
+	step := &types.Step{
+		Title:   "First title",
+		Content: nodes.NewListNode(nodes.NewHeaderNode(2, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Details"}))),
+	}
+	ctx := Context{Meta: &types.Meta{}, Steps: []*types.Step{step}}.WithAnchors()
+	if ctx.anchors == nil || docAnchors(ctx) != ctx.anchors {
+		t.Fatal("docAnchors() of a context with anchors computes them again")
+	}
+	if id, ok := ctx.anchors.stepID(step); !ok || id != "first-title" {
+		t.Errorf("stepID() = %q, %v; want first-title", id, ok)
+	}
+	step.Title = "Second title"
+	if again := ctx.WithAnchors(); again.anchors == ctx.anchors {
+		t.Error("WithAnchors() kept the anchors of the copied context")
+	} else if id, _ := again.anchors.stepID(step); id != "second-title" {
+		t.Errorf("stepID() after WithAnchors() = %q; want second-title", id)
+	}
+}
+
+func TestExecuteAnalytics(t *testing.T) {
+	step := &types.Step{
+		Title:   "Set up",
+		Content: nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "text"})),
+	}
+	a := &types.Analytics{GA4: "G-ABC123", Scripts: []string{"https://example.com/a.js"}, StepEvent: "step_view"}
+	data := &struct {
+		Context
+		Current *types.Step
+		StepNum int
+		Prev    bool
+		Next    bool
+	}{Context: Context{
+		Meta:      &types.Meta{ID: "lab"},
+		Steps:     []*types.Step{step},
+		GlobalGA4: a.GA4,
+		Analytics: a,
+	}, Current: step, StepNum: 1}
+	tests := map[string][]string{
+		"html": {
+			`ga4id="G-ABC123"`,
+			`<script src="https://example.com/a.js" async></script>`,
+			`var event = "step_view";`,
+			`var titles = ["Set up", ];`,
+		},
+		"offline": {
+			`<script src="https://www.googletagmanager.com/gtag/js?id=G-ABC123" async></script>`,
+			`<script src="https://example.com/a.js" async></script>`,
+			`var detail = {codelab: "lab", step:  1 , title: "Set up"};`,
+		},
+	}
+	for f, want := range tests {
+		var buf bytes.Buffer
+		if err := Execute(&buf, f, data); err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+		for _, s := range want {
+			if !strings.Contains(buf.String(), s) {
+				t.Errorf("%s output does not contain %s", f, s)
+			}
+		}
+	}
+}
+
+func TestExecutePWA(t *testing.T) {
+	step := &types.Step{
+		Title:   "Set up",
+		Content: nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "text"})),
+	}
+	data := &struct {
+		Context
+		Current *types.Step
+		StepNum int
+		Prev    bool
+		Next    bool
+	}{Context: Context{
+		Meta:  &types.Meta{ID: "lab"},
+		Steps: []*types.Step{step},
+	}, Current: step, StepNum: 1}
+	for _, pwa := range []bool{false, true} {
+		data.PWA = pwa
+		for _, f := range []string{"html", "offline", "html-step", "html-steps"} {
+			var buf bytes.Buffer
+			if err := Execute(&buf, f, data); err != nil {
+				t.Fatalf("%s: %v", f, err)
+			}
+			for _, s := range []string{
+				`<link rel="manifest" href="manifest.webmanifest">`,
+				`navigator.serviceWorker.register('sw.js');`,
+			} {
+				if got := strings.Contains(buf.String(), s); got != pwa {
+					t.Errorf("%s output with PWA %v contains %s: %v", f, pwa, s, got)
+				}
+			}
+		}
+	}
+}
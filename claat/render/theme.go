@@ -0,0 +1,141 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"embed"
+	"fmt"
+	htmlTemplate "html/template"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ThemeDir is the directory of the files of a theme in the output
+// directory of a codelab.
+const ThemeDir = "theme"
+
+// Files of a theme with the fragments written at the start and the end
+// of the body of pages, rather than copied to the output.
+const (
+	themeHeader = "header.html"
+	themeFooter = "footer.html"
+)
+
+//go:embed themes
+var bundledThemes embed.FS
+
+// Theme is a bundle of stylesheets, scripts and other assets, such as logos,
+// along with header and footer fragments, which brands HTML output.
+// Its stylesheets are linked after the built-in ones, so that they take
+// precedence.
+type Theme struct {
+	Name    string            // Name of a bundled theme, or absolute directory of the theme
+	Styles  []string          // URLs of the stylesheets of the theme
+	Scripts []string          // URLs of the scripts of the theme
+	Header  htmlTemplate.HTML // Fragment written at the start of page bodies
+	Footer  htmlTemplate.HTML // Fragment written at the end of page bodies
+
+	files fs.FS    // files of the theme
+	paths []string // paths of files copied to the output, in lexical order
+}
+
+// BundledThemes returns the names of the themes built into claat.
+func BundledThemes() []string {
+	dd, _ := bundledThemes.ReadDir("themes")
+	var names []string
+	for _, d := range dd {
+		if d.IsDir() {
+			names = append(names, d.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadTheme loads the bundled theme called name or, if there is none,
+// the theme of the directory name.
+//
+// All .css and .js files of the theme, including in subdirectories,
+// are its stylesheets and scripts, in lexical order; header.html and
+// footer.html at its root, if any, are its header and footer.
+func LoadTheme(name string) (*Theme, error) {
+	var files fs.FS
+	if sub, err := fs.Sub(bundledThemes, path.Join("themes", name)); err == nil && !strings.ContainsAny(name, `./\`) {
+		if _, err := fs.Stat(sub, "."); err == nil {
+			files = sub
+		}
+	}
+	if files == nil {
+		if fi, err := os.Stat(name); err != nil || !fi.IsDir() {
+			return nil, fmt.Errorf("theme %q is neither a directory nor a bundled theme: %s", name, strings.Join(BundledThemes(), ", "))
+		}
+		files = os.DirFS(name)
+		if abs, err := filepath.Abs(name); err == nil {
+			name = abs
+		}
+	}
+	t := &Theme{Name: name, files: files}
+	err := fs.WalkDir(files, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch p {
+		case themeHeader:
+			b, err := fs.ReadFile(files, p)
+			t.Header = htmlTemplate.HTML(b)
+			return err
+		case themeFooter:
+			b, err := fs.ReadFile(files, p)
+			t.Footer = htmlTemplate.HTML(b)
+			return err
+		}
+		t.paths = append(t.paths, p)
+		switch path.Ext(p) {
+		case ".css":
+			t.Styles = append(t.Styles, path.Join(ThemeDir, p))
+		case ".js":
+			t.Scripts = append(t.Scripts, path.Join(ThemeDir, p))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("theme %q: %v", name, err)
+	}
+	return t, nil
+}
+
+// Write copies the files of t, other than its header and footer,
+// to the ThemeDir of dir, the output directory of a codelab.
+func (t *Theme) Write(dir string) error {
+	for _, p := range t.paths {
+		b, err := fs.ReadFile(t.files, p)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(dir, ThemeDir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(dst, b, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
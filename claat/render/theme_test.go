@@ -0,0 +1,173 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// writeTheme writes a theme of files to a temporary directory,
+// removed at the end of the test.
+func writeTheme(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "claat-theme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	for name, s := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(s), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadTheme(t *testing.T) {
+	dir := writeTheme(t, map[string]string{
+		"header.html":      `<header class="partner">Partner</header>`,
+		"footer.html":      `<footer>© Partner</footer>`,
+		"brand.css":        "body {}",
+		"css/fonts.css":    "@font-face {}",
+		"js/analytics.js":  "",
+		"img/logo.svg":     "<svg></svg>",
+		"theme/ignore.txt": "",
+	})
+	tests := []struct {
+		name string
+		in   string
+		out  *Theme
+		err  string
+	}{
+		{
+			name: "Bundled",
+			in:   "dark",
+			out:  &Theme{Name: "dark", Styles: []string{"theme/theme.css"}},
+		},
+		{
+			name: "Directory",
+			in:   dir,
+			out: &Theme{
+				Name:    dir,
+				Styles:  []string{"theme/brand.css", "theme/css/fonts.css"},
+				Scripts: []string{"theme/js/analytics.js"},
+				Header:  `<header class="partner">Partner</header>`,
+				Footer:  `<footer>© Partner</footer>`,
+			},
+		},
+		{
+			name: "Unknown",
+			in:   "no-such-theme",
+			err:  `theme "no-such-theme" is neither a directory nor a bundled theme: dark`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			th, err := LoadTheme(tc.in)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("LoadTheme(%q) = %v; want error %q", tc.in, err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.out, th, cmpopts.IgnoreUnexported(Theme{})); diff != "" {
+				t.Errorf("LoadTheme(%q) got diff (-want +got):\n%s", tc.in, diff)
+			}
+		})
+	}
+}
+
+func TestThemeWrite(t *testing.T) {
+	th, err := LoadTheme(writeTheme(t, map[string]string{
+		"header.html":  "<header></header>",
+		"brand.css":    "body {}",
+		"img/logo.svg": "<svg></svg>",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := writeTheme(t, nil)
+	if err := th.Write(out); err != nil {
+		t.Fatal(err)
+	}
+	var files []string
+	filepath.Walk(out, func(p string, fi os.FileInfo, err error) error {
+		if err == nil && !fi.IsDir() {
+			rel, _ := filepath.Rel(out, p)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	want := []string{"theme/brand.css", "theme/img/logo.svg"}
+	if diff := cmp.Diff(want, files); diff != "" {
+		t.Errorf("Write got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestExecuteTheme(t *testing.T) {
+	step := &types.Step{
+		Title:   "Set up",
+		Content: nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "text"})),
+	}
+	th := &Theme{
+		Styles:  []string{"theme/brand.css"},
+		Scripts: []string{"theme/brand.js"},
+		Header:  `<header class="partner">Partner</header>`,
+		Footer:  `<footer>© Partner</footer>`,
+	}
+	data := &struct {
+		Context
+		Current *types.Step
+		StepNum int
+		Prev    bool
+		Next    bool
+	}{Context: Context{
+		Meta:  &types.Meta{ID: "lab"},
+		Steps: []*types.Step{step},
+		Theme: th,
+	}, Current: step, StepNum: 1}
+	for _, f := range []string{"html", "offline", "html-step", "html-steps"} {
+		var buf bytes.Buffer
+		if err := Execute(&buf, f, data); err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+		out := buf.String()
+		for _, s := range []string{
+			`  <link rel="stylesheet" href="theme/brand.css">` + "\n</head>",
+			`<header class="partner">Partner</header>`,
+			`<footer>© Partner</footer><script src="theme/brand.js"></script></body>`,
+		} {
+			if !strings.Contains(out, s) {
+				t.Errorf("%s output does not contain %s", f, s)
+			}
+		}
+	}
+}
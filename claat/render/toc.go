@@ -0,0 +1,183 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// anchors are the anchors of a codelab's step titles and headings.
+type anchors struct {
+	all     []*types.Step                // all codelab steps, in order
+	index   map[*types.Step]int          // position of rendered steps, from 0
+	steps   map[*types.Step]string       // anchors of rendered steps
+	headers map[*nodes.HeaderNode]string // anchors of rendered headings
+	// glossary is the anchor of the glossary step, or of the glossary section
+	// added by the md template if there is no such step.
+	glossary string
+	// glossaryStep is the glossary step, nil for the glossary section.
+	glossaryStep *types.Step
+	// split is the format of the step files of split output, which anchors
+	// of other steps are linked in, or empty if all steps are in one file.
+	split string
+}
+
+// WithAnchors returns a copy of ctx with the anchors of its steps and their
+// headings computed once for all of its renders. Every step is rendered
+// with the anchors of all steps, which are otherwise computed again for
+// each of them. The steps, their titles and headings, and the Env, Format,
+// Split and Meta title of ctx must not change afterwards.
+func (ctx Context) WithAnchors() Context {
+	ctx.anchors = nil
+	ctx.anchors = docAnchors(ctx)
+	return ctx
+}
+
+// docAnchors returns the anchors of ctx.Steps and their headings, computed
+// by WithAnchors, or else generated in document order, as they appear in
+// the Markdown template output, starting with the codelab title. Steps and
+// headings of other environments than ctx.Env are skipped.
+func docAnchors(ctx Context) *anchors {
+	if ctx.anchors != nil {
+		return ctx.anchors
+	}
+	a := &anchors{
+		all:     ctx.Steps,
+		index:   make(map[*types.Step]int),
+		steps:   make(map[*types.Step]string),
+		headers: make(map[*nodes.HeaderNode]string),
+	}
+	if ctx.Split {
+		a.split = ctx.Format
+	}
+	var sl nodes.Slugger
+	if ctx.Meta != nil {
+		sl.Slug(ctx.Meta.Title)
+	}
+	for _, st := range ctx.Steps {
+		if !nodes.MatchEnv(st.Tags, ctx.Env) {
+			continue
+		}
+		a.index[st] = len(a.steps)
+		a.steps[st] = sl.Slug(st.Title)
+		if a.glossary == "" && strings.EqualFold(st.Title, glossaryTitle) {
+			a.glossary = a.steps[st]
+			a.glossaryStep = st
+		}
+		if st.Content == nil {
+			continue
+		}
+		nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			if !entering {
+				return n, nil
+			}
+			if !nodes.MatchEnv(n.Env(), ctx.Env) {
+				return n, nodes.SkipChildren
+			}
+			if h, ok := n.(*nodes.HeaderNode); ok {
+				a.headers[h] = sl.Slug(nodes.PlainText(h.Content.Nodes...))
+			}
+			return n, nil
+		})
+	}
+	if a.glossary == "" && hasGlossarySection(ctx) {
+		a.glossary = sl.Slug(glossaryTitle)
+	}
+	return a
+}
+
+// header returns the anchor of heading n. Headings unknown to a,
+// e.g. of nodes rendered outside of a codelab, get an anchor from sl.
+// A nil a is valid.
+func (a *anchors) header(n *nodes.HeaderNode, sl *nodes.Slugger) string {
+	if a != nil {
+		if id, ok := a.headers[n]; ok {
+			return id
+		}
+	}
+	return sl.Slug(nodes.PlainText(n.Content.Nodes...))
+}
+
+// step returns the anchor and the position among rendered steps, from 0,
+// of step number num, starting at 1. It returns false if there is
+// no such rendered step. A nil a is valid.
+func (a *anchors) step(num int) (id string, index int, ok bool) {
+	if a == nil || num < 1 || num > len(a.all) {
+		return "", 0, false
+	}
+	st := a.all[num-1]
+	id, ok = a.steps[st]
+	return id, a.index[st], ok
+}
+
+// stepID returns the anchor of step st, if it is a rendered step.
+// A nil a is valid.
+func (a *anchors) stepID(st *types.Step) (string, bool) {
+	if a == nil {
+		return "", false
+	}
+	id, ok := a.steps[st]
+	return id, ok
+}
+
+// href returns the link to anchor id of st, which is in the same file
+// unless the output is split. In split output, anchors of a nil st are
+// in the index file, such as that of the glossary section.
+// A nil a is valid.
+func (a *anchors) href(st *types.Step, id string) string {
+	if a == nil || a.split == "" {
+		return "#" + id
+	}
+	if st == nil {
+		return SplitIndexFile(a.split) + "#" + id
+	}
+	return StepFile(a.index[st]+1, a.split) + "#" + id
+}
+
+// TOC renders a linked table of contents of ctx.Steps and their headings
+// as a Markdown list. Anchors are the same as in the HTML output.
+func TOC(ctx Context) string {
+	a := docAnchors(ctx)
+	var b strings.Builder
+	for _, st := range ctx.Steps {
+		if !nodes.MatchEnv(st.Tags, ctx.Env) {
+			continue
+		}
+		fmt.Fprintf(&b, "- [%s](%s)\n", st.Title, a.href(st, a.steps[st]))
+		if st.Content == nil {
+			continue
+		}
+		for _, n := range st.Content.Nodes {
+			h, ok := n.(*nodes.HeaderNode)
+			if !ok || !nodes.MatchEnv(h.Env(), ctx.Env) {
+				continue
+			}
+			text := strings.TrimSpace(nodes.PlainText(h.Content.Nodes...))
+			var indent string
+			if h.Level > 1 {
+				indent = strings.Repeat("  ", h.Level-1)
+			}
+			fmt.Fprintf(&b, "%s- [%s](%s)\n", indent, text, a.href(st, a.headers[h]))
+		}
+	}
+	if hasGlossarySection(ctx) {
+		fmt.Fprintf(&b, "- [%s](%s)\n", glossaryTitle, a.href(nil, a.glossary))
+	}
+	return b.String()
+}
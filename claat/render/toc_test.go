@@ -0,0 +1,159 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestTOC(t *testing.T) {
+	h := func(level int, s string, env ...string) nodes.Node {
+		n := nodes.NewHeaderNode(level, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: s}))
+		n.MutateEnv(env)
+		return n
+	}
+	step := func(title string, nn ...nodes.Node) *types.Step {
+		return &types.Step{Title: title, Content: nodes.NewListNode(nn...)}
+	}
+	ctx := Context{
+		Env:  "web",
+		Meta: &types.Meta{Title: "Overview"},
+		Steps: []*types.Step{
+			step("Overview", h(2, "Setup"), h(3, "Install"), nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "text"})),
+			step("Deploy", h(2, "Setup"), h(2, "Kiosk only", "kiosk")),
+			{Title: "Kiosk step", Tags: []string{"kiosk"}},
+		},
+	}
+	want := "- [Overview](#overview-1)\n" +
+		"  - [Setup](#setup)\n" +
+		"    - [Install](#install)\n" +
+		"- [Deploy](#deploy)\n" +
+		"  - [Setup](#setup-1)\n"
+	if diff := cmp.Diff(want, TOC(ctx)); diff != "" {
+		t.Errorf("TOC got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestMDTemplateTOC(t *testing.T) {
+	clab := &types.Codelab{
+		Meta: types.Meta{Title: "Codelab"},
+		Steps: []*types.Step{
+			{Title: "Intro", Content: nodes.NewListNode(nodes.NewHeaderNode(2, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Goals"})))},
+		},
+	}
+	for _, toc := range []bool{false, true} {
+		var buf bytes.Buffer
+		data := &struct{ Context }{Context{Env: "web", Format: "md", Meta: &clab.Meta, Steps: clab.Steps, TOC: toc}}
+		if err := Execute(&buf, "md", data); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		out := buf.String()
+		want := "- [Intro](#intro)\n  - [Goals](#goals)\n"
+		if got := strings.Contains(out, want); got != toc {
+			t.Errorf("TOC=%v: output contains TOC = %v:\n%s", toc, got, out)
+		}
+		if toc && strings.Index(out, want) > strings.Index(out, "## Intro") {
+			t.Errorf("TOC is not at the top of the output:\n%s", out)
+		}
+	}
+}
+
+func TestAnchorsMatchTOC(t *testing.T) {
+	setup := nodes.NewHeaderNode(2, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Setup"}))
+	ctx := Context{
+		Env:  "web",
+		Meta: &types.Meta{Title: "Codelab"},
+		Steps: []*types.Step{
+			{Title: "Intro", Content: nodes.NewListNode(nodes.NewHeaderNode(2, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Setup"})))},
+			{Title: "Deploy", Content: nodes.NewListNode(setup)},
+		},
+	}
+	if toc := TOC(ctx); !strings.Contains(toc, "[Setup](#setup-1)") {
+		t.Errorf("TOC does not link to #setup-1:\n%s", toc)
+	}
+	want := `id="setup-1"`
+	out, err := HTML(ctx, setup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), want) {
+		t.Errorf("HTML(%v) = %q; want %s", setup, out, want)
+	}
+	out, err = Lite(ctx, setup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), want) {
+		t.Errorf("Lite(%v) = %q; want %s", setup, out, want)
+	}
+}
+
+func TestXref(t *testing.T) {
+	see := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "see "})
+	xref := nodes.NewXrefNode(3, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Step 3: Deploy"}))
+	ctx := Context{
+		Env:  "web",
+		Meta: &types.Meta{Title: "Codelab"},
+		Steps: []*types.Step{
+			{Title: "Intro", Content: nodes.NewListNode()},
+			{Title: "Kiosk setup", Tags: []string{"kiosk"}, Content: nodes.NewListNode()},
+			{Title: "Deploy", Content: nodes.NewListNode()},
+		},
+	}
+	tests := []struct {
+		name   string
+		render func(Context, ...nodes.Node) (string, error)
+		out    string
+	}{
+		{
+			name: "HTML",
+			render: func(ctx Context, nn ...nodes.Node) (string, error) {
+				s, err := HTML(ctx, nn...)
+				return string(s), err
+			},
+			out: `see <a href="#1">Step 3: Deploy</a>`,
+		},
+		{
+			name: "Lite",
+			render: func(ctx Context, nn ...nodes.Node) (string, error) {
+				s, err := Lite(ctx, nn...)
+				return string(s), err
+			},
+			out: `see <a href="step-3.html">Step 3: Deploy</a>`,
+		},
+		{
+			name:   "MD",
+			render: MD,
+			out:    "see [Step 3: Deploy](#deploy)",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := tc.render(ctx, see, xref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.out, out); diff != "" {
+				t.Errorf("render got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+// TutorialExt is the file extension of Cloud Shell tutorial output,
+// as in index.tutorial.md.
+const TutorialExt = "tutorial.md"
+
+// Link schemes of codelab links written as walkthrough directives
+// in Cloud Shell tutorial output, such as [Activate Cloud Shell](spotlight:devshell-activate-button)
+// and [main.go](editor:src/main.go). Other formats write them as is.
+const (
+	spotlightScheme = "spotlight:"
+	editorScheme    = "editor:"
+)
+
+// Tutorial renders nodes as the Markdown of a Cloud Shell tutorial
+// for the target env: Markdown with walkthrough directives, such as
+// spotlight pointers, and terminal code blocks Cloud Shell copies
+// to its terminal.
+func Tutorial(ctx Context, nodes ...nodes.Node) (string, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	mw := mdWriter{w: buf, env: ctx.Env, format: ctx.Format, Prefix: []byte(""), anchors: docAnchors(ctx), rtl: ctx.isRTL(), tutorial: true, keepRuntimeVars: ctx.KeepRuntimeVars, iconImages: ctx.IconImages}
+	if err := mw.write(nodes...); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// walkthrough writes link n as a walkthrough directive, returning false
+// if its URL has none of the walkthrough schemes.
+func (mw *mdWriter) walkthrough(n *nodes.URLNode) bool {
+	var tag, attr, val string
+	switch {
+	case strings.HasPrefix(n.URL, spotlightScheme):
+		tag, attr, val = "walkthrough-spotlight-pointer", "spotlightId", strings.TrimPrefix(n.URL, spotlightScheme)
+	case strings.HasPrefix(n.URL, editorScheme):
+		tag, attr, val = "walkthrough-editor-open-file", "filePath", strings.TrimPrefix(n.URL, editorScheme)
+	default:
+		return false
+	}
+	mw.space()
+	mw.writeString(fmt.Sprintf(`<%s %s="%s">`, tag, attr, html.EscapeString(val)))
+	mw.write(n.Content.Nodes...)
+	mw.writeString(fmt.Sprintf("</%s>", tag))
+	return true
+}
+
+// tutorialLang returns the language of the fenced block of code n
+// in a Cloud Shell tutorial: bash for terminal commands, which Cloud Shell
+// offers to copy to its terminal, and none for their output.
+func tutorialLang(n *nodes.CodeNode) string {
+	switch {
+	case n.Output:
+		return ""
+	case n.Term:
+		return "bash"
+	}
+	return n.Lang
+}
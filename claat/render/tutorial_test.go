@@ -0,0 +1,112 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestTutorial(t *testing.T) {
+	text := func(s string) *nodes.TextNode {
+		return nodes.NewTextNode(nodes.NewTextNodeOptions{Value: s})
+	}
+	output := nodes.NewCodeNode("total 0", true, "")
+	output.Output = true
+	numbered := nodes.NewCodeNode("fmt.Println()", false, "go")
+	numbered.LineNumbers = true
+	tests := []struct {
+		name string
+		in   nodes.Node
+		out  string
+	}{
+		{
+			name: "Spotlight",
+			in:   nodes.NewURLNode("spotlight:devshell-activate-button", text("Activate Cloud Shell")),
+			out:  ` <walkthrough-spotlight-pointer spotlightId="devshell-activate-button">Activate Cloud Shell</walkthrough-spotlight-pointer>`,
+		},
+		{
+			name: "Editor",
+			in:   nodes.NewURLNode("editor:src/main.go", text("main.go")),
+			out:  ` <walkthrough-editor-open-file filePath="src/main.go">main.go</walkthrough-editor-open-file>`,
+		},
+		{
+			name: "EditorEscaped",
+			in:   nodes.NewURLNode(`editor:src/"a"&<b>.go`, text("a.go")),
+			out:  ` <walkthrough-editor-open-file filePath="src/&#34;a&#34;&amp;&lt;b&gt;.go">a.go</walkthrough-editor-open-file>`,
+		},
+		{
+			name: "Link",
+			in:   nodes.NewURLNode("https://cloud.google.com", text("Cloud")),
+			out:  ` [Cloud](https://cloud.google.com)`,
+		},
+		{
+			name: "Terminal",
+			in:   nodes.NewCodeNode("gcloud config list", true, ""),
+			out:  "\n\n```bash\ngcloud config list\n```\n",
+		},
+		{
+			name: "Output",
+			in:   output,
+			out:  "\n\n```\ntotal 0\n```\n",
+		},
+		{
+			name: "CodeBlockAttrs",
+			in:   numbered,
+			out:  "\n\n```go\nfmt.Println()\n```\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := Tutorial(Context{}, tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.out, out); diff != "" {
+				t.Errorf("Tutorial got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExecuteTutorial(t *testing.T) {
+	step := func(title, cmd string) *types.Step {
+		return &types.Step{Title: title, Content: nodes.NewListNode(nodes.NewCodeNode(cmd, true, ""))}
+	}
+	data := &struct{ Context }{Context{
+		Meta: &types.Meta{Title: "Deploy", Summary: "Deploy an app.", Duration: 20},
+		Steps: []*types.Step{
+			step("Set up", "gcloud init"),
+			step("Deploy", "gcloud app deploy"),
+		},
+	}}
+	var buf bytes.Buffer
+	if err := Execute(&buf, "tutorial", data); err != nil {
+		t.Fatal(err)
+	}
+	want := "# Deploy\n\n" +
+		"<walkthrough-tutorial-duration duration=\"20\"></walkthrough-tutorial-duration>\n\n" +
+		"Deploy an app.\n\n" +
+		"## Set up\n\n```bash\ngcloud init\n```\n\n\n" +
+		"## Deploy\n\n```bash\ngcloud app deploy\n```\n\n\n" +
+		"<walkthrough-conclusion-trophy></walkthrough-conclusion-trophy>\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("Execute(tutorial) got diff (-want +got):\n%s", diff)
+	}
+}
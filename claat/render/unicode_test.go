@@ -0,0 +1,150 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"日本語", 6},
+		{"한국어", 6},
+		{"ｱ", 1}, // halfwidth katakana
+		{"ＡＢ", 4},
+		{"café", 4},
+		{"café", 4}, // combining acute accent
+		{"🚀", 2},
+		{"👩\u200d💻", 4}, // zero width joiner
+		{"\u200fمرحبا", 5},
+	}
+	for _, tc := range tests {
+		if got := displayWidth(tc.in); got != tc.want {
+			t.Errorf("displayWidth(%q) = %d; want %d", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestMDSpaceEnd(t *testing.T) {
+	// "ぅ" is encoded as E3 81 85; its last byte is not NEL.
+	nn := []nodes.Node{
+		nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "ぅ"}),
+		nodes.NewURLNode("https://example.com", nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "リンク"})),
+	}
+	out, err := MD(Context{Env: "web"}, nn...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "ぅ [リンク](https://example.com)"; out != want {
+		t.Errorf("MD = %q; want %q", out, want)
+	}
+}
+
+func TestMDEmphasisUnicodeSpace(t *testing.T) {
+	n := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "　太字 ", Bold: true})
+	out, err := MD(Context{Env: "web"}, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "　**太字** "; out != want {
+		t.Errorf("MD = %q; want %q", out, want)
+	}
+}
+
+// TestUnicodeConformance exports the Unicode fixtures of testdata/unicode
+// in all formats and checks that the output is valid UTF-8, keeps all text,
+// is stable in a md roundtrip and has aligned md tables.
+func TestUnicodeConformance(t *testing.T) {
+	files, err := filepath.Glob("testdata/unicode/*.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no fixtures in testdata/unicode")
+	}
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			src, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			clab, out1 := exportMD(t, src)
+			_, out2 := exportMD(t, []byte(out1))
+			if diff := cmp.Diff(out1, out2); diff != "" {
+				t.Errorf("md export is not stable (-first +second):\n%s", diff)
+			}
+			if !utf8.ValidString(out1) {
+				t.Errorf("md export is not valid UTF-8:\n%q", out1)
+			}
+			checkTables(t, out1)
+
+			ctx := Context{Env: "web", Meta: &clab.Meta, Steps: clab.Steps}
+			for _, st := range clab.Steps {
+				h, err := HTML(ctx, st.Content.Nodes...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				l, err := Lite(ctx, st.Content.Nodes...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !utf8.ValidString(string(h)) || !utf8.ValidString(string(l)) {
+					t.Errorf("step %q: HTML or Lite output is not valid UTF-8", st.Title)
+				}
+				nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+					tn, ok := n.(*nodes.TextNode)
+					if !ok || !entering {
+						return n, nil
+					}
+					s := strings.TrimSpace(tn.Value)
+					if !strings.Contains(out1, s) || !strings.Contains(string(l), s) {
+						t.Errorf("step %q: text %q is missing from md or Lite output", st.Title, s)
+					}
+					return n, nil
+				})
+			}
+		})
+	}
+}
+
+// checkTables checks that all rows of each Markdown table in md
+// have the same display width.
+func checkTables(t *testing.T, md string) {
+	t.Helper()
+	width := -1
+	for _, line := range strings.Split(md, "\n") {
+		if !strings.HasPrefix(line, "|") {
+			width = -1
+			continue
+		}
+		w := displayWidth(line)
+		if width >= 0 && w != width {
+			t.Errorf("table row %q has width %d; want %d", line, w, width)
+		}
+		width = w
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// allNodes are all the node types.
+const allNodes = nodes.NodeList | nodes.NodeGrid | nodes.NodeText | nodes.NodeCode |
+	nodes.NodeInfobox | nodes.NodeSurvey | nodes.NodeURL | nodes.NodeImage | nodes.NodeButton |
+	nodes.NodeItemsList | nodes.NodeItemsCheck | nodes.NodeItemsFAQ |
+	nodes.NodeHeader | nodes.NodeHeaderCheck | nodes.NodeHeaderFAQ |
+	nodes.NodeYouTube | nodes.NodeIframe | nodes.NodeImport | nodes.NodeXref | nodes.NodeTerm |
+	nodes.NodeActivity | nodes.NodeAttachment | nodes.NodeVideo | nodes.NodeReview | nodes.NodeIcon |
+	nodes.NodeKbd
+
+// rendered are the node types written by the renderers of the built-in
+// formats: HTML for html, Markdown for md, Tutorial for tutorial and Lite for offline.
+// Renderers skip nodes of other types, along with their content.
+// Keep in sync with the type switches of the renderers.
+var rendered = map[string]nodes.NodeType{
+	"html":     allNodes,
+	"md":       allNodes,
+	"offline":  allNodes &^ nodes.NodeIframe,
+	"tutorial": allNodes,
+}
+
+// Unsupported returns warnings about the nodes of steps which cannot be
+// rendered in format, such as iframes in tutorials, which are dropped
+// or replaced with a fallback. Nodes of custom template formats are
+// not checked.
+func Unsupported(format string, steps []*types.Step) []string {
+	known, ok := rendered[format]
+	if !ok {
+		return nil
+	}
+	var res []string
+	for i, st := range steps {
+		if st.Content == nil {
+			continue
+		}
+		nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			if !entering {
+				return n, nil
+			}
+			if n.Type()&known == 0 {
+				res = append(res, fmt.Sprintf("%s: %s output has no renderer for %s nodes, dropped", st.Location(i+1, n), format, n.Type()))
+				return n, nodes.SkipChildren
+			}
+			if n, ok := n.(*nodes.IframeNode); ok && format == "tutorial" {
+				res = append(res, fmt.Sprintf("%s: %s output cannot embed iframe %s, written as a link", st.Location(i+1, n), format, n.URL))
+			}
+			return n, nil
+		})
+	}
+	return res
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestUnsupported(t *testing.T) {
+	iframe := nodes.NewIframeNode("https://codepen.io/foo")
+	steps := []*types.Step{
+		{Title: "Intro", Content: nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "text"}))},
+		{Title: "Demo", Content: nodes.NewListNode(nodes.NewInfoboxNode(nodes.InfoboxPositive, iframe))},
+		{Title: "Empty"},
+	}
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"html", nil},
+		{"md", nil},
+		{"offline", []string{`step 2 "Demo": offline output has no renderer for iframe nodes, dropped`}},
+		{"tutorial", []string{`step 2 "Demo": tutorial output cannot embed iframe https://codepen.io/foo, written as a link`}},
+		{"custom.html", nil},
+	}
+	for _, tc := range tests {
+		if diff := cmp.Diff(tc.want, Unsupported(tc.format, steps)); diff != "" {
+			t.Errorf("Unsupported(%q) got diff (-want +got):\n%s", tc.format, diff)
+		}
+	}
+}
+
+// TestRendered checks that rendered matches the type switches
+// of the renderers: nodes of a format's types are written,
+// and nodes of other types are not.
+func TestRendered(t *testing.T) {
+	writers := map[string]func(io.Writer, nodes.Node) error{
+		"html":    func(w io.Writer, n nodes.Node) error { return WriteHTML(w, "web", "html", n) },
+		"md":      func(w io.Writer, n nodes.Node) error { return WriteMD(w, "web", "md", n) },
+		"offline": func(w io.Writer, n nodes.Node) error { return WriteLite(w, "web", n) },
+		"tutorial": func(w io.Writer, n nodes.Node) error {
+			s, err := Tutorial(Context{Env: "web"}, n)
+			io.WriteString(w, s)
+			return err
+		},
+	}
+	for format, known := range rendered {
+		write, ok := writers[format]
+		if !ok {
+			t.Errorf("no writer to test the %s renderer with", format)
+			continue
+		}
+		seen := map[nodes.NodeType]bool{}
+		corpus := conformanceCorpus()
+		imp := nodes.NewImportNode("frag.md")
+		imp.Content.Append(conformanceText("imported"))
+		corpus["importContent"] = []nodes.Node{imp}
+		corpus["xref"] = []nodes.Node{nodes.NewXrefNode(2, conformanceText("next step"))}
+		corpus["term"] = []nodes.Node{nodes.NewTermNode("VPC", "A virtual network.", conformanceText("VPC"))}
+		for name, nn := range corpus {
+			nodes.WalkNodes(nn, func(n nodes.Node, entering bool) (nodes.Node, error) {
+				if !entering || n.Empty() {
+					return n, nil
+				}
+				seen[n.Type()] = true
+				var buf bytes.Buffer
+				if err := write(&buf, n); err != nil {
+					t.Errorf("%s %s: %v", format, name, err)
+				} else if got, want := buf.Len() > 0, n.Type()&known != 0; got != want {
+					t.Errorf("%s %s: %s node written = %v; want %v", format, name, n.Type(), got, want)
+				}
+				return n, nil
+			})
+		}
+		for typ := nodes.NodeList; typ <= nodes.NodeVideo; typ <<= 1 {
+			if !seen[typ] && typ&(nodes.NodeHeaderCheck|nodes.NodeHeaderFAQ|nodes.NodeItemsCheck|nodes.NodeItemsFAQ) == 0 {
+				t.Errorf("%s: no %s node in the corpus", format, typ)
+			}
+		}
+	}
+}
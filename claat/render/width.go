@@ -0,0 +1,118 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// wideRunes are the East Asian Wide and Fullwidth characters,
+// and emoji, which take two columns in a monospace font.
+var wideRunes = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x1100, Hi: 0x115f, Stride: 1}, // Hangul Jamo initial consonants
+		{Lo: 0x2e80, Hi: 0x303e, Stride: 1}, // CJK radicals, symbols and punctuation
+		{Lo: 0x3041, Hi: 0x33ff, Stride: 1}, // Hiragana, Katakana, Bopomofo, CJK compatibility
+		{Lo: 0x3400, Hi: 0x4dbf, Stride: 1}, // CJK Unified Ideographs Extension A
+		{Lo: 0x4e00, Hi: 0x9fff, Stride: 1}, // CJK Unified Ideographs
+		{Lo: 0xa000, Hi: 0xa4cf, Stride: 1}, // Yi
+		{Lo: 0xac00, Hi: 0xd7a3, Stride: 1}, // Hangul syllables
+		{Lo: 0xf900, Hi: 0xfaff, Stride: 1}, // CJK Compatibility Ideographs
+		{Lo: 0xfe30, Hi: 0xfe4f, Stride: 1}, // CJK compatibility forms
+		{Lo: 0xff00, Hi: 0xff60, Stride: 1}, // Fullwidth forms
+		{Lo: 0xffe0, Hi: 0xffe6, Stride: 1}, // Fullwidth signs
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f300, Hi: 0x1f64f, Stride: 1}, // Miscellaneous symbols and pictographs, emoticons
+		{Lo: 0x1f680, Hi: 0x1f6ff, Stride: 1}, // Transport and map symbols
+		{Lo: 0x1f900, Hi: 0x1f9ff, Stride: 1}, // Supplemental symbols and pictographs
+		{Lo: 0x20000, Hi: 0x2fffd, Stride: 1}, // CJK Unified Ideographs Extension B and later
+		{Lo: 0x30000, Hi: 0x3fffd, Stride: 1}, // CJK Unified Ideographs Extension G and later
+	},
+}
+
+// displayWidth returns the number of monospace columns s takes:
+// two for wide characters, none for combining marks and format
+// characters, such as a zero width joiner, and one for others.
+func displayWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		w += runeWidth(r)
+	}
+	return w
+}
+
+// runeWidth returns the number of monospace columns r takes.
+func runeWidth(r rune) int {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case unicode.Is(wideRunes, r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// widthWriter is an io.Writer which discards everything written to it,
+// keeping only the display width of the text and whether it has a newline.
+type widthWriter struct {
+	width   int    // display width of the text written so far
+	newline bool   // text written so far has a newline
+	partial []byte // incomplete rune at the end of the last write
+}
+
+func (w *widthWriter) Write(b []byte) (int, error) {
+	n := len(b)
+	if len(w.partial) > 0 {
+		b = append(w.partial, b...)
+		w.partial = nil
+	}
+	for len(b) > 0 {
+		if !utf8.FullRune(b) {
+			w.partial = append([]byte(nil), b...)
+			break
+		}
+		r, size := utf8.DecodeRune(b)
+		w.rune(r)
+		b = b[size:]
+	}
+	return n, nil
+}
+
+func (w *widthWriter) WriteString(s string) (int, error) {
+	if len(w.partial) > 0 {
+		return w.Write([]byte(s))
+	}
+	n := len(s)
+	for len(s) > 0 {
+		if !utf8.FullRuneInString(s) {
+			w.partial = []byte(s)
+			break
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		w.rune(r)
+		s = s[size:]
+	}
+	return n, nil
+}
+
+func (w *widthWriter) rune(r rune) {
+	if r == '\n' {
+		w.newline = true
+	}
+	w.width += runeWidth(r)
+}
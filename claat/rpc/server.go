@@ -0,0 +1,123 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rpc implements the Converter gRPC service of claat.proto.
+//
+// The messages and service stubs are generated from claat.proto
+// by protoc-gen-go and protoc-gen-go-grpc; run go generate after
+// changing it.
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative claat.proto
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/googlecodelabs/tools/claat/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ServiceName is the full name of the Converter service.
+	ServiceName = "claat.v1.Converter"
+
+	// MaxMessageSize is the maximum size of a received message,
+	// and of the source of a RenderStream call.
+	MaxMessageSize = 32 << 20
+	// chunkSize is the size of the content of RenderStream responses.
+	chunkSize = 64 << 10
+)
+
+// RenderFunc renders the codelab of req into w and returns its metadata.
+// The ctx is done when the call is cancelled by the client, or its
+// deadline expires. Errors which are not a gRPC status have the Unknown
+// code, except context errors which have the Canceled or DeadlineExceeded code.
+type RenderFunc func(ctx context.Context, req *RenderRequest, w io.Writer) (*types.Meta, error)
+
+// Server implements the Converter service with a RenderFunc.
+// Register it with RegisterConverterServer.
+type Server struct {
+	UnimplementedConverterServer
+	render RenderFunc
+}
+
+// NewServer returns a server rendering codelabs with render.
+func NewServer(render RenderFunc) *Server {
+	return &Server{render: render}
+}
+
+// Render implements ConverterServer.
+func (s *Server) Render(ctx context.Context, req *RenderRequest) (*RenderResponse, error) {
+	var buf bytes.Buffer
+	meta, err := s.render(ctx, req, &buf)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &RenderResponse{Content: buf.Bytes(), Id: meta.ID, Title: meta.Title}, nil
+}
+
+// RenderStream implements ConverterServer.
+func (s *Server) RenderStream(stream Converter_RenderStreamServer) error {
+	req := &RenderRequest{}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if len(req.Source)+len(chunk.Source) > MaxMessageSize {
+			return status.Errorf(codes.InvalidArgument, "source is larger than %d bytes", MaxMessageSize)
+		}
+		req.Source = append(req.Source, chunk.Source...)
+		if req.Format == "" {
+			req.Format = chunk.Format
+		}
+		if req.Doc == "" {
+			req.Doc = chunk.Doc
+		}
+	}
+	var buf bytes.Buffer
+	meta, err := s.render(stream.Context(), req, &buf)
+	if err != nil {
+		return statusError(err)
+	}
+	res := &RenderResponse{Id: meta.ID, Title: meta.Title}
+	content := buf.Bytes()
+	for first := true; first || len(content) > 0; first = false {
+		n := chunkSize
+		if n > len(content) {
+			n = len(content)
+		}
+		res.Content, content = content[:n], content[n:]
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+		res = &RenderResponse{}
+	}
+	return nil
+}
+
+// statusError returns err as a gRPC status error: err itself if it is one,
+// or an error with the code of a context error, or Unknown.
+func statusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.FromContextError(err).Err()
+}
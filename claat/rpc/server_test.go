@@ -0,0 +1,145 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// newClient serves s in memory and returns a client connection to it.
+func newClient(t *testing.T, s *Server) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	g := grpc.NewServer()
+	RegisterConverterServer(g, s)
+	go g.Serve(lis)
+	t.Cleanup(g.Stop)
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestServer(t *testing.T) {
+	s := NewServer(func(ctx context.Context, req *RenderRequest, w io.Writer) (*types.Meta, error) {
+		switch {
+		case req.Format == "bad":
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported format %q", req.Format)
+		case req.Doc == "timeout":
+			return nil, fmt.Errorf("fetching: %w", context.DeadlineExceeded)
+		case req.Doc != "":
+			return nil, errors.New("no access")
+		}
+		_, err := w.Write(bytes.ToUpper(req.Source))
+		return &types.Meta{ID: "lab", Title: "Lab"}, err
+	})
+	client := NewConverterClient(newClient(t, s))
+	ctx := context.Background()
+
+	out, err := client.Render(ctx, &RenderRequest{Source: []byte("# lab")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &RenderResponse{Content: []byte("# LAB"), Id: "lab", Title: "Lab"}
+	if diff := cmp.Diff(want, out, protocmp.Transform()); diff != "" {
+		t.Errorf("Render got diff (-want +got): %s", diff)
+	}
+
+	stream, err := client.RenderStream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	big := strings.Repeat("x", chunkSize+10)
+	for _, req := range []*RenderRequest{{Source: []byte(big[:10]), Format: "md"}, {Source: []byte(big[10:])}} {
+		if err := stream.Send(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	var chunks []*RenderResponse
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunks = append(chunks, res)
+	}
+	wantChunks := []*RenderResponse{
+		{Content: []byte(strings.ToUpper(big[:chunkSize])), Id: "lab", Title: "Lab"},
+		{Content: []byte(strings.ToUpper(big[chunkSize:]))},
+	}
+	if diff := cmp.Diff(wantChunks, chunks, protocmp.Transform()); diff != "" {
+		t.Errorf("RenderStream got diff (-want +got): %s", diff)
+	}
+
+	tests := []struct {
+		req  *RenderRequest
+		code codes.Code
+		msg  string
+	}{
+		{&RenderRequest{Format: "bad"}, codes.InvalidArgument, `unsupported format "bad"`},
+		{&RenderRequest{Doc: "doc"}, codes.Unknown, "no access"},
+		{&RenderRequest{Doc: "timeout"}, codes.DeadlineExceeded, "fetching: context deadline exceeded"},
+	}
+	for _, tc := range tests {
+		_, err := client.Render(ctx, tc.req)
+		if st := status.Convert(err); st.Code() != tc.code || st.Message() != tc.msg {
+			t.Errorf("Render(%v) = %v; want %s %q", tc.req, err, tc.code, tc.msg)
+		}
+	}
+}
+
+func TestServerDeadline(t *testing.T) {
+	s := NewServer(func(ctx context.Context, req *RenderRequest, w io.Writer) (*types.Meta, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	client := NewConverterClient(newClient(t, s))
+	// the deadline of the client is sent as grpc-timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.Render(ctx, &RenderRequest{})
+	if code := status.Code(err); code != codes.DeadlineExceeded {
+		t.Errorf("Render() past its deadline = %v; want %s", err, codes.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package telemetry reports anonymous claat usage to an opt-in endpoint.
+//
+// Nothing is ever sent unless an endpoint is configured explicitly.
+// Reports carry no source IDs, file names, content or user identity.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"time"
+)
+
+// timeout limits how long a report may delay the process exit.
+const timeout = 3 * time.Second
+
+// Event is a single anonymous usage report.
+type Event struct {
+	Command    string `json:"command"`               // Subcommand, e.g. "export"
+	Format     string `json:"format,omitempty"`      // Output format, e.g. "html"
+	DurationMS int64  `json:"duration_ms"`           // Wall time of the command
+	ErrorClass string `json:"error_class,omitempty"` // Coarse failure kind, empty on success
+	Version    string `json:"version,omitempty"`     // claat version
+	OS         string `json:"os"`                    // runtime.GOOS
+	Arch       string `json:"arch"`                  // runtime.GOARCH
+}
+
+// NewEvent creates a new usage event for cmd and output format,
+// measuring duration since start.
+func NewEvent(cmd, format, version string, start time.Time) *Event {
+	return &Event{
+		Command:    cmd,
+		Format:     format,
+		DurationMS: int64(time.Since(start) / time.Millisecond),
+		Version:    version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+}
+
+// Error classes of Event.ErrorClass.
+const (
+	ErrorUsage    = "usage"    // invalid command, flags or option files
+	ErrorCanceled = "canceled" // interrupted, e.g. with Ctrl-C
+	ErrorCommand  = "command"  // the command ran and failed
+)
+
+// ErrorClass returns a coarse classification of the failure of a command
+// exiting with exitCode, suitable for Event.ErrorClass: empty on success,
+// ErrorCanceled if ctxErr, the error of the context of the command,
+// is context.Canceled, ErrorCommand if the command started,
+// and ErrorUsage otherwise.
+func ErrorClass(exitCode int, started bool, ctxErr error) string {
+	switch {
+	case exitCode == 0:
+		return ""
+	case errors.Is(ctxErr, context.Canceled):
+		return ErrorCanceled
+	case started:
+		return ErrorCommand
+	}
+	return ErrorUsage
+}
+
+// Report posts e as JSON to endpoint.
+// It is a no-op if endpoint is empty.
+func Report(endpoint string, e *Event) error {
+	if endpoint == "" {
+		return nil
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: timeout}
+	res, err := client.Post(endpoint, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("telemetry %s: %s", endpoint, res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReport(t *testing.T) {
+	var got Event
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Decode: %v", err)
+		}
+	}))
+	defer ts.Close()
+
+	e := &Event{Command: "export", Format: "md", DurationMS: 42, ErrorClass: ErrorCommand, OS: "linux", Arch: "amd64"}
+	if err := Report(ts.URL, e); err != nil {
+		t.Fatalf("Report(%q, %+v) = %v", ts.URL, e, err)
+	}
+	if diff := cmp.Diff(*e, got); diff != "" {
+		t.Errorf("Report(%q) got diff (-want +got): %s", ts.URL, diff)
+	}
+}
+
+func TestReportDisabled(t *testing.T) {
+	if err := Report("", &Event{Command: "export"}); err != nil {
+		t.Errorf("Report(\"\") = %v, want nil", err)
+	}
+}
+
+func TestReportServerError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+	if err := Report(ts.URL, &Event{Command: "export"}); err == nil {
+		t.Errorf("Report(%q) = nil, want error", ts.URL)
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		code    int
+		started bool
+		ctxErr  error
+		out     string
+	}{
+		{0, true, nil, ""},
+		{0, true, context.Canceled, ""},
+		{1, false, nil, ErrorUsage},
+		{1, true, nil, ErrorCommand},
+		{1, true, context.Canceled, ErrorCanceled},
+	}
+	for _, tc := range tests {
+		if out := ErrorClass(tc.code, tc.started, tc.ctxErr); out != tc.out {
+			t.Errorf("ErrorClass(%d, %t, %v) = %q, want %q", tc.code, tc.started, tc.ctxErr, out, tc.out)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// NumberActivities numbers the Qwiklabs activity tracking blocks of clab
+// which have no step number yet, following the previous block in document
+// order, starting at 1.
+//
+// It returns an error if a step number is used more than once,
+// or if step numbers are not sequential in document order.
+func NumberActivities(clab *types.Codelab) error {
+	next := 1
+	seen := make(map[int]bool)
+	for i, st := range clab.Steps {
+		if st.Content == nil {
+			continue
+		}
+		var at nodes.Node // activity node in error
+		_, err := nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			an, ok := n.(*nodes.ActivityNode)
+			if !ok || !entering {
+				return n, nil
+			}
+			at = n
+			switch {
+			case an.Step == 0:
+				an.Step = next
+			case seen[an.Step]:
+				return n, fmt.Errorf("activity tracking step %d is used more than once", an.Step)
+			case an.Step != next:
+				return n, fmt.Errorf("activity tracking step %d is out of sequence, want %d", an.Step, next)
+			}
+			seen[an.Step] = true
+			next = an.Step + 1
+			return n, nodes.SkipChildren
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %v", st.Location(i+1, at), err)
+		}
+	}
+	return nil
+}
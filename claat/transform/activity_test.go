@@ -0,0 +1,89 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestNumberActivities(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps [][]int // step numbers of activity tracking blocks, per codelab step
+		out   []int
+		err   string
+	}{
+		{
+			name:  "Auto",
+			steps: [][]int{{0, 0}, {0}},
+			out:   []int{1, 2, 3},
+		},
+		{
+			name:  "Mixed",
+			steps: [][]int{{1}, {0, 3}, {}, {0}},
+			out:   []int{1, 2, 3, 4},
+		},
+		{
+			name:  "Duplicate",
+			steps: [][]int{{1}, {0, 2}},
+			err:   `step 2 "Step 2": activity tracking step 2 is used more than once`,
+		},
+		{
+			name:  "Gap",
+			steps: [][]int{{1, 3}},
+			err:   `step 1 "Step 1": activity tracking step 3 is out of sequence, want 2`,
+		},
+		{
+			name:  "StartsAtTwo",
+			steps: [][]int{{}, {2}},
+			err:   `step 2 "Step 2": activity tracking step 2 is out of sequence, want 1`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clab := types.NewCodelab()
+			var all []*nodes.ActivityNode
+			for i, nums := range tc.steps {
+				st := clab.NewStep("Step " + string(rune('1'+i)))
+				for _, num := range nums {
+					an := nodes.NewActivityNode(num, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "objective"}))
+					all = append(all, an)
+					st.Content.Append(an)
+				}
+			}
+			err := NumberActivities(clab)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("NumberActivities() = %v; want %s", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NumberActivities() = %v", err)
+			}
+			var got []int
+			for _, an := range all {
+				got = append(got, an.Step)
+			}
+			if diff := cmp.Diff(tc.out, got); diff != "" {
+				t.Errorf("NumberActivities() step numbers got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// Annotations moves the annotations written in the alt text of the images
+// of clab, such as {box 10 20 40 30 Open the menu}, to their Annotations,
+// as parsed by nodes.ParseAnnotations, so that they are drawn over the image.
+// Images with annotations already are left untouched.
+func Annotations(clab *types.Codelab) {
+	for _, st := range clab.Steps {
+		if st.Content == nil {
+			continue
+		}
+		for _, img := range nodes.ImageNodes(st.Content.Nodes) {
+			if img.Annotations == nil {
+				img.Alt, img.Annotations = nodes.ParseAnnotations(img.Alt)
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestAnnotations(t *testing.T) {
+	img := nodes.NewImageNode(nodes.NewImageNodeOptions{Src: "a.png", Alt: "Console {marker 10 20}"})
+	clab := &types.Codelab{Steps: []*types.Step{{Title: "Setup", Content: nodes.NewListNode(nodes.NewListNode(img))}}}
+	Annotations(clab)
+	if img.Alt != "Console" {
+		t.Errorf("Alt = %q; want %q", img.Alt, "Console")
+	}
+	want := []nodes.Annotation{{Kind: nodes.AnnotationMarker, X: 10, Y: 20, Label: "1"}}
+	if diff := cmp.Diff(want, img.Annotations); diff != "" {
+		t.Errorf("Annotations got diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// Audiences of a codelab export, rendered from the same source:
+// instructor guides show its instructor notes, and student handouts
+// strip them.
+const (
+	AudienceInstructor = "instructor"
+	AudienceStudent    = "student"
+)
+
+// Audiences are the audiences of codelab exports.
+var Audiences = []string{AudienceInstructor, AudienceStudent}
+
+// InstructorEnv is the environment tag of instructor notes, such as answers,
+// common pitfalls and timing guidance, as in "Environment: instructor".
+const InstructorEnv = "instructor"
+
+// instructorLabel starts the infobox of instructor notes.
+const instructorLabel = "Instructor notes"
+
+// Audience prepares the content of clab for audience, whatever
+// the environment it is exported to. For AudienceInstructor, consecutive
+// top-level instructor notes of a step are shown in an infobox of
+// nodes.InfoboxInstructor kind, and nested ones as is. For AudienceStudent,
+// they are removed at any depth, along with the steps they were all of. Other audiences, such as "", leave clab untouched, so that
+// instructor notes are only visible in the instructor environment.
+func Audience(clab *types.Codelab, audience string) {
+	if audience != AudienceInstructor && audience != AudienceStudent {
+		return
+	}
+	var steps []*types.Step
+	for _, st := range clab.Steps {
+		if audience == AudienceInstructor {
+			showInstructorNotes(st.Content)
+		} else {
+			eachList(st.Content, func(l *nodes.ListNode) {
+				l.Nodes = instructorNotes(l.Nodes, false)
+			})
+		}
+		if audience == AudienceStudent && st.Content != nil && st.Content.Empty() && hasEnv(st.Tags, InstructorEnv) {
+			continue
+		}
+		st.Tags = withoutEnv(st.Tags, InstructorEnv)
+		steps = append(steps, st)
+	}
+	clab.Steps = steps
+	clab.Tags = withoutEnv(clab.Tags, InstructorEnv)
+}
+
+// instructorNotes returns nn with the instructor notes among them grouped
+// in infoboxes, visible in all environments, if show is true,
+// or removed otherwise.
+func instructorNotes(nn []nodes.Node, show bool) []nodes.Node {
+	var res []nodes.Node
+	var box *nodes.InfoboxNode
+	for _, n := range nn {
+		if !hasEnv(n.Env(), InstructorEnv) {
+			res = append(res, n)
+			box = nil
+			continue
+		}
+		if !show {
+			continue
+		}
+		n.MutateEnv(withoutEnv(n.Env(), InstructorEnv))
+		if box == nil {
+			label := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: instructorLabel, Bold: true})
+			box = nodes.NewInfoboxNode(nodes.InfoboxInstructor, nodes.NewListNode(label))
+			res = append(res, box)
+		}
+		box.Content.Append(n)
+	}
+	return res
+}
+
+// showInstructorNotes groups the top-level instructor notes of content
+// in infoboxes and removes the instructor tag of all nodes of content,
+// so that the notes are visible in all environments. The nodes of a note,
+// such as the text of a paragraph, carry its tag too: only the note itself
+// is boxed.
+func showInstructorNotes(content *nodes.ListNode) {
+	if content == nil {
+		return
+	}
+	content.Nodes = instructorNotes(content.Nodes, true)
+	nodes.WalkNodes(content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if entering {
+			n.MutateEnv(withoutEnv(n.Env(), InstructorEnv))
+		}
+		return n, nil
+	})
+}
+
+// hasEnv reports whether environment tags include env.
+func hasEnv(tags []string, env string) bool {
+	for _, t := range tags {
+		if t == env {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutEnv returns environment tags without env, or nil if none is left.
+// Tags without env are returned as is.
+func withoutEnv(tags []string, env string) []string {
+	if !hasEnv(tags, env) {
+		return tags
+	}
+	var res []string
+	for _, t := range tags {
+		if t != env {
+			res = append(res, t)
+		}
+	}
+	return res
+}
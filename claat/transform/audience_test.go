@@ -0,0 +1,156 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// audienceString describes the steps of clab, their environment tags
+// and content, e.g. "Intro [instructor]: a | {instructor: Instructor notes | answer}".
+func audienceString(clab *types.Codelab) []string {
+	var desc func(nn []nodes.Node) string
+	desc = func(nn []nodes.Node) string {
+		var ss []string
+		for _, n := range nn {
+			s := nodes.PlainText(n)
+			if ib, ok := n.(*nodes.InfoboxNode); ok {
+				s = "{" + string(ib.Kind) + ": " + desc(ib.Content.Nodes) + "}"
+			}
+			if len(n.Env()) > 0 {
+				s += " " + strings.Join(n.Env(), ",")
+			}
+			ss = append(ss, s)
+		}
+		return strings.Join(ss, " | ")
+	}
+	var res []string
+	for _, st := range clab.Steps {
+		res = append(res, st.Title+" "+strings.Join(st.Tags, ",")+": "+desc(st.Content.Nodes))
+	}
+	return res
+}
+
+func TestAudience(t *testing.T) {
+	text := func(v string, env ...string) nodes.Node {
+		n := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: v})
+		n.MutateEnv(env)
+		return n
+	}
+	codelab := func() *types.Codelab {
+		clab := types.NewCodelab()
+		clab.Tags = []string{"instructor", "web"}
+		clab.Steps = []*types.Step{
+			{Title: "Intro", Tags: []string{"instructor", "web"}, Content: nodes.NewListNode(
+				text("a"),
+				text("answer", "instructor"),
+				text("pitfall", "instructor", "web"),
+				text("b", "web"),
+				text("timing", "instructor"),
+			)},
+			{Title: "Answers", Tags: []string{"instructor"}, Content: nodes.NewListNode(text("all", "instructor"))},
+			{Title: "Wrap up", Content: nodes.NewListNode(text("c"))},
+		}
+		return clab
+	}
+	tests := []struct {
+		audience string
+		out      []string
+		tags     []string
+	}{
+		{
+			audience: AudienceInstructor,
+			out: []string{
+				"Intro web: a | {instructor: Instructor notes | answer | pitfall web} | b web | {instructor: Instructor notes | timing}",
+				"Answers : {instructor: Instructor notes | all}",
+				"Wrap up : c",
+			},
+			tags: []string{"web"},
+		},
+		{
+			audience: AudienceStudent,
+			out: []string{
+				"Intro web: a | b web",
+				"Wrap up : c",
+			},
+			tags: []string{"web"},
+		},
+		{
+			audience: "",
+			out: []string{
+				"Intro instructor,web: a | answer instructor | pitfall instructor,web | b web | timing instructor",
+				"Answers instructor: all instructor",
+				"Wrap up : c",
+			},
+			tags: []string{"instructor", "web"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.audience, func(t *testing.T) {
+			clab := codelab()
+			Audience(clab, tc.audience)
+			if diff := cmp.Diff(tc.out, audienceString(clab)); diff != "" {
+				t.Errorf("Audience(%q) got diff (-want +got):\n%s", tc.audience, diff)
+			}
+			if diff := cmp.Diff(tc.tags, clab.Tags); diff != "" {
+				t.Errorf("Audience(%q) tags got diff (-want +got):\n%s", tc.audience, diff)
+			}
+		})
+	}
+}
+
+func TestAudienceParagraph(t *testing.T) {
+	codelab := func() *types.Codelab {
+		// a parser tags a paragraph and its text alike
+		text := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "The answer is 42."})
+		text.MutateEnv([]string{"instructor"})
+		p := nodes.NewListNode(text)
+		p.MutateBlock(true)
+		p.MutateEnv([]string{"instructor"})
+		clab := types.NewCodelab()
+		clab.Steps = []*types.Step{{Title: "Quiz", Content: nodes.NewListNode(p)}}
+		return clab
+	}
+	clab := codelab()
+	Audience(clab, AudienceInstructor)
+	want := []string{"Quiz : {instructor: Instructor notes | The answer is 42.}"}
+	if diff := cmp.Diff(want, audienceString(clab)); diff != "" {
+		t.Errorf("Audience(instructor) got diff (-want +got):\n%s", diff)
+	}
+	boxes := 0
+	nodes.WalkNodes(clab.Steps[0].Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if _, ok := n.(*nodes.InfoboxNode); ok && entering {
+			boxes++
+		}
+		if entering && len(n.Env()) > 0 {
+			t.Errorf("Audience(instructor) left %v tags on a %s node", n.Env(), n.Type())
+		}
+		return n, nil
+	})
+	if boxes != 1 {
+		t.Errorf("Audience(instructor) made %d infoboxes; want 1", boxes)
+	}
+
+	clab = codelab()
+	Audience(clab, AudienceStudent)
+	if want := []string{"Quiz : "}; !cmp.Equal(want, audienceString(clab)) {
+		t.Errorf("Audience(student) = %q; want %q", audienceString(clab), want)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// consoleHost is the host console buttons must link to.
+const consoleHost = "console.cloud.google.com"
+
+// ValidateButtons checks the links of the buttons of clab and returns
+// the problems found: "Open Google Cloud console" buttons must link to
+// the Google Cloud console.
+func ValidateButtons(clab *types.Codelab) []string {
+	var res []string
+	for i, st := range clab.Steps {
+		if st.Content == nil {
+			continue
+		}
+		nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			un, ok := n.(*nodes.URLNode)
+			if !ok || !entering || len(un.Content.Nodes) == 0 {
+				return n, nil
+			}
+			btn, ok := un.Content.Nodes[0].(*nodes.ButtonNode)
+			if !ok || btn.Variant != nodes.ButtonConsole {
+				return n, nil
+			}
+			if u, err := url.Parse(un.URL); err != nil || u.Scheme != "https" || !strings.EqualFold(u.Hostname(), consoleHost) {
+				text := strings.TrimSpace(nodes.PlainText(btn.Content.Nodes...))
+				res = append(res, fmt.Sprintf("%s: console button %q links to %s, want https://%s", st.Location(i+1, un), text, un.URL, consoleHost))
+			}
+			return n, nil
+		})
+	}
+	return res
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestValidateButtons(t *testing.T) {
+	button := func(href, text string) nodes.Node {
+		btn := nodes.NewButtonNode(true, true, false, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: text}))
+		btn.Variant = nodes.ButtonVariantOf(text)
+		return nodes.NewURLNode(href, btn)
+	}
+	clab := types.NewCodelab()
+	clab.NewStep("Setup").Content.Append(
+		button("https://console.cloud.google.com/home", "Open Google Cloud console"),
+		button("https://console.google.com/", "Open console"),
+		button("https://example.com/", "Download SDK"),
+	)
+	want := []string{`step 1 "Setup": console button "Open console" links to https://console.google.com/, want https://console.cloud.google.com`}
+	if diff := cmp.Diff(want, ValidateButtons(clab)); diff != "" {
+		t.Errorf("ValidateButtons got diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// captionTitleRegexp matches the prefix of an image title,
+// such as the alt title of a Google Doc image, which is a caption.
+var captionTitleRegexp = regexp.MustCompile(`(?i)^caption\s*:\s*`)
+
+// DefaultFigureLabel is the label of numbered figures of codelabs
+// in a language without one in FigureLabels.
+const DefaultFigureLabel = "Figure {n}"
+
+// FigureLabels maps languages, by ISO 639 code, to the label of numbered
+// figures of codelabs in that language, with {n} replaced by the number.
+var FigureLabels = map[string]string{
+	"de": "Abbildung {n}",
+	"en": "Figure {n}",
+	"es": "Figura {n}",
+	"fr": "Figure {n}",
+	"id": "Gambar {n}",
+	"it": "Figura {n}",
+	"ja": "図 {n}",
+	"ko": "그림 {n}",
+	"nl": "Figuur {n}",
+	"pl": "Rysunek {n}",
+	"pt": "Figura {n}",
+	"ru": "Рисунок {n}",
+	"tr": "Şekil {n}",
+	"zh": "图 {n}",
+}
+
+// CaptionOptions controls the captions of images.
+type CaptionOptions struct {
+	// Number numbers the images with a caption as figures, in document order,
+	// starting at 1.
+	Number bool
+	// Label is the label of numbered figures, with {n} replaced by
+	// the number, such as "Fig. {n}". If empty, it is that of the language
+	// of the codelab in FigureLabels.
+	Label string
+}
+
+// label returns the label of numbered figures of a codelab in locale.
+func (opts CaptionOptions) label(locale string) string {
+	if opts.Label != "" {
+		return opts.Label
+	}
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if l, ok := FigureLabels[lang]; ok {
+		return l
+	}
+	return DefaultFigureLabel
+}
+
+// labelRegexp returns a regular expression matching the "Figure 3:" label
+// of a rendered caption, for figures labeled label, so that numbering
+// an exported codelab again does not repeat it.
+func labelRegexp(label string) *regexp.Regexp {
+	p := strings.Replace(regexp.QuoteMeta(label), regexp.QuoteMeta("{n}"), `\d+`, -1)
+	return regexp.MustCompile(`(?i)^` + p + `\s*[:.：]\s*`)
+}
+
+// Captions sets the caption of the images of clab which start a paragraph,
+// from either:
+//
+//   - the italic text following the image in its paragraph;
+//   - the title of an image alone in its paragraph, if it starts with
+//     "Caption:", like the alt title of an image in a Google Doc,
+//     which is then cleared;
+//   - or the paragraph following an image alone in its paragraph,
+//     if all of it is italic, which is removed.
+//
+// Images with a caption already are left untouched, except for numbering.
+// Numbered figures are labeled as described in CaptionOptions.
+func Captions(clab *types.Codelab, opts CaptionOptions) {
+	label := opts.label(clab.Meta.Locale)
+	labelRe := labelRegexp(label)
+	fig := 0
+	for _, st := range clab.Steps {
+		eachList(st.Content, func(l *nodes.ListNode) {
+			var res []nodes.Node
+			for i := 0; i < len(l.Nodes); i++ {
+				n := l.Nodes[i]
+				res = append(res, n)
+				img, rest := figureImage(n)
+				if img == nil {
+					continue
+				}
+				if img.Caption == "" {
+					var next nodes.Node
+					if i+1 < len(l.Nodes) {
+						next = l.Nodes[i+1]
+					}
+					if caption(img, n.(*nodes.ListNode), rest, next, labelRe) {
+						i++
+					}
+				}
+				if img.Caption != "" && opts.Number {
+					fig++
+					img.Figure = strings.Replace(label, "{n}", strconv.Itoa(fig), -1)
+				}
+			}
+			l.Nodes = res
+		})
+	}
+}
+
+// figureImage returns the image of paragraph n, if it starts with an image
+// followed only by text, along with that text.
+func figureImage(n nodes.Node) (*nodes.ImageNode, []nodes.Node) {
+	p, ok := n.(*nodes.ListNode)
+	if !ok || p.Block() != true || len(p.Nodes) == 0 {
+		return nil, nil
+	}
+	img, ok := p.Nodes[0].(*nodes.ImageNode)
+	if !ok {
+		return nil, nil
+	}
+	for _, n := range p.Nodes[1:] {
+		if _, ok := n.(*nodes.TextNode); !ok {
+			return nil, nil
+		}
+	}
+	return img, p.Nodes[1:]
+}
+
+// caption sets the caption of img, alone in paragraph p but for text rest
+// and followed by the next node, if any, as described in Captions,
+// without a figure label matched by labelRe.
+// It reports whether next is the caption.
+func caption(img *nodes.ImageNode, p *nodes.ListNode, rest []nodes.Node, next nodes.Node, labelRe *regexp.Regexp) bool {
+	if t := strings.TrimSpace(italicText(rest)); t != "" {
+		img.Caption = captionText(t, labelRe)
+		p.Nodes = p.Nodes[:1]
+		return false
+	}
+	if !blank(rest) {
+		return false
+	}
+	if t := captionTitleRegexp.FindString(img.Title); t != "" {
+		img.Caption = captionText(html.UnescapeString(img.Title[len(t):]), labelRe)
+		img.Title = ""
+		return false
+	}
+	np, ok := next.(*nodes.ListNode)
+	if !ok || np.Block() != true {
+		return false
+	}
+	if t := strings.TrimSpace(italicText(np.Nodes)); t != "" {
+		img.Caption = captionText(t, labelRe)
+		return true
+	}
+	return false
+}
+
+// blank reports whether text nodes nn are only space, if any.
+func blank(nn []nodes.Node) bool {
+	for _, n := range nn {
+		if t, ok := n.(*nodes.TextNode); !ok || strings.TrimSpace(t.Value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// italicText returns the text of nn, if all of nn is italic text
+// other than space, or an empty string otherwise.
+func italicText(nn []nodes.Node) string {
+	var b strings.Builder
+	for _, n := range nn {
+		t, ok := n.(*nodes.TextNode)
+		if !ok || t.Code || !t.Italic && strings.TrimSpace(t.Value) != "" {
+			return ""
+		}
+		b.WriteString(t.Value)
+	}
+	return b.String()
+}
+
+// captionText returns caption s without its figure label matched
+// by labelRe, if any.
+func captionText(s string, labelRe *regexp.Regexp) string {
+	s = strings.TrimSpace(s)
+	return strings.TrimSpace(s[len(labelRe.FindString(s)):])
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// captionPara returns a paragraph of nn.
+func captionPara(nn ...nodes.Node) *nodes.ListNode {
+	p := nodes.NewListNode(nn...)
+	p.MutateBlock(true)
+	return p
+}
+
+// captionString describes the images and paragraphs of nn,
+// e.g. "[a.png Figure 1:The console] | Next".
+func captionString(nn []nodes.Node) string {
+	var ss []string
+	for _, n := range nn {
+		p, ok := n.(*nodes.ListNode)
+		if !ok {
+			continue
+		}
+		var s string
+		for _, n := range p.Nodes {
+			switch n := n.(type) {
+			case *nodes.ImageNode:
+				s += "[" + n.Src
+				if n.Figure != "" {
+					s += " " + n.Figure
+				}
+				if n.Caption != "" {
+					s += ":" + n.Caption
+				}
+				if n.Title != "" {
+					s += " title=" + n.Title
+				}
+				s += "]"
+			default:
+				s += nodes.PlainText(n)
+			}
+		}
+		ss = append(ss, s)
+	}
+	return strings.Join(ss, " | ")
+}
+
+func TestCaptions(t *testing.T) {
+	img := func(src, title string) *nodes.ImageNode {
+		return nodes.NewImageNode(nodes.NewImageNodeOptions{Src: src, Title: title})
+	}
+	text := func(v string) *nodes.TextNode {
+		return nodes.NewTextNode(nodes.NewTextNodeOptions{Value: v})
+	}
+	italic := func(v string) *nodes.TextNode {
+		return nodes.NewTextNode(nodes.NewTextNodeOptions{Value: v, Italic: true})
+	}
+	tests := []struct {
+		name   string
+		in     []nodes.Node
+		number bool
+		locale string
+		label  string
+		out    string
+	}{
+		{
+			name: "NextParagraph",
+			in:   []nodes.Node{captionPara(img("a.png", "")), captionPara(italic("The console")), captionPara(text("Next"))},
+			out:  "[a.png:The console] | Next",
+		},
+		{
+			name: "SameParagraph",
+			in:   []nodes.Node{captionPara(img("a.png", ""), text("\n"), italic("The console"))},
+			out:  "[a.png:The console]",
+		},
+		{
+			name: "Title",
+			in:   []nodes.Node{captionPara(img("a.png", "Caption: The &lt;b&gt; console"))},
+			out:  "[a.png:The <b> console]",
+		},
+		{
+			name: "OtherTitle",
+			in:   []nodes.Node{captionPara(img("a.png", "Console"))},
+			out:  "[a.png title=Console]",
+		},
+		{
+			name: "NotItalic",
+			in:   []nodes.Node{captionPara(img("a.png", "")), captionPara(italic("The "), text("console"))},
+			out:  "[a.png] | The console",
+		},
+		{
+			name: "InlineImage",
+			in:   []nodes.Node{captionPara(text("Click "), img("a.png", "")), captionPara(italic("The console"))},
+			out:  "Click [a.png] | The console",
+		},
+		{
+			name:   "Number",
+			in:     []nodes.Node{captionPara(img("a.png", "")), captionPara(italic("Figure 7: The console")), captionPara(img("b.png", "")), captionPara(img("c.png", "Caption: Menu"))},
+			number: true,
+			out:    "[a.png Figure 1:The console] | [b.png] | [c.png Figure 2:Menu]",
+		},
+		{
+			name:   "Locale",
+			in:     []nodes.Node{captionPara(img("a.png", "")), captionPara(italic("Abbildung 7: Die Konsole")), captionPara(img("c.png", "Caption: Menü"))},
+			number: true,
+			locale: "de-CH",
+			out:    "[a.png Abbildung 1:Die Konsole] | [c.png Abbildung 2:Menü]",
+		},
+		{
+			name:   "Label",
+			in:     []nodes.Node{captionPara(img("a.png", "")), captionPara(italic("Fig. 7. The console"))},
+			number: true,
+			locale: "de",
+			label:  "Fig. {n}",
+			out:    "[a.png Fig. 1:The console]",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clab := &types.Codelab{Meta: types.Meta{Locale: tc.locale}, Steps: []*types.Step{{Title: "Setup", Content: nodes.NewListNode(tc.in...)}}}
+			Captions(clab, CaptionOptions{Number: tc.number, Label: tc.label})
+			if diff := cmp.Diff(tc.out, captionString(clab.Steps[0].Content.Nodes)); diff != "" {
+				t.Errorf("Captions got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
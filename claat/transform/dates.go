@@ -0,0 +1,155 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// datePattern matches the dates of dateLayouts.
+const datePattern = `\d{4}-\d{2}-\d{2}|\d{1,2}/\d{1,2}/\d{4}|[A-Z][a-z]+\.? \d{1,2},? \d{4}`
+
+var (
+	// watermarkRegexp matches a "Last Updated: date" or "Last Tested: date"
+	// watermark, also with the date in a separate text node, e.g. after a bold label.
+	watermarkRegexp = regexp.MustCompile(`(?i)(last\s+(updated|tested))(\s*:?\s*)(` + datePattern + `)?`)
+	// leadingDateRegexp matches a date at the start of a text node following
+	// a watermark label.
+	leadingDateRegexp = regexp.MustCompile(`^(\s*:?\s*)(` + datePattern + `)`)
+
+	// dateLayouts are the recognized watermark date formats.
+	// A rewritten date keeps the format of the original.
+	dateLayouts = []string{
+		"2006-01-02",
+		"01/02/2006",
+		"1/2/2006",
+		"January 2, 2006",
+		"Jan 2, 2006",
+		"Jan. 2, 2006",
+		"January 2 2006",
+		"Jan 2 2006",
+	}
+)
+
+// DatesOptions configures watermark date management.
+type DatesOptions struct {
+	// Updated is the date to set in Last Updated watermarks.
+	// If zero, they are left untouched.
+	Updated time.Time
+	// Tested is the date to set in Last Tested watermarks.
+	// If zero, they are left untouched.
+	Tested time.Time
+	// MaxTestedAge makes UpdateDates fail if a Last Tested date is older,
+	// or if there is no Last Tested watermark at all. Zero disables the check.
+	MaxTestedAge time.Duration
+	// Now is the current time used for the MaxTestedAge check.
+	// Zero means time.Now.
+	Now time.Time
+}
+
+// UpdateDates rewrites the dates of "Last Updated: date" and "Last Tested: date"
+// watermarks in all steps of clab, keeping the original date format.
+//
+// It returns an error if the last tested date is older than opts.MaxTestedAge.
+func UpdateDates(clab *types.Codelab, opts DatesOptions) error {
+	var tested []time.Time
+	for _, st := range clab.Steps {
+		tested = append(tested, updateDates(st.Content.Nodes, opts)...)
+	}
+	if opts.MaxTestedAge == 0 {
+		return nil
+	}
+	if len(tested) == 0 {
+		return fmt.Errorf("no Last Tested date")
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	for _, t := range tested {
+		if age := now.Sub(t); age > opts.MaxTestedAge {
+			return fmt.Errorf("Last Tested date %s is %d days old, more than %d",
+				t.Format("2006-01-02"), int(age.Hours()/24), int(opts.MaxTestedAge.Hours()/24))
+		}
+	}
+	return nil
+}
+
+// updateDates rewrites watermark dates in nn and returns all Last Tested dates,
+// after rewriting.
+func updateDates(nn []nodes.Node, opts DatesOptions) []time.Time {
+	var tested []time.Time
+	// kind of a watermark label without a date in the previous text node
+	var pending string
+	nodes.WalkNodes(nn, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		t, ok := n.(*nodes.TextNode)
+		if !entering || !ok {
+			return n, nil
+		}
+		if pending != "" {
+			kind := pending
+			pending = ""
+			if m := leadingDateRegexp.FindStringSubmatchIndex(t.Value); m != nil {
+				d, v := rewriteDate(kind, t.Value[m[4]:m[5]], opts)
+				t.Value = t.Value[:m[4]] + v + t.Value[m[5]:]
+				if kind == "tested" && !d.IsZero() {
+					tested = append(tested, d)
+				}
+				return n, nil
+			}
+		}
+		t.Value = watermarkRegexp.ReplaceAllStringFunc(t.Value, func(s string) string {
+			m := watermarkRegexp.FindStringSubmatch(s)
+			kind := strings.ToLower(m[2])
+			if m[4] == "" {
+				pending = kind
+				return s
+			}
+			d, v := rewriteDate(kind, m[4], opts)
+			if kind == "tested" && !d.IsZero() {
+				tested = append(tested, d)
+			}
+			return m[1] + m[3] + v
+		})
+		return n, nil
+	})
+	return tested
+}
+
+// rewriteDate returns the date for a watermark of kind "updated" or "tested"
+// with current date value v, both as time and in the format of v.
+// The returned time is zero if v is in an unknown format.
+func rewriteDate(kind, v string, opts DatesOptions) (time.Time, string) {
+	for _, layout := range dateLayouts {
+		d, err := time.Parse(layout, v)
+		if err != nil {
+			continue
+		}
+		switch {
+		case kind == "updated" && !opts.Updated.IsZero():
+			d = opts.Updated
+		case kind == "tested" && !opts.Tested.IsZero():
+			d = opts.Tested
+		}
+		return d, d.Format(layout)
+	}
+	return time.Time{}, v
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func watermarkCodelab(texts ...string) *types.Codelab {
+	clab := types.NewCodelab()
+	st := clab.NewStep("Step")
+	for _, v := range texts {
+		st.Content.Append(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: v}))
+	}
+	return clab
+}
+
+func codelabTexts(clab *types.Codelab) []string {
+	var texts []string
+	for _, n := range clab.Steps[0].Content.Nodes {
+		texts = append(texts, n.(*nodes.TextNode).Value)
+	}
+	return texts
+}
+
+func TestUpdateDates(t *testing.T) {
+	updated := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	tested := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		in   []string
+		opts DatesOptions
+		out  []string
+	}{
+		{
+			name: "ISO",
+			in:   []string{"Last Updated: 2020-01-02", "Last Tested: 2020-01-02"},
+			opts: DatesOptions{Updated: updated, Tested: tested},
+			out:  []string{"Last Updated: 2026-03-05", "Last Tested: 2026-02-01"},
+		},
+		{
+			name: "KeepFormat",
+			in:   []string{"Manual Last Updated: January 2, 2020 by Jane"},
+			opts: DatesOptions{Updated: updated},
+			out:  []string{"Manual Last Updated: March 5, 2026 by Jane"},
+		},
+		{
+			name: "SeparateLabel",
+			in:   []string{"Last updated:", " 1/2/2020"},
+			opts: DatesOptions{Updated: updated},
+			out:  []string{"Last updated:", " 3/5/2026"},
+		},
+		{
+			name: "TestedUntouched",
+			in:   []string{"Last Tested: Jan 2, 2020"},
+			opts: DatesOptions{Updated: updated},
+			out:  []string{"Last Tested: Jan 2, 2020"},
+		},
+		{
+			name: "NoWatermark",
+			in:   []string{"It was last updated yesterday"},
+			opts: DatesOptions{Updated: updated},
+			out:  []string{"It was last updated yesterday"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clab := watermarkCodelab(tc.in...)
+			if err := UpdateDates(clab, tc.opts); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.out, codelabTexts(clab)); diff != "" {
+				t.Errorf("UpdateDates() got diff (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateDatesMaxTestedAge(t *testing.T) {
+	now := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		in      string
+		tested  time.Time
+		wantErr bool
+	}{
+		{name: "Fresh", in: "Last Tested: 2026-03-01"},
+		{name: "Stale", in: "Last Tested: 2025-03-01", wantErr: true},
+		{name: "StaleRetested", in: "Last Tested: 2025-03-01", tested: now},
+		{name: "Missing", in: "Last Updated: 2026-03-01", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := DatesOptions{Tested: tc.tested, MaxTestedAge: 30 * 24 * time.Hour, Now: now}
+			err := UpdateDates(watermarkCodelab(tc.in), opts)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("UpdateDates() = %v; want error: %v", err, tc.wantErr)
+			}
+		})
+	}
+}
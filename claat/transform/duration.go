@@ -0,0 +1,67 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// DefaultDurationTolerance is the difference between the declared codelab
+// duration and the sum of step durations above which ComputeDuration warns.
+const DefaultDurationTolerance = 5 * time.Minute
+
+// StepsDuration returns the sum of durations of all clab steps.
+func StepsDuration(clab *types.Codelab) time.Duration {
+	var d time.Duration
+	for _, st := range clab.Steps {
+		d += st.Duration
+	}
+	return d
+}
+
+// ComputeDuration sets the total duration of clab, in minutes,
+// to the sum of its step durations, unless a total was declared in metadata,
+// as told by DurationDeclared. Parsers sum the step durations too, but
+// steps may get a duration since, e.g. with FillDurations.
+//
+// It returns a warning for each step without a duration and,
+// if a total was declared, when it differs from the sum of step durations
+// by more than tolerance.
+func ComputeDuration(clab *types.Codelab, tolerance time.Duration) []string {
+	var warns []string
+	for i, st := range clab.Steps {
+		if st.Duration == 0 {
+			warns = append(warns, fmt.Sprintf("step %d %q has no duration", i+1, st.Title))
+		}
+	}
+	sum := StepsDuration(clab)
+	if !clab.DurationDeclared {
+		clab.Duration = int(sum.Minutes())
+		return warns
+	}
+	declared := time.Duration(clab.Duration) * time.Minute
+	diff := declared - sum
+	if diff < 0 {
+		diff = -diff
+	}
+	if sum > 0 && diff > tolerance {
+		warns = append(warns, fmt.Sprintf("declared duration of %d min differs from the %d min sum of step durations",
+			clab.Duration, int(sum.Minutes())))
+	}
+	return warns
+}
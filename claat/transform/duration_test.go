@@ -0,0 +1,84 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestComputeDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		declared int
+		summed   int // by the parser, before steps got a duration
+		steps    []time.Duration
+		duration int
+		warns    []string
+	}{
+		{
+			name:     "Computed",
+			steps:    []time.Duration{5 * time.Minute, 10 * time.Minute},
+			duration: 15,
+		},
+		{
+			name:     "MissingStep",
+			steps:    []time.Duration{5 * time.Minute, 0},
+			duration: 5,
+			warns:    []string{`step 2 "Step" has no duration`},
+		},
+		{
+			name:     "StaleSum",
+			summed:   5,
+			steps:    []time.Duration{5 * time.Minute, 10 * time.Minute},
+			duration: 15,
+		},
+		{
+			name:     "DeclaredWithinTolerance",
+			declared: 20,
+			steps:    []time.Duration{5 * time.Minute, 10 * time.Minute},
+			duration: 20,
+		},
+		{
+			name:     "DeclaredMismatch",
+			declared: 60,
+			steps:    []time.Duration{5 * time.Minute, 10 * time.Minute},
+			duration: 60,
+			warns:    []string{"declared duration of 60 min differs from the 15 min sum of step durations"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clab := types.NewCodelab()
+			clab.Duration = tc.summed
+			if tc.declared > 0 {
+				clab.Duration, clab.DurationDeclared = tc.declared, true
+			}
+			for _, d := range tc.steps {
+				clab.NewStep("Step").Duration = d
+			}
+			warns := ComputeDuration(clab, DefaultDurationTolerance)
+			if clab.Duration != tc.duration {
+				t.Errorf("Duration = %d; want %d", clab.Duration, tc.duration)
+			}
+			if diff := cmp.Diff(tc.warns, warns); diff != "" {
+				t.Errorf("ComputeDuration() warnings diff (-want +got): %s", diff)
+			}
+		})
+	}
+}
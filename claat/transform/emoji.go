@@ -0,0 +1,249 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// EmojiShortcodes maps the names of :name: shortcodes to their emoji,
+// following the names of GitHub and Slack.
+var EmojiShortcodes = map[string]string{
+	"+1":                       "👍",
+	"-1":                       "👎",
+	"100":                      "💯",
+	"alarm_clock":              "⏰",
+	"arrow_down":               "⬇️",
+	"arrow_left":               "⬅️",
+	"arrow_right":              "➡️",
+	"arrow_up":                 "⬆️",
+	"art":                      "🎨",
+	"bell":                     "🔔",
+	"book":                     "📖",
+	"books":                    "📚",
+	"boom":                     "💥",
+	"bookmark":                 "🔖",
+	"brain":                    "🧠",
+	"bug":                      "🐛",
+	"bulb":                     "💡",
+	"calendar":                 "📆",
+	"chart_with_upwards_trend": "📈",
+	"check":                    "✔️",
+	"checkered_flag":           "🏁",
+	"clap":                     "👏",
+	"clipboard":                "📋",
+	"clock":                    "🕒",
+	"closed_lock_with_key":     "🔐",
+	"cloud":                    "☁️",
+	"coffee":                   "☕",
+	"computer":                 "💻",
+	"construction":             "🚧",
+	"crossed_fingers":          "🤞",
+	"dart":                     "🎯",
+	"desktop_computer":         "🖥️",
+	"dizzy":                    "💫",
+	"dna":                      "🧬",
+	"exclamation":              "❗",
+	"eyes":                     "👀",
+	"file_folder":              "📁",
+	"fire":                     "🔥",
+	"gear":                     "⚙️",
+	"gem":                      "💎",
+	"globe_with_meridians":     "🌐",
+	"grey_question":            "❔",
+	"grin":                     "😁",
+	"hammer":                   "🔨",
+	"hammer_and_wrench":        "🛠️",
+	"hand":                     "✋",
+	"heart":                    "❤️",
+	"heavy_check_mark":         "✔️",
+	"heavy_minus_sign":         "➖",
+	"heavy_plus_sign":          "➕",
+	"hourglass":                "⌛",
+	"hourglass_flowing_sand":   "⏳",
+	"information_source":       "ℹ️",
+	"joy":                      "😂",
+	"key":                      "🔑",
+	"keyboard":                 "⌨️",
+	"label":                    "🏷️",
+	"laptop":                   "💻",
+	"link":                     "🔗",
+	"lock":                     "🔒",
+	"loudspeaker":              "📢",
+	"mag":                      "🔍",
+	"mailbox":                  "📫",
+	"memo":                     "📝",
+	"microscope":               "🔬",
+	"money_with_wings":         "💸",
+	"muscle":                   "💪",
+	"no_entry":                 "⛔",
+	"no_entry_sign":            "🚫",
+	"ok_hand":                  "👌",
+	"package":                  "📦",
+	"paperclip":                "📎",
+	"partying_face":            "🥳",
+	"pencil2":                  "✏️",
+	"point_down":               "👇",
+	"point_left":               "👈",
+	"point_right":              "👉",
+	"point_up":                 "☝️",
+	"pray":                     "🙏",
+	"pushpin":                  "📌",
+	"question":                 "❓",
+	"raised_hands":             "🙌",
+	"recycle":                  "♻️",
+	"red_circle":               "🔴",
+	"robot":                    "🤖",
+	"rocket":                   "🚀",
+	"rotating_light":           "🚨",
+	"scroll":                   "📜",
+	"see_no_evil":              "🙈",
+	"shield":                   "🛡️",
+	"smile":                    "😄",
+	"smiley":                   "😃",
+	"sparkles":                 "✨",
+	"speech_balloon":           "💬",
+	"star":                     "⭐",
+	"star2":                    "🌟",
+	"stop_sign":                "🛑",
+	"stopwatch":                "⏱️",
+	"sunglasses":               "😎",
+	"tada":                     "🎉",
+	"test_tube":                "🧪",
+	"thinking":                 "🤔",
+	"thumbsdown":               "👎",
+	"thumbsup":                 "👍",
+	"trophy":                   "🏆",
+	"unlock":                   "🔓",
+	"warning":                  "⚠️",
+	"wave":                     "👋",
+	"white_check_mark":         "✅",
+	"wink":                     "😉",
+	"wrench":                   "🔧",
+	"x":                        "❌",
+	"zap":                      "⚡",
+}
+
+// shortcodeRegexp matches a :name: emoji shortcode.
+var shortcodeRegexp = regexp.MustCompile(`:([a-z0-9_+-]+):`)
+
+// emojiSize is the width, in pixels, of emoji images.
+const emojiSize = 20
+
+// EmojiOptions configures the conversion of emoji shortcodes.
+type EmojiOptions struct {
+	// Images is the URL prefix of emoji images, named after the hexadecimal
+	// code points of their emoji, joined with dashes, such as 1f680.png,
+	// as the Twemoji images are. If not empty, shortcodes of text are
+	// replaced with inline images, for targets without emoji fonts.
+	Images string
+}
+
+// Emoji replaces the known :name: shortcodes of EmojiShortcodes in the text
+// of clab, including its title, summary and step titles, with their emoji.
+// Text of code is left untouched, and unknown names, such as in "10:30:00",
+// are left as is.
+// With opts.Images, shortcodes of paragraphs and lists are replaced with
+// emoji images instead, while those of titles, headings and links,
+// which cannot hold images, are still replaced with emoji.
+func Emoji(clab *types.Codelab, opts EmojiOptions) {
+	clab.Title = ReplaceShortcodes(clab.Title)
+	clab.Summary = ReplaceShortcodes(clab.Summary)
+	for _, st := range clab.Steps {
+		st.Title = ReplaceShortcodes(st.Title)
+		if opts.Images != "" {
+			replaceText(st.Content, func(t *nodes.TextNode) []nodes.Node {
+				return emojiImages(t, opts.Images)
+			})
+		}
+		if st.Content == nil {
+			continue
+		}
+		nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			switch n := n.(type) {
+			case *nodes.CodeNode:
+				return n, nodes.SkipChildren
+			case *nodes.TextNode:
+				if entering && !n.Code {
+					n.Value = ReplaceShortcodes(n.Value)
+				}
+			}
+			return n, nil
+		})
+	}
+}
+
+// ReplaceShortcodes replaces the known :name: shortcodes of s with their emoji.
+func ReplaceShortcodes(s string) string {
+	if !strings.Contains(s, ":") {
+		return s
+	}
+	return shortcodeRegexp.ReplaceAllStringFunc(s, func(m string) string {
+		if e, ok := EmojiShortcodes[m[1:len(m)-1]]; ok {
+			return e
+		}
+		return m
+	})
+}
+
+// emojiImages splits t around its known shortcodes, replaced with images
+// of their emoji at URL prefix images.
+func emojiImages(t *nodes.TextNode, images string) []nodes.Node {
+	v := t.Value
+	var res []nodes.Node
+	pos := 0
+	for _, m := range shortcodeRegexp.FindAllStringSubmatchIndex(v, -1) {
+		e, ok := EmojiShortcodes[v[m[2]:m[3]]]
+		if !ok {
+			continue
+		}
+		if m[0] > pos {
+			res = append(res, textSpan(t, v[pos:m[0]]))
+		}
+		img := nodes.NewImageNode(nodes.NewImageNodeOptions{
+			Src:   images + emojiFilename(e),
+			Width: emojiSize,
+			Alt:   e,
+			Title: v[m[0]:m[1]],
+		})
+		img.MutateEnv(t.Env())
+		res = append(res, img)
+		pos = m[1]
+	}
+	if pos == 0 {
+		return []nodes.Node{t}
+	}
+	if pos < len(v) {
+		res = append(res, textSpan(t, v[pos:]))
+	}
+	return res
+}
+
+// emojiFilename returns the image file name of emoji e: its hexadecimal
+// code points joined with dashes, without variation selectors, and .png.
+func emojiFilename(e string) string {
+	var cp []string
+	for _, r := range e {
+		if r != '\ufe0f' {
+			cp = append(cp, fmt.Sprintf("%x", r))
+		}
+	}
+	return strings.Join(cp, "-") + ".png"
+}
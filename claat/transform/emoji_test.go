@@ -0,0 +1,114 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestReplaceShortcodes(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		{"", ""},
+		{"no shortcodes", "no shortcodes"},
+		{":warning: Billing", "⚠️ Billing"},
+		{"Done:tada::+1:", "Done🎉👍"},
+		{"at 10:30:00", "at 10:30:00"},
+		{":unknown: and :Rocket:", ":unknown: and :Rocket:"},
+		{"https://example.com:8080/", "https://example.com:8080/"},
+		{"keep 🚀 as is", "keep 🚀 as is"},
+	}
+	for _, tc := range tests {
+		if got := ReplaceShortcodes(tc.in); got != tc.out {
+			t.Errorf("ReplaceShortcodes(%q) = %q; want %q", tc.in, got, tc.out)
+		}
+	}
+}
+
+func TestEmoji(t *testing.T) {
+	text := func(s string) *nodes.TextNode {
+		return nodes.NewTextNode(nodes.NewTextNodeOptions{Value: s})
+	}
+	newCodelab := func() *types.Codelab {
+		clab := &types.Codelab{Meta: types.Meta{Title: "Launch :rocket:", Summary: ":zap: fast"}}
+		clab.Steps = []*types.Step{{
+			Title: "Setup :gear:",
+			Content: nodes.NewListNode(
+				nodes.NewHeaderNode(2, text("Careful :warning:")),
+				text("Done :tada: at 10:30:00"),
+				nodes.NewCodeNode("echo :tada:", true, ""),
+			),
+		}}
+		return clab
+	}
+
+	clab := newCodelab()
+	Emoji(clab, EmojiOptions{})
+	got := []string{clab.Title, clab.Summary, clab.Steps[0].Title}
+	nodes.WalkNodes(clab.Steps[0].Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if !entering {
+			return n, nil
+		}
+		switch n := n.(type) {
+		case *nodes.TextNode:
+			got = append(got, n.Value)
+		case *nodes.CodeNode:
+			got = append(got, n.Value)
+		}
+		return n, nil
+	})
+	want := []string{"Launch 🚀", "⚡ fast", "Setup ⚙️", "Careful ⚠️", "Done 🎉 at 10:30:00", "echo :tada:"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Emoji mismatch (-want +got):\n%s", diff)
+	}
+
+	clab = newCodelab()
+	Emoji(clab, EmojiOptions{Images: "https://example.com/emoji/"})
+	content := clab.Steps[0].Content.Nodes
+	if len(content) != 5 {
+		t.Fatalf("Emoji with images: %d nodes; want 5", len(content))
+	}
+	img, ok := content[2].(*nodes.ImageNode)
+	if !ok {
+		t.Fatalf("Emoji with images: node 2 is a %T; want an image", content[2])
+	}
+	wantImg := &nodes.ImageNode{Src: "https://example.com/emoji/1f389.png", Width: emojiSize, Alt: "🎉", Title: ":tada:"}
+	if img.Src != wantImg.Src || img.Width != wantImg.Width || img.Alt != wantImg.Alt || img.Title != wantImg.Title {
+		t.Errorf("Emoji image = %+v; want %+v", img, wantImg)
+	}
+	if v := content[0].(*nodes.HeaderNode).Content.Nodes[0].(*nodes.TextNode).Value; v != "Careful ⚠️" {
+		t.Errorf("Emoji with images: heading %q; want %q", v, "Careful ⚠️")
+	}
+}
+
+func TestEmojiFilename(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		{"🚀", "1f680.png"},
+		{"⚠️", "26a0.png"},
+		{"👩‍💻", "1f469-200d-1f4bb.png"},
+	}
+	for _, tc := range tests {
+		if got := emojiFilename(tc.in); got != tc.out {
+			t.Errorf("emojiFilename(%q) = %q; want %q", tc.in, got, tc.out)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+const (
+	// DefaultWordsPerMinute is the reading speed of EstimateOptions.
+	DefaultWordsPerMinute = 200
+	// DefaultCodeLineTime is the time to read, type or run a line of code
+	// of EstimateOptions.
+	DefaultCodeLineTime = 10 * time.Second
+)
+
+// EstimateOptions configures step duration estimation.
+type EstimateOptions struct {
+	// WordsPerMinute is the reading speed of text.
+	// Zero means DefaultWordsPerMinute.
+	WordsPerMinute int
+	// CodeLineTime is the time spent on each non-blank line of code blocks,
+	// other than expected output, which is read like text.
+	// Zero means DefaultCodeLineTime.
+	CodeLineTime time.Duration
+	// VideoLength returns the length of a YouTube video.
+	// If nil, videos are counted but their length is not estimated.
+	VideoLength func(id string) (time.Duration, error)
+}
+
+// Estimate is the estimated reading and execution time of a step.
+type Estimate struct {
+	Words     int           `json:"words"`     // Words of text
+	CodeLines int           `json:"codeLines"` // Non-blank lines of code blocks
+	Videos    int           `json:"videos"`    // Number of videos
+	VideoTime time.Duration `json:"videoTime"` // Total length of videos
+	Duration  time.Duration `json:"duration"`  // Estimated duration
+}
+
+// Add adds the counts and duration of e2 to e.
+func (e *Estimate) Add(e2 *Estimate) {
+	e.Words += e2.Words
+	e.CodeLines += e2.CodeLines
+	e.Videos += e2.Videos
+	e.VideoTime += e2.VideoTime
+	e.Duration += e2.Duration
+}
+
+// EstimateStep estimates the time needed to go through the content of st:
+// reading its text, working with its code blocks and watching its videos.
+func EstimateStep(st *types.Step, opts EstimateOptions) (*Estimate, error) {
+	wpm := opts.WordsPerMinute
+	if wpm <= 0 {
+		wpm = DefaultWordsPerMinute
+	}
+	lineTime := opts.CodeLineTime
+	if lineTime <= 0 {
+		lineTime = DefaultCodeLineTime
+	}
+
+	e := &Estimate{Words: len(strings.Fields(st.Title))}
+	var videos []string
+	_, err := nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if !entering {
+			return n, nil
+		}
+		switch n := n.(type) {
+		case *nodes.TextNode:
+			e.Words += len(strings.Fields(n.Value))
+		case *nodes.CodeNode:
+			// expected output is read, not typed or run
+			if n.Output {
+				e.Words += len(strings.Fields(n.Value))
+				break
+			}
+			for _, l := range strings.Split(n.Value, "\n") {
+				if strings.TrimSpace(l) != "" {
+					e.CodeLines++
+				}
+			}
+		case *nodes.YouTubeNode:
+			videos = append(videos, n.VideoID)
+		case *nodes.VideoNode:
+			// the length of other videos is unknown
+			e.Videos++
+		}
+		return n, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	e.Videos += len(videos)
+	if opts.VideoLength != nil {
+		for _, id := range videos {
+			d, err := opts.VideoLength(id)
+			if err != nil {
+				return nil, fmt.Errorf("video %s: %v", id, err)
+			}
+			e.VideoTime += d
+		}
+	}
+	e.Duration = time.Duration(e.Words)*time.Minute/time.Duration(wpm) +
+		time.Duration(e.CodeLines)*lineTime + e.VideoTime
+	return e, nil
+}
+
+// EstimateCodelab estimates the duration of each step of clab.
+func EstimateCodelab(clab *types.Codelab, opts EstimateOptions) ([]*Estimate, error) {
+	ee := make([]*Estimate, len(clab.Steps))
+	for i, st := range clab.Steps {
+		e, err := EstimateStep(st, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", st.Title, err)
+		}
+		ee[i] = e
+	}
+	return ee, nil
+}
+
+// FillDurations sets the duration of each clab step without one
+// to its estimate, rounded up to the minute.
+func FillDurations(clab *types.Codelab, opts EstimateOptions) error {
+	for _, st := range clab.Steps {
+		if st.Duration != 0 {
+			continue
+		}
+		e, err := EstimateStep(st, opts)
+		if err != nil {
+			return fmt.Errorf("%s: %v", st.Title, err)
+		}
+		st.Duration = ceilMinute(e.Duration)
+	}
+	return nil
+}
+
+// ceilMinute rounds d up to a whole number of minutes.
+func ceilMinute(d time.Duration) time.Duration {
+	if r := d % time.Minute; r != 0 {
+		d += time.Minute - r
+	}
+	return d
+}
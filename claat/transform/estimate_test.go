@@ -0,0 +1,112 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func estimateCodelab() *types.Codelab {
+	clab := types.NewCodelab()
+	st := clab.NewStep("Set up")
+	st.Content.Append(
+		nodes.NewTextNode(nodes.NewTextNodeOptions{Value: strings.Repeat("word ", 398)}),
+		nodes.NewCodeNode("gcloud init\n\ngcloud auth login\n", true, ""),
+		nodes.NewYouTubeNode("vid1"),
+	)
+	done := clab.NewStep("Done")
+	done.Duration = 2 * time.Minute
+	return clab
+}
+
+func TestEstimateStep(t *testing.T) {
+	videoLength := func(id string) (time.Duration, error) {
+		return 90 * time.Second, nil
+	}
+	tests := []struct {
+		name string
+		opts EstimateOptions
+		want *Estimate
+	}{
+		{
+			name: "Defaults",
+			want: &Estimate{Words: 400, CodeLines: 2, Videos: 1, Duration: 2*time.Minute + 20*time.Second},
+		},
+		{
+			name: "Videos",
+			opts: EstimateOptions{WordsPerMinute: 100, CodeLineTime: time.Minute, VideoLength: videoLength},
+			want: &Estimate{Words: 400, CodeLines: 2, Videos: 1, VideoTime: 90 * time.Second, Duration: 7*time.Minute + 30*time.Second},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := EstimateStep(estimateCodelab().Steps[0], tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, e); diff != "" {
+				t.Errorf("EstimateStep() got diff (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestEstimateStepOutput(t *testing.T) {
+	st := types.NewCodelab().NewStep("Run")
+	out := nodes.NewCodeNode("Hello, world\nBye\n", true, "")
+	out.Output = true
+	st.Content.Append(nodes.NewCodeNode("./hello\n", true, ""), out)
+	e, err := EstimateStep(st, EstimateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Words != 4 || e.CodeLines != 1 {
+		t.Errorf("EstimateStep() words, code lines = %d, %d; want 4, 1", e.Words, e.CodeLines)
+	}
+}
+
+func TestEstimateStepVideo(t *testing.T) {
+	st := types.NewCodelab().NewStep("Watch")
+	st.Content.Append(nodes.NewYouTubeNode("vid1"), nodes.NewVideoNode("https://vimeo.com/76979871"))
+	videoLength := func(id string) (time.Duration, error) {
+		return time.Minute, nil
+	}
+	e, err := EstimateStep(st, EstimateOptions{VideoLength: videoLength})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Videos != 2 || e.VideoTime != time.Minute {
+		t.Errorf("EstimateStep() videos, video time = %d, %v; want 2, 1m0s", e.Videos, e.VideoTime)
+	}
+}
+
+func TestFillDurations(t *testing.T) {
+	clab := estimateCodelab()
+	if err := FillDurations(clab, EstimateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Duration{3 * time.Minute, 2 * time.Minute}
+	for i, st := range clab.Steps {
+		if st.Duration != want[i] {
+			t.Errorf("Steps[%d].Duration = %v; want %v", i, st.Duration, want[i])
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// Glossary wraps the first occurrence of each term of the clab glossary
+// in its text with a glossary term node. Terms are matched as whole words,
+// ignoring case, longer terms first, so that "Cloud Run" takes precedence
+// over "Cloud". Text of headings, links and code is not considered.
+func Glossary(clab *types.Codelab) {
+	if len(clab.Glossary) == 0 {
+		return
+	}
+	terms := clab.Glossary.Terms()
+	sort.SliceStable(terms, func(i, j int) bool {
+		return len(terms[i]) > len(terms[j])
+	})
+	byName := make(map[string]string, len(terms))
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		byName[strings.ToLower(t)] = t
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+	g := &glossary{
+		defs:   clab.Glossary,
+		byName: byName,
+		re:     regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`),
+		seen:   make(map[string]bool),
+	}
+	for _, st := range clab.Steps {
+		replaceText(st.Content, g.text)
+	}
+}
+
+// glossary wraps the first occurrences of glossary terms.
+type glossary struct {
+	defs   types.Glossary
+	byName map[string]string // terms by lowercase name
+	re     *regexp.Regexp    // matches any term
+	seen   map[string]bool   // terms already wrapped
+}
+
+// text splits t around the first occurrences of terms.
+func (g *glossary) text(t *nodes.TextNode) []nodes.Node {
+	v := t.Value
+	var res []nodes.Node
+	pos := 0
+	for _, m := range g.re.FindAllStringIndex(v, -1) {
+		term := g.byName[strings.ToLower(v[m[0]:m[1]])]
+		if term == "" || g.seen[term] {
+			continue
+		}
+		g.seen[term] = true
+		if m[0] > pos {
+			res = append(res, textSpan(t, v[pos:m[0]]))
+		}
+		res = append(res, nodes.NewTermNode(term, g.defs[term], textSpan(t, v[m[0]:m[1]])))
+		pos = m[1]
+	}
+	if pos == 0 {
+		return []nodes.Node{t}
+	}
+	if pos < len(v) {
+		res = append(res, textSpan(t, v[pos:]))
+	}
+	return res
+}
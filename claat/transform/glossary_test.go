@@ -0,0 +1,76 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// termString describes text and glossary term nodes of nn, e.g. "a [VPC:vpc]".
+func termString(nn []nodes.Node) string {
+	var s string
+	for _, n := range nn {
+		switch n := n.(type) {
+		case *nodes.TextNode:
+			s += n.Value
+		case *nodes.TermNode:
+			s += "[" + n.Term + ":" + nodes.PlainText(n.Content.Nodes...) + "]"
+		case *nodes.ListNode:
+			s += "{" + termString(n.Nodes) + "}"
+		default:
+			s += "<" + nodes.PlainText(n) + ">"
+		}
+	}
+	return s
+}
+
+func TestGlossary(t *testing.T) {
+	text := func(s string) nodes.Node {
+		return nodes.NewTextNode(nodes.NewTextNodeOptions{Value: s})
+	}
+	clab := types.NewCodelab()
+	clab.Glossary = types.Glossary{
+		"VPC":       "Virtual Private Cloud",
+		"Cloud":     "Someone else's computer",
+		"Cloud Run": "Serverless containers",
+	}
+	clab.Steps = []*types.Step{
+		{Title: "One", Content: nodes.NewListNode(
+			nodes.NewHeaderNode(2, text("Cloud Run")),
+			nodes.NewListNode(text("Deploy to cloud run in a VPCs network or a VPC.")),
+			nodes.NewListNode(nodes.NewURLNode("https://example.com", text("Cloud"))),
+		)},
+		{Title: "Two", Content: nodes.NewListNode(
+			nodes.NewListNode(text("Cloud Run, VPC and Cloud.")),
+			nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Cloud", Code: true})),
+		)},
+	}
+	Glossary(clab)
+	want := []string{
+		"<Cloud Run>{Deploy to [Cloud Run:cloud run] in a VPCs network or a [VPC:VPC].}{<Cloud>}",
+		"{Cloud Run, VPC and [Cloud:Cloud].}{Cloud}",
+	}
+	var got []string
+	for _, st := range clab.Steps {
+		got = append(got, termString(st.Content.Nodes))
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Glossary() got diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// titlePunct is the trailing punctuation stripped from step titles.
+// Question marks and closing brackets are part of a title.
+const titlePunct = ".,:;!"
+
+// NormalizeCodelabHeadings fixes common heading mistakes of the steps
+// of clab: it strips trailing punctuation from step titles, and normalizes
+// the headers of their content as NormalizeHeadings does.
+// It returns a description of each change, in step order, which the
+// normalize-headings pass reports as warnings.
+func NormalizeCodelabHeadings(clab *types.Codelab) []string {
+	var res []string
+	for i, st := range clab.Steps {
+		if title := strings.TrimRight(strings.TrimSpace(st.Title), titlePunct); title != st.Title && title != "" {
+			res = append(res, fmt.Sprintf("%s: title changed to %q", st.Location(i+1, nil), title))
+			st.Title = title
+		}
+		if st.Content == nil {
+			continue
+		}
+		for _, c := range normalizeHeadings(st.Content.Nodes) {
+			hn := c.header
+			text := strings.TrimSpace(nodes.PlainText(hn.Content.Nodes...))
+			res = append(res, fmt.Sprintf("%s: h%d %q changed to h%d", st.Location(i+1, hn), c.level, text, hn.Level))
+		}
+	}
+	return res
+}
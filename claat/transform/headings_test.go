@@ -0,0 +1,62 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestNormalizeCodelabHeadings(t *testing.T) {
+	header := func(level int, text string) *nodes.HeaderNode {
+		return nodes.NewHeaderNode(level, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: text}))
+	}
+	clab := &types.Codelab{Steps: []*types.Step{
+		{Title: "Overview.", Content: nodes.NewListNode(header(2, "Intro"), header(3, "Goals"))},
+		{Title: "Why bother?", Content: nodes.NewListNode(header(1, "Setup"), header(4, "Details"))},
+		{Title: "Next steps:"},
+	}}
+	got := NormalizeCodelabHeadings(clab)
+	want := []string{
+		`step 1 "Overview.": title changed to "Overview"`,
+		`step 2 "Why bother?": h1 "Setup" changed to h2`,
+		`step 2 "Why bother?": h4 "Details" changed to h3`,
+		`step 3 "Next steps:": title changed to "Next steps"`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NormalizeCodelabHeadings report mismatch (-want +got):\n%s", diff)
+	}
+
+	var titles []string
+	var levels []int
+	for _, st := range clab.Steps {
+		titles = append(titles, st.Title)
+		if st.Content == nil {
+			continue
+		}
+		for _, n := range st.Content.Nodes {
+			levels = append(levels, n.(*nodes.HeaderNode).Level)
+		}
+	}
+	if diff := cmp.Diff([]string{"Overview", "Why bother?", "Next steps"}, titles); diff != "" {
+		t.Errorf("titles mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{2, 3, 2, 3}, levels); diff != "" {
+		t.Errorf("levels mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"regexp"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// iconRegexp matches an {icon:name} reference to a Material icon,
+// with the name in group 1.
+var iconRegexp = regexp.MustCompile(`\{icon:([a-z0-9_]+)\}`)
+
+// Icons replaces {icon:name} references to Material icons, such as
+// {icon:menu}, in the text of all clab steps, except headings and links,
+// with icon nodes, so that user interface elements are shown consistently.
+func Icons(clab *types.Codelab) {
+	for _, st := range clab.Steps {
+		replaceText(st.Content, icons)
+	}
+}
+
+// icons splits t around its icon references.
+func icons(t *nodes.TextNode) []nodes.Node {
+	v := t.Value
+	mm := iconRegexp.FindAllStringSubmatchIndex(v, -1)
+	if mm == nil {
+		return []nodes.Node{t}
+	}
+	var res []nodes.Node
+	pos := 0
+	for _, m := range mm {
+		if m[0] > pos {
+			res = append(res, textSpan(t, v[pos:m[0]]))
+		}
+		in := nodes.NewIconNode(v[m[2]:m[3]])
+		in.MutateEnv(t.Env())
+		in.MutatePos(t.Pos())
+		res = append(res, in)
+		pos = m[1]
+	}
+	if pos < len(v) {
+		res = append(res, textSpan(t, v[pos:]))
+	}
+	return res
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestIcons(t *testing.T) {
+	text := func(s string) *nodes.TextNode {
+		return nodes.NewTextNode(nodes.NewTextNodeOptions{Value: s})
+	}
+	clab := &types.Codelab{Steps: []*types.Step{{
+		Title: "Setup",
+		Content: nodes.NewListNode(
+			nodes.NewHeaderNode(2, text("The {icon:menu} menu")),
+			text("Click {icon:menu} then {icon:more_vert}, not {icon:Bad} nor {icon:}."),
+		),
+	}}}
+	Icons(clab)
+
+	var got []string
+	for _, n := range clab.Steps[0].Content.Nodes {
+		switch n := n.(type) {
+		case *nodes.HeaderNode:
+			got = append(got, "header "+nodes.PlainText(n.Content.Nodes...))
+		case *nodes.TextNode:
+			got = append(got, n.Value)
+		case *nodes.IconNode:
+			got = append(got, "icon "+n.Name)
+		}
+	}
+	want := []string{"header The {icon:menu} menu", "Click ", "icon menu", " then ", "icon more_vert", ", not {icon:Bad} nor {icon:}."}
+	if len(got) != len(want) {
+		t.Fatalf("Icons: %q; want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Icons node %d = %q; want %q", i, got[i], want[i])
+		}
+	}
+}
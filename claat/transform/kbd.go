@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// kbdRegexp matches a [[Ctrl+C]] keyboard shortcut, with its keys in group 1.
+var kbdRegexp = regexp.MustCompile(`\[\[([^\[\]\n]+)\]\]`)
+
+// maxKeyLen is the maximum length of a key name in a keyboard shortcut,
+// so that [[...]] text which is not a shortcut is left as is.
+const maxKeyLen = 16
+
+// Kbd replaces [[Ctrl+C]] keyboard shortcuts in the text of all clab steps,
+// except headings and links, with keyboard nodes. Keys are separated by "+",
+// and a "+" key is written as such, e.g. [[Ctrl++]].
+func Kbd(clab *types.Codelab) {
+	for _, st := range clab.Steps {
+		replaceText(st.Content, kbd)
+	}
+}
+
+// kbd splits t around its keyboard shortcuts.
+func kbd(t *nodes.TextNode) []nodes.Node {
+	v := t.Value
+	var res []nodes.Node
+	pos := 0
+	for _, m := range kbdRegexp.FindAllStringSubmatchIndex(v, -1) {
+		keys := splitKeys(v[m[2]:m[3]])
+		if keys == nil {
+			continue
+		}
+		if m[0] > pos {
+			res = append(res, textSpan(t, v[pos:m[0]]))
+		}
+		kn := nodes.NewKbdNode(keys...)
+		kn.MutateEnv(t.Env())
+		kn.MutatePos(t.Pos())
+		res = append(res, kn)
+		pos = m[1]
+	}
+	if pos == 0 {
+		return []nodes.Node{t}
+	}
+	if pos < len(v) {
+		res = append(res, textSpan(t, v[pos:]))
+	}
+	return res
+}
+
+// splitKeys returns the keys of shortcut s, separated by "+",
+// or nil if s is not a shortcut.
+func splitKeys(s string) []string {
+	var keys []string
+	parts := strings.Split(s, "+")
+	for i := 0; i < len(parts); i++ {
+		k := strings.TrimSpace(parts[i])
+		if k == "" && i+1 < len(parts) && strings.TrimSpace(parts[i+1]) == "" {
+			// the "+" key, as in Ctrl++
+			k = "+"
+			i++
+		}
+		if k == "" || len(k) > maxKeyLen {
+			return nil
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
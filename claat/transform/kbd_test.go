@@ -0,0 +1,68 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestSplitKeys(t *testing.T) {
+	tests := []struct {
+		in  string
+		out []string
+	}{
+		{"Ctrl+C", []string{"Ctrl", "C"}},
+		{" Ctrl + Shift + P ", []string{"Ctrl", "Shift", "P"}},
+		{"Enter", []string{"Enter"}},
+		{"Ctrl++", []string{"Ctrl", "+"}},
+		{"+", []string{"+"}},
+		{"Ctrl+", nil},
+		{"not a keyboard shortcut at all", nil},
+	}
+	for _, tc := range tests {
+		if diff := cmp.Diff(tc.out, splitKeys(tc.in)); diff != "" {
+			t.Errorf("splitKeys(%q) got diff (-want +got):\n%s", tc.in, diff)
+		}
+	}
+}
+
+func TestKbd(t *testing.T) {
+	clab := &types.Codelab{Steps: []*types.Step{{
+		Title: "Copy",
+		Content: nodes.NewListNode(
+			nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Press [[Ctrl+C]], then [[Enter]]; see [[not a keyboard shortcut at all]]."}),
+		),
+	}}}
+	Kbd(clab)
+
+	var got []string
+	for _, n := range clab.Steps[0].Content.Nodes {
+		switch n := n.(type) {
+		case *nodes.TextNode:
+			got = append(got, n.Value)
+		case *nodes.KbdNode:
+			got = append(got, fmt.Sprint(n.Keys))
+		}
+	}
+	want := []string{"Press ", "[Ctrl C]", ", then ", "[Enter]", "; see [[not a keyboard shortcut at all]]."}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Kbd got diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// Names of the built-in passes.
+const (
+	PassAnnotations       = "annotations"
+	PassAudience          = "audience"
+	PassCaptions          = "captions"
+	PassEmoji             = "emoji"
+	PassGlossary          = "glossary"
+	PassIcons             = "icons"
+	PassKbd               = "kbd"
+	PassLabSequence       = "lab-sequence"
+	PassNormalizeHeadings = "normalize-headings"
+	PassNumberActivities  = "number-activities"
+	PassSnippets          = "snippets"
+	PassVars              = "vars"
+	PassXrefs             = "xrefs"
+)
+
+// Built-in passes of every export, in order. Content is expanded and
+// converted by the prepare passes before custom passes see it,
+// and instructor notes, lab sequences, cross-references, activities and
+// glossary terms are resolved by the finish passes once it is final.
+var (
+	preparePasses = []string{PassSnippets, PassVars, PassIcons, PassKbd, PassAnnotations}
+	finishPasses  = []string{PassAudience, PassLabSequence, PassXrefs, PassNumberActivities, PassGlossary}
+)
+
+// DefaultPipeline returns the names of the passes of an export
+// applying custom passes names: the built-in prepare passes,
+// names, in order, and the built-in finish passes.
+func DefaultPipeline(names ...string) []string {
+	res := append([]string(nil), preparePasses...)
+	res = append(res, names...)
+	return append(res, finishPasses...)
+}
+
+// minHeaderLevel is the level of top headers in step content.
+// Level 1 is reserved for the step title.
+const minHeaderLevel = 2
+
+func init() {
+	RegisterCodelab(PassSnippets, func(clab *types.Codelab, opts *Options) error {
+		if opts.Snippets == "" {
+			return nil
+		}
+		lib, err := ReadSnippets(opts.Snippets)
+		if err != nil {
+			return err
+		}
+		return lib.ExpandCodelab(clab, opts.Vars)
+	})
+	RegisterCodelab(PassVars, func(clab *types.Codelab, opts *Options) error {
+		SubstituteCodelab(clab, opts.Vars)
+		return nil
+	})
+	RegisterCodelab(PassEmoji, func(clab *types.Codelab, opts *Options) error {
+		Emoji(clab, opts.Emoji)
+		return nil
+	})
+	RegisterCodelab(PassIcons, codelabFunc(Icons))
+	RegisterCodelab(PassKbd, codelabFunc(Kbd))
+	RegisterCodelab(PassAnnotations, codelabFunc(Annotations))
+	RegisterCodelab(PassCaptions, func(clab *types.Codelab, opts *Options) error {
+		Captions(clab, opts.Captions)
+		return nil
+	})
+	RegisterCodelab(PassAudience, func(clab *types.Codelab, opts *Options) error {
+		Audience(clab, opts.Audience)
+		return nil
+	})
+	RegisterCodelab(PassLabSequence, codelabFunc(LabSequence))
+	RegisterCodelab(PassXrefs, func(clab *types.Codelab, opts *Options) error {
+		for _, w := range Xrefs(clab) {
+			opts.warn(w)
+		}
+		return nil
+	})
+	RegisterCodelab(PassNumberActivities, func(clab *types.Codelab, _ *Options) error {
+		return NumberActivities(clab)
+	})
+	RegisterCodelab(PassGlossary, codelabFunc(Glossary))
+	RegisterCodelab(PassNormalizeHeadings, func(clab *types.Codelab, opts *Options) error {
+		for _, w := range NormalizeCodelabHeadings(clab) {
+			opts.warn(w)
+		}
+		return nil
+	})
+}
+
+// codelabFunc returns a codelab pass applying fn, which has no options.
+func codelabFunc(fn func(*types.Codelab)) CodelabPass {
+	return func(clab *types.Codelab, _ *Options) error {
+		fn(clab)
+		return nil
+	}
+}
+
+// NormalizeHeadings demotes h1 headers of nn, reserved for the step title,
+// to h2 and closes gaps in header levels, so that each header is at most
+// one level deeper than the previous one, in document order, including
+// headers nested in lists, infoboxes and other containers.
+// For instance, an h2 followed by an h4 becomes an h2 followed by an h3.
+func NormalizeHeadings(nn []nodes.Node) ([]nodes.Node, error) {
+	normalizeHeadings(nn)
+	return nn, nil
+}
+
+// headingChange is a header changed by normalizeHeadings.
+type headingChange struct {
+	header *nodes.HeaderNode
+	level  int // original level
+}
+
+// normalizeHeadings normalizes the header levels of nn as NormalizeHeadings
+// does, and returns the changed headers, in document order.
+func normalizeHeadings(nn []nodes.Node) []headingChange {
+	var changed []headingChange
+	prev := minHeaderLevel - 1
+	nodes.WalkNodes(nn, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		hn, ok := n.(*nodes.HeaderNode)
+		if !ok || !entering {
+			return n, nil
+		}
+		level := hn.Level
+		if hn.Level < minHeaderLevel {
+			hn.Level = minHeaderLevel
+		}
+		if hn.Level > prev+1 {
+			hn.Level = prev + 1
+		}
+		if hn.Level != level {
+			changed = append(changed, headingChange{hn, level})
+		}
+		prev = hn.Level
+		return n, nil
+	})
+	return changed
+}
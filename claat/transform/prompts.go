@@ -0,0 +1,110 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+// Names of the shell prompt passes.
+const (
+	PassNormalizePrompts = "normalize-prompts"
+	PassStripPrompts     = "strip-prompts"
+)
+
+var (
+	// promptRegexp matches a leading shell prompt: "$", "#" or "%",
+	// followed by a space or the end of the line.
+	promptRegexp = regexp.MustCompile(`^\s*[$#%](?: |$)`)
+	// rootRegexp matches a command starting a root shell, such as
+	// "sudo -i" or "su -", in which "#" is the prompt.
+	rootRegexp = regexp.MustCompile(`^(?:sudo\s+(?:-i|-s|su)|su)(?:\s|$)`)
+)
+
+func init() {
+	Register(PassNormalizePrompts, PromptsPass(PromptOptions{Normalize: "$ "}))
+	Register(PassStripPrompts, PromptsPass(PromptOptions{PreserveNoCopy: true}))
+}
+
+// PromptOptions configures PromptsPass.
+type PromptOptions struct {
+	// Normalize replaces prompts with this prompt, e.g. "$ ",
+	// instead of stripping them.
+	Normalize string
+	// PreserveNoCopy leaves prompts of display-only code blocks,
+	// tagged nocopy, as they are.
+	PreserveNoCopy bool
+}
+
+// PromptsPass returns a pass which strips or normalizes leading "$", "#"
+// and "%" prompts in terminal code blocks, so that copying a command
+// does not include the prompt.
+//
+// A block is only changed if its first non-blank line starts with a "$" or "%"
+// prompt, and only lines starting with the same prompt character are changed.
+// A leading "#" is a prompt only in a root session, from a command such as
+// "sudo -i" until "exit", and is otherwise the start of a comment.
+// Other lines, such as the output of a command or continuation lines
+// following a trailing "\", are left untouched, as are expected output blocks.
+func PromptsPass(opts PromptOptions) Pass {
+	return func(nn []nodes.Node) ([]nodes.Node, error) {
+		return nodes.WalkNodes(nn, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			cn, ok := n.(*nodes.CodeNode)
+			if !ok || !entering || !cn.Term || cn.Output {
+				return n, nil
+			}
+			if cn.NoCopy && opts.PreserveNoCopy {
+				return n, nil
+			}
+			cn.Value = replacePrompts(cn.Value, opts.Normalize)
+			return n, nil
+		})
+	}
+}
+
+// replacePrompts replaces the prompts of terminal code v with prompt.
+func replacePrompts(v, prompt string) string {
+	lines := strings.Split(v, "\n")
+	var char string
+	cont, root := false, false
+	for i, l := range lines {
+		if char == "" && strings.TrimSpace(l) == "" {
+			continue
+		}
+		m := promptRegexp.FindString(l)
+		c := strings.TrimSpace(m)
+		if char == "" {
+			if m == "" || c == "#" {
+				return v
+			}
+			char = c
+		}
+		if m != "" && !cont && (c == char || c == "#" && root) {
+			cmd := strings.TrimSpace(l[len(m):])
+			switch {
+			case c == "#" && cmd == "exit":
+				root = false
+			case c == char && rootRegexp.MatchString(cmd):
+				root = true
+			}
+			lines[i] = prompt + l[len(m):]
+		}
+		cont = strings.HasSuffix(strings.TrimRight(l, " \t"), `\`)
+	}
+	return strings.Join(lines, "\n")
+}
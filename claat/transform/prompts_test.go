@@ -0,0 +1,105 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+)
+
+func TestPromptsPass(t *testing.T) {
+	tests := []struct {
+		name string
+		opts PromptOptions
+		code *nodes.CodeNode
+		want string
+	}{
+		{
+			name: "Strip",
+			code: nodes.NewCodeNode("$ gcloud init\n$ gcloud auth login\n$\n", true, ""),
+			want: "gcloud init\ngcloud auth login\n\n",
+		},
+		{
+			name: "Output",
+			code: nodes.NewCodeNode("\n$ ls\nfile.txt\n# not a prompt\n", true, ""),
+			want: "\nls\nfile.txt\n# not a prompt\n",
+		},
+		{
+			name: "Continuation",
+			code: nodes.NewCodeNode("$ gcloud run deploy \\\n  $ARG\n$ echo \\\n# done\n", true, ""),
+			want: "gcloud run deploy \\\n  $ARG\necho \\\n# done\n",
+		},
+		{
+			name: "NoPrompt",
+			code: nodes.NewCodeNode("# comment\n$ gcloud init\n", false, "sh"),
+			want: "# comment\n$ gcloud init\n",
+		},
+		{
+			name: "NoLeadingPrompt",
+			code: nodes.NewCodeNode("export X=1\n$ echo $X\n", true, ""),
+			want: "export X=1\n$ echo $X\n",
+		},
+		{
+			name: "Comments",
+			code: nodes.NewCodeNode("# update the packages\napt-get update\n", true, ""),
+			want: "# update the packages\napt-get update\n",
+		},
+		{
+			name: "CommentsAfterPrompts",
+			code: nodes.NewCodeNode("$ ls\n# list the files\n$ pwd\n", true, ""),
+			want: "ls\n# list the files\npwd\n",
+		},
+		{
+			name: "Root",
+			code: nodes.NewCodeNode("$ sudo -i\n# apt-get update\n# apt-get install git\n# exit\n# done\n$ git --version\n", true, ""),
+			want: "sudo -i\napt-get update\napt-get install git\nexit\n# done\ngit --version\n",
+		},
+		{
+			name: "Normalize",
+			opts: PromptOptions{Normalize: "$ "},
+			code: nodes.NewCodeNode("% ls\nfile.txt\n% pwd\n", true, ""),
+			want: "$ ls\nfile.txt\n$ pwd\n",
+		},
+		{
+			name: "PreserveNoCopy",
+			opts: PromptOptions{PreserveNoCopy: true},
+			code: &nodes.CodeNode{Term: true, NoCopy: true, Value: "$ ls\n"},
+			want: "$ ls\n",
+		},
+		{
+			name: "StripNoCopy",
+			code: &nodes.CodeNode{Term: true, NoCopy: true, Value: "$ ls\n"},
+			want: "ls\n",
+		},
+		{
+			name: "OutputBlock",
+			code: &nodes.CodeNode{Term: true, Output: true, Value: "# ok\n"},
+			want: "# ok\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			list := nodes.NewListNode(tc.code)
+			if _, err := PromptsPass(tc.opts)([]nodes.Node{list}); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, tc.code.Value); diff != "" {
+				t.Errorf("PromptsPass(%+v) got diff (-want +got):\n%s", tc.opts, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// Quizzes returns the scoring metadata of the graded surveys of clab,
+// in document order. Questions without points are worth 1 point.
+// Free text questions are left out: they cannot be graded.
+func Quizzes(clab *types.Codelab) []*types.Quiz {
+	var res []*types.Quiz
+	walkQuizzes(clab, func(step int, sn *nodes.SurveyNode) {
+		q := &types.Quiz{ID: sn.ID, Step: step, Pass: sn.Pass}
+		for _, g := range sn.Groups {
+			if g.Kind == nodes.SurveyText {
+				continue
+			}
+			qq := &types.QuizQuestion{
+				Name:    g.Name,
+				Kind:    string(g.Kind),
+				Points:  questionPoints(g),
+				Options: g.Options,
+				Correct: g.Correct,
+			}
+			q.Points += qq.Points
+			q.Questions = append(q.Questions, qq)
+		}
+		res = append(res, q)
+	})
+	return res
+}
+
+// ValidateQuizzes checks the scoring metadata of the graded surveys of clab
+// and returns the problems found: every question must have exactly one
+// correct answer, among its options, or at least one if it is multi-select.
+// Free text questions cannot be graded, and the pass threshold cannot
+// exceed the maximum score.
+func ValidateQuizzes(clab *types.Codelab) []string {
+	var res []string
+	walkQuizzes(clab, func(step int, sn *nodes.SurveyNode) {
+		report := func(format string, args ...interface{}) {
+			prefix := fmt.Sprintf("%s: quiz %s: ", clab.Steps[step-1].Location(step, sn), sn.ID)
+			res = append(res, prefix+fmt.Sprintf(format, args...))
+		}
+		var total int
+		for _, g := range sn.Groups {
+			if g.Kind == nodes.SurveyText {
+				if g.Points > 0 || len(g.Correct) > 0 {
+					report("free text question %q cannot be graded", g.Name)
+				}
+				continue
+			}
+			total += questionPoints(g)
+			switch n := len(g.Correct); {
+			case g.Kind == nodes.SurveyMulti && n == 0:
+				report("question %q has no correct answer, want at least 1", g.Name)
+			case g.Kind != nodes.SurveyMulti && n != 1:
+				report("question %q has %d correct answers, want exactly 1", g.Name, n)
+			}
+			for _, c := range g.Correct {
+				if !hasOption(g, c) {
+					report("question %q: correct answer %q is not an option", g.Name, c)
+				}
+			}
+		}
+		if sn.Pass > total {
+			report("pass threshold %d exceeds the maximum score %d", sn.Pass, total)
+		}
+	})
+	return res
+}
+
+// walkQuizzes calls fn for each graded survey of clab
+// with the number of its step, from 1.
+func walkQuizzes(clab *types.Codelab, fn func(step int, sn *nodes.SurveyNode)) {
+	for i, st := range clab.Steps {
+		if st.Content == nil {
+			continue
+		}
+		nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			if sn, ok := n.(*nodes.SurveyNode); ok && entering && sn.Graded() {
+				fn(i+1, sn)
+			}
+			return n, nil
+		})
+	}
+}
+
+// questionPoints returns the score of a correct answer to question g.
+func questionPoints(g *nodes.SurveyGroup) int {
+	if g.Points > 0 {
+		return g.Points
+	}
+	return 1
+}
+
+// hasOption reports whether s is an option of question g.
+func hasOption(g *nodes.SurveyGroup, s string) bool {
+	for _, o := range g.Options {
+		if o == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func quizCodelab(sn ...nodes.Node) *types.Codelab {
+	clab := types.NewCodelab()
+	clab.Steps = []*types.Step{
+		{Title: "Intro", Content: nodes.NewListNode(nodes.NewSurveyNode("feedback", &nodes.SurveyGroup{Name: "Level", Options: []string{"a", "b"}}))},
+		{Title: "Quiz", Content: nodes.NewListNode(sn...)},
+	}
+	return clab
+}
+
+func TestQuizzes(t *testing.T) {
+	sn := nodes.NewSurveyNode("quiz",
+		&nodes.SurveyGroup{Name: "Q1", Options: []string{"a", "b"}, Correct: []string{"b"}},
+		&nodes.SurveyGroup{Name: "Q2", Kind: nodes.SurveyMulti, Options: []string{"a", "b", "c"}, Correct: []string{"a", "c"}, Points: 3},
+		&nodes.SurveyGroup{Name: "Comments", Kind: nodes.SurveyText},
+	)
+	sn.Pass = 2
+	want := []*types.Quiz{{
+		ID:     "quiz",
+		Step:   2,
+		Pass:   2,
+		Points: 4,
+		Questions: []*types.QuizQuestion{
+			{Name: "Q1", Points: 1, Options: []string{"a", "b"}, Correct: []string{"b"}},
+			{Name: "Q2", Kind: "multi", Points: 3, Options: []string{"a", "b", "c"}, Correct: []string{"a", "c"}},
+		},
+	}}
+	clab := quizCodelab(sn)
+	if diff := cmp.Diff(want, Quizzes(clab)); diff != "" {
+		t.Errorf("Quizzes got diff (-want +got):\n%s", diff)
+	}
+	if got := ValidateQuizzes(clab); len(got) != 0 {
+		t.Errorf("ValidateQuizzes = %q; want none", got)
+	}
+}
+
+func TestValidateQuizzes(t *testing.T) {
+	sn := nodes.NewSurveyNode("quiz",
+		&nodes.SurveyGroup{Name: "None", Options: []string{"a", "b"}},
+		&nodes.SurveyGroup{Name: "Two", Options: []string{"a", "b"}, Correct: []string{"a", "b"}},
+		&nodes.SurveyGroup{Name: "Multi", Kind: nodes.SurveyMulti, Options: []string{"a", "b"}},
+		&nodes.SurveyGroup{Name: "Rating", Kind: nodes.SurveyRating, Options: nodes.RatingOptions(1, 5), Correct: []string{"6"}},
+		&nodes.SurveyGroup{Name: "Text", Kind: nodes.SurveyText, Points: 2},
+	)
+	sn.Pass = 10
+	want := []string{
+		`step 2 "Quiz": quiz quiz: question "None" has 0 correct answers, want exactly 1`,
+		`step 2 "Quiz": quiz quiz: question "Two" has 2 correct answers, want exactly 1`,
+		`step 2 "Quiz": quiz quiz: question "Multi" has no correct answer, want at least 1`,
+		`step 2 "Quiz": quiz quiz: question "Rating": correct answer "6" is not an option`,
+		`step 2 "Quiz": quiz quiz: free text question "Text" cannot be graded`,
+		`step 2 "Quiz": quiz quiz: pass threshold 10 exceeds the maximum score 4`,
+	}
+	if diff := cmp.Diff(want, ValidateQuizzes(quizCodelab(sn))); diff != "" {
+		t.Errorf("ValidateQuizzes got diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,389 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// Redaction actions.
+const (
+	// RedactFail fails the export of codelabs with sensitive content.
+	RedactFail = "fail"
+	// RedactMask replaces sensitive content with RedactedText.
+	RedactMask = "mask"
+)
+
+// RedactedText replaces sensitive content with RedactMask.
+const RedactedText = "[REDACTED]"
+
+// DefaultRedactRules are the patterns of well-known credentials,
+// always applied in addition to the rules of a Redaction, and looked for
+// by ScanSecrets.
+var DefaultRedactRules = []*RedactRule{
+	defaultRule("gcp-service-account-key", `\{[^{}]*"type"\s*:\s*"service_account"[^{}]*"private_key"[^{}]*\}`, true),
+	defaultRule("private-key", `-----BEGIN (?:[A-Z]+ )?PRIVATE KEY-----`, true),
+	defaultRule("aws-access-key-id", `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`, false),
+	defaultRule("aws-secret-access-key", `(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?[0-9A-Za-z/+]{40}\b`, false),
+	defaultRule("google-api-key", `AIza[0-9A-Za-z_-]{35}`, false),
+	defaultRule("google-oauth-access-token", `\bya29\.[0-9A-Za-z_-]{20,}`, false),
+	defaultRule("google-oauth-refresh-token", `\b1//0[0-9A-Za-z_-]{30,}`, false),
+	defaultRule("google-oauth-client-secret", `GOCSPX-[0-9A-Za-z_-]{28}`, false),
+	defaultRule("github-token", `\bgh[pousr]_[0-9A-Za-z]{36}\b`, false),
+	defaultRule("slack-token", `\bxox[abprs]-[0-9A-Za-z-]{10,}`, false),
+	defaultRule("jwt", `\beyJ[0-9A-Za-z_-]{10,}\.eyJ[0-9A-Za-z_-]{10,}\.[0-9A-Za-z_-]{10,}`, false),
+	defaultRule("bearer-token", `(?i)\bauthorization:\s*bearer\s+[0-9A-Za-z._~+/-]{20,}=*`, false),
+}
+
+// defaultRule returns a compiled rule of DefaultRedactRules.
+// Its matches are described without hint if noHint is true.
+func defaultRule(name, pattern string, noHint bool) *RedactRule {
+	return &RedactRule{Name: name, Pattern: pattern, re: regexp.MustCompile(pattern), noHint: noHint}
+}
+
+// RedactRule is a pattern of sensitive content, such as an API key,
+// a project ID or an internal hostname.
+type RedactRule struct {
+	// Name identifies the rule in errors.
+	Name string `json:"name"`
+	// Pattern is the regular expression of sensitive content.
+	Pattern string `json:"pattern"`
+	// Allow are regular expressions of matches of Pattern which are not
+	// sensitive, such as sample project IDs. They must match the whole match.
+	Allow []string `json:"allow,omitempty"`
+
+	re    *regexp.Regexp
+	allow []*regexp.Regexp
+	// noHint leaves the start of matches out of their description,
+	// such as for a whole key file.
+	noHint bool
+}
+
+// Redaction configures the detection of sensitive content.
+type Redaction struct {
+	// Action is RedactFail, the default if empty, or RedactMask.
+	Action string `json:"action,omitempty"`
+	// Allow are literal strings which are never sensitive, for all rules.
+	Allow []string `json:"allow,omitempty"`
+	// Rules are the patterns of sensitive content,
+	// along with DefaultRedactRules.
+	Rules []*RedactRule `json:"rules,omitempty"`
+}
+
+// ReadRedaction reads a Redaction from a JSON file and compiles its rules.
+func ReadRedaction(path string) (*Redaction, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Redaction{}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, err
+	}
+	return r, r.Compile()
+}
+
+// Compile checks the action of r and compiles the patterns of its rules.
+func (r *Redaction) Compile() error {
+	switch r.Action {
+	case "", RedactFail, RedactMask:
+	default:
+		return fmt.Errorf("unknown redaction action %q; want %s or %s", r.Action, RedactFail, RedactMask)
+	}
+	for _, rule := range r.rules() {
+		if rule.re != nil {
+			continue
+		}
+		if rule.Name == "" || rule.Pattern == "" {
+			return fmt.Errorf("redaction rule %q: need a name and a pattern", rule.Name)
+		}
+		var err error
+		if rule.re, err = regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("redaction rule %s: %v", rule.Name, err)
+		}
+		for _, a := range rule.Allow {
+			re, err := regexp.Compile(`^(?:` + a + `)$`)
+			if err != nil {
+				return fmt.Errorf("redaction rule %s: allow: %v", rule.Name, err)
+			}
+			rule.allow = append(rule.allow, re)
+		}
+	}
+	return nil
+}
+
+// rules returns the rules of r, after DefaultRedactRules.
+func (r *Redaction) rules() []*RedactRule {
+	return append(append([]*RedactRule(nil), DefaultRedactRules...), r.Rules...)
+}
+
+// allowed reports whether the match m of rule is not sensitive.
+func (r *Redaction) allowed(rule *RedactRule, m string) bool {
+	for _, a := range r.Allow {
+		if m == a {
+			return true
+		}
+	}
+	for _, re := range rule.allow {
+		if re.MatchString(m) {
+			return true
+		}
+	}
+	return false
+}
+
+// span is the byte range [start, end) of sensitive content in a string.
+type span struct{ start, end int }
+
+// find returns the merged spans of sensitive content in s, sorted, along with
+// a description of each match. The descriptions do not reveal the matches.
+func (r *Redaction) find(s string) ([]span, []string) {
+	var (
+		spans []span
+		found []string
+	)
+	for _, rule := range r.rules() {
+		for _, loc := range rule.re.FindAllStringIndex(s, -1) {
+			m := s[loc[0]:loc[1]]
+			if r.allowed(rule, m) {
+				continue
+			}
+			spans = append(spans, span{loc[0], loc[1]})
+			found = append(found, rule.describe(m))
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	var merged []span
+	for _, sp := range spans {
+		if n := len(merged); n > 0 && sp.start <= merged[n-1].end {
+			if sp.end > merged[n-1].end {
+				merged[n-1].end = sp.end
+			}
+			continue
+		}
+		merged = append(merged, sp)
+	}
+	return merged, found
+}
+
+// mask returns the bytes [start, end) of s with the parts in spans removed,
+// and RedactedText written where a span starts.
+func mask(s string, spans []span, start, end int) string {
+	var b strings.Builder
+	pos := start
+	for _, sp := range spans {
+		if sp.end <= start || sp.start >= end {
+			continue
+		}
+		if sp.start > pos {
+			b.WriteString(s[pos:sp.start])
+		}
+		if sp.start >= start {
+			b.WriteString(RedactedText)
+		}
+		pos = sp.end
+		if pos > end {
+			pos = end
+		}
+	}
+	b.WriteString(s[pos:end])
+	return b.String()
+}
+
+// redact returns s with sensitive content replaced by RedactedText, along with
+// a description of each match. The descriptions do not reveal the matches.
+func (r *Redaction) redact(s string) (string, []string) {
+	spans, found := r.find(s)
+	if len(spans) == 0 {
+		return s, nil
+	}
+	return mask(s, spans, 0, len(s)), found
+}
+
+// describe returns the description of match m of rule,
+// which does not reveal m.
+func (rule *RedactRule) describe(m string) string {
+	if rule.noHint {
+		return rule.Name
+	}
+	return fmt.Sprintf("%s %q", rule.Name, hint(m))
+}
+
+// hint returns the start of sensitive content m, enough to find it in
+// a codelab but not to use it.
+func hint(m string) string {
+	const n = 4
+	r := []rune(m)
+	if len(r) <= 2*n {
+		return string(r[:len(r)/2]) + "…"
+	}
+	return string(r[:n]) + "…"
+}
+
+// redactor collects the sensitive content found with a Redaction.
+type redactor struct {
+	r     *Redaction
+	found []string
+}
+
+// field looks for sensitive content in *s, located at loc,
+// and masks it with RedactMask.
+func (rd *redactor) field(loc string, s *string) {
+	v, ff := rd.r.redact(*s)
+	for _, f := range ff {
+		rd.found = append(rd.found, fmt.Sprintf("%s: %s", loc, f))
+	}
+	if rd.r.Action == RedactMask {
+		*s = v
+	}
+}
+
+// run looks for sensitive content in the text of consecutive text nodes tt,
+// located at loc, such as the differently formatted runs of a paragraph,
+// so that content split across runs is found too.
+func (rd *redactor) run(loc string, tt []*nodes.TextNode) {
+	if len(tt) == 1 {
+		rd.field(loc, &tt[0].Value)
+		return
+	}
+	var b strings.Builder
+	for _, t := range tt {
+		b.WriteString(t.Value)
+	}
+	s := b.String()
+	spans, ff := rd.r.find(s)
+	for _, f := range ff {
+		rd.found = append(rd.found, fmt.Sprintf("%s: %s", loc, f))
+	}
+	if rd.r.Action != RedactMask || len(spans) == 0 {
+		return
+	}
+	start := 0
+	for _, t := range tt {
+		end := start + len(t.Value)
+		t.Value = mask(s, spans, start, end)
+		start = end
+	}
+}
+
+// nodes looks for sensitive content in nn, the content of step i of st.
+func (rd *redactor) nodes(st *types.Step, i int, nn []nodes.Node) {
+	var run []*nodes.TextNode
+	flush := func() {
+		if len(run) > 0 {
+			rd.run(st.Location(i, run[0]), run)
+			run = nil
+		}
+	}
+	nodes.WalkNodes(nn, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if t, ok := n.(*nodes.TextNode); ok {
+			if entering {
+				run = append(run, t)
+			}
+			return n, nil
+		}
+		flush()
+		if !entering {
+			return n, nil
+		}
+		loc := st.Location(i, n)
+		switch v := n.(type) {
+		case *nodes.CodeNode:
+			rd.field(loc, &v.Value)
+		case *nodes.URLNode:
+			rd.field(loc, &v.URL)
+		case *nodes.ImageNode:
+			for _, s := range []*string{&v.Src, &v.Dark, &v.Alt, &v.Title, &v.Caption} {
+				rd.field(loc, s)
+			}
+		case *nodes.IframeNode:
+			rd.field(loc, &v.URL)
+		case *nodes.VideoNode:
+			for _, s := range []*string{&v.Src, &v.Poster, &v.Captions} {
+				rd.field(loc, s)
+			}
+		case *nodes.AttachmentNode:
+			rd.field(loc, &v.Src)
+			rd.field(loc, &v.Name)
+		}
+		return n, nil
+	})
+	flush()
+}
+
+// err returns an error listing the sensitive content found by rd,
+// if any and it is not masked.
+func (rd *redactor) err() error {
+	if len(rd.found) > 0 && rd.r.Action != RedactMask {
+		return fmt.Errorf("sensitive content:\n%s", strings.Join(rd.found, "\n"))
+	}
+	return nil
+}
+
+// Redact looks for sensitive content in the title, summary, feedback and
+// extra metadata, step titles, and the text, code, links, images and
+// embedded frames and videos of all steps of clab, as configured by r.
+// Text split across differently formatted runs is matched as a whole.
+// r must have been compiled.
+//
+// With RedactMask, it replaces sensitive content with RedactedText and
+// returns the description of each occurrence. Otherwise, clab is left as is
+// and an error lists them all.
+func Redact(clab *types.Codelab, r *Redaction) ([]string, error) {
+	rd := &redactor{r: r}
+	rd.field("title", &clab.Title)
+	rd.field("summary", &clab.Summary)
+	rd.field("feedback", &clab.Feedback)
+	keys := make([]string, 0, len(clab.Extra))
+	for k := range clab.Extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := clab.Extra[k]
+		rd.field("metadata "+k, &v)
+		clab.Extra[k] = v
+	}
+	for i, st := range clab.Steps {
+		rd.field(st.Location(i+1, nil), &st.Title)
+		rd.nodes(st, i+1, st.Content.Nodes)
+	}
+	return rd.found, rd.err()
+}
+
+// RedactField is a string checked for sensitive content by RedactFields,
+// such as the text of a comment, located at Loc.
+type RedactField struct {
+	Loc   string
+	Value *string
+}
+
+// RedactFields looks for sensitive content in ff, as Redact does
+// in the content of a codelab.
+func RedactFields(ff []RedactField, r *Redaction) ([]string, error) {
+	rd := &redactor{r: r}
+	for _, f := range ff {
+		rd.field(f.Loc, f.Value)
+	}
+	return rd.found, rd.err()
+}
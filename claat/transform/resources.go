@@ -0,0 +1,124 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+var (
+	// gcsRegexp matches a gs://bucket/object URL, or a Cloud Storage
+	// HTTP URL of an object, with the bucket and object path in group 1 or 2.
+	gcsRegexp = regexp.MustCompile(`\bgs://([a-z0-9][a-z0-9._-]*[a-z0-9](?:/[^\s'"` + "`" + `<>()]*)?)|https?://storage\.(?:googleapis|cloud\.google)\.com/([a-z0-9][a-z0-9._-]*[a-z0-9](?:/[^\s'"` + "`" + `<>()?#]*)?)`)
+	// repoRegexp matches a GitHub repository URL, including SSH clone URLs,
+	// with the owner in group 1 and the repository in group 2.
+	repoRegexp = regexp.MustCompile(`\bgithub\.com[/:]([A-Za-z0-9_.-]+)/([A-Za-z0-9_.-]+)`)
+	// apiRegexp matches the service name of a Google API.
+	apiRegexp = regexp.MustCompile(`\b[a-z0-9-]+(?:\.[a-z0-9-]+)*\.googleapis\.com\b`)
+)
+
+// nonAPIs are googleapis.com hosts which are not services to enable.
+var nonAPIs = map[string]bool{
+	"www.googleapis.com":     true,
+	"storage.googleapis.com": true,
+}
+
+// Resources returns the manifest of the external resources referenced
+// in the text, links and code of clab: Cloud Storage objects, repositories
+// and Google APIs. Resources are sorted by name.
+func Resources(clab *types.Codelab) *types.Resources {
+	m := resourceSet{
+		gcs:   make(map[string][]int),
+		repos: make(map[string][]int),
+		apis:  make(map[string][]int),
+	}
+	for i, st := range clab.Steps {
+		if st.Content == nil {
+			continue
+		}
+		nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			if !entering {
+				return n, nil
+			}
+			switch n := n.(type) {
+			case *nodes.TextNode:
+				m.scan(n.Value, i+1)
+			case *nodes.CodeNode:
+				m.scan(n.Value, i+1)
+			case *nodes.URLNode:
+				m.scan(n.URL, i+1)
+			case *nodes.AttachmentNode:
+				m.scan(n.Src, i+1)
+			case *nodes.VideoNode:
+				m.scan(n.Src, i+1)
+			}
+			return n, nil
+		})
+	}
+	return &types.Resources{
+		ID:         clab.ID,
+		GCSObjects: resourceList(m.gcs),
+		Repos:      resourceList(m.repos),
+		APIs:       resourceList(m.apis),
+	}
+}
+
+// resourceSet maps resource names to the numbers of steps referencing them.
+type resourceSet struct {
+	gcs, repos, apis map[string][]int
+}
+
+// scan adds the resources referenced in s, in step number step.
+func (m resourceSet) scan(s string, step int) {
+	for _, sm := range gcsRegexp.FindAllStringSubmatch(s, -1) {
+		obj := sm[1]
+		if obj == "" {
+			obj = sm[2]
+		}
+		addResource(m.gcs, "gs://"+strings.TrimRight(obj, ".,;:"), step)
+	}
+	for _, sm := range repoRegexp.FindAllStringSubmatch(s, -1) {
+		repo := strings.TrimSuffix(strings.TrimRight(sm[2], "."), ".git")
+		addResource(m.repos, "github.com/"+sm[1]+"/"+repo, step)
+	}
+	for _, api := range apiRegexp.FindAllString(s, -1) {
+		if !nonAPIs[api] {
+			addResource(m.apis, api, step)
+		}
+	}
+}
+
+// addResource records that resource name is referenced in step number step.
+func addResource(m map[string][]int, name string, step int) {
+	steps := m[name]
+	if len(steps) == 0 || steps[len(steps)-1] != step {
+		m[name] = append(steps, step)
+	}
+}
+
+// resourceList returns the resources of m sorted by name.
+func resourceList(m map[string][]int) []*types.Resource {
+	var res []*types.Resource
+	for name, steps := range m {
+		res = append(res, &types.Resource{Name: name, Steps: steps})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestResources(t *testing.T) {
+	clab := types.NewCodelab()
+	clab.ID = "lab"
+	clab.NewStep("Setup").Content.Append(
+		nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Enable compute.googleapis.com and copy gs://my-bucket/data.csv."}),
+		nodes.NewCodeNode("gcloud services enable run.googleapis.com compute.googleapis.com\n"+
+			"git clone https://github.com/GoogleCloudPlatform/golang-samples.git\n"+
+			"curl https://www.googleapis.com/oauth2/v1/tokeninfo", true, ""),
+	)
+	clab.NewStep("Deploy").Content.Append(
+		nodes.NewURLNode("https://storage.googleapis.com/my-bucket/data.csv"),
+		nodes.NewCodeNode("git clone git@github.com:owner/repo\ngsutil cp x gs://other-bucket/", true, ""),
+		nodes.NewInfoboxNode(nodes.InfoboxPositive, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "See github.com/owner/repo."})),
+	)
+	want := &types.Resources{
+		ID: "lab",
+		GCSObjects: []*types.Resource{
+			{Name: "gs://my-bucket/data.csv", Steps: []int{1, 2}},
+			{Name: "gs://other-bucket/", Steps: []int{2}},
+		},
+		Repos: []*types.Resource{
+			{Name: "github.com/GoogleCloudPlatform/golang-samples", Steps: []int{1}},
+			{Name: "github.com/owner/repo", Steps: []int{2}},
+		},
+		APIs: []*types.Resource{
+			{Name: "compute.googleapis.com", Steps: []int{1}},
+			{Name: "run.googleapis.com", Steps: []int{1}},
+		},
+	}
+	if diff := cmp.Diff(want, Resources(clab)); diff != "" {
+		t.Errorf("Resources() got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestResourcesEmpty(t *testing.T) {
+	clab := types.NewCodelab()
+	clab.NewStep("Intro").Content.Append(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Nothing to provision."}))
+	if r := Resources(clab); !r.Empty() {
+		t.Errorf("Resources() = %+v; want no resources", r)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// ScanSecrets returns the credentials of DefaultRedactRules found in the code
+// blocks of clab, such as AWS keys, Google Cloud service account keys and OAuth tokens,
+// each with its location and the start of the credential.
+// Content is left as is.
+func ScanSecrets(clab *types.Codelab) []string {
+	var res []string
+	for i, st := range clab.Steps {
+		if st.Content == nil {
+			continue
+		}
+		nodes.WalkNodes(st.Content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+			cn, ok := n.(*nodes.CodeNode)
+			if !ok || !entering {
+				return n, nil
+			}
+			for _, s := range scanSecrets(cn.Value) {
+				res = append(res, fmt.Sprintf("%s: %s", st.Location(i+1, cn), s))
+			}
+			return n, nil
+		})
+	}
+	return res
+}
+
+// scanSecrets returns the credentials of DefaultRedactRules found in s,
+// by rule, then in order. A match overlapping that of an earlier rule is
+// not reported again, e.g. the private key of a service account key file.
+func scanSecrets(s string) []string {
+	var res []string
+	var found [][]int
+	for _, rule := range DefaultRedactRules {
+	matches:
+		for _, m := range rule.re.FindAllStringIndex(s, -1) {
+			for _, f := range found {
+				if m[0] < f[1] && f[0] < m[1] {
+					continue matches
+				}
+			}
+			found = append(found, m)
+			res = append(res, rule.describe(s[m[0]:m[1]]))
+		}
+	}
+	return res
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// Titles of the sections listing prerequisite and next labs.
+const (
+	PrerequisitesTitle = "Before you begin"
+	NextLabsTitle      = "What's next"
+)
+
+// LabSequence adds a "Before you begin" section listing the prerequisite
+// labs of clab at the start of its first step, and a "What's next" section
+// listing its next labs at the end of its last step, unless the step
+// already has a heading with the same title, e.g. in a re-exported codelab.
+//
+// Labs are linked to by URL, or by ID as a sibling codelab directory.
+func LabSequence(clab *types.Codelab) {
+	if len(clab.Steps) == 0 {
+		return
+	}
+	if first := clab.Steps[0]; len(clab.Prerequisites) > 0 && !hasHeading(first, PrerequisitesTitle) {
+		first.Content.Nodes = append(labSection(PrerequisitesTitle, clab.Prerequisites), first.Content.Nodes...)
+	}
+	if last := clab.Steps[len(clab.Steps)-1]; len(clab.Next) > 0 && !hasHeading(last, NextLabsTitle) {
+		last.Content.Append(labSection(NextLabsTitle, clab.Next)...)
+	}
+}
+
+// labSection returns a heading with title followed by a list of links to labs.
+func labSection(title string, labs []string) []nodes.Node {
+	list := nodes.NewItemsListNode("", 0)
+	for _, lab := range labs {
+		link := nodes.NewURLNode(labURL(lab), nodes.NewTextNode(nodes.NewTextNodeOptions{Value: lab}))
+		link.Target = ""
+		list.NewItem(link)
+	}
+	h := nodes.NewHeaderNode(minHeaderLevel, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: title}))
+	return []nodes.Node{h, list}
+}
+
+// labURL returns the URL of lab, a URL or the ID of a codelab exported
+// to a sibling directory.
+func labURL(lab string) string {
+	if strings.Contains(lab, "://") {
+		return lab
+	}
+	return "../" + lab + "/"
+}
+
+// hasHeading reports whether st has a top-level heading with title,
+// ignoring case.
+func hasHeading(st *types.Step, title string) bool {
+	for _, n := range st.Content.Nodes {
+		if h, ok := n.(*nodes.HeaderNode); ok && strings.EqualFold(strings.TrimSpace(nodes.PlainText(h.Content.Nodes...)), title) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// sectionLinks returns the heading title and link URLs of the lab section
+// starting at nn[0].
+func sectionLinks(nn []nodes.Node) (string, []string) {
+	title := nodes.PlainText(nn[0].(*nodes.HeaderNode).Content.Nodes...)
+	var urls []string
+	for _, it := range nn[1].(*nodes.ItemsListNode).Items {
+		urls = append(urls, it.Nodes[0].(*nodes.URLNode).URL)
+	}
+	return title, urls
+}
+
+func TestLabSequence(t *testing.T) {
+	clab := types.NewCodelab()
+	clab.Prerequisites = []string{"gcs-basics", "https://example.com/labs/iam"}
+	clab.Next = []string{"bigquery-intro"}
+	text := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Welcome"})
+	clab.NewStep("Overview").Content.Append(text)
+	clab.NewStep("Congratulations")
+
+	LabSequence(clab)
+	nn := clab.Steps[0].Content.Nodes
+	if len(nn) != 3 || nn[2] != text {
+		t.Fatalf("first step nodes = %v; want a section followed by the step content", nn)
+	}
+	title, urls := sectionLinks(nn)
+	if diff := cmp.Diff([]string{"../gcs-basics/", "https://example.com/labs/iam"}, urls); diff != "" || title != PrerequisitesTitle {
+		t.Errorf("first step section %q got diff (-want +got):\n%s", title, diff)
+	}
+	title, urls = sectionLinks(clab.Steps[1].Content.Nodes)
+	if diff := cmp.Diff([]string{"../bigquery-intro/"}, urls); diff != "" || title != NextLabsTitle {
+		t.Errorf("last step section %q got diff (-want +got):\n%s", title, diff)
+	}
+
+	// Sections are not added twice.
+	LabSequence(clab)
+	if n := len(clab.Steps[0].Content.Nodes); n != 3 {
+		t.Errorf("first step has %d nodes after a second LabSequence, want 3", n)
+	}
+	if n := len(clab.Steps[1].Content.Nodes); n != 2 {
+		t.Errorf("last step has %d nodes after a second LabSequence, want 2", n)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/parser"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+var (
+	// snippetRegexp matches a {{> name key=value ...}} snippet reference,
+	// which must be the only content of a paragraph.
+	snippetRegexp = regexp.MustCompile(`^\s*\{\{>\s*([\w.-]+)((?:\s+[A-Za-z_][\w.-]*=(?:"[^"]*"|“[^”]*”|[^\s}]+))*)\s*\}\}\s*$`)
+	// snippetArgRegexp matches a single key=value argument of a snippet reference.
+	// Values may be quoted, also with typographic quotes of Markdown and Google Docs.
+	snippetArgRegexp = regexp.MustCompile(`([A-Za-z_][\w.-]*)=(?:"([^"]*)"|“([^”]*)”|([^\s}]+))`)
+)
+
+// snippetParsers maps snippet file extensions to the parser of their content.
+var snippetParsers = map[string]string{
+	".md":   "md",
+	".html": "html",
+	".htm":  "html",
+}
+
+// Snippets is a library of reusable named content, such as setup or cleanup
+// instructions shared by many codelabs.
+type Snippets struct {
+	src map[string]*snippet // keyed by snippet name
+}
+
+type snippet struct {
+	parser string // parser name
+	body   []byte // snippet source
+}
+
+// ReadSnippets loads a snippet library from dir. Each Markdown or HTML file
+// of dir is a codelab fragment defining a snippet named after the file,
+// without extension: "activate-cloud-shell.md" defines "activate-cloud-shell".
+func ReadSnippets(dir string) (*Snippets, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	lib := &Snippets{src: make(map[string]*snippet)}
+	for _, fi := range files {
+		ext := strings.ToLower(filepath.Ext(fi.Name()))
+		p, ok := snippetParsers[ext]
+		if fi.IsDir() || !ok {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, fi.Name()))
+		if err != nil {
+			return nil, err
+		}
+		lib.src[strings.TrimSuffix(fi.Name(), filepath.Ext(fi.Name()))] = &snippet{parser: p, body: b}
+	}
+	return lib, nil
+}
+
+// Expand replaces each paragraph consisting of a {{> name key=value ...}}
+// reference with the content of snippet name, parsed anew for every reference.
+//
+// Variable references of the snippet content are substituted with
+// the key=value arguments of the reference, which override opts.Vars.
+// Unknown variables are left as is, for a later SubstituteVars.
+// Snippets may not reference other snippets.
+func (lib *Snippets) Expand(nn []nodes.Node, opts VarsOptions) ([]nodes.Node, error) {
+	var res []nodes.Node
+	for _, n := range nn {
+		name, args, ok := snippetRef(n)
+		if !ok {
+			if l, isList := n.(*nodes.ListNode); isList {
+				var err error
+				if l.Nodes, err = lib.Expand(l.Nodes, opts); err != nil {
+					return nil, err
+				}
+			}
+			res = append(res, n)
+			continue
+		}
+		sn, err := lib.snippet(name, args, opts)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, sn...)
+	}
+	return res, nil
+}
+
+// ExpandCodelab expands snippet references in the content of every step of clab.
+func (lib *Snippets) ExpandCodelab(clab *types.Codelab, opts VarsOptions) error {
+	for _, st := range clab.Steps {
+		nn, err := lib.Expand(st.Content.Nodes, opts)
+		if err != nil {
+			return fmt.Errorf("%s: %v", st.Title, err)
+		}
+		st.Content.Nodes = nn
+	}
+	return nil
+}
+
+// snippet returns parsed content of snippet name with args substituted.
+func (lib *Snippets) snippet(name string, args map[string]string, opts VarsOptions) ([]nodes.Node, error) {
+	s, ok := lib.src[name]
+	if !ok {
+		return nil, fmt.Errorf("no snippet named %q", name)
+	}
+	nn, err := parser.ParseFragment(context.Background(), s.parser, bytes.NewReader(s.body), *parser.NewOptions())
+	if err != nil {
+		return nil, fmt.Errorf("snippet %q: %v", name, err)
+	}
+	vars := make(map[string]string, len(opts.Vars)+len(args))
+	for k, v := range opts.Vars {
+		vars[k] = v
+	}
+	for k, v := range args {
+		vars[k] = v
+	}
+	SubstituteVars(nn, VarsOptions{Vars: vars})
+	return nn, nil
+}
+
+// snippetRef reports whether n is a paragraph referencing a snippet,
+// returning the snippet name and arguments.
+func snippetRef(n nodes.Node) (string, map[string]string, bool) {
+	l, ok := n.(*nodes.ListNode)
+	if !ok || l.Block() != true || len(l.Nodes) == 0 {
+		return "", nil, false
+	}
+	var text strings.Builder
+	for _, c := range l.Nodes {
+		t, ok := c.(*nodes.TextNode)
+		if !ok {
+			return "", nil, false
+		}
+		text.WriteString(t.Value)
+	}
+	m := snippetRegexp.FindStringSubmatch(text.String())
+	if m == nil {
+		return "", nil, false
+	}
+	args := make(map[string]string)
+	for _, a := range snippetArgRegexp.FindAllStringSubmatch(m[2], -1) {
+		args[a[1]] = a[2] + a[3] + a[4]
+	}
+	return m[1], args, true
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/parser"
+	_ "github.com/googlecodelabs/tools/claat/parser/md"
+	"github.com/googlecodelabs/tools/claat/render"
+)
+
+func testSnippets(t *testing.T) *Snippets {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"activate-shell.md": "Activate Cloud Shell in {{zone}}.\n\n```\ngcloud config set project {{project_id}}\n```\n",
+		"notes.txt":         "not a snippet",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lib, err := ReadSnippets(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lib
+}
+
+func TestSnippetsExpand(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{
+			name: "Defaults",
+			in:   "Before\n\n{{> activate-shell}}\n\nAfter\n",
+			out: "<p>Before</p>\n<p>Activate Cloud Shell in &#123;&#123;zone}}.</p>\n" +
+				"<pre><code>gcloud config set project my-project\n</code></pre>\n<p>After</p>\n",
+		},
+		{
+			name: "Overrides",
+			in:   "{{> activate-shell zone=us-east1-b project_id=\"other project\"}}\n",
+			out: "<p>Activate Cloud Shell in us-east1-b.</p>\n" +
+				"<pre><code>gcloud config set project other project\n</code></pre>\n",
+		},
+		{
+			name: "NotAlone",
+			in:   "See {{> activate-shell}}\n",
+			out:  "<p>See &#123;&#123;&gt; activate-shell}}</p>\n",
+		},
+	}
+	lib := testSnippets(t)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nn, err := parser.ParseFragment(context.Background(), "md", strings.NewReader(tc.in), *parser.NewOptions())
+			if err != nil {
+				t.Fatal(err)
+			}
+			nn, err = lib.Expand(nn, VarsOptions{Vars: testVars})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := render.WriteHTML(&buf, "", "", nn...); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.out, buf.String()); diff != "" {
+				t.Errorf("Expand() got diff (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestSnippetsExpandUnknown(t *testing.T) {
+	lib := testSnippets(t)
+	p := nodes.NewListNode(nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "{{> cleanup}}"}))
+	p.MutateBlock(true)
+	if _, err := lib.Expand([]nodes.Node{p}, VarsOptions{}); err == nil {
+		t.Errorf("Expand() of an unknown snippet returned nil error")
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import "github.com/googlecodelabs/tools/claat/nodes"
+
+// replaceText replaces each text node of content, recursively,
+// with the nodes returned by fn, in document order.
+// Code, headings, as well as text of links, attachments, buttons and other
+// inline nodes with content, are left untouched.
+func replaceText(content *nodes.ListNode, fn func(*nodes.TextNode) []nodes.Node) {
+	eachList(content, func(l *nodes.ListNode) {
+		var res []nodes.Node
+		for _, n := range l.Nodes {
+			t, ok := n.(*nodes.TextNode)
+			if !ok || t.Code {
+				res = append(res, n)
+				continue
+			}
+			res = append(res, fn(t)...)
+		}
+		l.Nodes = res
+	})
+}
+
+// eachList calls fn with content and each list of block or text nodes
+// within it, recursively, in document order, before descending into
+// the nodes of the list, which fn may replace.
+// Headings, links, attachments, buttons and other inline nodes with content
+// are skipped.
+func eachList(content *nodes.ListNode, fn func(*nodes.ListNode)) {
+	if content == nil {
+		return
+	}
+	list := func(l *nodes.ListNode) {
+		if l != nil {
+			fn(l)
+		}
+	}
+	list(content)
+	nodes.WalkNodes(content.Nodes, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if !entering {
+			return n, nil
+		}
+		switch n := n.(type) {
+		case *nodes.HeaderNode, *nodes.URLNode, *nodes.AttachmentNode, *nodes.ButtonNode, *nodes.XrefNode, *nodes.TermNode, *nodes.CodeNode:
+			return n, nodes.SkipChildren
+		case *nodes.ListNode:
+			list(n)
+		case *nodes.InfoboxNode:
+			list(n.Content)
+		case *nodes.ActivityNode:
+			list(n.Content)
+		case *nodes.ImportNode:
+			list(n.Content)
+		case *nodes.ItemsListNode:
+			for _, it := range n.Items {
+				list(it)
+			}
+		case *nodes.GridNode:
+			for _, r := range n.Rows {
+				for _, c := range r {
+					list(c.Content)
+				}
+			}
+		}
+		return n, nil
+	})
+}
+
+// textSpan returns a copy of t with value s, keeping its style and environment.
+func textSpan(t *nodes.TextNode, s string) *nodes.TextNode {
+	t2 := *t
+	t2.Value = s
+	return &t2
+}
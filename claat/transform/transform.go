@@ -0,0 +1,174 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// Pass transforms content nodes of a single codelab step.
+// It may modify nn in place and returns the resulting nodes.
+type Pass func(nn []nodes.Node) ([]nodes.Node, error)
+
+// CodelabPass transforms a whole codelab, configured by opts.
+// It is the kind of passes which need the metadata of the codelab,
+// all of its steps at once, or export options.
+type CodelabPass func(clab *types.Codelab, opts *Options) error
+
+// Options configures the passes of a CodelabPipeline.
+type Options struct {
+	Audience string // instructor notes to keep, see Audience
+	Captions CaptionOptions
+	Emoji    EmojiOptions
+	Snippets string // snippets library directory, if any, see ReadSnippets
+	Vars     VarsOptions
+	// Warn, if not nil, is called with each warning of the passes.
+	Warn func(msg string)
+}
+
+// warn reports warning msg with opts.Warn, if not nil.
+func (opts *Options) warn(msg string) {
+	if opts.Warn != nil {
+		opts.Warn(msg)
+	}
+}
+
+var (
+	passes        = map[string]Pass{}
+	codelabPasses = map[string]CodelabPass{}
+)
+
+// Register registers a new pass p under specified name, making it available
+// to NewPipeline. Custom passes are usually registered from an init function.
+// It panics if another pass is already registered under the same name.
+func Register(name string, p Pass) {
+	checkName(name)
+	passes[name] = p
+}
+
+// RegisterCodelab registers a new codelab pass p under specified name,
+// making it available to NewCodelabPipeline. Names are shared with the
+// passes of Register, and it panics if either already uses name.
+func RegisterCodelab(name string, p CodelabPass) {
+	checkName(name)
+	codelabPasses[name] = p
+}
+
+// checkName panics if a pass of either kind is registered under name.
+func checkName(name string) {
+	_, exists := passes[name]
+	if _, ok := codelabPasses[name]; ok {
+		exists = true
+	}
+	if exists {
+		panic(fmt.Sprintf("transform pass %q already registered", name))
+	}
+}
+
+// Passes returns a sorted slice of all registered pass names,
+// of either kind.
+func Passes() []string {
+	p := make([]string, 0, len(passes)+len(codelabPasses))
+	for k := range passes {
+		p = append(p, k)
+	}
+	for k := range codelabPasses {
+		p = append(p, k)
+	}
+	sort.Strings(p)
+	return p
+}
+
+// Pipeline is an ordered sequence of passes.
+type Pipeline []Pass
+
+// NewPipeline creates a pipeline of registered passes in the order of names.
+func NewPipeline(names ...string) (Pipeline, error) {
+	var pl Pipeline
+	for _, name := range names {
+		p, ok := passes[name]
+		if !ok {
+			return nil, fmt.Errorf("no transform pass named %q", name)
+		}
+		pl = append(pl, p)
+	}
+	return pl, nil
+}
+
+// Run applies each pass of pl to nn, in order.
+// It stops at the first pass returning an error.
+func (pl Pipeline) Run(nn []nodes.Node) ([]nodes.Node, error) {
+	for _, p := range pl {
+		var err error
+		if nn, err = p(nn); err != nil {
+			return nil, err
+		}
+	}
+	return nn, nil
+}
+
+// RunCodelab applies pl to the content of every step of clab.
+func (pl Pipeline) RunCodelab(clab *types.Codelab) error {
+	if len(pl) == 0 {
+		return nil
+	}
+	for _, st := range clab.Steps {
+		nn, err := pl.Run(st.Content.Nodes)
+		if err != nil {
+			return fmt.Errorf("%s: %v", st.Title, err)
+		}
+		st.Content.Nodes = nn
+	}
+	return nil
+}
+
+// CodelabPipeline is an ordered sequence of codelab passes.
+type CodelabPipeline []CodelabPass
+
+// NewCodelabPipeline creates a pipeline of registered passes of either kind
+// in the order of names. Passes registered with Register are applied
+// to the content of every step of a codelab.
+func NewCodelabPipeline(names ...string) (CodelabPipeline, error) {
+	var pl CodelabPipeline
+	for _, name := range names {
+		if p, ok := codelabPasses[name]; ok {
+			pl = append(pl, p)
+			continue
+		}
+		p, ok := passes[name]
+		if !ok {
+			return nil, fmt.Errorf("no transform pass named %q", name)
+		}
+		pl = append(pl, func(clab *types.Codelab, _ *Options) error {
+			return Pipeline{p}.RunCodelab(clab)
+		})
+	}
+	return pl, nil
+}
+
+// Run applies each pass of pl to clab with opts, in order.
+// It stops at the first pass returning an error.
+func (pl CodelabPipeline) Run(clab *types.Codelab, opts *Options) error {
+	for _, p := range pl {
+		if err := p(clab, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
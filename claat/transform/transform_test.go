@@ -0,0 +1,202 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+func TestRegister(t *testing.T) {
+	Register("test-register", func(nn []nodes.Node) ([]nodes.Node, error) { return nn, nil })
+	defer delete(passes, "test-register")
+
+	if _, err := NewPipeline("test-register", PassStripPrompts); err != nil {
+		t.Errorf("NewPipeline() = %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register() of a duplicate name did not panic")
+		}
+	}()
+	Register("test-register", nil)
+}
+
+func TestNewPipelineUnknown(t *testing.T) {
+	if _, err := NewPipeline("no-such-pass"); err == nil {
+		t.Errorf("NewPipeline(%q) = nil error, want non-nil", "no-such-pass")
+	}
+}
+
+func TestPipelineRun(t *testing.T) {
+	var order []string
+	pass := func(name string) Pass {
+		return func(nn []nodes.Node) ([]nodes.Node, error) {
+			order = append(order, name)
+			return append(nn, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: name})), nil
+		}
+	}
+	pl := Pipeline{pass("a"), pass("b")}
+	nn, err := pl.Run(nil)
+	if err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, order); diff != "" {
+		t.Errorf("Run() order got diff (-want +got): %s", diff)
+	}
+	if len(nn) != 2 {
+		t.Errorf("Run() returned %d nodes, want 2", len(nn))
+	}
+
+	fail := errors.New("fail")
+	pl = Pipeline{func(nn []nodes.Node) ([]nodes.Node, error) { return nil, fail }, pass("c")}
+	if _, err := pl.Run(nil); err != fail {
+		t.Errorf("Run() = %v, want %v", err, fail)
+	}
+	if len(order) != 2 {
+		t.Errorf("Run() continued after an error: %v", order)
+	}
+}
+
+func TestRunCodelab(t *testing.T) {
+	clab := types.NewCodelab()
+	st := clab.NewStep("step")
+	st.Content.Append(nodes.NewHeaderNode(4, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "h"})))
+	if err := (Pipeline{NormalizeHeadings}).RunCodelab(clab); err != nil {
+		t.Fatalf("RunCodelab() = %v", err)
+	}
+	if lvl := st.Content.Nodes[0].(*nodes.HeaderNode).Level; lvl != 2 {
+		t.Errorf("RunCodelab() header level = %d, want 2", lvl)
+	}
+}
+
+func TestDefaultPipeline(t *testing.T) {
+	names := DefaultPipeline(PassNormalizeHeadings)
+	want := []string{
+		PassSnippets, PassVars, PassIcons, PassKbd, PassAnnotations,
+		PassNormalizeHeadings,
+		PassAudience, PassLabSequence, PassXrefs, PassNumberActivities, PassGlossary,
+	}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Errorf("DefaultPipeline() got diff (-want +got): %s", diff)
+	}
+	if _, err := NewCodelabPipeline(names...); err != nil {
+		t.Errorf("NewCodelabPipeline(%v) = %v", names, err)
+	}
+	if _, err := NewCodelabPipeline("no-such-pass"); err == nil {
+		t.Errorf("NewCodelabPipeline(%q) = nil error, want non-nil", "no-such-pass")
+	}
+}
+
+func TestCodelabPipelineRun(t *testing.T) {
+	clab := types.NewCodelab()
+	st := clab.NewStep("step")
+	st.Content.Append(
+		nodes.NewHeaderNode(4, nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "h"})),
+		nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Hello {{name}} :wave:"}),
+	)
+	pl, err := NewCodelabPipeline(DefaultPipeline(PassEmoji, PassNormalizeHeadings)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var warnings []string
+	opts := &Options{
+		Vars: VarsOptions{Vars: map[string]string{"name": "World"}},
+		Warn: func(msg string) { warnings = append(warnings, msg) },
+	}
+	if err := pl.Run(clab, opts); err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+	if diff := cmp.Diff([]string{`step 1 "step": h4 "h" changed to h2`}, warnings); diff != "" {
+		t.Errorf("Run() warnings got diff (-want +got): %s", diff)
+	}
+	if lvl := st.Content.Nodes[0].(*nodes.HeaderNode).Level; lvl != 2 {
+		t.Errorf("Run() header level = %d, want 2", lvl)
+	}
+	if got := nodes.PlainText(st.Content.Nodes[1:]...); got != "Hello World 👋" {
+		t.Errorf("Run() text = %q, want %q", got, "Hello World 👋")
+	}
+}
+
+func TestNormalizeHeadings(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		out  []int
+	}{
+		{
+			name: "Empty",
+		},
+		{
+			name: "NoGaps",
+			in:   []int{2, 3, 3, 2, 3},
+			out:  []int{2, 3, 3, 2, 3},
+		},
+		{
+			name: "FirstTooDeep",
+			in:   []int{3, 4},
+			out:  []int{2, 3},
+		},
+		{
+			name: "Gap",
+			in:   []int{2, 4, 5, 2},
+			out:  []int{2, 3, 4, 2},
+		},
+		{
+			name: "H1",
+			in:   []int{1, 3, 1},
+			out:  []int{2, 3, 2},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var nn []nodes.Node
+			for _, l := range tc.in {
+				nn = append(nn, nodes.NewHeaderNode(l), nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "text"}))
+			}
+			nn, err := NormalizeHeadings(nn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out []int
+			for _, n := range nn {
+				if hn, ok := n.(*nodes.HeaderNode); ok {
+					out = append(out, hn.Level)
+				}
+			}
+			if diff := cmp.Diff(tc.out, out); diff != "" {
+				t.Errorf("NormalizeHeadings(%v) got diff (-want +got): %s", tc.in, diff)
+			}
+		})
+	}
+}
+
+func TestNormalizeHeadingsNested(t *testing.T) {
+	h2 := nodes.NewHeaderNode(2)
+	nested := nodes.NewHeaderNode(5)
+	last := nodes.NewHeaderNode(5)
+	nn := []nodes.Node{h2, nodes.NewInfoboxNode(nodes.InfoboxPositive, nested), last}
+	if _, err := NormalizeHeadings(nn); err != nil {
+		t.Fatal(err)
+	}
+	got := []int{h2.Level, nested.Level, last.Level}
+	if diff := cmp.Diff([]int{2, 3, 4}, got); diff != "" {
+		t.Errorf("NormalizeHeadings() levels got diff (-want +got): %s", diff)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package transform implements codelab content transformations
+// applied between parsing and rendering.
+package transform
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// varRegexp matches a {{name}} variable reference, or a Qwiklabs {{{...}}}
+// runtime expression, with no name, so that it can be skipped over.
+var varRegexp = regexp.MustCompile(`\{\{\{.*?\}\}\}|\{\{\s*([A-Za-z_][\w.-]*)\s*\}\}`)
+
+// VarsOptions configures variable substitution.
+type VarsOptions struct {
+	// Vars are the variable values, keyed by name.
+	Vars map[string]string
+}
+
+// ReadVars reads variable values from a JSON file
+// containing an object of string:string key value pairs.
+func ReadVars(path string) (map[string]string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	vars := map[string]string{}
+	if err := json.Unmarshal(b, &vars); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// SubstituteString replaces {{name}} references in s with their values.
+// References to unknown variables and Qwiklabs {{{...}}} runtime
+// expressions are left as is.
+func SubstituteString(s string, opts VarsOptions) string {
+	if len(opts.Vars) == 0 || !strings.Contains(s, "{{") {
+		return s
+	}
+	return varRegexp.ReplaceAllStringFunc(s, func(m string) string {
+		name := varRegexp.FindStringSubmatch(m)[1]
+		if v, ok := opts.Vars[name]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// SubstituteVars replaces {{name}} references in the values of all text
+// and code nodes of nn, recursively.
+//
+// A reference must be contained in a single text node to be substituted,
+// i.e. it cannot span differently formatted runs of text.
+func SubstituteVars(nn []nodes.Node, opts VarsOptions) {
+	nodes.WalkNodes(nn, func(n nodes.Node, entering bool) (nodes.Node, error) {
+		if !entering {
+			return n, nil
+		}
+		switch n := n.(type) {
+		case *nodes.TextNode:
+			n.Value = SubstituteString(n.Value, opts)
+		case *nodes.CodeNode:
+			n.Value = SubstituteString(n.Value, opts)
+		}
+		return n, nil
+	})
+}
+
+// SubstituteCodelab replaces {{name}} references in codelab title and summary,
+// as well as each step title and content.
+func SubstituteCodelab(clab *types.Codelab, opts VarsOptions) {
+	if len(opts.Vars) == 0 {
+		return
+	}
+	clab.Title = SubstituteString(clab.Title, opts)
+	clab.Summary = SubstituteString(clab.Summary, opts)
+	for _, st := range clab.Steps {
+		st.Title = SubstituteString(st.Title, opts)
+		SubstituteVars(st.Content.Nodes, opts)
+	}
+}
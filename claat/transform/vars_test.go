@@ -0,0 +1,103 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+var testVars = map[string]string{
+	"project_id": "my-project",
+	"region":     "us-central1",
+}
+
+func TestSubstituteString(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{
+			name: "Empty",
+		},
+		{
+			name: "NoVars",
+			in:   "gcloud config list",
+			out:  "gcloud config list",
+		},
+		{
+			name: "Simple",
+			in:   "gcloud config set project {{project_id}}",
+			out:  "gcloud config set project my-project",
+		},
+		{
+			name: "Spaces",
+			in:   "--region {{ region }}",
+			out:  "--region us-central1",
+		},
+		{
+			name: "Unknown",
+			in:   "{{zone}} in {{region}}",
+			out:  "{{zone}} in us-central1",
+		},
+		{
+			name: "Runtime",
+			in:   "{{{project_id}}}",
+			out:  "{{{project_id}}}",
+		},
+		{
+			name: "RuntimeExpression",
+			in:   "{{{project_0.project_id|Project ID}}} in {{region}}",
+			out:  "{{{project_0.project_id|Project ID}}} in us-central1",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := VarsOptions{Vars: testVars}
+			if out := SubstituteString(tc.in, opts); out != tc.out {
+				t.Errorf("SubstituteString(%q, %+v) = %q, want %q", tc.in, opts, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestSubstituteCodelab(t *testing.T) {
+	clab := types.NewCodelab()
+	clab.Title = "Deploy to {{region}}"
+	st := clab.NewStep("Set {{project_id}}")
+	text := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Project {{project_id}}"})
+	code := nodes.NewCodeNode("gcloud config set project {{project_id}}", true, "")
+	item := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "{{region}}"})
+	list := nodes.NewItemsListNode("", 0)
+	list.NewItem(item)
+	st.Content.Append(nodes.NewInfoboxNode(nodes.InfoboxPositive, text), code, list)
+
+	SubstituteCodelab(clab, VarsOptions{Vars: testVars})
+
+	for _, c := range []struct{ got, want string }{
+		{clab.Title, "Deploy to us-central1"},
+		{st.Title, "Set my-project"},
+		{text.Value, "Project my-project"},
+		{code.Value, "gcloud config set project my-project"},
+		{item.Value, "us-central1"},
+	} {
+		if c.got != c.want {
+			t.Errorf("SubstituteCodelab: got %q, want %q", c.got, c.want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+var (
+	// xrefRegexp matches a "see Step 4" cross-reference, with "Step 4"
+	// in group 1 and the step number in group 2.
+	xrefRegexp = regexp.MustCompile(`(?i)\bsee\s+(step\s+(\d+))`)
+	// xrefTitleRegexp matches the separator of a step title following
+	// a cross-reference, as in "see Step 4: Deploy the app".
+	xrefTitleRegexp = regexp.MustCompile(`^:\s*`)
+	// xrefTitleEnd matches the end of an unknown step title in warnings.
+	xrefTitleEnd = regexp.MustCompile(`[.,;()\n]`)
+)
+
+// Xrefs replaces "see Step 4" and "see Step 4: Deploy the app" references
+// in the text of all clab steps, except headings, with cross-reference nodes
+// to the step, spanning the step number and title.
+//
+// References to a step which does not exist, or followed by a title
+// other than the title of the step, are left as text. It returns
+// a warning for each of them.
+func Xrefs(clab *types.Codelab) []string {
+	var ww []string
+	for i, st := range clab.Steps {
+		x := xrefs{steps: clab.Steps}
+		replaceText(st.Content, x.text)
+		for _, w := range x.warnings {
+			ww = append(ww, fmt.Sprintf("%s: %s", st.Location(i+1, w.at), w.msg))
+		}
+	}
+	return ww
+}
+
+// xrefs replaces cross-references in a step, recording the invalid ones.
+type xrefs struct {
+	steps    []*types.Step
+	warnings []xrefWarning
+}
+
+// xrefWarning is an invalid cross-reference in text at.
+type xrefWarning struct {
+	at  nodes.Node
+	msg string
+}
+
+// text splits t around its cross-references.
+func (x *xrefs) text(t *nodes.TextNode) []nodes.Node {
+	v := t.Value
+	mm := xrefRegexp.FindAllStringSubmatchIndex(v, -1)
+	if mm == nil {
+		return []nodes.Node{t}
+	}
+	var res []nodes.Node
+	pos := 0
+	for _, m := range mm {
+		if m[2] < pos {
+			continue
+		}
+		num, _ := strconv.Atoi(v[m[4]:m[5]])
+		end, err := x.target(num, v[m[3]:])
+		if err != nil {
+			x.warnings = append(x.warnings, xrefWarning{at: t, msg: err.Error()})
+			continue
+		}
+		end += m[3]
+		if m[2] > pos {
+			res = append(res, textSpan(t, v[pos:m[2]]))
+		}
+		xn := nodes.NewXrefNode(num, textSpan(t, v[m[2]:end]))
+		xn.MutatePos(t.Pos())
+		res = append(res, xn)
+		pos = end
+	}
+	if pos == 0 {
+		return []nodes.Node{t}
+	}
+	if pos < len(v) {
+		res = append(res, textSpan(t, v[pos:]))
+	}
+	return res
+}
+
+// target validates a reference to step num followed by text rest,
+// and returns the length of the step title at the start of rest, if any.
+func (x *xrefs) target(num int, rest string) (int, error) {
+	if num < 1 || num > len(x.steps) {
+		return 0, fmt.Errorf("see Step %d: no such step, the codelab has %d", num, len(x.steps))
+	}
+	sep := xrefTitleRegexp.FindString(rest)
+	if sep == "" {
+		return 0, nil
+	}
+	after := rest[len(sep):]
+	if hasTitlePrefix(after, x.steps[num-1].Title) {
+		return len(sep) + len(x.steps[num-1].Title), nil
+	}
+	for i, st := range x.steps {
+		if hasTitlePrefix(after, st.Title) {
+			return 0, fmt.Errorf("see Step %d: %s refers to step %d", num, st.Title, i+1)
+		}
+	}
+	title := after
+	if i := xrefTitleEnd.FindStringIndex(title); i != nil {
+		title = title[:i[0]]
+	}
+	return 0, fmt.Errorf("see Step %d: %s does not match step title %q", num, strings.TrimSpace(title), x.steps[num-1].Title)
+}
+
+// hasTitlePrefix reports whether s starts with title, ignoring case,
+// followed by the end of s or a character other than a letter or digit.
+func hasTitlePrefix(s, title string) bool {
+	if title == "" || len(s) < len(title) || !strings.EqualFold(s[:len(title)], title) {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(s[len(title):])
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
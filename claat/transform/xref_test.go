@@ -0,0 +1,136 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
+	"github.com/googlecodelabs/tools/claat/types"
+)
+
+// xrefString describes text and cross-reference nodes of nn, e.g. "see [4:Step 4]".
+func xrefString(nn []nodes.Node) string {
+	var s string
+	for _, n := range nn {
+		switch n := n.(type) {
+		case *nodes.TextNode:
+			s += n.Value
+		case *nodes.XrefNode:
+			s += "[" + strconv.Itoa(n.Step) + ":" + nodes.PlainText(n.Content.Nodes...) + "]"
+		default:
+			s += "<" + nodes.PlainText(n) + ">"
+		}
+	}
+	return s
+}
+
+func TestXrefs(t *testing.T) {
+	tests := []struct {
+		name string
+		in   nodes.Node
+		out  string
+		err  string
+	}{
+		{
+			name: "Number",
+			in:   nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "For details, see Step 2."}),
+			out:  "For details, see [2:Step 2].",
+		},
+		{
+			name: "Title",
+			in:   nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "See step 3: deploy the app, then return."}),
+			out:  "See [3:step 3: deploy the app], then return.",
+		},
+		{
+			name: "Multiple",
+			in:   nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "see Step 1 and see Step 3: Deploy the app"}),
+			out:  "see [1:Step 1] and see [3:Step 3: Deploy the app]",
+		},
+		{
+			name: "Link",
+			in:   nodes.NewURLNode("https://example.com", nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "see Step 2"})),
+			out:  "<see Step 2>",
+		},
+		{
+			name: "Code",
+			in:   nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "see Step 2", Code: true}),
+			out:  "see Step 2",
+		},
+		{
+			name: "NoReference",
+			in:   nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "Step 2 is next."}),
+			out:  "Step 2 is next.",
+		},
+		{
+			name: "NoSuchStep",
+			in:   nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "see Step 4"}),
+			out:  "see Step 4",
+			err:  `step 1 "Overview": see Step 4: no such step, the codelab has 3`,
+		},
+		{
+			name: "WrongNumber",
+			in:   nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "see Step 2: Deploy the app"}),
+			out:  "see Step 2: Deploy the app",
+			err:  `step 1 "Overview": see Step 2: Deploy the app refers to step 3`,
+		},
+		{
+			name: "WrongTitle",
+			in:   nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "see Step 2: Cleanup."}),
+			out:  "see Step 2: Cleanup.",
+			err:  `step 1 "Overview": see Step 2: Cleanup does not match step title "Setup"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clab := types.NewCodelab()
+			clab.Steps = []*types.Step{
+				{Title: "Overview", Content: nodes.NewListNode(nodes.NewListNode(tc.in))},
+				{Title: "Setup", Content: nodes.NewListNode()},
+				{Title: "Deploy the app", Content: nodes.NewListNode()},
+			}
+			ww := Xrefs(clab)
+			var want []string
+			if tc.err != "" {
+				want = []string{tc.err}
+			}
+			if diff := cmp.Diff(want, ww); diff != "" {
+				t.Errorf("Xrefs() warnings got diff (-want +got):\n%s", diff)
+			}
+			para := clab.Steps[0].Content.Nodes[0].(*nodes.ListNode)
+			if diff := cmp.Diff(tc.out, xrefString(para.Nodes)); diff != "" {
+				t.Errorf("Xrefs() got diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestXrefsKeepStyle(t *testing.T) {
+	clab := types.NewCodelab()
+	text := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "see Step 1", Bold: true})
+	clab.Steps = []*types.Step{{Title: "Overview", Content: nodes.NewListNode(text)}}
+	if ww := Xrefs(clab); ww != nil {
+		t.Fatal(ww)
+	}
+	xref, ok := clab.Steps[0].Content.Nodes[1].(*nodes.XrefNode)
+	if !ok {
+		t.Fatalf("got %T; want *nodes.XrefNode", clab.Steps[0].Content.Nodes[1])
+	}
+	if tn := xref.Content.Nodes[0].(*nodes.TextNode); !tn.Bold {
+		t.Errorf("cross-reference text is not bold")
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// DefaultStepEvent is the name of the analytics event sent on step navigation.
+const DefaultStepEvent = "codelab_step"
+
+// Analytics are the analytics snippets injected into HTML output,
+// in place of the legacy global Google Analytics account.
+type Analytics struct {
+	GA4       string   `json:"ga4,omitempty"`       // GA4 measurement ID, e.g. G-XXXXXXXXXX
+	Scripts   []string `json:"scripts,omitempty"`   // URLs of custom analytics scripts
+	StepEvent string   `json:"stepEvent,omitempty"` // Name of the GA4 event sent on step navigation
+}
+
+// AnalyticsProfiles are analytics configurations by profile name,
+// such as one per site the codelabs are published on.
+type AnalyticsProfiles map[string]*Analytics
+
+// MeasurementID returns the GA4 measurement ID of a, if any.
+func (a *Analytics) MeasurementID() string {
+	if a == nil {
+		return ""
+	}
+	return a.GA4
+}
+
+// Validate reports the first invalid field of a, if any.
+func (a *Analytics) Validate() error {
+	if a.GA4 != "" && !ga4Regexp.MatchString(a.GA4) {
+		return fmt.Errorf("%q is not a G-XXXXXXXXXX measurement ID", a.GA4)
+	}
+	for _, s := range a.Scripts {
+		if u, err := url.Parse(s); err != nil || u.Scheme != "https" {
+			return fmt.Errorf("script %q is not an https URL", s)
+		}
+	}
+	return nil
+}
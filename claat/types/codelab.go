@@ -16,6 +16,7 @@
 package types
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/googlecodelabs/tools/claat/nodes"
@@ -29,6 +30,8 @@ type Meta struct {
 	Authors    string            `json:"authors,omitempty"`  // Arbitrary authorship text
 	Summary    string            `json:"summary"`            // Short summary
 	Source     string            `json:"source"`             // Codelab source doc
+	Revision   string            `json:"-"`                  // Source revision, stored in Context.Provenance
+	Inputs     map[string]string `json:"-"`                  // Revisions of the other sources read, stored in Context.Provenance
 	Theme      string            `json:"theme"`              // Usually first item of Categories
 	Status     *LegacyStatus     `json:"status"`             // Draft, Published, Hidden, etc.
 	Categories []string          `json:"category"`           // Categories from the meta table
@@ -37,6 +40,29 @@ type Meta struct {
 	GA         string            `json:"ga,omitempty"`       // Codelab-specific GA tracking ID
 	GA4        string            `json:"ga4,omitempty"`      // Codelab-specific GA4 tracking ID
 	Extra      map[string]string `json:"extra,omitempty"`    // Extra metadata specified in pass_metadata
+	Glossary   Glossary          `json:"glossary,omitempty"` // Definitions of terms used in the codelab
+	Locale     string            `json:"locale,omitempty"`   // Content language, e.g. "es"
+	Image      string            `json:"image,omitempty"`    // Social card image URL
+
+	// DurationDeclared is whether Duration was declared in metadata,
+	// rather than summed from the step durations, which may change.
+	DurationDeclared bool `json:"-"`
+
+	// Prerequisites are the IDs or URLs of labs to complete before this one.
+	Prerequisites []string `json:"prerequisites,omitempty"`
+	// Next are the IDs or URLs of labs recommended after this one.
+	Next []string `json:"next,omitempty"`
+
+	// Translations are the sources of the codelab in each locale, including its own,
+	// if it is part of a set of translations.
+	Translations Translations `json:"translations,omitempty"`
+
+	// Quizzes are the graded surveys of the codelab, set at export.
+	Quizzes []*Quiz `json:"quizzes,omitempty"`
+
+	// StepInfo is the metadata of each step, set at export, so that
+	// platforms can skip or gate steps.
+	StepInfo []*StepInfo `json:"steps,omitempty"`
 
 	URL string `json:"url"` // Legacy ID; TODO: remove
 }
@@ -66,5 +92,43 @@ type Step struct {
 	Title    string          // Step title
 	Tags     []string        // Step environments
 	Duration time.Duration   // Duration
+	Optional bool            // Learners may skip the step
+	Products []string        // Products the step requires, e.g. "Cloud Storage"
 	Content  *nodes.ListNode // Root node of the step nodes tree
 }
+
+// Location returns where node n of s, the num'th step of a codelab, is
+// for warnings and errors: step 2 "Setup", followed by the source position
+// of n when it is known, as in step 2 "Setup", line 14.
+func (s *Step) Location(num int, n nodes.Node) string {
+	loc := fmt.Sprintf("step %d %q", num, s.Title)
+	if n != nil && n.Pos().IsValid() {
+		loc += ", " + n.Pos().String()
+	}
+	return loc
+}
+
+// StepInfo is the metadata of a codelab step, as listed in codelab.json.
+type StepInfo struct {
+	Title        string   `json:"title"`
+	Duration     int      `json:"duration"`               // Duration in minutes
+	Environments []string `json:"environments,omitempty"` // Step environments; all if empty
+	Optional     bool     `json:"optional,omitempty"`
+	Products     []string `json:"products,omitempty"`
+	File         string   `json:"file,omitempty"` // File of the step in split output
+}
+
+// StepsInfo returns the metadata of the steps of c.
+func (c *Codelab) StepsInfo() []*StepInfo {
+	res := make([]*StepInfo, len(c.Steps))
+	for i, st := range c.Steps {
+		res[i] = &StepInfo{
+			Title:        st.Title,
+			Duration:     int(st.Duration.Minutes()),
+			Environments: st.Tags,
+			Optional:     st.Optional,
+			Products:     st.Products,
+		}
+	}
+	return res
+}
@@ -2,6 +2,10 @@ package types
 
 import (
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googlecodelabs/tools/claat/nodes"
 )
 
 func TestNewCodelab(t *testing.T) {
@@ -26,3 +30,42 @@ func TestNewStep(t *testing.T) {
 		t.Errorf(`Codelab.NewStep("foobar") did not initialize s.Content`)
 	}
 }
+
+func TestStepLocation(t *testing.T) {
+	s := &Step{Title: "Setup"}
+	text := func(p nodes.Pos) nodes.Node {
+		n := nodes.NewTextNode(nodes.NewTextNodeOptions{Value: "x"})
+		n.MutatePos(p)
+		return n
+	}
+	tests := []struct {
+		n    nodes.Node
+		want string
+	}{
+		{nil, `step 2 "Setup"`},
+		{text(nodes.Pos{}), `step 2 "Setup"`},
+		{text(nodes.Pos{Line: 14}), `step 2 "Setup", line 14`},
+		{text(nodes.Pos{Paragraph: 3}), `step 2 "Setup", paragraph 3`},
+	}
+	for _, tc := range tests {
+		if got := s.Location(2, tc.n); got != tc.want {
+			t.Errorf("Location(2, %v) = %q; want %q", tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestStepsInfo(t *testing.T) {
+	c := NewCodelab()
+	c.NewStep("Setup").Duration = 2 * time.Minute
+	s := c.NewStep("Cleanup")
+	s.Tags = []string{"web"}
+	s.Optional = true
+	s.Products = []string{"Cloud Storage"}
+	want := []*StepInfo{
+		{Title: "Setup", Duration: 2},
+		{Title: "Cleanup", Environments: []string{"web"}, Optional: true, Products: []string{"Cloud Storage"}},
+	}
+	if diff := cmp.Diff(want, c.StepsInfo()); diff != "" {
+		t.Errorf("StepsInfo() got diff (-want +got): %s", diff)
+	}
+}
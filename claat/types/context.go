@@ -13,6 +13,55 @@ type Context struct {
 	Prefix  string       `json:"prefix,omitempty"`  // Assets URL prefix for HTML-based formats
 	MainGA  string       `json:"mainga,omitempty"`  // Global Google Analytics ID
 	Updated *ContextTime `json:"updated,omitempty"` // Last update timestamp
+	TOC     bool         `json:"toc,omitempty"`     // Table of contents in Markdown output
+	Split   bool         `json:"split,omitempty"`   // Each step in its own file, see StepInfo.File
+	PageURL string       `json:"pageurl,omitempty"` // Published URL of the codelab page, if known
+	// Lightbox is the minimum width of images zooming in on click
+	// in HTML output, or 0 to disable it.
+	Lightbox int `json:"lightbox,omitempty"`
+	// HTMLTheme is the name of a bundled theme, or the directory of a theme,
+	// branding HTML output, if any. It is unrelated to the Theme of Meta,
+	// named after its first category.
+	HTMLTheme string `json:"html_theme,omitempty"`
+	// Paper is the paper size of printed HTML output, such as a4, if any.
+	Paper string `json:"paper,omitempty"`
+	// PWA makes HTML output an installable progressive web app working offline.
+	PWA bool `json:"pwa,omitempty"`
+	// CloudShell is how terminal code blocks of HTML output open Cloud Shell,
+	// link or embed, if they do.
+	CloudShell string `json:"cloudshell,omitempty"`
+	// Audience is the audience of the export, instructor or student, if any.
+	Audience string `json:"audience,omitempty"`
+	// Analytics are the analytics snippets of HTML output, replacing MainGA.
+	Analytics *Analytics `json:"analytics,omitempty"`
+	// Locales are the directories of the codelab in each locale of its translations,
+	// relative to its own, for a language switch.
+	Locales map[string]string `json:"locales,omitempty"`
+	// Provenance traces the codelab back to its source revision and export.
+	Provenance *Provenance `json:"provenance,omitempty"`
+	// KeepRuntimeVars leaves Qwiklabs {{{...}}} runtime expressions unescaped
+	// in Markdown output. Like the -vars values, it is set by each export
+	// or update rather than stored.
+	KeepRuntimeVars bool `json:"-"`
+	// IconImages is the URL of the images of icons in Markdown output,
+	// with {name} replaced by the icon name, if any. It is set by each
+	// export or update.
+	IconImages string `json:"-"`
+	// SanitizePolicy is a JSON file of HTML elements, attributes and
+	// URL schemes to allow in rendered content, if any. It is set by each
+	// export or update.
+	SanitizePolicy string `json:"-"`
+}
+
+// Provenance identifies the exact source revision an exported codelab
+// was rendered from, and the export which rendered it.
+type Provenance struct {
+	Source   string            `json:"source"`             // Codelab source, e.g. a doc ID or URL
+	Revision string            `json:"revision,omitempty"` // Source revision, if known
+	Inputs   map[string]string `json:"inputs,omitempty"`   // Revisions of other sources read, e.g. parts, imports and images
+	Version  string            `json:"claat_version"`      // Version of claat which exported it
+	Profile  string            `json:"profile"`            // Render options, as key=value pairs
+	Exported ContextTime       `json:"exported"`           // Export timestamp
 }
 
 // ContextMeta is a composition of export context and meta data.
@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Course is a sequence of codelabs taken in order, such as a learning
+// track. It is declared in a YAML course file, such as:
+//
+//	id: cloud-run-track
+//	title: Cloud Run from zero to production
+//	summary: Deploy, secure and scale a service on Cloud Run.
+//	labs:
+//	  - source: intro.md
+//	  - source: 1AbC...docID
+//	    prerequisites: [cloud-run-intro]
+type Course struct {
+	ID      string       `yaml:"id" json:"id"`                     // Also the course page directory
+	Title   string       `yaml:"title" json:"title"`               // Course title
+	Summary string       `yaml:"summary" json:"summary,omitempty"` // Short summary
+	Labs    []*CourseLab `yaml:"labs" json:"labs"`                 // Labs of the course, in order
+
+	// Duration is the sum of the durations of the labs in minutes,
+	// set at export.
+	Duration int `yaml:"-" json:"duration"`
+}
+
+// CourseLab is a codelab of a Course.
+type CourseLab struct {
+	// Source is the codelab source. Local files and URLs are relative
+	// to the course file.
+	Source string `yaml:"source" json:"source"`
+	// Prerequisites are the IDs of earlier labs of the course, or URLs of
+	// other labs, to complete before this one, in addition to those
+	// of the codelab metadata.
+	Prerequisites []string `yaml:"prerequisites" json:"prerequisites,omitempty"`
+
+	// ID, Title and Duration are those of the codelab, set at export.
+	ID       string `yaml:"-" json:"id"`
+	Title    string `yaml:"-" json:"title"`
+	Duration int    `yaml:"-" json:"duration"`
+}
+
+// ReadCourse decodes the Course in r.
+func ReadCourse(r io.Reader) (*Course, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	c := &Course{}
+	if err := yaml.UnmarshalStrict(b, c); err != nil {
+		return nil, fmt.Errorf("course: %v", err)
+	}
+	if c.ID == "" {
+		return nil, fmt.Errorf("course: no id")
+	}
+	if !idRegexp.MatchString(c.ID) {
+		return nil, fmt.Errorf("course: id %q must only contain lowercase letters, digits, dashes and underscores", c.ID)
+	}
+	if len(c.Labs) == 0 {
+		return nil, fmt.Errorf("course: no labs")
+	}
+	for i, l := range c.Labs {
+		if l == nil || l.Source == "" {
+			return nil, fmt.Errorf("course: lab %d has no source", i+1)
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadCourse(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want *Course
+		err  string
+	}{
+		{
+			name: "course",
+			in: `id: track
+title: Track
+summary: All of it.
+labs:
+  - source: intro.md
+  - source: https://example.com/deploy.md
+    prerequisites: [intro]
+`,
+			want: &Course{
+				ID:      "track",
+				Title:   "Track",
+				Summary: "All of it.",
+				Labs: []*CourseLab{
+					{Source: "intro.md"},
+					{Source: "https://example.com/deploy.md", Prerequisites: []string{"intro"}},
+				},
+			},
+		},
+		{
+			name: "no id",
+			in:   "labs:\n  - source: intro.md\n",
+			err:  "course: no id",
+		},
+		{
+			name: "path id",
+			in:   "id: a/b\nlabs:\n  - source: intro.md\n",
+			err:  `course: id "a/b" must only contain`,
+		},
+		{
+			name: "parent id",
+			in:   "id: ..\nlabs:\n  - source: intro.md\n",
+			err:  `course: id ".." must only contain`,
+		},
+		{
+			name: "uppercase id",
+			in:   "id: Track\nlabs:\n  - source: intro.md\n",
+			err:  `course: id "Track" must only contain`,
+		},
+		{
+			name: "no labs",
+			in:   "id: track\n",
+			err:  "course: no labs",
+		},
+		{
+			name: "no source",
+			in:   "id: track\nlabs:\n  - source: intro.md\n  - prerequisites: [intro]\n",
+			err:  "course: lab 2 has no source",
+		},
+		{
+			name: "unknown field",
+			in:   "id: track\nlab:\n  - source: intro.md\n",
+			err:  "field lab not found",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ReadCourse(strings.NewReader(tc.in))
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("ReadCourse() error = %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ReadCourse() diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
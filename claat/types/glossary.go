@@ -0,0 +1,64 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"sort"
+	"strings"
+)
+
+// Glossary maps terms to their definitions.
+type Glossary map[string]string
+
+// ParseGlossary parses the value of a glossary metadata field,
+// a semicolon separated list of "term: definition" entries,
+// such as "VPC: Virtual Private Cloud; IAM: Identity and Access Management".
+// Malformed entries are ignored.
+func ParseGlossary(s string) Glossary {
+	g := Glossary{}
+	for _, e := range strings.Split(s, ";") {
+		kv := strings.SplitN(e, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		term, def := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if term == "" || def == "" {
+			continue
+		}
+		g[term] = def
+	}
+	return g
+}
+
+// Terms returns the terms of g in alphabetical order, ignoring case.
+func (g Glossary) Terms() []string {
+	terms := make([]string, 0, len(g))
+	for t := range g {
+		terms = append(terms, t)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		return strings.ToLower(terms[i]) < strings.ToLower(terms[j])
+	})
+	return terms
+}
+
+// String returns g in the metadata field format of ParseGlossary.
+func (g Glossary) String() string {
+	var a []string
+	for _, t := range g.Terms() {
+		a = append(a, t+": "+g[t])
+	}
+	return strings.Join(a, "; ")
+}
@@ -0,0 +1,24 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseGlossary(t *testing.T) {
+	in := "VPC: Virtual Private Cloud;  IAM : Identity and Access Management; bad; empty: ; URL: see http://example.com"
+	want := Glossary{
+		"VPC": "Virtual Private Cloud",
+		"IAM": "Identity and Access Management",
+		"URL": "see http://example.com",
+	}
+	g := ParseGlossary(in)
+	if diff := cmp.Diff(want, g); diff != "" {
+		t.Errorf("ParseGlossary(%q) got diff (-want +got):\n%s", in, diff)
+	}
+	s := "IAM: Identity and Access Management; URL: see http://example.com; VPC: Virtual Private Cloud"
+	if got := g.String(); got != s {
+		t.Errorf("String() = %q; want %q", got, s)
+	}
+}
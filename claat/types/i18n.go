@@ -0,0 +1,133 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is the locale of a codelab which does not declare one.
+const DefaultLocale = "en"
+
+// rtlLanguages are the languages written from right to left,
+// by ISO 639 code, including the deprecated "iw" for Hebrew.
+var rtlLanguages = map[string]bool{
+	"ar": true, // Arabic
+	"dv": true, // Divehi
+	"fa": true, // Persian
+	"he": true, // Hebrew
+	"iw": true, // Hebrew
+	"ku": true, // Kurdish (Sorani)
+	"ps": true, // Pashto
+	"sd": true, // Sindhi
+	"ug": true, // Uyghur
+	"ur": true, // Urdu
+	"yi": true, // Yiddish
+}
+
+// IsRTL reports whether locale, such as "ar" or "fa-IR", is the locale
+// of a language written from right to left.
+func IsRTL(locale string) bool {
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return rtlLanguages[lang]
+}
+
+// Translations maps locales to the sources of a codelab in each locale.
+type Translations map[string]string
+
+// ParseTranslations parses the value of a translations metadata field,
+// a comma separated list of "locale=source" entries,
+// such as "es=codelab.es.md, ja=codelab.ja.md".
+// Malformed entries are ignored.
+func ParseTranslations(s string) Translations {
+	t := Translations{}
+	for _, e := range strings.Split(s, ",") {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		loc, src := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if loc == "" || src == "" {
+			continue
+		}
+		t[loc] = src
+	}
+	return t
+}
+
+// Locales returns the locales of t in alphabetical order.
+func (t Translations) Locales() []string {
+	locs := make([]string, 0, len(t))
+	for l := range t {
+		locs = append(locs, l)
+	}
+	sort.Strings(locs)
+	return locs
+}
+
+// String returns t in the metadata field format of ParseTranslations.
+func (t Translations) String() string {
+	var a []string
+	for _, l := range t.Locales() {
+		a = append(a, l+"="+t[l])
+	}
+	return strings.Join(a, ", ")
+}
+
+// LocaleOrDefault returns the locale of m, or DefaultLocale if not set.
+func (m *Meta) LocaleOrDefault() string {
+	if m.Locale == "" {
+		return DefaultLocale
+	}
+	return m.Locale
+}
+
+// TextDirection returns the direction of the codelab text, "rtl" if its locale
+// is written from right to left and "ltr" otherwise, as the value
+// of an HTML dir attribute.
+func (m *Meta) TextDirection() string {
+	if IsRTL(m.Locale) {
+		return "rtl"
+	}
+	return "ltr"
+}
+
+// Dir returns the slash-separated output directory of the codelab,
+// relative to the export output directory: its ID, prefixed
+// with its locale if it is part of a set of translations.
+func (m *Meta) Dir() string {
+	if len(m.Translations) == 0 {
+		return m.ID
+	}
+	return path.Join(m.LocaleOrDefault(), m.ID)
+}
+
+// LocaleDirs returns the output directories of the codelab in each locale
+// of its translations, relative to its own output directory.
+func (m *Meta) LocaleDirs() map[string]string {
+	if len(m.Translations) == 0 {
+		return nil
+	}
+	dirs := make(map[string]string, len(m.Translations))
+	for l := range m.Translations {
+		dirs[l] = path.Join("..", "..", l, m.ID)
+	}
+	return dirs
+}
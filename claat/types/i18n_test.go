@@ -0,0 +1,72 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseTranslations(t *testing.T) {
+	in := "es=codelab.es.md, ja = https://example.com/ja.md,bad, pt="
+	want := Translations{
+		"es": "codelab.es.md",
+		"ja": "https://example.com/ja.md",
+	}
+	tr := ParseTranslations(in)
+	if diff := cmp.Diff(want, tr); diff != "" {
+		t.Errorf("ParseTranslations(%q) got diff (-want +got):\n%s", in, diff)
+	}
+	if got, want := tr.String(), "es=codelab.es.md, ja=https://example.com/ja.md"; got != want {
+		t.Errorf("String() = %q; want %q", got, want)
+	}
+}
+
+func TestMetaDir(t *testing.T) {
+	m := &Meta{ID: "intro"}
+	if got := m.Dir(); got != "intro" {
+		t.Errorf("Dir() = %q; want intro", got)
+	}
+	if m.LocaleDirs() != nil {
+		t.Errorf("LocaleDirs() = %v; want nil", m.LocaleDirs())
+	}
+	m.Translations = Translations{"en": "intro.md", "es": "intro.es.md"}
+	if got := m.Dir(); got != "en/intro" {
+		t.Errorf("Dir() = %q; want en/intro", got)
+	}
+	m.Locale = "es"
+	if got := m.Dir(); got != "es/intro" {
+		t.Errorf("Dir() = %q; want es/intro", got)
+	}
+	want := map[string]string{"en": "../../en/intro", "es": "../../es/intro"}
+	if diff := cmp.Diff(want, m.LocaleDirs()); diff != "" {
+		t.Errorf("LocaleDirs() got diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestIsRTL(t *testing.T) {
+	tests := []struct {
+		locale string
+		rtl    bool
+	}{
+		{"", false},
+		{"en", false},
+		{"ar", true},
+		{"fa-IR", true},
+		{"he_IL", true},
+		{"HE", true},
+		{"fr-CA", false},
+	}
+	for _, tc := range tests {
+		if got := IsRTL(tc.locale); got != tc.rtl {
+			t.Errorf("IsRTL(%q) = %v; want %v", tc.locale, got, tc.rtl)
+		}
+		want := "ltr"
+		if tc.rtl {
+			want = "rtl"
+		}
+		m := &Meta{Locale: tc.locale}
+		if got := m.TextDirection(); got != want {
+			t.Errorf("TextDirection() of locale %q = %q; want %q", tc.locale, got, want)
+		}
+	}
+}
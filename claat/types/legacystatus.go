@@ -39,6 +39,20 @@ func (s *LegacyStatus) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// Has reports whether s contains status v, regardless of case.
+// A nil status contains none.
+func (s *LegacyStatus) Has(v string) bool {
+	if s == nil {
+		return false
+	}
+	for _, e := range *s {
+		if strings.EqualFold(strings.TrimSpace(e), v) {
+			return true
+		}
+	}
+	return false
+}
+
 // String turns a status into a string
 func (s LegacyStatus) String() string {
 	ss := []string(s)
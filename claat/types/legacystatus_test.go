@@ -99,3 +99,19 @@ func TestString(t *testing.T) {
 		})
 	}
 }
+
+func TestLegacyStatusHas(t *testing.T) {
+	s := &LegacyStatus{"Draft", " review"}
+	for _, v := range []string{"draft", "review", "REVIEW"} {
+		if !s.Has(v) {
+			t.Errorf("%v.Has(%q) = false; want true", s, v)
+		}
+	}
+	if s.Has("published") {
+		t.Errorf("%v.Has(%q) = true; want false", s, "published")
+	}
+	var none *LegacyStatus
+	if none.Has("draft") {
+		t.Errorf("nil.Has(%q) = true; want false", "draft")
+	}
+}
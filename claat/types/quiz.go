@@ -0,0 +1,34 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// Quiz is the scoring metadata of a graded survey of a codelab,
+// for the codelab platform to grade answers.
+type Quiz struct {
+	ID        string          `json:"id"`             // Survey ID
+	Step      int             `json:"step"`           // Number of the step with the quiz, from 1
+	Pass      int             `json:"pass,omitempty"` // Minimum score to pass, in points
+	Points    int             `json:"points"`         // Maximum score, in points
+	Questions []*QuizQuestion `json:"questions"`
+}
+
+// QuizQuestion is a graded question of a quiz.
+type QuizQuestion struct {
+	Name    string   `json:"name"`
+	Kind    string   `json:"kind,omitempty"` // "multi" or "rating"; single choice if empty
+	Points  int      `json:"points"`         // Score of a correct answer
+	Options []string `json:"options,omitempty"`
+	Correct []string `json:"correct"` // Correct options
+}
@@ -0,0 +1,35 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// Resources is the manifest of the external resources a codelab depends on,
+// for lab environments to be provisioned with them.
+type Resources struct {
+	ID         string      `json:"id"`                   // Codelab ID
+	GCSObjects []*Resource `json:"gcsObjects,omitempty"` // gs://bucket/object URLs
+	Repos      []*Resource `json:"repos,omitempty"`      // Repositories, such as github.com/owner/repo
+	APIs       []*Resource `json:"apis,omitempty"`       // Google APIs, such as compute.googleapis.com
+}
+
+// Resource is an external resource and the steps referencing it.
+type Resource struct {
+	Name  string `json:"name"`
+	Steps []int  `json:"steps"` // Step numbers, from 1
+}
+
+// Empty reports whether r lists no resources.
+func (r *Resources) Empty() bool {
+	return len(r.GCSObjects) == 0 && len(r.Repos) == 0 && len(r.APIs) == 0
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Taxonomy lists the allowed values of codelab categories and tags,
+// so that catalog filters are not broken by typos and synonyms.
+// All values are normalized to lowercase, like those of codelab metadata.
+type Taxonomy struct {
+	// Categories are the allowed categories; any category if empty.
+	Categories []string `json:"categories,omitempty"`
+	// Tags are the allowed tags; any tag if empty.
+	Tags []string `json:"tags,omitempty"`
+	// Aliases map free-form values to their canonical category or tag,
+	// such as "k8s" to "kubernetes".
+	Aliases map[string]string `json:"aliases,omitempty"`
+}
+
+// Normalize lowercases and trims the values of t, and checks that its
+// aliases map to an allowed value.
+func (t *Taxonomy) Normalize() error {
+	norm := func(a []string) {
+		for i, v := range a {
+			a[i] = strings.ToLower(strings.TrimSpace(v))
+		}
+	}
+	norm(t.Categories)
+	norm(t.Tags)
+	aliases := make(map[string]string, len(t.Aliases))
+	for k, v := range t.Aliases {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if !contains(t.Categories, v) && !contains(t.Tags, v) {
+			return fmt.Errorf("alias %q: %q is neither an allowed category nor tag", k, v)
+		}
+		aliases[strings.ToLower(strings.TrimSpace(k))] = v
+	}
+	t.Aliases = aliases
+	return nil
+}
+
+// Check returns MetaErrors listing the categories and tags of m
+// which t does not allow, along with suggestions of canonical values,
+// or nil if all are allowed.
+func (t *Taxonomy) Check(m *Meta) error {
+	var ee MetaErrors
+	check := func(field string, allowed, values []string) {
+		if len(allowed) == 0 {
+			return
+		}
+		for _, v := range values {
+			if contains(allowed, strings.ToLower(v)) {
+				continue
+			}
+			msg := fmt.Sprintf("%q is not in the taxonomy", v)
+			if s := t.suggest(allowed, v); s != "" {
+				msg += fmt.Sprintf("; did you mean %q?", s)
+			}
+			ee = append(ee, &MetaError{Field: field, Msg: msg})
+		}
+	}
+	check("categories", t.Categories, m.Categories)
+	check("tags", t.Tags, m.Tags)
+	if len(ee) > 0 {
+		return ee
+	}
+	return nil
+}
+
+// Suggest replaces the categories and tags of m which t does not allow
+// with their canonical values, if any, and returns a description of each
+// replacement. Values without a suggestion are left as is.
+func (t *Taxonomy) Suggest(m *Meta) []string {
+	var res []string
+	suggest := func(field string, allowed, values []string) []string {
+		if len(allowed) == 0 {
+			return values
+		}
+		var out []string
+		for _, v := range values {
+			if s := t.suggest(allowed, v); !contains(allowed, strings.ToLower(v)) && s != "" {
+				res = append(res, fmt.Sprintf("%s: %q replaced with %q", field, v, s))
+				v = s
+			}
+			if !contains(out, v) {
+				out = append(out, v)
+			}
+		}
+		return out
+	}
+	m.Categories = suggest("categories", t.Categories, m.Categories)
+	m.Tags = suggest("tags", t.Tags, m.Tags)
+	return res
+}
+
+// suggest returns the canonical value of v among allowed: its alias,
+// or the closest allowed value within a few typos, or an empty string.
+// A typo is allowed for every 4 runes of v, up to 3, so that short values
+// such as "ar" are not mistaken for others such as "vr".
+func (t *Taxonomy) suggest(allowed []string, v string) string {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if s, ok := t.Aliases[v]; ok && contains(allowed, s) {
+		return s
+	}
+	typos := utf8.RuneCountInString(v) / 4
+	if typos > 3 {
+		typos = 3
+	}
+	best, dist := "", typos+1
+	for _, a := range allowed {
+		if d := editDistance(v, a); d < dist {
+			best, dist = a, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b, in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+func contains(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testTaxonomy(t *testing.T) *Taxonomy {
+	tax := &Taxonomy{
+		Categories: []string{"Cloud", "web "},
+		Tags:       []string{"kubernetes", "serverless"},
+		Aliases:    map[string]string{"K8s": "Kubernetes"},
+	}
+	if err := tax.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	return tax
+}
+
+func TestTaxonomyNormalize(t *testing.T) {
+	tax := testTaxonomy(t)
+	want := &Taxonomy{
+		Categories: []string{"cloud", "web"},
+		Tags:       []string{"kubernetes", "serverless"},
+		Aliases:    map[string]string{"k8s": "kubernetes"},
+	}
+	if diff := cmp.Diff(want, tax); diff != "" {
+		t.Errorf("Normalize mismatch (-want +got):\n%s", diff)
+	}
+
+	bad := &Taxonomy{Tags: []string{"go"}, Aliases: map[string]string{"golang": "gopher"}}
+	if err := bad.Normalize(); err == nil {
+		t.Error("Normalize of an alias to an unknown value: no error")
+	}
+}
+
+func TestTaxonomyCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories []string
+		tags       []string
+		want       MetaErrors
+	}{
+		{"allowed", []string{"cloud"}, []string{"kubernetes"}, nil},
+		{"typo", []string{"cloud"}, []string{"kuberenetes"}, MetaErrors{
+			{Field: "tags", Msg: `"kuberenetes" is not in the taxonomy; did you mean "kubernetes"?`},
+		}},
+		{"alias", []string{"cloud"}, []string{"k8s"}, MetaErrors{
+			{Field: "tags", Msg: `"k8s" is not in the taxonomy; did you mean "kubernetes"?`},
+		}},
+		{"unknown", []string{"mobile"}, []string{"serverless"}, MetaErrors{
+			{Field: "categories", Msg: `"mobile" is not in the taxonomy`},
+		}},
+	}
+	tax := testTaxonomy(t)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tax.Check(&Meta{Categories: tc.categories, Tags: tc.tags})
+			var got MetaErrors
+			if err != nil {
+				got = err.(MetaErrors)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Check mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTaxonomySuggest(t *testing.T) {
+	tax := testTaxonomy(t)
+	m := &Meta{Categories: []string{"clud", "mobile"}, Tags: []string{"k8s", "kuberenetes", "serverless"}}
+	got := tax.Suggest(m)
+	want := []string{
+		`categories: "clud" replaced with "cloud"`,
+		`tags: "k8s" replaced with "kubernetes"`,
+		`tags: "kuberenetes" replaced with "kubernetes"`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Suggest mismatch (-want +got):\n%s", diff)
+	}
+	wantMeta := &Meta{Categories: []string{"cloud", "mobile"}, Tags: []string{"kubernetes", "serverless"}}
+	if diff := cmp.Diff(wantMeta, m); diff != "" {
+		t.Errorf("Suggest meta mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTaxonomySuggestShort(t *testing.T) {
+	tax := &Taxonomy{Tags: []string{"vr", "go", "ios"}}
+	if err := tax.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	m := &Meta{Tags: []string{"ar", "og", "iOS", "ipad"}}
+	if got := tax.Suggest(m); len(got) != 0 {
+		t.Errorf("Suggest() = %q; want no replacement of short tags", got)
+	}
+	if diff := cmp.Diff([]string{"ar", "og", "iOS", "ipad"}, m.Tags); diff != "" {
+		t.Errorf("Suggest tags mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kuberenetes", "kubernetes", 1},
+		{"kitten", "sitting", 3},
+	}
+	for _, tc := range tests {
+		if got := editDistance(tc.a, tc.b); got != tc.want {
+			t.Errorf("editDistance(%q, %q) = %d; want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
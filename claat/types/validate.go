@@ -0,0 +1,129 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Statuses are the known codelab status values.
+var Statuses = []string{"draft", "review", "published", "hidden", "deprecated"}
+
+var (
+	// idRegexp matches a valid codelab ID, which is part of codelab URL.
+	idRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	// gaRegexp matches a Universal Analytics tracking ID.
+	gaRegexp = regexp.MustCompile(`^UA-\d+-\d+$`)
+	// ga4Regexp matches a GA4 measurement ID.
+	ga4Regexp = regexp.MustCompile(`^G-[A-Z0-9]+$`)
+)
+
+// MetaError is a single invalid metadata field.
+type MetaError struct {
+	Field string // Metadata field name, as in the source meta table
+	Msg   string // What is wrong with the field value
+}
+
+func (e *MetaError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Msg)
+}
+
+// MetaErrors is a list of metadata validation errors.
+type MetaErrors []*MetaError
+
+func (ee MetaErrors) Error() string {
+	s := make([]string, len(ee))
+	for i, e := range ee {
+		s[i] = e.Error()
+	}
+	return "invalid metadata: " + strings.Join(s, "; ")
+}
+
+// Validate checks m against the codelab metadata schema:
+//
+//   - id is required, lowercase letters, digits, dashes and underscores
+//   - title, summary and at least one category are required
+//   - duration must be positive, i.e. steps must have a duration
+//   - status values must be one of Statuses
+//   - feedback link must be an absolute http or https URL
+//   - analytics accounts must be UA-NNN-N and G-XXX IDs, respectively
+//
+// It returns MetaErrors listing all invalid fields, or nil if m is valid.
+func (m *Meta) Validate() error {
+	var ee MetaErrors
+	add := func(field, format string, args ...interface{}) {
+		ee = append(ee, &MetaError{Field: field, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	switch {
+	case m.ID == "":
+		add("id", "required")
+	case !idRegexp.MatchString(m.ID):
+		add("id", "%q must only contain lowercase letters, digits, dashes and underscores", m.ID)
+	}
+	if strings.TrimSpace(m.Title) == "" {
+		add("title", "required")
+	}
+	if strings.TrimSpace(m.Summary) == "" {
+		add("summary", "required")
+	}
+	if len(m.Categories) == 0 {
+		add("categories", "at least one category is required")
+	}
+	for _, c := range m.Categories {
+		if strings.TrimSpace(c) == "" {
+			add("categories", "empty category")
+		}
+	}
+	if m.Duration <= 0 {
+		add("duration", "must be positive; set step durations")
+	}
+	if m.Status != nil {
+		for _, s := range *m.Status {
+			if !knownStatus(s) {
+				add("status", "unknown status %q; want one of %s", s, strings.Join(Statuses, ", "))
+			}
+		}
+	}
+	if m.Feedback != "" {
+		u, err := url.Parse(m.Feedback)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("feedback_link", "%q is not an absolute http(s) URL", m.Feedback)
+		}
+	}
+	if m.GA != "" && !gaRegexp.MatchString(m.GA) {
+		add("analytics_account", "%q is not a UA-NNNNNN-N tracking ID", m.GA)
+	}
+	if m.GA4 != "" && !ga4Regexp.MatchString(m.GA4) {
+		add("analytics_ga4_account", "%q is not a G-XXXXXXXXXX measurement ID", m.GA4)
+	}
+
+	if len(ee) > 0 {
+		return ee
+	}
+	return nil
+}
+
+func knownStatus(s string) bool {
+	for _, v := range Statuses {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+	return false
+}
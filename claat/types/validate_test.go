@@ -0,0 +1,85 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func validMeta() *Meta {
+	status := LegacyStatus{"published"}
+	return &Meta{
+		ID:         "my-codelab_1",
+		Title:      "My Codelab",
+		Summary:    "Learn things.",
+		Categories: []string{"web"},
+		Duration:   30,
+		Status:     &status,
+		Feedback:   "https://github.com/org/repo/issues",
+		GA:         "UA-12345-1",
+		GA4:        "G-ABC123",
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(m *Meta)
+		fields []string
+	}{
+		{
+			name:   "Valid",
+			mutate: func(m *Meta) {},
+		},
+		{
+			name: "Missing",
+			mutate: func(m *Meta) {
+				*m = Meta{}
+			},
+			fields: []string{"id", "title", "summary", "categories", "duration"},
+		},
+		{
+			name: "BadID",
+			mutate: func(m *Meta) {
+				m.ID = "My Codelab"
+			},
+			fields: []string{"id"},
+		},
+		{
+			name: "UnknownStatus",
+			mutate: func(m *Meta) {
+				m.Status = &LegacyStatus{"Draft", "final"}
+			},
+			fields: []string{"status"},
+		},
+		{
+			name: "BadLinks",
+			mutate: func(m *Meta) {
+				m.Feedback = "github.com/org/repo"
+				m.GA = "12345"
+				m.GA4 = "UA-12345-1"
+			},
+			fields: []string{"feedback_link", "analytics_account", "analytics_ga4_account"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := validMeta()
+			tc.mutate(m)
+			err := m.Validate()
+			var fields []string
+			if err != nil {
+				ee, ok := err.(MetaErrors)
+				if !ok {
+					t.Fatalf("Validate() = %T; want MetaErrors", err)
+				}
+				for _, e := range ee {
+					fields = append(fields, e.Field)
+				}
+			}
+			if diff := cmp.Diff(tc.fields, fields); diff != "" {
+				t.Errorf("Validate() invalid fields diff (-want +got): %s\n%v", diff, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestContextReader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := ContextReader(ctx, strings.NewReader("text"))
+	b, err := ioutil.ReadAll(r)
+	if string(b) != "text" || err != nil {
+		t.Errorf("ReadAll() = %q, %v; want %q, nil", b, err, "text")
+	}
+	cancel()
+	r = ContextReader(ctx, strings.NewReader("text"))
+	if b, err = ioutil.ReadAll(r); len(b) != 0 || err != context.Canceled {
+		t.Errorf("ReadAll() after cancel = %q, %v; want empty, %v", b, err, context.Canceled)
+	}
+}
+
+func TestContextWriter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var buf bytes.Buffer
+	w := ContextWriter(ctx, &buf)
+	if _, err := w.Write([]byte("text")); err != nil {
+		t.Errorf("Write() = %v; want nil", err)
+	}
+	cancel()
+	if n, err := w.Write([]byte("more")); n != 0 || err != context.Canceled {
+		t.Errorf("Write() after cancel = %d, %v; want 0, %v", n, err, context.Canceled)
+	}
+	if buf.String() != "text" {
+		t.Errorf("buf = %q; want %q", buf.String(), "text")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 Google LLC. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"path"
+	"strings"
+)
+
+// GitSource is a file of a git repository, as host/owner/repo//path@ref.
+type GitSource struct {
+	Repo string // repository, as host/owner/repo
+	Path string // slash-separated path of the file in Repo
+	Ref  string // branch, tag or commit; empty for the default branch
+}
+
+// ParseGitSource parses git source src, returning false
+// if src is not one.
+func ParseGitSource(src string) (*GitSource, bool) {
+	i := strings.Index(src, "//")
+	if i <= 0 || strings.Contains(src[:i], ":") {
+		return nil, false
+	}
+	g := &GitSource{Repo: strings.TrimSuffix(src[:i], "/"), Path: src[i+2:]}
+	if j := strings.LastIndexByte(g.Path, '@'); j >= 0 {
+		g.Path, g.Ref = g.Path[:j], g.Path[j+1:]
+	}
+	host := strings.SplitN(g.Repo, "/", 2)[0]
+	if !strings.Contains(host, ".") || !strings.Contains(g.Repo, "/") || g.Path == "" {
+		return nil, false
+	}
+	return g, true
+}
+
+// String returns the source of g.
+func (g *GitSource) String() string {
+	s := g.Repo + "//" + g.Path
+	if g.Ref != "" {
+		s += "@" + g.Ref
+	}
+	return s
+}
+
+// Resolve returns the git source of ref, relative to the file of g,
+// in the same repository and at the same revision. References out of
+// the repository are resolved to its root.
+func (g *GitSource) Resolve(ref string) string {
+	r := *g
+	r.Path = strings.TrimPrefix(path.Join("/", path.Dir(g.Path), ref), "/")
+	return r.String()
+}
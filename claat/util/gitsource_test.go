@@ -0,0 +1,29 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseGitSource(t *testing.T) {
+	tests := []struct {
+		src  string
+		want *GitSource
+	}{
+		{"github.com/org/repo//labs/intro.md@main", &GitSource{"github.com/org/repo", "labs/intro.md", "main"}},
+		{"github.com/org/repo//intro.md", &GitSource{"github.com/org/repo", "intro.md", ""}},
+		{"gitlab.example.com/group/sub/repo//intro.md@release/v1", &GitSource{"gitlab.example.com/group/sub/repo", "intro.md", "release/v1"}},
+		{"https://example.com//intro.md", nil},
+		{"github.com//intro.md", nil},
+		{"labs//intro.md", nil},
+		{"github.com/org/repo//", nil},
+		{"1a2b3c", nil},
+	}
+	for _, tc := range tests {
+		got, _ := ParseGitSource(tc.src)
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("ParseGitSource(%q) got diff (-want +got):\n%s", tc.src, diff)
+		}
+	}
+}
@@ -22,6 +22,10 @@ import (
 // relative to the codelab dir.
 const ImgDirname = "img"
 
+// FilesDirname is where a codelab attachments are stored,
+// relative to the codelab dir.
+const FilesDirname = "files"
+
 // Unique de-dupes a.
 // The argument a is not modified.
 func Unique(a []string) []string {
@@ -55,6 +55,15 @@ const OPTION_WRAPPER_CLASS = 'survey-option-wrapper';
 /** @const {string} */
 const RADIO_TEXT_CLASS = 'option-text';
 
+/** @const {string} */
+const OPTIONS_CLASS = 'survey-question-options';
+
+/** @const {string} */
+const MULTI_CLASS = 'survey-multi';
+
+/** @const {string} */
+const RATING_CLASS = 'survey-rating';
+
 /**
  * @extends {HTMLElement}
  */
@@ -118,31 +127,65 @@ class CodelabSurvey extends HTMLElement {
    * @private
    */
   handleOptionSelected_(event) {
+    if (event.target instanceof HTMLTextAreaElement) {
+      this.storeAnswer_(event.target.name, event.target.value);
+      return;
+    }
     if (!(event.target instanceof HTMLInputElement)) {
       return;
     }
     const inputElement = event.target;
+    if (inputElement.type != 'checkbox') {
+      this.storeAnswer_(inputElement.name, this.optionText_(inputElement));
+      return;
+    }
+    const optionsElement = dom.getAncestorByClass(inputElement, OPTIONS_CLASS);
+    if (!(optionsElement instanceof Element)) {
+      return;
+    }
+    const answers = [];
+    optionsElement.querySelectorAll('input').forEach(inp => {
+      if (inp.checked) {
+        answers.push(this.optionText_(inp));
+      }
+    });
+    this.storeAnswer_(inputElement.name, answers);
+  }
+
+  /**
+   * @param {!HTMLInputElement} inputElement
+   * @return {string} The text of the option of inputElement.
+   * @private
+   */
+  optionText_(inputElement) {
     const optionWrapperElement =
         dom.getAncestorByClass(inputElement, OPTION_WRAPPER_CLASS);
     if (!(optionWrapperElement instanceof Element)) {
-      return;
+      return '';
     }
     const optionTextElement =
         optionWrapperElement.querySelector(`.${RADIO_TEXT_CLASS}`);
-    let answer = '';
-    if (optionTextElement) {
-      answer = optionTextElement.textContent;
-    }
+    return optionTextElement ? optionTextElement.textContent : '';
+  }
 
-    const question = inputElement.name;
+  /**
+   * Stores the answer to question and reports it.
+   * @param {string} question
+   * @param {string|!Array<string>} answer The option of a single choice or
+   *     rating question, the options of a multi-select question, or the text
+   *     of a free text question.
+   * @private
+   */
+  storeAnswer_(question, answer) {
     this.storedData_[this.surveyName_][question] = answer;
     this.storage_.set(
       this.storageKey_, JSON.stringify(this.storedData_[this.surveyName_]));
+    const label = Array.isArray(answer) ? answer.join(', ') : answer;
     const codelabEvent = new CustomEvent('google-codelab-action', {
       detail: {
         'category': 'survey',
         'action': question.substring(0, 500),
-        'label': answer.substring(0, 500)
+        'label': label.substring(0, 500)
       }
     });
     document.body.dispatchEvent(codelabEvent);
@@ -161,28 +204,56 @@ class CodelabSurvey extends HTMLElement {
 
   /** @private */
   updateDom_() {
-    const radioGroupEls = this.querySelectorAll('paper-radio-group');
-    const questionEls = this.querySelectorAll('h4');
     const surveyQuestions = [];
-    if (radioGroupEls.length && (questionEls.length == radioGroupEls.length)) {
-      radioGroupEls.forEach((radioGroupEl, index) => {
-        const surveyOptions = [];
-        const polymerRadioEls = radioGroupEl.querySelectorAll(
-          'paper-radio-button');
-        dom.removeNode(radioGroupEl);
-        polymerRadioEls.forEach(radioEl => {
-          const title = radioEl.textContent;
+    // Each question is followed by its answer element: a group of radio
+    // buttons for a single choice or a rating, of checkboxes for multiple
+    // choices, or a text area for free text.
+    this.querySelectorAll('h4').forEach(questionEl => {
+      const answerEl = dom.getNextElementSibling(questionEl);
+      if (!answerEl) {
+        return;
+      }
+      const question = questionEl.textContent;
+      let type;
+      let optionTag;
+      switch (answerEl.tagName.toLowerCase()) {
+        case 'paper-radio-group':
+          type = 'radio';
+          optionTag = 'paper-radio-button';
+          break;
+        case 'div':
+          if (!answerEl.classList.contains(MULTI_CLASS)) {
+            return;
+          }
+          type = 'checkbox';
+          optionTag = 'paper-checkbox';
+          break;
+        case 'paper-textarea':
+          type = 'text';
+          break;
+        default:
+          return;
+      }
+      const surveyOptions = [];
+      if (optionTag) {
+        answerEl.querySelectorAll(optionTag).forEach(optionEl => {
+          const title = optionEl.textContent;
           surveyOptions.push({
-            radioId: this.normalizeIdAttr_(questionEls[index].textContent, title),
+            radioId: this.normalizeIdAttr_(question, title),
             radioTitle: title
           });
         });
-        surveyQuestions.push({
-          question: questionEls[index].textContent,
-          options: surveyOptions
-        });
-        dom.removeNode(questionEls[index]);
+      }
+      surveyQuestions.push({
+        question: question,
+        type: type,
+        rating: answerEl.classList.contains(RATING_CLASS),
+        options: surveyOptions
       });
+      dom.removeNode(answerEl);
+      dom.removeNode(questionEl);
+    });
+    if (surveyQuestions.length) {
       const updatedDom = soy.renderAsElement(Templates.survey, {
         surveyName: this.surveyName_,
         surveyQuestions: surveyQuestions
@@ -198,17 +269,37 @@ class CodelabSurvey extends HTMLElement {
     const surveyData = this.storedData_[this.surveyName_];
     if (surveyData) {
       Object.keys(surveyData).forEach(key => {
-        const id = this.normalizeIdAttr_(key, surveyData[key]);
-        /** @type {?HTMLInputElement} */
-        const inp = /** @type {?HTMLInputElement} */ (
-            this.querySelector(`#${id}`));
-        if (inp) {
-          inp.checked = true;
+        const answer = surveyData[key];
+        if (Array.isArray(answer)) {
+          answer.forEach(a => this.checkOption_(key, a));
+          return;
         }
+        this.checkOption_(key, answer);
+        this.querySelectorAll('textarea').forEach(textarea => {
+          if (textarea.name == key) {
+            textarea.value = answer;
+          }
+        });
       });
     }
   }
 
+  /**
+   * Checks the input of the option answer to question, if any.
+   * @param {string} question
+   * @param {string} answer
+   * @private
+   */
+  checkOption_(question, answer) {
+    const id = this.normalizeIdAttr_(question, answer);
+    /** @type {?HTMLInputElement} */
+    const inp = /** @type {?HTMLInputElement} */ (
+        this.querySelector(`#${id}`));
+    if (inp) {
+      inp.checked = true;
+    }
+  }
+
   /**
    * @param {string} question
    * @param {string} answer
@@ -46,6 +46,18 @@ const polymerHtml = '<google-codelab-survey survey-id="test">' +
   '<paper-radio-button>Second Option</paper-radio-button>' +
   '</paper-radio-group></google-codelab-survey>';
 
+const polymerHtmlKinds = '<google-codelab-survey survey-id="test">' +
+  '<h4>Topics?</h4><div class="survey-multi">' +
+  '<paper-checkbox>Go</paper-checkbox>' +
+  '<paper-checkbox>Java</paper-checkbox>' +
+  '</div>' +
+  '<h4>Rate it</h4><paper-radio-group class="survey-rating">' +
+  '<paper-radio-button>1</paper-radio-button>' +
+  '<paper-radio-button>2</paper-radio-button>' +
+  '</paper-radio-group>' +
+  '<h4>Comments?</h4><paper-textarea></paper-textarea>' +
+  '</google-codelab-survey>';
+
 const polymerHtmlInvalid = '<google-codelab-survey survey-id="test"><paper-radio-group>' +
   '<paper-radio-button>Title Text</paper-radio-button>' +
   '</paper-radio-group></google-codelab-survey>';
@@ -145,6 +157,55 @@ testSuite({
     assertEquals('{"Question?":"Second Option"}', localStorage.get('codelab-survey-test'));
   },
 
+  testCodelabSurveyKinds() {
+    div.innerHTML = polymerHtmlKinds;
+    document.body.appendChild(div);
+    const surveyCE = div.querySelector('google-codelab-survey');
+    assertEquals(3, surveyCE.querySelectorAll('.survey-question-wrapper').length);
+    assertEquals('checkbox',
+        surveyCE.querySelector('input#topics--java').type);
+    assertEquals('radio', surveyCE.querySelector('input#rate-it--2').type);
+    assertNotNull(surveyCE.querySelector('.survey-rating input#rate-it--2'));
+    assertEquals('Comments?', surveyCE.querySelector('textarea').name);
+    assertTrue(surveyCE.hasAttribute('upgraded'));
+  },
+
+  testCodelabSurveyMultiClick() {
+    div.innerHTML = polymerHtmlKinds;
+    document.body.appendChild(div);
+    const optionEls = div.querySelectorAll(`.${OPTION_WRAPPER_CLASS}`);
+    optionEls[0].click();
+    optionEls[1].click();
+    assertEquals('{"Topics?":["Go","Java"]}',
+        localStorage.get('codelab-survey-test'));
+    optionEls[0].click();
+    assertEquals('{"Topics?":["Java"]}',
+        localStorage.get('codelab-survey-test'));
+  },
+
+  testCodelabSurveyTextChange() {
+    div.innerHTML = polymerHtmlKinds;
+    document.body.appendChild(div);
+    const textarea = div.querySelector('textarea');
+    textarea.value = 'Great';
+    textarea.dispatchEvent(new Event(events.EventType.CHANGE, {
+          bubbles: true
+        }));
+    assertEquals('{"Comments?":"Great"}',
+        localStorage.get('codelab-survey-test'));
+  },
+
+  testCodelabSurveyLoadsStoredKinds() {
+    localStorage.set('codelab-survey-test',
+        '{"Topics?":["Java"],"Rate it":"2","Comments?":"Great"}');
+    div.innerHTML = polymerHtmlKinds;
+    document.body.appendChild(div);
+    assertFalse(div.querySelector('input#topics--go').checked);
+    assertTrue(div.querySelector('input#topics--java').checked);
+    assertTrue(div.querySelector('input#rate-it--2').checked);
+    assertEquals('Great', div.querySelector('textarea').value);
+  },
+
   testCodelabSurveyLoadsStoredAnswers() {
     localStorage.set('codelab-survey-test', '{"Question?":"Second Option"}');
     document.body.appendChild(div);